@@ -1,24 +1,109 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/pmarsceill/mapcli/internal/daemon"
 )
 
+// version is set via -ldflags at build time
+var version = "dev"
+
 func main() {
 	socketPath := flag.String("socket", "/tmp/mapd.sock", "socket path")
+	socketMode := flag.String("socket-mode", "0600", "socket permissions (octal)")
 	dataDir := flag.String("data-dir", "", "data directory (default ~/.mapd)")
+	strategy := flag.String("scheduling-strategy", "round-robin", "agent selection: round-robin, least-loaded, or random")
+	autoSpawn := flag.Bool("auto-spawn", false, "spawn an agent when a task is submitted and none are idle")
+	autoSpawnMax := flag.Int("auto-spawn-max", 3, "maximum running agents when auto-spawning")
+	autoSpawnType := flag.String("auto-spawn-type", "claude", "agent type to auto-spawn: claude, codex, or gemini")
+	warmPool := flag.Int("warm-pool", 0, "minimum number of idle agents to keep alive (0 = disabled)")
+	warmPoolRepo := flag.String("warm-pool-repo", "", "repository to keep the warm pool in (default: daemon's repo)")
+	requirePermissions := flag.Bool("require-permissions", false, "start auto-spawned and warm pool agents with permission prompts")
+	maxAgents := flag.Int("max-agents", daemon.DefaultMaxAgents, "maximum number of live agents (negative = unlimited)")
+	maxRetries := flag.Int("max-retries", 3, "maximum times a failed task may be retried (0 = unlimited)")
+	maxDescription := flag.Int("max-description-bytes", daemon.DefaultMaxDescriptionBytes, "maximum size of a task description in bytes (0 = unlimited)")
+	maxResult := flag.Int("max-result-bytes", daemon.DefaultMaxResultBytes, "largest task result kept in the database; larger ones are truncated (negative = unlimited)")
+	resultFiles := flag.Bool("result-files", false, "write task results over -max-result-bytes to <data-dir>/results instead of truncating them")
+	branchTemplate := flag.String("branch-template", "", "create agent worktrees on a new branch named by this template (e.g. map/{{.AgentID}}); default detached HEAD")
+	githubTimeout := flag.Duration("github-timeout", daemon.DefaultGitHubTimeout, "timeout for each gh call made by the daemon")
+	questionWebhook := flag.String("question-webhook-url", "", "Slack or Discord webhook to post agents' questions to")
+	noGitHubQuestions := flag.Bool("no-github-questions", false, "don't post agents' questions to the task's GitHub issue")
+	promptWait := flag.Duration("prompt-confirm-wait", daemon.DefaultPromptConfirmWait, "how long to wait for an agent's initial prompt to appear before resending it")
+	promptRetries := flag.Int("prompt-retries", daemon.DefaultPromptRetries, "times to resend an initial prompt that doesn't appear (0 = send once)")
+	submitKeyFlags := make(map[string]*string)
+	for _, agentType := range daemon.AgentTypes() {
+		defaults := strings.Join(daemon.LookupAgentType(agentType).SubmitKeys, ",")
+		submitKeyFlags[agentType] = flag.String(agentType+"-submit-keys", "",
+			fmt.Sprintf("comma-separated tmux keys that submit a prompt to %s (default %s)", agentType, defaults))
+	}
+	stuckThreshold := flag.Duration("stuck-threshold", daemon.DefaultStuckThreshold, "report a busy agent as stuck after its pane is unchanged this long (negative = off)")
+	bootTimeout := flag.Duration("boot-timeout", daemon.DefaultBootTimeout, "fail a spawn whose agent CLI exits within this long of starting (negative = don't check)")
+	heartbeatInterval := flag.Duration("heartbeat-interval", daemon.DefaultHeartbeatInterval, "emit a heartbeat event for each live agent this often (negative = off)")
+	agentRuntime := flag.String("agent-runtime", daemon.RuntimeNative, "where agent CLIs run: native, docker, or podman")
+	agentImage := flag.String("agent-image", "", "container image agents run in when -agent-runtime is docker or podman")
+	eventBuffer := flag.Int("event-buffer", daemon.DefaultEventBuffer, "events that can wait to be broadcast before new ones are dropped")
+	allowedRepos := flag.String("allowed-repos", "", "comma-separated repository paths or patterns agents may be spawned against (default: any)")
+	routingRules := flag.String("routing-rules", "", `routing rules as a JSON list, e.g. [{"scope":["docs/**"],"agent-type":"gemini"}]`)
 	flag.Parse()
 
+	mode, err := daemon.ParseSocketMode(*socketMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var rules []daemon.RoutingRule
+	if *routingRules != "" {
+		if err := json.Unmarshal([]byte(*routingRules), &rules); err != nil {
+			log.Fatalf("invalid -routing-rules: %v", err)
+		}
+	}
+
+	submitKeys := make(map[string][]string, len(submitKeyFlags))
+	for agentType, keys := range submitKeyFlags {
+		submitKeys[agentType] = strings.Split(*keys, ",")
+	}
+
 	cfg := &daemon.Config{
-		SocketPath: *socketPath,
-		DataDir:    *dataDir,
+		SocketPath:          *socketPath,
+		SocketMode:          mode,
+		DataDir:             *dataDir,
+		SchedulingStrategy:  *strategy,
+		AutoSpawn:           *autoSpawn,
+		AutoSpawnMax:        *autoSpawnMax,
+		AutoSpawnType:       *autoSpawnType,
+		WarmPoolSize:        *warmPool,
+		WarmPoolRepo:        *warmPoolRepo,
+		WarmPoolType:        *autoSpawnType,
+		RequirePermissions:  *requirePermissions,
+		MaxRetries:          *maxRetries,
+		MaxDescriptionBytes: *maxDescription,
+		MaxResultBytes:      *maxResult,
+		ResultFiles:         *resultFiles,
+		BranchTemplate:      *branchTemplate,
+		Version:             version,
+		MaxAgents:           *maxAgents,
+		GitHubTimeout:       *githubTimeout,
+		QuestionWebhookURL:  *questionWebhook,
+		NoGitHubQuestions:   *noGitHubQuestions,
+		PromptConfirmWait:   *promptWait,
+		PromptRetries:       *promptRetries,
+		SubmitKeys:          submitKeys,
+		StuckThreshold:      *stuckThreshold,
+		BootTimeout:         *bootTimeout,
+		HeartbeatInterval:   *heartbeatInterval,
+		AgentRuntime:        *agentRuntime,
+		AgentImage:          *agentImage,
+		EventBuffer:         *eventBuffer,
+		AllowedRepos:        strings.Split(*allowedRepos, ","),
+		RoutingRules:        rules,
 	}
 
 	srv, err := daemon.NewServer(cfg)
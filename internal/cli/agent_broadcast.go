@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var agentBroadcastCmd = &cobra.Command{
+	Use:   "broadcast <text>",
+	Short: "Send a message to every agent",
+	Long: `Type a message into the session of every agent in the current repository
+and submit it, as if you had typed it yourself, e.g. to tell the fleet to
+wrap up before shutting down.
+
+Use --idle to only message idle agents, --label key=value (repeatable) to
+only message agents carrying those labels, and --all-repos to message agents
+from every repository. Each message is delivered with the same safeguards
+as task prompts, so it never interleaves with a task being sent to the same
+agent. Headless agents have no session, so they are skipped.
+
+Examples:
+  map agent broadcast "Wrap up and commit your work; shutting down in 10 minutes"
+  map agent broadcast --idle --label pool=frontend "Pull the latest main"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAgentBroadcast,
+}
+
+var (
+	broadcastIdle     bool
+	broadcastAllRepos bool
+)
+
+func init() {
+	agentBroadcastCmd.Flags().BoolVar(&broadcastIdle, "idle", false, "Only message idle agents")
+	agentBroadcastCmd.Flags().StringArray("label", nil, "Only message agents with this key=value label (repeatable)")
+	agentBroadcastCmd.Flags().BoolVar(&broadcastAllRepos, "all-repos", false, "Message agents from every repository, not just the current one")
+	agentCmd.AddCommand(agentBroadcastCmd)
+}
+
+func runAgentBroadcast(cmd *cobra.Command, args []string) error {
+	labels, err := labelsFlag(cmd)
+	if err != nil {
+		return err
+	}
+	text := strings.Join(args, " ")
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("message is empty")
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	req := &mapv1.BroadcastMessageRequest{
+		Text:     text,
+		IdleOnly: broadcastIdle,
+		Labels:   labels,
+	}
+	if !broadcastAllRepos {
+		req.RepoRoot = getRepoRoot()
+	}
+	deliveries, err := c.BroadcastMessage(ctx, req)
+	if err != nil {
+		return fmt.Errorf("broadcast: %w", err)
+	}
+
+	if len(deliveries) == 0 {
+		fmt.Println("no agents match")
+		return nil
+	}
+
+	var failed int
+	for _, d := range deliveries {
+		if d.Error != "" {
+			failed++
+			fmt.Printf("  %s: not delivered: %s\n", d.AgentId, d.Error)
+		} else if !isQuiet() {
+			fmt.Printf("  %s: delivered\n", d.AgentId)
+		}
+	}
+	if !isQuiet() {
+		fmt.Printf("message delivered to %d of %d agent(s)\n", len(deliveries)-failed, len(deliveries))
+	}
+	if failed > 0 {
+		return fmt.Errorf("message not delivered to %d agent(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var agentHistoryCmd = &cobra.Command{
+	Use:   "history <agent-id>",
+	Short: "Show the messages exchanged with an agent",
+	Long: `Show the messages the daemon sent to an agent, oldest first: task
+prompts, initial prompts, nudges, broadcasts, and responses to its
+questions, along with the questions the agent asked.
+
+Unlike the pane capture in 'map agent watch', this is a clean record of
+daemon-initiated interactions. History is kept after the agent is killed;
+pass its full ID to see it.
+
+Examples:
+  map agent history swift-falcon
+  map agent history swift-falcon -n 5
+  map agent history swift-falcon --full`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentHistory,
+}
+
+func init() {
+	agentHistoryCmd.Flags().IntP("limit", "n", 0, "show only the most recent messages (0 = all)")
+	agentHistoryCmd.Flags().Bool("full", false, "print each message in full instead of one line")
+	agentCmd.AddCommand(agentHistoryCmd)
+}
+
+func runAgentHistory(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	full, _ := cmd.Flags().GetBool("full")
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Killed agents are no longer listed, so fall back to the ID as given
+	agentID, err := resolveAgentID(ctx, c, args[0])
+	if err != nil {
+		agentID = args[0]
+	}
+
+	messages, err := c.GetAgentHistory(ctx, agentID, limit)
+	if err != nil {
+		return fmt.Errorf("get agent history: %w", err)
+	}
+
+	if len(messages) == 0 {
+		fmt.Printf("no messages recorded for agent %s\n", agentID)
+		return nil
+	}
+
+	if full {
+		for i, msg := range messages {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s %s %s", formatMessageTime(msg), messageArrow(msg), msg.Kind)
+			if msg.TaskId != "" {
+				fmt.Printf(" (task %s)", msg.TaskId)
+			}
+			fmt.Printf("\n%s\n", msg.Content)
+		}
+		return nil
+	}
+
+	fmt.Printf("%-20s %-3s %-15s %-10s %s\n", "TIME", "DIR", "KIND", "TASK", "MESSAGE")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, msg := range messages {
+		fmt.Printf("%-20s %-3s %-15s %-10s %s\n",
+			formatMessageTime(msg),
+			messageArrow(msg),
+			msg.Kind,
+			truncate(valueOrDash(msg.TaskId), 10),
+			truncate(strings.Join(strings.Fields(msg.Content), " "), 50),
+		)
+	}
+
+	return nil
+}
+
+// messageArrow shows which way a message went: -> to the agent, <- from it
+func messageArrow(msg *mapv1.AgentMessage) string {
+	if msg.Direction == daemon.AgentMessageReceived {
+		return "<-"
+	}
+	return "->"
+}
+
+func formatMessageTime(msg *mapv1.AgentMessage) string {
+	if msg.Timestamp == nil {
+		return "-"
+	}
+	return msg.Timestamp.AsTime().Local().Format("2006-01-02 15:04:05")
+}
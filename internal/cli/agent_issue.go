@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// issueAssignTimeout bounds how long 'agent create --prompt-from-issue'
+// waits to see the new agent pick up its issue task
+const issueAssignTimeout = 10 * time.Second
+
+// issueRef identifies a GitHub issue as owner/repo#number
+type issueRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+func (r issueRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// parseIssueRef parses an owner/repo#number issue reference
+func parseIssueRef(ref string) (issueRef, error) {
+	repoPart, numPart, ok := strings.Cut(strings.TrimSpace(ref), "#")
+	owner, repo, slash := strings.Cut(repoPart, "/")
+	if !ok || !slash || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return issueRef{}, fmt.Errorf("invalid issue %q: want owner/repo#number", ref)
+	}
+	number, err := strconv.Atoi(numPart)
+	if err != nil || number <= 0 {
+		return issueRef{}, fmt.Errorf("invalid issue %q: want owner/repo#number", ref)
+	}
+	return issueRef{Owner: owner, Repo: repo, Number: number}, nil
+}
+
+// fetchIssue loads an issue with gh as a project item, so its task
+// description is built the same way 'task sync gh-project' builds one
+func fetchIssue(ref issueRef) (ghItem, error) {
+	if err := checkGHCLI(); err != nil {
+		return ghItem{}, err
+	}
+
+	out, err := ghOutput("issue", "view", strconv.Itoa(ref.Number),
+		"--repo", ref.Owner+"/"+ref.Repo, "--json", "number,title,body,url")
+	if err != nil {
+		return ghItem{}, err
+	}
+
+	var content ghItemContent
+	if err := json.Unmarshal(out, &content); err != nil {
+		return ghItem{}, fmt.Errorf("parse issue %s: %w", ref, err)
+	}
+	content.Type = "Issue"
+	if content.Number == 0 {
+		content.Number = ref.Number
+	}
+	return ghItem{Content: content}, nil
+}
+
+// submitIssueTask creates the GitHub-tracked task for an issue, preferring
+// the agent spawned for it, and reports which agent picked it up. The agent
+// is held for the task, so it is removed if the task can't be created.
+func submitIssueTask(c *client.Client, ref issueRef, issue ghItem, agentID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
+		Description:       buildTaskDescription(issue),
+		GithubOwner:       ref.Owner,
+		GithubRepo:        ref.Repo,
+		GithubIssueNumber: int32(ref.Number),
+		RepoRoot:          getRepoRoot(),
+		PreferAgent:       agentID,
+	})
+	if err != nil {
+		if killErr := removeHeldAgent(c, agentID); killErr != nil {
+			return fmt.Errorf("create task for %s: %w (and remove agent %s: %v)", ref, err, agentID, killErr)
+		}
+		return fmt.Errorf("create task for %s: %w (agent %s removed)", ref, err, agentID)
+	}
+	if isQuiet() {
+		return nil
+	}
+	fmt.Printf("\ntask created for %s: %s\n", ref, task.TaskId)
+
+	assigned, err := waitForAssignment(c, task.TaskId, issueAssignTimeout)
+	if err != nil {
+		return err
+	}
+	switch assigned.AssignedTo {
+	case agentID:
+		fmt.Printf("task assigned to %s\n", agentID)
+	case "":
+		fmt.Printf("%s has not picked up the task yet; check on it with 'map task show %s'\n", agentID, task.TaskId)
+	default:
+		fmt.Printf("%s was busy, so the task went to %s\n", agentID, assigned.AssignedTo)
+	}
+	return nil
+}
+
+// removeHeldAgent kills an agent that was spawned held for a task that could
+// not be submitted. Held agents take no other tasks, so it would otherwise
+// sit idle for good.
+func removeHeldAgent(c *client.Client, agentID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.KillAgent(ctx, agentID, false, false)
+	if err != nil {
+		return err
+	}
+	if !resp.GetSuccess() {
+		return fmt.Errorf("%s", resp.GetMessage())
+	}
+	return nil
+}
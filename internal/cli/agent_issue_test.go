@@ -0,0 +1,22 @@
+package cli
+
+import "testing"
+
+func TestParseIssueRef(t *testing.T) {
+	ref, err := parseIssueRef("pmarsceill/mapcli#42")
+	if err != nil {
+		t.Fatalf("parseIssueRef: %v", err)
+	}
+	if ref != (issueRef{Owner: "pmarsceill", Repo: "mapcli", Number: 42}) {
+		t.Errorf("parseIssueRef = %+v", ref)
+	}
+	if ref.String() != "pmarsceill/mapcli#42" {
+		t.Errorf("String() = %q", ref.String())
+	}
+
+	for _, bad := range []string{"mapcli#42", "pmarsceill/mapcli", "pmarsceill/mapcli#x", "pmarsceill/mapcli#0", "a/b/c#1", "/mapcli#1"} {
+		if _, err := parseIssueRef(bad); err == nil {
+			t.Errorf("parseIssueRef(%q) succeeded, want error", bad)
+		}
+	}
+}
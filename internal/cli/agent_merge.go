@@ -148,7 +148,7 @@ func runAgentMerge(cmd *cobra.Command, args []string) error {
 		killCtx, killCancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer killCancel()
 
-		resp, err := c.KillAgent(killCtx, foundAgent, false)
+		resp, err := c.KillAgent(killCtx, foundAgent, false, false)
 		if err != nil {
 			return fmt.Errorf("kill agent: %w", err)
 		}
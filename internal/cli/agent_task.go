@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var agentTaskCmd = &cobra.Command{
+	Use:   "task <agent-id>",
+	Short: "Show the task an agent is working on",
+	Long: `Show the in-progress or waiting-for-input task assigned to an agent.
+
+This is the by-ID counterpart of 'map task my-task', for tools that know the
+agent rather than its working directory. Partial agent IDs are accepted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentTask,
+}
+
+func init() {
+	agentCmd.AddCommand(agentTaskCmd)
+}
+
+func runAgentTask(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	agentID, err := resolveAgentID(ctx, c, args[0])
+	if err != nil {
+		return err
+	}
+
+	task, err := c.GetAgentTask(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("get agent task: %w", err)
+	}
+
+	if isQuiet() {
+		if task != nil {
+			fmt.Println(task.TaskId)
+		}
+		return nil
+	}
+
+	if task == nil {
+		fmt.Printf("agent %s has no active task\n", agentID)
+		return nil
+	}
+
+	printTaskSummary(task)
+	return nil
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var agentTopCmd = &cobra.Command{
+	Use:   "top [agent-id]",
+	Short: "Show agents' CPU and memory usage",
+	Long: `Show the CPU and memory used by each agent, heaviest first.
+
+An agent's usage is that of the process in its tmux pane and everything it
+started (shells, test runs, language servers). CPU is a percentage of one
+core as ps reports it; on Linux that is averaged over each process's
+lifetime rather than measured right now. Memory is resident memory.
+
+Headless agents and agents running in a container aren't sampled.
+
+Examples:
+  map agent top
+  map agent top --sort mem
+  map agent top swift-falcon`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentTop,
+}
+
+func init() {
+	agentTopCmd.Flags().String("sort", "cpu", "sort by cpu or mem")
+	agentCmd.AddCommand(agentTopCmd)
+}
+
+func runAgentTop(cmd *cobra.Command, args []string) error {
+	sortBy, _ := cmd.Flags().GetString("sort")
+	if sortBy != "cpu" && sortBy != "mem" {
+		return fmt.Errorf("--sort must be cpu or mem, got %q", sortBy)
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var agentID string
+	if len(args) == 1 {
+		agentID, err = resolveAgentID(ctx, c, args[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	usage, err := c.GetAgentUsage(ctx, agentID)
+	if err != nil {
+		return fmt.Errorf("get agent usage: %w", err)
+	}
+
+	if len(usage) == 0 {
+		fmt.Println("no agents")
+		return nil
+	}
+
+	sortUsage(usage, sortBy)
+
+	fmt.Printf("%-24s %-8s %7s %10s %6s\n", "AGENT", "PID", "CPU%", "MEM", "PROCS")
+	fmt.Println(strings.Repeat("-", 60))
+	for _, u := range usage {
+		if u.Error != "" {
+			fmt.Printf("%-24s %s\n", truncate(u.AgentId, 24), u.Error)
+			continue
+		}
+		fmt.Printf("%-24s %-8d %7.1f %10s %6d\n",
+			truncate(u.AgentId, 24),
+			u.Pid,
+			u.CpuPercent,
+			formatBytes(u.MemoryBytes),
+			u.Processes,
+		)
+	}
+	return nil
+}
+
+// sortUsage orders agents heaviest first by CPU or memory, with agents that
+// couldn't be sampled last
+func sortUsage(usage []*mapv1.AgentUsage, by string) {
+	sort.SliceStable(usage, func(i, j int) bool {
+		a, b := usage[i], usage[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if by == "mem" && a.MemoryBytes != b.MemoryBytes {
+			return a.MemoryBytes > b.MemoryBytes
+		}
+		if a.CpuPercent != b.CpuPercent {
+			return a.CpuPercent > b.CpuPercent
+		}
+		return a.AgentId < b.AgentId
+	})
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "312.4 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTP"[exp])
+}
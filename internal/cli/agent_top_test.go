@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:                      "0 B",
+		1023:                   "1023 B",
+		1024:                   "1.0 KiB",
+		1536:                   "1.5 KiB",
+		300 * 1024 * 1024:      "300.0 MiB",
+		5 * 1024 * 1024 * 1024: "5.0 GiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestSortUsage(t *testing.T) {
+	usage := []*mapv1.AgentUsage{
+		{AgentId: "a", CpuPercent: 5, MemoryBytes: 900},
+		{AgentId: "b", Error: "headless agents have no pane to sample"},
+		{AgentId: "c", CpuPercent: 40, MemoryBytes: 100},
+		{AgentId: "d", CpuPercent: 5, MemoryBytes: 500},
+	}
+
+	sortUsage(usage, "cpu")
+	if got := ids(usage); got != "c,a,d,b" {
+		t.Errorf("by cpu: %s", got)
+	}
+
+	sortUsage(usage, "mem")
+	if got := ids(usage); got != "a,d,c,b" {
+		t.Errorf("by mem: %s", got)
+	}
+}
+
+func ids(usage []*mapv1.AgentUsage) string {
+	var s string
+	for i, u := range usage {
+		if i > 0 {
+			s += ","
+		}
+		s += u.AgentId
+	}
+	return s
+}
@@ -1,17 +1,22 @@
 package cli
 
 import (
-	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var agentWatchCmd = &cobra.Command{
@@ -28,20 +33,63 @@ Use standard tmux controls:
 
 If no agent-id is specified, attaches to the first available agent.
 
-Use --all to view multiple agents in a tiled tmux layout (up to 6 agents, 3 per row).`,
+Use --all to view multiple agents in a tiled tmux layout (up to 6 agents, 3 per row).
+Use --grid N instead to arrange them in exactly N equal-width columns, filled
+row by row, e.g. --grid 2 shows 4 agents as two rows of two.
+
+Use --capture-on-detach to save the pane contents to a transcript file
+under ~/.mapd/transcripts when you detach.
+
+Use --cmd to send text to the agent without attaching: the text is typed
+into the session, and once the pane stops changing for --settle, the new
+output is printed and the command exits. For example:
+
+  map agent watch claude-swift-fox --cmd "/status"
+
+Use --no-tty to follow an agent without a terminal, e.g. in CI logs or over
+'ssh host map agent watch ...'. The daemon streams the pane's new and
+changed lines to stdout as plain text until the agent exits or you press
+Ctrl+C; --tail sets how many recent lines are printed first.`,
 	RunE: runAgentWatch,
 }
 
-var watchAllFlag bool
+var (
+	watchAllFlag         bool
+	watchGrid            int
+	watchCaptureOnDetach bool
+	watchCmdText         string
+	watchCmdSettle       time.Duration
+	watchCmdTimeout      time.Duration
+	watchNoTTY           bool
+	watchTail            int
+)
 
 func init() {
 	agentCmd.AddCommand(agentWatchCmd)
 	agentWatchCmd.Flags().BoolVarP(&watchAllFlag, "all", "a", false, "View all agents in a tiled tmux layout (up to 6)")
+	agentWatchCmd.Flags().IntVar(&watchGrid, "grid", 0, "View all agents in a grid of N columns instead of the tiled layout (implies --all)")
+	agentWatchCmd.Flags().BoolVar(&watchCaptureOnDetach, "capture-on-detach", false, "Save the pane contents to a transcript file after detaching")
+	agentWatchCmd.Flags().StringVar(&watchCmdText, "cmd", "", "Send text to the agent, print the resulting output, and exit without attaching")
+	agentWatchCmd.Flags().DurationVar(&watchCmdSettle, "settle", 3*time.Second, "With --cmd, how long the pane must be unchanged before output is captured")
+	agentWatchCmd.Flags().DurationVar(&watchCmdTimeout, "cmd-timeout", 5*time.Minute, "With --cmd, maximum time to wait for output to settle")
+	agentWatchCmd.Flags().BoolVar(&watchNoTTY, "no-tty", false, "Stream the pane's output to stdout as plain lines instead of attaching (no terminal needed)")
+	agentWatchCmd.Flags().IntVar(&watchTail, "tail", 50, "With --no-tty, how many recent pane lines to print first")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("cmd", "all")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("cmd", "capture-on-detach")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("no-tty", "cmd")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("no-tty", "all")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("cmd", "grid")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("no-tty", "grid")
+	agentWatchCmd.MarkFlagsMutuallyExclusive("no-tty", "capture-on-detach")
 }
 
 func runAgentWatch(cmd *cobra.Command, args []string) error {
-	// Check if tmux is available
-	if _, err := exec.LookPath("tmux"); err != nil {
+	if cmd.Flags().Changed("grid") && watchGrid < 1 {
+		return fmt.Errorf("--grid must be at least 1")
+	}
+
+	// Check if tmux is available; --no-tty has the daemon read the pane
+	if _, err := exec.LookPath("tmux"); err != nil && !watchNoTTY {
 		return fmt.Errorf("tmux not found in PATH - required for agent watch")
 	}
 
@@ -65,21 +113,29 @@ func runAgentWatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no spawned agents found - create one with 'map agent create'")
 	}
 
-	// Handle --all flag for tiled view
-	if watchAllFlag {
+	// Handle --all flag for tiled view, or --grid for columns
+	if watchAllFlag || watchGrid > 0 {
 		return runAgentWatchAll(agents)
 	}
 
 	// Find target agent
 	var targetSession string
 	var targetAgent string
+	var targetType string
 
 	if len(args) > 0 {
 		// Find agent by ID (supports partial match)
 		targetID := args[0]
 		for _, a := range agents {
 			if a.GetAgentId() == targetID || strings.HasPrefix(a.GetAgentId(), targetID) {
+				if a.GetHeadless() {
+					return fmt.Errorf("agent %s is headless (output-only) and has no session to watch; its output is stored as task results", a.GetAgentId())
+				}
+				if err := requireTmuxSession(a); err != nil {
+					return err
+				}
 				targetAgent = a.GetAgentId()
+				targetType = a.GetAgentType()
 				targetSession = a.GetLogFile() // LogFile field repurposed to hold tmux session name
 				break
 			}
@@ -88,9 +144,22 @@ func runAgentWatch(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("agent %s not found", targetID)
 		}
 	} else {
-		// Use first agent
-		targetAgent = agents[0].GetAgentId()
-		targetSession = agents[0].GetLogFile()
+		// Use first agent with a session to watch
+		for _, a := range agents {
+			if !a.GetHeadless() && requireTmuxSession(a) == nil {
+				targetAgent = a.GetAgentId()
+				targetType = a.GetAgentType()
+				targetSession = a.GetLogFile()
+				break
+			}
+		}
+		if targetSession == "" {
+			return fmt.Errorf("no agents with a session to watch (headless agents have none)")
+		}
+	}
+
+	if watchNoTTY {
+		return followAgentOutput(c, targetAgent)
 	}
 
 	// Verify tmux session exists
@@ -99,60 +168,182 @@ func runAgentWatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tmux session %s not found - agent may have crashed", targetSession)
 	}
 
+	// Non-interactive: send text and print its output instead of attaching
+	if watchCmdText != "" {
+		if diag := inspectPane(targetSession); diag != nil {
+			if diag.dead {
+				return fmt.Errorf("agent %s looks crashed: %s - restart it with 'map agent respawn --force %s'", targetAgent, diag, targetAgent)
+			}
+			fmt.Fprintf(os.Stderr, "warning: agent %s may have a problem: %s\n", targetAgent, diag)
+		}
+		return runAgentWatchCmd(targetSession, targetType, watchCmdText, watchCmdSettle, watchCmdTimeout)
+	}
+
 	// Enable mouse mode for scrolling
 	_ = exec.Command("tmux", "set-option", "-t", targetSession, "mouse", "on").Run()
 
-	// Check if the pane is dead (claude exited but session preserved)
-	if isPaneDead(targetSession) {
-		fmt.Printf("Agent %s pane is dead (claude exited).\n", targetAgent)
-		fmt.Print("Respawn claude? [Y/n] ")
+	// Check for a crashed agent: a dead pane (the CLI exited but the session
+	// was preserved) is offered a restart, while a fatal-looking error on a
+	// live pane, such as an auth failure, is only warned about
+	if diag := inspectPane(targetSession); diag != nil {
+		attach, err := offerPaneRepair(c, targetAgent, diag)
+		if err != nil {
+			return err
+		}
+		if !attach {
+			return nil
+		}
+	}
+
+	if err := tmuxAttach(targetAgent, targetSession); err != nil {
+		return err
+	}
+
+	// Attach blocks until detach, so the pane can be captured right away
+	if watchCaptureOnDetach {
+		path, err := captureTranscript(targetAgent, targetSession)
+		if err != nil {
+			return fmt.Errorf("capture transcript: %w", err)
+		}
+		fmt.Printf("Transcript saved to %s\n", path)
+	}
+
+	return nil
+}
+
+// followAgentOutput prints the lines the daemon streams from an agent's pane
+// until the agent's session ends or the user interrupts
+func followAgentOutput(c *client.Client, agentID string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
 
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
+	stream, err := c.StreamAgentOutput(ctx, agentID, int32(max(watchTail, 0)))
+	if err != nil {
+		return fmt.Errorf("stream agent output: %w", err)
+	}
 
-		if response == "" || response == "y" || response == "yes" {
-			// Respawn via daemon
-			resp, err := c.RespawnAgent(ctx, targetAgent)
-			if err != nil {
-				return fmt.Errorf("respawn agent: %w", err)
+	for {
+		line, err := stream.Recv()
+		if err == io.EOF {
+			fmt.Fprintf(os.Stderr, "agent %s session ended\n", agentID)
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// Interrupted, normal exit
+				return nil
 			}
-			if !resp.Success {
-				return fmt.Errorf("respawn failed: %s", resp.Message)
+			return fmt.Errorf("receive agent output: %w", err)
+		}
+		fmt.Println(line.Line)
+	}
+}
+
+// captureTranscript writes the full scrollback of an agent's tmux pane to
+// <data-dir>/transcripts/<agent-id>-<timestamp>.txt and returns the file path
+func captureTranscript(agentID, sessionName string) (string, error) {
+	output, err := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-S", "-").Output()
+	if err != nil {
+		return "", fmt.Errorf("capture pane: %w", err)
+	}
+
+	dir := filepath.Join(viper.GetString("data-dir"), "transcripts")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create transcripts directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.txt", agentID, time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, output, 0644); err != nil {
+		return "", fmt.Errorf("write transcript: %w", err)
+	}
+
+	return path, nil
+}
+
+const watchAllSessionName = "map-watch-all"
+const maxWatchAgents = 6
+
+// attachToAgent attaches the terminal to a running agent's tmux session
+func attachToAgent(c *client.Client, agentID string) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return fmt.Errorf("tmux not found in PATH - required to attach")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agents, err := c.ListSpawnedAgents(ctx, "")
+	if err != nil {
+		return fmt.Errorf("list agents: %w", err)
+	}
+
+	var session string
+	for _, a := range agents {
+		if a.GetAgentId() == agentID {
+			if a.GetHeadless() {
+				return fmt.Errorf("agent %s is headless (output-only) and has no session to attach to", agentID)
 			}
-			fmt.Println("Claude respawned.")
-			// Give it a moment to start
-			time.Sleep(300 * time.Millisecond)
+			if err := requireTmuxSession(a); err != nil {
+				return err
+			}
+			session = a.GetLogFile() // LogFile field repurposed to hold tmux session name
+			break
 		}
 	}
+	if session == "" {
+		return fmt.Errorf("agent %s not found", agentID)
+	}
 
-	fmt.Printf("Attaching to agent %s (tmux session: %s)\n", targetAgent, targetSession)
+	if err := exec.Command("tmux", "has-session", "-t", session).Run(); err != nil {
+		return fmt.Errorf("tmux session %s not found - agent may have crashed", session)
+	}
+	_ = exec.Command("tmux", "set-option", "-t", session, "mouse", "on").Run()
+
+	return tmuxAttach(agentID, session)
+}
+
+// requireTmuxSession fails for an agent whose session was created by a
+// multiplexer other than tmux. Agents from daemons that didn't record one
+// are tmux agents.
+func requireTmuxSession(a *mapv1.SpawnedAgentInfo) error {
+	if m := a.GetMultiplexer(); m != "" && m != daemon.MultiplexerTmux {
+		return fmt.Errorf("agent %s runs in a %s session; attach to it with %s", a.GetAgentId(), m, m)
+	}
+	return nil
+}
+
+// tmuxAttach prints the detach hints and attaches to a tmux session,
+// returning once the user detaches
+func tmuxAttach(agentID, session string) error {
+	fmt.Printf("Attaching to agent %s (tmux session: %s)\n", agentID, session)
 	fmt.Println()
 	fmt.Println("  Ctrl+B d     Detach (keeps agent running)")
 	fmt.Println("  Ctrl+C       Interrupts claude (session preserved)")
 	fmt.Println("  Ctrl+B n/p   Switch agents")
 	fmt.Println()
 
-	// Attach to the tmux session
-	// We need to use syscall.Exec to replace the current process
-	// so that tmux can properly take over the terminal
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return err
 	}
 
-	// Use exec.Command but connect stdin/stdout/stderr
-	attachCmd := exec.Command(tmuxPath, "attach", "-t", targetSession)
+	// Connect stdin/stdout/stderr so tmux can take over the terminal
+	attachCmd := exec.Command(tmuxPath, "attach", "-t", session)
 	attachCmd.Stdin = os.Stdin
 	attachCmd.Stdout = os.Stdout
 	attachCmd.Stderr = os.Stderr
-
 	return attachCmd.Run()
 }
 
-const watchAllSessionName = "map-watch-all"
-const maxWatchAgents = 6
-
 func runAgentWatchAll(agents []*mapv1.SpawnedAgentInfo) error {
 	// Limit to maxWatchAgents agents
 	if len(agents) > maxWatchAgents {
@@ -168,6 +359,9 @@ func runAgentWatchAll(agents []*mapv1.SpawnedAgentInfo) error {
 	// Verify all agent sessions exist
 	var validAgents []*mapv1.SpawnedAgentInfo
 	for _, a := range agents {
+		if a.GetHeadless() {
+			continue
+		}
 		checkCmd := exec.Command(tmuxPath, "has-session", "-t", a.GetLogFile())
 		if err := checkCmd.Run(); err == nil {
 			validAgents = append(validAgents, a)
@@ -181,9 +375,10 @@ func runAgentWatchAll(agents []*mapv1.SpawnedAgentInfo) error {
 	// Configure inner agent sessions: enable mouse and customize status bar
 	for _, a := range validAgents {
 		_ = exec.Command(tmuxPath, "set-option", "-t", a.GetLogFile(), "mouse", "on").Run()
-		// Show agent ID on left side of status bar
-		agentLabel := fmt.Sprintf(" %s ", a.GetAgentId())
-		_ = exec.Command(tmuxPath, "set-option", "-t", a.GetLogFile(), "status-left-length", "50").Run()
+		// Show agent ID and its window title (the current task) on the
+		// left side of the status bar
+		agentLabel := fmt.Sprintf(" %s | #W ", a.GetAgentId())
+		_ = exec.Command(tmuxPath, "set-option", "-t", a.GetLogFile(), "status-left-length", "80").Run()
 		_ = exec.Command(tmuxPath, "set-option", "-t", a.GetLogFile(), "status-left", agentLabel).Run()
 		// Hide right side of status bar (timestamp)
 		_ = exec.Command(tmuxPath, "set-option", "-t", a.GetLogFile(), "status-right", "").Run()
@@ -199,23 +394,50 @@ func runAgentWatchAll(agents []*mapv1.SpawnedAgentInfo) error {
 	// Use TMUX= to allow nested tmux attach
 	firstSession := validAgents[0].GetLogFile()
 	attachScript := fmt.Sprintf("TMUX= exec tmux attach -t %s", firstSession)
-	createCmd := exec.Command(tmuxPath, "new-session", "-d", "-s", watchAllSessionName, "sh", "-c", attachScript)
-	if err := createCmd.Run(); err != nil {
+	createCmd := exec.Command(tmuxPath, "new-session", "-d", "-s", watchAllSessionName, "-P", "-F", "#{pane_id}", "sh", "-c", attachScript)
+	out, err := createCmd.Output()
+	if err != nil {
 		return fmt.Errorf("create watch session: %w", err)
 	}
+	firstPane := strings.TrimSpace(string(out))
 
 	// Hide status bar on outer watch session
 	_ = exec.Command(tmuxPath, "set-option", "-t", watchAllSessionName, "status", "off").Run()
 
+	if watchGrid > 0 {
+		dropped := addGridPanes(tmuxPath, firstPane, validAgents, watchGrid)
+		fmt.Printf("Watching %d agents in %d columns\n", len(validAgents)-len(dropped), min(watchGrid, len(validAgents)))
+		if len(dropped) > 0 {
+			fmt.Printf("Warning: %d agent(s) left out of the grid: %s\n", len(dropped), strings.Join(dropped, ", "))
+		}
+	} else {
+		addTiledPanes(tmuxPath, validAgents)
+		fmt.Printf("Watching %d agents in tiled view\n", len(validAgents))
+	}
+	fmt.Println("Use Ctrl+B d to detach, Ctrl+B arrow keys to navigate panes")
+	fmt.Println()
+
+	// Attach to the watch-all session
+	attachCmd := exec.Command(tmuxPath, "attach", "-t", watchAllSessionName)
+	attachCmd.Stdin = os.Stdin
+	attachCmd.Stdout = os.Stdout
+	attachCmd.Stderr = os.Stderr
+
+	return attachCmd.Run()
+}
+
+// addTiledPanes adds a pane for each agent after the first to the watch
+// session, letting tmux's tiled layout arrange them
+func addTiledPanes(tmuxPath string, agents []*mapv1.SpawnedAgentInfo) {
 	// Add panes for remaining agents
-	for i := 1; i < len(validAgents); i++ {
-		agentSession := validAgents[i].GetLogFile()
+	for i := 1; i < len(agents); i++ {
+		agentSession := agents[i].GetLogFile()
 		attachScript := fmt.Sprintf("TMUX= exec tmux attach -t %s", agentSession)
 
 		// Split window and run attach command
 		splitCmd := exec.Command(tmuxPath, "split-window", "-t", watchAllSessionName, "sh", "-c", attachScript)
 		if err := splitCmd.Run(); err != nil {
-			fmt.Printf("Warning: failed to add pane for agent %s: %v\n", validAgents[i].GetAgentId(), err)
+			fmt.Printf("Warning: failed to add pane for agent %s: %v\n", agents[i].GetAgentId(), err)
 			continue
 		}
 
@@ -225,22 +447,10 @@ func runAgentWatchAll(agents []*mapv1.SpawnedAgentInfo) error {
 
 	// Final layout adjustment for 3-per-row arrangement
 	// For 4-6 agents, use main-horizontal with proper sizing
-	if len(validAgents) >= 4 && len(validAgents) <= 6 {
+	if len(agents) >= 4 && len(agents) <= 6 {
 		// Use tiled which gives a reasonable 2-row layout
 		_ = exec.Command(tmuxPath, "select-layout", "-t", watchAllSessionName, "tiled").Run()
 	}
-
-	fmt.Printf("Watching %d agents in tiled view\n", len(validAgents))
-	fmt.Println("Use Ctrl+B d to detach, Ctrl+B arrow keys to navigate panes")
-	fmt.Println()
-
-	// Attach to the watch-all session
-	attachCmd := exec.Command(tmuxPath, "attach", "-t", watchAllSessionName)
-	attachCmd.Stdin = os.Stdin
-	attachCmd.Stdout = os.Stdout
-	attachCmd.Stderr = os.Stderr
-
-	return attachCmd.Run()
 }
 
 // isPaneDead checks if a tmux pane's process has exited
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/daemon"
+)
+
+// paneSettlePoll is how often the pane is sampled while waiting for it to settle
+const paneSettlePoll = 250 * time.Millisecond
+
+// runAgentWatchCmd sends text to an agent's tmux session, submits it with
+// the agent type's submit keys, waits for the pane to stop changing, and
+// prints the output the text produced
+func runAgentWatchCmd(session, agentType, text string, settle, timeout time.Duration) error {
+	before, err := capturePane(session)
+	if err != nil {
+		return err
+	}
+
+	// Send the text literally, then wait for the terminal to finish
+	// rendering it (long pastes collapse into a preview) before submitting
+	if err := exec.Command("tmux", "send-keys", "-t", session, "-l", text).Run(); err != nil {
+		return fmt.Errorf("send text: %w", err)
+	}
+	if _, err := waitForPaneSettle(session, 500*time.Millisecond, 5*time.Second); err != nil {
+		return err
+	}
+	if err := daemon.PressSubmitKeys(context.Background(), session, agentSubmitKeys(agentType)); err != nil {
+		return err
+	}
+
+	after, err := waitForPaneSettle(session, settle, timeout)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(newPaneOutput(before, after))
+	return nil
+}
+
+// capturePane returns the full scrollback of a tmux pane
+func capturePane(session string) (string, error) {
+	out, err := exec.Command("tmux", "capture-pane", "-t", session, "-p", "-S", "-").Output()
+	if err != nil {
+		return "", fmt.Errorf("capture pane: %w", err)
+	}
+	return string(out), nil
+}
+
+// waitForPaneSettle polls a pane until its contents have not changed for the
+// settle duration and returns them. If the pane is still changing when the
+// timeout elapses, the latest contents are returned.
+func waitForPaneSettle(session string, settle, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	last, err := capturePane(session)
+	if err != nil {
+		return "", err
+	}
+	lastChange := time.Now()
+
+	for time.Since(lastChange) < settle && time.Now().Before(deadline) {
+		time.Sleep(paneSettlePoll)
+		current, err := capturePane(session)
+		if err != nil {
+			return "", err
+		}
+		if current != last {
+			last = current
+			lastChange = time.Now()
+		}
+	}
+	return last, nil
+}
+
+// newPaneOutput returns the lines of after that were not already present in
+// before. If the scrollback no longer shares a prefix (e.g. the pane was
+// cleared or history was trimmed), the whole of after is returned.
+func newPaneOutput(before, after string) string {
+	before = strings.TrimRight(before, "\n ")
+	after = strings.TrimRight(after, "\n ")
+
+	// The last line of before is usually the prompt the text was typed into,
+	// which is redrawn with the text, so compare up to the line before it
+	prefix := before
+	if i := strings.LastIndex(before, "\n"); i >= 0 {
+		prefix = before[:i+1]
+	} else {
+		prefix = ""
+	}
+
+	if strings.HasPrefix(after, prefix) {
+		return strings.Trim(after[len(prefix):], "\n")
+	}
+	return after
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// gridColumns assigns count agents, in order, to rows of up to cols panes,
+// returning the agent indexes in each column from top to bottom. With 5
+// agents in 2 columns, the left column holds 0, 2, 4 and the right 1, 3.
+func gridColumns(count, cols int) [][]int {
+	cols = min(cols, count)
+	if cols <= 0 {
+		return nil
+	}
+	columns := make([][]int, cols)
+	for i := range count {
+		columns[i%cols] = append(columns[i%cols], i)
+	}
+	return columns
+}
+
+// gridSplitPercent is the share of a pane, in percent, given to the new pane
+// when splitting a pane that must end up holding n equal slots: the existing
+// pane keeps one slot and the new pane takes the other n-1.
+func gridSplitPercent(n int) int {
+	return 100 * (n - 1) / n
+}
+
+// addGridPanes lays out agents in the watch session as a grid of cols
+// columns, starting from firstPane, which already shows agents[0]. Each
+// column is split off the one to its left and each row off the pane above,
+// sized so the columns share the width equally and each column's panes
+// share its height. A failed split also leaves out the panes that would have
+// been split off it, so the IDs of every agent left out are returned.
+func addGridPanes(tmuxPath, firstPane string, agents []*mapv1.SpawnedAgentInfo, cols int) (dropped []string) {
+	columns := gridColumns(len(agents), cols)
+	drop := func(indexes []int) {
+		for _, i := range indexes {
+			dropped = append(dropped, agents[i].GetAgentId())
+		}
+	}
+
+	// Column tops first, left to right, so every column gets an equal width.
+	// Once one fails, the columns to its right have nothing to split off.
+	tops := make([]string, len(columns))
+	tops[0] = firstPane
+	for c := 1; c < len(columns); c++ {
+		if tops[c-1] == "" {
+			continue
+		}
+		pane, err := splitWatchPane(tmuxPath, tops[c-1], "-h", gridSplitPercent(len(columns)-c+1), agents[columns[c][0]])
+		if err != nil {
+			fmt.Printf("Warning: failed to add pane for agent %s: %v\n", agents[columns[c][0]].GetAgentId(), err)
+			continue
+		}
+		tops[c] = pane
+	}
+
+	// Then each column's remaining rows, top to bottom
+	for c, column := range columns {
+		pane := tops[c]
+		if pane == "" {
+			drop(column)
+			continue
+		}
+		for r := 1; r < len(column); r++ {
+			next, err := splitWatchPane(tmuxPath, pane, "-v", gridSplitPercent(len(column)-r+1), agents[column[r]])
+			if err != nil {
+				fmt.Printf("Warning: failed to add pane for agent %s: %v\n", agents[column[r]].GetAgentId(), err)
+				drop(column[r:])
+				break
+			}
+			pane = next
+		}
+	}
+	return dropped
+}
+
+// splitWatchPane splits target in direction ("-h" or "-v"), giving the new
+// pane percent of its size, attaches the new pane to the agent's session,
+// and returns the new pane's ID
+func splitWatchPane(tmuxPath, target, direction string, percent int, agent *mapv1.SpawnedAgentInfo) (string, error) {
+	attachScript := fmt.Sprintf("TMUX= exec tmux attach -t %s", agent.GetLogFile())
+	out, err := exec.Command(tmuxPath, "split-window", direction, "-t", target, "-p", strconv.Itoa(percent),
+		"-P", "-F", "#{pane_id}", "sh", "-c", attachScript).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestGridColumns(t *testing.T) {
+	tests := []struct {
+		count, cols int
+		want        [][]int
+	}{
+		{4, 2, [][]int{{0, 2}, {1, 3}}},
+		{5, 2, [][]int{{0, 2, 4}, {1, 3}}},
+		{6, 3, [][]int{{0, 3}, {1, 4}, {2, 5}}},
+		{2, 4, [][]int{{0}, {1}}}, // fewer agents than columns
+		{3, 1, [][]int{{0, 1, 2}}},
+		{0, 2, nil},
+	}
+
+	for _, tt := range tests {
+		if got := gridColumns(tt.count, tt.cols); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("gridColumns(%d, %d) = %v, want %v", tt.count, tt.cols, got, tt.want)
+		}
+	}
+}
+
+func TestGridSplitPercent(t *testing.T) {
+	// Splitting off n-1 of n slots leaves every slot the same size: for 3
+	// columns the first split gives 66% to the new pane, which then splits
+	// 50/50
+	tests := map[int]int{2: 50, 3: 66, 4: 75}
+	for n, want := range tests {
+		if got := gridSplitPercent(n); got != want {
+			t.Errorf("gridSplitPercent(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestAddGridPanes_ReportsDropped(t *testing.T) {
+	// A fake tmux whose splits in one direction fail
+	fakeTmux := func(failing string) string {
+		script := filepath.Join(t.TempDir(), "tmux")
+		body := fmt.Sprintf("#!/bin/sh\n[ \"$2\" = %s ] && exit 1\necho %%1\n", failing)
+		if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return script
+	}
+	agents := make([]*mapv1.SpawnedAgentInfo, 5)
+	for i := range agents {
+		agents[i] = &mapv1.SpawnedAgentInfo{AgentId: fmt.Sprintf("agent-%d", i)}
+	}
+
+	tests := []struct {
+		failing string
+		want    []string
+	}{
+		// Columns hold 0, 2, 4 and 1, 3: failed rows leave out the rest of
+		// each column, and a failed column top leaves out its whole column
+		{"-v", []string{"agent-2", "agent-4", "agent-3"}},
+		{"-h", []string{"agent-1", "agent-3"}},
+	}
+	for _, tt := range tests {
+		var dropped []string
+		captureStdout(t, func() {
+			dropped = addGridPanes(fakeTmux(tt.failing), "%0", agents, 2)
+		})
+		if !reflect.DeepEqual(dropped, tt.want) {
+			t.Errorf("failing %s splits: dropped %v, want %v", tt.failing, dropped, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+)
+
+// paneFix is the restart that addresses a diagnosed crash
+type paneFix int
+
+const (
+	// fixNone means restarting won't help until the user fixes something
+	fixNone paneFix = iota
+	// fixRestart restarts the agent with its current flags
+	fixRestart
+	// fixRequirePermissions restarts the agent with permission prompts
+	fixRequirePermissions
+)
+
+// paneCrashPattern recognizes a fatal CLI error in a pane's visible content
+type paneCrashPattern struct {
+	match   string // lowercase substring to look for
+	problem string
+	hint    string
+	fix     paneFix
+	// deadOnly patterns only count once the CLI has exited, since the text
+	// can also show up in a running agent's tool output
+	deadOnly bool
+}
+
+// paneCrashPatterns are fatal errors printed by the agent CLIs. They are
+// checked in order, so more specific patterns come first.
+var paneCrashPatterns = []paneCrashPattern{
+	{
+		match:    "cannot be used with root/sudo privileges",
+		problem:  "permission bypass is refused when running as root",
+		hint:     "restart with permission prompts, or run the daemon as a regular user",
+		fix:      fixRequirePermissions,
+		deadOnly: true,
+	},
+	{
+		match:   "invalid api key",
+		problem: "authentication failed (invalid API key)",
+		hint:    "check the API key in the agent's environment, or log in again, then restart",
+		fix:     fixRestart,
+	},
+	{
+		match:   "please run /login",
+		problem: "the CLI is not logged in",
+		hint:    "attach and run /login, or log in from a terminal, then restart",
+		fix:     fixRestart,
+	},
+	{
+		match:   "oauth token has expired",
+		problem: "the login has expired",
+		hint:    "attach and run /login, or log in from a terminal, then restart",
+		fix:     fixRestart,
+	},
+	{
+		match:   "authentication_error",
+		problem: "authentication failed",
+		hint:    "log in again, then restart",
+		fix:     fixRestart,
+	},
+	{
+		match:   "credit balance is too low",
+		problem: "the account is out of credits",
+		hint:    "add credits to the account, then restart",
+		fix:     fixRestart,
+	},
+	{
+		match:    "command not found",
+		problem:  "the agent CLI is not installed or not on PATH",
+		hint:     "install the CLI where the daemon can find it, then restart the daemon",
+		fix:      fixNone,
+		deadOnly: true,
+	},
+	{
+		match:    "cannot find module",
+		problem:  "the agent CLI installation is broken",
+		hint:     "reinstall the CLI, then restart",
+		fix:      fixNone,
+		deadOnly: true,
+	},
+}
+
+// paneDiagnosis describes why an agent's pane looks crashed
+type paneDiagnosis struct {
+	problem string
+	hint    string
+	fix     paneFix
+	line    string // the pane line that matched, empty for a dead pane
+	// dead is set when the CLI has exited. Errors on a live pane may just
+	// be output the agent is printing, so only dead panes are repaired.
+	dead bool
+}
+
+// diagnosePane looks for a fatal error in a pane's title and visible content.
+// A dead pane with no recognizable error is diagnosed as a plain exit. It
+// returns nil if the pane looks healthy.
+func diagnosePane(title, content string, dead bool) *paneDiagnosis {
+	lines := append([]string{title}, strings.Split(content, "\n")...)
+	for _, p := range paneCrashPatterns {
+		if p.deadOnly && !dead {
+			continue
+		}
+		for _, line := range lines {
+			if strings.Contains(strings.ToLower(line), p.match) {
+				return &paneDiagnosis{problem: p.problem, hint: p.hint, fix: p.fix, line: strings.TrimSpace(line), dead: dead}
+			}
+		}
+	}
+
+	if dead {
+		return &paneDiagnosis{problem: "the agent CLI exited", fix: fixRestart, dead: true}
+	}
+	return nil
+}
+
+// inspectPane diagnoses a tmux session's pane from its title and the
+// content currently on screen. Only the visible screen is checked so errors
+// that scrolled away after a recovery are ignored.
+func inspectPane(session string) *paneDiagnosis {
+	title, _ := exec.Command("tmux", "display-message", "-t", session, "-p", "#{pane_title}").Output()
+	content, _ := exec.Command("tmux", "capture-pane", "-t", session, "-p").Output()
+	return diagnosePane(strings.TrimSpace(string(title)), string(content), isPaneDead(session))
+}
+
+// String renders the diagnosis for error messages
+func (d *paneDiagnosis) String() string {
+	s := d.problem
+	if d.line != "" {
+		s += fmt.Sprintf(" (%q)", d.line)
+	}
+	if d.hint != "" {
+		s += "; " + d.hint
+	}
+	return s
+}
+
+// offerPaneRepair explains a crashed agent pane and lets the user restart it
+// before attaching. It reports whether to go on and attach. A live pane is
+// only warned about: restarting would kill an agent that may still be
+// working.
+func offerPaneRepair(c *client.Client, agentID string, diag *paneDiagnosis) (bool, error) {
+	if !diag.dead {
+		fmt.Fprintf(os.Stderr, "warning: agent %s may have a problem: %s\n", agentID, diag)
+		return true, nil
+	}
+
+	fmt.Printf("Agent %s looks crashed: %s\n", agentID, diag.problem)
+	if diag.line != "" {
+		fmt.Printf("  %s\n", diag.line)
+	}
+	if diag.hint != "" {
+		fmt.Printf("Suggestion: %s\n", diag.hint)
+	}
+	fmt.Println()
+
+	choice := "a"
+	fmt.Println("  [r] restart the agent")
+	if diag.fix == fixRequirePermissions {
+		fmt.Println("  [p] restart with permission prompts")
+		choice = "p"
+	} else if diag.fix == fixRestart {
+		choice = "r"
+	}
+	fmt.Println("  [a] attach anyway")
+	fmt.Println("  [q] quit")
+	fmt.Printf("Choice [%s]: ", choice)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	if response = strings.TrimSpace(strings.ToLower(response)); response != "" {
+		choice = response[:1]
+	}
+
+	switch choice {
+	case "r", "p":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		resp, err := c.RespawnAgent(ctx, agentID, true, choice == "p")
+		if err != nil {
+			return false, fmt.Errorf("respawn agent: %w", err)
+		}
+		if !resp.Success {
+			return false, fmt.Errorf("respawn failed: %s", resp.Message)
+		}
+		fmt.Println("Agent restarted.")
+		// Give it a moment to start
+		time.Sleep(300 * time.Millisecond)
+		return true, nil
+	case "a":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
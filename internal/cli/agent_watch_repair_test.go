@@ -0,0 +1,79 @@
+package cli
+
+import "testing"
+
+func TestDiagnosePane(t *testing.T) {
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		dead    bool
+		want    string // expected problem, empty for healthy
+		fix     paneFix
+	}{
+		{
+			name:    "healthy",
+			title:   "✳ Working",
+			content: "> refactor the parser\n● Reading parser.go",
+		},
+		{
+			name:    "invalid api key on a live pane",
+			content: "Welcome\nInvalid API key · Please run /login\n>",
+			want:    "authentication failed (invalid API key)",
+			fix:     fixRestart,
+		},
+		{
+			name:  "error in the pane title",
+			title: "OAuth token has expired",
+			want:  "the login has expired",
+			fix:   fixRestart,
+		},
+		{
+			name:    "root refuses permission bypass",
+			content: "--dangerously-skip-permissions cannot be used with root/sudo privileges for security reasons",
+			dead:    true,
+			want:    "permission bypass is refused when running as root",
+			fix:     fixRequirePermissions,
+		},
+		{
+			name:    "tool output mentioning a missing command is not a crash",
+			content: "● Bash(make lint)\n  ⎿ sh: golangci-lint: command not found",
+		},
+		{
+			name:    "missing CLI in a dead pane",
+			content: "sh: claude: command not found",
+			dead:    true,
+			want:    "the agent CLI is not installed or not on PATH",
+			fix:     fixNone,
+		},
+		{
+			name:    "dead pane without a known error",
+			content: "goodbye",
+			dead:    true,
+			want:    "the agent CLI exited",
+			fix:     fixRestart,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diag := diagnosePane(tt.title, tt.content, tt.dead)
+			if tt.want == "" {
+				if diag != nil {
+					t.Fatalf("diagnosePane = %+v, want healthy", diag)
+				}
+				return
+			}
+			if diag == nil {
+				t.Fatalf("diagnosePane = nil, want %q", tt.want)
+			}
+			if diag.problem != tt.want || diag.fix != tt.fix {
+				t.Errorf("diagnosePane = %q (fix %d), want %q (fix %d)", diag.problem, diag.fix, tt.want, tt.fix)
+			}
+			// Only a dead pane is offered a restart
+			if diag.dead != tt.dead {
+				t.Errorf("diagnosePane dead = %v, want %v", diag.dead, tt.dead)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	"github.com/spf13/cobra"
 )
 
@@ -14,15 +15,23 @@ var agentsCmd = &cobra.Command{
 	Use:     "agents",
 	Aliases: []string{"ag"},
 	Short:   "List spawned agents",
-	Long:    `List all agents spawned by the daemon (claude and codex).`,
-	RunE:    runAgents,
+	Long: `List all agents spawned by the daemon (` + strings.Join(daemon.AgentTypes(), ", ") + `).
+
+Use --label key=value (repeatable) to only list agents carrying those labels.`,
+	RunE: runAgents,
 }
 
 func init() {
+	agentsCmd.Flags().StringArray("label", nil, "Only list agents with this key=value label (repeatable)")
 	rootCmd.AddCommand(agentsCmd)
 }
 
 func runAgents(cmd *cobra.Command, args []string) error {
+	labels, err := labelsFlag(cmd)
+	if err != nil {
+		return err
+	}
+
 	c, err := client.New(getSocketPath())
 	if err != nil {
 		return fmt.Errorf("connect to daemon: %w", err)
@@ -34,24 +43,29 @@ func runAgents(cmd *cobra.Command, args []string) error {
 
 	// Filter by current repo
 	repoRoot := getRepoRoot()
-	agents, err := c.ListSpawnedAgents(ctx, repoRoot)
+	agents, err := c.ListSpawnedAgentsWithLabels(ctx, repoRoot, labels)
 	if err != nil {
 		return fmt.Errorf("list agents: %w", err)
 	}
 
 	if len(agents) == 0 {
+		if len(labels) > 0 {
+			fmt.Println("no agents match the given labels")
+			return nil
+		}
 		fmt.Println("no agents spawned")
 		return nil
 	}
 
-	fmt.Printf("%-25s %-8s %s\n", "AGENT ID", "TYPE", "WORKTREE")
-	fmt.Println(strings.Repeat("-", 75))
+	fmt.Printf("%-25s %-8s %-40s %s\n", "AGENT ID", "TYPE", "WORKTREE", "LABELS")
+	fmt.Println(strings.Repeat("-", 100))
 
 	for _, agent := range agents {
-		fmt.Printf("%-25s %-8s %s\n",
+		fmt.Printf("%-25s %-8s %-40s %s\n",
 			truncate(agent.AgentId, 25),
 			agent.AgentType,
 			truncate(agent.WorktreePath, 40),
+			formatLabels(agent.Labels),
 		)
 	}
 
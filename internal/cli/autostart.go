@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// daemonlessCommands are top-level commands that work without a running
+// daemon, or manage its lifecycle, and so never trigger an auto-start
+var daemonlessCommands = map[string]bool{
+	"up":                            true,
+	"down":                          true,
+	"restart":                       true,
+	"status":                        true,
+	"clean":                         true,
+	"logs":                          true,
+	"config":                        true,
+	"completion":                    true,
+	"help":                          true,
+	cobra.ShellCompRequestCmd:       true,
+	cobra.ShellCompNoDescRequestCmd: true,
+}
+
+// daemonReadyTimeout bounds how long an auto-start waits for the socket
+const daemonReadyTimeout = 10 * time.Second
+
+// needsDaemon reports whether cmd talks to the daemon
+func needsDaemon(cmd *cobra.Command) bool {
+	if cmd == rootCmd {
+		return false
+	}
+	top := cmd
+	for top.HasParent() && top.Parent() != rootCmd {
+		top = top.Parent()
+	}
+	return !daemonlessCommands[top.Name()]
+}
+
+// ensureDaemon starts the daemon in the background when auto-start-daemon is
+// enabled and nothing is listening on the socket. Concurrent invocations
+// serialize on a lock file next to the socket so only one daemon is started.
+func ensureDaemon() error {
+	socketPath := getSocketPath()
+	if !viper.GetBool("auto-start-daemon") || client.IsDaemonRunning(socketPath) {
+		return nil
+	}
+
+	unlock, err := lockDaemonStart(socketPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Another command may have started the daemon while we waited for the lock
+	if client.IsDaemonRunning(socketPath) {
+		return nil
+	}
+
+	pid, err := startDaemonProcess()
+	if err != nil {
+		return err
+	}
+
+	if err := waitForDaemon(socketPath, pid); err != nil {
+		return err
+	}
+
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "mapd started (pid %d)\n", pid)
+	}
+	return nil
+}
+
+// lockDaemonStart takes the lock file next to the socket that serializes
+// starting a daemon, returning a function that releases it
+func lockDaemonStart(socketPath string) (func(), error) {
+	lock, err := os.OpenFile(socketPath+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open daemon lock: %w", err)
+	}
+
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("lock daemon start: %w", err)
+	}
+	return func() {
+		_ = syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
+		_ = lock.Close()
+	}, nil
+}
+
+// waitForDaemon waits up to daemonReadyTimeout for the daemon started as
+// pid to answer pings on socketPath. Listening alone isn't enough: the
+// socket is bound before the daemon can serve RPCs.
+func waitForDaemon(socketPath string, pid int) error {
+	deadline := time.Now().Add(daemonReadyTimeout)
+	for !client.IsDaemonResponsive(socketPath, 500*time.Millisecond) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("daemon (pid %d) did not start answering on %s within %s", pid, socketPath, daemonReadyTimeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script for map.
+
+Agent and task IDs are completed dynamically by querying the daemon, so
+'map agent kill <TAB>' suggests running agents.
+
+To load completions:
+
+  bash:  source <(map completion bash)
+  zsh:   map completion zsh > "${fpath[1]}/_map"
+  fish:  map completion fish > ~/.config/fish/completions/map.fish`,
+	Args:                  cobra.ExactArgs(1),
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	DisableFlagsInUseLine: true,
+	RunE:                  runCompletion,
+}
+
+func init() {
+	// Replace cobra's default completion command with ours
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(completionCmd)
+
+	agentKillCmd.ValidArgsFunction = completeAgentIDs
+	agentRespawnCmd.ValidArgsFunction = completeAgentIDs
+	agentMergeCmd.ValidArgsFunction = completeAgentIDs
+	agentWatchCmd.ValidArgsFunction = completeAgentIDs
+	agentTaskCmd.ValidArgsFunction = completeAgentIDs
+	agentHistoryCmd.ValidArgsFunction = completeAgentIDs
+	worktreeOpenCmd.ValidArgsFunction = completeAgentIDs
+	taskShowCmd.ValidArgsFunction = completeTaskIDs
+	taskCancelCmd.ValidArgsFunction = completeTaskIDs
+	taskNudgeCmd.ValidArgsFunction = completeTaskIDs
+	taskRetryCmd.ValidArgsFunction = completeTaskIDs
+	taskLogCmd.ValidArgsFunction = completeTaskIDs
+	taskAttemptsCmd.ValidArgsFunction = completeTaskIDs
+	taskInputNeededCmd.ValidArgsFunction = completeTaskIDs
+}
+
+func runCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(out, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(out)
+	case "fish":
+		return rootCmd.GenFishCompletion(out, true)
+	default:
+		return fmt.Errorf("unsupported shell %q: must be bash, zsh, or fish", args[0])
+	}
+}
+
+// completionClient connects to the daemon for dynamic completions. Completion
+// runs without the root PersistentPreRunE, so config is loaded here.
+func completionClient() (*client.Client, bool) {
+	_ = initConfig()
+	if !client.IsDaemonRunning(getSocketPath()) {
+		return nil, false
+	}
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return nil, false
+	}
+	return c, true
+}
+
+// completeAgentIDs suggests the IDs of running agents for the first argument
+func completeAgentIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	c, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	agents, err := c.ListSpawnedAgents(ctx, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list agents: %v\n", err)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, agent := range agents {
+		if strings.HasPrefix(agent.AgentId, toComplete) {
+			ids = append(ids, agent.AgentId+"\t"+agent.AgentType)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTaskIDs suggests task IDs for the first argument, described by status
+// and a truncated description
+func completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	c, ok := completionClient()
+	if !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	tasks, err := c.ListTasks(ctx, 50, "", false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "list tasks: %v\n", err)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, task := range tasks {
+		if strings.HasPrefix(task.TaskId, toComplete) {
+			desc := fmt.Sprintf("%s: %s", taskStatusString(task.Status), truncate(task.Description, 40))
+			ids = append(ids, task.TaskId+"\t"+desc)
+		}
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
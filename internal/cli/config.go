@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -55,23 +56,71 @@ Examples:
 	RunE: runConfigSet,
 }
 
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List named agent spawn profiles",
+	Long: `List the spawn profiles defined under "profiles" in the config file.
+
+Profiles are used as defaults by 'map agent create --profile <name>'.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigProfiles,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configProfilesCmd)
 }
 
 // initConfig reads in config file and ENV variables if set
 func initConfig() error {
 	// Set defaults
 	viper.SetDefault("socket", "/tmp/mapd.sock")
+	viper.SetDefault("socket-mode", "0600")
 	viper.SetDefault("data-dir", filepath.Join(os.Getenv("HOME"), ".mapd"))
+	viper.SetDefault("auto-start-daemon", false)
+	viper.SetDefault("event-buffer", 1000)
 	viper.SetDefault("agent.default-type", "claude")
 	viper.SetDefault("agent.default-count", 1)
 	viper.SetDefault("agent.default-branch", "")
 	viper.SetDefault("agent.use-worktree", true)
+	viper.SetDefault("agent.spawn-delay", "500ms")
 	viper.SetDefault("agent.skip-permissions", true)
+	viper.SetDefault("agent.git-config", false)
+	viper.SetDefault("agent.git-user-name", "map-agent {{.AgentID}}")
+	viper.SetDefault("agent.git-user-email", "{{.AgentID}}@map.local")
+	viper.SetDefault("agent.max-agents", 20)
+	viper.SetDefault("agent.warm-pool", 0)
+	viper.SetDefault("agent.prompt-confirm-wait", "3s")
+	viper.SetDefault("agent.prompt-retries", 2)
+	for _, agentType := range daemon.AgentTypes() {
+		viper.SetDefault(submitKeysKey(agentType), daemon.LookupAgentType(agentType).SubmitKeys)
+	}
+	viper.SetDefault("agent.stuck-threshold", "10m")
+	viper.SetDefault("agent.boot-timeout", "1s")
+	viper.SetDefault("agent.heartbeat-interval", "1m")
+	viper.SetDefault("agent.warm-pool-repo", "")
+	viper.SetDefault("agent.runtime", "native")
+	viper.SetDefault("agent.image", "")
+	viper.SetDefault("task.scheduling-strategy", "round-robin")
+	viper.SetDefault("task.auto-spawn", false)
+	viper.SetDefault("task.auto-spawn-max", 3)
+	viper.SetDefault("task.auto-spawn-type", "")
+	viper.SetDefault("task.max-retries", 3)
+	viper.SetDefault("task.max-description-bytes", 32*1024)
+	viper.SetDefault("task.max-result-bytes", 1024*1024)
+	viper.SetDefault("task.result-files", false)
+	viper.SetDefault("worktree.branch-per-agent", false)
+	viper.SetDefault("github.timeout", "30s")
+	viper.SetDefault("input-monitor.github", true)
+	viper.SetDefault("input-monitor.webhook-url", "")
+	viper.SetDefault("sync.state-file", "")
+	viper.SetDefault("sync.no-worktree-labels", []string{})
+	viper.SetDefault("security.allowed-repos", []string{})
+	viper.SetDefault("routing.rules", []any{})
+	viper.SetDefault("worktree.branch-template", "map/{{.AgentID}}")
 
 	if cfgFile != "" {
 		// Use config file from the flag
@@ -105,6 +154,9 @@ func initConfig() error {
 	if err := viper.BindPFlag("socket", rootCmd.PersistentFlags().Lookup("socket")); err != nil {
 		return fmt.Errorf("bind socket flag: %w", err)
 	}
+	if err := viper.BindPFlag("quiet", rootCmd.PersistentFlags().Lookup("quiet")); err != nil {
+		return fmt.Errorf("bind quiet flag: %w", err)
+	}
 
 	return nil
 }
@@ -187,3 +239,32 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("set %s = %v\n", key, viper.Get(key))
 	return nil
 }
+
+func runConfigProfiles(cmd *cobra.Command, args []string) error {
+	profiles := viper.GetStringMap("profiles")
+	if len(profiles) == 0 {
+		fmt.Println("no profiles configured")
+		return nil
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%s:\n", name)
+		settings := viper.GetStringMap(profilePrefix + name)
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("  %-20s %v\n", key, settings[key])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,356 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config file for unknown keys and bad values",
+	Long: `Check the config file for keys MAP doesn't recognize and values of the
+wrong type, e.g. a misspelled key or a non-numeric agent.default-count.
+Unknown keys are otherwise ignored silently.
+
+Exits non-zero if any problem is found.
+
+Examples:
+  map config validate
+  map --config ./team.yaml config validate`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+
+	for _, agentType := range daemon.AgentTypes() {
+		configSchema[submitKeysKey(agentType)] = kindStringList
+	}
+}
+
+// configKind is the type of value a config key expects
+type configKind int
+
+const (
+	kindString configKind = iota
+	kindInt
+	kindBool
+	kindDuration
+	kindStringList
+	kindRoutingRules
+)
+
+func (k configKind) String() string {
+	switch k {
+	case kindInt:
+		return "an integer"
+	case kindBool:
+		return "true or false"
+	case kindDuration:
+		return "a duration (e.g. 30s)"
+	case kindStringList:
+		return "a list of strings"
+	case kindRoutingRules:
+		return "a list of rules with agent-type and scope, match, or labels"
+	default:
+		return "a string"
+	}
+}
+
+// configSchema lists every config key MAP reads and the type it expects.
+// Keep it in step with the defaults in initConfig.
+var configSchema = map[string]configKind{
+	"socket":                     kindString,
+	"socket-mode":                kindString,
+	"data-dir":                   kindString,
+	"auto-start-daemon":          kindBool,
+	"event-buffer":               kindInt,
+	"quiet":                      kindBool,
+	"agent.default-type":         kindString,
+	"agent.default-count":        kindInt,
+	"agent.default-branch":       kindString,
+	"agent.use-worktree":         kindBool,
+	"agent.spawn-delay":          kindDuration,
+	"agent.skip-permissions":     kindBool,
+	"agent.git-config":           kindBool,
+	"agent.git-user-name":        kindString,
+	"agent.git-user-email":       kindString,
+	"agent.max-agents":           kindInt,
+	"agent.warm-pool":            kindInt,
+	"agent.warm-pool-repo":       kindString,
+	"agent.prompt-confirm-wait":  kindDuration,
+	"agent.prompt-retries":       kindInt,
+	"agent.stuck-threshold":      kindDuration,
+	"agent.boot-timeout":         kindDuration,
+	"agent.heartbeat-interval":   kindDuration,
+	"agent.runtime":              kindString,
+	"agent.image":                kindString,
+	"task.scheduling-strategy":   kindString,
+	"task.auto-spawn":            kindBool,
+	"task.auto-spawn-max":        kindInt,
+	"task.auto-spawn-type":       kindString,
+	"task.max-retries":           kindInt,
+	"task.max-description-bytes": kindInt,
+	"task.max-result-bytes":      kindInt,
+	"task.result-files":          kindBool,
+	"worktree.branch-per-agent":  kindBool,
+	"worktree.branch-template":   kindString,
+	"github.timeout":             kindDuration,
+	"input-monitor.github":       kindBool,
+	"input-monitor.webhook-url":  kindString,
+	"sync.state-file":            kindString,
+	"sync.no-worktree-labels":    kindStringList,
+	"security.allowed-repos":     kindStringList,
+	"routing.rules":              kindRoutingRules,
+}
+
+// routingRuleSchema lists the settings a rule under routing.rules may set
+var routingRuleSchema = map[string]configKind{
+	"scope":      kindStringList,
+	"match":      kindString,
+	"labels":     kindStringList,
+	"agent-type": kindString,
+}
+
+// profileSchema lists the settings a spawn profile under "profiles" may set
+var profileSchema = map[string]configKind{
+	"agent-type":       kindString,
+	"count":            kindInt,
+	"branch":           kindString,
+	"worktree":         kindBool,
+	"name":             kindString,
+	"prompt":           kindString,
+	"skip-permissions": kindBool,
+	"spawn-delay":      kindDuration,
+	"git-config":       kindBool,
+}
+
+// configProblem is one issue found in a config file
+type configProblem struct {
+	key     string
+	message string
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	problems := validateConfig(v.AllSettings())
+	if len(problems) == 0 {
+		fmt.Printf("%s: ok\n", path)
+		return nil
+	}
+
+	for _, p := range problems {
+		fmt.Printf("%s: %s: %s\n", path, p.key, p.message)
+	}
+	return fmt.Errorf("config file has %d problem(s)", len(problems))
+}
+
+// configFilePath returns the config file validate should check: --config
+// when given, otherwise the file viper loaded or ~/.mapd/config.yaml
+func configFilePath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("get home directory: %w", err)
+	}
+	return filepath.Join(home, ".mapd", "config.yaml"), nil
+}
+
+// validateConfig checks settings read from a config file against the known
+// keys, returning problems sorted by key
+func validateConfig(settings map[string]any) []configProblem {
+	var problems []configProblem
+	for key, value := range flattenConfig("", settings) {
+		if name, setting, ok := splitProfileKey(key); ok {
+			kind, known := profileSchema[setting]
+			if !known {
+				problems = append(problems, configProblem{key, fmt.Sprintf("unknown setting for profile %q", name)})
+				continue
+			}
+			if !configValueMatches(kind, value) {
+				problems = append(problems, configProblem{key, fmt.Sprintf("expected %s, got %v", kind, value)})
+			}
+			continue
+		}
+
+		kind, known := configSchema[key]
+		if !known {
+			msg := "unknown key"
+			if hint := suggestConfigKey(key); hint != "" {
+				msg += fmt.Sprintf(" (did you mean %s?)", hint)
+			}
+			problems = append(problems, configProblem{key, msg})
+			continue
+		}
+		if !configValueMatches(kind, value) {
+			problems = append(problems, configProblem{key, fmt.Sprintf("expected %s, got %v", kind, value)})
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].key < problems[j].key })
+	return problems
+}
+
+// flattenConfig turns nested settings into dotted keys. A section holding
+// no settings is kept as a key of its own so it still gets checked.
+func flattenConfig(prefix string, settings map[string]any) map[string]any {
+	flat := make(map[string]any)
+	for key, value := range settings {
+		full := key
+		if prefix != "" {
+			full = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			for k, v := range flattenConfig(full, nested) {
+				flat[k] = v
+			}
+			continue
+		}
+		flat[full] = value
+	}
+	return flat
+}
+
+// splitProfileKey splits "profiles.<name>.<setting>" into its name and
+// setting
+func splitProfileKey(key string) (name, setting string, ok bool) {
+	rest, found := strings.CutPrefix(key, profilePrefix)
+	if !found {
+		return "", "", false
+	}
+	name, setting, ok = strings.Cut(rest, ".")
+	return name, setting, ok
+}
+
+// configValueMatches reports whether a value read from YAML fits kind.
+// Quoted numbers and booleans are accepted, since viper converts them.
+func configValueMatches(kind configKind, value any) bool {
+	switch kind {
+	case kindInt:
+		switch v := value.(type) {
+		case int, int64, uint64:
+			return true
+		case string:
+			_, err := strconv.Atoi(v)
+			return err == nil
+		}
+		return false
+	case kindBool:
+		switch v := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(v)
+			return err == nil
+		}
+		return false
+	case kindDuration:
+		switch v := value.(type) {
+		case int, int64:
+			// viper reads a bare number as nanoseconds; 0 is the common case
+			return true
+		case string:
+			_, err := time.ParseDuration(v)
+			return err == nil
+		}
+		return false
+	case kindStringList:
+		switch v := value.(type) {
+		case string:
+			return true
+		case []any:
+			for _, item := range v {
+				if _, ok := item.(string); !ok {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	case kindRoutingRules:
+		rules, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range rules {
+			rule, ok := item.(map[string]any)
+			if !ok || rule["agent-type"] == nil {
+				return false
+			}
+			for key, v := range rule {
+				kind, known := routingRuleSchema[key]
+				if !known || !configValueMatches(kind, v) {
+					return false
+				}
+			}
+		}
+		return true
+	default:
+		switch value.(type) {
+		case string, int, int64, float64, bool:
+			return true
+		}
+		return false
+	}
+}
+
+// suggestConfigKey returns the known key closest to an unknown one, if it
+// is close enough to likely be a typo
+func suggestConfigKey(key string) string {
+	best, bestDist := "", 3
+	for known := range configSchema {
+		if d := editDistance(key, known); d < bestDist || (d == bestDist && best != "" && known < best) {
+			best, bestDist = known, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	"github.com/spf13/viper"
+)
+
+func TestValidateConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `socket: /tmp/mapd.sock
+agent:
+  default-count: three
+  use-worktree: true
+  spawn-delay: 1s
+  defualt-type: codex
+task:
+  max-retries: "5"
+github:
+  timeout: soon
+security:
+  allowed-repos: [~/code/*]
+routing:
+  rules:
+    - scope: [docs/**]
+      agent-type: gemini
+    - match: refactor
+      labels: [backend]
+      agent-type: codex
+profiles:
+  backend:
+    agent-type: codex
+    count: 2
+    colour: blue
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	problems := validateConfig(v.AllSettings())
+	got := make(map[string]string, len(problems))
+	for _, p := range problems {
+		got[p.key] = p.message
+	}
+
+	want := map[string]string{
+		"agent.default-count":     "expected an integer, got three",
+		"agent.defualt-type":      "unknown key (did you mean agent.default-type?)",
+		"github.timeout":          "expected a duration (e.g. 30s), got soon",
+		"profiles.backend.colour": `unknown setting for profile "backend"`,
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d problems %v, want %d", len(got), got, len(want))
+	}
+	for key, msg := range want {
+		if got[key] != msg {
+			t.Errorf("problem for %s = %q, want %q", key, got[key], msg)
+		}
+	}
+}
+
+func TestValidateConfig_Clean(t *testing.T) {
+	settings := map[string]any{
+		"socket": "/tmp/mapd.sock",
+		"agent":  map[string]any{"default-count": 2, "stuck-threshold": "5m"},
+	}
+	if problems := validateConfig(settings); len(problems) != 0 {
+		t.Errorf("validateConfig() = %v, want no problems", problems)
+	}
+}
+
+func TestValidateConfig_SubmitKeysPerAgentType(t *testing.T) {
+	agent := make(map[string]any)
+	for _, agentType := range daemon.AgentTypes() {
+		agent[agentType+"-submit-keys"] = []any{"Escape", "Enter"}
+	}
+	if problems := validateConfig(map[string]any{"agent": agent}); len(problems) != 0 {
+		t.Errorf("validateConfig() = %v, want no problems", problems)
+	}
+}
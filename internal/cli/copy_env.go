@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmarsceill/mapcli/internal/daemon"
+)
+
+// copyEnv looks up the variables named by --copy-env so they can be set in
+// an agent's session. Names that aren't set are returned in missing rather
+// than copied as empty values.
+func copyEnv(names []string, lookup func(string) (string, bool)) (env map[string]string, missing []string, err error) {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !daemon.EnvNamePattern.MatchString(name) {
+			return nil, nil, fmt.Errorf("invalid --copy-env name %q: want a variable name like NPM_TOKEN", name)
+		}
+		value, ok := lookup(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		if env == nil {
+			env = make(map[string]string, len(names))
+		}
+		env[name] = value
+	}
+	return env, missing, nil
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCopyEnv(t *testing.T) {
+	host := map[string]string{"NPM_TOKEN": "secret", "AWS_PROFILE": "dev", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := host[name]
+		return v, ok
+	}
+
+	env, missing, err := copyEnv([]string{"NPM_TOKEN", " AWS_PROFILE", "EMPTY", "UNSET"}, lookup)
+	if err != nil {
+		t.Fatalf("copyEnv: %v", err)
+	}
+	want := map[string]string{"NPM_TOKEN": "secret", "AWS_PROFILE": "dev", "EMPTY": ""}
+	if !maps.Equal(env, want) {
+		t.Errorf("env = %v, want %v", env, want)
+	}
+	if !slices.Equal(missing, []string{"UNSET"}) {
+		t.Errorf("missing = %v, want [UNSET]", missing)
+	}
+
+	if env, _, err := copyEnv(nil, lookup); err != nil || env != nil {
+		t.Errorf("copyEnv(nil) = %v, %v; want nil, nil", env, err)
+	}
+
+	for _, name := range []string{"", "1ABC", "A=B", "WITH SPACE"} {
+		if _, _, err := copyEnv([]string{name}, lookup); err == nil {
+			t.Errorf("copyEnv(%q) should fail", name)
+		}
+	}
+}
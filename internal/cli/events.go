@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show recent events",
+	Long: `Show events recorded by the daemon, oldest first.
+
+Unlike 'map watch', which only streams events as they happen, this queries
+the daemon's event log so past activity can be reviewed after the fact.
+
+Examples:
+  map events
+  map events --since 1h
+  map events --type task-completed --type task-failed
+  map events --since 2025-01-02T15:04:05Z --limit 200`,
+	Args: cobra.NoArgs,
+	RunE: runEvents,
+}
+
+var (
+	eventsLimit int32
+	eventsTypes []string
+	eventsSince string
+)
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+
+	eventsCmd.Flags().Int32VarP(&eventsLimit, "limit", "n", 50, "maximum number of events to show")
+	eventsCmd.Flags().StringSliceVarP(&eventsTypes, "type", "t", nil, "only show events of this type (e.g. task-completed, agent); repeatable")
+	eventsCmd.Flags().StringVar(&eventsSince, "since", "", "only show events newer than a duration (e.g. 30m, 2h) or RFC3339 time")
+}
+
+func runEvents(cmd *cobra.Command, args []string) error {
+	var types []mapv1.EventType
+	for _, t := range eventsTypes {
+		et, err := parseEventType(t)
+		if err != nil {
+			return err
+		}
+		types = append(types, et)
+	}
+
+	since, err := parseSince(eventsSince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	events, err := c.ListEvents(ctx, eventsLimit, types, since)
+	if err != nil {
+		return fmt.Errorf("list events: %w", err)
+	}
+
+	if len(events) == 0 {
+		fmt.Println("no events found")
+		return nil
+	}
+
+	// The daemon returns the most recent events first; print oldest first
+	// so the output reads like 'map watch'
+	for i := len(events) - 1; i >= 0; i-- {
+		if line := formatEvent(events[i], "2006-01-02 15:04:05"); line != "" {
+			fmt.Println(line)
+		}
+	}
+
+	return nil
+}
+
+// parseEventType converts a user-supplied event type such as "task-completed",
+// "completed", or "EVENT_TYPE_TASK_COMPLETED" into an EventType. "agent"
+// selects agent lifecycle events, which carry no specific type.
+func parseEventType(s string) (mapv1.EventType, error) {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(strings.TrimSpace(s)))
+	if name == "AGENT" {
+		return mapv1.EventType_EVENT_TYPE_UNSPECIFIED, nil
+	}
+
+	name = strings.TrimPrefix(name, "EVENT_TYPE_")
+	for _, candidate := range []string{"EVENT_TYPE_" + name, "EVENT_TYPE_TASK_" + name} {
+		if v, ok := mapv1.EventType_value[candidate]; ok && v != 0 {
+			return mapv1.EventType(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown event type %q (e.g. task-created, task-completed, task-failed, agent)", s)
+}
+
+// parseSince interprets --since as either a duration before now or an
+// RFC3339 timestamp. An empty value means no lower bound.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration (e.g. 2h) or RFC3339 time", s)
+}
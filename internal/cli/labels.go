@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// parseLabels turns key=value pairs from --label flags into a map. A key may
+// only be given once.
+func parseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q: want key=value", pair)
+		}
+		if strings.ContainsAny(key, ", ") {
+			return nil, fmt.Errorf("invalid label %q: key must not contain spaces or commas", pair)
+		}
+		if _, dup := labels[key]; dup {
+			return nil, fmt.Errorf("label %q given more than once", key)
+		}
+		labels[key] = strings.TrimSpace(value)
+	}
+	return labels, nil
+}
+
+// labelsFlag parses the command's --label flags
+func labelsFlag(cmd *cobra.Command) (map[string]string, error) {
+	pairs, _ := cmd.Flags().GetStringArray("label")
+	return parseLabels(pairs)
+}
+
+// formatLabels renders labels as sorted key=value pairs, or "-" for none
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
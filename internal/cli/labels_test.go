@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := parseLabels([]string{"team=backend", "tier = fast", "empty="})
+	if err != nil {
+		t.Fatalf("parseLabels failed: %v", err)
+	}
+	want := map[string]string{"team": "backend", "tier": "fast", "empty": ""}
+	if !maps.Equal(labels, want) {
+		t.Errorf("labels = %v, want %v", labels, want)
+	}
+
+	if labels, err := parseLabels(nil); err != nil || labels != nil {
+		t.Errorf("parseLabels(nil) = %v, %v; want nil, nil", labels, err)
+	}
+
+	for _, bad := range [][]string{{"team"}, {"=backend"}, {"a b=c"}, {"team=a", "team=b"}} {
+		if _, err := parseLabels(bad); err == nil {
+			t.Errorf("parseLabels(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestFormatLabels(t *testing.T) {
+	if got := formatLabels(nil); got != "-" {
+		t.Errorf("formatLabels(nil) = %q, want -", got)
+	}
+	if got := formatLabels(map[string]string{"tier": "fast", "team": "backend"}); got != "team=backend,tier=fast" {
+		t.Errorf("formatLabels = %q, want sorted pairs", got)
+	}
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Show the daemon's log",
+	Long: `Show the daemon's log output, oldest first.
+
+By default the log file in the data directory (<data-dir>/mapd.log) is read
+directly, so this works even when the daemon is not running. Use --remote to
+stream the log from the daemon over its socket instead, e.g. when the daemon
+runs on another host and its log file isn't reachable.
+
+Log levels are inferred from each message: warnings start with "warning",
+and errors mention a failure. With --remote, --level is applied by the
+daemon so filtered lines never cross the connection.
+
+Examples:
+  map logs
+  map logs -f --level warn
+  map logs --remote -n 200`,
+	Args: cobra.NoArgs,
+	RunE: runLogs,
+}
+
+var (
+	logsRemote bool
+	logsFollow bool
+	logsLines  int
+	logsLevel  string
+)
+
+// logsPollInterval is how often a followed log file is checked for new lines
+const logsPollInterval = 500 * time.Millisecond
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+
+	logsCmd.Flags().BoolVar(&logsRemote, "remote", false, "stream the log from the daemon instead of reading the local log file")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "keep printing new lines as they are logged")
+	logsCmd.Flags().IntVarP(&logsLines, "lines", "n", 50, "number of recent lines to show")
+	logsCmd.Flags().StringVar(&logsLevel, "level", daemon.LogLevelInfo, "minimum level to show: info, warn, or error")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	if !daemon.ValidLogLevel(logsLevel) {
+		return fmt.Errorf("invalid --level %q: must be info, warn, or error", logsLevel)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Handle interrupt
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	if logsRemote {
+		return streamRemoteLogs(ctx)
+	}
+	return tailLogFile(ctx, filepath.Join(viper.GetString("data-dir"), daemon.LogFileName))
+}
+
+// streamRemoteLogs prints log lines streamed by the daemon
+func streamRemoteLogs(ctx context.Context) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	stream, err := c.StreamLogs(ctx, logsLevel, int32(logsLines), logsFollow)
+	if err != nil {
+		return fmt.Errorf("stream logs: %w", err)
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				// Context cancelled, normal exit
+				return nil
+			}
+			return fmt.Errorf("receive log: %w", err)
+		}
+		printLogEntry(daemon.LogEntry{Time: entry.Timestamp.AsTime(), Level: entry.Level, Message: entry.Message})
+	}
+}
+
+// tailLogFile prints the last matching lines of the log file and, with
+// --follow, polls it for new lines until ctx is cancelled
+func tailLogFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no log file at %s - the daemon writes it once started (use --remote for a daemon elsewhere)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := bufio.NewReader(f)
+	var recent []daemon.LogEntry
+	var partial string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			// Keep an unfinished line until the rest is written
+			partial = line
+			break
+		}
+		if entry, ok := logFileEntry(line); ok {
+			recent = append(recent, entry)
+			if len(recent) > logsLines {
+				recent = recent[1:]
+			}
+		}
+	}
+	for _, entry := range recent {
+		printLogEntry(entry)
+	}
+
+	if !logsFollow {
+		return nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logsPollInterval):
+		}
+		read := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				// Keep an unfinished line until the rest is written
+				partial += line
+				break
+			}
+			read = true
+			if entry, ok := logFileEntry(partial + line); ok {
+				printLogEntry(entry)
+			}
+			partial = ""
+		}
+
+		// The daemon rotates its log: once the file at path is a new one,
+		// follow that instead
+		if !read && logRotated(f, path) {
+			next, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			_ = f.Close()
+			f, reader, partial = next, bufio.NewReader(next), ""
+		}
+	}
+}
+
+// logRotated reports whether path now names a different file than f
+func logRotated(f *os.File, path string) bool {
+	current, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	latest, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(current, latest)
+}
+
+// logFileEntry parses a line from the log file, reporting whether it passes
+// the --level filter
+func logFileEntry(line string) (daemon.LogEntry, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if strings.TrimSpace(line) == "" {
+		return daemon.LogEntry{}, false
+	}
+	entry := daemon.ParseLogLine(line, time.Time{})
+	return entry, daemon.LogLevelAtLeast(entry.Level, logsLevel)
+}
+
+func printLogEntry(entry daemon.LogEntry) {
+	ts := "                   "
+	if !entry.Time.IsZero() {
+		ts = entry.Time.Local().Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%s %-5s %s\n", ts, strings.ToUpper(entry.Level), entry.Message)
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Stop assigning tasks to agents",
+	Long: `Pause the daemon's scheduler. New and retried tasks stay pending instead
+of being assigned, while tasks agents are already working on carry on. Use
+'map resume' to assign the queued tasks again.
+
+Useful during maintenance or a controlled rollout, without stopping the
+daemon or its agents.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSchedulerPaused(true)
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume assigning tasks to agents",
+	Long:  `Resume the daemon's scheduler after 'map pause', assigning pending tasks to idle agents.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSchedulerPaused(false)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func setSchedulerPaused(paused bool) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := c.SetSchedulerPaused(ctx, paused)
+	if err != nil {
+		return fmt.Errorf("set scheduler state: %w", err)
+	}
+
+	switch {
+	case paused && resp.Changed:
+		fmt.Println("scheduler paused; new tasks will stay pending")
+	case paused:
+		fmt.Println("scheduler is already paused")
+	case resp.Changed:
+		fmt.Println("scheduler resumed")
+	default:
+		fmt.Println("scheduler is not paused")
+	}
+	return nil
+}
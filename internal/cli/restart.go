@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// defaultRestartTimeout bounds how long restart waits for the old daemon to
+// stop. Stopping kills every agent and removes its worktrees, so it can take
+// a while on a busy daemon.
+const defaultRestartTimeout = 30 * time.Second
+
+var restartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Restart the mapd daemon",
+	Long: `Stop the mapd daemon, wait for it to exit, and start a fresh one in the
+background on the same socket and data directory.
+
+Like 'map down', stopping the daemon kills its agents. While restarting,
+commands that would auto-start the daemon wait for the new one instead of
+racing it. If the daemon is not running, it is simply started.`,
+	Args: cobra.NoArgs,
+	RunE: runRestart,
+}
+
+func init() {
+	restartCmd.Flags().BoolP("force", "f", false, "force immediate shutdown")
+	restartCmd.Flags().Duration("timeout", defaultRestartTimeout, "how long to wait for the daemon to stop")
+	restartCmd.Flags().StringVarP(&dataDir, "data-dir", "d", "", "data directory (default: the running daemon's, else ~/.mapd)")
+	rootCmd.AddCommand(restartCmd)
+}
+
+func runRestart(cmd *cobra.Command, args []string) error {
+	force, _ := cmd.Flags().GetBool("force")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	socketPath := getSocketPath()
+
+	// Hold the start lock throughout so an auto-start can't slip a daemon in
+	// between the old one stopping and the new one starting
+	unlock, err := lockDaemonStart(socketPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if client.IsDaemonRunning(socketPath) {
+		runningDir, err := stopDaemon(socketPath, force)
+		if err != nil {
+			return err
+		}
+		if !cmd.Flags().Changed("data-dir") {
+			dataDir = runningDir
+		}
+
+		if err := waitForSocketGone(socketPath, timeout); err != nil {
+			return err
+		}
+		fmt.Println("daemon stopped")
+	} else {
+		fmt.Println("daemon was not running")
+	}
+
+	pid, err := startDaemonProcess()
+	if err != nil {
+		return err
+	}
+	if err := waitForDaemon(socketPath, pid); err != nil {
+		return err
+	}
+
+	fmt.Printf("mapd started (pid %d)\n", pid)
+	return nil
+}
+
+// stopDaemon asks the daemon to shut down and returns the data directory it
+// was using, so the new daemon can be started on the same one
+func stopDaemon(socketPath string, force bool) (string, error) {
+	c, err := client.New(socketPath)
+	if err != nil {
+		return "", fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get status: %w", err)
+	}
+	if err := c.Shutdown(ctx, force); err != nil {
+		return "", fmt.Errorf("shutdown: %w", err)
+	}
+	return status.DataDir, nil
+}
+
+// waitForSocketGone waits for a stopping daemon to remove its socket, which
+// it does last, after releasing its data directory
+func waitForSocketGone(socketPath string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		_, err := os.Stat(socketPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("daemon did not stop within %s; check 'map status' before starting it again", timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWaitForSocketGone(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "mapd.sock")
+	if err := os.WriteFile(socketPath, nil, 0o600); err != nil {
+		t.Fatalf("create socket file: %v", err)
+	}
+
+	if err := waitForSocketGone(socketPath, 150*time.Millisecond); err == nil {
+		t.Fatal("expected timeout while the socket exists")
+	}
+
+	go func() {
+		time.Sleep(150 * time.Millisecond)
+		_ = os.Remove(socketPath)
+	}()
+	if err := waitForSocketGone(socketPath, 5*time.Second); err != nil {
+		t.Fatalf("expected socket removal to be noticed: %v", err)
+	}
+}
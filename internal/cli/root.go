@@ -1,11 +1,16 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Version is set via -ldflags at build time
@@ -22,21 +27,73 @@ var rootCmd = &cobra.Command{
 // Execute runs the CLI
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, errorMessage(err))
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError makes the CLI exit with code instead of 1
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// errorMessage renders err for the terminal. Daemon errors arrive as
+// "rpc error: code = NotFound desc = task not found: x"; the gRPC framing is
+// dropped so only the daemon's message shows, with a hint when the daemon
+// couldn't be reached at all.
+func errorMessage(err error) string {
+	var grpcErr interface {
+		error
+		GRPCStatus() *status.Status
+	}
+	if !errors.As(err, &grpcErr) {
+		return err.Error()
+	}
+	st := grpcErr.GRPCStatus()
+	msg := strings.Replace(err.Error(), grpcErr.Error(), st.Message(), 1)
+	if st.Code() == codes.Unavailable {
+		msg += " (is mapd running? start it with 'map up')"
+	}
+	return msg
+}
+
 // getSocketPath returns the socket path from Viper (flag > env > config > default)
 func getSocketPath() string {
 	return viper.GetString("socket")
 }
 
+// isQuiet reports whether non-essential output should be suppressed
+func isQuiet() bool {
+	return viper.GetBool("quiet")
+}
+
 func init() {
 	rootCmd.PersistentFlags().StringP("socket", "s", "/tmp/mapd.sock", "daemon socket path")
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ~/.mapd/config.yaml)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "suppress non-essential output")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		return initConfig()
+		if err := initConfig(); err != nil {
+			return err
+		}
+		// Normalize the socket path once, so every command and the daemon
+		// started by 'map up' agree on it. The daemon creates its directory.
+		socketPath, err := daemon.ResolveSocketPath(getSocketPath())
+		if err != nil {
+			return err
+		}
+		viper.Set("socket", socketPath)
+		if needsDaemon(cmd) {
+			return ensureDaemon()
+		}
+		return nil
 	}
 }
@@ -2,7 +2,17 @@ package cli
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestVersionDefault(t *testing.T) {
@@ -70,3 +80,307 @@ func TestVersionFlagShort(t *testing.T) {
 	// Reset args for other tests
 	rootCmd.SetArgs([]string{})
 }
+
+func TestQuietFlag(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("quiet")
+	if flag == nil {
+		t.Fatal("rootCmd should have a persistent --quiet flag")
+	}
+	if flag.Shorthand != "q" {
+		t.Errorf("--quiet shorthand = %q, want %q", flag.Shorthand, "q")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("--quiet default = %q, want %q", flag.DefValue, "false")
+	}
+}
+
+func TestProfileKey(t *testing.T) {
+	viper.Set("profiles.backend", map[string]any{"agent-type": "codex", "count": 2})
+	defer viper.Set("profiles.backend", nil)
+
+	tests := []struct {
+		profile string
+		key     string
+		want    string
+	}{
+		{"backend", "agent-type", "profiles.backend.agent-type"},
+		{"backend", "count", "profiles.backend.count"},
+		{"backend", "branch", "agent.default-branch"},
+		{"", "count", "agent.default-count"},
+	}
+
+	fallbacks := map[string]string{
+		"agent-type": "agent.default-type",
+		"count":      "agent.default-count",
+		"branch":     "agent.default-branch",
+	}
+
+	for _, tt := range tests {
+		if got := profileKey(tt.profile, tt.key, fallbacks[tt.key]); got != tt.want {
+			t.Errorf("profileKey(%q, %q) = %q, want %q", tt.profile, tt.key, got, tt.want)
+		}
+	}
+
+	if viper.GetInt(profileKey("backend", "count", "agent.default-count")) != 2 {
+		t.Error("profile count should be read from the profile")
+	}
+}
+
+func TestAgentCreateFromConfigAlias(t *testing.T) {
+	flag := agentCreateCmd.Flags().Lookup("from-config")
+	if flag == nil || flag.Name != "profile" {
+		t.Error("--from-config should resolve to the --profile flag")
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		buf := new(bytes.Buffer)
+		rootCmd.SetOut(buf)
+		rootCmd.SetArgs([]string{"completion", shell})
+
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("completion %s: unexpected error: %v", shell, err)
+		}
+		if !containsString(buf.String(), "map") {
+			t.Errorf("completion %s output should reference the map command", shell)
+		}
+	}
+	rootCmd.SetArgs([]string{})
+}
+
+func TestCompleteAgentIDs_NoDaemon(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("socket")
+	orig := flag.Value.String()
+	_ = flag.Value.Set(t.TempDir() + "/missing.sock")
+	defer func() { _ = flag.Value.Set(orig) }()
+
+	ids, directive := completeAgentIDs(agentKillCmd, nil, "")
+	if len(ids) != 0 {
+		t.Errorf("completeAgentIDs = %v, want none without a daemon", ids)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+}
+
+func TestResolveSkipPermissions(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "create"}
+		cmd.Flags().Bool("require-permissions", false, "")
+		cmd.Flags().Bool("skip-permissions", false, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		return cmd
+	}
+
+	viper.Set("agent.skip-permissions", false)
+	defer viper.Set("agent.skip-permissions", true)
+
+	if resolveSkipPermissions(newCmd(), "") {
+		t.Error("agent.skip-permissions=false should require permissions by default")
+	}
+	if !resolveSkipPermissions(newCmd("--skip-permissions"), "") {
+		t.Error("--skip-permissions should override agent.skip-permissions=false")
+	}
+
+	viper.Set("agent.skip-permissions", true)
+	if !resolveSkipPermissions(newCmd(), "") {
+		t.Error("agent.skip-permissions=true should skip permissions by default")
+	}
+	if resolveSkipPermissions(newCmd("--require-permissions"), "") {
+		t.Error("--require-permissions should override agent.skip-permissions=true")
+	}
+}
+
+func TestParseEventType(t *testing.T) {
+	tests := []struct {
+		input string
+		want  mapv1.EventType
+	}{
+		{"task-completed", mapv1.EventType_EVENT_TYPE_TASK_COMPLETED},
+		{"completed", mapv1.EventType_EVENT_TYPE_TASK_COMPLETED},
+		{"TASK_FAILED", mapv1.EventType_EVENT_TYPE_TASK_FAILED},
+		{"EVENT_TYPE_TASK_CREATED", mapv1.EventType_EVENT_TYPE_TASK_CREATED},
+		{"waiting-input", mapv1.EventType_EVENT_TYPE_TASK_WAITING_INPUT},
+		{"agent", mapv1.EventType_EVENT_TYPE_UNSPECIFIED},
+	}
+
+	for _, tt := range tests {
+		got, err := parseEventType(tt.input)
+		if err != nil {
+			t.Errorf("parseEventType(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseEventType(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "bogus", "unspecified"} {
+		if _, err := parseEventType(bad); err == nil {
+			t.Errorf("parseEventType(%q) expected error", bad)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 0, 0, 0, time.UTC)
+
+	got, err := parseSince("", now)
+	if err != nil || !got.IsZero() {
+		t.Errorf("parseSince(\"\") = %v, %v; want zero time", got, err)
+	}
+
+	got, err = parseSince("90m", now)
+	if err != nil {
+		t.Fatalf("parseSince(90m) error: %v", err)
+	}
+	if want := now.Add(-90 * time.Minute); !got.Equal(want) {
+		t.Errorf("parseSince(90m) = %v, want %v", got, want)
+	}
+
+	got, err = parseSince("2025-01-01T00:00:00Z", now)
+	if err != nil {
+		t.Fatalf("parseSince(RFC3339) error: %v", err)
+	}
+	if want := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("parseSince(RFC3339) = %v, want %v", got, want)
+	}
+
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Error("parseSince(yesterday) expected error")
+	}
+}
+
+func TestIsTerminalTaskStatus(t *testing.T) {
+	terminal := map[mapv1.TaskStatus]bool{
+		mapv1.TaskStatus_TASK_STATUS_COMPLETED: true,
+		mapv1.TaskStatus_TASK_STATUS_FAILED:    true,
+		mapv1.TaskStatus_TASK_STATUS_CANCELLED: true,
+	}
+	for v := range mapv1.TaskStatus_name {
+		status := mapv1.TaskStatus(v)
+		if got := isTerminalTaskStatus(status); got != terminal[status] {
+			t.Errorf("isTerminalTaskStatus(%s) = %v, want %v", status, got, terminal[status])
+		}
+	}
+}
+
+func TestTaskSubmitWaitFlags(t *testing.T) {
+	for _, name := range []string{"wait", "attach", "wait-timeout"} {
+		if taskSubmitCmd.Flags().Lookup(name) == nil {
+			t.Errorf("task submit missing --%s flag", name)
+		}
+	}
+}
+
+func TestNeedsDaemon(t *testing.T) {
+	tests := []struct {
+		cmd  *cobra.Command
+		want bool
+	}{
+		{taskSubmitCmd, true},
+		{agentCreateCmd, true},
+		{eventsCmd, true},
+		{upCmd, false},
+		{statusCmd, false},
+		{configSetCmd, false},
+		{completionCmd, false},
+		{rootCmd, false},
+	}
+
+	for _, tt := range tests {
+		if got := needsDaemon(tt.cmd); got != tt.want {
+			t.Errorf("needsDaemon(%s) = %v, want %v", tt.cmd.CommandPath(), got, tt.want)
+		}
+	}
+}
+
+func TestEnsureDaemon_Disabled(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("socket", t.TempDir()+"/mapd.sock")
+
+	// With auto-start off, a missing daemon is left for the command to report
+	if err := ensureDaemon(); err != nil {
+		t.Errorf("ensureDaemon with auto-start disabled: %v", err)
+	}
+}
+
+func TestNewPaneOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			name:   "output appended after prompt",
+			before: "welcome\n> \n\n",
+			after:  "welcome\n> /status\nmodel: default\ncwd: /repo\n> \n",
+			want:   "> /status\nmodel: default\ncwd: /repo\n>",
+		},
+		{
+			name:   "single line before",
+			before: "> ",
+			after:  "> hello\nhi there\n",
+			want:   "> hello\nhi there",
+		},
+		{
+			name:   "pane cleared",
+			before: "old output\nmore\n> ",
+			after:  "fresh screen\n> ",
+			want:   "fresh screen\n>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newPaneOutput(tt.before, tt.after); got != tt.want {
+				t.Errorf("newPaneOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTaskLogEntry(t *testing.T) {
+	start := time.Date(2025, 1, 2, 15, 0, 0, 0, time.Local)
+	event := &mapv1.Event{
+		Type:      mapv1.EventType_EVENT_TYPE_TASK_WAITING_INPUT,
+		Timestamp: timestamppb.New(start.Add(90 * time.Second)),
+		Payload: &mapv1.Event_Task{
+			Task: &mapv1.TaskEvent{TaskId: "t1", AgentId: "claude-swift-fox"},
+		},
+	}
+
+	want := "2025-01-02 15:01:30  +1m30s    waiting input   claude-swift-fox"
+	if got := formatTaskLogEntry(event, start); got != want {
+		t.Errorf("formatTaskLogEntry() = %q, want %q", got, want)
+	}
+
+	event.Type = mapv1.EventType_EVENT_TYPE_TASK_CREATED
+	event.Timestamp = timestamppb.New(start)
+	event.GetTask().AgentId = ""
+	want = "2025-01-02 15:00:00  +0s       created"
+	if got := formatTaskLogEntry(event, start); got != want {
+		t.Errorf("formatTaskLogEntry() = %q, want %q", got, want)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{errors.New("agent ID required"), "agent ID required"},
+		{fmt.Errorf("cancel task: %w", status.Error(codes.NotFound, "task not found: abc")), "cancel task: task not found: abc"},
+		{fmt.Errorf("list tasks: %w", status.Error(codes.Unavailable, "connection refused")), "list tasks: connection refused (is mapd running? start it with 'map up')"},
+	}
+	for _, tt := range tests {
+		if got := errorMessage(tt.err); got != tt.want {
+			t.Errorf("errorMessage(%q) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:     "run",
+	Aliases: []string{"runs"},
+	Short:   "Track batches of related tasks",
+	Long: `A run groups tasks submitted together so their progress can be followed
+as a unit. Each 'map task sync' starts a new run; pass --run to 'map task
+submit' to add tasks to a run of your own.`,
+}
+
+var runListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List runs and their progress",
+	RunE:    runRunList,
+}
+
+var runShowCmd = &cobra.Command{
+	Use:   "show <run-id>",
+	Short: "Show a run's progress and tasks",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRunShow,
+}
+
+var runLimit int32
+
+func init() {
+	runListCmd.Flags().Int32VarP(&runLimit, "limit", "n", 20, "maximum number of runs to show")
+
+	runCmd.AddCommand(runListCmd)
+	runCmd.AddCommand(runShowCmd)
+	rootCmd.AddCommand(runCmd)
+}
+
+// newRunID returns an ID for a new run
+func newRunID() string {
+	return "run-" + uuid.New().String()[:8]
+}
+
+func runRunList(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Filter by current repo
+	runs, err := c.ListRuns(ctx, runLimit, getRepoRoot())
+	if err != nil {
+		return fmt.Errorf("list runs: %w", err)
+	}
+
+	if len(runs) == 0 {
+		fmt.Println("no runs")
+		return nil
+	}
+
+	fmt.Printf("%-14s %-20s %-20s %s\n", "RUN ID", "CREATED", "UPDATED", "PROGRESS")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, run := range runs {
+		fmt.Printf("%-14s %-20s %-20s %s\n",
+			run.RunId,
+			run.CreatedAt.AsTime().Local().Format("2006-01-02 15:04:05"),
+			run.UpdatedAt.AsTime().Local().Format("2006-01-02 15:04:05"),
+			formatRunProgress(run),
+		)
+	}
+
+	return nil
+}
+
+func runRunShow(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := c.GetRun(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("get run: %w", err)
+	}
+	run := resp.Run
+
+	fmt.Printf("Run ID:   %s\n", run.RunId)
+	fmt.Printf("Progress: %s\n", formatRunProgress(run))
+	fmt.Printf("Created:  %s\n", run.CreatedAt.AsTime().Local().Format(time.RFC3339))
+	fmt.Printf("Updated:  %s\n", run.UpdatedAt.AsTime().Local().Format(time.RFC3339))
+	if run.RepoRoot != "" {
+		fmt.Printf("Repo:     %s\n", run.RepoRoot)
+	}
+
+	fmt.Println()
+	fmt.Printf("%-36s %-15s %-20s %s\n", "TASK ID", "STATUS", "ASSIGNED TO", "DESCRIPTION")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, task := range resp.Tasks {
+		fmt.Printf("%-36s %-15s %-20s %s\n",
+			task.TaskId,
+			taskStatusString(task.Status),
+			truncate(valueOrDash(task.AssignedTo), 20),
+			truncate(strings.Join(strings.Fields(task.Description), " "), 40),
+		)
+	}
+
+	return nil
+}
+
+// formatRunProgress summarizes a run as "7/10 complete", followed by the
+// counts of tasks that are not done, e.g. "(1 failed, 2 in progress)"
+func formatRunProgress(run *mapv1.RunSummary) string {
+	s := fmt.Sprintf("%d/%d complete", run.Completed, run.Total)
+
+	var rest []string
+	for _, part := range []struct {
+		n     int32
+		label string
+	}{
+		{run.Active, "in progress"},
+		{run.Pending, "pending"},
+		{run.Failed, "failed"},
+		{run.Cancelled, "cancelled"},
+	} {
+		if part.n > 0 {
+			rest = append(rest, fmt.Sprintf("%d %s", part.n, part.label))
+		}
+	}
+	if len(rest) > 0 {
+		s += " (" + strings.Join(rest, ", ") + ")"
+	}
+	return s
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestFormatRunProgress(t *testing.T) {
+	tests := []struct {
+		name string
+		run  *mapv1.RunSummary
+		want string
+	}{
+		{"all done", &mapv1.RunSummary{Total: 3, Completed: 3}, "3/3 complete"},
+		{"mixed", &mapv1.RunSummary{Total: 10, Completed: 7, Active: 2, Failed: 1}, "7/10 complete (2 in progress, 1 failed)"},
+		{"not started", &mapv1.RunSummary{Total: 4, Pending: 4}, "0/4 complete (4 pending)"},
+		{"cancelled", &mapv1.RunSummary{Total: 2, Completed: 1, Cancelled: 1}, "1/2 complete (1 cancelled)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatRunProgress(tt.run); got != tt.want {
+				t.Errorf("formatRunProgress = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRunID(t *testing.T) {
+	a, b := newRunID(), newRunID()
+	if !strings.HasPrefix(a, "run-") || len(a) != len("run-")+8 {
+		t.Errorf("newRunID = %q, want run- followed by 8 characters", a)
+	}
+	if a == b {
+		t.Errorf("newRunID returned %q twice", a)
+	}
+}
+
+func TestRunList_ListsSubmittedRun(t *testing.T) {
+	startTestDaemon(t)
+	chdirTestRepo(t)
+
+	taskRun = "batch-1"
+	t.Cleanup(func() { taskRun = "" })
+	captureStdout(t, func() {
+		if err := runTaskSubmit(taskSubmitCmd, []string{"fix", "the", "build"}); err != nil {
+			t.Errorf("runTaskSubmit failed: %v", err)
+		}
+	})
+
+	out := captureStdout(t, func() {
+		if err := runRunList(runListCmd, nil); err != nil {
+			t.Errorf("runRunList failed: %v", err)
+		}
+	})
+	if !strings.Contains(out, "batch-1") {
+		t.Errorf("map run ls output = %q, want the run submitted from this repo", out)
+	}
+}
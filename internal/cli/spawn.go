@@ -8,25 +8,66 @@ import (
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 var agentCmd = &cobra.Command{
 	Use:   "agent",
-	Short: "Manage spawned agents (Claude or Codex)",
-	Long:  `Commands for spawning, listing, and killing Claude Code or OpenAI Codex agents.`,
+	Short: "Manage spawned agents (Claude, Codex, or Gemini)",
+	Long:  `Commands for spawning, listing, and killing Claude Code, OpenAI Codex, or Gemini CLI agents.`,
 }
 
 var agentCreateCmd = &cobra.Command{
 	Use:   "create",
-	Short: "Spawn agents (Claude or Codex)",
+	Short: "Spawn agents (Claude, Codex, or Gemini)",
 	Long: `Spawn one or more agents as subprocesses.
 
-Use -a claude (default) for Claude Code agents or -a codex for OpenAI Codex agents.
+Use -a claude (default) for Claude Code agents, -a codex for OpenAI Codex
+agents, or -a gemini for Gemini CLI agents.
 Each agent can optionally be isolated in its own git worktree for safe
-concurrent work in the same repository.`,
+concurrent work in the same repository.
+
+Use --profile to load a named spawn profile from the config file as
+defaults. Explicitly passed flags override the profile:
+
+  profiles:
+    backend:
+      agent-type: codex
+      count: 2
+      worktree: true
+
+Use --prompt-from-issue owner/repo#n to spawn an agent for a GitHub issue.
+The issue is fetched with gh, and a task tracking it is created for the new
+agent, so questions the agent asks are posted to the issue and answers there
+are delivered back. It spawns a single agent and replaces --prompt. The
+agent is held for the issue task, so it takes no older pending task first,
+and it is removed if the task can't be created.
+
+Use --claude-continue to start claude with --continue, picking up the most
+recent conversation in the agent's working directory. Claude keys
+conversations by directory, so this is mainly useful with --no-worktree (a
+fresh worktree has no history). The agent keeps resuming on respawn. It is
+claude-specific: codex and gemini agents ignore it.
+
+Use --copy-env NAME (repeatable, or comma-separated) to copy variables from
+your shell into the agents' sessions, e.g. tokens a build needs. Only the
+named variables are copied; the daemon's own environment is unchanged. They
+survive respawns and are passed into containers by name.
+
+Use --offer-tasks to let the agents choose their work: tasks routed to them
+are offered rather than assigned, and each agent answers with 'map task
+accept <id>' or 'map task reject <id>' from its session. A rejected task
+goes to another agent. Headless agents can't be offered tasks.
+
+Use --on-complete to decide what happens to an agent once a task it was
+given completes, fails, or is cancelled: idle (default) returns it to the
+pool, keep leaves it running for inspection without giving it more tasks,
+and kill removes it and its worktree. With keep or kill each agent takes a
+single task.`,
 	RunE: runAgentCreate,
 }
 
@@ -34,19 +75,31 @@ var agentListCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List spawned agents",
-	Long:    `List all spawned Claude Code agents and their status.`,
-	RunE:    runAgentList,
+	Long: `List all spawned Claude Code agents and their status.
+
+Use --label key=value (repeatable) to only list agents carrying those labels.
+
+Use --stale to only list agents the daemon still tracks whose tmux session no
+longer exists, e.g. after a session was killed outside map. Add --prune to
+remove them, putting their unfinished tasks back in the queue.`,
+	RunE: runAgentList,
 }
 
 var agentKillCmd = &cobra.Command{
 	Use:   "kill [agent-id]",
 	Short: "Terminate a spawned agent",
-	Long: `Terminate a spawned agent by its ID, or kill all agents with --all.
+	Long: `Terminate a spawned agent by its ID, kill all agents with --all, or kill
+every agent carrying a set of labels with --label.
+
+Tasks the agent is still working on are cancelled. Use --requeue to put them
+back in the queue for another agent instead.
 
 Examples:
-  map agent kill claude-abc123     # Kill a specific agent
-  map agent kill -a                # Kill all agents
-  map agent kill --all --force     # Force kill all agents`,
+  map agent kill claude-abc123       # Kill a specific agent
+  map agent kill -a                  # Kill all agents
+  map agent kill --all --force       # Force kill all agents
+  map agent kill claude-abc123 --requeue
+  map agent kill --label team=backend`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runAgentKill,
 }
@@ -58,7 +111,11 @@ var agentRespawnCmd = &cobra.Command{
 
 When you press Ctrl+C in an agent session, the claude process exits but
 the tmux pane is preserved. Use this command to restart claude in that
-agent and continue where you left off.`,
+agent and continue where you left off.
+
+Use --force to restart an agent whose CLI is still running but stuck on a
+fatal error (e.g. an auth failure), and --require-permissions to restart it
+with permission prompts.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAgentRespawn,
 }
@@ -70,6 +127,10 @@ func init() {
 	agentCmd.AddCommand(agentKillCmd)
 	agentCmd.AddCommand(agentRespawnCmd)
 
+	// agent respawn flags
+	agentRespawnCmd.Flags().Bool("force", false, "Restart the agent even if its pane is still running")
+	agentRespawnCmd.Flags().Bool("require-permissions", false, "Restart with permission prompts and keep that mode")
+
 	// agent create flags
 	agentCreateCmd.Flags().IntP("count", "n", 1, "Number of agents to spawn")
 	agentCreateCmd.Flags().String("branch", "", "Git branch for worktrees (default: current branch)")
@@ -77,12 +138,50 @@ func init() {
 	agentCreateCmd.Flags().Bool("no-worktree", false, "Skip worktree isolation (all agents share cwd)")
 	agentCreateCmd.Flags().String("name", "", "Agent name prefix (default: agent type)")
 	agentCreateCmd.Flags().StringP("prompt", "p", "", "Initial prompt to send to the agent")
-	agentCreateCmd.Flags().StringP("agent-type", "a", "claude", "Agent type: claude (default) or codex")
-	agentCreateCmd.Flags().Bool("require-permissions", false, "Require permission prompts (default: permissions are skipped for autonomous operation)")
+	agentCreateCmd.Flags().StringP("agent-type", "a", "claude", "Agent type: "+daemon.AgentTypeChoices())
+	agentCreateCmd.Flags().Bool("require-permissions", false, "Require permission prompts (default: agent.skip-permissions from config, true unless changed)")
+	agentCreateCmd.Flags().Bool("skip-permissions", false, "Skip permission prompts even if agent.skip-permissions is false in config")
+	agentCreateCmd.MarkFlagsMutuallyExclusive("require-permissions", "skip-permissions")
+	agentCreateCmd.Flags().Bool("claude-continue", false, "Start claude with --continue to resume the directory's previous conversation (ignored for "+strings.Join(agentTypesWithoutContinue(), ", ")+")")
+	agentCreateCmd.Flags().Bool("output-only", false, "Run headless without tmux: each task runs non-interactively and its output is stored as the task result")
+	agentCreateCmd.Flags().Bool("offer-tasks", false, "Offer tasks to the agents to accept or reject instead of assigning them")
+	agentCreateCmd.MarkFlagsMutuallyExclusive("output-only", "offer-tasks")
+	agentCreateCmd.Flags().String("on-complete", "idle", "What to do with each agent once its task finishes: idle, keep, or kill")
+	agentCreateCmd.Flags().Bool("git-config", false, "Set user.name/user.email in each agent worktree (agent.git-user-name/agent.git-user-email)")
+	agentCreateCmd.Flags().Duration("spawn-delay", 500*time.Millisecond, "Delay between starting each agent when spawning several (default: agent.spawn-delay)")
+	agentCreateCmd.Flags().StringArray("label", nil, "Label the agents with key=value (repeatable)")
+	agentCreateCmd.Flags().StringSlice("copy-env", nil, "Copy these environment variables from this shell into the agents' sessions (repeatable)")
+	agentCreateCmd.Flags().String("prompt-from-issue", "", "Work a GitHub issue (owner/repo#n): fetch it and create a task tracking it for the agent")
+	agentCreateCmd.MarkFlagsMutuallyExclusive("prompt", "prompt-from-issue")
+	agentCreateCmd.Flags().String("profile", "", "Named spawn profile from config (profiles.<name>) to use as defaults")
+	agentCreateCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		// --from-config is accepted as an alias for --profile
+		if name == "from-config" {
+			name = "profile"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	// agent kill flags
 	agentKillCmd.Flags().BoolP("force", "f", false, "Force kill (SIGKILL instead of SIGTERM)")
 	agentKillCmd.Flags().BoolP("all", "a", false, "Kill all running agents")
+	agentKillCmd.Flags().Bool("requeue", false, "Put the agent's active tasks back in the queue instead of cancelling them")
+	agentKillCmd.Flags().StringArray("label", nil, "Kill all agents with this key=value label (repeatable)")
+	agentListCmd.Flags().StringArray("label", nil, "Only list agents with this key=value label (repeatable)")
+	agentListCmd.Flags().Bool("stale", false, "Only list agents whose tmux session no longer exists")
+	agentListCmd.Flags().Bool("prune", false, "Remove stale agents (requires --stale)")
+}
+
+// agentTypesWithoutContinue lists the agent types that have no way to resume
+// a conversation, so --claude-continue doesn't apply to them
+func agentTypesWithoutContinue() []string {
+	var names []string
+	for _, name := range daemon.AgentTypes() {
+		if daemon.LookupAgentType(name).ContinueFlag == "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 func runAgentCreate(cmd *cobra.Command, args []string) error {
@@ -92,52 +191,130 @@ func runAgentCreate(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = c.Close() }()
 
-	// Get flag values, using Viper defaults when flags aren't explicitly set
+	profile, _ := cmd.Flags().GetString("profile")
+	if profile != "" && !viper.IsSet(profilePrefix+profile) {
+		return fmt.Errorf("profile %q not found in config", profile)
+	}
+
+	// Get flag values, falling back to the profile and then Viper defaults
+	// when flags aren't explicitly set
 	count, _ := cmd.Flags().GetInt("count")
 	if !cmd.Flags().Changed("count") {
-		count = viper.GetInt("agent.default-count")
+		count = viper.GetInt(profileKey(profile, "count", "agent.default-count"))
 	}
 
 	branch, _ := cmd.Flags().GetString("branch")
 	if !cmd.Flags().Changed("branch") {
-		branch = viper.GetString("agent.default-branch")
+		branch = viper.GetString(profileKey(profile, "branch", "agent.default-branch"))
 	}
 
 	noWorktree, _ := cmd.Flags().GetBool("no-worktree")
 	worktree, _ := cmd.Flags().GetBool("worktree")
 	if !cmd.Flags().Changed("worktree") && !cmd.Flags().Changed("no-worktree") {
-		worktree = viper.GetBool("agent.use-worktree")
+		worktree = viper.GetBool(profileKey(profile, "worktree", "agent.use-worktree"))
 	}
 
 	name, _ := cmd.Flags().GetString("name")
+	if !cmd.Flags().Changed("name") {
+		name = viper.GetString(profileKey(profile, "name", ""))
+	}
 	prompt, _ := cmd.Flags().GetString("prompt")
+	if !cmd.Flags().Changed("prompt") {
+		prompt = viper.GetString(profileKey(profile, "prompt", ""))
+	}
 
 	agentType, _ := cmd.Flags().GetString("agent-type")
 	if !cmd.Flags().Changed("agent-type") {
-		agentType = viper.GetString("agent.default-type")
+		agentType = viper.GetString(profileKey(profile, "agent-type", "agent.default-type"))
 	}
 
-	requirePermissions, _ := cmd.Flags().GetBool("require-permissions")
-	skipPermissions := !requirePermissions
-	if !cmd.Flags().Changed("require-permissions") {
-		skipPermissions = viper.GetBool("agent.skip-permissions")
+	skipPermissions := resolveSkipPermissions(cmd, profile)
+
+	spawnDelay, _ := cmd.Flags().GetDuration("spawn-delay")
+	if !cmd.Flags().Changed("spawn-delay") {
+		spawnDelay = viper.GetDuration(profileKey(profile, "spawn-delay", "agent.spawn-delay"))
+	}
+	if spawnDelay < 0 {
+		return fmt.Errorf("--spawn-delay must not be negative")
 	}
 
 	// Validate agent type
-	if agentType != "claude" && agentType != "codex" {
-		return fmt.Errorf("invalid agent type %q: must be 'claude' or 'codex'", agentType)
+	if !daemon.ValidAgentType(agentType) {
+		return fmt.Errorf("invalid agent type %q: must be %s", agentType, daemon.AgentTypeChoices())
 	}
 
 	// no-worktree overrides worktree
 	useWorktree := worktree && !noWorktree
 
+	gitConfig, _ := cmd.Flags().GetBool("git-config")
+	if !cmd.Flags().Changed("git-config") {
+		gitConfig = viper.GetBool(profileKey(profile, "git-config", "agent.git-config"))
+	}
+	if gitConfig && !useWorktree {
+		return fmt.Errorf("--git-config requires worktree isolation")
+	}
+
+	outputOnly, _ := cmd.Flags().GetBool("output-only")
+	offerTasks, _ := cmd.Flags().GetBool("offer-tasks")
+	onComplete, _ := cmd.Flags().GetString("on-complete")
+	switch onComplete {
+	case "idle", "keep", "kill":
+	default:
+		return fmt.Errorf("invalid --on-complete %q: must be idle, keep, or kill", onComplete)
+	}
+	claudeContinue, _ := cmd.Flags().GetBool("claude-continue")
+	if claudeContinue {
+		if outputOnly {
+			return fmt.Errorf("--claude-continue resumes an interactive session and can't be used with --output-only")
+		}
+		if daemon.LookupAgentType(agentType).ContinueFlag == "" {
+			fmt.Fprintf(os.Stderr, "warning: --claude-continue is ignored for %s agents\n", agentType)
+		} else if useWorktree {
+			fmt.Fprintln(os.Stderr, "warning: --claude-continue has no conversation to resume in a fresh worktree until the agent is respawned; use --no-worktree to resume this checkout's conversation")
+		}
+	}
+
+	labels, err := labelsFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	copyNames, _ := cmd.Flags().GetStringSlice("copy-env")
+	env, missing, err := copyEnv(copyNames, os.LookupEnv)
+	if err != nil {
+		return err
+	}
+	for _, name := range missing {
+		fmt.Fprintf(os.Stderr, "warning: %s is not set; not copying it\n", name)
+	}
+
+	// An issue becomes the agent's first task rather than its initial prompt,
+	// so the task tracks the issue
+	var ref issueRef
+	var issue ghItem
+	issueFlag, _ := cmd.Flags().GetString("prompt-from-issue")
+	if issueFlag != "" {
+		if count != 1 {
+			return fmt.Errorf("--prompt-from-issue spawns a single agent; got --count %d", count)
+		}
+		if ref, err = parseIssueRef(issueFlag); err != nil {
+			return err
+		}
+		if issue, err = fetchIssue(ref); err != nil {
+			return err
+		}
+		prompt = ""
+	}
+
 	// Get current working directory to pass to daemon
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("get working directory: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	// Allow for the stagger on top of the time to start the agents
+	timeout := 60*time.Second + time.Duration(max(count-1, 0))*spawnDelay
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	req := &mapv1.SpawnAgentRequest{
@@ -149,6 +326,20 @@ func runAgentCreate(cmd *cobra.Command, args []string) error {
 		AgentType:        agentType,
 		SkipPermissions:  skipPermissions,
 		WorkingDirectory: cwd,
+		Labels:           labels,
+		SpawnDelayMs:     spawnDelay.Milliseconds(),
+		Env:              env,
+	}
+	req.OutputOnly = outputOnly
+	req.ClaudeContinue = claudeContinue
+	req.OfferTasks = offerTasks
+	req.OnComplete = onComplete
+	// Keep the agent from taking an older pending task before its issue
+	// task is submitted
+	req.HoldForTask = issueFlag != ""
+	if gitConfig {
+		req.GitUserName = viper.GetString("agent.git-user-name")
+		req.GitUserEmail = viper.GetString("agent.git-user-email")
 	}
 
 	resp, err := c.SpawnAgent(ctx, req)
@@ -157,15 +348,33 @@ func runAgentCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(resp.Agents) == 0 {
-		fmt.Println("no agents spawned")
+		if !isQuiet() {
+			fmt.Println("no agents spawned")
+		}
 		return nil
 	}
 
-	fmt.Printf("spawned %d agent(s):\n\n", len(resp.Agents))
+	if isQuiet() {
+		for _, agent := range resp.Agents {
+			fmt.Println(agent.AgentId)
+		}
+	} else {
+		printSpawnedAgents(resp.Agents)
+	}
+
+	if issueFlag != "" {
+		return submitIssueTask(c, ref, issue, resp.Agents[0].AgentId)
+	}
+	return nil
+}
+
+// printSpawnedAgents prints the table of newly spawned agents
+func printSpawnedAgents(agents []*mapv1.SpawnedAgentInfo) {
+	fmt.Printf("spawned %d agent(s):\n\n", len(agents))
 	fmt.Printf("%-25s %-8s %s\n", "AGENT ID", "TYPE", "WORKTREE")
 	fmt.Println(strings.Repeat("-", 75))
 
-	for _, agent := range resp.Agents {
+	for _, agent := range agents {
 		worktreePath := agent.WorktreePath
 		if worktreePath == "" {
 			worktreePath = "(none)"
@@ -180,11 +389,49 @@ func runAgentCreate(cmd *cobra.Command, args []string) error {
 			worktreePath,
 		)
 	}
+}
 
-	return nil
+// resolveSkipPermissions decides whether agents bypass permission prompts.
+// Precedence: --require-permissions / --skip-permissions flags, then the
+// profile's skip-permissions, then agent.skip-permissions (default true).
+func resolveSkipPermissions(cmd *cobra.Command, profile string) bool {
+	if cmd.Flags().Changed("require-permissions") {
+		require, _ := cmd.Flags().GetBool("require-permissions")
+		return !require
+	}
+	if cmd.Flags().Changed("skip-permissions") {
+		skip, _ := cmd.Flags().GetBool("skip-permissions")
+		return skip
+	}
+	return viper.GetBool(profileKey(profile, "skip-permissions", "agent.skip-permissions"))
+}
+
+// profilePrefix is the config key prefix under which spawn profiles live
+const profilePrefix = "profiles."
+
+// profileKey returns the config key to read a setting from: the profile's own
+// key when the profile sets it, otherwise the fallback key
+func profileKey(profile, key, fallback string) string {
+	if profile != "" {
+		k := profilePrefix + profile + "." + key
+		if viper.IsSet(k) {
+			return k
+		}
+	}
+	return fallback
 }
 
 func runAgentList(cmd *cobra.Command, args []string) error {
+	labels, err := labelsFlag(cmd)
+	if err != nil {
+		return err
+	}
+	stale, _ := cmd.Flags().GetBool("stale")
+	prune, _ := cmd.Flags().GetBool("prune")
+	if prune && !stale {
+		return fmt.Errorf("--prune requires --stale")
+	}
+
 	c, err := client.New(getSocketPath())
 	if err != nil {
 		return fmt.Errorf("connect to daemon: %w", err)
@@ -196,33 +443,106 @@ func runAgentList(cmd *cobra.Command, args []string) error {
 
 	// Filter by current repo
 	repoRoot := getRepoRoot()
-	agents, err := c.ListSpawnedAgents(ctx, repoRoot)
+	if stale {
+		return listStaleAgents(ctx, c, repoRoot, labels, prune)
+	}
+	agents, err := c.ListSpawnedAgentsWithLabels(ctx, repoRoot, labels)
 	if err != nil {
 		return fmt.Errorf("list agents: %w", err)
 	}
 
 	if len(agents) == 0 {
+		if len(labels) > 0 {
+			fmt.Println("no agents match the given labels")
+			return nil
+		}
 		fmt.Println("no agents spawned")
 		return nil
 	}
 
-	fmt.Printf("%-25s %-8s %s\n", "AGENT ID", "TYPE", "WORKTREE")
-	fmt.Println(strings.Repeat("-", 75))
+	fmt.Printf("%-25s %-8s %-40s %s\n", "AGENT ID", "TYPE", "WORKTREE", "LABELS")
+	fmt.Println(strings.Repeat("-", 100))
 
+	headless := 0
 	for _, agent := range agents {
-		fmt.Printf("%-25s %-8s %s\n",
+		agentType := agent.AgentType
+		if agent.Headless {
+			agentType += "*"
+			headless++
+		}
+		fmt.Printf("%-25s %-8s %-40s %s\n",
 			truncate(agent.AgentId, 25),
-			agent.AgentType,
+			agentType,
 			truncate(agent.WorktreePath, 40),
+			formatLabels(agent.Labels),
 		)
 	}
+	if headless > 0 {
+		fmt.Println("\n* headless (output-only): tasks run non-interactively and store their output as the result")
+	}
 
 	return nil
 }
 
+// listStaleAgents lists agents whose tmux session is gone and, with prune,
+// removes them. Their unfinished tasks are requeued, since the agents
+// stopped without anyone deciding the work should stop.
+func listStaleAgents(ctx context.Context, c *client.Client, repoRoot string, labels map[string]string, prune bool) error {
+	agents, err := c.ListStaleAgents(ctx, repoRoot, labels)
+	if err != nil {
+		return fmt.Errorf("list agents: %w", err)
+	}
+
+	if len(agents) == 0 {
+		fmt.Println("no stale agents")
+		return nil
+	}
+
+	if !prune {
+		fmt.Printf("%-25s %-8s %-30s %s\n", "AGENT ID", "TYPE", "SESSION", "WORKTREE")
+		fmt.Println(strings.Repeat("-", 100))
+		for _, agent := range agents {
+			fmt.Printf("%-25s %-8s %-30s %s\n",
+				truncate(agent.AgentId, 25),
+				agent.AgentType,
+				truncate(agent.LogFile, 30),
+				truncate(agent.WorktreePath, 40),
+			)
+		}
+		fmt.Printf("\n%d stale agent(s); run with --prune to remove them\n", len(agents))
+		return nil
+	}
+
+	var failed int
+	for _, agent := range agents {
+		resp, err := c.KillAgent(ctx, agent.GetAgentId(), true, true)
+		if err != nil {
+			fmt.Printf("  failed to prune %s: %v\n", agent.GetAgentId(), err)
+			failed++
+			continue
+		}
+		if !resp.Success {
+			fmt.Printf("  failed to prune %s: %s\n", agent.GetAgentId(), resp.Message)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s\n", resp.Message)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to prune %d stale agent(s)", failed)
+	}
+	fmt.Printf("pruned %d stale agent(s)\n", len(agents))
+	return nil
+}
+
 func runAgentKill(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
 	killAll, _ := cmd.Flags().GetBool("all")
+	requeue, _ := cmd.Flags().GetBool("requeue")
+	labels, err := labelsFlag(cmd)
+	if err != nil {
+		return err
+	}
 
 	c, err := client.New(getSocketPath())
 	if err != nil {
@@ -233,11 +553,11 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Handle --all flag
-	if killAll {
-		// Kill all agents in current repo
+	// Handle --all and --label
+	if killAll || len(labels) > 0 {
+		// Kill all agents in current repo that carry the labels
 		repoRoot := getRepoRoot()
-		agents, err := c.ListSpawnedAgents(ctx, repoRoot)
+		agents, err := c.ListSpawnedAgentsWithLabels(ctx, repoRoot, labels)
 		if err != nil {
 			return fmt.Errorf("list agents: %w", err)
 		}
@@ -250,14 +570,14 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 		fmt.Printf("Killing %d agent(s)...\n", len(agents))
 		var failed int
 		for _, agent := range agents {
-			resp, err := c.KillAgent(ctx, agent.GetAgentId(), force)
+			resp, err := c.KillAgent(ctx, agent.GetAgentId(), force, requeue)
 			if err != nil {
 				fmt.Printf("  failed to kill %s: %v\n", agent.GetAgentId(), err)
 				failed++
 				continue
 			}
 			if resp.Success {
-				fmt.Printf("  killed %s\n", agent.GetAgentId())
+				fmt.Printf("  %s\n", resp.Message)
 			} else {
 				fmt.Printf("  failed to kill %s: %s\n", agent.GetAgentId(), resp.Message)
 				failed++
@@ -267,13 +587,17 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 		if failed > 0 {
 			return fmt.Errorf("failed to kill %d agent(s)", failed)
 		}
-		fmt.Println("All agents killed")
+		if len(labels) > 0 {
+			fmt.Println("All matching agents killed")
+		} else {
+			fmt.Println("All agents killed")
+		}
 		return nil
 	}
 
 	// Single agent kill requires an argument
 	if len(args) == 0 {
-		return fmt.Errorf("agent ID required (or use --all to kill all agents)")
+		return fmt.Errorf("agent ID required (or use --all to kill all agents, or --label to kill matching agents)")
 	}
 
 	agentID := args[0]
@@ -284,13 +608,13 @@ func runAgentKill(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	resp, err := c.KillAgent(ctx, resolvedID, force)
+	resp, err := c.KillAgent(ctx, resolvedID, force, requeue)
 	if err != nil {
 		return fmt.Errorf("kill agent: %w", err)
 	}
 
 	if resp.Success {
-		fmt.Printf("agent %s killed\n", resolvedID)
+		fmt.Println(resp.Message)
 	} else {
 		fmt.Printf("failed to kill agent: %s\n", resp.Message)
 	}
@@ -316,7 +640,9 @@ func runAgentRespawn(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	resp, err := c.RespawnAgent(ctx, resolvedID)
+	force, _ := cmd.Flags().GetBool("force")
+	requirePermissions, _ := cmd.Flags().GetBool("require-permissions")
+	resp, err := c.RespawnAgent(ctx, resolvedID, force, requirePermissions)
 	if err != nil {
 		return fmt.Errorf("respawn agent: %w", err)
 	}
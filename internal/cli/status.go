@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show daemon status",
+	Long: `Show whether the mapd daemon is running and responsive, along with
+agent and task counts.
+
+The daemon is probed with a Ping RPC, so a daemon whose socket is open but
+whose server is wedged is reported as unresponsive. Exits non-zero when the
+daemon is not running or not responding.
+
+With --json, a single JSON object is printed instead of the table, including
+when the daemon is down, so health checks can consume it directly.`,
+	RunE: runStatus,
+}
+
+var statusJSON bool
+
+// statusReport is the stable JSON shape printed by map status --json
+type statusReport struct {
+	Running       bool    `json:"running"`
+	Responding    bool    `json:"responding"`
+	Socket        string  `json:"socket"`
+	Version       string  `json:"version,omitempty"`
+	Multiplexer   string  `json:"multiplexer,omitempty"`
+	StartedAt     string  `json:"started_at,omitempty"`
+	UptimeSeconds int64   `json:"uptime_seconds"`
+	PingMillis    float64 `json:"ping_ms"`
+	Agents        int32   `json:"agents"`
+	IdleAgents    int32   `json:"idle_agents"`
+	BusyAgents    int32   `json:"busy_agents"`
+	PendingTasks  int32   `json:"pending_tasks"`
+	ActiveTasks   int32   `json:"active_tasks"`
+	Paused        bool    `json:"scheduler_paused"`
+	// Events dropped since the daemon started: on a full event channel,
+	// and to watchers that fell behind
+	DroppedEvents        int64 `json:"dropped_events"`
+	DroppedWatcherEvents int64 `json:"dropped_watcher_events"`
+}
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "print status as a JSON object")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	socketPath := getSocketPath()
+	report := statusReport{Socket: socketPath}
+
+	if !client.IsDaemonRunning(socketPath) {
+		if statusJSON {
+			_ = printStatusJSON(report)
+		} else {
+			fmt.Println("daemon is not running")
+		}
+		return errors.New("daemon not running")
+	}
+	report.Running = true
+
+	c, err := client.New(socketPath)
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	pingCtx, pingCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer pingCancel()
+
+	start := time.Now()
+	if _, err := c.Ping(pingCtx); err != nil {
+		if statusJSON {
+			_ = printStatusJSON(report)
+		} else {
+			fmt.Println("daemon is listening but not responding")
+		}
+		return fmt.Errorf("ping: %w", err)
+	}
+	latency := time.Since(start)
+	report.Responding = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status, err := c.GetStatus(ctx)
+	if err != nil {
+		return fmt.Errorf("get status: %w", err)
+	}
+
+	startedAt := status.StartedAt.AsTime()
+	if statusJSON {
+		report.Version = status.Version
+		report.Multiplexer = status.Multiplexer
+		report.StartedAt = startedAt.UTC().Format(time.RFC3339)
+		report.UptimeSeconds = int64(time.Since(startedAt).Seconds())
+		report.PingMillis = float64(latency.Microseconds()) / 1000
+		report.Agents = status.ConnectedAgents
+		report.IdleAgents = status.IdleAgents
+		report.BusyAgents = status.BusyAgents
+		report.PendingTasks = status.PendingTasks
+		report.ActiveTasks = status.ActiveTasks
+		report.Paused = status.SchedulerPaused
+		report.DroppedEvents = status.DroppedEvents
+		report.DroppedWatcherEvents = status.DroppedWatcherEvents
+		return printStatusJSON(report)
+	}
+
+	fmt.Printf("Daemon:        running (ping %s)\n", latency.Round(time.Microsecond))
+	fmt.Printf("Version:       %s\n", status.Version)
+	fmt.Printf("Socket:        %s\n", socketPath)
+	fmt.Printf("Multiplexer:   %s\n", status.Multiplexer)
+	fmt.Printf("Started:       %s (up %s)\n", startedAt.Local().Format(time.RFC3339), time.Since(startedAt).Round(time.Second))
+	fmt.Printf("Agents:        %d (%d idle, %d busy)\n", status.ConnectedAgents, status.IdleAgents, status.BusyAgents)
+	fmt.Printf("Pending Tasks: %d\n", status.PendingTasks)
+	fmt.Printf("Active Tasks:  %d\n", status.ActiveTasks)
+	if status.SchedulerPaused {
+		fmt.Println("Scheduler:     paused (run 'map resume' to assign pending tasks)")
+	}
+	if status.DroppedEvents > 0 || status.DroppedWatcherEvents > 0 {
+		fmt.Printf("Dropped Events: %d (%d to slow watchers); consider raising event-buffer\n", status.DroppedEvents, status.DroppedWatcherEvents)
+	}
+
+	return nil
+}
+
+func printStatusJSON(report statusReport) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
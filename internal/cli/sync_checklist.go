@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// checklistItemRe matches a markdown task-list item, e.g. "- [ ] Add tests"
+// or "  * [x] Done", capturing the checkbox state and the item text
+var checklistItemRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.+)$`)
+
+// parseChecklist returns the text of each unchecked task-list item in a
+// markdown body, in order. Items inside fenced code blocks are ignored.
+func parseChecklist(body string) []string {
+	var items []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		m := checklistItemRe.FindStringSubmatch(strings.TrimRight(line, " \t\r"))
+		if m == nil || m[1] != " " {
+			continue
+		}
+		items = append(items, strings.TrimSpace(m[2]))
+	}
+	return items
+}
+
+// buildChecklistTaskDescription builds the description for one checklist
+// item of an issue synced with --split-checklists. The whole issue is
+// included for context, but the agent is asked to do only its item.
+func buildChecklistTaskDescription(item ghItem, step string, index, total int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("GitHub Issue #%d: %s (checklist item %d of %d)\n\n", item.Content.Number, item.Content.Title, index, total))
+	sb.WriteString(fmt.Sprintf("Your task is this item from the issue's checklist:\n\n- [ ] %s\n\n", step))
+	sb.WriteString("The other items are handled as separate tasks, so work on this one only. The full issue follows for context.\n\n")
+
+	if item.Content.Body != "" {
+		sb.WriteString(item.Content.Body)
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("Source: %s\n\n", item.Content.URL))
+	sb.WriteString("When you're done with your work and you're confident in your solution, open a PR with the GH CLI. ")
+	sb.WriteString(fmt.Sprintf("Reference the issue (#%d) and quote the checklist item in the PR description so it can be ticked off.", item.Content.Number))
+
+	return sb.String()
+}
+
+// syncTaskDescriptions returns the descriptions of the tasks to create for
+// an item: one per unchecked checklist item when split is set and the body
+// has any, otherwise one for the whole issue
+func syncTaskDescriptions(item ghItem, split bool) []string {
+	if split {
+		if steps := parseChecklist(item.Content.Body); len(steps) > 0 {
+			descriptions := make([]string, len(steps))
+			for i, step := range steps {
+				descriptions[i] = buildChecklistTaskDescription(item, step, i+1, len(steps))
+			}
+			return descriptions
+		}
+	}
+	return []string{buildTaskDescription(item)}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChecklist(t *testing.T) {
+	body := strings.Join([]string{
+		"Some context.",
+		"",
+		"- [ ] Add the endpoint",
+		"- [x] Write the design doc",
+		"* [ ]   Update the client  ",
+		"  - [ ] Nested step",
+		"1. [ ] Numbered step",
+		"- [X] Also done",
+		"- [] not a checkbox",
+		"```",
+		"- [ ] example in a code block",
+		"```",
+		"+ [ ] Last step",
+	}, "\n")
+
+	want := []string{"Add the endpoint", "Update the client", "Nested step", "Numbered step", "Last step"}
+	if got := parseChecklist(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseChecklist = %q, want %q", got, want)
+	}
+
+	if got := parseChecklist("No checklist here\n- a plain list item"); len(got) != 0 {
+		t.Errorf("expected no items, got %q", got)
+	}
+}
+
+func TestSyncTaskDescriptions(t *testing.T) {
+	item := ghItem{Content: ghItemContent{
+		Number: 42,
+		Title:  "Build the thing",
+		Body:   "- [ ] First\n- [x] Done\n- [ ] Second",
+		URL:    "https://github.com/o/r/issues/42",
+	}}
+
+	whole := syncTaskDescriptions(item, false)
+	if len(whole) != 1 || whole[0] != buildTaskDescription(item) {
+		t.Errorf("without splitting, expected the whole-issue description, got %q", whole)
+	}
+
+	split := syncTaskDescriptions(item, true)
+	if len(split) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(split))
+	}
+	if !strings.Contains(split[0], "(checklist item 1 of 2)") || !strings.Contains(split[0], "- [ ] First") {
+		t.Errorf("unexpected first description:\n%s", split[0])
+	}
+	if !strings.Contains(split[1], "(checklist item 2 of 2)") || !strings.Contains(split[1], "- [ ] Second") {
+		t.Errorf("unexpected second description:\n%s", split[1])
+	}
+	if !strings.Contains(split[1], item.Content.URL) {
+		t.Errorf("description should link the issue:\n%s", split[1])
+	}
+
+	item.Content.Body = "Nothing to split"
+	if got := syncTaskDescriptions(item, true); len(got) != 1 {
+		t.Errorf("issue without checklist should give one task, got %d", len(got))
+	}
+}
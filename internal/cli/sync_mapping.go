@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// syncRoute sends the items in a source status column to a target column
+// once tasks are created for them
+type syncRoute struct {
+	Source string
+	Target string
+
+	targetOptionID string
+}
+
+// loadStatusMapping reads a --status-mapping file: a JSON object mapping
+// each source status column to the column its items move to, e.g.
+// {"Ready": "Doing", "Review": "Verifying"}. Routes are returned sorted by
+// source column so runs are repeatable.
+func loadStatusMapping(path string) ([]syncRoute, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read status mapping: %w", err)
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("parse status mapping %s: %w", path, err)
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("status mapping %s maps no columns", path)
+	}
+
+	routes := make([]syncRoute, 0, len(mapping))
+	for source, target := range mapping {
+		source, target = strings.TrimSpace(source), strings.TrimSpace(target)
+		if source == "" || target == "" {
+			return nil, fmt.Errorf("status mapping %s: column names must not be empty", path)
+		}
+		routes = append(routes, syncRoute{Source: source, Target: target})
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Source < routes[j].Source })
+	return routes, nil
+}
+
+// resolveSyncRoutes checks that every column the routes reference is an
+// option of the project's Status field and fills in the target option IDs.
+// All missing columns are reported together, before anything is synced.
+func resolveSyncRoutes(routes []syncRoute, statusField *ghField) error {
+	optionIDs := make(map[string]string, len(statusField.Options))
+	var available []string
+	for _, opt := range statusField.Options {
+		optionIDs[opt.Name] = opt.ID
+		available = append(available, opt.Name)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for i, route := range routes {
+		for _, column := range []string{route.Source, route.Target} {
+			if _, ok := optionIDs[column]; !ok && !seen[column] {
+				missing = append(missing, fmt.Sprintf("%q", column))
+				seen[column] = true
+			}
+		}
+		routes[i].targetOptionID = optionIDs[route.Target]
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("status column(s) %s not found. Available options: %s", strings.Join(missing, ", "), strings.Join(available, ", "))
+	}
+	return nil
+}
+
+// suggestSourceColumn proposes a source column when the requested one isn't
+// an option of the Status field: an option differing only in case, or else
+// the board's leftmost column other than target, which is usually its
+// backlog. It returns "" if there is nothing to suggest, and otherwise the
+// suggestion and why it was chosen.
+func suggestSourceColumn(statusField *ghField, requested, target string) (column, reason string) {
+	for _, opt := range statusField.Options {
+		if opt.Name == requested {
+			return "", ""
+		}
+	}
+	for _, opt := range statusField.Options {
+		if strings.EqualFold(opt.Name, requested) {
+			return opt.Name, "same name, different case"
+		}
+	}
+	for _, opt := range statusField.Options {
+		if opt.Name != target {
+			return opt.Name, "the board's leftmost column, likely its backlog"
+		}
+	}
+	return "", ""
+}
+
+// routedItem is a project item selected for syncing and the route it
+// was selected by
+type routedItem struct {
+	item  ghItem
+	route syncRoute
+}
+
+// selectRoutedItems selects the items to sync for each route in turn, up to
+// limit items across all of them
+func selectRoutedItems(items []ghItem, routes []syncRoute, labels []string, limit int) []routedItem {
+	var selected []routedItem
+	for _, route := range routes {
+		if len(selected) >= limit {
+			break
+		}
+		for _, item := range selectSyncItems(items, route.Source, labels, limit-len(selected)) {
+			selected = append(selected, routedItem{item: item, route: route})
+		}
+	}
+	return selected
+}
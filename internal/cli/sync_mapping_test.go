@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStatusMapping(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write mapping: %v", err)
+	}
+	return path
+}
+
+func TestLoadStatusMapping(t *testing.T) {
+	routes, err := loadStatusMapping(writeStatusMapping(t, `{"Review": "Verifying", "Ready": "Doing"}`))
+	if err != nil {
+		t.Fatalf("loadStatusMapping: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+	if routes[0].Source != "Ready" || routes[0].Target != "Doing" {
+		t.Errorf("expected Ready -> Doing first, got %+v", routes[0])
+	}
+	if routes[1].Source != "Review" || routes[1].Target != "Verifying" {
+		t.Errorf("expected Review -> Verifying second, got %+v", routes[1])
+	}
+
+	for _, bad := range []string{`{}`, `{"Ready": ""}`, `["Ready"]`} {
+		if _, err := loadStatusMapping(writeStatusMapping(t, bad)); err == nil {
+			t.Errorf("expected error for mapping %s", bad)
+		}
+	}
+}
+
+func TestResolveSyncRoutes(t *testing.T) {
+	field := &ghField{Options: []ghFieldOption{
+		{ID: "opt-ready", Name: "Ready"},
+		{ID: "opt-doing", Name: "Doing"},
+		{ID: "opt-review", Name: "Review"},
+	}}
+
+	routes := []syncRoute{{Source: "Ready", Target: "Doing"}, {Source: "Review", Target: "Doing"}}
+	if err := resolveSyncRoutes(routes, field); err != nil {
+		t.Fatalf("resolveSyncRoutes: %v", err)
+	}
+	if routes[0].targetOptionID != "opt-doing" || routes[1].targetOptionID != "opt-doing" {
+		t.Errorf("expected target option IDs to be filled in, got %+v", routes)
+	}
+
+	routes = []syncRoute{{Source: "Ready", Target: "Shipped"}, {Source: "Backlog", Target: "Shipped"}}
+	err := resolveSyncRoutes(routes, field)
+	if err == nil {
+		t.Fatal("expected error for missing columns")
+	}
+	if !strings.Contains(err.Error(), `"Shipped", "Backlog"`) {
+		t.Errorf("expected each missing column once, got %v", err)
+	}
+}
+
+func TestSuggestSourceColumn(t *testing.T) {
+	field := &ghField{Options: []ghFieldOption{
+		{ID: "opt-backlog", Name: "Backlog"},
+		{ID: "opt-todo", Name: "TODO"},
+		{ID: "opt-doing", Name: "In Progress"},
+	}}
+
+	tests := []struct {
+		requested, target string
+		want              string
+	}{
+		{"TODO", "In Progress", ""},
+		{"Todo", "In Progress", "TODO"},
+		{"Ready", "In Progress", "Backlog"},
+		{"Ready", "Backlog", "TODO"},
+	}
+	for _, tt := range tests {
+		if got, _ := suggestSourceColumn(field, tt.requested, tt.target); got != tt.want {
+			t.Errorf("suggestSourceColumn(%q, %q) = %q, want %q", tt.requested, tt.target, got, tt.want)
+		}
+	}
+
+	if got, _ := suggestSourceColumn(&ghField{}, "Todo", "Done"); got != "" {
+		t.Errorf("suggestSourceColumn with no options = %q, want none", got)
+	}
+}
+
+func TestSelectRoutedItems(t *testing.T) {
+	items := []ghItem{
+		{ID: "1", Status: "Ready", Content: ghItemContent{Type: "Issue"}},
+		{ID: "2", Status: "Review", Content: ghItemContent{Type: "Issue"}},
+		{ID: "3", Status: "Ready", Content: ghItemContent{Type: "Issue"}},
+		{ID: "4", Status: "Done", Content: ghItemContent{Type: "Issue"}},
+	}
+	routes := []syncRoute{{Source: "Ready", Target: "Doing"}, {Source: "Review", Target: "Verifying"}}
+
+	selected := selectRoutedItems(items, routes, nil, 10)
+	if len(selected) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(selected))
+	}
+	if selected[2].item.ID != "2" || selected[2].route.Target != "Verifying" {
+		t.Errorf("expected item 2 routed to Verifying, got %+v", selected[2])
+	}
+
+	if selected := selectRoutedItems(items, routes, nil, 2); len(selected) != 2 || selected[1].item.ID != "3" {
+		t.Errorf("expected limit to apply across routes, got %+v", selected)
+	}
+}
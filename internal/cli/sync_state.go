@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// syncStateVersion is written to new state files so the format can evolve
+const syncStateVersion = 1
+
+// syncState records which issues `task sync` has already turned into tasks.
+// It lives in a file outside the daemon's database, so syncing stays
+// idempotent across database resets and, when the file is committed, across
+// machines.
+type syncState struct {
+	Version int                        `json:"version"`
+	Issues  map[string]syncedIssueInfo `json:"issues"`
+
+	path string
+}
+
+// syncedIssueInfo is what the state file remembers about a synced issue
+type syncedIssueInfo struct {
+	TaskID   string    `json:"task_id"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// syncedIssueKey identifies an issue as provider/owner/repo/number
+func syncedIssueKey(provider, owner, repo string, number int) string {
+	return fmt.Sprintf("%s/%s/%s/%d", provider, owner, repo, number)
+}
+
+// loadSyncState reads the state file at path. A missing file is an empty
+// state that will be created on the first save.
+func loadSyncState(path string) (*syncState, error) {
+	state := &syncState{Version: syncStateVersion, Issues: map[string]syncedIssueInfo{}, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read sync state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse sync state %s: %w", path, err)
+	}
+	if state.Issues == nil {
+		state.Issues = map[string]syncedIssueInfo{}
+	}
+	return state, nil
+}
+
+// lookup returns what was recorded for an issue, if it was synced before
+func (s *syncState) lookup(key string) (syncedIssueInfo, bool) {
+	info, ok := s.Issues[key]
+	return info, ok
+}
+
+// record notes that an issue was synced as taskID and saves the file, so a
+// sync interrupted partway still remembers the issues it finished
+func (s *syncState) record(key, taskID string) error {
+	s.Issues[key] = syncedIssueInfo{TaskID: taskID, SyncedAt: time.Now().UTC()}
+	return s.save()
+}
+
+// save writes the state file, replacing it atomically
+func (s *syncState) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode sync state: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create sync state directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("write sync state: %w", err)
+	}
+	return nil
+}
+
+// resolveSyncStatePath makes a relative state file path relative to the
+// repository root, so the same file is used from any subdirectory
+func resolveSyncStatePath(path, repoRoot string) string {
+	if path == "" || filepath.IsAbs(path) || repoRoot == "" {
+		return path
+	}
+	return filepath.Join(repoRoot, path)
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".map", "synced-issues.json")
+
+	state, err := loadSyncState(path)
+	if err != nil {
+		t.Fatalf("loadSyncState on a missing file: %v", err)
+	}
+	if len(state.Issues) != 0 {
+		t.Fatalf("new state has %d issues, want 0", len(state.Issues))
+	}
+
+	key := syncedIssueKey("github", "acme", "api", 42)
+	if err := state.record(key, "task-1"); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	reloaded, err := loadSyncState(path)
+	if err != nil {
+		t.Fatalf("loadSyncState: %v", err)
+	}
+	info, ok := reloaded.lookup(key)
+	if !ok || info.TaskID != "task-1" || info.SyncedAt.IsZero() {
+		t.Errorf("lookup(%s) = %+v, %v; want task-1 with a sync time", key, info, ok)
+	}
+	if reloaded.Version != syncStateVersion {
+		t.Errorf("Version = %d, want %d", reloaded.Version, syncStateVersion)
+	}
+	if _, ok := reloaded.lookup(syncedIssueKey("github", "acme", "api", 43)); ok {
+		t.Error("unrecorded issue reported as synced")
+	}
+}
+
+func TestLoadSyncState_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSyncState(path); err == nil {
+		t.Error("expected an error for a corrupt state file")
+	}
+}
+
+func TestResolveSyncStatePath(t *testing.T) {
+	if got := resolveSyncStatePath(".map/synced-issues.json", "/repo"); got != "/repo/.map/synced-issues.json" {
+		t.Errorf("relative path = %q", got)
+	}
+	if got := resolveSyncStatePath("/etc/state.json", "/repo"); got != "/etc/state.json" {
+		t.Errorf("absolute path = %q", got)
+	}
+	if got := resolveSyncStatePath("state.json", ""); got != "state.json" {
+		t.Errorf("outside a repo = %q", got)
+	}
+}
+
+func TestDropSyncedItems(t *testing.T) {
+	state, err := loadSyncState(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Issues[syncedIssueKey("github", "acme", "api", 1)] = syncedIssueInfo{TaskID: "t1"}
+	state.Issues[syncedIssueKey("github", "acme", "api", 3)] = syncedIssueInfo{TaskID: "t3"}
+
+	issue := func(id, status string, number int) ghItem {
+		return ghItem{ID: id, Status: status, Content: ghItemContent{
+			Type: "Issue", Number: number, URL: "https://github.com/acme/api/issues/" + id,
+		}}
+	}
+	items := []ghItem{
+		issue("1", "Todo", 1),
+		issue("2", "Todo", 2),
+		issue("3", "In Progress", 3),
+		{ID: "draft", Status: "Todo", Content: ghItemContent{Type: "DraftIssue", Title: "idea"}},
+	}
+
+	kept, dropped := dropSyncedItems(items, "Todo", state)
+	if dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+	var ids []string
+	for _, item := range kept {
+		ids = append(ids, item.ID)
+	}
+	want := []string{"2", "3", "draft"}
+	if len(ids) != len(want) {
+		t.Fatalf("kept = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("kept = %v, want %v", ids, want)
+		}
+	}
+}
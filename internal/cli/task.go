@@ -2,14 +2,18 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
 	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // getRepoRoot returns the git repository root for the current directory
@@ -31,17 +35,60 @@ var taskCmd = &cobra.Command{
 var taskSubmitCmd = &cobra.Command{
 	Use:   "submit <description>",
 	Short: "Submit a new task",
-	Long:  `Create and submit a new task for agent processing.`,
-	Args:  cobra.MinimumNArgs(1),
-	RunE:  runTaskSubmit,
+	Long: `Create and submit a new task for agent processing.
+
+Use --wait to block until an agent picks the task up, or --attach to also
+attach to that agent's tmux session and watch it work. If no agent takes the
+task within --wait-timeout, the task stays queued and the command exits.
+
+Use --run to group related tasks, e.g. a batch submitted from a script, so
+'map run show' can report their progress together. Any ID works; tasks
+submitted with the same --run belong to the same run.
+
+Use --edit to write the description in $VISUAL/$EDITOR (default: vi), as
+'git commit' does. Any description given on the command line pre-fills the
+editor. Lines starting with '#' are ignored, and an empty or unchanged
+description aborts the submission.
+
+Use --prefer-agent to send the task to a particular agent when it is idle,
+or --follow-up to prefer the agent that ran an earlier task, which still has
+its context. If that agent is busy or gone, the task is scheduled as usual.
+Retried tasks automatically prefer the agent that ran them before.
+
+Use --no-worktree for read-only tasks, such as analysis or questions, that
+don't need an isolated worktree. They go to an idle agent working in the
+shared checkout when there is one, leaving worktree agents free for tasks
+that change files, and otherwise are scheduled as usual.
+
+Use --repeat to submit the task on a cron schedule instead of once, e.g.
+--repeat "0 9 * * 1" for 9am every Monday. Schedules use the daemon's local
+time zone; see 'map task schedule --help' for the syntax and how to manage
+recurring tasks.
+
+Use --blocking-output to run a task from a script or CI job: the command
+spawns an agent if none is idle, streams the agent's output to stdout as it
+works, and exits once the task finishes (whether the agent reports it done
+or its GitHub issue is closed). The exit status is 0 if the task completed,
+1 if it failed, and 2 if it was cancelled. Ctrl+C cancels the task. Status
+messages go to stderr. A spawned agent is kept for the task alone until it
+starts, and stays in the pool afterwards, so later blocking tasks reuse it.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if taskEdit {
+			return nil
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	RunE: runTaskSubmit,
 }
 
 var taskListCmd = &cobra.Command{
 	Use:     "ls",
 	Aliases: []string{"list"},
 	Short:   "List tasks",
-	Long:    `List all tasks with their current status.`,
-	RunE:    runTaskList,
+	Long: `List tasks in the current repository with their current status.
+
+Archived tasks are hidden; use --include-archived to list them too.`,
+	RunE: runTaskList,
 }
 
 var taskShowCmd = &cobra.Command{
@@ -53,32 +100,108 @@ var taskShowCmd = &cobra.Command{
 }
 
 var taskCancelCmd = &cobra.Command{
-	Use:   "cancel <task-id>",
+	Use:   "cancel [task-id]",
 	Short: "Cancel a task",
-	Long:  `Cancel a pending or in-progress task.`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runTaskCancel,
+	Long: `Cancel a pending or in-progress task.
+
+Use --all-pending to clear the queue: every pending task, in any repository,
+is cancelled. Add --status in_progress to cancel every in-progress task
+instead. The tasks are listed and you are asked to confirm first, unless
+--yes is given.
+
+Examples:
+  map task cancel 3f2a9c1e-...
+  map task cancel --all-pending
+  map task cancel --all-pending --status in_progress --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskCancel,
+}
+
+var taskNudgeCmd = &cobra.Command{
+	Use:   "nudge <task-id>",
+	Short: "Re-send a task's prompt to its agent",
+	Long: `Re-send an in-progress task's prompt to the agent working on it, for when
+the agent stalled or the original prompt never landed. The task's status and
+assignment are left unchanged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskNudge,
 }
 
 var (
-	taskLimit int32
-	taskPaths []string
+	taskLimit       int32
+	taskArchived    bool
+	taskPaths       []string
+	taskWait        bool
+	taskAttach      bool
+	taskWaitTimeout time.Duration
+	taskRun         string
+	taskEdit        bool
+	taskPreferAgent string
+	taskFollowUp    string
+	taskNoWorktree  bool
+	taskRepeat      string
+	taskAgentType   string
+	taskBlocking    bool
+
+	cancelAllPending bool
+	cancelStatus     string
+	cancelYes        bool
 )
 
 func init() {
 	taskSubmitCmd.Flags().StringSliceVarP(&taskPaths, "path", "p", nil, "scope paths for the task")
+	taskSubmitCmd.Flags().BoolVarP(&taskWait, "wait", "w", false, "wait until an agent picks up the task")
+	taskSubmitCmd.Flags().BoolVarP(&taskAttach, "attach", "a", false, "attach to the assigned agent's session (implies --wait)")
+	taskSubmitCmd.Flags().DurationVar(&taskWaitTimeout, "wait-timeout", 60*time.Second, "how long --wait/--attach wait for an agent")
+	taskSubmitCmd.Flags().StringVar(&taskRun, "run", "", "group the task into a run, tracked with 'map run show'")
+	taskSubmitCmd.Flags().BoolVarP(&taskEdit, "edit", "e", false, "compose the description in $EDITOR")
+	taskSubmitCmd.Flags().StringVar(&taskPreferAgent, "prefer-agent", "", "route the task to this agent when it is idle")
+	taskSubmitCmd.Flags().StringVar(&taskFollowUp, "follow-up", "", "prefer the agent that ran this earlier task")
+	taskSubmitCmd.MarkFlagsMutuallyExclusive("prefer-agent", "follow-up")
+	taskSubmitCmd.Flags().BoolVar(&taskNoWorktree, "no-worktree", false, "read-only task: prefer an agent in the shared checkout over a worktree agent")
+	taskSubmitCmd.Flags().StringVar(&taskAgentType, "agent-type", "", "prefer an idle agent of this type ("+strings.Join(daemon.AgentTypes(), ", ")+"); overrides routing rules")
+	taskSubmitCmd.Flags().StringVar(&taskRepeat, "repeat", "", "submit the task each time a cron schedule fires (e.g. \"0 9 * * 1\")")
+	taskSubmitCmd.Flags().BoolVar(&taskBlocking, "blocking-output", false, "stream the agent's output until the task finishes and exit with its status")
+	for _, flag := range []string{"wait", "attach", "run", "prefer-agent", "follow-up", "no-worktree", "agent-type", "blocking-output"} {
+		taskSubmitCmd.MarkFlagsMutuallyExclusive("repeat", flag)
+	}
+	taskSubmitCmd.MarkFlagsMutuallyExclusive("blocking-output", "wait")
+	taskSubmitCmd.MarkFlagsMutuallyExclusive("blocking-output", "attach")
+	taskSubmitCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		// --interactive-edit is accepted as an alias for --edit
+		if name == "interactive-edit" {
+			name = "edit"
+		}
+		return pflag.NormalizedName(name)
+	})
+	taskCancelCmd.Flags().BoolVar(&cancelAllPending, "all-pending", false, "cancel every pending task (or every task in --status)")
+	taskCancelCmd.Flags().StringVar(&cancelStatus, "status", "pending", "with --all-pending, the status to cancel: pending or in_progress")
+	taskCancelCmd.Flags().BoolVarP(&cancelYes, "yes", "y", false, "with --all-pending, don't ask for confirmation")
 	taskListCmd.Flags().Int32VarP(&taskLimit, "limit", "n", 20, "maximum number of tasks to show")
+	taskListCmd.Flags().BoolVar(&taskArchived, "include-archived", false, "also list archived tasks")
 
 	taskCmd.AddCommand(taskSubmitCmd)
 	taskCmd.AddCommand(taskListCmd)
 	taskCmd.AddCommand(taskShowCmd)
 	taskCmd.AddCommand(taskCancelCmd)
+	taskCmd.AddCommand(taskNudgeCmd)
 	taskCmd.AddCommand(taskSyncCmd)
 	rootCmd.AddCommand(taskCmd)
 }
 
 func runTaskSubmit(cmd *cobra.Command, args []string) error {
 	description := strings.Join(args, " ")
+	if taskEdit {
+		edited, err := composeDescription(description, taskPaths)
+		if err != nil {
+			return err
+		}
+		description = edited
+	}
+
+	if taskRepeat != "" {
+		return createSchedule(taskRepeat, description, taskPaths)
+	}
 
 	c, err := client.New(getSocketPath())
 	if err != nil {
@@ -86,16 +209,163 @@ func runTaskSubmit(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = c.Close() }()
 
+	// With --blocking-output, stdout carries only the agent's output, and
+	// an agent is spawned for the task if none is idle
+	info := os.Stdout
+	var spawned string
+	if taskBlocking {
+		info = os.Stderr
+		if taskPreferAgent == "" && taskFollowUp == "" {
+			spawned, err = ensureIdleAgent(c, getRepoRoot(), taskAgentType)
+			if err != nil {
+				return err
+			}
+			if spawned != "" && !isQuiet() {
+				fmt.Fprintf(info, "spawned agent: %s\n", spawned)
+			}
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	task, err := c.SubmitTask(ctx, description, taskPaths)
+	// Resolve scope paths against the current directory so the daemon can
+	// rebase them onto the agent's worktree
+	scopePaths, err := absPaths(taskPaths)
 	if err != nil {
+		return err
+	}
+
+	preferAgent, err := resolvePreferredAgent(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	if spawned != "" {
+		preferAgent = spawned
+	}
+
+	// Tag the task with the repo so 'map task ls' and 'map run ls' here
+	// list it
+	task, err := c.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
+		Description: description,
+		ScopePaths:  scopePaths,
+		RepoRoot:    getRepoRoot(),
+		RunId:       taskRun,
+		PreferAgent: preferAgent,
+		NoWorktree:  taskNoWorktree,
+		AgentType:   taskAgentType,
+	})
+	if err != nil {
+		// The agent spawned for the task is held for it, so it would
+		// otherwise sit idle for good
+		if spawned != "" {
+			if killErr := removeHeldAgent(c, spawned); killErr != nil {
+				return fmt.Errorf("submit task: %w (and remove agent %s: %v)", err, spawned, killErr)
+			}
+		}
 		return fmt.Errorf("submit task: %w", err)
 	}
 
-	fmt.Printf("task created: %s\n", task.TaskId)
-	return nil
+	if isQuiet() {
+		fmt.Fprintln(info, task.TaskId)
+	} else {
+		fmt.Fprintf(info, "task created: %s\n", task.TaskId)
+		if task.EstimateSampleSize > 0 {
+			fmt.Fprintf(info, "estimated duration: %s (median of %d similar tasks)\n",
+				formatEstimate(task.EstimatedDurationSeconds), task.EstimateSampleSize)
+		}
+	}
+
+	if taskBlocking {
+		err := followTaskToCompletion(c, task.TaskId)
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			// A failed or cancelled task isn't a usage mistake
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+		}
+		return err
+	}
+
+	if !taskWait && !taskAttach {
+		return nil
+	}
+
+	if !isQuiet() {
+		fmt.Println("waiting for an agent to pick up the task...")
+	}
+	assigned, err := waitForAssignment(c, task.TaskId, taskWaitTimeout)
+	if err != nil {
+		return err
+	}
+	if assigned.AssignedTo == "" {
+		fmt.Printf("no agent picked up the task within %s; it remains %s\n",
+			taskWaitTimeout, taskStatusString(assigned.Status))
+		fmt.Printf("check on it with 'map task show %s'\n", task.TaskId)
+		return nil
+	}
+
+	if !taskAttach {
+		fmt.Printf("task assigned to %s (%s)\n", assigned.AssignedTo, taskStatusString(assigned.Status))
+		return nil
+	}
+
+	return attachToAgent(c, assigned.AssignedTo)
+}
+
+// resolvePreferredAgent returns the agent a submitted task should prefer:
+// the --prefer-agent agent, or the agent that ran the --follow-up task
+func resolvePreferredAgent(ctx context.Context, c *client.Client) (string, error) {
+	if taskPreferAgent != "" {
+		return resolveAgentID(ctx, c, taskPreferAgent)
+	}
+	if taskFollowUp == "" {
+		return "", nil
+	}
+
+	prior, err := c.GetTask(ctx, taskFollowUp)
+	if err != nil {
+		return "", fmt.Errorf("get task %s: %w", taskFollowUp, err)
+	}
+	if prior.AssignedTo != "" {
+		return prior.AssignedTo, nil
+	}
+	if prior.PreferAgent != "" {
+		return prior.PreferAgent, nil
+	}
+	return "", fmt.Errorf("task %s was never assigned to an agent", taskFollowUp)
+}
+
+// waitForAssignment polls a task until it is assigned to an agent, finishes,
+// or the timeout elapses. The latest task state is returned in every case;
+// an unassigned result means no agent took the task in time.
+func waitForAssignment(c *client.Client, taskID string, timeout time.Duration) (*mapv1.Task, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		task, err := c.GetTask(ctx, taskID)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("get task: %w", err)
+		}
+
+		if task.AssignedTo != "" || isTerminalTaskStatus(task.Status) || time.Now().After(deadline) {
+			return task, nil
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// isTerminalTaskStatus reports whether a task can no longer change state
+func isTerminalTaskStatus(s mapv1.TaskStatus) bool {
+	switch s {
+	case mapv1.TaskStatus_TASK_STATUS_COMPLETED,
+		mapv1.TaskStatus_TASK_STATUS_FAILED,
+		mapv1.TaskStatus_TASK_STATUS_CANCELLED:
+		return true
+	}
+	return false
 }
 
 func runTaskList(cmd *cobra.Command, args []string) error {
@@ -110,7 +380,7 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 
 	// Filter by current repo
 	repoRoot := getRepoRoot()
-	tasks, err := c.ListTasks(ctx, taskLimit, repoRoot)
+	tasks, err := c.ListTasks(ctx, taskLimit, repoRoot, taskArchived)
 	if err != nil {
 		return fmt.Errorf("list tasks: %w", err)
 	}
@@ -123,18 +393,27 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	fmt.Printf("%-36s %-15s %-20s %s\n", "TASK ID", "STATUS", "ASSIGNED TO", "DESCRIPTION")
 	fmt.Println(strings.Repeat("-", 100))
 
+	var archived bool
 	for _, task := range tasks {
 		assignedTo := task.AssignedTo
 		if assignedTo == "" {
 			assignedTo = "-"
 		}
+		status := taskStatusString(task.Status)
+		if task.ArchivedAt != nil {
+			status += "*"
+			archived = true
+		}
 		fmt.Printf("%-36s %-15s %-20s %s\n",
 			task.TaskId,
-			taskStatusString(task.Status),
+			status,
 			truncate(assignedTo, 20),
 			truncate(task.Description, 40),
 		)
 	}
+	if archived {
+		fmt.Println("\n* archived")
+	}
 
 	return nil
 }
@@ -160,12 +439,47 @@ func runTaskShow(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Status:      %s\n", taskStatusString(task.Status))
 	fmt.Printf("Description: %s\n", task.Description)
 	fmt.Printf("Assigned To: %s\n", valueOrDash(task.AssignedTo))
+	if task.RunId != "" {
+		fmt.Printf("Run:         %s\n", task.RunId)
+	}
+	if task.PreferAgent != "" && task.AssignedTo == "" {
+		fmt.Printf("Prefers:     %s\n", task.PreferAgent)
+	}
+	if task.AgentType != "" {
+		fmt.Printf("Agent type:  %s\n", task.AgentType)
+	}
+	if task.NoWorktree {
+		fmt.Printf("Worktree:    not needed (read-only)\n")
+	}
 	fmt.Printf("Created:     %s\n", task.CreatedAt.AsTime().Local().Format(time.RFC3339))
 	fmt.Printf("Updated:     %s\n", task.UpdatedAt.AsTime().Local().Format(time.RFC3339))
+	if task.StartedAt != nil {
+		fmt.Printf("Started:     %s\n", task.StartedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	if task.CompletedAt != nil {
+		fmt.Printf("Completed:   %s\n", task.CompletedAt.AsTime().Local().Format(time.RFC3339))
+		if task.StartedAt != nil {
+			took := task.CompletedAt.AsTime().Sub(task.StartedAt.AsTime())
+			fmt.Printf("Duration:    %s\n", formatEstimate(int64(took/time.Second)))
+		}
+	}
+	if task.ArchivedAt != nil {
+		fmt.Printf("Archived:    %s\n", task.ArchivedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	if task.RetryCount > 0 {
+		fmt.Printf("Retries:     %d\n", task.RetryCount)
+	}
+	if task.EstimateSampleSize > 0 {
+		fmt.Printf("Estimate:    %s (median of %d similar tasks)\n",
+			formatEstimate(task.EstimatedDurationSeconds), task.EstimateSampleSize)
+	}
 
 	if len(task.ScopePaths) > 0 {
 		fmt.Printf("Scope Paths: %s\n", strings.Join(task.ScopePaths, ", "))
 	}
+	if env := task.Environment; env != nil {
+		fmt.Printf("\n--- Environment (last start) ---\n%s", formatEnvironment(env))
+	}
 	if task.Error != "" {
 		fmt.Printf("\n--- Error ---\n%s\n", task.Error)
 	}
@@ -176,7 +490,44 @@ func runTaskShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatEnvironment renders the snapshot taken when the task last started
+func formatEnvironment(env *mapv1.TaskEnvironment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Agent:       %s (%s)\n", env.AgentId, env.AgentType)
+	fmt.Fprintf(&b, "Model:       %s\n", valueOr(env.Model, "CLI default"))
+	fmt.Fprintf(&b, "Permissions: %s\n", env.PermissionMode)
+	if env.Headless {
+		fmt.Fprintf(&b, "Mode:        headless\n")
+	}
+	if env.Image != "" {
+		fmt.Fprintf(&b, "Image:       %s\n", env.Image)
+	}
+	if env.Workdir != "" {
+		kind := "shared checkout"
+		if env.Worktree {
+			kind = "worktree"
+		}
+		fmt.Fprintf(&b, "Workdir:     %s (%s)\n", env.Workdir, kind)
+	}
+	if env.HeadSha != "" {
+		fmt.Fprintf(&b, "Commit:      %s on %s\n", env.HeadSha, valueOr(env.Branch, "detached HEAD"))
+	}
+	if env.CapturedAt != nil {
+		fmt.Fprintf(&b, "Captured:    %s\n", env.CapturedAt.AsTime().Local().Format(time.RFC3339))
+	}
+	return b.String()
+}
+
 func runTaskCancel(cmd *cobra.Command, args []string) error {
+	if cancelAllPending == (len(args) == 1) {
+		return errors.New("specify either a task ID or --all-pending")
+	}
+	if cmd.Flags().Changed("status") && !cancelAllPending {
+		return errors.New("--status requires --all-pending")
+	}
+	if cancelAllPending {
+		return cancelAllInStatus(cancelStatus, cancelYes)
+	}
 	taskID := args[0]
 
 	c, err := client.New(getSocketPath())
@@ -197,6 +548,28 @@ func runTaskCancel(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runTaskNudge(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	// Sending a long prompt to tmux takes a few seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	task, err := c.NudgeTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("nudge task: %w", err)
+	}
+
+	fmt.Printf("task prompt re-sent: %s (agent: %s)\n", task.TaskId, task.AssignedTo)
+	return nil
+}
+
 func taskStatusString(s mapv1.TaskStatus) string {
 	switch s {
 	case mapv1.TaskStatus_TASK_STATUS_PENDING:
@@ -220,9 +593,23 @@ func taskStatusString(s mapv1.TaskStatus) string {
 	}
 }
 
+// formatEstimate renders a duration in seconds rounded for display
+func formatEstimate(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	if d >= time.Minute {
+		d = d.Round(time.Minute)
+	}
+	return d.String()
+}
+
 func valueOrDash(s string) string {
+	return valueOr(s, "-")
+}
+
+// valueOr returns s, or fallback when s is empty
+func valueOr(s, fallback string) string {
 	if s == "" {
-		return "-"
+		return fallback
 	}
 	return s
 }
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var taskAcceptCmd = &cobra.Command{
+	Use:   "accept <task-id>",
+	Short: "Accept a task offered to this agent",
+	Long: `Claim a task the daemon offered to this agent and start working on it.
+
+Agents created with 'map agent create --offer-tasks' are offered tasks
+rather than assigned them: the offer is typed into the agent's session and
+the agent answers from there with 'map task accept' or 'map task reject'.
+--agent names the answering agent, which must be the one the task was
+offered to; the offer says which. An accepted task moves to in_progress and
+its prompt is sent to the agent as usual. Offers that aren't answered
+within 5 minutes, or that are still open when the daemon restarts, count
+as rejected.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskAccept,
+}
+
+var taskRejectCmd = &cobra.Command{
+	Use:   "reject <task-id> [reason]",
+	Short: "Decline a task offered to this agent",
+	Long: `Decline a task the daemon offered to this agent, e.g. because it is
+outside the agent's expertise or conflicts with its current work.
+
+The task goes back to pending and is offered to, or assigned to, another
+agent. The refusal is recorded on the task, so it isn't offered to this
+agent again. The optional reason is written to the daemon log. --agent
+names the answering agent, as for 'map task accept'.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTaskReject,
+}
+
+var taskOfferAgent string
+
+func init() {
+	for _, cmd := range []*cobra.Command{taskAcceptCmd, taskRejectCmd} {
+		cmd.Flags().StringVar(&taskOfferAgent, "agent", "", "the agent answering the offer (required)")
+		_ = cmd.MarkFlagRequired("agent")
+		taskCmd.AddCommand(cmd)
+	}
+}
+
+func runTaskAccept(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.AcceptTask(ctx, args[0], taskOfferAgent)
+	if err != nil {
+		return fmt.Errorf("accept task: %w", err)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("task accepted: %s (%s on %s)\n", task.TaskId, taskStatusString(task.Status), task.AssignedTo)
+	}
+	return nil
+}
+
+func runTaskReject(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.RejectTask(ctx, args[0], taskOfferAgent, strings.Join(args[1:], " "))
+	if err != nil {
+		return fmt.Errorf("reject task: %w", err)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("task rejected: %s (back to %s)\n", task.TaskId, taskStatusString(task.Status))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var taskArchiveCmd = &cobra.Command{
+	Use:   "archive <task-id>",
+	Short: "Hide a finished task from task listings",
+	Long: `Archive a completed, failed, or cancelled task. Archived tasks are left out
+of 'map task ls' and the TUI but are kept: 'map task show' still finds them,
+'map task ls --include-archived' lists them, and 'map task export' includes
+them.
+
+Retrying or reopening an archived task brings it back.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskArchive,
+}
+
+func init() {
+	taskCmd.AddCommand(taskArchiveCmd)
+}
+
+func runTaskArchive(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.ArchiveTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("archive task: %w", err)
+	}
+
+	if !isQuiet() {
+		fmt.Printf("task archived: %s (%s)\n", task.TaskId, taskStatusString(task.Status))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var taskAttemptsCmd = &cobra.Command{
+	Use:   "attempts <task-id>",
+	Short: "List a task's attempts and compare their changes",
+	Long: `List every attempt at a task: the first run and each retry, with the
+agent that ran it, its outcome, and how many files it changed.
+
+Use --diff to compare the changed-file sets of two attempts. With no value
+it compares the two most recent attempts; pass two attempt numbers to pick
+them, e.g. --diff=1,3.
+
+Examples:
+  map task attempts 3f2a9c1e-...
+  map task attempts 3f2a9c1e-... --diff
+  map task attempts 3f2a9c1e-... --diff=1,3`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskAttempts,
+}
+
+var taskAttemptsDiff string
+
+// latestAttempts is the --diff value used when the flag is given without one
+const latestAttempts = "latest"
+
+func init() {
+	taskAttemptsCmd.Flags().StringVar(&taskAttemptsDiff, "diff", "", "compare the changed files of two attempts (e.g. --diff=1,3; default: the last two)")
+	taskAttemptsCmd.Flags().Lookup("diff").NoOptDefVal = latestAttempts
+
+	taskCmd.AddCommand(taskAttemptsCmd)
+}
+
+func runTaskAttempts(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	attempts, err := c.ListTaskAttempts(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("list task attempts: %w", err)
+	}
+
+	if taskAttemptsDiff != "" {
+		a, b, err := pickAttempts(attempts, taskAttemptsDiff)
+		if err != nil {
+			return err
+		}
+		printAttemptDiff(a, b)
+		return nil
+	}
+
+	if len(attempts) == 0 {
+		fmt.Println("no attempts recorded for this task")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-15s %-20s %-20s %-6s %s\n", "ATTEMPT", "STATUS", "AGENT", "STARTED", "FILES", "OUTCOME")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, attempt := range attempts {
+		started := "-"
+		if attempt.StartedAt != nil {
+			started = attempt.StartedAt.AsTime().Local().Format("2006-01-02 15:04:05")
+		}
+		outcome := attempt.Result
+		if attempt.Error != "" {
+			outcome = "error: " + attempt.Error
+		}
+		outcome = strings.Join(strings.Fields(outcome), " ")
+		if outcome == "" {
+			outcome = "-"
+		}
+		fmt.Printf("%-8d %-15s %-20s %-20s %-6d %s\n",
+			attempt.Attempt,
+			taskStatusString(attempt.Status),
+			truncate(attempt.AgentId, 20),
+			started,
+			len(attempt.ChangedFiles),
+			truncate(outcome, 40),
+		)
+	}
+
+	return nil
+}
+
+// pickAttempts resolves a --diff value to the two attempts to compare: the
+// last two for latestAttempts, otherwise the attempt numbers in "a,b"
+func pickAttempts(attempts []*mapv1.TaskAttempt, spec string) (*mapv1.TaskAttempt, *mapv1.TaskAttempt, error) {
+	if spec == latestAttempts {
+		if len(attempts) < 2 {
+			return nil, nil, fmt.Errorf("task has %d attempt(s); need two to diff", len(attempts))
+		}
+		return attempts[len(attempts)-2], attempts[len(attempts)-1], nil
+	}
+
+	parts := strings.Split(spec, ",")
+	if len(parts) != 2 {
+		return nil, nil, fmt.Errorf("invalid --diff %q: want two attempt numbers, e.g. --diff=1,3", spec)
+	}
+
+	var picked [2]*mapv1.TaskAttempt
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid --diff %q: want two attempt numbers, e.g. --diff=1,3", spec)
+		}
+		idx := slices.IndexFunc(attempts, func(a *mapv1.TaskAttempt) bool { return int(a.Attempt) == n })
+		if idx < 0 {
+			return nil, nil, fmt.Errorf("attempt %d not found", n)
+		}
+		picked[i] = attempts[idx]
+	}
+	return picked[0], picked[1], nil
+}
+
+// printAttemptDiff shows which files only one of two attempts changed
+func printAttemptDiff(a, b *mapv1.TaskAttempt) {
+	onlyA, onlyB, both := diffFileSets(a.ChangedFiles, b.ChangedFiles)
+
+	fmt.Printf("attempt %d (%s) vs attempt %d (%s)\n",
+		a.Attempt, taskStatusString(a.Status), b.Attempt, taskStatusString(b.Status))
+	for _, f := range onlyA {
+		fmt.Printf("- %s\n", f)
+	}
+	for _, f := range onlyB {
+		fmt.Printf("+ %s\n", f)
+	}
+	fmt.Printf("%d file(s) only in attempt %d, %d only in attempt %d, %d in both\n",
+		len(onlyA), a.Attempt, len(onlyB), b.Attempt, len(both))
+}
+
+// diffFileSets splits two file lists into files only in a, only in b, and in
+// both, each sorted
+func diffFileSets(a, b []string) (onlyA, onlyB, both []string) {
+	inB := make(map[string]bool, len(b))
+	for _, f := range b {
+		inB[f] = true
+	}
+	inA := make(map[string]bool, len(a))
+	for _, f := range a {
+		if inA[f] {
+			continue
+		}
+		inA[f] = true
+		if inB[f] {
+			both = append(both, f)
+		} else {
+			onlyA = append(onlyA, f)
+		}
+	}
+	for f := range inB {
+		if !inA[f] {
+			onlyB = append(onlyB, f)
+		}
+	}
+
+	slices.Sort(onlyA)
+	slices.Sort(onlyB)
+	slices.Sort(both)
+	return onlyA, onlyB, both
+}
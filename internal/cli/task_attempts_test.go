@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestDiffFileSets(t *testing.T) {
+	onlyA, onlyB, both := diffFileSets(
+		[]string{"b.go", "a.go", "shared.go"},
+		[]string{"shared.go", "c.go"},
+	)
+	if !slices.Equal(onlyA, []string{"a.go", "b.go"}) {
+		t.Errorf("onlyA = %v", onlyA)
+	}
+	if !slices.Equal(onlyB, []string{"c.go"}) {
+		t.Errorf("onlyB = %v", onlyB)
+	}
+	if !slices.Equal(both, []string{"shared.go"}) {
+		t.Errorf("both = %v", both)
+	}
+}
+
+func TestPickAttempts(t *testing.T) {
+	attempts := []*mapv1.TaskAttempt{{Attempt: 1}, {Attempt: 2}, {Attempt: 3}}
+
+	tests := []struct {
+		spec    string
+		a, b    int32
+		wantErr bool
+	}{
+		{spec: latestAttempts, a: 2, b: 3},
+		{spec: "1,3", a: 1, b: 3},
+		{spec: "3, 1", a: 3, b: 1},
+		{spec: "1,4", wantErr: true},
+		{spec: "1", wantErr: true},
+		{spec: "x,2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		a, b, err := pickAttempts(attempts, tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("pickAttempts(%q): expected error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("pickAttempts(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if a.Attempt != tt.a || b.Attempt != tt.b {
+			t.Errorf("pickAttempts(%q) = %d,%d, want %d,%d", tt.spec, a.Attempt, b.Attempt, tt.a, tt.b)
+		}
+	}
+
+	if _, _, err := pickAttempts(attempts[:1], latestAttempts); err == nil {
+		t.Error("expected error diffing a task with one attempt")
+	}
+}
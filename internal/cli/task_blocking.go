@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// Exit statuses of 'map task submit --blocking-output' for tasks that did
+// not complete
+const (
+	exitTaskFailed    = 1
+	exitTaskCancelled = 2
+)
+
+// ensureIdleAgent spawns an agent in repoRoot for a blocking task when none
+// of the right type is idle, and returns its ID so the task can prefer it.
+// It returns "" when an idle agent is already available. The agent is held
+// for the task, so an older pending task can't take it first; the caller
+// removes it if the task isn't submitted. Once the task is done the agent
+// stays in the pool, keeping its worktree with the task's changes, and
+// later blocking tasks reuse it rather than spawning another.
+func ensureIdleAgent(c *client.Client, repoRoot, agentType string) (string, error) {
+	idle, err := hasIdleAgent(c, repoRoot, agentType)
+	if err != nil || idle {
+		return "", err
+	}
+
+	spawner := &syncSpawner{c: c, repoRoot: repoRoot}
+	agentID, err := spawner.spawn(false, true, agentType)
+	if err != nil {
+		return "", fmt.Errorf("spawn agent: %w", err)
+	}
+	return agentID, nil
+}
+
+// hasIdleAgent reports whether an agent of agentType ("" = any) in
+// repoRoot is free for a task. A tmux agent's status goes back to idle as
+// soon as a task's prompt is sent, so agents with an active task are
+// working whatever their status says.
+func hasIdleAgent(c *client.Client, repoRoot, agentType string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	agents, err := c.ListSpawnedAgents(ctx, repoRoot)
+	if err != nil {
+		return false, fmt.Errorf("list agents: %w", err)
+	}
+	working := make(map[string]bool)
+	for _, status := range []mapv1.TaskStatus{mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS, mapv1.TaskStatus_TASK_STATUS_WAITING_INPUT} {
+		tasks, err := c.ListTasksByStatus(ctx, status)
+		if err != nil {
+			return false, fmt.Errorf("list tasks: %w", err)
+		}
+		for _, task := range tasks {
+			working[task.AssignedTo] = true
+		}
+	}
+
+	for _, agent := range agents {
+		if agent.Status == "idle" && !working[agent.AgentId] && (agentType == "" || agent.AgentType == agentType) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// followTaskToCompletion streams the output of the agent working on a task
+// to stdout until the task finishes, then returns its exit status. If the
+// task moves to another agent, that agent's output is followed instead.
+// Ctrl+C cancels the task.
+func followTaskToCompletion(c *client.Client, taskID string) error {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	var stream *taskOutputStream
+	var streamed atomic.Bool
+	defer func() { stream.stop() }()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		task, err := c.GetTask(ctx, taskID)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("get task: %w", err)
+		}
+
+		if isTerminalTaskStatus(task.Status) {
+			stream.stop()
+			// Headless agents have no pane to follow, so show the result
+			if !streamed.Load() && task.Result != "" {
+				fmt.Println(task.Result)
+			}
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "task %s %s\n", task.TaskId, taskStatusString(task.Status))
+			}
+			return taskExitError(task)
+		}
+
+		if task.AssignedTo != "" && (stream == nil || stream.agentID != task.AssignedTo) {
+			stream.stop()
+			if !isQuiet() {
+				fmt.Fprintf(os.Stderr, "task assigned to %s\n", task.AssignedTo)
+			}
+			stream = startTaskOutputStream(c, task.AssignedTo, &streamed)
+		}
+
+		select {
+		case <-sigCh:
+			stream.stop()
+			return cancelBlockingTask(c, taskID)
+		case <-ticker.C:
+		}
+	}
+}
+
+// cancelBlockingTask cancels a task after Ctrl+C and returns its exit status
+func cancelBlockingTask(c *client.Client, taskID string) error {
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "\ncancelling task %s...\n", taskID)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.CancelTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("cancel task: %w", err)
+	}
+	return taskExitError(task)
+}
+
+// taskExitError returns nil for a completed task, and otherwise an error
+// carrying the exit status for how the task ended
+func taskExitError(task *mapv1.Task) error {
+	switch task.Status {
+	case mapv1.TaskStatus_TASK_STATUS_COMPLETED:
+		return nil
+	case mapv1.TaskStatus_TASK_STATUS_FAILED:
+		err := fmt.Errorf("task %s failed", task.TaskId)
+		if task.Error != "" {
+			err = fmt.Errorf("task %s failed: %s", task.TaskId, task.Error)
+		}
+		return &exitCodeError{code: exitTaskFailed, err: err}
+	default:
+		return &exitCodeError{code: exitTaskCancelled, err: fmt.Errorf("task %s %s", task.TaskId, taskStatusString(task.Status))}
+	}
+}
+
+// taskOutputStream prints an agent's pane output to stdout in the background
+type taskOutputStream struct {
+	agentID string
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// startTaskOutputStream follows agentID's pane, setting streamed once any
+// output has been printed
+func startTaskOutputStream(c *client.Client, agentID string, streamed *atomic.Bool) *taskOutputStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &taskOutputStream{agentID: agentID, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(s.done)
+		stream, err := c.StreamAgentOutput(ctx, agentID, 0)
+		if err != nil {
+			return
+		}
+		for {
+			line, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil && !errors.Is(err, context.Canceled) && !isQuiet() {
+					fmt.Fprintf(os.Stderr, "not following agent %s: %s\n", agentID, errorMessage(err))
+				}
+				return
+			}
+			streamed.Store(true)
+			fmt.Println(line.Line)
+		}
+	}()
+	return s
+}
+
+// stop stops the stream and waits for it to finish printing. A nil stream
+// is ignored.
+func (s *taskOutputStream) stop() {
+	if s == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestTaskExitError(t *testing.T) {
+	tests := []struct {
+		name     string
+		task     *mapv1.Task
+		wantCode int
+		wantMsg  string
+	}{
+		{"completed", &mapv1.Task{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_COMPLETED}, 0, ""},
+		{"failed", &mapv1.Task{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_FAILED, Error: "tests broke"}, exitTaskFailed, "task t1 failed: tests broke"},
+		{"failed without error", &mapv1.Task{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_FAILED}, exitTaskFailed, "task t1 failed"},
+		{"cancelled", &mapv1.Task{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_CANCELLED}, exitTaskCancelled, "task t1 cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := taskExitError(tt.task)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Errorf("taskExitError = %v, want nil", err)
+				}
+				return
+			}
+			var exitErr *exitCodeError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("taskExitError = %v, want an exit code error", err)
+			}
+			if exitErr.code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", exitErr.code, tt.wantCode)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("message = %q, want %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestHasIdleAgent(t *testing.T) {
+	// A stand-in CLI so a headless agent can be spawned
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "claude"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	startTestDaemon(t)
+	repoRoot := chdirTestRepo(t)
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = c.Close() }()
+	ctx := context.Background()
+
+	resp, err := c.SpawnAgent(ctx, &mapv1.SpawnAgentRequest{OutputOnly: true, AgentType: "claude", WorkingDirectory: repoRoot})
+	if err != nil {
+		t.Fatalf("SpawnAgent failed: %v", err)
+	}
+	agentID := resp.Agents[0].AgentId
+
+	if idle, err := hasIdleAgent(c, repoRoot, ""); err != nil || !idle {
+		t.Errorf("hasIdleAgent = %v, %v; want true for an idle agent", idle, err)
+	}
+
+	// Its status still says idle, as a tmux agent's does while it works,
+	// but it has a task in progress
+	task := &mapv1.Task{TaskId: "task-1", Description: "fix the build", Status: mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS, AssignedTo: agentID}
+	if _, err := c.ImportTasks(ctx, []*mapv1.Task{task}, false); err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if idle, err := hasIdleAgent(c, repoRoot, ""); err != nil || idle {
+		t.Errorf("hasIdleAgent = %v, %v; want false for an agent with a task in progress", idle, err)
+	}
+}
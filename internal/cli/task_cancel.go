@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// cancellableStatuses are the task statuses 'task cancel --all-pending' can
+// clear, by the name --status takes
+var cancellableStatuses = map[string]mapv1.TaskStatus{
+	"pending":     mapv1.TaskStatus_TASK_STATUS_PENDING,
+	"in_progress": mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS,
+}
+
+// cancelAllInStatus cancels every task in a status, after listing them and
+// asking for confirmation unless yes is set
+func cancelAllInStatus(statusName string, yes bool) error {
+	status, ok := cancellableStatuses[statusName]
+	if !ok {
+		return fmt.Errorf("--status must be pending or in_progress, got %q", statusName)
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	tasks, err := c.ListTasksByStatus(ctx, status)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("list tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Printf("no %s tasks\n", statusName)
+		return nil
+	}
+
+	if !yes {
+		for _, task := range tasks {
+			fmt.Printf("  %s  %s\n", task.TaskId, truncate(strings.Join(strings.Fields(task.Description), " "), 60))
+		}
+		if !confirm(fmt.Sprintf("Cancel these %d %s task(s)?", len(tasks), statusName)) {
+			fmt.Println("aborted")
+			return nil
+		}
+	}
+
+	var cancelled, failed int
+	for _, task := range tasks {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := c.CancelTask(ctx, task.TaskId)
+		cancel()
+		if err != nil {
+			// The task may have moved on since it was listed
+			fmt.Printf("could not cancel %s: %v\n", task.TaskId, err)
+			failed++
+			continue
+		}
+		cancelled++
+	}
+
+	fmt.Printf("cancelled %d task(s)", cancelled)
+	if failed > 0 {
+		fmt.Printf(", %d could not be cancelled", failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(response)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConfirm(t *testing.T) {
+	tests := map[string]bool{
+		"y\n":     true,
+		"YES\n":   true,
+		" yes \n": true,
+		"n\n":     false,
+		"\n":      false,
+		"":        false,
+		"maybe\n": false,
+	}
+
+	for input, want := range tests {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.WriteString(input)
+		_ = w.Close()
+
+		stdin := os.Stdin
+		os.Stdin = r
+		got := confirm("Proceed?")
+		os.Stdin = stdin
+		_ = r.Close()
+
+		if got != want {
+			t.Errorf("confirm with input %q = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestCancelAllInStatus_InvalidStatus(t *testing.T) {
+	if err := cancelAllInStatus("completed", true); err == nil {
+		t.Error("expected error for a status that can't be cancelled")
+	}
+}
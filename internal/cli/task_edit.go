@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// taskEditHelp is appended to the description template opened by
+// 'task submit --edit'
+const taskEditHelp = `
+# Describe the task for the agent above. Lines starting with '#' are
+# ignored, and an empty or unchanged description aborts the submission.
+`
+
+// composeDescription opens the user's editor on a description template, as
+// 'git commit' does, and returns the saved description. initial pre-fills
+// the template, and scope paths are listed for reference.
+func composeDescription(initial string, paths []string) (string, error) {
+	f, err := os.CreateTemp("", "map-task-*.md")
+	if err != nil {
+		return "", fmt.Errorf("create description file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(descriptionTemplate(initial, paths)); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write description file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write description file: %w", err)
+	}
+
+	editor := editorCommand(os.Getenv, "vi")
+	editorCmd := exec.Command(editor[0], append(editor[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("run editor %s: %w", editor[0], err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read description file: %w", err)
+	}
+
+	description := stripDescriptionComments(string(data))
+	if description == "" {
+		return "", fmt.Errorf("aborting submission due to empty task description")
+	}
+	if description == strings.TrimSpace(initial) {
+		return "", fmt.Errorf("aborting submission: task description unchanged")
+	}
+	return description, nil
+}
+
+// descriptionTemplate is the file the editor opens: the initial description
+// followed by commented help and scope paths
+func descriptionTemplate(initial string, paths []string) string {
+	var b strings.Builder
+	if initial != "" {
+		b.WriteString(strings.TrimSpace(initial))
+		b.WriteString("\n")
+	}
+	b.WriteString(taskEditHelp)
+	if len(paths) > 0 {
+		b.WriteString("#\n# Scope paths:\n")
+		for _, p := range paths {
+			fmt.Fprintf(&b, "#   %s\n", p)
+		}
+	}
+	return b.String()
+}
+
+// stripDescriptionComments drops '#' comment lines from an edited
+// description and trims surrounding blank space
+func stripDescriptionComments(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDescriptionTemplate(t *testing.T) {
+	tmpl := descriptionTemplate("Fix the login bug", []string{"/repo/internal/auth"})
+	if !strings.HasPrefix(tmpl, "Fix the login bug\n") {
+		t.Errorf("template does not start with the initial description: %q", tmpl)
+	}
+	if !strings.Contains(tmpl, "#   /repo/internal/auth\n") {
+		t.Errorf("template does not list the scope path: %q", tmpl)
+	}
+	if got := stripDescriptionComments(tmpl); got != "Fix the login bug" {
+		t.Errorf("unedited template strips to %q, want the initial description", got)
+	}
+}
+
+func TestStripDescriptionComments(t *testing.T) {
+	content := "\n# heading comment\nFix the flaky test  \n\nin auth_test.go\n# trailing comment\n\n"
+	if got, want := stripDescriptionComments(content), "Fix the flaky test\n\nin auth_test.go"; got != want {
+		t.Errorf("stripDescriptionComments = %q, want %q", got, want)
+	}
+}
+
+func TestComposeDescription(t *testing.T) {
+	// A fake editor that appends a line to the file it is given
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'and add a regression test' >> \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	got, err := composeDescription("Fix the login bug", nil)
+	if err != nil {
+		t.Fatalf("composeDescription: %v", err)
+	}
+	if want := "Fix the login bug\n\nand add a regression test"; got != want {
+		t.Errorf("composeDescription = %q, want %q", got, want)
+	}
+
+	// An editor that saves nothing new aborts
+	t.Setenv("EDITOR", "true")
+	if _, err := composeDescription("Fix the login bug", nil); err == nil {
+		t.Error("composeDescription with an unchanged description succeeded, want an error")
+	}
+	if _, err := composeDescription("", nil); err == nil {
+		t.Error("composeDescription with an empty description succeeded, want an error")
+	}
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var taskExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export task history as JSON",
+	Long: `Write every task the daemon knows about, from all repositories and
+including archived ones, as JSON:
+description, scope, status, assignee, result, timestamps, GitHub source, and
+run. Load the file into another daemon with 'map task import', e.g. when
+moving to a new machine, or keep it as a backup.
+
+Attempt history and events are not exported.
+
+Examples:
+  map task export -o tasks.json
+  map task export > backup.json`,
+	Args: cobra.NoArgs,
+	RunE: runTaskExport,
+}
+
+var taskImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks exported by 'map task export'",
+	Long: `Recreate tasks from a 'map task export' file, keeping their IDs, timestamps,
+status, and GitHub source. Use - to read from stdin.
+
+Tasks whose IDs already exist are skipped, so importing the same file twice
+is harmless; --overwrite replaces them instead. Imported pending tasks join
+the queue. Tasks that were in progress keep their status and assignee, but
+the agents they name are not running here; cancel or retry them with
+'map task cancel' or 'map task retry' once they are imported.
+
+Examples:
+  map task import tasks.json
+  ssh old-host map task export | map task import -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskImport,
+}
+
+var (
+	taskExportOutput  string
+	taskImportReplace bool
+)
+
+func init() {
+	taskExportCmd.Flags().StringVarP(&taskExportOutput, "output", "o", "", "write to this file instead of stdout")
+	taskImportCmd.Flags().BoolVar(&taskImportReplace, "overwrite", false, "replace tasks whose IDs already exist")
+
+	taskCmd.AddCommand(taskExportCmd)
+	taskCmd.AddCommand(taskImportCmd)
+}
+
+// taskExportVersion is the format version written by map task export
+const taskExportVersion = 1
+
+// taskExportFile is the JSON document written by map task export. Tasks are
+// in protobuf JSON form so fields added later round-trip.
+type taskExportFile struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Tasks      []json.RawMessage `json:"tasks"`
+}
+
+func runTaskExport(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tasks, err := c.ExportTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("export tasks: %w", err)
+	}
+
+	data, err := encodeTaskExport(tasks, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if taskExportOutput == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(taskExportOutput, data, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", taskExportOutput, err)
+	}
+	if !isQuiet() {
+		fmt.Fprintf(os.Stderr, "exported %d task(s) to %s\n", len(tasks), taskExportOutput)
+	}
+	return nil
+}
+
+// encodeTaskExport renders tasks, oldest first, as an export file
+func encodeTaskExport(tasks []*mapv1.Task, now time.Time) ([]byte, error) {
+	export := taskExportFile{
+		Version:    taskExportVersion,
+		ExportedAt: now.UTC(),
+		Tasks:      make([]json.RawMessage, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		raw, err := protojson.Marshal(task)
+		if err != nil {
+			return nil, fmt.Errorf("encode task %s: %w", task.TaskId, err)
+		}
+		export.Tasks = append(export.Tasks, raw)
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode export: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// decodeTaskExport parses an export file
+func decodeTaskExport(data []byte) ([]*mapv1.Task, error) {
+	var export taskExportFile
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("not a task export: %w", err)
+	}
+	if export.Version != taskExportVersion {
+		return nil, fmt.Errorf("unsupported task export version %d (want %d)", export.Version, taskExportVersion)
+	}
+
+	tasks := make([]*mapv1.Task, 0, len(export.Tasks))
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	for i, raw := range export.Tasks {
+		task := &mapv1.Task{}
+		if err := opts.Unmarshal(raw, task); err != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func runTaskImport(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+
+	tasks, err := decodeTaskExport(data)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := c.ImportTasks(ctx, tasks, taskImportReplace)
+	if err != nil {
+		return fmt.Errorf("import tasks: %w", err)
+	}
+
+	if isQuiet() {
+		return nil
+	}
+	fmt.Printf("imported %d task(s)\n", resp.Imported)
+	if n := len(resp.SkippedIds); n > 0 {
+		fmt.Printf("skipped %d task(s) that already exist (use --overwrite to replace them)\n", n)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskExportRoundTrip(t *testing.T) {
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []*mapv1.Task{
+		{
+			TaskId:      "task-1",
+			Description: "Fix the login bug",
+			Status:      mapv1.TaskStatus_TASK_STATUS_COMPLETED,
+			CreatedAt:   timestamppb.New(created),
+			RepoRoot:    "/repo",
+		},
+		{
+			TaskId:       "task-2",
+			Description:  "Update the docs",
+			Status:       mapv1.TaskStatus_TASK_STATUS_PENDING,
+			CreatedAt:    timestamppb.New(created.Add(time.Minute)),
+			GithubSource: &mapv1.GitHubSource{Owner: "acme", Repo: "web", IssueNumber: 7},
+		},
+	}
+
+	data, err := encodeTaskExport(tasks, created)
+	if err != nil {
+		t.Fatalf("encodeTaskExport: %v", err)
+	}
+	got, err := decodeTaskExport(data)
+	if err != nil {
+		t.Fatalf("decodeTaskExport: %v", err)
+	}
+
+	if len(got) != 2 || got[0].TaskId != "task-1" || got[1].TaskId != "task-2" {
+		t.Fatalf("decoded %v, want task-1 then task-2", got)
+	}
+	if !proto.Equal(got[0], tasks[0]) || !proto.Equal(got[1], tasks[1]) {
+		t.Errorf("tasks changed in the round trip:\n%v\n%v", got[0], got[1])
+	}
+}
+
+func TestDecodeTaskExport_Invalid(t *testing.T) {
+	for _, data := range []string{
+		`not json`,
+		`{"version": 99, "tasks": []}`,
+		`{"version": 1, "tasks": ["task-1"]}`,
+	} {
+		if _, err := decodeTaskExport([]byte(data)); err == nil {
+			t.Errorf("decodeTaskExport(%s) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestTaskExportImport_PastMessageLimit(t *testing.T) {
+	ctx := context.Background()
+	description := strings.Repeat("x", 200*1024)
+	const count = 30 // about 6 MiB, past gRPC's 4 MiB message limit
+
+	startTestDaemon(t)
+	src, err := client.New(getSocketPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = src.Close() }()
+	for i := 0; i < count; i++ {
+		if _, err := src.SubmitTask(ctx, &mapv1.SubmitTaskRequest{Description: description, RepoRoot: "/repo"}); err != nil {
+			t.Fatalf("SubmitTask failed: %v", err)
+		}
+	}
+
+	tasks, err := src.ExportTasks(ctx)
+	if err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	if len(tasks) != count {
+		t.Fatalf("exported %d tasks, want %d", len(tasks), count)
+	}
+
+	startTestDaemon(t)
+	dst, err := client.New(getSocketPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = dst.Close() }()
+	resp, err := dst.ImportTasks(ctx, tasks, false)
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if resp.Imported != count {
+		t.Errorf("imported %d tasks, want %d", resp.Imported, count)
+	}
+}
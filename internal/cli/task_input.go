@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
 )
 
@@ -91,6 +92,12 @@ func runTaskMyTask(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	printTaskSummary(task)
+	return nil
+}
+
+// printTaskSummary prints the details an agent needs about its task
+func printTaskSummary(task *mapv1.Task) {
 	fmt.Printf("Task ID:     %s\n", task.TaskId)
 	fmt.Printf("Status:      %s\n", taskStatusString(task.Status))
 	fmt.Printf("Description: %s\n", task.Description)
@@ -106,6 +113,4 @@ func runTaskMyTask(cmd *cobra.Command, args []string) error {
 	if task.WaitingInputQuestion != "" {
 		fmt.Printf("\nWaiting for input:\n%s\n", task.WaitingInputQuestion)
 	}
-
-	return nil
 }
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var taskLogCmd = &cobra.Command{
+	Use:   "log <task-id>",
+	Short: "Show a task's lifecycle timeline",
+	Long: `Show the recorded lifecycle events for a task, oldest first: when it was
+created, started, waited for input, received input, and finished.
+
+The timeline is built from the daemon's event log, so it covers events
+recorded since event persistence was enabled.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskLog,
+}
+
+func init() {
+	taskCmd.AddCommand(taskLogCmd)
+}
+
+func runTaskLog(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("get task: %w", err)
+	}
+
+	events, err := c.TaskEvents(ctx, task.TaskId)
+	if err != nil {
+		return fmt.Errorf("list task events: %w", err)
+	}
+
+	fmt.Printf("Task %s (%s): %s\n\n", task.TaskId, taskStatusString(task.Status), truncate(task.Description, 60))
+
+	if len(events) == 0 {
+		fmt.Println("no events recorded for this task")
+		return nil
+	}
+
+	// Events arrive most recent first; print a chronological timeline
+	start := events[len(events)-1].Timestamp.AsTime()
+	for i := len(events) - 1; i >= 0; i-- {
+		fmt.Println(formatTaskLogEntry(events[i], start))
+	}
+
+	return nil
+}
+
+// formatTaskLogEntry renders one timeline line: timestamp, offset from the
+// first event, what happened, and the agent involved
+func formatTaskLogEntry(event *mapv1.Event, start time.Time) string {
+	ts := event.Timestamp.AsTime()
+	offset := "+" + ts.Sub(start).Round(time.Second).String()
+
+	what := strings.ToLower(strings.TrimPrefix(event.Type.String(), "EVENT_TYPE_TASK_"))
+	what = strings.ReplaceAll(what, "_", " ")
+
+	line := fmt.Sprintf("%s  %-8s  %-14s", ts.Local().Format("2006-01-02 15:04:05"), offset, what)
+	if agent := event.GetTask().GetAgentId(); agent != "" {
+		line += "  " + agent
+	}
+	return strings.TrimRight(line, " ")
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var taskMoveCmd = &cobra.Command{
+	Use:   "move <task-id> <column>",
+	Short: "Move a task's GitHub project item to another status column",
+	Long: `Move the GitHub Project item linked to a task to a different status column.
+
+The task must have originated from a GitHub issue (via 'map task sync gh-project').
+The project is selected by name with --project or by number with --project-number,
+the same way 'map task sync gh-project' selects it.
+
+Examples:
+  map task move <task-id> Done --project "Sprint Board"
+  map task move <task-id> "In Review" --project-number 7 --owner myorg`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTaskMove,
+}
+
+var (
+	moveProject    string
+	moveProjectNum int
+	moveOwner      string
+)
+
+func init() {
+	taskMoveCmd.Flags().StringVar(&moveProject, "project", "", "GitHub project name")
+	taskMoveCmd.Flags().IntVar(&moveProjectNum, "project-number", 0, "GitHub project number (with --owner)")
+	taskMoveCmd.Flags().StringVar(&moveOwner, "owner", "", "GitHub project owner (user or org); if empty, searches projects linked to current repo")
+	taskMoveCmd.ValidArgsFunction = completeTaskIDs
+
+	taskCmd.AddCommand(taskMoveCmd)
+}
+
+func runTaskMove(cmd *cobra.Command, args []string) error {
+	taskID, column := args[0], args[1]
+
+	if moveProject == "" && moveProjectNum <= 0 {
+		return fmt.Errorf("specify the project with --project or --project-number")
+	}
+
+	if err := checkGHCLI(); err != nil {
+		return err
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("get task: %w", err)
+	}
+	src := task.GithubSource
+	if src == nil {
+		return fmt.Errorf("task %s has no GitHub source", taskID)
+	}
+
+	var project *ghProject
+	if moveProjectNum > 0 {
+		project, err = findProjectByNumber(moveProjectNum, moveOwner)
+	} else {
+		project, err = findProject(moveProject, moveOwner)
+	}
+	if err != nil {
+		return err
+	}
+
+	statusField, err := getStatusField(project.Number, project.Owner)
+	if err != nil {
+		return err
+	}
+
+	var optionID string
+	var availableOptions []string
+	for _, opt := range statusField.Options {
+		availableOptions = append(availableOptions, opt.Name)
+		if strings.EqualFold(opt.Name, column) {
+			optionID = opt.ID
+			column = opt.Name
+		}
+	}
+	if optionID == "" {
+		return fmt.Errorf("status column %q not found. Available options: %s", column, strings.Join(availableOptions, ", "))
+	}
+
+	items, err := getProjectItems(project.Number, project.Owner)
+	if err != nil {
+		return err
+	}
+
+	item := findItemForIssue(items, src.Owner, src.Repo, int(src.IssueNumber))
+	if item == nil {
+		return fmt.Errorf("issue %s/%s#%d not found in project %q", src.Owner, src.Repo, src.IssueNumber, project.Title)
+	}
+
+	if item.Status == column {
+		fmt.Printf("%s/%s#%d is already in %q\n", src.Owner, src.Repo, src.IssueNumber, column)
+		return nil
+	}
+
+	if err := updateItemStatus(project.ID, item.ID, statusField.ID, optionID); err != nil {
+		return fmt.Errorf("update item status: %w", err)
+	}
+
+	fmt.Printf("moved %s/%s#%d from %q to %q\n", src.Owner, src.Repo, src.IssueNumber, item.Status, column)
+	return nil
+}
+
+// findItemForIssue returns the project item for the given issue, or nil
+func findItemForIssue(items []ghItem, owner, repo string, number int) *ghItem {
+	for i := range items {
+		item := &items[i]
+		if item.Content.Type != "Issue" || item.Content.Number != number {
+			continue
+		}
+		itemOwner, itemRepo := parseGitHubURL(item.Content.URL)
+		if strings.EqualFold(itemOwner, owner) && strings.EqualFold(itemRepo, repo) {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var taskReopenCmd = &cobra.Command{
+	Use:   "reopen <task-id>",
+	Short: "Reopen a completed task that needs more work",
+	Long: `Move a completed task back into work, e.g. when the GitHub poller marked
+it completed because its issue was closed but follow-up is needed.
+
+If the agent that completed the task is still running and not on another
+task, the task goes back to in_progress on that agent, which still has its
+context; tell it what else is needed with 'map agent watch', or re-send the
+task with 'map task nudge'. Otherwise the task goes back to pending and is
+picked up like a retried task, preferring the same agent. Either way the
+completed attempt is kept in 'map task attempts' and the new work is a new
+attempt.
+
+Cancelled and failed tasks can't be reopened; use 'map task retry'.
+
+A task synced from a GitHub issue is marked completed again while the issue
+is closed, so reopen the issue too.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskReopen,
+}
+
+func init() {
+	taskCmd.AddCommand(taskReopenCmd)
+}
+
+func runTaskReopen(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := c.ReopenTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("reopen task: %w", err)
+	}
+
+	if task.Status == mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS {
+		fmt.Printf("task reopened: %s (in_progress on %s)\n", task.TaskId, task.AssignedTo)
+		fmt.Printf("tell the agent what's needed with 'map agent watch %s'\n", task.AssignedTo)
+	} else {
+		fmt.Printf("task reopened: %s (%s)\n", task.TaskId, taskStatusString(task.Status))
+	}
+	if gh := task.GithubSource; gh != nil {
+		fmt.Printf("note: reopen %s/%s#%d too, or the task will be marked completed again while it is closed\n",
+			gh.Owner, gh.Repo, gh.IssueNumber)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var taskRetryCmd = &cobra.Command{
+	Use:   "retry [task-id]",
+	Short: "Re-queue a failed task",
+	Long: `Re-queue a failed or cancelled task so an agent picks it up again.
+
+Use --all-failed to re-queue every failed task in the current repository,
+optionally only those that failed within --since. Tasks that have already
+been retried task.max-retries times are skipped so permanently broken tasks
+don't loop forever.
+
+Examples:
+  map task retry 3f2a9c1e-...
+  map task retry --all-failed
+  map task retry --all-failed --since 1h`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTaskRetry,
+}
+
+var (
+	retryAllFailed bool
+	retrySince     string
+)
+
+func init() {
+	taskRetryCmd.Flags().BoolVar(&retryAllFailed, "all-failed", false, "retry every failed task in the current repo")
+	taskRetryCmd.Flags().StringVar(&retrySince, "since", "", "with --all-failed, only retry tasks that failed within a duration (e.g. 2h) or since an RFC3339 time")
+
+	taskCmd.AddCommand(taskRetryCmd)
+}
+
+func runTaskRetry(cmd *cobra.Command, args []string) error {
+	if retryAllFailed == (len(args) == 1) {
+		return errors.New("specify either a task ID or --all-failed")
+	}
+	if retrySince != "" && !retryAllFailed {
+		return errors.New("--since requires --all-failed")
+	}
+
+	since, err := parseSince(retrySince, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if !retryAllFailed {
+		task, err := c.RetryTask(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("retry task: %w", err)
+		}
+		fmt.Printf("task re-queued: %s (retry %d)\n", task.TaskId, task.RetryCount)
+		return nil
+	}
+
+	resp, err := c.RetryFailedTasks(ctx, getRepoRoot(), since)
+	if err != nil {
+		return fmt.Errorf("retry failed tasks: %w", err)
+	}
+
+	for _, task := range resp.Retried {
+		fmt.Printf("re-queued %s (retry %d): %s\n", task.TaskId, task.RetryCount, truncate(task.Description, 50))
+	}
+	for _, task := range resp.Skipped {
+		fmt.Printf("skipped %s: retry limit reached after %d retries\n", task.TaskId, task.RetryCount)
+	}
+
+	fmt.Printf("retried %d failed task(s)", len(resp.Retried))
+	if len(resp.Skipped) > 0 {
+		fmt.Printf(", skipped %d at the retry limit", len(resp.Skipped))
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+const scheduleHelp = `The cron expression has the standard five fields: minute, hour, day of
+month, month, and day of week, e.g. "0 9 * * 1" for 9am every Monday. Fields
+accept *, lists (1,15), ranges (1-5), steps (*/15), and month and day names
+(jan, mon). @hourly, @daily, @weekly, @monthly, and @yearly work too.
+
+Schedules run in the daemon's local time zone. To use another zone, start
+mapd with TZ set, e.g. 'TZ=UTC map up'. Runs missed while the daemon was
+down are not replayed; a schedule that came due fires once when the daemon
+starts again.
+
+Each run submits an ordinary task. All tasks from a schedule share a run
+named after the schedule, so 'map run show <schedule-id>' lists its history.`
+
+var taskScheduleCmd = &cobra.Command{
+	Use:     "schedule <cron> <description>",
+	Aliases: []string{"schedules"},
+	Short:   "Create and manage recurring tasks",
+	Long: `Create a recurring task that is submitted each time a cron schedule fires.
+
+` + scheduleHelp + `
+
+Examples:
+  map task schedule "0 9 * * 1" "Update dependencies and open a PR"
+  map task schedule @daily -p docs/ "Fix broken links in the docs"
+  map task schedules ls
+  map task schedules rm sched-1a2b3c4d`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTaskSchedule,
+}
+
+var taskScheduleListCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List recurring tasks",
+	Args:    cobra.NoArgs,
+	RunE:    runTaskScheduleList,
+}
+
+var taskScheduleRemoveCmd = &cobra.Command{
+	Use:     "rm <schedule-id>",
+	Aliases: []string{"remove", "delete"},
+	Short:   "Remove a recurring task",
+	Long:    `Remove a recurring task. Tasks it has already submitted are left alone.`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    runTaskScheduleRemove,
+}
+
+var scheduleScopePaths []string
+
+func init() {
+	taskScheduleCmd.Flags().StringSliceVarP(&scheduleScopePaths, "path", "p", nil, "scope paths for each submitted task")
+
+	taskScheduleCmd.AddCommand(taskScheduleListCmd)
+	taskScheduleCmd.AddCommand(taskScheduleRemoveCmd)
+	taskCmd.AddCommand(taskScheduleCmd)
+}
+
+func runTaskSchedule(cmd *cobra.Command, args []string) error {
+	return createSchedule(args[0], strings.Join(args[1:], " "), scheduleScopePaths)
+}
+
+// createSchedule asks the daemon to submit description each time cron fires
+func createSchedule(cron, description string, paths []string) error {
+	scopePaths, err := absPaths(paths)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sched, err := c.CreateScheduledTask(ctx, cron, description, scopePaths, getRepoRoot())
+	if err != nil {
+		return fmt.Errorf("create schedule: %w", err)
+	}
+
+	if isQuiet() {
+		fmt.Println(sched.ScheduleId)
+		return nil
+	}
+	fmt.Printf("schedule created: %s\n", sched.ScheduleId)
+	fmt.Printf("next run: %s\n", sched.NextRunAt.AsTime().Local().Format(time.RFC3339))
+	return nil
+}
+
+// absPaths resolves scope paths against the current directory
+func absPaths(paths []string) ([]string, error) {
+	resolved := make([]string, 0, len(paths))
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, fmt.Errorf("resolve path %s: %w", p, err)
+		}
+		resolved = append(resolved, abs)
+	}
+	return resolved, nil
+}
+
+func runTaskScheduleList(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	schedules, err := c.ListScheduledTasks(ctx)
+	if err != nil {
+		return fmt.Errorf("list schedules: %w", err)
+	}
+
+	if len(schedules) == 0 {
+		fmt.Println("no schedules")
+		return nil
+	}
+
+	fmt.Printf("%-14s %-16s %-20s %-36s %s\n", "SCHEDULE ID", "CRON", "NEXT RUN", "LAST TASK", "DESCRIPTION")
+	fmt.Println(strings.Repeat("-", 120))
+
+	for _, sched := range schedules {
+		next := "never"
+		if sched.NextRunAt != nil {
+			next = sched.NextRunAt.AsTime().Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-14s %-16s %-20s %-36s %s\n",
+			sched.ScheduleId,
+			truncate(sched.Cron, 16),
+			next,
+			valueOrDash(sched.LastTaskId),
+			truncate(strings.Join(strings.Fields(sched.Description), " "), 40),
+		)
+	}
+
+	return nil
+}
+
+func runTaskScheduleRemove(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := c.DeleteScheduledTask(ctx, args[0]); err != nil {
+		return fmt.Errorf("remove schedule: %w", err)
+	}
+	fmt.Printf("schedule removed: %s\n", args[0])
+	return nil
+}
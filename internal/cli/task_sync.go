@@ -4,12 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
 )
 
 // GitHub Project data structures for JSON parsing
@@ -87,6 +91,7 @@ type ghItem struct {
 	ID      string        `json:"id"`
 	Content ghItemContent `json:"content"`
 	Status  string        `json:"status"`
+	Labels  []string      `json:"labels"`
 }
 
 type ghItemList struct {
@@ -100,7 +105,7 @@ var taskSyncCmd = &cobra.Command{
 }
 
 var taskSyncGHProjectCmd = &cobra.Command{
-	Use:   "gh-project <project-name>",
+	Use:   "gh-project [project-name]",
 	Short: "Sync tasks from a GitHub Project",
 	Long: `Fetch issues from a GitHub Project's Todo column and create tasks for them.
 
@@ -113,17 +118,78 @@ This command:
 By default, searches for projects linked to the current repository, which includes
 projects owned by organizations. Use --owner to search a specific user/org instead.
 
+Use --project-number to select the project by number and skip the name search
+entirely. The number is resolved against --owner (default: @me).
+
+Use --label to only sync issues carrying a label (e.g. agent-ready). It narrows
+the --status-column selection rather than replacing it: an item must be in the
+source column and carry every --label given. --limit counts matching items only.
+
+Use --state-file (or sync.state-file in config) to record synced issues in a
+file, e.g. .map/synced-issues.json. Issues listed there are skipped, so syncing
+stays idempotent after a database reset, or on another machine if the file is
+committed. Relative paths are resolved against the repository root.
+--ignore-state syncs listed issues again and updates their entries.
+
+If the board has no column named by --status-column ("Todo" by default),
+the error suggests one: an option differing only in case, or else the
+board's leftmost column, which is usually its backlog. --auto-column syncs
+from the suggestion without asking.
+
+Use --status-mapping to sync several columns in one run, for boards with
+their own workflow. It names a JSON file mapping each source column to the
+column its items move to, and replaces --status-column and --target-column:
+
+  {"Ready": "Doing", "Review": "Verifying"}
+
+Every column in the mapping must exist on the project's Status field, or the
+sync stops before creating any tasks. --limit counts items across all source
+columns, taken in column name order.
+
+Use --split-checklists to break issues with task lists into smaller tasks:
+each unchecked "- [ ]" item in an issue's body becomes its own task, linked
+to the issue like any synced task, so agent questions are posted there. Each
+task's description quotes its item and asks the agent to reference the issue
+and the item in its PR. Issues without unchecked items sync as one task, and
+--limit still counts issues, not tasks.
+
+Issues carrying a label listed in sync.no-worktree-labels (e.g. question)
+become read-only tasks, as with 'map task submit --no-worktree'.
+
+Use --spawn N (alias --concurrency) to start working the issues straight away:
+as tasks are created, agents are spawned for the repository until N agents
+exist or there is one per task. Agents already running count toward N, and the
+daemon's agent.max-agents cap still applies; spawning stops once it is hit.
+
+Use --isolated (alias --create-worktree-per-task) to give every task its own
+agent instead: before each task is created, a new agent is spawned in its own
+worktree and the task is created preferring it, so N issues are worked by N
+agents in parallel without touching each other's files. With
+worktree.branch-per-agent set, each agent also works on its own branch. The
+agent type comes from routing rules, else agent.default-type. Spawning stops
+at the agent.max-agents cap; later tasks are still created and queued for
+whichever agents are free.
+
 Requires the 'gh' CLI to be installed and authenticated.`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.MaximumNArgs(1),
 	RunE: runTaskSyncGHProject,
 }
 
 var (
-	syncStatusColumn string
-	syncTargetColumn string
-	syncDryRun       bool
-	syncOwner        string
-	syncLimit        int
+	syncStatusColumn   string
+	syncTargetColumn   string
+	syncDryRun         bool
+	syncOwner          string
+	syncLimit          int
+	syncProjectNum     int
+	syncLabels         []string
+	syncStateFile      string
+	syncIgnoreState    bool
+	syncSpawn          int
+	syncStatusMapping  string
+	syncSplitChecklist bool
+	syncAutoColumn     bool
+	syncIsolated       bool
 )
 
 func init() {
@@ -132,20 +198,74 @@ func init() {
 	taskSyncGHProjectCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "preview without creating tasks or updating GitHub")
 	taskSyncGHProjectCmd.Flags().StringVar(&syncOwner, "owner", "", "GitHub project owner (user or org); if empty, searches projects linked to current repo")
 	taskSyncGHProjectCmd.Flags().IntVar(&syncLimit, "limit", 10, "maximum number of items to sync")
+	taskSyncGHProjectCmd.Flags().IntVar(&syncProjectNum, "project-number", 0, "select the project by number (with --owner) instead of by name")
+	taskSyncGHProjectCmd.Flags().StringSliceVar(&syncLabels, "label", nil, "only sync issues carrying this label (repeatable; all must match)")
+	taskSyncGHProjectCmd.Flags().StringVar(&syncStateFile, "state-file", "", "file recording synced issues, which are skipped (default: sync.state-file from config)")
+	taskSyncGHProjectCmd.Flags().BoolVar(&syncIgnoreState, "ignore-state", false, "sync issues even if the state file lists them as synced")
+	taskSyncGHProjectCmd.Flags().IntVar(&syncSpawn, "spawn", 0, "spawn agents as tasks are created, until this many exist for the repo")
+	taskSyncGHProjectCmd.Flags().StringVar(&syncStatusMapping, "status-mapping", "", "JSON file mapping source status columns to target columns, to sync several columns in one run")
+	taskSyncGHProjectCmd.Flags().BoolVar(&syncSplitChecklist, "split-checklists", false, "create a task per unchecked checklist item in an issue's body instead of one per issue")
+	taskSyncGHProjectCmd.Flags().BoolVar(&syncAutoColumn, "auto-column", false, "if the status column isn't on the board, sync from the suggested column instead")
+	taskSyncGHProjectCmd.Flags().BoolVar(&syncIsolated, "isolated", false, "spawn a worktree-isolated agent for each task created and have the task prefer it")
+	taskSyncGHProjectCmd.MarkFlagsMutuallyExclusive("isolated", "spawn")
+	taskSyncGHProjectCmd.MarkFlagsMutuallyExclusive("status-mapping", "status-column")
+	taskSyncGHProjectCmd.MarkFlagsMutuallyExclusive("status-mapping", "auto-column")
+	taskSyncGHProjectCmd.MarkFlagsMutuallyExclusive("status-mapping", "target-column")
+	taskSyncGHProjectCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		// --label-filter is accepted as an alias for --label,
+		// --concurrency for --spawn, and --create-worktree-per-task for
+		// --isolated
+		switch name {
+		case "label-filter":
+			name = "label"
+		case "concurrency":
+			name = "spawn"
+		case "create-worktree-per-task":
+			name = "isolated"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	taskSyncCmd.AddCommand(taskSyncGHProjectCmd)
 }
 
 func runTaskSyncGHProject(cmd *cobra.Command, args []string) error {
-	projectName := args[0]
+	var projectName string
+	if len(args) > 0 {
+		projectName = args[0]
+	}
+	if projectName == "" && syncProjectNum <= 0 {
+		return fmt.Errorf("specify a project name or --project-number")
+	}
+	if projectName != "" && syncProjectNum > 0 {
+		return fmt.Errorf("specify either a project name or --project-number, not both")
+	}
+	if syncSpawn < 0 {
+		return fmt.Errorf("--spawn must not be negative")
+	}
+
+	routes := []syncRoute{{Source: syncStatusColumn, Target: syncTargetColumn}}
+	if syncStatusMapping != "" {
+		var err error
+		routes, err = loadStatusMapping(syncStatusMapping)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Check if gh CLI is available
 	if err := checkGHCLI(); err != nil {
 		return err
 	}
 
-	// Find project by name
-	project, err := findProject(projectName, syncOwner)
+	// Find project by number or by name
+	var project *ghProject
+	var err error
+	if syncProjectNum > 0 {
+		project, err = findProjectByNumber(syncProjectNum, syncOwner)
+	} else {
+		project, err = findProject(projectName, syncOwner)
+	}
 	if err != nil {
 		return err
 	}
@@ -158,24 +278,27 @@ func runTaskSyncGHProject(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Find the source and target option IDs
-	var sourceOptionID, targetOptionID string
-	var availableOptions []string
-	for _, opt := range statusField.Options {
-		availableOptions = append(availableOptions, opt.Name)
-		if opt.Name == syncStatusColumn {
-			sourceOptionID = opt.ID
-		}
-		if opt.Name == syncTargetColumn {
-			targetOptionID = opt.ID
+	// Boards without the default "Todo" column get a suggested source
+	// column, used straight away with --auto-column
+	var hint string
+	if syncStatusMapping == "" {
+		requested := routes[0].Source
+		if column, reason := suggestSourceColumn(statusField, requested, routes[0].Target); column != "" {
+			if syncAutoColumn {
+				fmt.Printf("Status column %q not found; syncing from %q (%s)\n", requested, column, reason)
+				routes[0].Source = column
+			} else {
+				hint = fmt.Sprintf("Suggestion: %q (%s). Pass --status-column %q, or --auto-column to use it automatically", column, reason, column)
+			}
 		}
 	}
 
-	if sourceOptionID == "" {
-		return fmt.Errorf("status column %q not found. Available options: %s", syncStatusColumn, strings.Join(availableOptions, ", "))
-	}
-	if targetOptionID == "" {
-		return fmt.Errorf("target column %q not found. Available options: %s", syncTargetColumn, strings.Join(availableOptions, ", "))
+	// Check every source and target column exists before syncing anything
+	if err := resolveSyncRoutes(routes, statusField); err != nil {
+		if hint != "" {
+			return fmt.Errorf("%w\n%s", err, hint)
+		}
+		return err
 	}
 
 	// Fetch items from the project (use project's owner)
@@ -184,29 +307,66 @@ func runTaskSyncGHProject(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Filter items by status
-	var todoItems []ghItem
-	for _, item := range items {
-		if item.Status == syncStatusColumn && item.Content.Type == "Issue" {
-			todoItems = append(todoItems, item)
-			if len(todoItems) >= syncLimit {
-				break
+	// Skip issues the state file says were already synced
+	var state *syncState
+	stateFile := syncStateFile
+	if stateFile == "" {
+		stateFile = viper.GetString("sync.state-file")
+	}
+	if stateFile != "" {
+		state, err = loadSyncState(resolveSyncStatePath(stateFile, getRepoRoot()))
+		if err != nil {
+			return err
+		}
+		if !syncIgnoreState {
+			var skipped int
+			for _, route := range routes {
+				var n int
+				items, n = dropSyncedItems(items, route.Source, state)
+				skipped += n
+			}
+			if skipped > 0 {
+				fmt.Printf("Skipping %d issue(s) already recorded in %s (use --ignore-state to sync them again)\n", skipped, stateFile)
 			}
 		}
 	}
 
+	// Filter items by status and labels
+	todoItems := selectRoutedItems(items, routes, syncLabels, syncLimit)
+	columns := describeSourceColumns(routes)
+
+	labelNote := ""
+	if len(syncLabels) > 0 {
+		labelNote = fmt.Sprintf(" with label(s) %s", strings.Join(syncLabels, ", "))
+	}
+
 	if len(todoItems) == 0 {
-		fmt.Printf("No items found in %q column\n", syncStatusColumn)
+		fmt.Printf("No items found in %s%s\n", columns, labelNote)
 		return nil
 	}
 
-	fmt.Printf("Found %d item(s) in %q column\n", len(todoItems), syncStatusColumn)
+	fmt.Printf("Found %d item(s) in %s%s\n", len(todoItems), columns, labelNote)
 
 	if syncDryRun {
 		fmt.Println("\n[DRY RUN] Would create the following tasks:")
-		for _, item := range todoItems {
+		for _, routed := range todoItems {
+			item := routed.item
 			fmt.Printf("  - #%d: %s\n", item.Content.Number, item.Content.Title)
 			fmt.Printf("    URL: %s\n", item.Content.URL)
+			if syncSplitChecklist {
+				for _, step := range parseChecklist(item.Content.Body) {
+					fmt.Printf("    Task: %s\n", step)
+				}
+			}
+			if len(routes) > 1 {
+				fmt.Printf("    Move: %q -> %q\n", routed.route.Source, routed.route.Target)
+			}
+		}
+		if syncSpawn > 0 {
+			fmt.Printf("\n[DRY RUN] Would spawn up to %d agent(s) to work them\n", min(syncSpawn, len(todoItems)))
+		}
+		if syncIsolated {
+			fmt.Println("\n[DRY RUN] Would spawn a worktree-isolated agent for each task")
 		}
 		return nil
 	}
@@ -218,54 +378,269 @@ func runTaskSyncGHProject(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = c.Close() }()
 
+	repoRoot := getRepoRoot()
+	runID := newRunID()
+	noWorktreeLabels := viper.GetStringSlice("sync.no-worktree-labels")
+
+	// Label routing rules are applied here, where the issue's labels are
+	// known; the daemon applies scope and match rules itself
+	rules, err := routingRules()
+	if err != nil {
+		return err
+	}
+	routing, err := daemon.NewTaskRouting(rules)
+	if err != nil {
+		return err
+	}
+
+	var spawner *syncSpawner
+	if syncSpawn > 0 || syncIsolated {
+		spawner, err = newSyncSpawner(c, repoRoot, syncSpawn)
+		if err != nil {
+			return err
+		}
+		spawner.isolated = syncIsolated
+	}
+
 	// Process each item
-	var succeeded, failed int
-	for _, item := range todoItems {
+	var succeeded, failed, created int
+	for _, routed := range todoItems {
+		item := routed.item
 		fmt.Printf("\nProcessing #%d: %s\n", item.Content.Number, item.Content.Title)
 
-		// Build task description
-		description := buildTaskDescription(item)
-
 		// Extract GitHub metadata from issue URL
 		owner, repo := parseGitHubURL(item.Content.URL)
-
-		// Submit task with GitHub source tracking
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		repoRoot := getRepoRoot()
-		task, err := c.SubmitTaskWithGitHub(ctx, description, nil, owner, repo, int32(item.Content.Number), repoRoot)
-		cancel()
-
-		if err != nil {
-			fmt.Printf("  Error creating task: %v\n", err)
+		noWorktree := hasAnyLabel(item, noWorktreeLabels)
+
+		// Submit the item's task (or one per checklist item) with GitHub
+		// source tracking
+		var firstTaskID string
+		descriptions := syncTaskDescriptions(item, syncSplitChecklist)
+		itemFailures := 0
+		for _, description := range descriptions {
+			agentType := routing.AgentTypeFor(repoRoot, nil, description, item.Labels)
+			preferAgent := spawner.isolatedAgent(agentType)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			task, err := c.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
+				Description:       description,
+				GithubOwner:       owner,
+				GithubRepo:        repo,
+				GithubIssueNumber: int32(item.Content.Number),
+				RepoRoot:          repoRoot,
+				RunId:             runID,
+				PreferAgent:       preferAgent,
+				NoWorktree:        noWorktree,
+				AgentType:         agentType,
+			})
+			cancel()
+
+			if err != nil {
+				fmt.Printf("  Error creating task: %v\n", err)
+				spawner.discardIsolatedAgent(preferAgent)
+				itemFailures++
+				continue
+			}
+			fmt.Printf("  Created task: %s\n", task.TaskId)
+			if firstTaskID == "" {
+				firstTaskID = task.TaskId
+			}
+			created++
+		}
+		if firstTaskID == "" {
 			failed++
 			continue
 		}
 
-		fmt.Printf("  Created task: %s\n", task.TaskId)
+		if noWorktree {
+			fmt.Printf("  Read-only: no worktree needed\n")
+		}
 		if owner != "" && repo != "" {
 			fmt.Printf("  GitHub source: %s/%s#%d\n", owner, repo, item.Content.Number)
 		}
 
+		// Only an item whose tasks were all created moves on and is recorded
+		// as synced; one with failed checklist items stays put, so it is
+		// clear it isn't fully underway and the next sync picks it up again
+		if itemFailures > 0 {
+			fmt.Printf("  Warning: %d of %d checklist task(s) failed; leaving the issue in its column and unsynced\n", itemFailures, len(descriptions))
+			failed++
+			spawner.afterTask(created)
+			continue
+		}
+
+		if key, ok := ghItemKey(item); ok && state != nil {
+			if err := state.record(key, firstTaskID); err != nil {
+				fmt.Printf("  Warning: failed to update sync state: %v\n", err)
+			}
+		}
+
 		// Update item status on GitHub
-		if err := updateItemStatus(project.ID, item.ID, statusField.ID, targetOptionID); err != nil {
+		if err := updateItemStatus(project.ID, item.ID, statusField.ID, routed.route.targetOptionID); err != nil {
 			fmt.Printf("  Warning: failed to update GitHub status: %v\n", err)
 		} else {
-			fmt.Printf("  Moved to %q on GitHub\n", syncTargetColumn)
+			fmt.Printf("  Moved to %q on GitHub\n", routed.route.Target)
 		}
 
 		succeeded++
+		spawner.afterTask(created)
 	}
 
-	fmt.Printf("\nSync complete: %d succeeded, %d failed\n", succeeded, failed)
+	if spawner != nil {
+		fmt.Printf("\nSync complete: %d succeeded, %d failed, %d agent(s) spawned\n", succeeded, failed, len(spawner.spawned))
+	} else {
+		fmt.Printf("\nSync complete: %d succeeded, %d failed\n", succeeded, failed)
+	}
+	if created > succeeded {
+		fmt.Printf("Created %d task(s) from checklists\n", created)
+	}
+	if succeeded > 0 {
+		fmt.Printf("Track progress with: map run show %s\n", runID)
+	}
 	return nil
 }
 
-func checkGHCLI() error {
-	_, err := exec.LookPath("gh")
+// syncSpawner spawns agents for a repository as a sync creates tasks, so the
+// tasks start being worked without a separate `map agent create`
+type syncSpawner struct {
+	c        *client.Client
+	repoRoot string
+	limit    int
+	running  int // agents that existed before the sync started
+	spawned  []string
+	stopped  bool
+	// isolated spawns a worktree-isolated agent for every task rather than
+	// up to limit shared ones
+	isolated bool
+}
+
+// newSyncSpawner counts the repository's existing agents, which count toward
+// limit
+func newSyncSpawner(c *client.Client, repoRoot string, limit int) (*syncSpawner, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	agents, err := c.ListSpawnedAgents(ctx, repoRoot)
 	if err != nil {
-		return fmt.Errorf("gh CLI not found. Install it from https://cli.github.com/")
+		return nil, fmt.Errorf("list agents: %w", err)
 	}
-	return nil
+	return &syncSpawner{c: c, repoRoot: repoRoot, limit: limit, running: len(agents)}, nil
+}
+
+// agentsToSpawn returns how many more agents to spawn so that there are
+// limit agents, or one per task if there are fewer tasks than that
+func agentsToSpawn(limit, tasks, running int) int {
+	return max(min(limit, tasks)-running, 0)
+}
+
+// afterTask spawns the agents wanted now that tasks tasks have been created.
+// After a failed spawn, usually the daemon's agent cap, it stops trying.
+func (s *syncSpawner) afterTask(tasks int) {
+	if s == nil || s.stopped {
+		return
+	}
+
+	for range agentsToSpawn(s.limit, tasks, s.running+len(s.spawned)) {
+		agentID, err := s.spawn(false, false, "")
+		if err != nil {
+			fmt.Printf("  Not spawning more agents: %v\n", err)
+			s.stopped = true
+			return
+		}
+		s.spawned = append(s.spawned, agentID)
+		fmt.Printf("  Spawned agent: %s\n", agentID)
+	}
+}
+
+// isolatedAgent spawns a worktree-isolated agent of agentType for the next
+// task and returns its ID, or "" when s isn't isolating tasks or has stopped
+// spawning. The agent is held for the task, which must prefer it. After a
+// failed spawn, usually the daemon's agent cap, it stops trying.
+func (s *syncSpawner) isolatedAgent(agentType string) string {
+	if s == nil || !s.isolated || s.stopped {
+		return ""
+	}
+
+	agentID, err := s.spawn(true, true, agentType)
+	if err != nil {
+		fmt.Printf("  Not spawning more agents: %v\n", err)
+		s.stopped = true
+		return ""
+	}
+	s.spawned = append(s.spawned, agentID)
+	fmt.Printf("  Spawned isolated agent: %s\n", agentID)
+	return agentID
+}
+
+// discardIsolatedAgent removes an agent isolatedAgent spawned for a task
+// that then couldn't be created, as it is held for that task and would
+// otherwise sit idle
+func (s *syncSpawner) discardIsolatedAgent(agentID string) {
+	if s == nil || agentID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := s.c.KillAgent(ctx, agentID, false, false); err != nil {
+		fmt.Printf("  Warning: failed to remove agent %s: %v\n", agentID, err)
+		return
+	}
+	s.spawned = slices.DeleteFunc(s.spawned, func(id string) bool { return id == agentID })
+	fmt.Printf("  Removed agent: %s\n", agentID)
+}
+
+// spawn starts one agent with the configured agent defaults, in its own
+// worktree if isolated. A held agent takes no task until one preferring it
+// is submitted. An empty agentType means agent.default-type.
+func (s *syncSpawner) spawn(isolated, hold bool, agentType string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	useWorktree := isolated || viper.GetBool("agent.use-worktree")
+	if agentType == "" {
+		agentType = viper.GetString("agent.default-type")
+	}
+	req := &mapv1.SpawnAgentRequest{
+		Count:            1,
+		UseWorktree:      useWorktree,
+		AgentType:        agentType,
+		SkipPermissions:  viper.GetBool("agent.skip-permissions"),
+		WorkingDirectory: s.repoRoot,
+		HoldForTask:      hold,
+	}
+	if useWorktree && viper.GetBool("agent.git-config") {
+		req.GitUserName = viper.GetString("agent.git-user-name")
+		req.GitUserEmail = viper.GetString("agent.git-user-email")
+	}
+
+	resp, err := s.c.SpawnAgent(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Agents) == 0 {
+		return "", fmt.Errorf("no agent spawned")
+	}
+	return resp.Agents[0].AgentId, nil
+}
+
+// ghOutput runs gh and returns its stdout. Each call is limited to
+// github.timeout so a hung gh can't stall a sync indefinitely.
+func ghOutput(args ...string) ([]byte, error) {
+	return daemon.RunGH(context.Background(), ghTimeout(), args...)
+}
+
+// ghTimeout returns github.timeout, or the default if it isn't positive
+func ghTimeout() time.Duration {
+	if timeout := viper.GetDuration("github.timeout"); timeout > 0 {
+		return timeout
+	}
+	return daemon.DefaultGitHubTimeout
+}
+
+// checkGHCLI checks that gh is installed and logged in, so a command fails
+// up front with how to fix it rather than with gh's stderr mid-way
+func checkGHCLI() error {
+	return daemon.CheckGHAuth(context.Background(), ghTimeout())
 }
 
 func findProject(name, owner string) (*ghProject, error) {
@@ -285,7 +660,7 @@ func findProject(name, owner string) (*ghProject, error) {
 // findLinkedProject searches for a project by name among projects linked to the current repository
 func findLinkedProject(name string) (*ghProject, error) {
 	// Get current repo info
-	repoOut, err := exec.Command("gh", "repo", "view", "--json", "owner,name").Output()
+	repoOut, err := ghOutput("repo", "view", "--json", "owner,name")
 	if err != nil {
 		return nil, fmt.Errorf("not in a git repository or gh not authenticated")
 	}
@@ -312,12 +687,9 @@ func findLinkedProject(name string) (*ghProject, error) {
 		}
 	}`, repo.Owner.Login, repo.Name)
 
-	out, err := exec.Command("gh", "api", "graphql", "-f", "query="+query).Output()
+	out, err := ghOutput("api", "graphql", "-f", "query="+query)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh api graphql failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh api graphql failed: %w", err)
+		return nil, err
 	}
 
 	var resp ghLinkedProjectsResponse
@@ -347,12 +719,9 @@ func findLinkedProject(name string) (*ghProject, error) {
 // findProjectByOwner searches for a project by name using the gh project list command
 func findProjectByOwner(name, owner string) (*ghProject, error) {
 	args := []string{"project", "list", "--owner", owner, "--format", "json"}
-	out, err := exec.Command("gh", args...).Output()
+	out, err := ghOutput(args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh project list failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh project list failed: %w", err)
+		return nil, err
 	}
 
 	var list ghProjectListRaw
@@ -379,14 +748,40 @@ func findProjectByOwner(name, owner string) (*ghProject, error) {
 	return nil, fmt.Errorf("project %q not found. Available projects: %s", name, strings.Join(available, ", "))
 }
 
+// findProjectByNumber looks up a project directly by its number, skipping the name search
+func findProjectByNumber(number int, owner string) (*ghProject, error) {
+	if owner == "" {
+		owner = "@me"
+	}
+
+	args := []string{"project", "view", fmt.Sprintf("%d", number), "--owner", owner, "--format", "json"}
+	out, err := ghOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var p ghProjectRaw
+	if err := json.Unmarshal(out, &p); err != nil {
+		return nil, fmt.Errorf("parse project: %w", err)
+	}
+
+	project := &ghProject{
+		ID:     p.ID,
+		Number: p.Number,
+		Title:  p.Title,
+		Owner:  p.Owner.Login,
+	}
+	if project.Owner == "" {
+		project.Owner = owner
+	}
+	return project, nil
+}
+
 func getStatusField(projectNumber int, owner string) (*ghField, error) {
 	args := []string{"project", "field-list", fmt.Sprintf("%d", projectNumber), "--owner", owner, "--format", "json"}
-	out, err := exec.Command("gh", args...).Output()
+	out, err := ghOutput(args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh project field-list failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh project field-list failed: %w", err)
+		return nil, err
 	}
 
 	var list ghFieldList
@@ -405,20 +800,119 @@ func getStatusField(projectNumber int, owner string) (*ghField, error) {
 
 func getProjectItems(projectNumber int, owner string) ([]ghItem, error) {
 	args := []string{"project", "item-list", fmt.Sprintf("%d", projectNumber), "--owner", owner, "--format", "json", "--limit", "100"}
-	out, err := exec.Command("gh", args...).Output()
+	out, err := ghOutput(args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh project item-list failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh project item-list failed: %w", err)
+		return nil, err
 	}
 
+	items, skipped, err := parseProjectItems(out)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		fmt.Printf("Skipping %d project item(s) without issue content (draft issues or items you can't access)\n", skipped)
+	}
+	return items, nil
+}
+
+// parseProjectItems parses gh project item-list output, dropping items whose
+// content is null or has no URL or number. gh returns those for draft issues
+// and for issues the user can't see, and a task made from one would have no
+// issue to link to. It also returns how many items were dropped.
+func parseProjectItems(out []byte) ([]ghItem, int, error) {
 	var list ghItemList
 	if err := json.Unmarshal(out, &list); err != nil {
-		return nil, fmt.Errorf("parse item list: %w", err)
+		return nil, 0, fmt.Errorf("parse item list: %w", err)
+	}
+
+	items := make([]ghItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.Content.URL == "" || item.Content.Number == 0 {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, len(list.Items) - len(items), nil
+}
+
+// describeSourceColumns names the columns a sync reads from, for messages
+func describeSourceColumns(routes []syncRoute) string {
+	if len(routes) == 1 {
+		return fmt.Sprintf("%q column", routes[0].Source)
+	}
+	quoted := make([]string, len(routes))
+	for i, route := range routes {
+		quoted[i] = fmt.Sprintf("%q", route.Source)
+	}
+	return strings.Join(quoted, ", ") + " columns"
+}
+
+// selectSyncItems returns up to limit issues in the given status column that
+// carry every label in labels (compared case-insensitively)
+func selectSyncItems(items []ghItem, column string, labels []string, limit int) []ghItem {
+	var selected []ghItem
+	for _, item := range items {
+		if item.Status != column || item.Content.Type != "Issue" || !hasAllLabels(item, labels) {
+			continue
+		}
+		selected = append(selected, item)
+		if len(selected) >= limit {
+			break
+		}
+	}
+	return selected
+}
+
+// ghItemKey returns the sync state key for an issue item. Items without an
+// issue URL (e.g. draft issues) have no key and are never recorded.
+func ghItemKey(item ghItem) (string, bool) {
+	owner, repo := parseGitHubURL(item.Content.URL)
+	if owner == "" || repo == "" || item.Content.Number == 0 {
+		return "", false
 	}
+	return syncedIssueKey("github", owner, repo, item.Content.Number), true
+}
+
+// dropSyncedItems removes items in the given status column that are recorded
+// in state, returning the remaining items and how many were dropped
+func dropSyncedItems(items []ghItem, column string, state *syncState) ([]ghItem, int) {
+	var kept []ghItem
+	for _, item := range items {
+		if key, ok := ghItemKey(item); ok && item.Status == column {
+			if _, synced := state.lookup(key); synced {
+				continue
+			}
+		}
+		kept = append(kept, item)
+	}
+	return kept, len(items) - len(kept)
+}
+
+// hasAnyLabel reports whether item carries at least one label in labels
+func hasAnyLabel(item ghItem, labels []string) bool {
+	for _, want := range labels {
+		if hasAllLabels(item, []string{want}) {
+			return true
+		}
+	}
+	return false
+}
 
-	return list.Items, nil
+// hasAllLabels reports whether item carries every label in labels
+func hasAllLabels(item ghItem, labels []string) bool {
+	for _, want := range labels {
+		found := false
+		for _, have := range item.Labels {
+			if strings.EqualFold(have, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 func buildTaskDescription(item ghItem) string {
@@ -446,12 +940,8 @@ func updateItemStatus(projectID, itemID, fieldID, optionID string) error {
 		"--single-select-option-id", optionID,
 	}
 
-	out, err := exec.Command("gh", args...).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("%s: %s", err, string(out))
-	}
-
-	return nil
+	_, err := ghOutput(args...)
+	return err
 }
 
 // parseGitHubURL extracts owner and repo from a GitHub issue URL
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -36,6 +37,32 @@ func TestGHProjectListParsing(t *testing.T) {
 	}
 }
 
+func TestGHProjectViewParsing(t *testing.T) {
+	// Test parsing the format returned by gh project view --format json
+	jsonData := `{
+		"number": 7,
+		"title": "Sprint Board",
+		"id": "PVT_kwDOABC",
+		"owner": {"login": "org1", "type": "Organization"},
+		"items": {"totalCount": 12}
+	}`
+
+	var p ghProjectRaw
+	if err := json.Unmarshal([]byte(jsonData), &p); err != nil {
+		t.Fatalf("failed to parse project view: %v", err)
+	}
+
+	if p.Number != 7 {
+		t.Errorf("expected project number 7, got %d", p.Number)
+	}
+	if p.ID != "PVT_kwDOABC" {
+		t.Errorf("expected ID 'PVT_kwDOABC', got %q", p.ID)
+	}
+	if p.Owner.Login != "org1" {
+		t.Errorf("expected owner 'org1', got %q", p.Owner.Login)
+	}
+}
+
 func TestGHFieldListParsing(t *testing.T) {
 	jsonData := `{
 		"fields": [
@@ -137,6 +164,51 @@ func TestGHItemListParsing(t *testing.T) {
 	}
 }
 
+func TestParseProjectItems_NullContent(t *testing.T) {
+	jsonData := `{
+		"items": [
+			{
+				"id": "PVTI_123",
+				"content": {
+					"number": 42,
+					"title": "Fix the bug",
+					"url": "https://github.com/owner/repo/issues/42",
+					"type": "Issue"
+				},
+				"status": "Todo"
+			},
+			{
+				"id": "PVTI_hidden",
+				"content": null,
+				"status": "Todo"
+			},
+			{
+				"id": "PVTI_draft",
+				"content": {
+					"title": "Draft idea",
+					"body": "",
+					"type": "DraftIssue"
+				},
+				"status": "Todo"
+			}
+		]
+	}`
+
+	items, skipped, err := parseProjectItems([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("parseProjectItems: %v", err)
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 skipped items, got %d", skipped)
+	}
+	if len(items) != 1 || items[0].ID != "PVTI_123" {
+		t.Fatalf("expected only PVTI_123, got %+v", items)
+	}
+	if selected := selectSyncItems(items, "Todo", nil, 10); len(selected) != 1 {
+		t.Errorf("expected 1 item to sync, got %d", len(selected))
+	}
+}
+
 func TestBuildTaskDescription(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -278,3 +350,82 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFindItemForIssue(t *testing.T) {
+	items := []ghItem{
+		{ID: "1", Content: ghItemContent{Number: 42, Type: "Issue", URL: "https://github.com/other/repo/issues/42"}},
+		{ID: "2", Content: ghItemContent{Number: 42, Type: "PullRequest", URL: "https://github.com/owner/repo/pull/42"}},
+		{ID: "3", Content: ghItemContent{Number: 42, Type: "Issue", URL: "https://github.com/owner/repo/issues/42"}},
+	}
+
+	item := findItemForIssue(items, "Owner", "repo", 42)
+	if item == nil || item.ID != "3" {
+		t.Fatalf("expected item '3', got %+v", item)
+	}
+
+	if item := findItemForIssue(items, "owner", "repo", 7); item != nil {
+		t.Errorf("expected no item for missing issue, got %q", item.ID)
+	}
+}
+
+func TestSelectSyncItems_Labels(t *testing.T) {
+	items := []ghItem{
+		{ID: "1", Status: "Todo", Content: ghItemContent{Type: "Issue"}, Labels: []string{"bug"}},
+		{ID: "2", Status: "Todo", Content: ghItemContent{Type: "Issue"}, Labels: []string{"Agent-Ready", "bug"}},
+		{ID: "3", Status: "In Progress", Content: ghItemContent{Type: "Issue"}, Labels: []string{"agent-ready"}},
+		{ID: "4", Status: "Todo", Content: ghItemContent{Type: "Issue"}, Labels: []string{"agent-ready"}},
+		{ID: "5", Status: "Todo", Content: ghItemContent{Type: "Issue"}},
+	}
+
+	ids := func(items []ghItem) []string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		labels []string
+		limit  int
+		want   []string
+	}{
+		{"no label filter", nil, 10, []string{"1", "2", "4", "5"}},
+		{"single label", []string{"agent-ready"}, 10, []string{"2", "4"}},
+		{"all labels required", []string{"agent-ready", "bug"}, 10, []string{"2"}},
+		{"limit counts matches only", []string{"agent-ready"}, 1, []string{"2"}},
+		{"no matches", []string{"wontfix"}, 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ids(selectSyncItems(items, "Todo", tt.labels, tt.limit))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("selectSyncItems = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentsToSpawn(t *testing.T) {
+	tests := []struct {
+		name                  string
+		limit, tasks, running int
+		want                  int
+	}{
+		{"one per task below the limit", 3, 1, 0, 1},
+		{"capped at the limit", 3, 5, 0, 3},
+		{"existing agents count", 3, 5, 2, 1},
+		{"enough agents already", 3, 2, 2, 0},
+		{"more agents than the limit", 2, 5, 4, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agentsToSpawn(tt.limit, tt.tasks, tt.running); got != tt.want {
+				t.Errorf("agentsToSpawn(%d, %d, %d) = %d, want %d", tt.limit, tt.tasks, tt.running, got, tt.want)
+			}
+		})
+	}
+}
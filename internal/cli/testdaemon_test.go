@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	"github.com/spf13/viper"
+)
+
+// startTestDaemon runs a daemon on a temporary socket and data dir, points
+// the CLI's socket setting at it, and stops it when the test ends
+func startTestDaemon(t *testing.T) *daemon.Server {
+	t.Helper()
+
+	// Unix socket paths are short, so keep them out of the long test dir
+	dir, err := os.MkdirTemp("", "mapd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "mapd.sock")
+
+	srv, err := daemon.NewServer(&daemon.Config{SocketPath: socketPath, DataDir: filepath.Join(dir, "data")})
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	go func() { _ = srv.Start() }()
+	t.Cleanup(func() {
+		srv.Stop()
+		log.SetOutput(os.Stderr)
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !client.IsDaemonResponsive(socketPath, time.Second) {
+		if time.Now().After(deadline) {
+			t.Fatal("test daemon did not start")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	previous := viper.GetString("socket")
+	viper.Set("socket", socketPath)
+	t.Cleanup(func() { viper.Set("socket", previous) })
+	return srv
+}
+
+// chdirTestRepo makes a git repository the working directory for the rest
+// of the test and returns its root
+func chdirTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	t.Chdir(dir)
+	return getRepoRoot()
+}
+
+// captureStdout returns what fn prints to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		done <- string(out)
+	}()
+	fn()
+	_ = w.Close()
+	return strings.TrimSpace(<-done)
+}
@@ -0,0 +1,726 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	"github.com/pmarsceill/mapcli/internal/daemon"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive terminal UI for agents, tasks, and events",
+	Long: `Open a full-screen view of the daemon: agents with their current task,
+recent tasks, and a live event stream, refreshed as events arrive.
+
+Keys:
+  tab          switch between the agents and tasks panes
+  up/down, j/k move the selection
+  enter, a     attach to the selected agent (detach with Ctrl+B d to return)
+  s            spawn an agent (prompts for the type; other settings come
+               from the agent.* config, as for 'map agent create')
+  x            kill the selected agent
+  n            submit a task
+  c            cancel the selected task
+  /            filter tasks by ID, status, agent, or description
+  r            refresh now
+  q, Ctrl+C    quit
+
+The plain CLI commands are unaffected; everything here goes through the same
+daemon RPCs.`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func init() {
+	tuiCmd.Flags().Duration("refresh", 2*time.Second, "how often to refresh agents and tasks between events")
+	tuiCmd.Flags().Bool("keep-connection", false, "reconnect and resume the event stream when the daemon restarts")
+	rootCmd.AddCommand(tuiCmd)
+}
+
+// tuiEventLines is how many event lines the TUI keeps
+const tuiEventLines = 200
+
+// tuiTaskLimit is how many recent tasks the TUI lists
+const tuiTaskLimit = 100
+
+// tuiEventRefreshDelay is how long the TUI waits after an event before
+// reloading agents and tasks, so a burst of events costs one refresh
+const tuiEventRefreshDelay = 250 * time.Millisecond
+
+type tuiPane int
+
+const (
+	paneAgents tuiPane = iota
+	paneTasks
+)
+
+// tuiPrompt is a line being typed in the footer, e.g. a task description or
+// a confirmation
+type tuiPrompt struct {
+	label  string
+	input  string
+	submit func(input string)
+}
+
+// tuiState is everything the TUI draws. It is only touched from the main
+// loop.
+type tuiState struct {
+	agents   []*mapv1.SpawnedAgentInfo
+	tasks    []*mapv1.Task
+	events   []string
+	focus    tuiPane
+	agentSel int
+	taskSel  int
+	filter   string
+	prompt   *tuiPrompt
+	message  string
+}
+
+type tuiApp struct {
+	c       *client.Client
+	state   tuiState
+	results chan string
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetDuration("refresh")
+	if refresh <= 0 {
+		return fmt.Errorf("--refresh must be positive")
+	}
+
+	keep, _ := cmd.Flags().GetBool("keep-connection")
+
+	c, err := newStreamClient(keep)
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	// Without --keep-connection there is nothing to wait for, so a daemon
+	// that isn't running is reported before the screen is taken over
+	if !keep {
+		pingCtx, pingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err := c.Ping(pingCtx)
+		pingCancel()
+		if err != nil {
+			return fmt.Errorf("connect to daemon: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app := &tuiApp{c: c, results: make(chan string, 4)}
+
+	events := make(chan *mapv1.Event, 64)
+	go func() {
+		defer close(events)
+		_ = followEvents(ctx, c, keep, false, func(msg string) {
+			select {
+			case app.results <- msg:
+			case <-ctx.Done():
+			}
+		}, events)
+	}()
+
+	restore, err := enterTerminal()
+	if err != nil {
+		return err
+	}
+	defer func() { restore() }()
+
+	keys := newKeyReader(os.Stdin)
+	go keys.run()
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	app.refresh()
+	rows, cols := terminalSize()
+	app.draw(rows, cols)
+
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+
+	// pendingRefresh fires once events have arrived since the last refresh
+	// (nil = none pending)
+	var pendingRefresh <-chan time.Time
+
+	for {
+		select {
+		case key, ok := <-keys.keys:
+			if !ok {
+				return nil
+			}
+			quit, attach := app.handleKey(key)
+			if quit {
+				return nil
+			}
+			if attach != "" {
+				// Stop reading keys so tmux gets all of the input
+				keys.pause()
+				restore()
+				attachErr := attachToAgent(c, attach)
+				if restore, err = enterTerminalAfter(attachErr); err != nil {
+					restore = func() {}
+					return err
+				}
+				keys.resume()
+				app.state.message = ""
+				app.refresh()
+				rows, cols = terminalSize()
+			}
+		case event, ok := <-events:
+			if !ok {
+				app.state.message = "event stream closed; is the daemon still running?"
+				events = nil
+				break
+			}
+			if line := formatEvent(event, "15:04:05"); line != "" {
+				app.addEvent(line)
+			}
+			if pendingRefresh == nil {
+				pendingRefresh = time.After(tuiEventRefreshDelay)
+			}
+		case <-pendingRefresh:
+			pendingRefresh = nil
+			app.refresh()
+		case msg := <-app.results:
+			app.state.message = msg
+			app.refresh()
+		case <-ticker.C:
+			app.refresh()
+		case <-winch:
+			rows, cols = terminalSize()
+		}
+		app.draw(rows, cols)
+	}
+}
+
+// enterTerminalAfter re-enters the TUI after attaching to an agent. A failed
+// attach is shown to the user so they can read it before the screen returns.
+func enterTerminalAfter(attachErr error) (func(), error) {
+	if attachErr != nil {
+		fmt.Printf("%v\npress enter to return to map tui", attachErr)
+		_, _ = fmt.Scanln()
+	}
+	return enterTerminal()
+}
+
+// refresh reloads agents and tasks from the daemon
+func (a *tuiApp) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	agents, err := a.c.ListSpawnedAgents(ctx, "")
+	if err != nil {
+		a.state.message = fmt.Sprintf("list agents: %v", errorMessage(err))
+		return
+	}
+	tasks, err := a.c.ListTasks(ctx, tuiTaskLimit, "", false)
+	if err != nil {
+		a.state.message = fmt.Sprintf("list tasks: %v", errorMessage(err))
+		return
+	}
+	a.state.agents = agents
+	a.state.tasks = tasks
+	a.state.clampSelection()
+}
+
+func (a *tuiApp) addEvent(line string) {
+	a.state.events = append(a.state.events, line)
+	if over := len(a.state.events) - tuiEventLines; over > 0 {
+		a.state.events = a.state.events[over:]
+	}
+}
+
+// handleKey applies a key press. It reports whether to quit, or the agent
+// to attach to.
+func (a *tuiApp) handleKey(key string) (quit bool, attach string) {
+	s := &a.state
+	if s.prompt != nil {
+		switch key {
+		case "enter":
+			p := s.prompt
+			s.prompt = nil
+			p.submit(p.input)
+		case "esc", "ctrl+c":
+			s.prompt = nil
+		case "backspace":
+			if n := len(s.prompt.input); n > 0 {
+				s.prompt.input = s.prompt.input[:n-1]
+			}
+		default:
+			if len(key) == 1 {
+				s.prompt.input += key
+			}
+		}
+		return false, ""
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return true, ""
+	case "tab":
+		if s.focus == paneAgents {
+			s.focus = paneTasks
+		} else {
+			s.focus = paneAgents
+		}
+	case "up", "k":
+		s.move(-1)
+	case "down", "j":
+		s.move(1)
+	case "r":
+		a.refresh()
+	case "/":
+		s.prompt = &tuiPrompt{label: "filter tasks: ", input: s.filter, submit: func(in string) {
+			s.filter = strings.TrimSpace(in)
+			s.taskSel = 0
+		}}
+	case "n":
+		s.prompt = &tuiPrompt{label: "new task: ", submit: a.submitTask}
+	case "s":
+		s.prompt = &tuiPrompt{label: "spawn agent type: ", input: viper.GetString("agent.default-type"), submit: a.spawnAgent}
+	case "enter", "a":
+		if agent := s.selectedAgent(); agent != nil && s.focus == paneAgents {
+			return false, agent.AgentId
+		}
+	case "x":
+		if agent := s.selectedAgent(); agent != nil && s.focus == paneAgents {
+			id := agent.AgentId
+			s.prompt = &tuiPrompt{label: fmt.Sprintf("kill agent %s? [y/N] ", id), submit: func(in string) {
+				if isYes(in) {
+					a.killAgent(id)
+				}
+			}}
+		}
+	case "c":
+		if task := s.selectedTask(); task != nil && s.focus == paneTasks {
+			id := task.TaskId
+			s.prompt = &tuiPrompt{label: fmt.Sprintf("cancel task %s? [y/N] ", id), submit: func(in string) {
+				if isYes(in) {
+					a.cancelTask(id)
+				}
+			}}
+		}
+	}
+	return false, ""
+}
+
+func isYes(in string) bool {
+	in = strings.ToLower(strings.TrimSpace(in))
+	return in == "y" || in == "yes"
+}
+
+func (a *tuiApp) submitTask(description string) {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	task, err := a.c.SubmitTask(ctx, &mapv1.SubmitTaskRequest{Description: description, RepoRoot: getRepoRoot()})
+	if err != nil {
+		a.state.message = fmt.Sprintf("submit task: %s", errorMessage(err))
+		return
+	}
+	a.state.message = fmt.Sprintf("task submitted: %s", task.TaskId)
+	a.refresh()
+}
+
+// spawnAgent starts one agent in the background, since spawning waits for
+// the agent's CLI to boot. Settings come from the agent.* config.
+func (a *tuiApp) spawnAgent(agentType string) {
+	agentType = strings.TrimSpace(agentType)
+	if agentType == "" {
+		agentType = viper.GetString("agent.default-type")
+	}
+	if !daemon.ValidAgentType(agentType) {
+		a.state.message = fmt.Sprintf("invalid agent type %q: must be %s", agentType, daemon.AgentTypeChoices())
+		return
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		a.state.message = fmt.Sprintf("get working directory: %v", err)
+		return
+	}
+
+	req := &mapv1.SpawnAgentRequest{
+		Count:            1,
+		Branch:           viper.GetString("agent.default-branch"),
+		UseWorktree:      viper.GetBool("agent.use-worktree"),
+		AgentType:        agentType,
+		SkipPermissions:  viper.GetBool("agent.skip-permissions"),
+		WorkingDirectory: cwd,
+	}
+	a.state.message = fmt.Sprintf("spawning %s agent...", agentType)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		resp, err := a.c.SpawnAgent(ctx, req)
+		switch {
+		case err != nil:
+			a.results <- fmt.Sprintf("spawn agent: %s", errorMessage(err))
+		case len(resp.Agents) == 0:
+			a.results <- "no agents spawned"
+		default:
+			a.results <- fmt.Sprintf("spawned agent %s", resp.Agents[0].AgentId)
+		}
+	}()
+}
+
+func (a *tuiApp) killAgent(agentID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	resp, err := a.c.KillAgent(ctx, agentID, false, false)
+	switch {
+	case err != nil:
+		a.state.message = fmt.Sprintf("kill agent: %s", errorMessage(err))
+	case !resp.Success:
+		a.state.message = fmt.Sprintf("failed to kill agent: %s", resp.Message)
+	default:
+		a.state.message = resp.Message
+	}
+	a.refresh()
+}
+
+func (a *tuiApp) cancelTask(taskID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := a.c.CancelTask(ctx, taskID); err != nil {
+		a.state.message = fmt.Sprintf("cancel task: %s", errorMessage(err))
+		return
+	}
+	a.state.message = fmt.Sprintf("task cancelled: %s", taskID)
+	a.refresh()
+}
+
+// visibleTasks returns the tasks matching the filter
+func (s *tuiState) visibleTasks() []*mapv1.Task {
+	if s.filter == "" {
+		return s.tasks
+	}
+	needle := strings.ToLower(s.filter)
+	var tasks []*mapv1.Task
+	for _, t := range s.tasks {
+		haystack := strings.ToLower(strings.Join([]string{
+			t.TaskId, taskStatusString(t.Status), t.AssignedTo, t.Description,
+		}, " "))
+		if strings.Contains(haystack, needle) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func (s *tuiState) selectedAgent() *mapv1.SpawnedAgentInfo {
+	if s.agentSel < len(s.agents) {
+		return s.agents[s.agentSel]
+	}
+	return nil
+}
+
+func (s *tuiState) selectedTask() *mapv1.Task {
+	if tasks := s.visibleTasks(); s.taskSel < len(tasks) {
+		return tasks[s.taskSel]
+	}
+	return nil
+}
+
+func (s *tuiState) move(delta int) {
+	if s.focus == paneAgents {
+		s.agentSel += delta
+	} else {
+		s.taskSel += delta
+	}
+	s.clampSelection()
+}
+
+func (s *tuiState) clampSelection() {
+	s.agentSel = min(max(s.agentSel, 0), max(len(s.agents)-1, 0))
+	s.taskSel = min(max(s.taskSel, 0), max(len(s.visibleTasks())-1, 0))
+}
+
+// agentTask returns the ID of the task an agent is working on, if any
+func (s *tuiState) agentTask(agentID string) string {
+	for _, t := range s.tasks {
+		if t.AssignedTo == agentID && t.Status == mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS {
+			return t.TaskId
+		}
+	}
+	return ""
+}
+
+// render lays out the screen as lines of at most width columns, height lines
+// in all. The selected row of the focused pane is shown in reverse video.
+func (s *tuiState) render(width, height int) []string {
+	tasks := s.visibleTasks()
+
+	// Split the space between the header and footer: agents get up to a
+	// third, events a quarter, and tasks the rest
+	body := max(height-2, 6)
+	agentRows := min(max(len(s.agents), 1), max(body/3-1, 1))
+	eventRows := max(body/4-1, 1)
+	taskRows := max(body-agentRows-eventRows-3, 1)
+
+	header := fmt.Sprintf("map tui  %d agent(s), %d task(s)", len(s.agents), len(s.tasks))
+	if s.filter != "" {
+		header += fmt.Sprintf("  filter: %q", s.filter)
+	}
+	lines := []string{bold(fitLine(header, width))}
+
+	lines = append(lines, bold(fitLine(fmt.Sprintf("%-24s %-12s %-8s %s", "AGENTS", "STATUS", "TYPE", "TASK"), width)))
+	agentLines := make([]string, len(s.agents))
+	for i, agent := range s.agents {
+		agentLines[i] = fmt.Sprintf("%-24s %-12s %-8s %s",
+			truncate(agent.AgentId, 24),
+			truncate(valueOrDash(agent.Status), 12),
+			valueOrDash(agent.AgentType),
+			valueOrDash(s.agentTask(agent.AgentId)),
+		)
+	}
+	lines = append(lines, paneRows(agentLines, s.agentSel, s.focus == paneAgents, agentRows, width, "no agents")...)
+
+	lines = append(lines, bold(fitLine(fmt.Sprintf("%-36s %-12s %-20s %s", "TASKS", "STATUS", "ASSIGNED TO", "DESCRIPTION"), width)))
+	taskLines := make([]string, len(tasks))
+	for i, t := range tasks {
+		taskLines[i] = fmt.Sprintf("%-36s %-12s %-20s %s",
+			t.TaskId,
+			taskStatusString(t.Status),
+			truncate(valueOrDash(t.AssignedTo), 20),
+			strings.Join(strings.Fields(t.Description), " "),
+		)
+	}
+	lines = append(lines, paneRows(taskLines, s.taskSel, s.focus == paneTasks, taskRows, width, "no tasks")...)
+
+	lines = append(lines, bold(fitLine("EVENTS", width)))
+	events := s.events[max(len(s.events)-eventRows, 0):]
+	for i := range eventRows {
+		if i < len(events) {
+			lines = append(lines, fitLine(events[i], width))
+		} else {
+			lines = append(lines, "")
+		}
+	}
+
+	footer := "tab pane  j/k move  enter attach  s spawn  x kill  n task  c cancel  / filter  q quit"
+	switch {
+	case s.prompt != nil:
+		footer = s.prompt.label + s.prompt.input
+	case s.message != "":
+		footer = s.message
+	}
+	return append(lines, fitLine(footer, width))
+}
+
+// paneRows renders a pane's rows, scrolled so the selected row is visible,
+// padded to exactly rows lines
+func paneRows(items []string, selected int, focused bool, rows, width int, empty string) []string {
+	if len(items) == 0 {
+		items = []string{empty}
+		focused = false
+	}
+	start := 0
+	if selected >= rows {
+		start = selected - rows + 1
+	}
+	out := make([]string, 0, rows)
+	for i := start; i < start+rows; i++ {
+		if i >= len(items) {
+			out = append(out, "")
+			continue
+		}
+		line := fitLine(items[i], width)
+		if focused && i == selected {
+			line = "\x1b[7m" + line + "\x1b[0m"
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// fitLine truncates s to width columns
+func fitLine(s string, width int) string {
+	if len(s) <= width {
+		return s
+	}
+	if width < 4 {
+		return s[:max(width, 0)]
+	}
+	return truncate(s, width)
+}
+
+func bold(s string) string {
+	return "\x1b[1m" + s + "\x1b[0m"
+}
+
+// draw redraws the whole screen
+func (a *tuiApp) draw(rows, cols int) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range a.state.render(cols, rows) {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	_, _ = os.Stdout.WriteString(b.String())
+}
+
+// enterTerminal switches the terminal to the alternate screen with input
+// unbuffered and unechoed, returning a function that restores it. Ctrl+C
+// arrives as a key rather than a signal so the terminal is always restored.
+// Reads time out after a tenth of a second so the key reader can be paused.
+func enterTerminal() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("map tui needs an interactive terminal: %w", err)
+	}
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "0", "time", "1"); err != nil {
+		return nil, fmt.Errorf("set terminal mode: %w", err)
+	}
+	_, _ = os.Stdout.WriteString("\x1b[?1049h\x1b[?25l\x1b[2J")
+
+	restored := false
+	return func() {
+		if restored {
+			return
+		}
+		restored = true
+		_, _ = os.Stdout.WriteString("\x1b[?25h\x1b[?1049l")
+		_, _ = stty(strings.TrimSpace(saved))
+	}, nil
+}
+
+// terminalSize returns the terminal's rows and columns, or 24x80 if it
+// can't be determined
+func terminalSize() (rows, cols int) {
+	out, err := stty("size")
+	if err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			r, err1 := strconv.Atoi(fields[0])
+			c, err2 := strconv.Atoi(fields[1])
+			if err1 == nil && err2 == nil && r > 0 && c > 0 {
+				return r, c
+			}
+		}
+	}
+	return 24, 80
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// keyReader decodes key presses from the terminal. It can be paused while
+// another program, such as tmux, owns the terminal, so it doesn't swallow
+// that program's input.
+type keyReader struct {
+	r        io.Reader
+	keys     chan string
+	pauseCh  chan struct{}
+	parkedCh chan struct{}
+	resumeCh chan struct{}
+}
+
+func newKeyReader(r io.Reader) *keyReader {
+	return &keyReader{
+		r:        r,
+		keys:     make(chan string, 64),
+		pauseCh:  make(chan struct{}),
+		parkedCh: make(chan struct{}),
+		resumeCh: make(chan struct{}),
+	}
+}
+
+// run reads until the terminal fails. A read that times out with no input
+// reports io.EOF, which just means there was nothing to read.
+func (k *keyReader) run() {
+	defer close(k.keys)
+	buf := make([]byte, 64)
+	for {
+		select {
+		case <-k.pauseCh:
+			k.parkedCh <- struct{}{}
+			<-k.resumeCh
+		default:
+		}
+
+		n, err := k.r.Read(buf)
+		for _, key := range decodeKeys(buf[:n]) {
+			k.keys <- key
+		}
+		if err != nil && err != io.EOF {
+			return
+		}
+	}
+}
+
+// pause waits until the reader has stopped reading
+func (k *keyReader) pause() {
+	k.pauseCh <- struct{}{}
+	<-k.parkedCh
+}
+
+// resume starts the reader again after pause
+func (k *keyReader) resume() {
+	k.resumeCh <- struct{}{}
+}
+
+// decodeKeys turns raw terminal input into key names: printable characters
+// as themselves, plus "up", "down", "tab", "enter", "backspace", "esc", and
+// "ctrl+c". Other control sequences are dropped.
+func decodeKeys(b []byte) []string {
+	var keys []string
+	for i := 0; i < len(b); i++ {
+		switch ch := b[i]; {
+		case ch == 0x1b:
+			if i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				switch b[i+2] {
+				case 'A':
+					keys = append(keys, "up")
+				case 'B':
+					keys = append(keys, "down")
+				}
+				i += 2
+				continue
+			}
+			keys = append(keys, "esc")
+		case ch == '\t':
+			keys = append(keys, "tab")
+		case ch == '\r' || ch == '\n':
+			keys = append(keys, "enter")
+		case ch == 0x7f || ch == 0x08:
+			keys = append(keys, "backspace")
+		case ch == 0x03:
+			keys = append(keys, "ctrl+c")
+		case ch >= 0x20 && ch < 0x7f:
+			keys = append(keys, string(ch))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	got := decodeKeys([]byte("j\x1b[A\x1b[B\t\r\x7f\x03\x1bq\x1b[C"))
+	want := []string{"j", "up", "down", "tab", "enter", "backspace", "ctrl+c", "esc", "q"}
+	if !slices.Equal(got, want) {
+		t.Errorf("decodeKeys = %q, want %q", got, want)
+	}
+}
+
+func TestTUIState_Filter(t *testing.T) {
+	app := &tuiApp{state: tuiState{tasks: []*mapv1.Task{
+		{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_FAILED, Description: "Fix the build"},
+		{TaskId: "t2", Status: mapv1.TaskStatus_TASK_STATUS_PENDING, Description: "Write docs", AssignedTo: "claude-a"},
+	}}}
+
+	for _, key := range []string{"/", "f", "a", "i", "l", "enter"} {
+		if quit, _ := app.handleKey(key); quit {
+			t.Fatalf("key %q quit the TUI while typing a filter", key)
+		}
+	}
+	if app.state.filter != "fail" {
+		t.Fatalf("filter = %q, want %q", app.state.filter, "fail")
+	}
+	if tasks := app.state.visibleTasks(); len(tasks) != 1 || tasks[0].TaskId != "t1" {
+		t.Errorf("visibleTasks = %v, want only t1", tasks)
+	}
+
+	app.state.filter = "claude-a"
+	if tasks := app.state.visibleTasks(); len(tasks) != 1 || tasks[0].TaskId != "t2" {
+		t.Errorf("visibleTasks by agent = %v, want only t2", tasks)
+	}
+
+	if quit, _ := app.handleKey("q"); !quit {
+		t.Error("q outside a prompt should quit")
+	}
+}
+
+func TestTUIState_Render(t *testing.T) {
+	s := &tuiState{
+		agents: []*mapv1.SpawnedAgentInfo{{AgentId: "claude-a", AgentType: "claude"}},
+		tasks: []*mapv1.Task{
+			{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS, AssignedTo: "claude-a", Description: "A very long description that will not fit in a narrow terminal"},
+		},
+		events: []string{"[12:00:00] task started: t1"},
+	}
+	for i := range 20 {
+		s.tasks = append(s.tasks, &mapv1.Task{TaskId: fmt.Sprintf("p%d", i), Status: mapv1.TaskStatus_TASK_STATUS_PENDING})
+	}
+	s.focus = paneTasks
+	s.taskSel = 20
+
+	escapes := regexp.MustCompile("\x1b\\[[0-9;]*m")
+	lines := s.render(60, 24)
+	if len(lines) != 24 {
+		t.Errorf("render produced %d lines, want 24", len(lines))
+	}
+	var sawSelected bool
+	for _, line := range lines {
+		if plain := escapes.ReplaceAllString(line, ""); len(plain) > 60 {
+			t.Errorf("line wider than the terminal: %q", plain)
+		}
+		if regexp.MustCompile("\x1b\\[7mp19 ").MatchString(line) {
+			sawSelected = true
+		}
+	}
+	if !sawSelected {
+		t.Error("the selected task should be scrolled into view and highlighted")
+	}
+	if got := s.agentTask("claude-a"); got != "t1" {
+		t.Errorf("agentTask = %q, want t1", got)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pmarsceill/mapcli/internal/client"
 	"github.com/pmarsceill/mapcli/internal/daemon"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -45,9 +46,49 @@ func runUp(cmd *cobra.Command, args []string) error {
 }
 
 func runForeground() error {
+	socketMode, err := daemon.ParseSocketMode(viper.GetString("socket-mode"))
+	if err != nil {
+		return err
+	}
+
+	rules, err := routingRules()
+	if err != nil {
+		return err
+	}
+
 	cfg := &daemon.Config{
-		SocketPath: getSocketPath(),
-		DataDir:    dataDir,
+		SocketPath:          getSocketPath(),
+		SocketMode:          socketMode,
+		DataDir:             dataDir,
+		SchedulingStrategy:  viper.GetString("task.scheduling-strategy"),
+		AutoSpawn:           viper.GetBool("task.auto-spawn"),
+		AutoSpawnMax:        viper.GetInt("task.auto-spawn-max"),
+		AutoSpawnType:       viper.GetString(autoSpawnTypeKey()),
+		WarmPoolSize:        viper.GetInt("agent.warm-pool"),
+		WarmPoolRepo:        viper.GetString("agent.warm-pool-repo"),
+		WarmPoolType:        viper.GetString("agent.default-type"),
+		RequirePermissions:  !viper.GetBool("agent.skip-permissions"),
+		MaxRetries:          viper.GetInt("task.max-retries"),
+		MaxDescriptionBytes: viper.GetInt("task.max-description-bytes"),
+		MaxResultBytes:      viper.GetInt("task.max-result-bytes"),
+		ResultFiles:         viper.GetBool("task.result-files"),
+		BranchTemplate:      branchTemplate(),
+		Version:             Version,
+		MaxAgents:           viper.GetInt("agent.max-agents"),
+		GitHubTimeout:       viper.GetDuration("github.timeout"),
+		QuestionWebhookURL:  viper.GetString("input-monitor.webhook-url"),
+		NoGitHubQuestions:   !viper.GetBool("input-monitor.github"),
+		PromptConfirmWait:   viper.GetDuration("agent.prompt-confirm-wait"),
+		PromptRetries:       viper.GetInt("agent.prompt-retries"),
+		SubmitKeys:          submitKeys(),
+		StuckThreshold:      viper.GetDuration("agent.stuck-threshold"),
+		BootTimeout:         viper.GetDuration("agent.boot-timeout"),
+		HeartbeatInterval:   viper.GetDuration("agent.heartbeat-interval"),
+		AgentRuntime:        viper.GetString("agent.runtime"),
+		AgentImage:          viper.GetString("agent.image"),
+		EventBuffer:         viper.GetInt("event-buffer"),
+		AllowedRepos:        viper.GetStringSlice("security.allowed-repos"),
+		RoutingRules:        rules,
 	}
 
 	srv, err := daemon.NewServer(cfg)
@@ -69,11 +110,64 @@ func runForeground() error {
 	return srv.Start()
 }
 
+// routingRules reads the task routing rules under routing.rules
+func routingRules() ([]daemon.RoutingRule, error) {
+	var rules []daemon.RoutingRule
+	if err := viper.UnmarshalKey("routing.rules", &rules); err != nil {
+		return nil, fmt.Errorf("read routing.rules: %w", err)
+	}
+	return rules, nil
+}
+
+// submitKeys reads the keys that submit a prompt to each agent CLI from
+// agent.<type>-submit-keys
+func submitKeys() map[string][]string {
+	keys := make(map[string][]string)
+	for _, agentType := range daemon.AgentTypes() {
+		keys[agentType] = viper.GetStringSlice(submitKeysKey(agentType))
+	}
+	return keys
+}
+
+// agentSubmitKeys returns the keys the daemon submits a prompt to an agent
+// of agentType with, from agent.<type>-submit-keys
+func agentSubmitKeys(agentType string) []string {
+	if daemon.LookupAgentType(agentType) == nil {
+		agentType = daemon.AgentTypeClaude
+	}
+	return daemon.SubmitKeysFor(agentType, viper.GetStringSlice(submitKeysKey(agentType)))
+}
+
+// submitKeysKey returns the config key for an agent type's submit keys
+func submitKeysKey(agentType string) string {
+	return "agent." + agentType + "-submit-keys"
+}
+
+// autoSpawnTypeKey returns the config key for the auto-spawned agent type,
+// falling back to agent.default-type when task.auto-spawn-type is unset
+func autoSpawnTypeKey() string {
+	if viper.GetString("task.auto-spawn-type") != "" {
+		return "task.auto-spawn-type"
+	}
+	return "agent.default-type"
+}
+
 func runBackground() error {
-	// Start daemon as background process
+	pid, err := startDaemonProcess()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("mapd started (pid %d)\n", pid)
+	return nil
+}
+
+// startDaemonProcess launches 'map up -f' as a detached background process
+// and returns its PID
+func startDaemonProcess() (int, error) {
 	executable, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("get executable: %w", err)
+		return 0, fmt.Errorf("get executable: %w", err)
 	}
 
 	args := []string{"up", "-f", "-s", getSocketPath()}
@@ -92,9 +186,17 @@ func runBackground() error {
 	}
 
 	if err := proc.Start(); err != nil {
-		return fmt.Errorf("start daemon: %w", err)
+		return 0, fmt.Errorf("start daemon: %w", err)
 	}
 
-	fmt.Printf("mapd started (pid %d)\n", proc.Process.Pid)
-	return nil
+	return proc.Process.Pid, nil
+}
+
+// branchTemplate returns the worktree branch name template, or "" when
+// worktrees should use a detached HEAD
+func branchTemplate() string {
+	if !viper.GetBool("worktree.branch-per-agent") {
+		return ""
+	}
+	return viper.GetString("worktree.branch-template")
 }
@@ -17,16 +17,40 @@ import (
 var watchCmd = &cobra.Command{
 	Use:   "watch",
 	Short: "Watch real-time events",
-	Long:  `Stream events from the daemon in real-time.`,
-	RunE:  runWatch,
+	Long: `Stream events from the daemon in real-time.
+
+With --keep-connection, watch keeps its connection across daemon restarts:
+when the daemon goes away it waits for it to come back and resumes the
+stream. Events sent while it was disconnected are not shown; use
+'map events --since' to catch up on them.
+
+With --heartbeats, the periodic heartbeat the daemon emits for each live
+agent is shown too.`,
+	RunE: runWatch,
 }
 
+var (
+	watchKeepConnection bool
+	watchHeartbeats     bool
+)
+
 func init() {
+	watchCmd.Flags().BoolVar(&watchKeepConnection, "keep-connection", false, "reconnect and resume when the daemon restarts instead of exiting")
+	watchCmd.Flags().BoolVar(&watchHeartbeats, "heartbeats", false, "also show agent heartbeat events")
 	rootCmd.AddCommand(watchCmd)
 }
 
+// newStreamClient connects to the daemon for a long-running command. With
+// keep, the connection is kept across daemon restarts.
+func newStreamClient(keep bool) (*client.Client, error) {
+	if keep {
+		return client.NewPersistent(getSocketPath())
+	}
+	return client.New(getSocketPath())
+}
+
 func runWatch(cmd *cobra.Command, args []string) error {
-	c, err := client.New(getSocketPath())
+	c, err := newStreamClient(watchKeepConnection)
 	if err != nil {
 		return fmt.Errorf("connect to daemon: %w", err)
 	}
@@ -43,79 +67,155 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	stream, err := c.WatchEvents(ctx)
-	if err != nil {
+	fmt.Println("watching events (ctrl+c to stop)...")
+	fmt.Println()
+
+	events := make(chan *mapv1.Event, 64)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(events)
+		errCh <- followEvents(ctx, c, watchKeepConnection, watchHeartbeats, func(msg string) {
+			fmt.Fprintln(os.Stderr, msg)
+		}, events)
+	}()
+
+	for event := range events {
+		printEvent(event)
+	}
+	if err := <-errCh; err != nil {
 		return fmt.Errorf("watch events: %w", err)
 	}
+	return nil
+}
 
-	fmt.Println("watching events (ctrl+c to stop)...")
-	fmt.Println()
+// followEvents sends daemon events to out until ctx is done or the stream
+// ends. With keep, a broken stream (e.g. the daemon restarting) is
+// resubscribed once the daemon is reachable again, and notice is told about
+// the disconnect and the reconnect. Agent heartbeats are only sent with
+// heartbeats.
+func followEvents(ctx context.Context, c *client.Client, keep, heartbeats bool, notice func(string), out chan<- *mapv1.Event) error {
+	for {
+		err := receiveEvents(ctx, c, heartbeats, out)
+		if ctx.Err() != nil {
+			// Context cancelled, normal exit
+			return nil
+		}
+		if !keep {
+			return err
+		}
+
+		notice("lost connection to daemon; waiting for it to come back...")
+		if err := c.WaitReconnect(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		notice("reconnected to daemon")
+	}
+}
 
+// receiveEvents subscribes to daemon events and sends them to out until the
+// stream ends. A stream closed by the daemon returns nil.
+func receiveEvents(ctx context.Context, c *client.Client, heartbeats bool, out chan<- *mapv1.Event) error {
+	stream, err := c.WatchEvents(ctx, heartbeats)
+	if err != nil {
+		return err
+	}
 	for {
 		event, err := stream.Recv()
 		if err == io.EOF {
-			break
+			return nil
 		}
 		if err != nil {
-			if ctx.Err() != nil {
-				// Context cancelled, normal exit
-				break
-			}
-			return fmt.Errorf("receive event: %w", err)
+			return err
+		}
+		select {
+		case out <- event:
+		case <-ctx.Done():
+			return nil
 		}
-
-		printEvent(event)
 	}
-
-	return nil
 }
 
 func printEvent(event *mapv1.Event) {
-	ts := event.Timestamp.AsTime().Local().Format("15:04:05")
+	if line := formatEvent(event, "15:04:05"); line != "" {
+		fmt.Println(line)
+	}
+}
+
+// formatEvent renders an event as a single line, with its timestamp in the
+// given layout. Returns "" for events with nothing to show.
+func formatEvent(event *mapv1.Event, layout string) string {
+	ts := event.Timestamp.AsTime().Local().Format(layout)
 
 	// Handle status events (used for agent lifecycle events)
 	if se := event.GetStatus(); se != nil && se.Message != "" {
-		fmt.Printf("[%s] %s\n", ts, se.Message)
-		return
+		return fmt.Sprintf("[%s] %s", ts, se.Message)
 	}
 
 	switch event.Type {
 	case mapv1.EventType_EVENT_TYPE_TASK_CREATED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task created: %s\n", ts, te.TaskId)
+			return fmt.Sprintf("[%s] task created: %s", ts, te.TaskId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_OFFERED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task offered: %s -> %s\n", ts, te.TaskId, te.AgentId)
+			return fmt.Sprintf("[%s] task offered: %s -> %s", ts, te.TaskId, te.AgentId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_ACCEPTED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task accepted: %s by %s\n", ts, te.TaskId, te.AgentId)
+			return fmt.Sprintf("[%s] task accepted: %s by %s", ts, te.TaskId, te.AgentId)
+		}
+
+	case mapv1.EventType_EVENT_TYPE_TASK_REJECTED:
+		if te := event.GetTask(); te != nil {
+			return fmt.Sprintf("[%s] task rejected: %s by %s", ts, te.TaskId, te.AgentId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_STARTED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task started: %s\n", ts, te.TaskId)
+			return fmt.Sprintf("[%s] task started: %s", ts, te.TaskId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_COMPLETED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task completed: %s\n", ts, te.TaskId)
+			return fmt.Sprintf("[%s] task completed: %s", ts, te.TaskId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_FAILED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task failed: %s\n", ts, te.TaskId)
+			return fmt.Sprintf("[%s] task failed: %s", ts, te.TaskId)
 		}
 
 	case mapv1.EventType_EVENT_TYPE_TASK_CANCELLED:
 		if te := event.GetTask(); te != nil {
-			fmt.Printf("[%s] task cancelled: %s\n", ts, te.TaskId)
+			return fmt.Sprintf("[%s] task cancelled: %s", ts, te.TaskId)
+		}
+
+	case mapv1.EventType_EVENT_TYPE_TASK_REOPENED:
+		if te := event.GetTask(); te != nil {
+			return fmt.Sprintf("[%s] task reopened: %s", ts, te.TaskId)
+		}
+
+	case mapv1.EventType_EVENT_TYPE_AGENT_STUCK:
+		if te := event.GetTask(); te != nil {
+			return fmt.Sprintf("[%s] agent stuck: %s (task %s)", ts, te.AgentId, te.TaskId)
+		}
+
+	case mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT:
+		if hb := event.GetHeartbeat(); hb != nil {
+			if hb.TaskId != "" {
+				return fmt.Sprintf("[%s] agent heartbeat: %s (%s, task %s)", ts, hb.AgentId, hb.Status, hb.TaskId)
+			}
+			return fmt.Sprintf("[%s] agent heartbeat: %s (%s)", ts, hb.AgentId, hb.Status)
 		}
 
 	default:
-		fmt.Printf("[%s] event: %s\n", ts, event.Type.String())
+		return fmt.Sprintf("[%s] event: %s", ts, event.Type.String())
 	}
+
+	return ""
 }
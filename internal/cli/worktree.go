@@ -3,25 +3,49 @@ package cli
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var worktreeCmd = &cobra.Command{
 	Use:   "worktree",
 	Short: "Manage agent worktrees",
-	Long:  `Commands for listing and cleaning up git worktrees created for agents.`,
+	Long:  `Commands for creating, listing, and cleaning up git worktrees for agents.`,
 }
 
 var worktreeLsCmd = &cobra.Command{
 	Use:     "ls",
 	Aliases: []string{"list"},
 	Short:   "List worktrees",
-	Long:    `List all git worktrees created for spawned agents.`,
-	RunE:    runWorktreeLs,
+	Long: `List the git worktrees created for spawned agents from the current
+repository. Use --all-repos to list every tracked worktree, with the
+repository each was created from, e.g. to find strays from other projects.`,
+	RunE: runWorktreeLs,
+}
+
+var worktreeCreateCmd = &cobra.Command{
+	Use:   "create <agent-id>",
+	Short: "Create a worktree for an agent spawned without one",
+	Long: `Create a git worktree for an agent spawned with --no-worktree, so it can
+be merged and cleaned up like any other agent's.
+
+tmux can't change the directory of a process that is already running, so the
+agent's CLI keeps working in the shared checkout until it is restarted. The
+worktree is used from the next respawn on ('map agent respawn <id> --force'),
+or right away with --restart, which discards the CLI's conversation. Headless
+agents use it from their next task.
+
+The worktree is created from the repository's current branch unless --branch
+is given; uncommitted changes the agent made in the shared checkout are not
+carried over.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorktreeCreate,
 }
 
 var worktreeCleanupCmd = &cobra.Command{
@@ -33,14 +57,36 @@ By default, only removes orphaned worktrees. Use --all to remove all agent workt
 	RunE: runWorktreeCleanup,
 }
 
+var worktreePruneBranchesCmd = &cobra.Command{
+	Use:   "prune-branches",
+	Short: "Delete leftover map branches",
+	Long: `Delete local map/<agent-id> branches in the current repository that are
+not checked out in any worktree and do not belong to a running agent.
+
+Use --dry-run to list the branches that would be deleted.`,
+	RunE: runWorktreePruneBranches,
+}
+
 func init() {
 	rootCmd.AddCommand(worktreeCmd)
 	worktreeCmd.AddCommand(worktreeLsCmd)
+	worktreeCmd.AddCommand(worktreeCreateCmd)
 	worktreeCmd.AddCommand(worktreeCleanupCmd)
+	worktreeCmd.AddCommand(worktreePruneBranchesCmd)
+
+	// create flags
+	worktreeCreateCmd.Flags().StringP("branch", "b", "", "Branch or ref to create the worktree from (default: current branch)")
+	worktreeCreateCmd.Flags().Bool("restart", false, "Restart the agent's CLI in the new worktree")
+
+	// ls flags
+	worktreeLsCmd.Flags().Bool("all-repos", false, "List worktrees from every repository, not just the current one")
 
 	// cleanup flags
 	worktreeCleanupCmd.Flags().String("agent", "", "Remove worktree for a specific agent ID")
 	worktreeCleanupCmd.Flags().Bool("all", false, "Remove all agent worktrees (including those with running agents)")
+
+	// prune-branches flags
+	worktreePruneBranchesCmd.Flags().Bool("dry-run", false, "List branches that would be deleted without deleting them")
 }
 
 func runWorktreeLs(cmd *cobra.Command, args []string) error {
@@ -53,8 +99,12 @@ func runWorktreeLs(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Filter by current repo
-	repoRoot := getRepoRoot()
+	// Filter by current repo unless every repo was asked for
+	allRepos, _ := cmd.Flags().GetBool("all-repos")
+	repoRoot := ""
+	if !allRepos {
+		repoRoot = getRepoRoot()
+	}
 	worktrees, err := c.ListWorktrees(ctx, repoRoot)
 	if err != nil {
 		return fmt.Errorf("list worktrees: %w", err)
@@ -65,14 +115,34 @@ func runWorktreeLs(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	fmt.Printf("%-20s %-15s %s\n", "AGENT ID", "BRANCH", "PATH")
-	fmt.Println(strings.Repeat("-", 80))
+	if allRepos {
+		sort.Slice(worktrees, func(i, j int) bool {
+			if worktrees[i].RepoRoot != worktrees[j].RepoRoot {
+				return worktrees[i].RepoRoot < worktrees[j].RepoRoot
+			}
+			return worktrees[i].AgentId < worktrees[j].AgentId
+		})
+		fmt.Printf("%-20s %-15s %-30s %s\n", "AGENT ID", "BRANCH", "REPO", "PATH")
+		fmt.Println(strings.Repeat("-", 110))
+	} else {
+		fmt.Printf("%-20s %-15s %s\n", "AGENT ID", "BRANCH", "PATH")
+		fmt.Println(strings.Repeat("-", 80))
+	}
 
 	for _, wt := range worktrees {
 		branch := wt.Branch
 		if branch == "" {
 			branch = "(detached)"
 		}
+		if allRepos {
+			fmt.Printf("%-20s %-15s %-30s %s\n",
+				truncate(wt.AgentId, 20),
+				truncate(branch, 15),
+				truncate(valueOrDash(wt.RepoRoot), 30),
+				wt.Path,
+			)
+			continue
+		}
 		fmt.Printf("%-20s %-15s %s\n",
 			truncate(wt.AgentId, 20),
 			truncate(branch, 15),
@@ -83,6 +153,48 @@ func runWorktreeLs(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runWorktreeCreate(cmd *cobra.Command, args []string) error {
+	branch, _ := cmd.Flags().GetString("branch")
+	restart, _ := cmd.Flags().GetBool("restart")
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	agentID, err := resolveAgentID(ctx, c, args[0])
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.CreateWorktree(ctx, &mapv1.CreateWorktreeRequest{
+		AgentId:      agentID,
+		Branch:       branch,
+		Restart:      restart,
+		GitUserName:  viper.GetString("agent.git-user-name"),
+		GitUserEmail: viper.GetString("agent.git-user-email"),
+	})
+	if err != nil {
+		return fmt.Errorf("create worktree: %w", err)
+	}
+
+	if isQuiet() {
+		fmt.Println(resp.Worktree.Path)
+		return nil
+	}
+	fmt.Printf("created worktree for %s: %s\n", agentID, resp.Worktree.Path)
+	if resp.Restarted {
+		fmt.Println("agent restarted in the worktree")
+	} else {
+		fmt.Printf("the agent's current session still works in the shared checkout; restart it with 'map agent respawn %s --force'\n", agentID)
+	}
+	return nil
+}
+
 func runWorktreeCleanup(cmd *cobra.Command, args []string) error {
 	agentID, _ := cmd.Flags().GetString("agent")
 	all, _ := cmd.Flags().GetBool("all")
@@ -101,6 +213,13 @@ func runWorktreeCleanup(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cleanup worktrees: %w", err)
 	}
 
+	if isQuiet() {
+		for _, path := range resp.RemovedPaths {
+			fmt.Println(path)
+		}
+		return nil
+	}
+
 	if resp.RemovedCount == 0 {
 		fmt.Println("no worktrees to cleanup")
 		return nil
@@ -113,3 +232,37 @@ func runWorktreeCleanup(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runWorktreePruneBranches(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	branches, err := c.PruneBranches(ctx, getRepoRoot(), dryRun)
+	if err != nil {
+		return fmt.Errorf("prune branches: %w", err)
+	}
+
+	if len(branches) == 0 {
+		fmt.Println("no branches to prune")
+		return nil
+	}
+
+	if dryRun {
+		fmt.Printf("would delete %d branch(es):\n", len(branches))
+	} else {
+		fmt.Printf("deleted %d branch(es):\n", len(branches))
+	}
+	for _, branch := range branches {
+		fmt.Printf("  - %s\n", branch)
+	}
+
+	return nil
+}
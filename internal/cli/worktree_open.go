@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pmarsceill/mapcli/internal/client"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"github.com/spf13/cobra"
+)
+
+var worktreeOpenCmd = &cobra.Command{
+	Use:   "open <agent-id>",
+	Short: "Print or open an agent's worktree",
+	Long: `Print the path of an agent's worktree, so you can jump into it from your
+shell, or open it in an editor with --editor.
+
+The editor is $VISUAL, then $EDITOR, then VS Code ('code'). Partial agent IDs
+are accepted.
+
+Examples:
+  cd "$(map worktree open claude-abc)"
+  map worktree open claude-abc --editor`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorktreeOpen,
+}
+
+func init() {
+	worktreeCmd.AddCommand(worktreeOpenCmd)
+	worktreeOpenCmd.Flags().Bool("editor", false, "Open the worktree in $VISUAL/$EDITOR (default: VS Code) instead of printing its path")
+}
+
+func runWorktreeOpen(cmd *cobra.Command, args []string) error {
+	c, err := client.New(getSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to daemon: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	repoRoot := getRepoRoot()
+	worktrees, err := c.ListWorktrees(ctx, repoRoot)
+	if err != nil {
+		return fmt.Errorf("list worktrees: %w", err)
+	}
+	agents, err := c.ListSpawnedAgents(ctx, repoRoot)
+	if err != nil {
+		return fmt.Errorf("list agents: %w", err)
+	}
+
+	path, err := findWorktreePath(worktrees, agents, args[0])
+	if err != nil {
+		return err
+	}
+
+	if useEditor, _ := cmd.Flags().GetBool("editor"); !useEditor {
+		fmt.Println(path)
+		return nil
+	}
+
+	editor := editorCommand(os.Getenv, "code")
+	editorCmd := exec.Command(editor[0], append(editor[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("open %s in %s: %w", path, editor[0], err)
+	}
+	return nil
+}
+
+// findWorktreePath resolves an exact or partial agent ID to its worktree,
+// looking at the daemon's worktrees first and then at agents' worktree paths
+func findWorktreePath(worktrees []*mapv1.WorktreeInfo, agents []*mapv1.SpawnedAgentInfo, agentID string) (string, error) {
+	paths := make(map[string]string)
+	for _, a := range agents {
+		if a.GetWorktreePath() != "" {
+			paths[a.GetAgentId()] = a.GetWorktreePath()
+		}
+	}
+	for _, wt := range worktrees {
+		if wt.GetPath() != "" {
+			paths[wt.GetAgentId()] = wt.GetPath()
+		}
+	}
+
+	if path, ok := paths[agentID]; ok {
+		return path, nil
+	}
+
+	var matches []string
+	for id := range paths {
+		if strings.HasPrefix(id, agentID) {
+			matches = append(matches, id)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no worktree found for agent %s", agentID)
+	case 1:
+		return paths[matches[0]], nil
+	default:
+		return "", fmt.Errorf("agent ID %q is ambiguous: matches %s", agentID, strings.Join(matches, ", "))
+	}
+}
+
+// editorCommand returns the user's editor, split into the program and its
+// arguments, or fallback if neither $VISUAL nor $EDITOR is set
+func editorCommand(getenv func(string) string, fallback string) []string {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(getenv(key)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{fallback}
+}
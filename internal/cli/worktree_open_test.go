@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestFindWorktreePath(t *testing.T) {
+	worktrees := []*mapv1.WorktreeInfo{
+		{AgentId: "claude-abc", Path: "/wt/claude-abc"},
+		{AgentId: "claude-abd", Path: "/wt/claude-abd"},
+	}
+	agents := []*mapv1.SpawnedAgentInfo{
+		{AgentId: "codex-xyz", WorktreePath: "/wt/codex-xyz"},
+		{AgentId: "gemini-shared"},
+	}
+
+	tests := []struct {
+		id      string
+		want    string
+		wantErr string
+	}{
+		{"claude-abc", "/wt/claude-abc", ""},
+		{"claude-abd", "/wt/claude-abd", ""},
+		{"codex", "/wt/codex-xyz", ""},
+		{"claude-ab", "", "ambiguous"},
+		{"gemini", "", "no worktree"},
+	}
+	for _, tt := range tests {
+		got, err := findWorktreePath(worktrees, agents, tt.id)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("findWorktreePath(%q) error = %v, want %q", tt.id, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("findWorktreePath(%q) = %q, %v; want %q", tt.id, got, err, tt.want)
+		}
+	}
+}
+
+func TestEditorCommand(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(k string) string { return env[k] }
+
+	if got := editorCommand(getenv, "code"); !slices.Equal(got, []string{"code"}) {
+		t.Errorf("editorCommand with no env = %v, want [code]", got)
+	}
+
+	env["EDITOR"] = "vim"
+	if got := editorCommand(getenv, "code"); !slices.Equal(got, []string{"vim"}) {
+		t.Errorf("editorCommand = %v, want [vim]", got)
+	}
+
+	env["VISUAL"] = "code -n"
+	if got := editorCommand(getenv, "code"); !slices.Equal(got, []string{"code", "-n"}) {
+		t.Errorf("editorCommand = %v, want VISUAL split into args", got)
+	}
+}
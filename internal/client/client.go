@@ -3,12 +3,17 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"time"
 
 	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const DefaultSocketPath = "/tmp/mapd.sock"
@@ -19,67 +24,133 @@ type Client struct {
 	daemon mapv1.DaemonServiceClient
 }
 
-// New creates a new client connected to the daemon
+// reconnectBackoff paces every client's reconnect attempts. The daemon is
+// local, so it is retried far sooner than gRPC's default, which backs off
+// to two minutes.
+var reconnectBackoff = backoff.Config{
+	BaseDelay:  100 * time.Millisecond,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   time.Second,
+}
+
+// New creates a new client connected to the daemon. Like every client it
+// reconnects within about a second of a restarted daemon coming back, so
+// the connection can be kept across a restart.
 func New(socketPath string) (*Client, error) {
+	c, err := dial(socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trigger connection attempt (NewClient doesn't connect immediately)
+	c.conn.Connect()
+	return c, nil
+}
+
+// NewPersistent creates a client for long-running commands such as watch
+// and tui. It is New without the eager connect: it connects on the first
+// RPC, so one connection can be kept for the life of the command even if
+// the daemon isn't up yet. Use WaitReady to check the connection before
+// relying on it.
+func NewPersistent(socketPath string) (*Client, error) {
+	return dial(socketPath)
+}
+
+// dial creates a client for the daemon at socketPath, reconnecting on
+// reconnectBackoff's schedule
+func dial(socketPath string) (*Client, error) {
 	if socketPath == "" {
 		socketPath = DefaultSocketPath
 	}
 
-	conn, err := grpc.NewClient(
-		"unix:"+socketPath,
+	conn, err := grpc.NewClient("unix:"+socketPath,
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           reconnectBackoff,
+			MinConnectTimeout: time.Second,
+		}),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("connect to daemon: %w", err)
 	}
 
-	// Trigger connection attempt (NewClient doesn't connect immediately)
-	conn.Connect()
-
 	return &Client{
 		conn:   conn,
 		daemon: mapv1.NewDaemonServiceClient(conn),
 	}, nil
 }
 
+// WaitReady connects if the client is idle and waits until the connection
+// to the daemon is up, or ctx is done
+func (c *Client) WaitReady(ctx context.Context) error {
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Idle:
+			c.conn.Connect()
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection closed")
+		}
+		if !c.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("daemon not reachable: %w", ctx.Err())
+		}
+	}
+}
+
+// WaitReconnect waits for the daemon to be reachable again after a stream
+// broke. The connection may not have noticed the daemon going away yet, so
+// it is given a moment to drop before waiting for it to be ready.
+func (c *Client) WaitReconnect(ctx context.Context) error {
+	if c.conn.GetState() == connectivity.Ready {
+		dropCtx, cancel := context.WithTimeout(ctx, time.Second)
+		c.conn.WaitForStateChange(dropCtx, connectivity.Ready)
+		cancel()
+	}
+	return c.WaitReady(ctx)
+}
+
 // Close closes the client connection
 func (c *Client) Close() error {
 	return c.conn.Close()
 }
 
-// SubmitTask creates a new task
-func (c *Client) SubmitTask(ctx context.Context, description string, scopePaths []string) (*mapv1.Task, error) {
-	resp, err := c.daemon.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
-		Description: description,
-		ScopePaths:  scopePaths,
-	})
+// SubmitTask creates a new task. Set req's GitHub fields to track the
+// issue the task came from.
+func (c *Client) SubmitTask(ctx context.Context, req *mapv1.SubmitTaskRequest) (*mapv1.Task, error) {
+	resp, err := c.daemon.SubmitTask(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	return resp.Task, nil
 }
 
-// SubmitTaskWithGitHub creates a new task with GitHub issue source tracking
-func (c *Client) SubmitTaskWithGitHub(ctx context.Context, description string, scopePaths []string, owner, repo string, issueNumber int32, repoRoot string) (*mapv1.Task, error) {
-	resp, err := c.daemon.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
-		Description:       description,
-		ScopePaths:        scopePaths,
-		GithubOwner:       owner,
-		GithubRepo:        repo,
-		GithubIssueNumber: issueNumber,
-		RepoRoot:          repoRoot,
+// ListRuns returns run summaries, newest first
+func (c *Client) ListRuns(ctx context.Context, limit int32, repoRoot string) ([]*mapv1.RunSummary, error) {
+	resp, err := c.daemon.ListRuns(ctx, &mapv1.ListRunsRequest{
+		Limit:    limit,
+		RepoRoot: repoRoot,
 	})
 	if err != nil {
 		return nil, err
 	}
-	return resp.Task, nil
+	return resp.Runs, nil
 }
 
-// ListTasks returns tasks with optional filters
-func (c *Client) ListTasks(ctx context.Context, limit int32, repoRoot string) ([]*mapv1.Task, error) {
+// GetRun returns a run's summary and its tasks
+func (c *Client) GetRun(ctx context.Context, runID string) (*mapv1.GetRunResponse, error) {
+	return c.daemon.GetRun(ctx, &mapv1.GetRunRequest{RunId: runID})
+}
+
+// ListTasks returns tasks with optional filters. Archived tasks are only
+// included with includeArchived.
+func (c *Client) ListTasks(ctx context.Context, limit int32, repoRoot string, includeArchived bool) ([]*mapv1.Task, error) {
 	resp, err := c.daemon.ListTasks(ctx, &mapv1.ListTasksRequest{
-		Limit:    limit,
-		RepoRoot: repoRoot,
+		Limit:           limit,
+		RepoRoot:        repoRoot,
+		IncludeArchived: includeArchived,
 	})
 	if err != nil {
 		return nil, err
@@ -87,6 +158,15 @@ func (c *Client) ListTasks(ctx context.Context, limit int32, repoRoot string) ([
 	return resp.Tasks, nil
 }
 
+// ListTasksByStatus returns every task in a status, across all repos
+func (c *Client) ListTasksByStatus(ctx context.Context, status mapv1.TaskStatus) ([]*mapv1.Task, error) {
+	resp, err := c.daemon.ListTasks(ctx, &mapv1.ListTasksRequest{StatusFilter: status})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
 // GetTask retrieves a specific task
 func (c *Client) GetTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
 	resp, err := c.daemon.GetTask(ctx, &mapv1.GetTaskRequest{
@@ -109,6 +189,174 @@ func (c *Client) CancelTask(ctx context.Context, taskID string) (*mapv1.Task, er
 	return resp.Task, nil
 }
 
+// NudgeTask re-sends an in-progress task's prompt to its assigned agent
+func (c *Client) NudgeTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.NudgeTask(ctx, &mapv1.NudgeTaskRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// AcceptTask claims a task offered to agentID
+func (c *Client) AcceptTask(ctx context.Context, taskID, agentID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.AcceptTask(ctx, &mapv1.AcceptTaskRequest{TaskId: taskID, AgentId: agentID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// RejectTask declines a task offered to agentID
+func (c *Client) RejectTask(ctx context.Context, taskID, agentID, reason string) (*mapv1.Task, error) {
+	resp, err := c.daemon.RejectTask(ctx, &mapv1.RejectTaskRequest{TaskId: taskID, AgentId: agentID, Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// ListTaskAttempts returns a task's attempts, oldest first
+func (c *Client) ListTaskAttempts(ctx context.Context, taskID string) ([]*mapv1.TaskAttempt, error) {
+	resp, err := c.daemon.ListTaskAttempts(ctx, &mapv1.ListTaskAttemptsRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Attempts, nil
+}
+
+// RetryTask re-queues a failed or cancelled task
+func (c *Client) RetryTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.RetryTask(ctx, &mapv1.RetryTaskRequest{
+		TaskId: taskID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Retried) == 0 {
+		return nil, fmt.Errorf("task %s was not retried", taskID)
+	}
+	return resp.Retried[0], nil
+}
+
+// CreateScheduledTask creates a recurring task that submits description
+// each time the cron expression fires
+func (c *Client) CreateScheduledTask(ctx context.Context, cron, description string, scopePaths []string, repoRoot string) (*mapv1.ScheduledTask, error) {
+	resp, err := c.daemon.CreateScheduledTask(ctx, &mapv1.CreateScheduledTaskRequest{
+		Cron:        cron,
+		Description: description,
+		ScopePaths:  scopePaths,
+		RepoRoot:    repoRoot,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Schedule, nil
+}
+
+// ListScheduledTasks returns all recurring tasks, soonest first
+func (c *Client) ListScheduledTasks(ctx context.Context) ([]*mapv1.ScheduledTask, error) {
+	resp, err := c.daemon.ListScheduledTasks(ctx, &mapv1.ListScheduledTasksRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Schedules, nil
+}
+
+// DeleteScheduledTask removes a recurring task. Tasks it already submitted
+// are left alone.
+func (c *Client) DeleteScheduledTask(ctx context.Context, scheduleID string) error {
+	_, err := c.daemon.DeleteScheduledTask(ctx, &mapv1.DeleteScheduledTaskRequest{ScheduleId: scheduleID})
+	return err
+}
+
+// ReopenTask moves a completed task back into work
+func (c *Client) ReopenTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.ReopenTask(ctx, &mapv1.ReopenTaskRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// ArchiveTask hides a finished task from task listings
+func (c *Client) ArchiveTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.ArchiveTask(ctx, &mapv1.ArchiveTaskRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// ImportTasks recreates exported tasks, skipping or, with overwrite,
+// replacing those whose IDs already exist. Tasks are sent in batches, so
+// there is no limit on how many can be imported.
+func (c *Client) ImportTasks(ctx context.Context, tasks []*mapv1.Task, overwrite bool) (*mapv1.ImportTasksResponse, error) {
+	stream, err := c.daemon.ImportTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &mapv1.ImportTasksRequest{Overwrite: overwrite}
+	size := 0
+	for _, task := range tasks {
+		n := proto.Size(task)
+		if len(batch.Tasks) > 0 && size+n > importBatchBytes {
+			if err := stream.Send(batch); err != nil {
+				return nil, err
+			}
+			batch, size = &mapv1.ImportTasksRequest{}, 0
+		}
+		batch.Tasks = append(batch.Tasks, task)
+		size += n
+	}
+	if len(batch.Tasks) > 0 {
+		if err := stream.Send(batch); err != nil {
+			return nil, err
+		}
+	}
+	return stream.CloseAndRecv()
+}
+
+// importBatchBytes is roughly how much task data ImportTasks sends per
+// message, well under gRPC's 4 MiB message limit
+const importBatchBytes = 1 << 20
+
+// ExportTasks returns every task, from all repositories and including
+// archived ones, oldest first. Tasks are streamed, so there is no limit on
+// how many can be exported.
+func (c *Client) ExportTasks(ctx context.Context) ([]*mapv1.Task, error) {
+	stream, err := c.daemon.ExportTasks(ctx, &mapv1.ExportTasksRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*mapv1.Task
+	for {
+		task, err := stream.Recv()
+		if err == io.EOF {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+// RetryFailedTasks re-queues every failed task in repoRoot (empty = all
+// repos) that failed at or after since (zero = any time)
+func (c *Client) RetryFailedTasks(ctx context.Context, repoRoot string, since time.Time) (*mapv1.RetryTaskResponse, error) {
+	req := &mapv1.RetryTaskRequest{
+		AllFailed: true,
+		RepoRoot:  repoRoot,
+	}
+	if !since.IsZero() {
+		req.Since = timestamppb.New(since)
+	}
+	return c.daemon.RetryTask(ctx, req)
+}
+
 // RequestInput signals that an agent needs user input
 func (c *Client) RequestInput(ctx context.Context, taskID, question string) (*mapv1.RequestInputResponse, error) {
 	return c.daemon.RequestInput(ctx, &mapv1.RequestInputRequest{
@@ -128,20 +376,115 @@ func (c *Client) GetCurrentTask(ctx context.Context, workingDir string) (*mapv1.
 	return resp.Task, nil
 }
 
+// GetAgentTask returns an agent's active task, or nil if it has none
+func (c *Client) GetAgentTask(ctx context.Context, agentID string) (*mapv1.Task, error) {
+	resp, err := c.daemon.GetAgentTask(ctx, &mapv1.GetAgentTaskRequest{
+		AgentId: agentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Task, nil
+}
+
+// GetAgentHistory returns the messages exchanged with an agent, oldest
+// first. A positive limit keeps only the most recent.
+func (c *Client) GetAgentHistory(ctx context.Context, agentID string, limit int) ([]*mapv1.AgentMessage, error) {
+	resp, err := c.daemon.GetAgentHistory(ctx, &mapv1.GetAgentHistoryRequest{
+		AgentId: agentID,
+		Limit:   int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Messages, nil
+}
+
+// GetAgentUsage samples the CPU and memory used by an agent's processes,
+// or by every agent's when agentID is empty
+func (c *Client) GetAgentUsage(ctx context.Context, agentID string) ([]*mapv1.AgentUsage, error) {
+	resp, err := c.daemon.GetAgentUsage(ctx, &mapv1.GetAgentUsageRequest{AgentId: agentID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Usage, nil
+}
+
+// BroadcastMessage sends text to the sessions of every agent matching the
+// filters and reports the outcome for each
+func (c *Client) BroadcastMessage(ctx context.Context, req *mapv1.BroadcastMessageRequest) ([]*mapv1.BroadcastDelivery, error) {
+	resp, err := c.daemon.BroadcastMessage(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Deliveries, nil
+}
+
 // GetStatus returns daemon status
 func (c *Client) GetStatus(ctx context.Context) (*mapv1.GetStatusResponse, error) {
 	return c.daemon.GetStatus(ctx, &mapv1.GetStatusRequest{})
 }
 
+// SetSchedulerPaused pauses or resumes task assignment
+func (c *Client) SetSchedulerPaused(ctx context.Context, paused bool) (*mapv1.SetSchedulerPausedResponse, error) {
+	return c.daemon.SetSchedulerPaused(ctx, &mapv1.SetSchedulerPausedRequest{Paused: paused})
+}
+
 // Shutdown requests daemon shutdown
 func (c *Client) Shutdown(ctx context.Context, force bool) error {
 	_, err := c.daemon.Shutdown(ctx, &mapv1.ShutdownRequest{Force: force})
 	return err
 }
 
-// WatchEvents streams events from the daemon
-func (c *Client) WatchEvents(ctx context.Context) (mapv1.DaemonService_WatchEventsClient, error) {
-	return c.daemon.WatchEvents(ctx, &mapv1.WatchEventsRequest{})
+// WatchEvents streams events from the daemon, including agent heartbeats
+// if heartbeats is set
+func (c *Client) WatchEvents(ctx context.Context, heartbeats bool) (mapv1.DaemonService_WatchEventsClient, error) {
+	return c.daemon.WatchEvents(ctx, &mapv1.WatchEventsRequest{IncludeHeartbeats: heartbeats})
+}
+
+// StreamLogs streams daemon log lines at or above minLevel: the last tail
+// matching lines, then new lines as they are logged if follow is set
+func (c *Client) StreamLogs(ctx context.Context, minLevel string, tail int32, follow bool) (mapv1.DaemonService_StreamLogsClient, error) {
+	return c.daemon.StreamLogs(ctx, &mapv1.StreamLogsRequest{
+		MinLevel: minLevel,
+		Tail:     tail,
+		Follow:   follow,
+	})
+}
+
+// StreamAgentOutput follows an agent's pane, receiving new and changed lines
+// as plain text. tail recent lines are sent first.
+func (c *Client) StreamAgentOutput(ctx context.Context, agentID string, tail int32) (mapv1.DaemonService_StreamAgentOutputClient, error) {
+	return c.daemon.StreamAgentOutput(ctx, &mapv1.StreamAgentOutputRequest{
+		AgentId: agentID,
+		Tail:    tail,
+	})
+}
+
+// ListEvents returns persisted events, most recent first. A zero since and
+// empty types return all events, up to limit.
+func (c *Client) ListEvents(ctx context.Context, limit int32, types []mapv1.EventType, since time.Time) ([]*mapv1.Event, error) {
+	req := &mapv1.ListEventsRequest{
+		Limit:      limit,
+		TypeFilter: types,
+	}
+	if !since.IsZero() {
+		req.Since = timestamppb.New(since)
+	}
+	resp, err := c.daemon.ListEvents(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
+}
+
+// TaskEvents returns every persisted event about a task, most recent first
+func (c *Client) TaskEvents(ctx context.Context, taskID string) ([]*mapv1.Event, error) {
+	resp, err := c.daemon.ListEvents(ctx, &mapv1.ListEventsRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
 }
 
 // --- Spawned Agent Methods ---
@@ -151,11 +494,13 @@ func (c *Client) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 	return c.daemon.SpawnAgent(ctx, req)
 }
 
-// KillAgent terminates a spawned agent
-func (c *Client) KillAgent(ctx context.Context, agentID string, force bool) (*mapv1.KillAgentResponse, error) {
+// KillAgent terminates a spawned agent. Its active tasks are cancelled, or
+// put back in the queue with requeueTasks.
+func (c *Client) KillAgent(ctx context.Context, agentID string, force, requeueTasks bool) (*mapv1.KillAgentResponse, error) {
 	return c.daemon.KillAgent(ctx, &mapv1.KillAgentRequest{
-		AgentId: agentID,
-		Force:   force,
+		AgentId:      agentID,
+		Force:        force,
+		RequeueTasks: requeueTasks,
 	})
 }
 
@@ -170,13 +515,53 @@ func (c *Client) ListSpawnedAgents(ctx context.Context, repoRoot string) ([]*map
 	return resp.Agents, nil
 }
 
-// RespawnAgent restarts claude in an agent with a dead pane
-func (c *Client) RespawnAgent(ctx context.Context, agentID string) (*mapv1.RespawnAgentResponse, error) {
+// ListSpawnedAgentsWithLabels returns spawned agents that carry every one of
+// the given labels
+func (c *Client) ListSpawnedAgentsWithLabels(ctx context.Context, repoRoot string, labels map[string]string) ([]*mapv1.SpawnedAgentInfo, error) {
+	resp, err := c.daemon.ListSpawnedAgents(ctx, &mapv1.ListSpawnedAgentsRequest{
+		RepoRoot: repoRoot,
+		Labels:   labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Agents, nil
+}
+
+// ListStaleAgents returns the agents the daemon still tracks whose tmux
+// sessions no longer exist
+func (c *Client) ListStaleAgents(ctx context.Context, repoRoot string, labels map[string]string) ([]*mapv1.SpawnedAgentInfo, error) {
+	resp, err := c.daemon.ListSpawnedAgents(ctx, &mapv1.ListSpawnedAgentsRequest{
+		RepoRoot: repoRoot,
+		Labels:   labels,
+		Stale:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Agents, nil
+}
+
+// RespawnAgent restarts claude in an agent with a dead pane. With force, a
+// running pane is restarted too; requirePermissions restarts the agent with
+// permission prompts.
+func (c *Client) RespawnAgent(ctx context.Context, agentID string, force, requirePermissions bool) (*mapv1.RespawnAgentResponse, error) {
 	return c.daemon.RespawnAgent(ctx, &mapv1.RespawnAgentRequest{
-		AgentId: agentID,
+		AgentId:            agentID,
+		Force:              force,
+		RequirePermissions: requirePermissions,
 	})
 }
 
+// Ping checks that the daemon is serving requests and returns its clock
+func (c *Client) Ping(ctx context.Context) (time.Time, error) {
+	resp, err := c.daemon.Ping(ctx, &mapv1.PingRequest{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return resp.ServerTime.AsTime(), nil
+}
+
 // --- Worktree Methods ---
 
 // ListWorktrees returns all worktrees
@@ -190,6 +575,11 @@ func (c *Client) ListWorktrees(ctx context.Context, repoRoot string) ([]*mapv1.W
 	return resp.Worktrees, nil
 }
 
+// CreateWorktree creates a worktree for an agent spawned without one
+func (c *Client) CreateWorktree(ctx context.Context, req *mapv1.CreateWorktreeRequest) (*mapv1.CreateWorktreeResponse, error) {
+	return c.daemon.CreateWorktree(ctx, req)
+}
+
 // CleanupWorktrees removes orphaned worktrees
 func (c *Client) CleanupWorktrees(ctx context.Context, agentID string, all bool) (*mapv1.CleanupWorktreesResponse, error) {
 	return c.daemon.CleanupWorktrees(ctx, &mapv1.CleanupWorktreesRequest{
@@ -198,6 +588,18 @@ func (c *Client) CleanupWorktrees(ctx context.Context, agentID string, all bool)
 	})
 }
 
+// PruneBranches deletes leftover map/<agent-id> branches
+func (c *Client) PruneBranches(ctx context.Context, repoRoot string, dryRun bool) ([]string, error) {
+	resp, err := c.daemon.PruneBranches(ctx, &mapv1.PruneBranchesRequest{
+		RepoRoot: repoRoot,
+		DryRun:   dryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Branches, nil
+}
+
 // IsDaemonRunning checks if the daemon is running
 func IsDaemonRunning(socketPath string) bool {
 	if socketPath == "" {
@@ -211,3 +613,23 @@ func IsDaemonRunning(socketPath string) bool {
 	_ = conn.Close()
 	return true
 }
+
+// IsDaemonResponsive checks that the daemon is not only listening on its
+// socket but also answering RPCs within the given timeout
+func IsDaemonResponsive(socketPath string, timeout time.Duration) bool {
+	if !IsDaemonRunning(socketPath) {
+		return false
+	}
+
+	c, err := New(socketPath)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err = c.Ping(ctx)
+	return err == nil
+}
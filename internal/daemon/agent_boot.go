@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultBootTimeout is how long a new agent's CLI must stay running before
+// the spawn counts as successful
+const DefaultBootTimeout = 1 * time.Second
+
+// bootCheckInterval is how often a booting agent's pane is checked
+const bootCheckInterval = 250 * time.Millisecond
+
+// bootTailLines is how many of a dead pane's last lines go in the error
+const bootTailLines = 5
+
+// SetBootTimeout sets how long a new agent's CLI must stay running before
+// its spawn succeeds. Zero or negative skips the check.
+func (m *ProcessManager) SetBootTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bootTimeout = max(timeout, 0)
+}
+
+// waitForBoot watches a new agent's pane for timeout and fails if the CLI
+// exits in that time, e.g. because it isn't logged in. A failed session is
+// killed, and the error quotes the last lines the CLI printed.
+func waitForBoot(tmuxSession, cliBinary string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		if err := exec.Command("tmux", "has-session", "-t", tmuxSession).Run(); err != nil {
+			return fmt.Errorf("%s exited immediately after starting", cliBinary)
+		}
+		if IsTmuxPaneDead(tmuxSession) {
+			out, _ := exec.Command("tmux", "capture-pane", "-t", tmuxSession, "-p", "-J").Output()
+			_ = exec.Command("tmux", "kill-session", "-t", tmuxSession).Run()
+
+			err := fmt.Errorf("%s exited within %s of starting", cliBinary, timeout)
+			if tail := paneTail(string(out), bootTailLines); tail != "" {
+				err = fmt.Errorf("%w: %s", err, tail)
+			}
+			return err
+		}
+		if time.Now().After(deadline) {
+			return nil
+		}
+		time.Sleep(bootCheckInterval)
+	}
+}
+
+// paneTail returns the last n non-blank lines of pane content joined with
+// " | ", leaving out tmux's "Pane is dead" notice
+func paneTail(content string, n int) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "Pane is dead") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines[max(len(lines)-n, 0):], " | ")
+}
@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaneTail(t *testing.T) {
+	content := "Welcome\n\nError: invalid API key\n  Please run /login  \n\nPane is dead (status 1, Mon Jan  1 00:00:00 2024)\n\n"
+	if got, want := paneTail(content, 2), "Error: invalid API key | Please run /login"; got != want {
+		t.Errorf("paneTail = %q, want %q", got, want)
+	}
+	if got := paneTail("\n\n", 5); got != "" {
+		t.Errorf("paneTail of a blank pane = %q, want empty", got)
+	}
+}
+
+func TestSetBootTimeout(t *testing.T) {
+	m := NewProcessManager(t.TempDir(), nil)
+	if m.bootTimeout != DefaultBootTimeout {
+		t.Errorf("default boot timeout = %s, want %s", m.bootTimeout, DefaultBootTimeout)
+	}
+
+	m.SetBootTimeout(-time.Second)
+	if m.bootTimeout != 0 {
+		t.Errorf("negative boot timeout = %s, want 0 (off)", m.bootTimeout)
+	}
+	if err := waitForBoot("map-agent-missing", "claude", m.bootTimeout); err != nil {
+		t.Errorf("waitForBoot with the check off = %v, want nil", err)
+	}
+}
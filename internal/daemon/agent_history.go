@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"log"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Directions of a recorded agent message
+const (
+	AgentMessageSent     = "sent"     // daemon to agent
+	AgentMessageReceived = "received" // agent to daemon
+)
+
+// Kinds of recorded agent message
+const (
+	MessageKindInitialPrompt = "initial-prompt"
+	MessageKindTask          = "task"
+	MessageKindOffer         = "offer"
+	MessageKindNudge         = "nudge"
+	MessageKindInputResponse = "input-response"
+	MessageKindQuestion      = "question"
+	MessageKindBroadcast     = "broadcast"
+)
+
+// SetMessageStore makes the process manager record every prompt it sends
+// to an agent in store's agent history. Without a store nothing is recorded.
+func (m *ProcessManager) SetMessageStore(store *Store) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = store
+}
+
+// recordMessage adds a message to an agent's history. Failures are logged
+// rather than returned, since the history is an audit trail and must never
+// stop a prompt from being delivered.
+func (m *ProcessManager) recordMessage(agentID, taskID, kind, direction, content string) {
+	m.mu.RLock()
+	store := m.messages
+	m.mu.RUnlock()
+	if store == nil || agentID == "" {
+		return
+	}
+
+	err := store.CreateAgentMessage(&AgentMessageRecord{
+		AgentID:   agentID,
+		TaskID:    taskID,
+		Kind:      kind,
+		Direction: direction,
+		Content:   content,
+	})
+	if err != nil {
+		log.Printf("agent %s: failed to record %s message: %v", agentID, kind, err)
+	}
+}
+
+func agentMessageToProto(msg *AgentMessageRecord) *mapv1.AgentMessage {
+	return &mapv1.AgentMessage{
+		AgentId:   msg.AgentID,
+		TaskId:    msg.TaskID,
+		Kind:      msg.Kind,
+		Direction: msg.Direction,
+		Content:   msg.Content,
+		Timestamp: timestamppb.New(msg.CreatedAt),
+	}
+}
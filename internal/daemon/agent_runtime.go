@@ -0,0 +1,100 @@
+package daemon
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Agent runtime constants: where an agent's CLI process runs
+const (
+	RuntimeNative = "native"
+	RuntimeDocker = "docker"
+	RuntimePodman = "podman"
+)
+
+// SetRuntime selects where new agents' CLIs run. "native" (or empty) runs
+// them directly on the host; "docker" and "podman" run each one in its own
+// container from image, with the agent's working directory mounted at the
+// same path. Agents already running are not affected.
+func (m *ProcessManager) SetRuntime(runtime, image string) error {
+	switch runtime {
+	case "", RuntimeNative:
+		runtime, image = "", ""
+	case RuntimeDocker, RuntimePodman:
+		if image == "" {
+			return fmt.Errorf("agent runtime %s requires an image (agent.image)", runtime)
+		}
+	default:
+		return fmt.Errorf("unknown agent runtime %q (want native, docker, or podman)", runtime)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.runtime = runtime
+	m.image = image
+	return nil
+}
+
+// containerName is the name of the container an agent's CLI runs in
+func containerName(agentID string) string {
+	return tmuxPrefix + agentID
+}
+
+// containerCommand wraps cliCmd so it runs in a container of image, for use
+// as the agent's pane command. The working directory is mounted at the same
+// path so paths in tasks mean the same thing inside and out, and for a
+// worktree the repository's .git directory is mounted too so git works.
+// Files the agent writes stay owned by the invoking user. Variables in env
+// are passed through by name from the pane's environment, so their values
+// stay out of the command line.
+func containerCommand(runtime, image, agentID, workdir, repoRoot, cliCmd string, env map[string]string) string {
+	args := []string{runtime, "run", "--rm", "-it",
+		"--name", containerName(agentID),
+		"-v", workdir + ":" + workdir,
+		"-w", workdir,
+	}
+	if gitDir := repoRoot + "/.git"; repoRoot != "" && repoRoot != workdir {
+		args = append(args, "-v", gitDir+":"+gitDir)
+	}
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		args = append(args, "-e", name)
+	}
+	switch runtime {
+	case RuntimeDocker:
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	case RuntimePodman:
+		args = append(args, "--userns=keep-id")
+	}
+	args = append(args, image)
+
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ") + " " + cliCmd
+}
+
+// removeContainer force-removes an agent's container, which may outlive its
+// tmux session if the container CLI is killed before the agent exits
+func removeContainer(runtime, agentID string) error {
+	out, err := exec.Command(runtime, "rm", "-f", containerName(agentID)).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s rm: %w: %s", runtime, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell when it contains anything but
+// plainly safe characters
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
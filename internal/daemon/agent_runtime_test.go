@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSetRuntime(t *testing.T) {
+	m := NewProcessManager(t.TempDir(), nil)
+
+	if err := m.SetRuntime(RuntimeDocker, ""); err == nil {
+		t.Error("expected error for docker runtime without an image")
+	}
+	if err := m.SetRuntime("lxc", "img"); err == nil {
+		t.Error("expected error for unknown runtime")
+	}
+
+	if err := m.SetRuntime(RuntimePodman, "agent:latest"); err != nil {
+		t.Fatalf("SetRuntime: %v", err)
+	}
+	if m.runtime != RuntimePodman || m.image != "agent:latest" {
+		t.Errorf("runtime = %q, image = %q", m.runtime, m.image)
+	}
+
+	if err := m.SetRuntime(RuntimeNative, "ignored"); err != nil {
+		t.Fatalf("SetRuntime native: %v", err)
+	}
+	if m.runtime != "" || m.image != "" {
+		t.Errorf("native runtime should clear settings, got runtime = %q, image = %q", m.runtime, m.image)
+	}
+}
+
+func TestContainerCommand(t *testing.T) {
+	got := containerCommand(RuntimeDocker, "agent:latest", "claude-abc",
+		"/home/me/.mapd/worktrees/claude-abc", "/home/me/my repo", "claude --dangerously-skip-permissions",
+		map[string]string{"NPM_TOKEN": "secret", "AWS_PROFILE": "dev"})
+
+	want := strings.Join([]string{
+		"docker run --rm -it --name map-agent-claude-abc",
+		"-v /home/me/.mapd/worktrees/claude-abc:/home/me/.mapd/worktrees/claude-abc",
+		"-w /home/me/.mapd/worktrees/claude-abc",
+		"-v '/home/me/my repo/.git:/home/me/my repo/.git'",
+		"-e AWS_PROFILE -e NPM_TOKEN",
+		fmt.Sprintf("--user %d:%d", os.Getuid(), os.Getgid()),
+		"agent:latest claude --dangerously-skip-permissions",
+	}, " ")
+	if got != want {
+		t.Errorf("containerCommand =\n  %s\nwant\n  %s", got, want)
+	}
+
+	// An agent in the shared checkout mounts only the checkout
+	got = containerCommand(RuntimePodman, "agent", "a", "/repo", "/repo", "claude", nil)
+	if want := "podman run --rm -it --name map-agent-a -v /repo:/repo -w /repo --userns=keep-id agent claude"; got != want {
+		t.Errorf("containerCommand = %q, want %q", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain/path-1.0": "plain/path-1.0",
+		"":               "''",
+		"has space":      "'has space'",
+		"it's":           `'it'\''s'`,
+		"$HOME":          "'$HOME'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import "strings"
+
+// AgentTypeSpec describes how map drives one agent CLI. Supporting a new
+// CLI is a matter of adding its spec to agentTypeSpecs.
+type AgentTypeSpec struct {
+	// Name is the agent type, e.g. "claude"
+	Name string
+	// Binary is the CLI executable looked up in PATH
+	Binary string
+	// SkipPermissionsFlag makes the CLI run tools without asking for
+	// approval, so tasks execute unattended
+	SkipPermissionsFlag string
+	// ContinueFlag resumes the most recent conversation in the working
+	// directory ("" = the CLI has no equivalent)
+	ContinueFlag string
+	// HeadlessArgs builds the arguments for one non-interactive run of
+	// prompt, whose stdout is the result
+	HeadlessArgs func(prompt string, skipPermissions bool) []string
+	// ModelEnvVar names the environment variable the CLI reads its model
+	// from ("" = none)
+	ModelEnvVar string
+	// SubmitKeys are the tmux keys sent after a prompt's text to submit
+	// it, unless agent.<type>-submit-keys overrides them
+	SubmitKeys []string
+	// FirstNames and LastNames are the theme agent names are drawn from
+	FirstNames []string
+	LastNames  []string
+}
+
+// agentTypeSpecs are the supported agent CLIs. The first is the default.
+var agentTypeSpecs = []*AgentTypeSpec{
+	{
+		Name:                AgentTypeClaude,
+		Binary:              "claude",
+		SkipPermissionsFlag: "--dangerously-skip-permissions",
+		ContinueFlag:        "--continue",
+		HeadlessArgs:        promptFlagArgs("-p", "--dangerously-skip-permissions"),
+		ModelEnvVar:         "ANTHROPIC_MODEL",
+		SubmitKeys:          DefaultSubmitKeys,
+		FirstNames:          frenchFirstNames,
+		LastNames:           frenchLastNames,
+	},
+	{
+		Name:                AgentTypeCodex,
+		Binary:              "codex",
+		SkipPermissionsFlag: "--dangerously-bypass-approvals-and-sandbox",
+		HeadlessArgs: func(prompt string, skipPermissions bool) []string {
+			args := []string{"exec"}
+			if skipPermissions {
+				args = append(args, "--dangerously-bypass-approvals-and-sandbox")
+			}
+			return append(args, prompt)
+		},
+		SubmitKeys: DefaultSubmitKeys,
+		FirstNames: californiaFirstNames,
+		LastNames:  californiaLastNames,
+	},
+	{
+		Name:                AgentTypeGemini,
+		Binary:              "gemini",
+		SkipPermissionsFlag: "--yolo",
+		HeadlessArgs:        promptFlagArgs("-p", "--yolo"),
+		ModelEnvVar:         "GEMINI_MODEL",
+		SubmitKeys:          DefaultSubmitKeys,
+		FirstNames:          starFirstNames,
+		LastNames:           celestialLastNames,
+	},
+}
+
+// promptFlagArgs builds headless arguments for CLIs that take the prompt
+// as the value of promptFlag, followed by skipFlag when permissions are
+// skipped
+func promptFlagArgs(promptFlag, skipFlag string) func(string, bool) []string {
+	return func(prompt string, skipPermissions bool) []string {
+		args := []string{promptFlag, prompt}
+		if skipPermissions {
+			args = append(args, skipFlag)
+		}
+		return args
+	}
+}
+
+// LookupAgentType returns the spec for an agent type, or nil if the type
+// isn't supported
+func LookupAgentType(name string) *AgentTypeSpec {
+	for _, spec := range agentTypeSpecs {
+		if spec.Name == name {
+			return spec
+		}
+	}
+	return nil
+}
+
+// agentTypeSpec returns the spec for an agent type, falling back to the
+// default (claude) for "" and unknown types
+func agentTypeSpec(name string) *AgentTypeSpec {
+	if spec := LookupAgentType(name); spec != nil {
+		return spec
+	}
+	return agentTypeSpecs[0]
+}
+
+// AgentTypes returns the names of the supported agent types, default first
+func AgentTypes() []string {
+	names := make([]string, len(agentTypeSpecs))
+	for i, spec := range agentTypeSpecs {
+		names[i] = spec.Name
+	}
+	return names
+}
+
+// ValidAgentType reports whether agentType names a supported agent CLI
+func ValidAgentType(agentType string) bool {
+	return LookupAgentType(agentType) != nil
+}
+
+// AgentTypeChoices lists the supported agent types for error messages, e.g.
+// "'claude', 'codex', or 'gemini'"
+func AgentTypeChoices() string {
+	names := AgentTypes()
+	for i, name := range names {
+		names[i] = "'" + name + "'"
+	}
+	if len(names) < 3 {
+		return strings.Join(names, " or ")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1]
+}
+
+// InteractiveCommand returns the shell command that starts the CLI in an
+// agent's pane. continueSession is ignored for CLIs without ContinueFlag.
+func (spec *AgentTypeSpec) InteractiveCommand(skipPermissions, continueSession bool) string {
+	parts := []string{spec.Binary}
+	if continueSession && spec.ContinueFlag != "" {
+		parts = append(parts, spec.ContinueFlag)
+	}
+	if skipPermissions && spec.SkipPermissionsFlag != "" {
+		parts = append(parts, spec.SkipPermissionsFlag)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAgentTypes(t *testing.T) {
+	want := []string{AgentTypeClaude, AgentTypeCodex, AgentTypeGemini}
+	if got := AgentTypes(); !slices.Equal(got, want) {
+		t.Errorf("AgentTypes() = %v, want %v", got, want)
+	}
+	if got := AgentTypeChoices(); got != "'claude', 'codex', or 'gemini'" {
+		t.Errorf("AgentTypeChoices() = %q", got)
+	}
+
+	for _, name := range want {
+		if !ValidAgentType(name) {
+			t.Errorf("ValidAgentType(%q) = false, want true", name)
+		}
+	}
+	for _, name := range []string{"", "aider", "Claude"} {
+		if ValidAgentType(name) {
+			t.Errorf("ValidAgentType(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestAgentTypeSpecs(t *testing.T) {
+	for _, spec := range agentTypeSpecs {
+		if spec.Name == "" || spec.Binary == "" || spec.HeadlessArgs == nil {
+			t.Errorf("spec %q is missing its name, binary, or headless args", spec.Name)
+		}
+		if len(spec.FirstNames) == 0 || len(spec.LastNames) == 0 {
+			t.Errorf("spec %q has no name theme", spec.Name)
+		}
+	}
+
+	// Unknown and empty types fall back to claude, as spawning always has
+	for _, name := range []string{"", "aider"} {
+		if got := agentTypeSpec(name).Name; got != AgentTypeClaude {
+			t.Errorf("agentTypeSpec(%q) = %s, want claude", name, got)
+		}
+	}
+
+	models := map[string]string{AgentTypeClaude: "ANTHROPIC_MODEL", AgentTypeCodex: "", AgentTypeGemini: "GEMINI_MODEL"}
+	for name, want := range models {
+		if got := LookupAgentType(name).ModelEnvVar; got != want {
+			t.Errorf("%s model variable = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// psProcess is one line of ps output
+type psProcess struct {
+	pid, ppid int
+	cpu       float64 // percent of one core
+	rssKB     int64
+}
+
+// panePID returns the PID of the process running in a tmux session's pane,
+// or 0 if the pane's process has exited
+func panePID(tmuxSession string) (int, error) {
+	out, err := exec.Command("tmux", "display-message", "-t", tmuxSession, "-p", "#{pane_dead} #{pane_pid}").Output()
+	if err != nil {
+		return 0, fmt.Errorf("tmux session %s not found", tmuxSession)
+	}
+	dead, pid, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	if dead == "1" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(pid)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected pane pid %q", pid)
+	}
+	return n, nil
+}
+
+// listProcesses returns every process on the system with its CPU and
+// memory use, as ps reports them
+func listProcesses() ([]psProcess, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pid=,ppid=,pcpu=,rss=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("run ps: %w", err)
+	}
+	return parsePS(string(out)), nil
+}
+
+// parsePS parses "pid ppid pcpu rss" lines, skipping any it can't read
+func parsePS(out string) []psProcess {
+	var procs []psProcess
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		ppid, err2 := strconv.Atoi(fields[1])
+		cpu, err3 := strconv.ParseFloat(fields[2], 64)
+		rss, err4 := strconv.ParseInt(fields[3], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			continue
+		}
+		procs = append(procs, psProcess{pid: pid, ppid: ppid, cpu: cpu, rssKB: rss})
+	}
+	return procs
+}
+
+// treeUsage sums the CPU and memory of root and all its descendants
+func treeUsage(procs []psProcess, root int) (cpu float64, rssKB int64, count int) {
+	children := make(map[int][]int)
+	byPID := make(map[int]psProcess, len(procs))
+	for _, p := range procs {
+		children[p.ppid] = append(children[p.ppid], p.pid)
+		byPID[p.pid] = p
+	}
+
+	queue := []int{root}
+	seen := make(map[int]bool)
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = queue[1:]
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+
+		p, ok := byPID[pid]
+		if !ok {
+			continue
+		}
+		cpu += p.cpu
+		rssKB += p.rssKB
+		count++
+		queue = append(queue, children[pid]...)
+	}
+	return cpu, rssKB, count
+}
+
+// Usage samples the CPU and memory used by an agent's pane processes: the
+// CLI and everything it started. With agentID empty, every agent is
+// sampled. Agents that can't be sampled are returned with Error set.
+func (m *ProcessManager) Usage(agentID string) ([]*mapv1.AgentUsage, error) {
+	var slots []*AgentSlot
+	if agentID != "" {
+		slot := m.Get(agentID)
+		if slot == nil {
+			return nil, notFoundf("agent %s not found", agentID)
+		}
+		slots = []*AgentSlot{slot}
+	} else {
+		slots = m.List()
+		sort.Slice(slots, func(i, j int) bool { return slots[i].AgentID < slots[j].AgentID })
+	}
+
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]*mapv1.AgentUsage, 0, len(slots))
+	for _, slot := range slots {
+		u := &mapv1.AgentUsage{AgentId: slot.AgentID}
+		usage = append(usage, u)
+
+		switch {
+		case slot.Headless:
+			u.Error = "headless agents have no pane to sample"
+			continue
+		case slot.Runtime != "":
+			u.Error = fmt.Sprintf("runs in a container; see '%s stats %s'", slot.Runtime, containerName(slot.AgentID))
+			continue
+		}
+
+		pid, err := panePID(slot.TmuxSession)
+		if err != nil {
+			u.Error = err.Error()
+			continue
+		}
+		if pid == 0 {
+			u.Error = "agent process has exited"
+			continue
+		}
+
+		cpu, rssKB, count := treeUsage(procs, pid)
+		u.Pid = int32(pid)
+		u.CpuPercent = cpu
+		u.MemoryBytes = rssKB * 1024
+		u.Processes = int32(count)
+	}
+	return usage, nil
+}
@@ -0,0 +1,38 @@
+package daemon
+
+import "testing"
+
+func TestParsePS(t *testing.T) {
+	out := `    1     0  0.0  1024
+  100     1 12.5 20480
+  101   100  3.0   512
+garbage line
+  102   100  bad   100
+`
+	procs := parsePS(out)
+	if len(procs) != 3 {
+		t.Fatalf("expected 3 processes, got %d: %+v", len(procs), procs)
+	}
+	if procs[1] != (psProcess{pid: 100, ppid: 1, cpu: 12.5, rssKB: 20480}) {
+		t.Errorf("unexpected process: %+v", procs[1])
+	}
+}
+
+func TestTreeUsage(t *testing.T) {
+	procs := []psProcess{
+		{pid: 1, ppid: 0, cpu: 50, rssKB: 9999},
+		{pid: 10, ppid: 1, cpu: 10, rssKB: 1000}, // pane process
+		{pid: 11, ppid: 10, cpu: 5, rssKB: 200},  // child
+		{pid: 12, ppid: 11, cpu: 2.5, rssKB: 50}, // grandchild
+		{pid: 20, ppid: 1, cpu: 30, rssKB: 3000}, // unrelated
+	}
+
+	cpu, rss, count := treeUsage(procs, 10)
+	if cpu != 17.5 || rss != 1250 || count != 3 {
+		t.Errorf("treeUsage = %v, %d, %d; want 17.5, 1250, 3", cpu, rss, count)
+	}
+
+	if _, _, count := treeUsage(procs, 99); count != 0 {
+		t.Errorf("expected no processes for a missing root, got %d", count)
+	}
+}
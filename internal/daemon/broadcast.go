@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// Broadcast sends text to the session of every agent matching the filters:
+// idle agents only with idleOnly, agents carrying labels, and agents from
+// repoRoot when it is set. An agent with an in_progress or waiting_input
+// task in store isn't idle even when its slot is, as tmux agents' slots are
+// while they work (nil store = slot status only). Headless agents have no
+// session to type into, so they are skipped. Sessions are sent to in
+// parallel, each holding its send lock so the text never interleaves with a
+// task being dispatched.
+func (m *ProcessManager) Broadcast(ctx context.Context, store *Store, text string, idleOnly bool, labels map[string]string, repoRoot string) []*mapv1.BroadcastDelivery {
+	targets := broadcastTargets(m.List(), store, idleOnly, labels, repoRoot)
+	deliveries := make([]*mapv1.BroadcastDelivery, len(targets))
+
+	var wg sync.WaitGroup
+	for i, slot := range targets {
+		deliveries[i] = &mapv1.BroadcastDelivery{AgentId: slot.AgentID}
+
+		slot.mu.Lock()
+		session, agentType := slot.TmuxSession, slot.AgentType
+		slot.mu.Unlock()
+
+		wg.Add(1)
+		go func(d *mapv1.BroadcastDelivery) {
+			defer wg.Done()
+			if err := m.sendPrompt(ctx, session, agentType, text); err != nil {
+				d.Error = err.Error()
+				return
+			}
+			m.recordMessage(d.AgentId, "", MessageKindBroadcast, AgentMessageSent, text)
+		}(deliveries[i])
+	}
+	wg.Wait()
+
+	var delivered int
+	for _, d := range deliveries {
+		if d.Error == "" {
+			delivered++
+		}
+	}
+	log.Printf("broadcast delivered to %d of %d agent(s)", delivered, len(deliveries))
+	return deliveries
+}
+
+// broadcastTargets returns the slots a broadcast goes to, sorted by agent ID
+func broadcastTargets(slots []*AgentSlot, store *Store, idleOnly bool, labels map[string]string, repoRoot string) []*AgentSlot {
+	var targets []*AgentSlot
+	for _, slot := range slots {
+		if slot.Headless {
+			continue
+		}
+		if repoRoot != "" && slot.RepoRoot != repoRoot {
+			continue
+		}
+		if !slot.HasLabels(labels) {
+			continue
+		}
+		if idleOnly {
+			slot.mu.Lock()
+			idle := slot.Status == AgentStatusIdle
+			slot.mu.Unlock()
+			if !idle || (store != nil && store.AgentWorking(slot.AgentID)) {
+				continue
+			}
+		}
+		targets = append(targets, slot)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].AgentID < targets[j].AgentID })
+	return targets
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBroadcastTargets(t *testing.T) {
+	slots := []*AgentSlot{
+		{AgentID: "c", Status: AgentStatusBusy, RepoRoot: "/repo", Labels: map[string]string{"pool": "web"}},
+		{AgentID: "a", Status: AgentStatusIdle, RepoRoot: "/repo", Labels: map[string]string{"pool": "web"}},
+		{AgentID: "b", Status: AgentStatusIdle, RepoRoot: "/other"},
+	}
+
+	tests := []struct {
+		name     string
+		idleOnly bool
+		labels   map[string]string
+		repoRoot string
+		want     []string
+	}{
+		{"everyone", false, nil, "", []string{"a", "b", "c"}},
+		{"idle only", true, nil, "", []string{"a", "b"}},
+		{"by label", false, map[string]string{"pool": "web"}, "", []string{"a", "c"}},
+		{"by repo", false, nil, "/repo", []string{"a", "c"}},
+		{"idle in repo", true, nil, "/repo", []string{"a"}},
+		{"no match", false, map[string]string{"pool": "api"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, slot := range broadcastTargets(slots, nil, tt.idleOnly, tt.labels, tt.repoRoot) {
+				got = append(got, slot.AgentID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroadcastTargets_WorkingAgent(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	// Both slots show idle, as a tmux agent's does once its prompt is sent,
+	// but agent-b has a task in progress
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "task-1", Status: "in_progress", AssignedTo: "b", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	slots := []*AgentSlot{
+		{AgentID: "a", Status: AgentStatusIdle},
+		{AgentID: "b", Status: AgentStatusIdle},
+	}
+
+	var got []string
+	for _, slot := range broadcastTargets(slots, store, true, nil, "") {
+		got = append(got, slot.AgentID)
+	}
+	if !slices.Equal(got, []string{"a"}) {
+		t.Errorf("idle targets = %v, want [a]", got)
+	}
+}
+
+func TestProcessManager_BroadcastHeadless(t *testing.T) {
+	manager := NewProcessManager(t.TempDir(), nil)
+	manager.agents["headless"] = &AgentSlot{AgentID: "headless", Status: AgentStatusIdle, Headless: true}
+
+	// A headless agent has no session, so it isn't a target rather than a
+	// failed delivery
+	if deliveries := manager.Broadcast(context.Background(), nil, "wrap up", false, nil, ""); len(deliveries) != 0 {
+		t.Errorf("deliveries = %v, want none for the headless agent", deliveries)
+	}
+}
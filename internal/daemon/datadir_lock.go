@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// DataDirLockName is the lock file a daemon holds in its data directory
+const DataDirLockName = "mapd.lock"
+
+// dataDirLock is an exclusive lock on a data directory. Two daemons sharing
+// a data directory would contend for the SQLite file and disagree about
+// worktree state, so only the holder of the lock may use it.
+type dataDirLock struct {
+	file *os.File
+}
+
+// lockDataDir takes the data directory's lock, failing with the PID of the
+// holder if another daemon already has it. The lock is released when the
+// process exits, so a crashed daemon never leaves it stuck.
+func lockDataDir(dataDir string) (*dataDirLock, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("create data directory: %w", err)
+	}
+
+	path := filepath.Join(dataDir, DataDirLockName)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open data directory lock: %w", err)
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		holder := readLockPID(f)
+		_ = f.Close()
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("lock data directory: %w", err)
+		}
+		if holder > 0 {
+			return nil, fmt.Errorf("another daemon (pid %d) is already using data directory %s", holder, dataDir)
+		}
+		return nil, fmt.Errorf("another daemon is already using data directory %s", dataDir)
+	}
+
+	// Record our PID so a second daemon can say who holds the lock
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return &dataDirLock{file: f}, nil
+}
+
+// readLockPID returns the PID recorded in a lock file, or 0 if there is none
+func readLockPID(f *os.File) int {
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 64))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return pid
+}
+
+// Release gives up the lock. The file is left in place so the next daemon
+// locks the same inode.
+func (l *dataDirLock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+	_ = syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	_ = l.file.Close()
+	l.file = nil
+}
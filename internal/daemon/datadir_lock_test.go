@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLockDataDir(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := lockDataDir(dir)
+	if err != nil {
+		t.Fatalf("first lock: %v", err)
+	}
+
+	// flock locks belong to the open file, so a second open in the same
+	// process contends just like a second daemon would
+	if _, err := lockDataDir(dir); err == nil {
+		t.Fatal("second lock succeeded while the first was held")
+	} else if !strings.Contains(err.Error(), "another daemon") {
+		t.Errorf("error = %q, want it to mention another daemon", err)
+	}
+
+	first.Release()
+	first.Release() // releasing twice is harmless
+
+	second, err := lockDataDir(dir)
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	second.Release()
+}
+
+func TestLockDataDir_ReportsHolderPID(t *testing.T) {
+	dir := t.TempDir()
+
+	held, err := lockDataDir(dir)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	defer held.Release()
+
+	_, err = lockDataDir(dir)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if want := fmt.Sprintf("pid %d", os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %q, want it to contain %q", err, want)
+	}
+}
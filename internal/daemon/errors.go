@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors a client can act on carry a gRPC status code, so it can tell
+// classes of failure apart with status.Code(err) rather than matching
+// messages. Wrapping one with fmt.Errorf's %w keeps its code. Errors
+// without a code reach clients as codes.Unknown.
+
+// invalidArgumentf reports a request that is malformed or missing a field
+func invalidArgumentf(format string, a ...any) error {
+	return status.Errorf(codes.InvalidArgument, format, a...)
+}
+
+// notFoundf reports a task, agent, run, or schedule that doesn't exist
+func notFoundf(format string, a ...any) error {
+	return status.Errorf(codes.NotFound, format, a...)
+}
+
+// alreadyExistsf reports something the request would create that exists
+func alreadyExistsf(format string, a ...any) error {
+	return status.Errorf(codes.AlreadyExists, format, a...)
+}
+
+// failedPreconditionf reports a request that is valid but not in the
+// current state, e.g. cancelling a completed task or sending to a busy agent
+func failedPreconditionf(format string, a ...any) error {
+	return status.Errorf(codes.FailedPrecondition, format, a...)
+}
+
+// resourceExhaustedf reports a request over a configured limit
+func resourceExhaustedf(format string, a ...any) error {
+	return status.Errorf(codes.ResourceExhausted, format, a...)
+}
+
+// permissionDeniedf reports a request the daemon's security settings forbid
+func permissionDeniedf(format string, a ...any) error {
+	return status.Errorf(codes.PermissionDenied, format, a...)
+}
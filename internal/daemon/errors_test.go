@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCodes(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "done", Status: "completed", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	processes := newSchedulingTestManager(t, SchedulingRoundRobin)
+	processes.agents["agent-a"].Status = AgentStatusBusy
+	s := &Server{store: store, tasks: router, processes: processes}
+
+	_, cancelMissing := router.CancelTask("missing")
+	_, cancelDone := router.CancelTask("done")
+	_, executeBusy := processes.ExecuteTask(context.Background(), "agent-a", "t", "do it", nil)
+	_, noAgentID := s.GetAgentTask(context.Background(), &mapv1.GetAgentTaskRequest{})
+	badLogLevel := s.StreamLogs(&mapv1.StreamLogsRequest{MinLevel: "debug"}, nil)
+
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"cancel missing task", cancelMissing, codes.NotFound},
+		{"cancel completed task", cancelDone, codes.FailedPrecondition},
+		{"send to busy agent", executeBusy, codes.FailedPrecondition},
+		{"missing agent ID", noAgentID, codes.InvalidArgument},
+		{"invalid log level", badLogLevel, codes.InvalidArgument},
+		{"wrapped", fmt.Errorf("create agent: %w", alreadyExistsf("agent %s already exists", "a")), codes.AlreadyExists},
+	}
+	for _, tt := range tests {
+		if got := status.Code(tt.err); got != tt.want {
+			t.Errorf("%s: code = %v (err %v), want %v", tt.name, got, tt.err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// DefaultEventBuffer is how many events can wait to be broadcast before new
+// ones are dropped
+const DefaultEventBuffer = 1000
+
+// eventDropLogInterval is how often dropped events are reported in the log
+const eventDropLogInterval = time.Minute
+
+// eventDrops counts the events a server dropped since it started: full
+// because the event channel was full, watcher because a WatchEvents client
+// fell behind
+type eventDrops struct {
+	full    atomic.Int64
+	watcher atomic.Int64
+}
+
+// sendEvent queues event for broadcast without blocking. If the channel is
+// full the event is dropped and counted in drops (when set), since emitters
+// must never stall on a slow broadcaster.
+func sendEvent(ch chan<- *mapv1.Event, drops *eventDrops, event *mapv1.Event) {
+	if ch == nil {
+		return
+	}
+	select {
+	case ch <- event:
+	default:
+		if drops != nil {
+			drops.full.Add(1)
+		}
+	}
+}
+
+// eventDropReporter logs how many of drops' events were dropped since its
+// last report
+type eventDropReporter struct {
+	drops           *eventDrops
+	events, watcher int64
+}
+
+// report logs the drops since the last call, if there were any
+func (r *eventDropReporter) report() {
+	events, watcher := r.drops.full.Load(), r.drops.watcher.Load()
+	if events == r.events && watcher == r.watcher {
+		return
+	}
+	log.Printf("dropped %d event(s) on a full event channel and %d to slow watchers in the last %s (totals %d and %d); consider raising event-buffer",
+		events-r.events, watcher-r.watcher, eventDropLogInterval, events, watcher)
+	r.events, r.watcher = events, watcher
+}
@@ -0,0 +1,35 @@
+package daemon
+
+import (
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestSendEvent_CountsDrops(t *testing.T) {
+	var drops eventDrops
+	ch := make(chan *mapv1.Event, 1)
+
+	sendEvent(ch, &drops, &mapv1.Event{EventId: "first"})
+	sendEvent(ch, &drops, &mapv1.Event{EventId: "second"})
+	sendEvent(nil, &drops, &mapv1.Event{EventId: "no channel"})
+	sendEvent(ch, nil, &mapv1.Event{EventId: "uncounted"})
+
+	if got := (<-ch).EventId; got != "first" {
+		t.Errorf("queued event = %q, want first", got)
+	}
+	if dropped := drops.full.Load(); dropped != 1 {
+		t.Errorf("dropped events = %d, want 1 for the full channel only", dropped)
+	}
+}
+
+func TestEventDropReporter(t *testing.T) {
+	var drops eventDrops
+	r := &eventDropReporter{drops: &drops}
+
+	drops.watcher.Add(2)
+	r.report()
+	if r.watcher != 2 {
+		t.Errorf("reporter watcher count = %d, want 2", r.watcher)
+	}
+}
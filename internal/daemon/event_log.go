@@ -0,0 +1,115 @@
+package daemon
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// EventLog persists emitted events to the store so they survive daemon
+// restarts and can be queried after the fact. Writes happen on their own
+// goroutine so a slow database never delays delivery to live watchers.
+type EventLog struct {
+	store *Store
+	ch    chan *mapv1.Event
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewEventLog creates an event log backed by store
+func NewEventLog(store *Store) *EventLog {
+	return &EventLog{
+		store: store,
+		ch:    make(chan *mapv1.Event, 256),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// Start begins writing recorded events to the store
+func (l *EventLog) Start() {
+	go l.loop()
+}
+
+// Stop writes any queued events and stops the writer
+func (l *EventLog) Stop() {
+	close(l.stop)
+	<-l.done
+}
+
+// Record queues an event for persistence, assigning an ID and timestamp if
+// the emitter left them unset. It must be called before the event is shared
+// with other goroutines.
+func (l *EventLog) Record(event *mapv1.Event) {
+	if event.EventId == "" {
+		event.EventId = uuid.New().String()
+	}
+	if event.Timestamp == nil {
+		event.Timestamp = timestamppb.Now()
+	}
+
+	select {
+	case l.ch <- event:
+	default:
+		log.Printf("event log full, dropping event %s", event.EventId)
+	}
+}
+
+func (l *EventLog) loop() {
+	defer close(l.done)
+	for {
+		select {
+		case event := <-l.ch:
+			l.write(event)
+		case <-l.stop:
+			// Drain whatever was queued before shutdown
+			for {
+				select {
+				case event := <-l.ch:
+					l.write(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *EventLog) write(event *mapv1.Event) {
+	record, err := eventToRecord(event)
+	if err != nil {
+		log.Printf("failed to encode event %s: %v", event.EventId, err)
+		return
+	}
+	if err := l.store.CreateEvent(record); err != nil {
+		log.Printf("failed to persist event %s: %v", event.EventId, err)
+	}
+}
+
+// eventToRecord serializes an event for the events table
+func eventToRecord(event *mapv1.Event) (*EventRecord, error) {
+	payload, err := protojson.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return &EventRecord{
+		EventID:   event.EventId,
+		Type:      event.Type.String(),
+		TaskID:    event.GetTask().GetTaskId(),
+		Payload:   string(payload),
+		CreatedAt: event.Timestamp.AsTime(),
+	}, nil
+}
+
+// eventFromRecord decodes an event stored by eventToRecord
+func eventFromRecord(record *EventRecord) (*mapv1.Event, error) {
+	event := &mapv1.Event{}
+	if err := protojson.Unmarshal([]byte(record.Payload), event); err != nil {
+		return nil, fmt.Errorf("decode event %s: %w", record.EventID, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestEventLog_PersistsEvents(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	eventLog := NewEventLog(store)
+	eventLog.Start()
+
+	taskEvent := &mapv1.Event{
+		Type: mapv1.EventType_EVENT_TYPE_TASK_COMPLETED,
+		Payload: &mapv1.Event_Task{
+			Task: &mapv1.TaskEvent{TaskId: "task-1", AgentId: "claude-swift-fox"},
+		},
+	}
+	agentEvent := &mapv1.Event{
+		Payload: &mapv1.Event_Status{
+			Status: &mapv1.StatusEvent{Message: "agent claude-swift-fox connected"},
+		},
+	}
+	eventLog.Record(taskEvent)
+	eventLog.Record(agentEvent)
+
+	if taskEvent.EventId == "" || taskEvent.Timestamp == nil {
+		t.Error("Record should assign an event ID and timestamp")
+	}
+
+	// Stop drains queued events before returning
+	eventLog.Stop()
+
+	records, err := store.ListRecentEvents(10)
+	if err != nil {
+		t.Fatalf("ListRecentEvents failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d persisted events, want 2", len(records))
+	}
+
+	byID := make(map[string]*mapv1.Event)
+	for _, r := range records {
+		event, err := eventFromRecord(r)
+		if err != nil {
+			t.Fatalf("eventFromRecord failed: %v", err)
+		}
+		if r.Type != event.Type.String() {
+			t.Errorf("record type %q does not match event type %q", r.Type, event.Type)
+		}
+		if r.TaskID != event.GetTask().GetTaskId() {
+			t.Errorf("record task ID %q does not match event task ID %q", r.TaskID, event.GetTask().GetTaskId())
+		}
+		byID[event.EventId] = event
+	}
+
+	got := byID[taskEvent.EventId]
+	if got == nil {
+		t.Fatal("task event not persisted")
+	}
+	if got.GetTask().GetTaskId() != "task-1" || got.GetTask().GetAgentId() != "claude-swift-fox" {
+		t.Errorf("task payload = %v, want task-1/claude-swift-fox", got.GetTask())
+	}
+	if !got.Timestamp.AsTime().Equal(taskEvent.Timestamp.AsTime()) {
+		t.Errorf("timestamp = %v, want %v", got.Timestamp.AsTime(), taskEvent.Timestamp.AsTime())
+	}
+
+	if byID[agentEvent.EventId].GetStatus().GetMessage() != "agent claude-swift-fox connected" {
+		t.Errorf("status payload not round-tripped: %v", byID[agentEvent.EventId])
+	}
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ghAuthCacheTTL is how long a gh auth status result is reused, so callers
+// can check before every batch of gh calls without running gh each time,
+// while still noticing a 'gh auth login' within a minute
+const ghAuthCacheTTL = time.Minute
+
+// ghAuthChecker caches the result of gh auth status
+type ghAuthChecker struct {
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// ghAuth is the process-wide gh auth status cache
+var ghAuth = &ghAuthChecker{}
+
+// CheckGHAuth checks that gh is installed and logged in to GitHub, returning
+// an error that says how to fix it if not. The result is cached for a
+// minute.
+func CheckGHAuth(ctx context.Context, timeout time.Duration) error {
+	return ghAuth.check(ctx, timeout)
+}
+
+func (c *ghAuthChecker) check(ctx context.Context, timeout time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checked.IsZero() && time.Since(c.checked) < ghAuthCacheTTL {
+		return c.err
+	}
+	err := ghAuthStatus(ctx, timeout)
+	// A cancelled caller says nothing about gh, so don't remember it
+	if ctx.Err() != nil {
+		return err
+	}
+	c.checked, c.err = time.Now(), err
+	return err
+}
+
+// ghAuthStatus runs gh auth status
+func ghAuthStatus(ctx context.Context, timeout time.Duration) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return fmt.Errorf("gh CLI not found. Install it from https://cli.github.com/")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := exec.CommandContext(ctx, "gh", "auth", "status").Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("gh auth status timed out after %s", timeout)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("gh auth status cancelled: %w", ctx.Err())
+		}
+		return errors.New("gh is not logged in to GitHub: run 'gh auth login' (see 'gh auth status' for details)")
+	}
+	return nil
+}
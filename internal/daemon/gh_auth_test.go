@@ -0,0 +1,46 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGHAuthChecker(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "logged-in")
+	fakeBinary(t, "gh", `if [ "$1 $2" = "auth status" ] && [ ! -f "`+marker+`" ]; then
+  echo "You are not logged into any GitHub hosts." >&2; exit 1
+fi`)
+
+	var checker ghAuthChecker
+	err := checker.check(context.Background(), time.Second)
+	if err == nil || !strings.Contains(err.Error(), "gh auth login") {
+		t.Fatalf("check() = %v, want a hint to run gh auth login", err)
+	}
+
+	// The failure is cached until it expires
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.check(context.Background(), time.Second); err == nil {
+		t.Error("check() within the cache TTL should return the cached failure")
+	}
+
+	checker.checked = time.Now().Add(-ghAuthCacheTTL)
+	if err := checker.check(context.Background(), time.Second); err != nil {
+		t.Errorf("check() after login = %v, want nil", err)
+	}
+}
+
+func TestGHAuthChecker_NotInstalled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	var checker ghAuthChecker
+	err := checker.check(context.Background(), time.Second)
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("check() = %v, want gh not found", err)
+	}
+}
@@ -1,7 +1,9 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os/exec"
@@ -20,10 +22,25 @@ type GitHubPoller struct {
 	store     *Store
 	processes *ProcessManager
 	eventCh   chan *mapv1.Event
+	drops     *eventDrops // counts events dropped on eventCh (nil = uncounted)
 
 	mu       sync.Mutex
 	stop     chan struct{}
 	interval time.Duration
+
+	// ctx is cancelled on Stop so in-flight gh calls are abandoned;
+	// timeout bounds each individual gh call
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+
+	// authFailed records that polls are being skipped because gh isn't
+	// logged in, so it is reported once rather than every poll
+	authFailed bool
+
+	// onTaskDone, if set, is called after a task is completed because its
+	// issue was closed
+	onTaskDone func(taskID, agentID string)
 }
 
 // ghCommentAuthor represents the author of a GitHub comment
@@ -52,23 +69,50 @@ type ghIssueState struct {
 // inputRequestPrefix is the prefix we use when posting questions to GitHub
 const inputRequestPrefix = "**My agent needs more input:**"
 
-// tmuxPasteDelay is the delay after sending text to tmux before sending Enter
-// This allows long pastes to be processed before submission
+// DefaultGitHubTimeout bounds a single gh invocation so a hung call can't
+// stall the poll loop
+const DefaultGitHubTimeout = 30 * time.Second
+
+// tmuxPasteDelay is the delay after sending text to tmux before sending the
+// submit keys. This allows long pastes to be processed before submission
 const tmuxPasteDelay = 1 * time.Second
 
-// tmuxEnterDelay is the delay between Enter key presses
+// tmuxEnterDelay is the delay between submit key presses
 // Long pastes show as "[Pasted text #1 +N lines]" and need Enter to expand, then another to submit
 const tmuxEnterDelay = 500 * time.Millisecond
 
 // NewGitHubPoller creates a new GitHub poller
 func NewGitHubPoller(store *Store, processes *ProcessManager, eventCh chan *mapv1.Event) *GitHubPoller {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &GitHubPoller{
 		store:     store,
 		processes: processes,
 		eventCh:   eventCh,
 		stop:      make(chan struct{}),
 		interval:  30 * time.Second,
+		ctx:       ctx,
+		cancel:    cancel,
+		timeout:   DefaultGitHubTimeout,
+	}
+}
+
+// SetTimeout sets the per-call gh timeout. Non-positive values restore the
+// default.
+func (p *GitHubPoller) SetTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultGitHubTimeout
 	}
+	p.mu.Lock()
+	p.timeout = d
+	p.mu.Unlock()
+}
+
+// SetOnTaskDone sets a callback invoked with a task and its agent after the
+// task is completed because its issue was closed
+func (p *GitHubPoller) SetOnTaskDone(callback func(taskID, agentID string)) {
+	p.mu.Lock()
+	p.onTaskDone = callback
+	p.mu.Unlock()
 }
 
 // Start begins the polling loop
@@ -78,6 +122,7 @@ func (p *GitHubPoller) Start() {
 
 // Stop stops the polling loop
 func (p *GitHubPoller) Stop() {
+	p.cancel()
 	close(p.stop)
 }
 
@@ -102,24 +147,39 @@ func (p *GitHubPoller) poll() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	// Get all tasks waiting for input and check for responses
 	waitingTasks, err := p.store.ListTasksWaitingInput()
 	if err != nil {
 		log.Printf("github poller: failed to list waiting tasks: %v", err)
-	} else {
-		for _, task := range waitingTasks {
-			p.checkTaskForResponse(task)
-		}
 	}
-
-	// Get all in_progress tasks with GitHub sources and check if issues are closed
 	inProgressTasks, err := p.store.ListTasksInProgressWithGitHub()
 	if err != nil {
 		log.Printf("github poller: failed to list in_progress tasks: %v", err)
-	} else {
-		for _, task := range inProgressTasks {
-			p.checkTaskForClosedIssue(task)
+	}
+	if len(waitingTasks) == 0 && len(inProgressTasks) == 0 {
+		return
+	}
+
+	// Skip the poll rather than fail every gh call while gh is logged out
+	if err := CheckGHAuth(p.ctx, p.timeout); err != nil {
+		if !p.authFailed && p.ctx.Err() == nil {
+			log.Printf("github poller: skipping polls until gh works: %v", err)
+			p.authFailed = true
 		}
+		return
+	}
+	if p.authFailed {
+		log.Printf("github poller: gh is logged in again; resuming polls")
+		p.authFailed = false
+	}
+
+	// Check tasks waiting for input for responses
+	for _, task := range waitingTasks {
+		p.checkTaskForResponse(task)
+	}
+
+	// Check whether the issues of in_progress tasks have been closed
+	for _, task := range inProgressTasks {
+		p.checkTaskForClosedIssue(task)
 	}
 }
 
@@ -195,12 +255,9 @@ func (p *GitHubPoller) fetchGitHubComments(owner, repo string, issueNumber int)
 		"--json", "comments",
 	}
 
-	out, err := exec.Command("gh", args...).Output()
+	out, err := RunGH(p.ctx, p.timeout, args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return nil, fmt.Errorf("gh issue view failed: %s", string(exitErr.Stderr))
-		}
-		return nil, fmt.Errorf("gh issue view failed: %w", err)
+		return nil, err
 	}
 
 	var result ghIssueComments
@@ -231,6 +288,11 @@ func (p *GitHubPoller) checkTaskForClosedIssue(task *TaskRecord) {
 
 		// Emit completion event
 		p.emitTaskCompletedEvent(task)
+
+		// poll holds p.mu
+		if p.onTaskDone != nil {
+			p.onTaskDone(task.TaskID, task.AssignedTo)
+		}
 	}
 }
 
@@ -241,12 +303,9 @@ func (p *GitHubPoller) fetchGitHubIssueState(owner, repo string, issueNumber int
 		"--json", "state",
 	}
 
-	out, err := exec.Command("gh", args...).Output()
+	out, err := RunGH(p.ctx, p.timeout, args...)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("gh issue view failed: %s", string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("gh issue view failed: %w", err)
+		return "", err
 	}
 
 	var result ghIssueState
@@ -275,10 +334,7 @@ func (p *GitHubPoller) emitTaskCompletedEvent(task *TaskRecord) {
 		},
 	}
 
-	select {
-	case p.eventCh <- event:
-	default:
-	}
+	sendEvent(p.eventCh, p.drops, event)
 }
 
 func (p *GitHubPoller) deliverResponseToAgent(task *TaskRecord, response string) error {
@@ -286,7 +342,13 @@ func (p *GitHubPoller) deliverResponseToAgent(task *TaskRecord, response string)
 		return fmt.Errorf("task has no assigned agent")
 	}
 
-	tmuxSession := p.processes.GetTmuxSession(task.AssignedTo)
+	slot := p.processes.Get(task.AssignedTo)
+	if slot == nil {
+		return fmt.Errorf("agent %s has no tmux session", task.AssignedTo)
+	}
+	slot.mu.Lock()
+	tmuxSession, agentType := slot.TmuxSession, slot.AgentType
+	slot.mu.Unlock()
 	if tmuxSession == "" {
 		return fmt.Errorf("agent %s has no tmux session", task.AssignedTo)
 	}
@@ -294,34 +356,12 @@ func (p *GitHubPoller) deliverResponseToAgent(task *TaskRecord, response string)
 	// Format the response message
 	message := fmt.Sprintf("User response to your question:\n\n%s", response)
 
-	// Replace newlines for single-line tmux input
-	singleLineMessage := strings.ReplaceAll(message, "\n", " ")
-	singleLineMessage = strings.ReplaceAll(singleLineMessage, "  ", " ")
-
-	// Send to tmux session
-	cmd := exec.Command("tmux", "send-keys", "-t", tmuxSession, "-l", singleLineMessage)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to send response text: %w", err)
-	}
-
-	// Wait for pasted text to be processed (long text shows as collapsed paste)
-	time.Sleep(tmuxPasteDelay)
-
-	// Send Enter twice for long pastes:
-	// 1st Enter: confirms/expands the collapsed paste preview
-	// 2nd Enter: submits the prompt to the CLI
-	cmd = exec.Command("tmux", "send-keys", "-t", tmuxSession, "Enter")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to send first Enter: %w", err)
-	}
-
-	// Wait for paste to expand before sending second Enter
-	time.Sleep(tmuxEnterDelay)
-
-	cmd = exec.Command("tmux", "send-keys", "-t", tmuxSession, "Enter")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to send second Enter: %w", err)
+	// Send through the process manager so this can't interleave with other
+	// daemon-initiated sends to the same session
+	if err := p.processes.sendPrompt(context.Background(), tmuxSession, agentType, message); err != nil {
+		return fmt.Errorf("failed to send response: %w", err)
 	}
+	p.processes.recordMessage(task.AssignedTo, task.TaskID, MessageKindInputResponse, AgentMessageSent, message)
 
 	return nil
 }
@@ -344,14 +384,16 @@ func (p *GitHubPoller) emitInputReceivedEvent(task *TaskRecord) {
 		},
 	}
 
-	select {
-	case p.eventCh <- event:
-	default:
-	}
+	sendEvent(p.eventCh, p.drops, event)
 }
 
-// PostQuestionToGitHub posts an input request comment to a GitHub issue
-func PostQuestionToGitHub(owner, repo string, issueNumber int, question string) error {
+// PostQuestionToGitHub posts an input request comment to a GitHub issue,
+// giving up after timeout
+func PostQuestionToGitHub(ctx context.Context, timeout time.Duration, owner, repo string, issueNumber int, question string) error {
+	if err := CheckGHAuth(ctx, timeout); err != nil {
+		return err
+	}
+
 	body := fmt.Sprintf("%s %s", inputRequestPrefix, question)
 
 	args := []string{
@@ -360,10 +402,34 @@ func PostQuestionToGitHub(owner, repo string, issueNumber int, question string)
 		"--body", body,
 	}
 
-	out, err := exec.Command("gh", args...).CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("gh issue comment failed: %s: %s", err, string(out))
+	_, err := RunGH(ctx, timeout, args...)
+	return err
+}
+
+// RunGH runs gh with the given arguments and returns its stdout. The call is
+// killed if ctx is cancelled or timeout elapses, so a hung gh never blocks
+// the caller indefinitely.
+func RunGH(ctx context.Context, timeout time.Duration, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	op := "gh"
+	if len(args) >= 2 {
+		op = fmt.Sprintf("gh %s %s", args[0], args[1])
 	}
 
-	return nil
+	out, err := exec.CommandContext(ctx, "gh", args...).Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%s timed out after %s", op, timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s cancelled: %w", op, ctx.Err())
+		}
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%s failed: %s", op, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s failed: %w", op, err)
+	}
+	return out, nil
 }
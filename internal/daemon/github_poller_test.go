@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeBinary puts a shell script named name with the given body first on PATH
+func fakeBinary(t *testing.T, name, body string) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+		t.Fatalf("write fake %s: %v", name, err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunGH(t *testing.T) {
+	fakeBinary(t, "gh", `if [ "$1" = "fail" ]; then echo "boom" >&2; exit 1; fi
+if [ "$1" = "hang" ]; then exec sleep 10; fi
+echo '{"state":"OPEN"}'`)
+
+	out, err := RunGH(context.Background(), time.Second, "issue", "view")
+	if err != nil {
+		t.Fatalf("runGH: %v", err)
+	}
+	if !strings.Contains(string(out), "OPEN") {
+		t.Errorf("runGH output = %q", out)
+	}
+
+	_, err = RunGH(context.Background(), time.Second, "fail", "now")
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("RunGH(fail) error = %v, want stderr in message", err)
+	}
+
+	start := time.Now()
+	_, err = RunGH(context.Background(), 100*time.Millisecond, "hang", "now")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("RunGH(hang) error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("RunGH(hang) took %s, want it killed at the timeout", elapsed)
+	}
+}
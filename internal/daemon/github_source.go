@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// branchIssuePattern matches branch names that encode an issue number, such
+// as issue-42, issues/42, gh-42, or feature/issue_42-fix-login
+var branchIssuePattern = regexp.MustCompile(`(?i)(?:^|[/_-])(?:issues?|gh)[-_/]?(\d+)(?:$|[/_-])`)
+
+// ghPRClosingIssues is the response from gh pr view --json closingIssuesReferences
+type ghPRClosingIssues struct {
+	ClosingIssuesReferences []struct {
+		Number     int `json:"number"`
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	} `json:"closingIssuesReferences"`
+}
+
+// githubSource identifies the GitHub issue a task is linked to
+type githubSource struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// inferGitHubSource works out which GitHub issue the checkout at workdir is
+// for: first from an issue number in the branch name, then from the issues
+// closed by the branch's pull request. It is best-effort and returns false
+// whenever the source can't be determined.
+func inferGitHubSource(ctx context.Context, workdir string, timeout time.Duration) (githubSource, bool) {
+	branch := gitOutput(workdir, "rev-parse", "--abbrev-ref", "HEAD")
+	if branch == "" || branch == "HEAD" {
+		return githubSource{}, false
+	}
+
+	owner, repo := parseGitHubRemote(gitOutput(workdir, "remote", "get-url", "origin"))
+	if owner == "" {
+		return githubSource{}, false
+	}
+
+	if n := issueFromBranch(branch); n > 0 {
+		return githubSource{Owner: owner, Repo: repo, Number: n}, true
+	}
+
+	if CheckGHAuth(ctx, timeout) != nil {
+		return githubSource{}, false
+	}
+	out, err := RunGH(ctx, timeout, "pr", "view", branch, "--repo", owner+"/"+repo, "--json", "closingIssuesReferences")
+	if err != nil {
+		return githubSource{}, false
+	}
+	var pr ghPRClosingIssues
+	if err := json.Unmarshal(out, &pr); err != nil || len(pr.ClosingIssuesReferences) == 0 {
+		return githubSource{}, false
+	}
+
+	ref := pr.ClosingIssuesReferences[0]
+	src := githubSource{Owner: owner, Repo: repo, Number: ref.Number}
+	if ref.Repository.Owner.Login != "" && ref.Repository.Name != "" {
+		src.Owner = ref.Repository.Owner.Login
+		src.Repo = ref.Repository.Name
+	}
+	return src, true
+}
+
+// issueFromBranch returns the issue number encoded in a branch name, or 0
+func issueFromBranch(branch string) int {
+	m := branchIssuePattern.FindStringSubmatch(branch)
+	if m == nil {
+		return 0
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseGitHubRemote extracts owner and repo from a GitHub remote URL in
+// HTTPS (https://github.com/owner/repo.git) or SSH (git@github.com:owner/repo.git)
+// form. Non-GitHub remotes yield empty strings.
+func parseGitHubRemote(url string) (owner, repo string) {
+	url = strings.TrimSuffix(strings.TrimSpace(url), ".git")
+
+	var path string
+	switch {
+	case strings.HasPrefix(url, "git@github.com:"):
+		path = strings.TrimPrefix(url, "git@github.com:")
+	case strings.HasPrefix(url, "ssh://git@github.com/"):
+		path = strings.TrimPrefix(url, "ssh://git@github.com/")
+	case strings.HasPrefix(url, "https://github.com/"):
+		path = strings.TrimPrefix(url, "https://github.com/")
+	default:
+		return "", ""
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// gitOutput runs a git command in dir and returns its trimmed output, or ""
+// if it fails
+func gitOutput(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,50 @@
+package daemon
+
+import "testing"
+
+func TestIssueFromBranch(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   int
+	}{
+		{"issue-42", 42},
+		{"issues/42", 42},
+		{"gh-7", 7},
+		{"feature/issue_42-fix-login", 42},
+		{"Issue42", 42},
+		{"fix/ISSUE-100", 100},
+		{"main", 0},
+		{"map/claude-swift-fox", 0},
+		{"tissue-42", 0},
+		{"release-1.2", 0},
+	}
+
+	for _, tt := range tests {
+		if got := issueFromBranch(tt.branch); got != tt.want {
+			t.Errorf("issueFromBranch(%q) = %d, want %d", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestParseGitHubRemote(t *testing.T) {
+	tests := []struct {
+		url       string
+		wantOwner string
+		wantRepo  string
+	}{
+		{"https://github.com/pmarsceill/mapcli.git", "pmarsceill", "mapcli"},
+		{"https://github.com/pmarsceill/mapcli", "pmarsceill", "mapcli"},
+		{"git@github.com:pmarsceill/mapcli.git\n", "pmarsceill", "mapcli"},
+		{"ssh://git@github.com/pmarsceill/mapcli.git", "pmarsceill", "mapcli"},
+		{"https://gitlab.com/pmarsceill/mapcli.git", "", ""},
+		{"https://github.com/pmarsceill", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		owner, repo := parseGitHubRemote(tt.url)
+		if owner != tt.wantOwner || repo != tt.wantRepo {
+			t.Errorf("parseGitHubRemote(%q) = (%q, %q), want (%q, %q)", tt.url, owner, repo, tt.wantOwner, tt.wantRepo)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// headlessCommand returns the binary and arguments that run one
+// non-interactive agent invocation for prompt
+func headlessCommand(agentType string, skipPermissions bool, prompt string) (string, []string) {
+	spec := agentTypeSpec(agentType)
+	return spec.Binary, spec.HeadlessArgs(prompt, skipPermissions)
+}
+
+// CreateHeadlessSlot registers an output-only agent. Instead of a long-lived
+// tmux session, each task runs as a separate non-interactive CLI invocation
+// whose output becomes the task result, so no terminal multiplexer is needed.
+// The CLI runs on the host, so headless agents are refused when a container
+// runtime is configured.
+func (m *ProcessManager) CreateHeadlessSlot(agentID, workdir, agentType, repoRoot string, skipPermissions bool, env map[string]string) (*AgentSlot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if agentType == "" {
+		agentType = AgentTypeClaude
+	}
+
+	if _, exists := m.agents[agentID]; exists {
+		return nil, alreadyExistsf("agent %s already exists", agentID)
+	}
+
+	// Headless runs aren't wrapped in a container, so running one would
+	// bypass the isolation the runtime was configured for
+	if m.runtime != "" {
+		return nil, failedPreconditionf("output-only agents can't run in the %s agent runtime; set agent.runtime to native or spawn a tmux agent", m.runtime)
+	}
+
+	binary, _ := headlessCommand(agentType, skipPermissions, "")
+	if _, err := exec.LookPath(binary); err != nil {
+		return nil, fmt.Errorf("%s CLI not found in PATH: %w", binary, err)
+	}
+
+	if m.logsDir != "" {
+		if err := os.MkdirAll(m.logsDir, 0755); err != nil {
+			return nil, fmt.Errorf("create logs dir: %w", err)
+		}
+	}
+
+	slot := &AgentSlot{
+		AgentID:      agentID,
+		WorktreePath: workdir,
+		CreatedAt:    time.Now(),
+		Status:       AgentStatusIdle,
+		AgentType:    agentType,
+		RepoRoot:     repoRoot,
+		Headless:     true,
+		Env:          maps.Clone(env),
+
+		SkipPermissions: skipPermissions,
+	}
+
+	m.agents[agentID] = slot
+	callback := m.onAgentAvailable
+
+	m.emitAgentEvent(slot, true)
+	log.Printf("created headless %s agent %s (workdir: %s)", binary, agentID, workdir)
+
+	if callback != nil {
+		go callback()
+	}
+
+	return slot, nil
+}
+
+// SpawnHeadless creates a headless slot and, if a prompt is given, runs it
+// in the background. The prompt's output is written to the agent's log file.
+func (m *ProcessManager) SpawnHeadless(agentID, workdir, prompt, agentType, repoRoot string, skipPermissions bool, env map[string]string) (*AgentSlot, error) {
+	slot, err := m.CreateHeadlessSlot(agentID, workdir, agentType, repoRoot, skipPermissions, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if prompt != "" {
+		slot.mu.Lock()
+		slot.Status = AgentStatusBusy
+		slot.mu.Unlock()
+
+		go func() {
+			defer m.releaseSlot(slot)
+			if _, err := m.runHeadless(context.Background(), slot, prompt); err != nil {
+				log.Printf("headless agent %s initial prompt failed: %v", agentID, err)
+			}
+		}()
+	}
+
+	return slot, nil
+}
+
+// runHeadless runs prompt as a single non-interactive invocation in the
+// slot's working directory and returns its stdout. Both streams are appended
+// to the agent's log file. The run is cancelled if ctx ends or the agent is
+// removed.
+func (m *ProcessManager) runHeadless(ctx context.Context, slot *AgentSlot, prompt string) (string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	slot.mu.Lock()
+	slot.cancelRun = cancel
+	agentType := slot.AgentType
+	skipPermissions := slot.SkipPermissions
+	workdir := slot.WorktreePath
+	env := slot.Env
+	slot.mu.Unlock()
+
+	defer func() {
+		slot.mu.Lock()
+		slot.cancelRun = nil
+		slot.mu.Unlock()
+	}()
+
+	binary, args := headlessCommand(agentType, skipPermissions, prompt)
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = workdir
+	cmd.Env = append(os.Environ(), envPairs(env)...)
+
+	var stdout, stderr bytes.Buffer
+	var logWriter io.Writer = io.Discard
+	if logFile := m.openHeadlessLog(slot.AgentID); logFile != nil {
+		defer func() { _ = logFile.Close() }()
+		_, _ = fmt.Fprintf(logFile, "--- %s run started\n", time.Now().Format(time.RFC3339))
+		logWriter = logFile
+	}
+	cmd.Stdout = io.MultiWriter(&stdout, logWriter)
+	cmd.Stderr = io.MultiWriter(&stderr, logWriter)
+
+	log.Printf("headless agent %s running %s", slot.AgentID, binary)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s: %w: %s", binary, err, truncateLog(msg, 500))
+		}
+		return stdout.String(), fmt.Errorf("%s: %w", binary, err)
+	}
+	return stdout.String(), nil
+}
+
+// headlessLogPath returns the log file a headless agent's output is written to
+func (m *ProcessManager) headlessLogPath(agentID string) string {
+	if m.logsDir == "" {
+		return ""
+	}
+	return filepath.Join(m.logsDir, agentID+".log")
+}
+
+// openHeadlessLog opens a headless agent's log file for appending, or
+// returns nil if it can't be opened
+func (m *ProcessManager) openHeadlessLog(agentID string) *os.File {
+	path := m.headlessLogPath(agentID)
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("headless agent %s: open log file: %v", agentID, err)
+		return nil
+	}
+	return f
+}
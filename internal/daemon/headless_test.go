@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHeadlessCommand(t *testing.T) {
+	tests := []struct {
+		agentType string
+		skip      bool
+		binary    string
+		args      []string
+	}{
+		{AgentTypeClaude, false, "claude", []string{"-p", "do it"}},
+		{AgentTypeClaude, true, "claude", []string{"-p", "do it", "--dangerously-skip-permissions"}},
+		{AgentTypeCodex, false, "codex", []string{"exec", "do it"}},
+		{AgentTypeCodex, true, "codex", []string{"exec", "--dangerously-bypass-approvals-and-sandbox", "do it"}},
+		{AgentTypeGemini, false, "gemini", []string{"-p", "do it"}},
+		{AgentTypeGemini, true, "gemini", []string{"-p", "do it", "--yolo"}},
+		{"", false, "claude", []string{"-p", "do it"}},
+	}
+
+	for _, tt := range tests {
+		binary, args := headlessCommand(tt.agentType, tt.skip, "do it")
+		if binary != tt.binary || !slices.Equal(args, tt.args) {
+			t.Errorf("headlessCommand(%s, %v) = %s %v, want %s %v", tt.agentType, tt.skip, binary, args, tt.binary, tt.args)
+		}
+	}
+}
+
+func TestProcessManager_HeadlessExecuteTask(t *testing.T) {
+	fakeBinary(t, "claude", `case "$2" in
+*fail*) echo "it broke" >&2; exit 3 ;;
+esac
+echo "done: $2"`)
+
+	logsDir := t.TempDir()
+	workdir := t.TempDir()
+	manager := NewProcessManager(logsDir, nil)
+
+	slot, err := manager.CreateHeadlessSlot("headless-1", workdir, AgentTypeClaude, "", false, nil)
+	if err != nil {
+		t.Fatalf("CreateHeadlessSlot failed: %v", err)
+	}
+	if !slot.Headless || slot.TmuxSession != "" {
+		t.Fatalf("slot = %+v, want headless without a tmux session", slot)
+	}
+
+	output, err := manager.ExecuteTask(context.Background(), "headless-1", "task-1", "write tests", nil)
+	if err != nil {
+		t.Fatalf("ExecuteTask failed: %v", err)
+	}
+	if !strings.Contains(output, "done: [Task ID: task-1]") || !strings.Contains(output, "write tests") {
+		t.Errorf("output = %q, want the agent's stdout for the task prompt", output)
+	}
+	if slot.Status != AgentStatusIdle {
+		t.Errorf("slot status after run = %q, want idle", slot.Status)
+	}
+
+	if _, err := manager.ExecuteTask(context.Background(), "headless-1", "task-2", "please fail", nil); err == nil || !strings.Contains(err.Error(), "it broke") {
+		t.Errorf("ExecuteTask error = %v, want stderr in the error", err)
+	}
+
+	logPath := manager.GetLogFile("headless-1")
+	logData, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read log %q: %v", logPath, err)
+	}
+	if !strings.Contains(string(logData), "write tests") || !strings.Contains(string(logData), "it broke") {
+		t.Errorf("log = %q, want output of both runs", logData)
+	}
+
+	if err := manager.NudgeTask(context.Background(), "headless-1", "task-1", "x", nil); err == nil {
+		t.Error("expected NudgeTask to fail for a headless agent")
+	}
+	if err := manager.RespawnInPane("headless-1", false, false); err == nil {
+		t.Error("expected RespawnInPane to fail for a headless agent")
+	}
+
+	info := slot.ToProto()
+	if !info.Headless || info.LogFile != "" {
+		t.Errorf("ToProto = %+v, want headless with no session", info)
+	}
+
+	manager.Remove("headless-1")
+	if manager.Get("headless-1") != nil {
+		t.Error("agent still registered after Remove")
+	}
+}
+
+func TestProcessManager_HeadlessRefusedInContainerRuntime(t *testing.T) {
+	fakeBinary(t, "claude", `echo done`)
+	manager := NewProcessManager(t.TempDir(), nil)
+	if err := manager.SetRuntime(RuntimeDocker, "agent:latest"); err != nil {
+		t.Fatalf("SetRuntime failed: %v", err)
+	}
+
+	_, err := manager.CreateHeadlessSlot("headless-1", t.TempDir(), AgentTypeClaude, "", false, nil)
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("CreateHeadlessSlot with docker runtime: err = %v, want FailedPrecondition", err)
+	}
+	if manager.Get("headless-1") != nil {
+		t.Error("headless agent was registered despite the container runtime")
+	}
+}
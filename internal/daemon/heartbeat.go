@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultHeartbeatInterval is how often a heartbeat event is emitted for
+// each live agent
+const DefaultHeartbeatInterval = time.Minute
+
+// SetHeartbeatInterval sets how often a heartbeat event is emitted for each
+// live agent. Zero or negative disables heartbeats. Heartbeats go out from
+// the monitor's loop, so an interval shorter than its tick is rounded up.
+func (m *InputMonitor) SetHeartbeatInterval(interval time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.heartbeatInterval = max(interval, 0)
+}
+
+// heartbeatDue reports whether heartbeats should be sent at now, recording
+// them as sent if so. Callers hold m.mu.
+func (m *InputMonitor) heartbeatDue(now time.Time) bool {
+	if m.heartbeatInterval <= 0 || now.Sub(m.lastHeartbeat) < m.heartbeatInterval {
+		return false
+	}
+	m.lastHeartbeat = now
+	return true
+}
+
+// emitHeartbeats sends a heartbeat event for every agent that is still
+// running. Agents whose tmux session has gone away are skipped, so watchers
+// see them stop heartbeating. Status and task come from the agent's active
+// task in the store, as a tmux agent's slot goes idle once a task's prompt
+// is sent.
+func (m *InputMonitor) emitHeartbeats(agents []*AgentSlot) {
+	if m.eventCh == nil {
+		return
+	}
+
+	slots := append([]*AgentSlot(nil), agents...)
+	sort.Slice(slots, func(i, j int) bool { return slots[i].AgentID < slots[j].AgentID })
+	for _, slot := range slots {
+		if !slot.Headless && exec.Command("tmux", "has-session", "-t", slot.TmuxSession).Run() != nil {
+			continue
+		}
+
+		slot.mu.Lock()
+		heartbeat := &mapv1.AgentHeartbeat{
+			AgentId:   slot.AgentID,
+			Status:    AgentStatusIdle,
+			AgentType: slot.AgentType,
+		}
+		slot.mu.Unlock()
+		if task, err := m.store.GetTaskByAgentID(slot.AgentID); err == nil && task != nil {
+			heartbeat.Status = AgentStatusBusy
+			heartbeat.TaskId = task.TaskID
+		}
+
+		sendEvent(m.eventCh, m.drops, &mapv1.Event{
+			EventId:   uuid.New().String(),
+			Type:      mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT,
+			Timestamp: timestamppb.Now(),
+			Payload:   &mapv1.Event_Heartbeat{Heartbeat: heartbeat},
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestInputMonitor_EmitHeartbeats(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	eventCh := make(chan *mapv1.Event, 10)
+	m := NewInputMonitor(store, nil, eventCh)
+
+	// agent-b's slot shows idle, as a tmux agent's does once its prompt is
+	// sent, but it has a task in progress
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "task-1", Status: "in_progress", AssignedTo: "agent-b", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	agents := []*AgentSlot{
+		{AgentID: "agent-b", Status: AgentStatusIdle, AgentType: AgentTypeCodex, Headless: true},
+		{AgentID: "agent-a", Status: AgentStatusIdle, AgentType: AgentTypeClaude, Headless: true},
+		// A tmux agent whose session is gone is not alive
+		{AgentID: "agent-dead", TmuxSession: "map-agent-heartbeat-test-missing", Status: AgentStatusIdle},
+	}
+
+	m.emitHeartbeats(agents)
+	close(eventCh)
+
+	var got []*mapv1.AgentHeartbeat
+	for event := range eventCh {
+		if event.Type != mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT {
+			t.Errorf("event type = %v, want heartbeat", event.Type)
+		}
+		if event.EventId == "" || event.Timestamp == nil {
+			t.Errorf("event missing ID or timestamp: %v", event)
+		}
+		got = append(got, event.GetHeartbeat())
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("got %d heartbeats, want 2: %v", len(got), got)
+	}
+	if got[0].AgentId != "agent-a" || got[0].Status != AgentStatusIdle || got[0].TaskId != "" || got[0].AgentType != AgentTypeClaude {
+		t.Errorf("first heartbeat = %v, want idle agent-a", got[0])
+	}
+	if got[1].AgentId != "agent-b" || got[1].Status != AgentStatusBusy || got[1].TaskId != "task-1" {
+		t.Errorf("second heartbeat = %v, want busy agent-b on task-1", got[1])
+	}
+}
+
+func TestInputMonitor_HeartbeatDue(t *testing.T) {
+	m := NewInputMonitor(nil, nil, nil)
+	now := time.Now()
+
+	// Disabled heartbeats are never due
+	m.SetHeartbeatInterval(-1)
+	if m.heartbeatDue(now) {
+		t.Fatal("heartbeat due while disabled")
+	}
+
+	m.SetHeartbeatInterval(time.Minute)
+	if !m.heartbeatDue(now) {
+		t.Fatal("first heartbeat not due")
+	}
+	if m.heartbeatDue(now.Add(30 * time.Second)) {
+		t.Error("heartbeat due again before the interval passed")
+	}
+	if !m.heartbeatDue(now.Add(time.Minute)) {
+		t.Error("heartbeat not due after the interval")
+	}
+}
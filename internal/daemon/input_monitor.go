@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"log"
 	"os/exec"
 	"regexp"
@@ -13,12 +14,15 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// InputMonitor watches tmux sessions for agents waiting on user input
-// and automatically posts questions to GitHub issues
+// InputMonitor watches tmux sessions of busy agents. Agents waiting on user
+// input have their questions posted to the question sinks (by default the
+// task's GitHub issue), and agents whose pane has been frozen without a
+// question are reported as stuck.
 type InputMonitor struct {
 	store     *Store
 	processes *ProcessManager
 	eventCh   chan *mapv1.Event
+	drops     *eventDrops // counts events dropped on eventCh (nil = uncounted)
 
 	mu       sync.Mutex
 	stop     chan struct{}
@@ -28,6 +32,16 @@ type InputMonitor struct {
 	lastContent    map[string]string    // agentID -> last captured content
 	lastChangeTime map[string]time.Time // agentID -> when content last changed
 	idleThreshold  time.Duration        // how long idle before considered waiting
+	stuckThreshold time.Duration        // how long idle with no question before considered stuck (0 = off)
+	stuckReported  map[string]bool      // agentID -> stuck event sent for the current content
+	questionPosted map[string]bool      // agentID -> question for the current content sent to sinks that don't reply
+
+	sinks []QuestionSink // where detected questions are posted
+
+	// heartbeatInterval is how often a heartbeat event is emitted for
+	// each live agent (0 = off)
+	heartbeatInterval time.Duration
+	lastHeartbeat     time.Time
 }
 
 // Patterns that suggest the agent is asking a question
@@ -67,7 +81,72 @@ func NewInputMonitor(store *Store, processes *ProcessManager, eventCh chan *mapv
 		lastContent:    make(map[string]string),
 		lastChangeTime: make(map[string]time.Time),
 		idleThreshold:  10 * time.Second, // Consider waiting if idle for 10s with question
+		stuckThreshold: DefaultStuckThreshold,
+		stuckReported:  make(map[string]bool),
+		questionPosted: make(map[string]bool),
+		sinks:          []QuestionSink{NewGitHubQuestionSink(DefaultGitHubTimeout)},
+
+		heartbeatInterval: DefaultHeartbeatInterval,
+	}
+}
+
+// DefaultStuckThreshold is how long a busy agent's pane may stay unchanged,
+// with no sign of activity or a question, before the agent is reported stuck
+const DefaultStuckThreshold = 10 * time.Minute
+
+// paneState classifies the content of a pane that has stopped changing
+type paneState int
+
+const (
+	// paneWorking shows activity, such as a spinner
+	paneWorking paneState = iota
+	// paneWaiting shows a question for the user
+	paneWaiting
+	// paneStuck shows neither, so the agent may be hung
+	paneStuck
+)
+
+// SetStuckThreshold sets how long a busy agent's pane may stay unchanged
+// before it is reported stuck. Zero disables stuck detection.
+func (m *InputMonitor) SetStuckThreshold(d time.Duration) {
+	m.mu.Lock()
+	m.stuckThreshold = max(d, 0)
+	m.mu.Unlock()
+}
+
+// SetQuestionSinks replaces where detected questions are posted. With no
+// sinks, questions are detected but not posted.
+func (m *InputMonitor) SetQuestionSinks(sinks ...QuestionSink) {
+	m.mu.Lock()
+	m.sinks = sinks
+	m.mu.Unlock()
+}
+
+// questionSinksFor returns the sinks that can take questions about task.
+// Callers hold m.mu.
+func (m *InputMonitor) questionSinksFor(task *TaskRecord) []QuestionSink {
+	var sinks []QuestionSink
+	for _, sink := range m.sinks {
+		if sink.Accepts(task) {
+			sinks = append(sinks, sink)
+		}
 	}
+	return sinks
+}
+
+// postQuestion sends a question to every sink, reporting whether any took
+// it and whether one of those will carry back an answer
+func postQuestion(task *TaskRecord, question string, sinks []QuestionSink) (delivered, replies bool) {
+	for _, sink := range sinks {
+		if err := sink.PostQuestion(context.Background(), task, question); err != nil {
+			log.Printf("input monitor: failed to post question for task %s to %s: %v", task.TaskID, sink.Name(), err)
+			continue
+		}
+		log.Printf("input monitor: posted question for task %s to %s", task.TaskID, sink.Name())
+		delivered = true
+		replies = replies || sink.Replies()
+	}
+	return delivered, replies
 }
 
 // Start begins the monitoring loop
@@ -107,15 +186,24 @@ func (m *InputMonitor) checkAllAgents() {
 		m.checkAgent(agent)
 	}
 
+	// Heartbeats ride on the same loop, so watchers can tell agents are
+	// still alive
+	if m.heartbeatDue(time.Now()) {
+		m.emitHeartbeats(agents)
+	}
+
 	// Clean up stale entries for agents that no longer exist
 	for id := range m.lastContent {
 		if !activeIDs[id] {
 			delete(m.lastContent, id)
 			delete(m.lastChangeTime, id)
+			delete(m.stuckReported, id)
 		}
 	}
 }
 
+// checkAgent looks at one agent's pane for questions or a stall. Callers
+// hold m.mu.
 func (m *InputMonitor) checkAgent(agent *AgentSlot) {
 	// Skip if no tmux session
 	if agent.TmuxSession == "" {
@@ -128,8 +216,10 @@ func (m *InputMonitor) checkAgent(agent *AgentSlot) {
 		return
 	}
 
-	// Skip if task doesn't have GitHub source
-	if task.GitHubOwner == "" || task.GitHubRepo == "" || task.GitHubIssueNumber == 0 {
+	// Without a sink for the task there is nowhere to post questions, so
+	// the pane is only worth watching for stuck detection
+	sinks := m.questionSinksFor(task)
+	if len(sinks) == 0 && m.stuckThreshold <= 0 {
 		return
 	}
 
@@ -155,6 +245,8 @@ func (m *InputMonitor) checkAgent(agent *AgentSlot) {
 	if content != lastContent {
 		m.lastContent[agent.AgentID] = content
 		m.lastChangeTime[agent.AgentID] = now
+		delete(m.stuckReported, agent.AgentID)
+		delete(m.questionPosted, agent.AgentID)
 		return // Content changed, not idle yet
 	}
 
@@ -170,21 +262,37 @@ func (m *InputMonitor) checkAgent(agent *AgentSlot) {
 		return // Not idle long enough
 	}
 
-	// Check if content suggests waiting for input
-	if m.isActivelyWorking(content) {
+	state, question := m.classifyPane(content)
+	switch state {
+	case paneWorking:
 		return // Agent appears to be working
+	case paneStuck:
+		if m.stuckThreshold > 0 && idleDuration >= m.stuckThreshold && !m.stuckReported[agent.AgentID] {
+			m.stuckReported[agent.AgentID] = true
+			log.Printf("input monitor: agent %s looks stuck: pane unchanged for %s on task %s",
+				agent.AgentID, idleDuration.Round(time.Second), task.TaskID)
+			m.emitStuckEvent(task, agent.AgentID)
+		}
+		return
 	}
 
-	question := m.extractQuestion(content)
-	if question == "" {
-		return // No question detected
+	if len(sinks) == 0 || m.questionPosted[agent.AgentID] {
+		return // Nowhere to post the question, or already posted
 	}
 
 	log.Printf("input monitor: detected question from agent %s: %s", agent.AgentID, truncateLog(question, 100))
 
-	// Post question to GitHub
-	if err := PostQuestionToGitHub(task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, question); err != nil {
-		log.Printf("input monitor: failed to post question to GitHub: %v", err)
+	delivered, replies := postQuestion(task, question, sinks)
+	if !delivered {
+		return
+	}
+	m.processes.recordMessage(agent.AgentID, task.TaskID, MessageKindQuestion, AgentMessageReceived, question)
+
+	// Without a sink to carry an answer back, the task keeps running and
+	// the user answers in the agent's session; the question isn't posted
+	// again until the pane changes
+	if !replies {
+		m.questionPosted[agent.AgentID] = true
 		return
 	}
 
@@ -200,9 +308,6 @@ func (m *InputMonitor) checkAgent(agent *AgentSlot) {
 
 	// Emit event
 	m.emitWaitingInputEvent(task, question)
-
-	log.Printf("input monitor: posted question to %s/%s#%d for task %s",
-		task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, task.TaskID)
 }
 
 func (m *InputMonitor) captureTmuxContent(session string) string {
@@ -215,6 +320,18 @@ func (m *InputMonitor) captureTmuxContent(session string) string {
 	return strings.TrimSpace(string(output))
 }
 
+// classifyPane decides whether unchanged pane content shows an agent that is
+// working, waiting on a question (returned too), or neither
+func (m *InputMonitor) classifyPane(content string) (paneState, string) {
+	if m.isActivelyWorking(content) {
+		return paneWorking, ""
+	}
+	if question := m.extractQuestion(content); question != "" {
+		return paneWaiting, question
+	}
+	return paneStuck, ""
+}
+
 func (m *InputMonitor) isActivelyWorking(content string) bool {
 	// Check last few lines for active work patterns
 	lines := strings.Split(content, "\n")
@@ -304,10 +421,28 @@ func (m *InputMonitor) emitWaitingInputEvent(task *TaskRecord, question string)
 		},
 	}
 
-	select {
-	case m.eventCh <- event:
-	default:
+	sendEvent(m.eventCh, m.drops, event)
+}
+
+func (m *InputMonitor) emitStuckEvent(task *TaskRecord, agentID string) {
+	if m.eventCh == nil {
+		return
+	}
+
+	event := &mapv1.Event{
+		EventId:   uuid.New().String(),
+		Type:      mapv1.EventType_EVENT_TYPE_AGENT_STUCK,
+		Timestamp: timestamppb.Now(),
+		Payload: &mapv1.Event_Task{
+			Task: &mapv1.TaskEvent{
+				TaskId:    task.TaskID,
+				NewStatus: taskStatusFromString(task.Status),
+				AgentId:   agentID,
+			},
+		},
 	}
+
+	sendEvent(m.eventCh, m.drops, event)
 }
 
 func truncateLog(s string, maxLen int) string {
@@ -0,0 +1,88 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestInputMonitor_ClassifyPane(t *testing.T) {
+	m := NewInputMonitor(nil, nil, nil)
+
+	tests := []struct {
+		name    string
+		content string
+		want    paneState
+	}{
+		{"spinner", "⠋ Thinking", paneWorking},
+		{"running a command", "Running go test ./...", paneWorking},
+		{"question", "I found two config files.\nWhich one should I update?", paneWaiting},
+		{"confirmation prompt", "Apply these changes? [Y/n]", paneWaiting},
+		{"frozen output", "Edited internal/api/handler.go\n> ", paneStuck},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, question := m.classifyPane(tt.content)
+			if got != tt.want {
+				t.Errorf("classifyPane = %v, want %v", got, tt.want)
+			}
+			if (got == paneWaiting) != (question != "") {
+				t.Errorf("classifyPane returned question %q for state %v", question, got)
+			}
+		})
+	}
+}
+
+func TestInputMonitor_SetStuckThreshold(t *testing.T) {
+	m := NewInputMonitor(nil, nil, nil)
+	if m.stuckThreshold != DefaultStuckThreshold {
+		t.Errorf("default stuck threshold = %s, want %s", m.stuckThreshold, DefaultStuckThreshold)
+	}
+
+	m.SetStuckThreshold(-1)
+	if m.stuckThreshold != 0 {
+		t.Errorf("negative threshold should disable stuck detection, got %s", m.stuckThreshold)
+	}
+}
+
+// acceptAllSink takes questions about any task
+type acceptAllSink struct{}
+
+func (acceptAllSink) Name() string                                            { return "test" }
+func (acceptAllSink) Accepts(*TaskRecord) bool                                { return true }
+func (acceptAllSink) PostQuestion(context.Context, *TaskRecord, string) error { return nil }
+func (acceptAllSink) Replies() bool                                           { return false }
+
+func TestInputMonitor_CheckAllAgents_AssignedTask(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	eventCh := make(chan *mapv1.Event, 10)
+	processes := NewProcessManager(t.TempDir(), nil)
+	processes.agents["agent-a"] = &AgentSlot{
+		AgentID:     "agent-a",
+		TmuxSession: "map-agent-input-monitor-test-missing",
+		Status:      AgentStatusBusy,
+	}
+	m := NewInputMonitor(store, processes, eventCh)
+	m.SetQuestionSinks(acceptAllSink{})
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "task-1", Status: "in_progress", AssignedTo: "agent-a", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.checkAllAgents()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("checkAllAgents did not return for an agent with an assigned task")
+	}
+
+}
@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// MaxLogBytes is the size at which the daemon's log file is rotated. The
+// full file is renamed to mapd.log.1, replacing any earlier one, so at most
+// twice this much log is kept.
+const MaxLogBytes = 10 * 1024 * 1024
+
+// rotatingFile is an append-only log file that is rotated once a write
+// would take it past maxBytes
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	f        *os.File
+	size     int64
+}
+
+// openRotatingFile opens path for appending, rotating it first if it is
+// already full
+func openRotatingFile(path string, maxBytes int64) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxBytes: maxBytes}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if r.size >= maxBytes {
+		if err := r.rotate(); err != nil {
+			_ = r.f.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.f, r.size = f, info.Size()
+	return nil
+}
+
+// rotate moves the current file to <path>.1 and starts a new one
+func (r *rotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		// Keep logging to the full file rather than losing output
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return r.open()
+}
+
+// Write appends p, rotating first if it would take the file past maxBytes.
+// A failed rotation is reported to stderr and the write still goes to the
+// current file.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.size+int64(len(p)) > r.maxBytes {
+		if err := r.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+	n, err := r.f.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LogFileName)
+	r, err := openRotatingFile(path, 20)
+	if err != nil {
+		t.Fatalf("openRotatingFile failed: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+
+	for _, line := range []string{"first line\n", "second line\n", "third\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	// The second line didn't fit, so the first was rotated out
+	old, err := os.ReadFile(path + ".1")
+	if err != nil {
+		t.Fatalf("read rotated file: %v", err)
+	}
+	if string(old) != "first line\n" {
+		t.Errorf("rotated file = %q, want the first line", old)
+	}
+	current, _ := os.ReadFile(path)
+	if string(current) != "second line\nthird\n" {
+		t.Errorf("log file = %q, want the later lines", current)
+	}
+}
+
+func TestRotatingFile_FullOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LogFileName)
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 30)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := openRotatingFile(path, 20)
+	if err != nil {
+		t.Fatalf("openRotatingFile failed: %v", err)
+	}
+	defer func() { _ = r.Close() }()
+	if _, err := r.Write([]byte("new\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if current, _ := os.ReadFile(path); string(current) != "new\n" {
+		t.Errorf("log file = %q, want a fresh file", current)
+	}
+	if old, _ := os.ReadFile(path + ".1"); len(old) != 30 {
+		t.Errorf("rotated file has %d bytes, want the 30 already written", len(old))
+	}
+}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log levels, from least to most severe. The daemon logs with the standard
+// log package, so levels are inferred from each message.
+const (
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// logHubBacklog is how many recent log lines the hub keeps for new
+// subscribers
+const logHubBacklog = 1000
+
+// LogEntry is one line of daemon log output
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+}
+
+// LogHub captures the daemon's log output so it can be streamed to clients.
+// It is an io.Writer meant to be added to the log package's output; it keeps
+// a backlog of recent lines and fans new lines out to subscribers.
+type LogHub struct {
+	mu      sync.Mutex
+	backlog []LogEntry
+	partial []byte
+	subs    map[chan LogEntry]struct{}
+}
+
+// NewLogHub creates an empty log hub
+func NewLogHub() *LogHub {
+	return &LogHub{subs: make(map[chan LogEntry]struct{})}
+}
+
+// Write records each complete line in p. Lines are split on newlines, so a
+// line written across several calls is recorded once it is finished.
+func (h *LogHub) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.partial = append(h.partial, p...)
+	for {
+		i := bytes.IndexByte(h.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(h.partial[:i])
+		h.partial = h.partial[i+1:]
+		if strings.TrimSpace(line) != "" {
+			h.publish(ParseLogLine(line, time.Now()))
+		}
+	}
+	return len(p), nil
+}
+
+// publish adds an entry to the backlog and sends it to subscribers. A
+// subscriber that isn't keeping up misses entries rather than blocking the
+// daemon's logging. Caller must hold h.mu.
+func (h *LogHub) publish(entry LogEntry) {
+	h.backlog = append(h.backlog, entry)
+	if len(h.backlog) > logHubBacklog {
+		h.backlog = h.backlog[len(h.backlog)-logHubBacklog:]
+	}
+	for ch := range h.subs {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// Subscribe returns the backlog of recent entries, oldest first, and a
+// channel of entries logged from now on. The returned func unsubscribes.
+func (h *LogHub) Subscribe() ([]LogEntry, <-chan LogEntry, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	recent := make([]LogEntry, len(h.backlog))
+	copy(recent, h.backlog)
+
+	ch := make(chan LogEntry, 256)
+	h.subs[ch] = struct{}{}
+	return recent, ch, func() {
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+	}
+}
+
+// ParseLogLine turns a line written by the log package into an entry,
+// taking the timestamp from the standard prefix when present
+func ParseLogLine(line string, now time.Time) LogEntry {
+	entry := LogEntry{Time: now, Message: line}
+	const stdPrefix = "2006/01/02 15:04:05"
+	if len(line) > len(stdPrefix) && line[len(stdPrefix)] == ' ' {
+		if t, err := time.ParseInLocation(stdPrefix, line[:len(stdPrefix)], time.Local); err == nil {
+			entry.Time = t
+			entry.Message = line[len(stdPrefix)+1:]
+		}
+	}
+	entry.Level = inferLogLevel(entry.Message)
+	return entry
+}
+
+// inferLogLevel guesses a message's level from its wording: warnings are
+// prefixed "warning", and errors mention a failure
+func inferLogLevel(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.HasPrefix(lower, "warning") || strings.Contains(lower, ": warning"):
+		return LogLevelWarn
+	case strings.Contains(lower, "failed") || strings.Contains(lower, "error") || strings.Contains(lower, "panic"):
+		return LogLevelError
+	default:
+		return LogLevelInfo
+	}
+}
+
+// logLevelRank orders levels by severity; unknown levels rank as info
+func logLevelRank(level string) int {
+	switch level {
+	case LogLevelWarn:
+		return 1
+	case LogLevelError:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// LogLevelAtLeast reports whether level is at least as severe as min
+func LogLevelAtLeast(level, min string) bool {
+	return logLevelRank(level) >= logLevelRank(min)
+}
+
+// ValidLogLevel reports whether level names a known log level
+func ValidLogLevel(level string) bool {
+	switch level {
+	case LogLevelInfo, LogLevelWarn, LogLevelError:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+
+	entry := ParseLogLine("2025/06/07 08:09:10 agent claude-a failed to send prompt", now)
+	if want := time.Date(2025, 6, 7, 8, 9, 10, 0, time.Local); !entry.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", entry.Time, want)
+	}
+	if entry.Message != "agent claude-a failed to send prompt" || entry.Level != LogLevelError {
+		t.Errorf("entry = %+v, want the message without its prefix at error level", entry)
+	}
+
+	entry = ParseLogLine("no timestamp here", now)
+	if !entry.Time.Equal(now) || entry.Message != "no timestamp here" || entry.Level != LogLevelInfo {
+		t.Errorf("entry = %+v, want the whole line at info level stamped now", entry)
+	}
+}
+
+func TestInferLogLevel(t *testing.T) {
+	tests := map[string]string{
+		"mapd listening on /tmp/mapd.sock":                    LogLevelInfo,
+		"warning: failed to kill tmux session map-agent-x":    LogLevelWarn,
+		"warm pool: warning: spawn slow":                      LogLevelWarn,
+		"auto-spawn: failed to spawn agent: boom":             LogLevelError,
+		"input monitor: error reading pane":                   LogLevelError,
+		"task 1 linked to acme/api#3 from its agent's branch": LogLevelInfo,
+	}
+	for msg, want := range tests {
+		if got := inferLogLevel(msg); got != want {
+			t.Errorf("inferLogLevel(%q) = %s, want %s", msg, got, want)
+		}
+	}
+
+	if !LogLevelAtLeast(LogLevelError, LogLevelWarn) || LogLevelAtLeast(LogLevelInfo, LogLevelWarn) {
+		t.Error("LogLevelAtLeast ordering is wrong")
+	}
+}
+
+func TestLogHub(t *testing.T) {
+	hub := NewLogHub()
+
+	// A line split across writes is recorded once complete
+	_, _ = hub.Write([]byte("2025/06/07 08:09:10 first"))
+	_, _ = hub.Write([]byte(" line\n2025/06/07 08:09:11 second\n"))
+
+	backlog, ch, unsubscribe := hub.Subscribe()
+	if len(backlog) != 2 || backlog[0].Message != "first line" || backlog[1].Message != "second" {
+		t.Fatalf("backlog = %+v, want the two complete lines", backlog)
+	}
+
+	_, _ = hub.Write([]byte("third\n"))
+	select {
+	case entry := <-ch:
+		if entry.Message != "third" {
+			t.Errorf("streamed %q, want third", entry.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber did not receive the new line")
+	}
+
+	unsubscribe()
+	_, _ = hub.Write([]byte("fourth\n"))
+	select {
+	case entry := <-ch:
+		t.Errorf("received %q after unsubscribing", entry.Message)
+	default:
+	}
+}
+
+func TestLogHub_BacklogIsBounded(t *testing.T) {
+	hub := NewLogHub()
+	for i := range logHubBacklog + 10 {
+		_, _ = fmt.Fprintf(hub, "line %d\n", i)
+	}
+
+	backlog, _, unsubscribe := hub.Subscribe()
+	defer unsubscribe()
+	if len(backlog) != logHubBacklog {
+		t.Fatalf("backlog has %d lines, want %d", len(backlog), logHubBacklog)
+	}
+	if backlog[0].Message != "line 10" {
+		t.Errorf("oldest line = %q, want line 10", backlog[0].Message)
+	}
+}
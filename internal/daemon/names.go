@@ -103,6 +103,32 @@ var californiaLastNames = []string{
 	"sawyer", "fletcher", "spencer", "tucker", "weaver",
 }
 
+// Star names for Gemini agents
+var starFirstNames = []string{
+	"castor", "pollux", "vega", "sirius", "rigel",
+	"altair", "deneb", "antares", "arcturus", "capella",
+	"procyon", "aldebaran", "spica", "regulus", "betelgeuse",
+	"bellatrix", "mira", "polaris", "electra", "maia",
+	"alcyone", "merope", "atlas", "mintaka", "alnitak",
+	"alnilam", "saiph", "canopus", "achernar", "hadar",
+	"fomalhaut", "mizar", "alcor", "dubhe", "merak",
+	"alkaid", "nunki", "shaula", "sadr", "algol",
+	"mirach", "hamal", "menkar", "zosma", "denebola",
+	"wezen", "adhara", "naos", "avior", "peacock",
+}
+
+// Celestial last names for Gemini agents
+var celestialLastNames = []string{
+	"nova", "nebula", "comet", "quasar", "pulsar",
+	"orbit", "zenith", "nadir", "aurora", "eclipse",
+	"equinox", "solstice", "meridian", "perigee", "apogee",
+	"corona", "parallax", "redshift", "halo", "cluster",
+	"galaxy", "meteor", "stardust", "horizon", "transit",
+	"lumen", "photon", "cosmos", "vortex", "ecliptic",
+	"magnitude", "azimuth", "syzygy", "umbra", "penumbra",
+	"binary", "dwarf", "giant", "zodiac", "aphelion",
+}
+
 // NameGenerator generates unique human-friendly names for agents
 type NameGenerator struct {
 	mu       sync.Mutex
@@ -123,16 +149,8 @@ func (ng *NameGenerator) GenerateName(agentType string) string {
 	ng.mu.Lock()
 	defer ng.mu.Unlock()
 
-	var firstNames, lastNames []string
-
-	switch agentType {
-	case AgentTypeCodex:
-		firstNames = californiaFirstNames
-		lastNames = californiaLastNames
-	default: // claude
-		firstNames = frenchFirstNames
-		lastNames = frenchLastNames
-	}
+	spec := agentTypeSpec(agentType)
+	firstNames, lastNames := spec.FirstNames, spec.LastNames
 
 	// Try to find an unused combination
 	maxAttempts := 100
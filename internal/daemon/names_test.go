@@ -48,6 +48,27 @@ func TestNameGenerator_GenerateName_Codex(t *testing.T) {
 	}
 }
 
+func TestNameGenerator_GenerateName_Gemini(t *testing.T) {
+	ng := NewNameGenerator()
+
+	name := ng.GenerateName(AgentTypeGemini)
+
+	// Should be in format firstname-lastname
+	parts := strings.Split(name, "-")
+	if len(parts) != 2 {
+		t.Errorf("expected name in format firstname-lastname, got %s", name)
+	}
+
+	// Should be using star names
+	if !slices.Contains(starFirstNames, parts[0]) {
+		t.Errorf("first name %s not in star first names list", parts[0])
+	}
+
+	if !slices.Contains(celestialLastNames, parts[1]) {
+		t.Errorf("last name %s not in celestial last names list", parts[1])
+	}
+}
+
 func TestNameGenerator_UniqueNames(t *testing.T) {
 	ng := NewNameGenerator()
 
@@ -0,0 +1,105 @@
+package daemon
+
+import (
+	"context"
+	"log"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// Agent lifecycle policies, applied when a task given to the agent reaches a
+// terminal state (map agent create --on-complete)
+const (
+	// AgentOnCompleteIdle returns the agent to the pool for more tasks
+	AgentOnCompleteIdle = "idle"
+	// AgentOnCompleteKeep leaves the agent running, for inspection, but
+	// gives it no more tasks
+	AgentOnCompleteKeep = "keep"
+	// AgentOnCompleteKill removes the agent and its worktree
+	AgentOnCompleteKill = "kill"
+)
+
+// validOnComplete reports whether policy is a known lifecycle policy ("" is
+// the default, idle)
+func validOnComplete(policy string) bool {
+	switch policy {
+	case "", AgentOnCompleteIdle, AgentOnCompleteKeep, AgentOnCompleteKill:
+		return true
+	}
+	return false
+}
+
+// singleTask reports whether the agent takes only one task: its policy ends
+// its time in the pool once that task finishes. Callers hold slot.mu.
+func (slot *AgentSlot) singleTask() bool {
+	return slot.OnComplete == AgentOnCompleteKeep || slot.OnComplete == AgentOnCompleteKill
+}
+
+// spent reports whether a single-task agent has already been given its
+// task. Tmux agents go idle as soon as a task's prompt is sent, so this
+// keeps them from being handed another while the first is still running.
+// Callers hold slot.mu.
+func (slot *AgentSlot) spent() bool {
+	return slot.singleTask() && slot.TasksRun > 0
+}
+
+// retireAgent marks an agent's single task as finished and returns its
+// lifecycle policy. It returns "" for agents that go back to the pool, and
+// for agents already retired, so a policy is applied only once.
+func (m *ProcessManager) retireAgent(agentID string) string {
+	slot := m.Get(agentID)
+	if slot == nil {
+		return ""
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if !slot.spent() || slot.retired {
+		return ""
+	}
+	slot.retired = true
+	return slot.OnComplete
+}
+
+// SetRemoveAgent sets the function that removes an agent whose policy is
+// kill once its task is done
+func (r *TaskRouter) SetRemoveAgent(remove func(agentID string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.removeAgent = remove
+}
+
+// applyOnComplete applies the lifecycle policy of the agent a task was given.
+// It is called where the task moves to completed, failed, or cancelled, with
+// r.mu not held.
+func (r *TaskRouter) applyOnComplete(taskID, agentID string) {
+	if r.spawned == nil || agentID == "" {
+		return
+	}
+
+	switch r.spawned.retireAgent(agentID) {
+	case AgentOnCompleteKeep:
+		log.Printf("agent %s finished task %s; keeping it without further tasks", agentID, taskID)
+	case AgentOnCompleteKill:
+		log.Printf("agent %s finished task %s; removing it", agentID, taskID)
+		r.mu.RLock()
+		remove := r.removeAgent
+		r.mu.RUnlock()
+		if remove != nil {
+			// Removing the agent emits task events and takes r.mu, so it
+			// can't run on the caller's path
+			go remove(agentID)
+		}
+	}
+}
+
+// removeFinishedAgent kills an agent whose policy is kill, now that its task
+// is done. Any task it picked up meanwhile is requeued.
+func (s *Server) removeFinishedAgent(agentID string) {
+	resp, err := s.KillAgent(context.Background(), &mapv1.KillAgentRequest{AgentId: agentID, RequeueTasks: true})
+	if err != nil {
+		log.Printf("failed to remove agent %s after its task: %v", agentID, err)
+	} else if !resp.GetSuccess() {
+		log.Printf("failed to remove agent %s after its task: %s", agentID, resp.GetMessage())
+	}
+}
@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// setupOnCompleteTest returns a server with a headless agent using policy
+// that has been given task-1, which is in the given status. Agents the
+// router retires with kill are removed through the server.
+func setupOnCompleteTest(t *testing.T, policy, taskStatus string) (*Server, *Store) {
+	t.Helper()
+	router, store, cleanup := setupTestTaskRouter(t)
+	t.Cleanup(cleanup)
+
+	processes := NewProcessManager(t.TempDir(), nil)
+	processes.agents["agent-a"] = &AgentSlot{
+		AgentID:    "agent-a",
+		Status:     AgentStatusIdle,
+		Headless:   true,
+		TasksRun:   1,
+		OnComplete: policy,
+	}
+	router.spawned = processes
+
+	now := time.Now()
+	record := &TaskRecord{TaskID: "task-1", Description: "fix the build", Status: taskStatus, AssignedTo: "agent-a", CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateTask(record); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	s := &Server{store: store, tasks: router, processes: processes, names: NewNameGenerator()}
+	router.SetRemoveAgent(s.removeFinishedAgent)
+	return s, store
+}
+
+func TestTaskRouter_OnComplete_Kill(t *testing.T) {
+	s, _ := setupOnCompleteTest(t, AgentOnCompleteKill, "in_progress")
+
+	if _, err := s.tasks.CancelTask("task-1"); err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.processes.Get("agent-a") != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("agent-a was not removed after its task was cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTaskRouter_OnComplete_Keep(t *testing.T) {
+	s, _ := setupOnCompleteTest(t, AgentOnCompleteKeep, "in_progress")
+
+	s.tasks.finishHeadlessTask("task-1", "agent-a", "", errors.New("exit status 1"))
+
+	slot := s.processes.Get("agent-a")
+	if slot == nil {
+		t.Fatal("agent-a was removed, want it kept")
+	}
+	if !slot.retired {
+		t.Error("agent-a is not marked retired")
+	}
+	if got := s.processes.FindAvailableAgent(&mapv1.Task{TaskId: "task-2"}); got != nil {
+		t.Errorf("FindAvailableAgent = %s, want no agent for a kept agent", got.AgentID)
+	}
+}
+
+func TestTaskRouter_OnComplete_Idle(t *testing.T) {
+	for _, policy := range []string{"", AgentOnCompleteIdle} {
+		s, _ := setupOnCompleteTest(t, policy, "in_progress")
+
+		s.tasks.finishHeadlessTask("task-1", "agent-a", "done", nil)
+
+		if got := s.processes.FindAvailableAgent(&mapv1.Task{TaskId: "task-2"}); got == nil || got.AgentID != "agent-a" {
+			t.Errorf("policy %q: FindAvailableAgent = %v, want agent-a back in the pool", policy, got)
+		}
+	}
+}
+
+func TestProcessManager_FindAvailableAgent_SingleTask(t *testing.T) {
+	m := NewProcessManager(t.TempDir(), nil)
+	m.agents["agent-a"] = &AgentSlot{AgentID: "agent-a", Status: AgentStatusIdle, OnComplete: AgentOnCompleteKill}
+
+	// A fresh single-task agent takes a task
+	if got := m.FindAvailableAgent(&mapv1.Task{TaskId: "task-1"}); got == nil {
+		t.Fatal("FindAvailableAgent = nil, want agent-a for its first task")
+	}
+
+	// Once given one it takes no more, though a tmux agent shows idle
+	m.agents["agent-a"].TasksRun = 1
+	if got := m.FindAvailableAgent(&mapv1.Task{TaskId: "task-2"}); got != nil {
+		t.Errorf("FindAvailableAgent = %s, want nil for a spent agent", got.AgentID)
+	}
+
+	// Its policy is applied only once
+	if got := m.retireAgent("agent-a"); got != AgentOnCompleteKill {
+		t.Errorf("retireAgent = %q, want %q", got, AgentOnCompleteKill)
+	}
+	if got := m.retireAgent("agent-a"); got != "" {
+		t.Errorf("second retireAgent = %q, want \"\"", got)
+	}
+}
+
+func TestServer_SpawnAgent_InvalidOnComplete(t *testing.T) {
+	s := &Server{processes: NewProcessManager(t.TempDir(), nil)}
+
+	_, err := s.SpawnAgent(context.Background(), &mapv1.SpawnAgentRequest{Count: 1, OnComplete: "forever"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SpawnAgent with on_complete forever: err = %v, want InvalidArgument", err)
+	}
+}
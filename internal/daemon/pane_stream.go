@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// paneStreamInterval is how often a followed pane is captured for changes
+const paneStreamInterval = 500 * time.Millisecond
+
+// paneAnchorLines is how many lines must line up to treat a capture as the
+// previous one scrolled, rather than a redrawn screen
+const paneAnchorLines = 5
+
+// capturePaneLines returns a pane's joined lines from start (a capture-pane
+// -S value, e.g. "-" for the whole history), without trailing blank lines
+func capturePaneLines(ctx context.Context, session, start string) ([]string, error) {
+	out, err := exec.CommandContext(ctx, "tmux", "capture-pane", "-t", session, "-p", "-J", "-S", start).Output()
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(out), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines, nil
+}
+
+// paneDelta returns the lines of cur that are new or changed since prev.
+// Lines that scrolled into the history stay put between captures, so after
+// lining cur up with prev only the lines past their common prefix are
+// returned. When tmux has trimmed the top of the history, cur is lined up
+// with the point in prev it now starts from. If the two can't be lined up
+// at all (e.g. the screen was cleared), all of cur is returned.
+func paneDelta(prev, cur []string) []string {
+	if len(prev) == 0 || len(cur) == 0 {
+		return cur
+	}
+
+	start := 0
+	if prev[0] != cur[0] {
+		start = -1
+		for d := 1; d < len(prev); d++ {
+			if linesMatch(prev[d:], cur) {
+				start = d
+				break
+			}
+		}
+		if start < 0 {
+			return cur
+		}
+	}
+
+	aligned := prev[start:]
+	common := 0
+	for common < len(aligned) && common < len(cur) && aligned[common] == cur[common] {
+		common++
+	}
+	return cur[common:]
+}
+
+// linesMatch reports whether a and b begin with the same lines, comparing
+// up to paneAnchorLines of them
+func linesMatch(a, b []string) bool {
+	n := min(len(a), len(b), paneAnchorLines)
+	for i := range n {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
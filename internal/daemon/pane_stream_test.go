@@ -0,0 +1,30 @@
+package daemon
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPaneDelta(t *testing.T) {
+	tests := []struct {
+		name string
+		prev []string
+		cur  []string
+		want []string
+	}{
+		{"first capture", nil, []string{"a", "b"}, []string{"a", "b"}},
+		{"unchanged", []string{"a", "b"}, []string{"a", "b"}, nil},
+		{"lines appended", []string{"a", "b"}, []string{"a", "b", "c", "d"}, []string{"c", "d"}},
+		{"last line redrawn", []string{"a", "b", "> typ"}, []string{"a", "b", "> typing"}, []string{"> typing"}},
+		{"history trimmed", []string{"a", "b", "c", "d"}, []string{"b", "c", "d", "e"}, []string{"e"}},
+		{"screen cleared", []string{"a", "b"}, []string{"x", "y"}, []string{"x", "y"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paneDelta(tt.prev, tt.cur); !slices.Equal(got, tt.want) {
+				t.Errorf("paneDelta = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
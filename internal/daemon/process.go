@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -20,9 +24,40 @@ type ProcessManager struct {
 	mu               sync.RWMutex
 	agents           map[string]*AgentSlot
 	eventCh          chan *mapv1.Event
+	drops            *eventDrops // counts events dropped on eventCh (nil = uncounted)
 	logsDir          string
-	lastAssigned     string // ID of last agent assigned a task (for round-robin)
-	onAgentAvailable func() // callback when an agent becomes available
+	scheduler        Scheduler // picks the agent for each routed task
+	onAgentAvailable func()    // callback when an agent becomes available
+
+	// How long to wait for an initial prompt to show up in the pane, and
+	// how many times to resend it if it doesn't
+	promptConfirmWait time.Duration
+	promptRetries     int
+
+	// submitKeys are the tmux keys that submit a typed prompt, by agent
+	// type (types not listed use their spec's SubmitKeys)
+	submitKeys map[string][]string
+
+	// bootTimeout is how long a new agent's CLI must stay running for its
+	// spawn to succeed (0 = don't check)
+	bootTimeout time.Duration
+
+	// runtime and image select a container engine and image new agents'
+	// CLIs run in ("" = run natively)
+	runtime string
+	image   string
+
+	// messages records prompts sent to agents, when set
+	messages *Store
+
+	// held are agents, by ID, kept for the first task that prefers them
+	// (SpawnAgentRequest.hold_for_task); they are given no other task first
+	held map[string]bool
+
+	// Per-session locks serializing daemon-initiated sends so multi-step
+	// text+Enter sequences to the same session never interleave
+	sendMu    sync.Mutex
+	sendLocks map[string]*sync.Mutex
 }
 
 // AgentSlot represents an agent running in a tmux session
@@ -33,10 +68,44 @@ type AgentSlot struct {
 	CreatedAt    time.Time
 	Status       string // "idle", "busy"
 	CurrentTask  string // current task ID if busy
-	AgentType    string // "claude" or "codex"
+	AgentType    string // "claude", "codex", or "gemini"
 	RepoRoot     string // git repository root the agent was spawned from
-
-	mu sync.Mutex
+	TasksRun     int    // number of tasks this agent has been given
+	// SkipPermissions records whether the agent was started with
+	// permission-bypassing flags, so respawns keep the same mode
+	SkipPermissions bool
+	// ContinueSession records whether a claude agent was started resuming
+	// its directory's previous conversation, so respawns resume it too
+	ContinueSession bool
+	// Headless agents have no tmux session; each task runs as a
+	// non-interactive CLI invocation whose output becomes the task result
+	Headless bool
+	// Labels are user-defined key/value tags set at spawn time
+	Labels map[string]string
+	// Isolated agents run in their own git worktree rather than the
+	// shared checkout
+	Isolated bool
+	// Multiplexer is the terminal multiplexer hosting TmuxSession, recorded
+	// per agent so its session is only ever driven by the tool that
+	// created it ("" for headless agents)
+	Multiplexer string
+	// Runtime is the container engine the agent's CLI runs in, from Image
+	// ("" when it runs natively)
+	Runtime string
+	Image   string
+	// Env holds variables copied from the host into the agent's session,
+	// so respawns and container runs keep them
+	Env map[string]string
+	// OfferTasks agents are offered tasks to accept or reject rather than
+	// being assigned them
+	OfferTasks bool
+	// OnComplete is the lifecycle policy applied once a task given to the
+	// agent finishes ("" or "idle", "keep", "kill")
+	OnComplete string
+
+	mu        sync.Mutex
+	retired   bool               // the OnComplete policy has been applied
+	cancelRun context.CancelFunc // cancels the running headless invocation
 }
 
 // AgentSlot status constants
@@ -49,20 +118,57 @@ const (
 const (
 	AgentTypeClaude = "claude"
 	AgentTypeCodex  = "codex"
+	AgentTypeGemini = "gemini"
+)
+
+// Scheduling strategy constants for FindAvailableAgent
+const (
+	SchedulingRoundRobin  = "round-robin"
+	SchedulingLeastLoaded = "least-loaded"
+	SchedulingRandom      = "random"
 )
 
 // tmux session prefix to avoid conflicts
 const tmuxPrefix = "map-agent-"
 
+// MultiplexerTmux is the terminal multiplexer agent sessions run in
+const MultiplexerTmux = "tmux"
+
 // NewProcessManager creates a new process manager
 func NewProcessManager(logsDir string, eventCh chan *mapv1.Event) *ProcessManager {
 	return &ProcessManager{
-		agents:  make(map[string]*AgentSlot),
-		eventCh: eventCh,
-		logsDir: logsDir,
+		agents:    make(map[string]*AgentSlot),
+		eventCh:   eventCh,
+		logsDir:   logsDir,
+		scheduler: &roundRobinScheduler{},
+		sendLocks: make(map[string]*sync.Mutex),
+		held:      make(map[string]bool),
+
+		promptConfirmWait: DefaultPromptConfirmWait,
+		promptRetries:     DefaultPromptRetries,
+		bootTimeout:       DefaultBootTimeout,
 	}
 }
 
+// SetSchedulingStrategy selects the built-in scheduler FindAvailableAgent
+// uses to pick among idle agents
+func (m *ProcessManager) SetSchedulingStrategy(strategy string) error {
+	scheduler, err := NewScheduler(strategy)
+	if err != nil {
+		return err
+	}
+	m.SetScheduler(scheduler)
+	return nil
+}
+
+// SetScheduler replaces the scheduler FindAvailableAgent uses to pick among
+// idle agents
+func (m *ProcessManager) SetScheduler(scheduler Scheduler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scheduler = scheduler
+}
+
 // SetOnAgentAvailable sets a callback that is invoked when an agent becomes available.
 // This is used to trigger processing of pending tasks.
 func (m *ProcessManager) SetOnAgentAvailable(callback func()) {
@@ -71,22 +177,28 @@ func (m *ProcessManager) SetOnAgentAvailable(callback func()) {
 	m.onAgentAvailable = callback
 }
 
-// CreateSlot creates a new agent with a tmux session running claude or codex
-// agentType should be "claude" (default) or "codex"
+// CreateSlot creates a new agent with a tmux session running claude, codex, or gemini
+// agentType should be "claude" (default), "codex", or "gemini"
 // If skipPermissions is true, the agent is started with permission-bypassing flags
 // repoRoot is the git repository root the agent was spawned from
-func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string, skipPermissions bool) (*AgentSlot, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
+// If continueSession is true, a claude agent resumes the most recent
+// conversation in workdir (claude --continue); other agent types ignore it
+// env is set in the tmux session's environment, where the CLI and any
+// respawn of it see it
+func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string, skipPermissions, continueSession bool, env map[string]string) (*AgentSlot, error) {
 	// Default to claude if not specified
 	if agentType == "" {
 		agentType = AgentTypeClaude
 	}
 
 	// Check if agent already exists
-	if _, exists := m.agents[agentID]; exists {
-		return nil, fmt.Errorf("agent %s already exists", agentID)
+	m.mu.RLock()
+	_, exists := m.agents[agentID]
+	bootTimeout := m.bootTimeout
+	runtime, image := m.runtime, m.image
+	m.mu.RUnlock()
+	if exists {
+		return nil, alreadyExistsf("agent %s already exists", agentID)
 	}
 
 	// Check if tmux is available
@@ -94,47 +206,54 @@ func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string
 		return nil, fmt.Errorf("tmux not found in PATH: %w", err)
 	}
 
-	// Determine CLI binary and flags based on agent type
-	var cliBinary, cliCmd string
-	switch agentType {
-	case AgentTypeCodex:
-		if _, err := exec.LookPath("codex"); err != nil {
-			return nil, fmt.Errorf("codex CLI not found in PATH: %w", err)
-		}
-		cliBinary = "codex"
-		if skipPermissions {
-			cliCmd = "codex --dangerously-bypass-approvals-and-sandbox"
-		} else {
-			cliCmd = "codex"
-		}
-	default: // claude
-		if _, err := exec.LookPath("claude"); err != nil {
-			return nil, fmt.Errorf("claude CLI not found in PATH: %w", err)
-		}
-		cliBinary = "claude"
-		if skipPermissions {
-			cliCmd = "claude --dangerously-skip-permissions"
-		} else {
-			cliCmd = "claude"
+	cliBinary, cliCmd := interactiveCommand(agentType, skipPermissions, continueSession)
+
+	// The CLI must be installed wherever it runs: on the host, or in the
+	// container image
+	if runtime != "" {
+		if _, err := exec.LookPath(runtime); err != nil {
+			return nil, fmt.Errorf("%s not found in PATH: %w", runtime, err)
 		}
+		cliCmd = containerCommand(runtime, image, agentID, workdir, repoRoot, cliCmd, env)
+	} else if _, err := exec.LookPath(cliBinary); err != nil {
+		return nil, fmt.Errorf("%s CLI not found in PATH: %w", cliBinary, err)
 	}
 
 	tmuxSession := tmuxPrefix + agentID
 
-	// Create tmux session with the agent CLI running in it
-	cmd := exec.Command("tmux", "new-session", "-d", "-s", tmuxSession, "-c", workdir, cliCmd)
+	// Create tmux session with the agent CLI running in it. remain-on-exit
+	// keeps the pane open if the agent exits (prevents accidental Ctrl+C from
+	// killing the session); it is set in the same tmux command so a CLI that
+	// exits right away still leaves its output behind for the boot check.
+	// Copied variables go in the session environment before the CLI
+	// starts, so it and any respawn of it see them. The pane waits in cat
+	// until they are set.
+	startCmd := cliCmd
+	if len(env) > 0 {
+		startCmd = "cat"
+	}
+	cmd := exec.Command("tmux", "new-session", "-d", "-s", tmuxSession, "-n", idleWindowTitle, "-c", workdir, startCmd,
+		";", "set-option", "-t", tmuxSession, "remain-on-exit", "on")
 	cmd.Env = os.Environ()
 	if err := cmd.Run(); err != nil {
 		return nil, fmt.Errorf("failed to create tmux session: %w", err)
 	}
+	if len(env) > 0 {
+		err := setTmuxEnvironment(tmuxSession, env)
+		if err == nil {
+			err = exec.Command("tmux", "respawn-pane", "-k", "-t", tmuxSession, "-c", workdir, cliCmd).Run()
+		}
+		if err != nil {
+			_ = exec.Command("tmux", "kill-session", "-t", tmuxSession).Run()
+			return nil, fmt.Errorf("failed to start agent with copied environment: %w", err)
+		}
+	}
 
 	// Configure tmux session for better resilience
 	// - mouse: enable scrolling
-	// - remain-on-exit: keep pane open if agent exits (prevents accidental Ctrl+C from killing session)
 	// - @map_cli_cmd: store the CLI command for respawn keybinding
 	// - bind R: respawn the agent with Ctrl+b R
 	_ = exec.Command("tmux", "set-option", "-t", tmuxSession, "mouse", "on").Run()
-	_ = exec.Command("tmux", "set-option", "-t", tmuxSession, "remain-on-exit", "on").Run()
 	_ = exec.Command("tmux", "set-option", "-t", tmuxSession, "@map_cli_cmd", cliCmd).Run()
 	_ = exec.Command("tmux", "bind-key", "-t", tmuxSession, "R", "respawn-pane", "-k", cliCmd).Run()
 
@@ -148,6 +267,16 @@ func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string
 	_ = exec.Command("tmux", "set-option", "-t", tmuxSession, "status-right-style", "bg=colour243,fg=colour255").Run()
 	_ = exec.Command("tmux", "set-option", "-t", tmuxSession, "window-status-current-style", "bg=colour245,fg=colour232,bold").Run()
 
+	// Fail the spawn if the CLI dies while starting, rather than leaving a
+	// dead agent behind
+	if err := waitForBoot(tmuxSession, cliBinary, bootTimeout); err != nil {
+		log.Printf("agent %s failed to start: %v", agentID, err)
+		if runtime != "" {
+			_ = removeContainer(runtime, agentID)
+		}
+		return nil, err
+	}
+
 	slot := &AgentSlot{
 		AgentID:      agentID,
 		WorktreePath: workdir,
@@ -156,12 +285,20 @@ func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string
 		Status:       AgentStatusIdle,
 		AgentType:    agentType,
 		RepoRoot:     repoRoot,
+		Multiplexer:  MultiplexerTmux,
+		Runtime:      runtime,
+		Image:        image,
+		Env:          maps.Clone(env),
+
+		SkipPermissions: skipPermissions,
+		ContinueSession: continueSession,
 	}
 
+	m.mu.Lock()
 	m.agents[agentID] = slot
-
 	// Capture callback before unlocking
 	callback := m.onAgentAvailable
+	m.mu.Unlock()
 
 	// Emit connected event
 	m.emitAgentEvent(slot, true)
@@ -176,6 +313,51 @@ func (m *ProcessManager) CreateSlot(agentID, workdir, agentType, repoRoot string
 	return slot, nil
 }
 
+// EnvNamePattern matches names that are safe to set in a shell environment
+var EnvNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validEnvName reports whether name can be used as an environment variable
+func validEnvName(name string) bool {
+	return EnvNamePattern.MatchString(name)
+}
+
+// setTmuxEnvironment sets env in a tmux session's environment. The values
+// are handed to tmux in a private command file rather than as arguments,
+// where any user could read them with ps.
+func setTmuxEnvironment(session string, env map[string]string) error {
+	f, err := os.CreateTemp("", "map-env-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	var script strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		fmt.Fprintf(&script, "set-environment -t %s %s %s\n", shellQuote(session), name, shellQuote(env[name]))
+	}
+	if _, err := f.WriteString(script.String()); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if out, err := exec.Command("tmux", "source-file", f.Name()).CombinedOutput(); err != nil {
+		return fmt.Errorf("set tmux environment: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// envPairs renders env as NAME=VALUE pairs sorted by name
+func envPairs(env map[string]string) []string {
+	pairs := make([]string, 0, len(env))
+	for _, name := range slices.Sorted(maps.Keys(env)) {
+		pairs = append(pairs, name+"="+env[name])
+	}
+	return pairs
+}
+
 // ExecuteTask sends a task to the agent's tmux session
 func (m *ProcessManager) ExecuteTask(ctx context.Context, agentID string, taskID string, description string, scopePaths []string) (string, error) {
 	m.mu.RLock()
@@ -183,86 +365,220 @@ func (m *ProcessManager) ExecuteTask(ctx context.Context, agentID string, taskID
 	m.mu.RUnlock()
 
 	if !exists {
-		return "", fmt.Errorf("agent %s not found", agentID)
+		return "", notFoundf("agent %s not found", agentID)
 	}
 
-	// Try to acquire the slot
+	// Try to acquire the slot. An agent that accepted an offer for the
+	// task is already reserved for it.
 	slot.mu.Lock()
-	if slot.Status == AgentStatusBusy {
+	if slot.Status == AgentStatusBusy && slot.CurrentTask != taskID {
 		slot.mu.Unlock()
-		return "", fmt.Errorf("agent %s is busy", agentID)
+		return "", failedPreconditionf("agent %s is busy", agentID)
 	}
 	slot.Status = AgentStatusBusy
 	slot.CurrentTask = taskID
+	slot.TasksRun++
 	tmuxSession := slot.TmuxSession
+	agentType := slot.AgentType
+	workdir := slot.WorktreePath
+	repoRoot := slot.RepoRoot
+	headless := slot.Headless
 	slot.mu.Unlock()
 
 	// Ensure we release the slot when done and notify about availability
-	defer func() {
-		slot.mu.Lock()
-		slot.Status = AgentStatusIdle
-		slot.CurrentTask = ""
-		slot.mu.Unlock()
+	defer m.releaseSlot(slot)
 
-		// Notify that an agent is available (for pending task processing)
-		m.mu.RLock()
-		callback := m.onAgentAvailable
-		m.mu.RUnlock()
-		if callback != nil {
-			go callback()
-		}
-	}()
+	prompt := buildTaskPrompt(agentID, taskID, description, scopePaths, workdir, repoRoot)
+	m.recordMessage(agentID, taskID, MessageKindTask, AgentMessageSent, prompt)
+
+	// Headless agents run the task to completion and return its output
+	if headless {
+		log.Printf("agent %s executing task %s headless", agentID, taskID)
+		return m.runHeadless(ctx, slot, prompt)
+	}
 
 	log.Printf("agent %s executing task %s via tmux", agentID, taskID)
 
-	// Build the prompt with task ID prefix for agent introspection
+	if err := m.sendPrompt(ctx, tmuxSession, agentType, prompt); err != nil {
+		log.Printf("agent %s task %s failed to send prompt: %v", agentID, taskID, err)
+		return "", fmt.Errorf("failed to send task to tmux: %w", err)
+	}
+
+	log.Printf("agent %s task %s sent to tmux session", agentID, taskID)
+
+	// Note: With tmux, we don't wait for completion or capture output
+	// The user interacts directly with the session
+	return "Task sent to agent's tmux session. Use 'map agent watch' to interact.", nil
+}
+
+// releaseSlot marks a slot idle after a task and notifies that an agent is
+// available for pending tasks
+func (m *ProcessManager) releaseSlot(slot *AgentSlot) {
+	slot.mu.Lock()
+	slot.Status = AgentStatusIdle
+	slot.CurrentTask = ""
+	slot.mu.Unlock()
+
+	m.mu.RLock()
+	callback := m.onAgentAvailable
+	m.mu.RUnlock()
+	if callback != nil {
+		go callback()
+	}
+}
+
+// NudgeTask re-sends a task's prompt to the agent already working on it,
+// without touching the slot's status. It is used to re-prompt an agent that
+// stalled or never received the original prompt.
+func (m *ProcessManager) NudgeTask(ctx context.Context, agentID, taskID, description string, scopePaths []string) error {
+	m.mu.RLock()
+	slot, exists := m.agents[agentID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return notFoundf("agent %s not found", agentID)
+	}
+
+	slot.mu.Lock()
+	tmuxSession := slot.TmuxSession
+	agentType := slot.AgentType
+	workdir := slot.WorktreePath
+	repoRoot := slot.RepoRoot
+	headless := slot.Headless
+	slot.mu.Unlock()
+
+	if headless {
+		return failedPreconditionf("agent %s is headless and cannot be nudged", agentID)
+	}
+
+	log.Printf("agent %s nudged with task %s", agentID, taskID)
+
+	prompt := buildTaskPrompt(agentID, taskID, description, scopePaths, workdir, repoRoot)
+	if err := m.sendPrompt(ctx, tmuxSession, agentType, prompt); err != nil {
+		return fmt.Errorf("failed to send task to tmux: %w", err)
+	}
+	m.recordMessage(agentID, taskID, MessageKindNudge, AgentMessageSent, prompt)
+	return nil
+}
+
+// buildTaskPrompt formats the prompt sent to an agent for a task: the task
+// ID prefix for agent introspection, the description, and any scope paths
+func buildTaskPrompt(agentID, taskID, description string, scopePaths []string, workdir, repoRoot string) string {
 	prompt := fmt.Sprintf("[Task ID: %s]\n\n%s", taskID, description)
 	if len(scopePaths) > 0 {
-		prompt = fmt.Sprintf("%s\n\nScope/files: %s", prompt, strings.Join(scopePaths, ", "))
+		found, missing := resolveScopePaths(workdir, repoRoot, scopePaths)
+		if len(found) > 0 {
+			prompt = fmt.Sprintf("%s\n\nScope/files: %s", prompt, strings.Join(found, ", "))
+		}
+		if len(missing) > 0 {
+			log.Printf("agent %s task %s: scope paths not found in %s: %s", agentID, taskID, workdir, strings.Join(missing, ", "))
+			prompt = fmt.Sprintf("%s\n\nScope/files not found in your working directory: %s", prompt, strings.Join(missing, ", "))
+		}
 	}
+	return prompt
+}
+
+// sessionSendLock returns the send lock for a tmux session, creating it if needed
+func (m *ProcessManager) sessionSendLock(tmuxSession string) *sync.Mutex {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+
+	lock, ok := m.sendLocks[tmuxSession]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.sendLocks[tmuxSession] = lock
+	}
+	return lock
+}
+
+// sendPrompt types text into a tmux session and submits it with the agent
+// type's submit keys. The whole text+keys sequence holds the session's send
+// lock, so concurrent daemon-initiated sends (task dispatch, initial
+// prompts, GitHub responses) are delivered one at a time instead of
+// interleaving.
+func (m *ProcessManager) sendPrompt(ctx context.Context, tmuxSession, agentType, text string) error {
+	keys := m.submitKeysFor(agentType)
+
+	lock := m.sessionSendLock(tmuxSession)
+	lock.Lock()
+	defer lock.Unlock()
 
-	// Send the prompt to the tmux session
 	// Replace newlines with spaces to keep as single-line input for the CLI
-	singleLinePrompt := strings.ReplaceAll(prompt, "\n", " ")
-	singleLinePrompt = strings.ReplaceAll(singleLinePrompt, "  ", " ") // collapse double spaces
+	singleLine := strings.ReplaceAll(text, "\n", " ")
+	singleLine = strings.ReplaceAll(singleLine, "  ", " ") // collapse double spaces
 
-	// Use tmux send-keys with -l (literal) flag to send text, then Enter separately
+	// Use tmux send-keys with -l (literal) flag to send text, then the submit keys separately
 	// This ensures the text is sent exactly as-is without tmux interpreting special chars
-	cmd := exec.CommandContext(ctx, "tmux", "send-keys", "-t", tmuxSession, "-l", singleLinePrompt)
+	cmd := exec.CommandContext(ctx, "tmux", "send-keys", "-t", tmuxSession, "-l", singleLine)
 	if err := cmd.Run(); err != nil {
-		log.Printf("agent %s task %s failed to send text: %v", agentID, taskID, err)
-		return "", fmt.Errorf("failed to send task to tmux: %w", err)
+		return fmt.Errorf("send text: %w", err)
 	}
 
 	// Wait for the pasted text to be processed by the terminal
 	// Long text may show as "[Pasted text #1 +N lines]" and need confirmation
 	time.Sleep(tmuxPasteDelay)
 
-	// Send Enter key twice for long pastes:
-	// 1st Enter: confirms/expands the collapsed paste preview
-	// 2nd Enter: submits the prompt to the CLI
-	// For short pastes, the first Enter submits and the second is harmless
-	cmd = exec.CommandContext(ctx, "tmux", "send-keys", "-t", tmuxSession, "Enter")
-	if err := cmd.Run(); err != nil {
-		log.Printf("agent %s task %s failed to send first Enter: %v", agentID, taskID, err)
-		return "", fmt.Errorf("failed to confirm paste in tmux: %w", err)
-	}
+	return PressSubmitKeys(ctx, tmuxSession, keys)
+}
 
-	// Wait for paste to expand before sending second Enter
-	time.Sleep(tmuxEnterDelay)
+// resubmitPrompt sends the agent type's submit keys again, for a prompt
+// whose text arrived but is still sitting in the CLI's input
+func (m *ProcessManager) resubmitPrompt(ctx context.Context, tmuxSession, agentType string) error {
+	keys := m.submitKeysFor(agentType)
 
-	// Second Enter to submit the prompt
-	cmd = exec.CommandContext(ctx, "tmux", "send-keys", "-t", tmuxSession, "Enter")
-	if err := cmd.Run(); err != nil {
-		log.Printf("agent %s task %s failed to send second Enter: %v", agentID, taskID, err)
-		return "", fmt.Errorf("failed to submit task to tmux: %w", err)
+	lock := m.sessionSendLock(tmuxSession)
+	lock.Lock()
+	defer lock.Unlock()
+
+	return PressSubmitKeys(ctx, tmuxSession, keys)
+}
+
+// PressSubmitKeys sends the submit keys to a tmux session one at a time,
+// giving the CLI a moment to react to each (e.g. expanding a collapsed paste
+// before it is submitted). Within the daemon, callers hold the session's
+// send lock.
+func PressSubmitKeys(ctx context.Context, tmuxSession string, keys []string) error {
+	for i, key := range keys {
+		if i > 0 {
+			time.Sleep(tmuxEnterDelay)
+		}
+		cmd := exec.CommandContext(ctx, "tmux", "send-keys", "-t", tmuxSession, key)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("send %s: %w", key, err)
+		}
 	}
+	return nil
+}
 
-	log.Printf("agent %s task %s sent to tmux session", agentID, taskID)
+// resolveScopePaths resolves task scope paths against an agent's working directory.
+// Relative paths are taken relative to workdir; absolute paths inside repoRoot are
+// rebased onto workdir so they point into the agent's worktree. Paths are returned
+// relative to workdir when possible, split into those that exist and those that don't.
+func resolveScopePaths(workdir, repoRoot string, scopePaths []string) (found, missing []string) {
+	for _, p := range scopePaths {
+		if p == "" {
+			continue
+		}
 
-	// Note: With tmux, we don't wait for completion or capture output
-	// The user interacts directly with the session
-	return "Task sent to agent's tmux session. Use 'map agent watch' to interact.", nil
+		display := filepath.Clean(p)
+		if filepath.IsAbs(display) && repoRoot != "" {
+			if rel, err := filepath.Rel(repoRoot, display); err == nil && !strings.HasPrefix(rel, "..") {
+				display = rel
+			}
+		}
+
+		full := display
+		if !filepath.IsAbs(full) && workdir != "" {
+			full = filepath.Join(workdir, full)
+		}
+
+		if _, err := os.Stat(full); err != nil {
+			missing = append(missing, display)
+			continue
+		}
+		found = append(found, display)
+	}
+	return found, missing
 }
 
 // GetTmuxSession returns the tmux session name for an agent
@@ -290,46 +606,110 @@ func (m *ProcessManager) HasTmuxSession(agentID string) bool {
 	return cmd.Run() == nil
 }
 
-// FindAvailableAgent finds an idle agent slot using round-robin selection
-func (m *ProcessManager) FindAvailableAgent() *AgentSlot {
+// FindAvailableAgent picks an idle agent for a task using the scheduler
+// (round-robin by default). task may be nil when any idle agent will do.
+func (m *ProcessManager) FindAvailableAgent(task *mapv1.Task) *AgentSlot {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if len(m.agents) == 0 {
-		return nil
-	}
-
-	// Get sorted list of agent IDs for consistent ordering
+	// Idle agents sorted by ID for consistent ordering
 	ids := make([]string, 0, len(m.agents))
 	for id := range m.agents {
 		ids = append(ids, id)
 	}
 	sort.Strings(ids)
 
-	// Find starting index (after lastAssigned)
-	startIdx := 0
-	if m.lastAssigned != "" {
-		for i, id := range ids {
-			if id == m.lastAssigned {
-				startIdx = (i + 1) % len(ids)
-				break
+	var idle []*AgentSlot
+	for _, id := range ids {
+		slot := m.agents[id]
+		// Agents that declined the task aren't offered it again
+		if slices.Contains(task.GetRejectedBy(), id) {
+			continue
+		}
+		// A held agent waits for the task that prefers it
+		if m.held[id] && task.GetPreferAgent() != id {
+			continue
+		}
+		slot.mu.Lock()
+		if slot.Status == AgentStatusIdle && !slot.spent() {
+			idle = append(idle, slot)
+		}
+		slot.mu.Unlock()
+	}
+	if len(idle) == 0 {
+		return nil
+	}
+
+	// A task that prefers an agent, e.g. the one that ran it before a
+	// retry, goes to that agent while it is idle
+	if preferred := task.GetPreferAgent(); preferred != "" {
+		for _, slot := range idle {
+			if slot.AgentID == preferred {
+				delete(m.held, preferred)
+				return slot
 			}
 		}
 	}
 
-	// Round-robin: check agents starting from startIdx
-	for i := 0; i < len(ids); i++ {
-		idx := (startIdx + i) % len(ids)
-		slot := m.agents[ids[idx]]
+	// A task for an agent type goes to an idle agent of that type if there
+	// is one, otherwise to any agent
+	if agentType := task.GetAgentType(); agentType != "" {
+		if typed := agentsOfType(idle, agentType); len(typed) > 0 {
+			idle = typed
+		}
+	}
+
+	// A read-only task goes to an agent in the shared checkout if one is
+	// idle, so worktree agents stay free for tasks that change files
+	if task.GetNoWorktree() {
+		if shared := sharedAgents(idle); len(shared) > 0 {
+			idle = shared
+		}
+	}
+
+	return m.scheduler.PickAgent(task, idle)
+}
+
+// Hold keeps an agent, which may not have been created yet, for the first
+// task that prefers it: until that task is routed, the agent is given no
+// other. This stops a new agent from taking an older queued task before the
+// one it was spawned for has been submitted.
+func (m *ProcessManager) Hold(agentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.held[agentID] = true
+}
+
+// Unhold releases an agent held with Hold
+func (m *ProcessManager) Unhold(agentID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.held, agentID)
+}
+
+// agentsOfType returns the agents in slots of the given type
+func agentsOfType(slots []*AgentSlot, agentType string) []*AgentSlot {
+	var typed []*AgentSlot
+	for _, slot := range slots {
+		if slot.AgentType == agentType {
+			typed = append(typed, slot)
+		}
+	}
+	return typed
+}
+
+// sharedAgents returns the agents in slots that work in the shared checkout
+// rather than a worktree of their own
+func sharedAgents(slots []*AgentSlot) []*AgentSlot {
+	var shared []*AgentSlot
+	for _, slot := range slots {
 		slot.mu.Lock()
-		if slot.Status == AgentStatusIdle {
-			m.lastAssigned = slot.AgentID
-			slot.mu.Unlock()
-			return slot
+		if !slot.Isolated {
+			shared = append(shared, slot)
 		}
 		slot.mu.Unlock()
 	}
-	return nil
+	return shared
 }
 
 // Remove removes an agent slot and kills its tmux session
@@ -339,14 +719,36 @@ func (m *ProcessManager) Remove(agentID string) {
 	if exists {
 		delete(m.agents, agentID)
 	}
+	delete(m.held, agentID)
 	m.mu.Unlock()
 
+	if exists && slot.Headless {
+		// Stop any invocation in progress; there is no session to kill
+		slot.mu.Lock()
+		if slot.cancelRun != nil {
+			slot.cancelRun()
+		}
+		slot.mu.Unlock()
+
+		m.emitAgentEvent(slot, false)
+		log.Printf("removed headless agent %s", agentID)
+		return
+	}
+
 	if exists {
 		// Kill the tmux session
-		cmd := exec.Command("tmux", "kill-session", "-t", slot.TmuxSession)
-		if err := cmd.Run(); err != nil {
+		if err := exec.Command("tmux", "kill-session", "-t", slot.TmuxSession).Run(); err != nil {
 			log.Printf("warning: failed to kill tmux session %s: %v", slot.TmuxSession, err)
 		}
+		if slot.Runtime != "" {
+			if err := removeContainer(slot.Runtime, agentID); err != nil {
+				log.Printf("warning: failed to remove container for agent %s: %v", agentID, err)
+			}
+		}
+
+		m.sendMu.Lock()
+		delete(m.sendLocks, slot.TmuxSession)
+		m.sendMu.Unlock()
 
 		m.emitAgentEvent(slot, false)
 		log.Printf("removed agent %s and killed tmux session %s", agentID, slot.TmuxSession)
@@ -360,9 +762,16 @@ func (m *ProcessManager) Get(agentID string) *AgentSlot {
 	return m.agents[agentID]
 }
 
-// GetLogFile returns empty string - tmux sessions don't use log files
+// GetLogFile returns the output log of a headless agent. Agents in tmux
+// sessions don't use log files, so they return an empty string.
 func (m *ProcessManager) GetLogFile(agentID string) string {
-	return ""
+	m.mu.RLock()
+	slot, ok := m.agents[agentID]
+	m.mu.RUnlock()
+	if !ok || !slot.Headless {
+		return ""
+	}
+	return m.headlessLogPath(agentID)
 }
 
 // GetLogsDir returns the logs directory path
@@ -398,6 +807,24 @@ func (m *ProcessManager) ListIdle() []string {
 	return idle
 }
 
+// CountByStatus returns the number of idle and busy agents. An agent with
+// an in_progress or waiting_input task in store counts as busy even when
+// its slot is idle, as tmux agents' slots are while they work (nil store =
+// slot status only).
+func (m *ProcessManager) CountByStatus(store *Store) (idle, busy int) {
+	for _, slot := range m.List() {
+		slot.mu.Lock()
+		slotIdle := slot.Status == AgentStatusIdle
+		slot.mu.Unlock()
+		if slotIdle && (store == nil || !store.AgentWorking(slot.AgentID)) {
+			idle++
+		} else {
+			busy++
+		}
+	}
+	return idle, busy
+}
+
 // ListRunning returns all agent IDs (for worktree cleanup compatibility)
 func (m *ProcessManager) ListRunning() map[string]bool {
 	m.mu.RLock()
@@ -425,11 +852,49 @@ func (m *ProcessManager) KillAll() error {
 	return nil
 }
 
+// HasLabels reports whether the agent carries every label in selector
+func (slot *AgentSlot) HasLabels(selector map[string]string) bool {
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	for k, v := range selector {
+		if got, ok := slot.Labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// SetWindowTitle renames an agent's tmux window, whose name shows in the
+// session's status bar. Headless agents have no window and are skipped.
+func (m *ProcessManager) SetWindowTitle(agentID, title string) {
+	slot := m.Get(agentID)
+	if slot == nil || slot.Headless || slot.TmuxSession == "" {
+		return
+	}
+	_ = exec.Command("tmux", "rename-window", "-t", slot.TmuxSession, title).Run()
+}
+
 // ToProto converts an AgentSlot to its proto representation
 func (slot *AgentSlot) ToProto() *mapv1.SpawnedAgentInfo {
 	slot.mu.Lock()
 	defer slot.mu.Unlock()
 
+	if slot.Headless {
+		return &mapv1.SpawnedAgentInfo{
+			AgentId:      slot.AgentID,
+			WorktreePath: slot.WorktreePath,
+			Status:       slot.Status,
+			CreatedAt:    timestamppb.New(slot.CreatedAt),
+			AgentType:    slot.AgentType,
+			RepoRoot:     slot.RepoRoot,
+			Headless:     true,
+			Labels:       slot.Labels,
+			OfferTasks:   slot.OfferTasks,
+			OnComplete:   slot.OnComplete,
+		}
+	}
+
 	return &mapv1.SpawnedAgentInfo{
 		AgentId:      slot.AgentID,
 		WorktreePath: slot.WorktreePath,
@@ -439,6 +904,10 @@ func (slot *AgentSlot) ToProto() *mapv1.SpawnedAgentInfo {
 		LogFile:      slot.TmuxSession, // Repurpose LogFile to show tmux session
 		AgentType:    slot.AgentType,
 		RepoRoot:     slot.RepoRoot,
+		Labels:       slot.Labels,
+		Multiplexer:  slot.Multiplexer,
+		OfferTasks:   slot.OfferTasks,
+		OnComplete:   slot.OnComplete,
 	}
 }
 
@@ -449,7 +918,9 @@ func (m *ProcessManager) emitAgentEvent(slot *AgentSlot, connected bool) {
 	}
 
 	message := fmt.Sprintf("agent %s disconnected", slot.AgentID)
-	if connected {
+	if connected && slot.Headless {
+		message = fmt.Sprintf("agent %s connected (headless)", slot.AgentID)
+	} else if connected {
 		message = fmt.Sprintf("agent %s connected (tmux: %s)", slot.AgentID, slot.TmuxSession)
 	}
 
@@ -462,19 +933,16 @@ func (m *ProcessManager) emitAgentEvent(slot *AgentSlot, connected bool) {
 		},
 	}
 
-	select {
-	case m.eventCh <- event:
-	default:
-		// Channel full, drop event
-	}
+	sendEvent(m.eventCh, m.drops, event)
 }
 
 // Spawn creates a slot and optionally sends an initial prompt
-// agentType should be "claude" (default) or "codex"
+// agentType should be "claude" (default), "codex", or "gemini"
 // If skipPermissions is true, the agent is started with permission-bypassing flags
 // repoRoot is the git repository root the agent was spawned from
-func (m *ProcessManager) Spawn(agentID, workdir, prompt, agentType, repoRoot string, skipPermissions bool) (*AgentSlot, error) {
-	slot, err := m.CreateSlot(agentID, workdir, agentType, repoRoot, skipPermissions)
+// env holds variables to set in the agent's session
+func (m *ProcessManager) Spawn(agentID, workdir, prompt, agentType, repoRoot string, skipPermissions, continueSession bool, env map[string]string) (*AgentSlot, error) {
+	slot, err := m.CreateSlot(agentID, workdir, agentType, repoRoot, skipPermissions, continueSession, env)
 	if err != nil {
 		return nil, err
 	}
@@ -482,38 +950,18 @@ func (m *ProcessManager) Spawn(agentID, workdir, prompt, agentType, repoRoot str
 	// If a prompt was provided, send it to the tmux session
 	if prompt != "" {
 		// Give the agent time to fully start up and be ready for input
-		// Claude Code needs ~2s to initialize its UI
-		time.Sleep(2 * time.Second)
-
-		// Replace newlines with spaces to keep as single-line input
-		singleLinePrompt := strings.ReplaceAll(prompt, "\n", " ")
-		singleLinePrompt = strings.ReplaceAll(singleLinePrompt, "  ", " ")
+		// Claude Code needs ~2s to initialize its UI, part of which was
+		// spent in the boot check
+		m.mu.RLock()
+		booted := m.bootTimeout
+		m.mu.RUnlock()
+		time.Sleep(max(2*time.Second-booted, 0))
 
-		// Send text with -l (literal) flag, then Enter separately
-		cmd := exec.Command("tmux", "send-keys", "-t", slot.TmuxSession, "-l", singleLinePrompt)
-		if err := cmd.Run(); err != nil {
-			log.Printf("warning: failed to send initial prompt text to %s: %v", agentID, err)
+		if err := m.deliverPrompt(context.Background(), slot.TmuxSession, slot.AgentType, prompt); err != nil {
+			log.Printf("warning: failed to deliver initial prompt to %s: %v", agentID, err)
 		} else {
-			// Wait for pasted text to be processed (long text shows as collapsed paste)
-			time.Sleep(tmuxPasteDelay)
-
-			// Send Enter twice for long pastes:
-			// 1st Enter: confirms/expands the collapsed paste preview
-			// 2nd Enter: submits the prompt to the CLI
-			cmd = exec.Command("tmux", "send-keys", "-t", slot.TmuxSession, "Enter")
-			if err := cmd.Run(); err != nil {
-				log.Printf("warning: failed to send first Enter to %s: %v", agentID, err)
-			} else {
-				// Wait for paste to expand before sending second Enter
-				time.Sleep(tmuxEnterDelay)
-
-				cmd = exec.Command("tmux", "send-keys", "-t", slot.TmuxSession, "Enter")
-				if err := cmd.Run(); err != nil {
-					log.Printf("warning: failed to send second Enter to %s: %v", agentID, err)
-				} else {
-					log.Printf("sent initial prompt to agent %s", agentID)
-				}
-			}
+			log.Printf("sent initial prompt to agent %s", agentID)
+			m.recordMessage(agentID, "", MessageKindInitialPrompt, AgentMessageSent, prompt)
 		}
 	}
 
@@ -572,54 +1020,87 @@ func IsTmuxPaneDead(sessionName string) bool {
 	return strings.TrimSpace(string(output)) == "1"
 }
 
-// RespawnInPane respawns the agent process in a dead tmux pane
-func (m *ProcessManager) RespawnInPane(agentID string, skipPermissions bool) error {
+// MoveToWorktree makes a worktree created after spawning the agent's working
+// directory. A running CLI stays where it started; respawns and headless
+// runs use the new directory.
+func (m *ProcessManager) MoveToWorktree(agentID, path string) error {
 	m.mu.RLock()
 	slot, exists := m.agents[agentID]
 	m.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("agent %s not found", agentID)
+		return notFoundf("agent %s not found", agentID)
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if slot.Isolated {
+		return alreadyExistsf("agent %s already has a worktree", agentID)
+	}
+	slot.WorktreePath = path
+	slot.Isolated = true
+	return nil
+}
+
+// interactiveCommand returns the binary and shell command that start an
+// agent's CLI in its pane. continueSession adds claude's --continue, which
+// resumes the most recent conversation in the working directory; the other
+// CLIs have no equivalent, so it is ignored for them.
+func interactiveCommand(agentType string, skipPermissions, continueSession bool) (string, string) {
+	spec := agentTypeSpec(agentType)
+	return spec.Binary, spec.InteractiveCommand(skipPermissions, continueSession)
+}
+
+// RespawnInPane respawns the agent process in a dead tmux pane. With force,
+// a pane whose process is still running is restarted too. The agent keeps
+// skipPermissions as its permission mode for later respawns.
+func (m *ProcessManager) RespawnInPane(agentID string, skipPermissions, force bool) error {
+	m.mu.RLock()
+	slot, exists := m.agents[agentID]
+	m.mu.RUnlock()
+
+	if !exists {
+		return notFoundf("agent %s not found", agentID)
+	}
+	if slot.Headless {
+		return failedPreconditionf("agent %s is headless and has no pane to respawn", agentID)
 	}
 
 	// Check if session exists
 	checkCmd := exec.Command("tmux", "has-session", "-t", slot.TmuxSession)
 	if err := checkCmd.Run(); err != nil {
-		return fmt.Errorf("tmux session %s not found", slot.TmuxSession)
+		return failedPreconditionf("tmux session %s not found", slot.TmuxSession)
 	}
 
 	// Check if pane is dead
-	if !IsTmuxPaneDead(slot.TmuxSession) {
-		return fmt.Errorf("agent %s pane is still running - cannot respawn", agentID)
+	if !force && !IsTmuxPaneDead(slot.TmuxSession) {
+		return failedPreconditionf("agent %s pane is still running - cannot respawn", agentID)
 	}
 
-	// Determine CLI command based on agent type
-	var cliCmd string
 	agentType := slot.AgentType
 	if agentType == "" {
 		agentType = AgentTypeClaude
 	}
+	_, cliCmd := interactiveCommand(agentType, skipPermissions, slot.ContinueSession)
 
-	switch agentType {
-	case AgentTypeCodex:
-		if skipPermissions {
-			cliCmd = "codex --dangerously-bypass-approvals-and-sandbox"
-		} else {
-			cliCmd = "codex"
-		}
-	default: // claude
-		if skipPermissions {
-			cliCmd = "claude --dangerously-skip-permissions"
-		} else {
-			cliCmd = "claude"
-		}
+	// A container agent gets a fresh container; the old one may still be
+	// running if the pane is being forcibly respawned
+	if slot.Runtime != "" {
+		_ = removeContainer(slot.Runtime, agentID)
+		cliCmd = containerCommand(slot.Runtime, slot.Image, agentID, slot.WorktreePath, slot.RepoRoot, cliCmd, slot.Env)
 	}
 
-	cmd := exec.Command("tmux", "respawn-pane", "-t", slot.TmuxSession, "-k", cliCmd)
+	// Start in the agent's working directory explicitly, which may have
+	// moved to a worktree since the pane was created
+	cmd := exec.Command("tmux", "respawn-pane", "-t", slot.TmuxSession, "-k", "-c", slot.WorktreePath, cliCmd)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to respawn %s in pane: %w", agentType, err)
 	}
 
+	slot.mu.Lock()
+	slot.SkipPermissions = skipPermissions
+	slot.mu.Unlock()
+
 	log.Printf("respawned %s in agent %s", agentType, agentID)
 	return nil
 }
@@ -1,9 +1,15 @@
 package daemon
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 )
 
 func TestProcessManager_AgentTracking(t *testing.T) {
@@ -34,7 +40,7 @@ func TestProcessManager_AgentTracking(t *testing.T) {
 		t.Errorf("GetTmuxSession(missing) = %q, want empty", got)
 	}
 
-	slot := manager.FindAvailableAgent()
+	slot := manager.FindAvailableAgent(nil)
 	if slot == nil || slot.AgentID != "agent-idle" {
 		t.Fatalf("FindAvailableAgent returned %+v, want agent-idle", slot)
 	}
@@ -49,6 +55,10 @@ func TestProcessManager_AgentTracking(t *testing.T) {
 		t.Errorf("ListRunning = %v, want both agents", running)
 	}
 
+	if idle, busy := manager.CountByStatus(nil); idle != 1 || busy != 1 {
+		t.Errorf("CountByStatus = (%d, %d), want (1, 1)", idle, busy)
+	}
+
 	if got := manager.GetLogsDir(); got != "/tmp/logs" {
 		t.Errorf("GetLogsDir = %q, want %q", got, "/tmp/logs")
 	}
@@ -57,6 +67,185 @@ func TestProcessManager_AgentTracking(t *testing.T) {
 	}
 }
 
+func newSchedulingTestManager(t *testing.T, strategy string) *ProcessManager {
+	t.Helper()
+	manager := NewProcessManager("/tmp/logs", nil)
+	if err := manager.SetSchedulingStrategy(strategy); err != nil {
+		t.Fatalf("SetSchedulingStrategy(%q) failed: %v", strategy, err)
+	}
+	for _, id := range []string{"agent-a", "agent-b", "agent-c"} {
+		manager.agents[id] = &AgentSlot{AgentID: id, Status: AgentStatusIdle}
+	}
+	return manager
+}
+
+func TestProcessManager_CountByStatus_WorkingAgent(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	manager := NewProcessManager(t.TempDir(), nil)
+
+	// Both slots show idle, as tmux agents' slots do once a prompt is sent
+	manager.agents["agent-a"] = &AgentSlot{AgentID: "agent-a", Status: AgentStatusIdle}
+	manager.agents["agent-b"] = &AgentSlot{AgentID: "agent-b", Status: AgentStatusIdle}
+
+	now := time.Now()
+	for _, task := range []*TaskRecord{
+		{TaskID: "task-1", Description: "work", Status: "waiting_input", AssignedTo: "agent-a", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "task-2", Description: "done", Status: "completed", AssignedTo: "agent-b", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.CreateTask(task); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	if idle, busy := manager.CountByStatus(store); idle != 1 || busy != 1 {
+		t.Errorf("CountByStatus = (%d, %d), want (1, 1) with agent-a working", idle, busy)
+	}
+}
+
+func TestFindAvailableAgent_RoundRobin(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingRoundRobin)
+	manager.agents["agent-b"].Status = AgentStatusBusy
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, manager.FindAvailableAgent(nil).AgentID)
+	}
+	if want := []string{"agent-a", "agent-c", "agent-a", "agent-c"}; !slices.Equal(got, want) {
+		t.Errorf("round-robin order = %v, want %v", got, want)
+	}
+}
+
+func TestFindAvailableAgent_LeastLoaded(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingLeastLoaded)
+	manager.agents["agent-a"].TasksRun = 3
+	manager.agents["agent-b"].TasksRun = 1
+	manager.agents["agent-c"].TasksRun = 1
+
+	// Ties are broken by agent ID
+	if slot := manager.FindAvailableAgent(nil); slot.AgentID != "agent-b" {
+		t.Errorf("FindAvailableAgent = %s, want agent-b", slot.AgentID)
+	}
+
+	manager.agents["agent-b"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(nil); slot.AgentID != "agent-c" {
+		t.Errorf("FindAvailableAgent = %s, want agent-c", slot.AgentID)
+	}
+
+	manager.agents["agent-c"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(nil); slot.AgentID != "agent-a" {
+		t.Errorf("FindAvailableAgent = %s, want agent-a", slot.AgentID)
+	}
+}
+
+func TestFindAvailableAgent_Random(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingRandom)
+	manager.agents["agent-c"].Status = AgentStatusBusy
+
+	for i := 0; i < 20; i++ {
+		slot := manager.FindAvailableAgent(nil)
+		if slot == nil || slot.AgentID == "agent-c" {
+			t.Fatalf("FindAvailableAgent returned %+v, want an idle agent", slot)
+		}
+	}
+
+	manager.agents["agent-a"].Status = AgentStatusBusy
+	manager.agents["agent-b"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(nil); slot != nil {
+		t.Errorf("FindAvailableAgent = %s, want nil when all agents are busy", slot.AgentID)
+	}
+}
+
+func TestFindAvailableAgent_PreferAgent(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingRoundRobin)
+	task := &mapv1.Task{TaskId: "task-1", PreferAgent: "agent-c"}
+
+	if slot := manager.FindAvailableAgent(task); slot.AgentID != "agent-c" {
+		t.Errorf("FindAvailableAgent = %s, want the preferred agent-c", slot.AgentID)
+	}
+
+	// A busy or unknown preferred agent falls back to the scheduler
+	manager.agents["agent-c"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(task); slot.AgentID != "agent-a" {
+		t.Errorf("FindAvailableAgent with agent-c busy = %s, want agent-a", slot.AgentID)
+	}
+	task.PreferAgent = "agent-gone"
+	if slot := manager.FindAvailableAgent(task); slot.AgentID != "agent-b" {
+		t.Errorf("FindAvailableAgent with an unknown preference = %s, want agent-b", slot.AgentID)
+	}
+}
+
+func TestFindAvailableAgent_Held(t *testing.T) {
+	manager := NewProcessManager(t.TempDir(), nil)
+	manager.Hold("agent-a")
+	manager.agents["agent-a"] = &AgentSlot{AgentID: "agent-a", Status: AgentStatusIdle}
+
+	// An older queued task doesn't get the held agent
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "task-old"}); slot != nil {
+		t.Fatalf("FindAvailableAgent = %s, want no agent while agent-a is held", slot.AgentID)
+	}
+
+	// The task it is held for does, which releases the hold
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "task-new", PreferAgent: "agent-a"}); slot == nil || slot.AgentID != "agent-a" {
+		t.Fatalf("FindAvailableAgent = %v, want agent-a for the task preferring it", slot)
+	}
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "task-old"}); slot == nil {
+		t.Error("FindAvailableAgent = nil, want agent-a once its hold is released")
+	}
+}
+
+func TestFindAvailableAgent_AgentType(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingRoundRobin)
+	manager.agents["agent-a"].AgentType = AgentTypeClaude
+	manager.agents["agent-b"].AgentType = AgentTypeGemini
+	manager.agents["agent-c"].AgentType = AgentTypeClaude
+	task := &mapv1.Task{TaskId: "task-1", AgentType: AgentTypeGemini}
+
+	for range 2 {
+		if slot := manager.FindAvailableAgent(task); slot.AgentID != "agent-b" {
+			t.Errorf("FindAvailableAgent = %s, want the gemini agent-b", slot.AgentID)
+		}
+	}
+
+	// Without an idle agent of the type, any agent takes the task
+	manager.agents["agent-b"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(task); slot == nil || slot.AgentType != AgentTypeClaude {
+		t.Errorf("FindAvailableAgent with agent-b busy = %v, want a claude agent", slot)
+	}
+}
+
+func TestFindAvailableAgent_NoWorktree(t *testing.T) {
+	manager := newSchedulingTestManager(t, SchedulingRoundRobin)
+	manager.agents["agent-a"].Isolated = true
+	manager.agents["agent-c"].Isolated = true
+	task := &mapv1.Task{TaskId: "task-1", NoWorktree: true}
+
+	for range 2 {
+		if slot := manager.FindAvailableAgent(task); slot.AgentID != "agent-b" {
+			t.Errorf("FindAvailableAgent = %s, want the shared agent-b", slot.AgentID)
+		}
+	}
+
+	// Without an idle shared agent, a worktree agent takes the task
+	manager.agents["agent-b"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(task); slot == nil || !slot.Isolated {
+		t.Errorf("FindAvailableAgent with agent-b busy = %v, want a worktree agent", slot)
+	}
+
+	// Tasks that need a worktree are scheduled as before
+	manager.agents["agent-b"].Status = AgentStatusIdle
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "task-2"}); slot == nil {
+		t.Error("FindAvailableAgent returned nil for a regular task")
+	}
+}
+
+func TestSetSchedulingStrategy_Invalid(t *testing.T) {
+	manager := NewProcessManager("/tmp/logs", nil)
+	if err := manager.SetSchedulingStrategy("fastest"); err == nil {
+		t.Error("SetSchedulingStrategy should reject unknown strategies")
+	}
+}
+
 func TestProcessManager_Remove(t *testing.T) {
 	manager := NewProcessManager("/tmp/logs", nil)
 
@@ -76,3 +265,132 @@ func TestProcessManager_Remove(t *testing.T) {
 		t.Errorf("List returned %d agents, want 0", len(manager.List()))
 	}
 }
+
+func TestProcessManager_SessionSendLock(t *testing.T) {
+	manager := NewProcessManager("/tmp/logs", nil)
+
+	a1 := manager.sessionSendLock("map-agent-a")
+	a2 := manager.sessionSendLock("map-agent-a")
+	b := manager.sessionSendLock("map-agent-b")
+
+	if a1 != a2 {
+		t.Error("sends to the same session should share a lock")
+	}
+	if a1 == b {
+		t.Error("sends to different sessions should not share a lock")
+	}
+
+	// Holding one session's lock must not block another session
+	a1.Lock()
+	defer a1.Unlock()
+	if !b.TryLock() {
+		t.Fatal("lock for another session should be available")
+	}
+	b.Unlock()
+	if a2.TryLock() {
+		t.Error("lock for the same session should be held")
+	}
+}
+
+func TestResolveScopePaths(t *testing.T) {
+	workdir := t.TempDir()
+	repoRoot := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(workdir, "internal", "api"), 0755); err != nil {
+		t.Fatalf("create dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(workdir, "main.go"), []byte("package main"), 0644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	found, missing := resolveScopePaths(workdir, repoRoot, []string{
+		"./internal/api",
+		filepath.Join(repoRoot, "main.go"), // absolute path in source repo, rebased onto worktree
+		"missing.go",
+		"",
+	})
+
+	if want := []string{"internal/api", "main.go"}; !slices.Equal(found, want) {
+		t.Errorf("found = %v, want %v", found, want)
+	}
+	if want := []string{"missing.go"}; !slices.Equal(missing, want) {
+		t.Errorf("missing = %v, want %v", missing, want)
+	}
+}
+
+func TestAgentSlot_HasLabels(t *testing.T) {
+	slot := &AgentSlot{Labels: map[string]string{"team": "backend", "tier": "fast"}}
+
+	tests := []struct {
+		selector map[string]string
+		want     bool
+	}{
+		{nil, true},
+		{map[string]string{"team": "backend"}, true},
+		{map[string]string{"team": "backend", "tier": "fast"}, true},
+		{map[string]string{"team": "frontend"}, false},
+		{map[string]string{"team": "backend", "region": "eu"}, false},
+	}
+	for _, tt := range tests {
+		if got := slot.HasLabels(tt.selector); got != tt.want {
+			t.Errorf("HasLabels(%v) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+
+	if (&AgentSlot{}).HasLabels(map[string]string{"team": "backend"}) {
+		t.Error("unlabeled agent should not match a selector")
+	}
+}
+
+func TestInteractiveCommand(t *testing.T) {
+	tests := []struct {
+		agentType       string
+		skip, continued bool
+		wantBinary      string
+		wantCmd         string
+	}{
+		{AgentTypeClaude, false, false, "claude", "claude"},
+		{AgentTypeClaude, true, false, "claude", "claude --dangerously-skip-permissions"},
+		{AgentTypeClaude, true, true, "claude", "claude --continue --dangerously-skip-permissions"},
+		{AgentTypeCodex, false, false, "codex", "codex"},
+		{AgentTypeCodex, true, true, "codex", "codex --dangerously-bypass-approvals-and-sandbox"},
+		{AgentTypeGemini, false, true, "gemini", "gemini"},
+		{AgentTypeGemini, true, false, "gemini", "gemini --yolo"},
+		{"", true, false, "claude", "claude --dangerously-skip-permissions"},
+	}
+	for _, tt := range tests {
+		binary, cmd := interactiveCommand(tt.agentType, tt.skip, tt.continued)
+		if binary != tt.wantBinary || cmd != tt.wantCmd {
+			t.Errorf("interactiveCommand(%s, %v, %v) = %q, %q; want %q, %q",
+				tt.agentType, tt.skip, tt.continued, binary, cmd, tt.wantBinary, tt.wantCmd)
+		}
+	}
+}
+
+func TestSetTmuxEnvironment(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	// Use a tmux server of the test's own
+	t.Setenv("TMUX_TMPDIR", t.TempDir())
+	t.Setenv("TMUX", "")
+	if out, err := exec.Command("tmux", "new-session", "-d", "-s", "map-env-test", "cat").CombinedOutput(); err != nil {
+		t.Skipf("tmux new-session: %v: %s", err, out)
+	}
+	t.Cleanup(func() { _ = exec.Command("tmux", "kill-server").Run() })
+
+	env := map[string]string{"TOKEN": "it's $HOME; #{x}", "PLAIN": "abc"}
+	if err := setTmuxEnvironment("map-env-test", env); err != nil {
+		t.Fatalf("setTmuxEnvironment failed: %v", err)
+	}
+
+	for name, want := range env {
+		out, err := exec.Command("tmux", "show-environment", "-t", "map-env-test", name).Output()
+		if err != nil {
+			t.Fatalf("show-environment %s: %v", name, err)
+		}
+		if got := strings.TrimSuffix(string(out), "\n"); got != name+"="+want {
+			t.Errorf("tmux has %q, want %q", got, name+"="+want)
+		}
+	}
+}
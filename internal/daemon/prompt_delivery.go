@@ -0,0 +1,123 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Defaults for confirming that an initial prompt reached the agent
+const (
+	DefaultPromptConfirmWait = 3 * time.Second
+	DefaultPromptRetries     = 2
+)
+
+// promptCheckInterval is how often the pane is captured while waiting for a
+// sent prompt to show up
+const promptCheckInterval = 250 * time.Millisecond
+
+// promptSnippetLen is how much of the start of a prompt must be visible in
+// the pane for it to count as delivered
+const promptSnippetLen = 40
+
+// SetPromptDelivery sets how long to wait for an initial prompt to appear in
+// the agent's pane, and how many times to resend it if it doesn't. A
+// non-positive wait uses DefaultPromptConfirmWait; zero or negative retries
+// means the prompt is sent once.
+func (m *ProcessManager) SetPromptDelivery(wait time.Duration, retries int) {
+	if wait <= 0 {
+		wait = DefaultPromptConfirmWait
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.promptConfirmWait = wait
+	m.promptRetries = max(retries, 0)
+}
+
+// deliverPrompt sends a prompt and confirms it landed by looking for it in
+// the pane, resending it if it never shows up. CLIs that are still starting
+// can swallow keystrokes, which leaves a new agent sitting idle.
+func (m *ProcessManager) deliverPrompt(ctx context.Context, tmuxSession, agentType, prompt string) error {
+	m.mu.RLock()
+	wait, retries := m.promptConfirmWait, m.promptRetries
+	m.mu.RUnlock()
+
+	attempts := retries + 1
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err := m.sendPrompt(ctx, tmuxSession, agentType, prompt); err != nil {
+			return err
+		}
+		if m.waitForPrompt(ctx, tmuxSession, agentType, prompt, wait) {
+			return nil
+		}
+		if attempt < attempts {
+			log.Printf("warning: prompt not seen in %s after %s, resending (attempt %d of %d)", tmuxSession, wait, attempt+1, attempts)
+		}
+	}
+	return fmt.Errorf("prompt not seen in pane after %d attempt(s)", attempts)
+}
+
+// waitForPrompt polls the pane until the prompt appears or wait elapses. A
+// long prompt collapsed into a "[Pasted text ...]" placeholder has arrived
+// but may not have been submitted, so the submit keys are sent again, once
+// per wait, and the pane checked again.
+func (m *ProcessManager) waitForPrompt(ctx context.Context, tmuxSession, agentType, prompt string, wait time.Duration) bool {
+	deadline := time.Now().Add(wait)
+	resubmitted := false
+	for {
+		out, err := exec.CommandContext(ctx, "tmux", "capture-pane", "-t", tmuxSession, "-p", "-J", "-S", "-200").Output()
+		if err == nil && paneShowsPrompt(string(out), prompt) {
+			return true
+		}
+		if err == nil && !resubmitted && paneHasPendingPaste(string(out)) {
+			resubmitted = true
+			if err := m.resubmitPrompt(ctx, tmuxSession, agentType); err != nil {
+				log.Printf("warning: failed to resubmit prompt in %s: %v", tmuxSession, err)
+			}
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(promptCheckInterval):
+		}
+	}
+}
+
+// paneShowsPrompt reports whether pane content shows a sent prompt. The CLIs
+// wrap and frame input themselves, so whitespace and box-drawing characters
+// are ignored and only the start of the prompt is compared. A "[Pasted
+// text ...]" placeholder doesn't count: the paste may not be submitted yet.
+func paneShowsPrompt(content, prompt string) bool {
+	want := []rune(stripPaneChrome(prompt))
+	if len(want) == 0 {
+		return true
+	}
+	if len(want) > promptSnippetLen {
+		want = want[:promptSnippetLen]
+	}
+	return strings.Contains(stripPaneChrome(content), string(want))
+}
+
+// paneHasPendingPaste reports whether the pane shows a long paste collapsed
+// into a placeholder, which the CLI shows until the paste is submitted
+func paneHasPendingPaste(content string) bool {
+	return strings.Contains(content, "[Pasted text")
+}
+
+// stripPaneChrome drops whitespace and box-drawing characters, leaving the
+// text a pane renders regardless of how it was wrapped or framed
+func stripPaneChrome(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || (r >= 0x2500 && r <= 0x257F) {
+			return -1
+		}
+		return r
+	}, s)
+}
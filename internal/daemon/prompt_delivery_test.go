@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPaneShowsPrompt(t *testing.T) {
+	prompt := "Fix the flaky login test in auth_test.go and open a PR when you're done"
+
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"empty pane", "", false},
+		{"welcome screen only", "╭──────────────╮\n│ Welcome!     │\n╰──────────────╯\n> ", false},
+		{"prompt on one line", "> Fix the flaky login test in auth_test.go and open a PR when you're done", true},
+		{"prompt wrapped in a box", "│ > Fix the flaky login test in   │\n│ auth_test.go and open a PR when │\n│ you're done                     │", true},
+		{"collapsed paste not yet submitted", "> [Pasted text #1 +12 lines]", false},
+		{"different prompt", "> Fix the flaky signup test", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := paneShowsPrompt(tt.content, prompt); got != tt.want {
+				t.Errorf("paneShowsPrompt = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneHasPendingPaste(t *testing.T) {
+	if !paneHasPendingPaste("> [Pasted text #1 +12 lines]") {
+		t.Error("paneHasPendingPaste = false for a collapsed paste")
+	}
+	if paneHasPendingPaste("> Fix the flaky login test") {
+		t.Error("paneHasPendingPaste = true for typed text")
+	}
+}
+
+func TestSetPromptDelivery(t *testing.T) {
+	m := NewProcessManager(t.TempDir(), nil)
+
+	m.SetPromptDelivery(0, -1)
+	if m.promptConfirmWait != DefaultPromptConfirmWait {
+		t.Errorf("wait = %s, want default %s", m.promptConfirmWait, DefaultPromptConfirmWait)
+	}
+	if m.promptRetries != 0 {
+		t.Errorf("retries = %d, want 0 for a negative value", m.promptRetries)
+	}
+
+	m.SetPromptDelivery(5*time.Second, 4)
+	if m.promptConfirmWait != 5*time.Second || m.promptRetries != 4 {
+		t.Errorf("got wait %s retries %d, want 5s and 4", m.promptConfirmWait, m.promptRetries)
+	}
+}
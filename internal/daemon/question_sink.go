@@ -0,0 +1,148 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// QuestionSink delivers a question an agent is waiting on to somewhere the
+// user will see it
+type QuestionSink interface {
+	// Name identifies the sink in logs, e.g. "github"
+	Name() string
+	// Accepts reports whether the sink can deliver questions about task
+	Accepts(task *TaskRecord) bool
+	// PostQuestion delivers an agent's question about task
+	PostQuestion(ctx context.Context, task *TaskRecord, question string) error
+	// Replies reports whether answers come back through the sink, so the
+	// task can wait for one
+	Replies() bool
+}
+
+// DefaultWebhookTimeout bounds a single webhook post
+const DefaultWebhookTimeout = 10 * time.Second
+
+// GitHubQuestionSink posts questions as comments on the task's GitHub
+// issue. Answers left on the issue are delivered back to the agent by the
+// GitHub poller.
+type GitHubQuestionSink struct {
+	// Timeout bounds each gh call
+	Timeout time.Duration
+}
+
+// NewGitHubQuestionSink creates a GitHub sink whose gh calls give up after
+// timeout (non-positive = DefaultGitHubTimeout)
+func NewGitHubQuestionSink(timeout time.Duration) *GitHubQuestionSink {
+	if timeout <= 0 {
+		timeout = DefaultGitHubTimeout
+	}
+	return &GitHubQuestionSink{Timeout: timeout}
+}
+
+func (s *GitHubQuestionSink) Name() string { return "github" }
+
+// Accepts reports whether the task came from, or was linked to, an issue
+func (s *GitHubQuestionSink) Accepts(task *TaskRecord) bool {
+	return task.GitHubOwner != "" && task.GitHubRepo != "" && task.GitHubIssueNumber != 0
+}
+
+func (s *GitHubQuestionSink) PostQuestion(ctx context.Context, task *TaskRecord, question string) error {
+	return PostQuestionToGitHub(ctx, s.Timeout, task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, question)
+}
+
+func (s *GitHubQuestionSink) Replies() bool { return true }
+
+// WebhookQuestionSink posts questions to a Slack or Discord incoming
+// webhook. It is delivery only: answers are still given in the agent's
+// session, or on the GitHub issue when there is one.
+type WebhookQuestionSink struct {
+	url     string
+	discord bool
+	client  *http.Client
+}
+
+// NewWebhookQuestionSink creates a sink posting to an http(s) webhook URL.
+// Discord webhooks are recognized by their host and sent a "content" field;
+// anything else is sent Slack's "text" field.
+func NewWebhookQuestionSink(rawURL string) (*WebhookQuestionSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, invalidArgumentf("invalid question webhook URL %q: must be an http or https URL", rawURL)
+	}
+	host := strings.ToLower(u.Hostname())
+	return &WebhookQuestionSink{
+		url:     rawURL,
+		discord: host == "discord.com" || strings.HasSuffix(host, ".discord.com") || host == "discordapp.com",
+		client:  &http.Client{Timeout: DefaultWebhookTimeout},
+	}, nil
+}
+
+func (s *WebhookQuestionSink) Name() string { return "webhook" }
+
+// Accepts reports true: a webhook can take questions about any task
+func (s *WebhookQuestionSink) Accepts(task *TaskRecord) bool { return true }
+
+func (s *WebhookQuestionSink) PostQuestion(ctx context.Context, task *TaskRecord, question string) error {
+	field := "text"
+	if s.discord {
+		field = "content"
+	}
+	body, err := json.Marshal(map[string]string{field: webhookQuestionMessage(task, question)})
+	if err != nil {
+		return fmt.Errorf("marshal webhook message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post to webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post to webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *WebhookQuestionSink) Replies() bool { return false }
+
+// questionSinks builds the question sinks a daemon config asks for: the
+// task's GitHub issue unless turned off, and the webhook if one is set
+func questionSinks(cfg *Config) ([]QuestionSink, error) {
+	var sinks []QuestionSink
+	if !cfg.NoGitHubQuestions {
+		sinks = append(sinks, NewGitHubQuestionSink(cfg.GitHubTimeout))
+	}
+	if cfg.QuestionWebhookURL != "" {
+		webhook, err := NewWebhookQuestionSink(cfg.QuestionWebhookURL)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, webhook)
+	}
+	return sinks, nil
+}
+
+// webhookQuestionMessage formats a question for a chat channel, naming the
+// agent and task, where to answer, and the task's issue if it has one
+func webhookQuestionMessage(task *TaskRecord, question string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Agent %s needs more input on task %s (%s)", task.AssignedTo, task.TaskID, truncateLog(task.Description, 80))
+	if task.GitHubOwner != "" && task.GitHubRepo != "" && task.GitHubIssueNumber != 0 {
+		fmt.Fprintf(&b, "\nIssue: https://github.com/%s/%s/issues/%d", task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber)
+	}
+	fmt.Fprintf(&b, "\nSession: map agent watch %s", task.AssignedTo)
+	fmt.Fprintf(&b, "\n\n%s", question)
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewWebhookQuestionSink(t *testing.T) {
+	for _, raw := range []string{"", "hooks.slack.com/services/x", "ftp://example.com/hook", "https://"} {
+		if _, err := NewWebhookQuestionSink(raw); err == nil {
+			t.Errorf("NewWebhookQuestionSink(%q) succeeded, want error", raw)
+		}
+	}
+
+	tests := map[string]bool{
+		"https://hooks.slack.com/services/T0/B0/x":      false,
+		"https://discord.com/api/webhooks/1/x":          true,
+		"https://canary.discord.com/api/webhooks/1/x":   true,
+		"https://discordapp.com/api/webhooks/1/x":       true,
+		"http://localhost:8080/not-discord.com/webhook": false,
+	}
+	for raw, discord := range tests {
+		sink, err := NewWebhookQuestionSink(raw)
+		if err != nil {
+			t.Fatalf("NewWebhookQuestionSink(%q) failed: %v", raw, err)
+		}
+		if sink.discord != discord {
+			t.Errorf("NewWebhookQuestionSink(%q).discord = %v, want %v", raw, sink.discord, discord)
+		}
+	}
+}
+
+func TestWebhookQuestionSink_PostQuestion(t *testing.T) {
+	var got map[string]string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookQuestionSink(server.URL)
+	if err != nil {
+		t.Fatalf("NewWebhookQuestionSink failed: %v", err)
+	}
+	task := &TaskRecord{
+		TaskID: "task-1", Description: "fix login", AssignedTo: "agent-a",
+		GitHubOwner: "acme", GitHubRepo: "web", GitHubIssueNumber: 42,
+	}
+
+	if err := sink.PostQuestion(context.Background(), task, "Should I keep the old API?"); err != nil {
+		t.Fatalf("PostQuestion failed: %v", err)
+	}
+	text := got["text"]
+	for _, want := range []string{"agent-a", "task-1", "https://github.com/acme/web/issues/42", "map agent watch agent-a", "Should I keep the old API?"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("webhook text %q doesn't contain %q", text, want)
+		}
+	}
+
+	// Discord webhooks take the message as content
+	sink.discord = true
+	if err := sink.PostQuestion(context.Background(), task, "Which branch?"); err != nil {
+		t.Fatalf("PostQuestion failed: %v", err)
+	}
+	if !strings.Contains(got["content"], "Which branch?") || got["text"] != "" {
+		t.Errorf("discord payload = %v, want the question in content", got)
+	}
+
+	status = http.StatusNotFound
+	if err := sink.PostQuestion(context.Background(), task, "Still there?"); err == nil {
+		t.Error("PostQuestion to a failing webhook succeeded, want error")
+	}
+}
+
+func TestQuestionSinks(t *testing.T) {
+	sinks, err := questionSinks(&Config{})
+	if err != nil || len(sinks) != 1 || sinks[0].Name() != "github" {
+		t.Fatalf("default sinks = %v (err %v), want only github", sinks, err)
+	}
+
+	sinks, err = questionSinks(&Config{NoGitHubQuestions: true, QuestionWebhookURL: "https://hooks.slack.com/services/x"})
+	if err != nil || len(sinks) != 1 || sinks[0].Name() != "webhook" {
+		t.Fatalf("webhook-only sinks = %v (err %v), want only webhook", sinks, err)
+	}
+
+	if _, err := questionSinks(&Config{QuestionWebhookURL: "not a url"}); err == nil {
+		t.Error("questionSinks with an invalid webhook URL succeeded, want error")
+	}
+
+	// Only GitHub-linked tasks go to GitHub; the webhook takes any task
+	github, webhook := NewGitHubQuestionSink(0), &WebhookQuestionSink{}
+	task := &TaskRecord{TaskID: "task-1"}
+	if github.Accepts(task) || !webhook.Accepts(task) {
+		t.Errorf("unlinked task: github accepts %v, webhook accepts %v; want false, true", github.Accepts(task), webhook.Accepts(task))
+	}
+	task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber = "acme", "web", 42
+	if !github.Accepts(task) {
+		t.Error("github sink doesn't accept a linked task")
+	}
+}
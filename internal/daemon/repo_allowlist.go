@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// RepoAllowlist restricts which repositories agents may be spawned against.
+// Entries are paths, which allow that directory and everything beneath it,
+// or filepath.Match patterns (e.g. ~/code/*). An empty allowlist allows
+// every repository.
+type RepoAllowlist struct {
+	patterns []string
+}
+
+// NewRepoAllowlist creates an allowlist from paths and patterns, expanding a
+// leading ~ to the home directory. Symlinks in the entries are resolved, up
+// to the first component with a wildcard, so they compare against the real
+// locations of repositories.
+func NewRepoAllowlist(patterns []string) *RepoAllowlist {
+	a := &RepoAllowlist{}
+	for _, p := range patterns {
+		if p == "" {
+			continue
+		}
+		a.patterns = append(a.patterns, resolvePattern(filepath.Clean(expandPath(p))))
+	}
+	return a
+}
+
+// Empty reports whether the allowlist allows every repository
+func (a *RepoAllowlist) Empty() bool {
+	return a == nil || len(a.patterns) == 0
+}
+
+// Allows reports whether agents may operate on the repository at path.
+// Only the fully resolved path is matched, so a symlink inside an allowed
+// directory that points elsewhere is judged by where it points.
+func (a *RepoAllowlist) Allows(path string) bool {
+	if a.Empty() {
+		return true
+	}
+	if path == "" {
+		return false
+	}
+
+	real := resolvePath(filepath.Clean(path))
+	for _, pattern := range a.patterns {
+		if pathWithin(real, pattern) {
+			return true
+		}
+		if ok, err := filepath.Match(pattern, real); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath resolves the symlinks in path. Components that don't exist
+// yet are kept as they are, beneath their resolved parent.
+func resolvePath(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	dir, base := filepath.Split(path)
+	dir = filepath.Clean(dir)
+	if dir == path || base == "" {
+		return path
+	}
+	return filepath.Join(resolvePath(dir), base)
+}
+
+// resolvePattern resolves the symlinks in a pattern's leading components,
+// up to the first one with a wildcard
+func resolvePattern(pattern string) string {
+	parts := strings.Split(pattern, string(filepath.Separator))
+	for i, part := range parts {
+		if strings.ContainsAny(part, `*?[\`) {
+			prefix := strings.Join(parts[:i], string(filepath.Separator))
+			if prefix == "" {
+				return pattern
+			}
+			return filepath.Join(append([]string{resolvePath(prefix)}, parts[i:]...)...)
+		}
+	}
+	return resolvePath(pattern)
+}
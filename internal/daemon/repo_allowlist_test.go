@@ -0,0 +1,93 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoAllowlist_Allows(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skipf("no home directory: %v", err)
+	}
+
+	allowlist := NewRepoAllowlist([]string{"/srv/repos/api", "/srv/team-*/web", "~/code/*"})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/srv/repos/api", true},
+		{"/srv/repos/api/", true},
+		{"/srv/repos/api/cmd", true},
+		{"/srv/repos/api-old", false},
+		{"/srv/repos", false},
+		{"/srv/team-a/web", true},
+		{"/srv/team-a/db", false},
+		{filepath.Join(home, "code", "mapcli"), true},
+		{filepath.Join(home, "other", "mapcli"), false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := allowlist.Allows(tt.path); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRepoAllowlist_Empty(t *testing.T) {
+	for _, allowlist := range []*RepoAllowlist{nil, NewRepoAllowlist(nil), NewRepoAllowlist([]string{""})} {
+		if !allowlist.Empty() || !allowlist.Allows("/anywhere") || !allowlist.Allows("") {
+			t.Errorf("empty allowlist %+v should allow every repository", allowlist)
+		}
+	}
+}
+
+func TestRepoAllowlist_ResolvesSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	if err := os.Mkdir(real, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	realPath, err := filepath.EvalSymlinks(real)
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	if !NewRepoAllowlist([]string{realPath}).Allows(link) {
+		t.Errorf("allowlist of %s should allow %s, which links to it", realPath, link)
+	}
+}
+
+func TestRepoAllowlist_SymlinkOutside(t *testing.T) {
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed")
+	outside := filepath.Join(dir, "outside")
+	for _, d := range []string{allowed, outside} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+	}
+	link := filepath.Join(allowed, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	for _, entry := range []string{allowed, filepath.Join(allowed, "*")} {
+		allowlist := NewRepoAllowlist([]string{entry})
+		if !allowlist.Allows(filepath.Join(allowed, "repo")) {
+			t.Errorf("allowlist of %s should allow a repo inside it", entry)
+		}
+		for _, path := range []string{link, filepath.Join(link, "repo")} {
+			if allowlist.Allows(path) {
+				t.Errorf("allowlist of %s allowed %s, which links outside it", entry, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package daemon
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RoutingRule sends tasks to agents of a particular type. A rule matches a
+// task when every condition it sets holds: one of the task's scope paths
+// matches a Scope pattern, the description matches the Match regexp, and
+// the task's GitHub issue carries one of Labels. A rule must set at least
+// one condition.
+type RoutingRule struct {
+	// Scope patterns are matched against scope paths relative to the
+	// task's repository, or anywhere in the path for tasks without one. A
+	// pattern matches a path or any directory above it, and one without a
+	// slash matches any path element (e.g. docs/**, internal/*, *.md).
+	Scope []string `mapstructure:"scope" json:"scope,omitempty"`
+	// Match is a regular expression tested against the task description
+	Match string `mapstructure:"match" json:"match,omitempty"`
+	// Labels are GitHub issue labels, compared case-insensitively
+	Labels    []string `mapstructure:"labels" json:"labels,omitempty"`
+	AgentType string   `mapstructure:"agent-type" json:"agent-type"`
+}
+
+// TaskRouting picks the agent type a task should prefer from an ordered
+// list of routing rules. The first matching rule wins.
+type TaskRouting struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	RoutingRule
+	match *regexp.Regexp
+}
+
+// NewTaskRouting checks and compiles routing rules
+func NewTaskRouting(rules []RoutingRule) (*TaskRouting, error) {
+	t := &TaskRouting{}
+	for i, rule := range rules {
+		if !ValidAgentType(rule.AgentType) {
+			return nil, fmt.Errorf("routing rule %d: invalid agent type %q: must be %s", i+1, rule.AgentType, AgentTypeChoices())
+		}
+		if len(rule.Scope) == 0 && rule.Match == "" && len(rule.Labels) == 0 {
+			return nil, fmt.Errorf("routing rule %d: set at least one of scope, match, or labels", i+1)
+		}
+		for _, pattern := range rule.Scope {
+			if _, err := filepath.Match(strings.TrimSuffix(pattern, "/**"), ""); err != nil {
+				return nil, fmt.Errorf("routing rule %d: bad scope pattern %q: %w", i+1, pattern, err)
+			}
+		}
+
+		compiled := compiledRule{RoutingRule: rule}
+		if rule.Match != "" {
+			re, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return nil, fmt.Errorf("routing rule %d: bad match pattern: %w", i+1, err)
+			}
+			compiled.match = re
+		}
+		t.rules = append(t.rules, compiled)
+	}
+	return t, nil
+}
+
+// AgentTypeFor returns the agent type the first matching rule routes a task
+// to, or "" if no rule matches. Rules with labels only match when labels
+// are given.
+func (t *TaskRouting) AgentTypeFor(repoRoot string, scopePaths []string, description string, labels []string) string {
+	if t == nil {
+		return ""
+	}
+	for _, rule := range t.rules {
+		if rule.matches(repoRoot, scopePaths, description, labels) {
+			return rule.AgentType
+		}
+	}
+	return ""
+}
+
+func (r compiledRule) matches(repoRoot string, scopePaths []string, description string, labels []string) bool {
+	if len(r.Scope) > 0 && !r.matchesScope(repoRoot, scopePaths) {
+		return false
+	}
+	if r.match != nil && !r.match.MatchString(description) {
+		return false
+	}
+	if len(r.Labels) > 0 && !hasAnyLabel(labels, r.Labels) {
+		return false
+	}
+	return true
+}
+
+// matchesScope reports whether any scope path matches one of the rule's
+// scope patterns
+func (r compiledRule) matchesScope(repoRoot string, scopePaths []string) bool {
+	for _, path := range scopePaths {
+		rel := path
+		if repoRoot != "" && pathWithin(path, repoRoot) {
+			if p, err := filepath.Rel(repoRoot, path); err == nil {
+				rel = p
+			}
+		}
+
+		// Without a repository to anchor to, try every trailing part of
+		// the path, so docs/** matches /home/me/repo/docs/guide.md
+		candidates := []string{filepath.ToSlash(rel)}
+		if filepath.IsAbs(rel) {
+			elems := strings.Split(strings.Trim(filepath.ToSlash(rel), "/"), "/")
+			candidates = candidates[:0]
+			for i := range elems {
+				candidates = append(candidates, strings.Join(elems[i:], "/"))
+			}
+		}
+
+		for _, pattern := range r.Scope {
+			for _, c := range candidates {
+				if scopePatternMatches(pattern, c) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// scopePatternMatches matches a scope pattern against a slash-separated
+// path. A trailing "/**" or "/" matches everything beneath a directory; a
+// pattern without a slash matches any path element, so *.md matches
+// docs/guide.md and docs matches docs/guide.md.
+func scopePatternMatches(pattern, path string) bool {
+	pattern = strings.TrimSuffix(strings.TrimSuffix(pattern, "/**"), "/")
+	if !strings.Contains(pattern, "/") {
+		for _, elem := range strings.Split(path, "/") {
+			if ok, _ := filepath.Match(pattern, elem); ok {
+				return true
+			}
+		}
+		return false
+	}
+	// Try the path itself and each directory above it
+	for p := path; p != "." && p != "/" && p != ""; p = filepath.Dir(p) {
+		if ok, _ := filepath.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyLabel reports whether labels includes any of want, ignoring case
+func hasAnyLabel(labels, want []string) bool {
+	for _, label := range labels {
+		for _, w := range want {
+			if strings.EqualFold(label, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestTaskRouting_AgentTypeFor(t *testing.T) {
+	routing, err := NewTaskRouting([]RoutingRule{
+		{Scope: []string{"docs/**", "*.md"}, AgentType: AgentTypeGemini},
+		{Match: `(?i)\brefactor\b`, AgentType: AgentTypeCodex},
+		{Labels: []string{"frontend"}, AgentType: AgentTypeGemini},
+		{Scope: []string{"internal/*"}, Match: "test", AgentType: AgentTypeClaude},
+	})
+	if err != nil {
+		t.Fatalf("NewTaskRouting: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		repoRoot    string
+		scopePaths  []string
+		description string
+		labels      []string
+		want        string
+	}{
+		{"scope under directory", "/repo", []string{"/repo/docs/guide/intro.txt"}, "fix typo", nil, AgentTypeGemini},
+		{"scope base name", "/repo", []string{"/repo/README.md"}, "fix typo", nil, AgentTypeGemini},
+		{"scope without repo", "", []string{"/home/me/repo/docs/guide.txt"}, "fix typo", nil, AgentTypeGemini},
+		{"description match", "/repo", nil, "Refactor the store", nil, AgentTypeCodex},
+		{"label match", "/repo", nil, "fix button", []string{"Frontend"}, AgentTypeGemini},
+		{"all conditions hold", "/repo", []string{"/repo/internal/daemon"}, "add a test", nil, AgentTypeClaude},
+		{"one condition fails", "/repo", []string{"/repo/internal/daemon"}, "fix a bug", nil, ""},
+		{"scope outside pattern", "/repo", []string{"/repo/cmd/mapd/main.go"}, "add a test", nil, ""},
+		{"no match", "/repo", nil, "fix a bug", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routing.AgentTypeFor(tt.repoRoot, tt.scopePaths, tt.description, tt.labels); got != tt.want {
+				t.Errorf("AgentTypeFor = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var none *TaskRouting
+	if got := none.AgentTypeFor("/repo", nil, "anything", nil); got != "" {
+		t.Errorf("nil routing AgentTypeFor = %q, want empty", got)
+	}
+}
+
+func TestNewTaskRouting_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule RoutingRule
+	}{
+		{"unknown agent type", RoutingRule{Match: "docs", AgentType: "gpt"}},
+		{"no conditions", RoutingRule{AgentType: AgentTypeClaude}},
+		{"bad regexp", RoutingRule{Match: "(", AgentType: AgentTypeClaude}},
+		{"bad scope", RoutingRule{Scope: []string{"docs/["}, AgentType: AgentTypeClaude}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTaskRouting([]RoutingRule{tt.rule}); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestTaskRouter_SubmitTaskAgentType(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	routing, err := NewTaskRouting([]RoutingRule{{Scope: []string{"docs/**"}, AgentType: AgentTypeGemini}})
+	if err != nil {
+		t.Fatalf("NewTaskRouting: %v", err)
+	}
+	router.SetRouting(routing)
+
+	// A routing rule picks the type for a task submitted without one
+	task, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: "Fix broken links",
+		ScopePaths:  []string{"/repo/docs/index.md"},
+		RepoRoot:    "/repo",
+	})
+	if err != nil {
+		t.Fatalf("SubmitTask: %v", err)
+	}
+	if task.AgentType != AgentTypeGemini {
+		t.Errorf("task agent type = %q, want gemini", task.AgentType)
+	}
+	stored, err := store.GetTask(task.TaskId)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if stored.AgentType != AgentTypeGemini {
+		t.Errorf("stored agent type = %q, want gemini", stored.AgentType)
+	}
+
+	// An explicit type wins over the rules
+	task, err = router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: "Fix broken links",
+		ScopePaths:  []string{"/repo/docs/index.md"},
+		RepoRoot:    "/repo",
+		AgentType:   AgentTypeCodex,
+	})
+	if err != nil {
+		t.Fatalf("SubmitTask: %v", err)
+	}
+	if task.AgentType != AgentTypeCodex {
+		t.Errorf("task agent type = %q, want codex", task.AgentType)
+	}
+
+	if _, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: "anything",
+		AgentType:   "gpt",
+	}); err == nil {
+		t.Error("expected an error for an unknown agent type")
+	}
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// Scheduler decides which idle agent a task is routed to. The built-in
+// strategies are selected with SetSchedulingStrategy; other implementations
+// can be installed with ProcessManager.SetScheduler to try out different
+// routing.
+type Scheduler interface {
+	// PickAgent returns one of the idle agents, which are sorted by agent
+	// ID, or nil to leave the task pending. The process manager is locked
+	// during the call, so implementations may keep state without locking
+	// but must not call back into the manager.
+	PickAgent(task *mapv1.Task, idle []*AgentSlot) *AgentSlot
+}
+
+// NewScheduler returns the built-in scheduler for a strategy name
+func NewScheduler(strategy string) (Scheduler, error) {
+	switch strategy {
+	case SchedulingRoundRobin:
+		return &roundRobinScheduler{}, nil
+	case SchedulingLeastLoaded:
+		return leastLoadedScheduler{}, nil
+	case SchedulingRandom:
+		return randomScheduler{}, nil
+	default:
+		return nil, fmt.Errorf("unknown scheduling strategy %q: must be %s, %s, or %s",
+			strategy, SchedulingRoundRobin, SchedulingLeastLoaded, SchedulingRandom)
+	}
+}
+
+// roundRobinScheduler picks the first idle agent after the last one it
+// picked, wrapping around
+type roundRobinScheduler struct {
+	last string
+}
+
+func (s *roundRobinScheduler) PickAgent(task *mapv1.Task, idle []*AgentSlot) *AgentSlot {
+	if len(idle) == 0 {
+		return nil
+	}
+	pick := idle[0]
+	for _, slot := range idle {
+		if s.last != "" && slot.AgentID > s.last {
+			pick = slot
+			break
+		}
+	}
+	s.last = pick.AgentID
+	return pick
+}
+
+// leastLoadedScheduler picks the idle agent that has been given the fewest
+// tasks, breaking ties by agent ID
+type leastLoadedScheduler struct{}
+
+func (leastLoadedScheduler) PickAgent(task *mapv1.Task, idle []*AgentSlot) *AgentSlot {
+	var best *AgentSlot
+	bestLoad := 0
+	for _, slot := range idle {
+		slot.mu.Lock()
+		load := slot.TasksRun
+		slot.mu.Unlock()
+		if best == nil || load < bestLoad {
+			best = slot
+			bestLoad = load
+		}
+	}
+	return best
+}
+
+// randomScheduler picks a uniformly random idle agent
+type randomScheduler struct{}
+
+func (randomScheduler) PickAgent(task *mapv1.Task, idle []*AgentSlot) *AgentSlot {
+	if len(idle) == 0 {
+		return nil
+	}
+	return idle[rand.IntN(len(idle))]
+}
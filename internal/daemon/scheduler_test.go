@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// typeScheduler routes tasks that mention an agent type to an agent of that
+// type, and holds them back while none is idle
+type typeScheduler struct {
+	seen []string
+}
+
+func (s *typeScheduler) PickAgent(task *mapv1.Task, idle []*AgentSlot) *AgentSlot {
+	s.seen = append(s.seen, task.GetTaskId())
+	for _, agentType := range []string{AgentTypeClaude, AgentTypeCodex, AgentTypeGemini} {
+		if !strings.Contains(task.GetDescription(), agentType) {
+			continue
+		}
+		for _, slot := range idle {
+			if slot.AgentType == agentType {
+				return slot
+			}
+		}
+		return nil
+	}
+	return idle[0]
+}
+
+func TestProcessManager_CustomScheduler(t *testing.T) {
+	manager := NewProcessManager("/tmp/logs", nil)
+	manager.agents["agent-a"] = &AgentSlot{AgentID: "agent-a", Status: AgentStatusIdle, AgentType: AgentTypeClaude}
+	manager.agents["agent-b"] = &AgentSlot{AgentID: "agent-b", Status: AgentStatusIdle, AgentType: AgentTypeCodex}
+	manager.agents["agent-c"] = &AgentSlot{AgentID: "agent-c", Status: AgentStatusBusy, AgentType: AgentTypeGemini}
+
+	scheduler := &typeScheduler{}
+	manager.SetScheduler(scheduler)
+
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "t1", Description: "needs codex"}); slot == nil || slot.AgentID != "agent-b" {
+		t.Errorf("codex task went to %+v, want agent-b", slot)
+	}
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "t2", Description: "anything"}); slot == nil || slot.AgentID != "agent-a" {
+		t.Errorf("untyped task went to %+v, want agent-a", slot)
+	}
+
+	// The only gemini agent is busy, so the task is held back
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "t3", Description: "needs gemini"}); slot != nil {
+		t.Errorf("gemini task went to %s, want it held back", slot.AgentID)
+	}
+
+	if want := []string{"t1", "t2", "t3"}; !slices.Equal(scheduler.seen, want) {
+		t.Errorf("scheduler saw tasks %v, want %v", scheduler.seen, want)
+	}
+
+	// The scheduler isn't consulted when no agent is idle
+	manager.agents["agent-a"].Status = AgentStatusBusy
+	manager.agents["agent-b"].Status = AgentStatusBusy
+	if slot := manager.FindAvailableAgent(&mapv1.Task{TaskId: "t4"}); slot != nil {
+		t.Errorf("FindAvailableAgent = %s, want nil with no idle agents", slot.AgentID)
+	}
+	if len(scheduler.seen) != 3 {
+		t.Errorf("scheduler called %d times, want 3", len(scheduler.seen))
+	}
+}
+
+func TestNewScheduler(t *testing.T) {
+	for _, strategy := range []string{SchedulingRoundRobin, SchedulingLeastLoaded, SchedulingRandom} {
+		if s, err := NewScheduler(strategy); err != nil || s == nil {
+			t.Errorf("NewScheduler(%q) = %v, %v", strategy, s, err)
+		}
+	}
+	if _, err := NewScheduler("fastest"); err == nil {
+		t.Error("NewScheduler should reject unknown strategies")
+	}
+}
@@ -3,11 +3,14 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -21,6 +24,10 @@ import (
 const (
 	DefaultSocketPath = "/tmp/mapd.sock"
 	DefaultDataDir    = "~/.mapd"
+	DefaultSocketMode = os.FileMode(0600)
+	DefaultMaxAgents  = 20
+	// LogFileName is the daemon's log file inside the data directory
+	LogFileName = "mapd.log"
 )
 
 // Server is the main daemon server
@@ -34,9 +41,15 @@ type Server struct {
 	names        *NameGenerator
 	githubPoller *GitHubPoller
 	inputMonitor *InputMonitor
+	eventLog     *EventLog
+	warmPool     *WarmPool
+	scheduler    *TaskScheduler
 	eventCh      chan *mapv1.Event
 	dataDir      string
 
+	// drops counts the events dropped since the server started
+	drops eventDrops
+
 	grpcServer *grpc.Server
 	listener   net.Listener
 	startedAt  time.Time
@@ -45,40 +58,234 @@ type Server struct {
 	watchers   map[string]chan *mapv1.Event
 	shutdown   chan struct{}
 	socketPath string
+	socketMode os.FileMode
+	version    string
+
+	// githubTimeout bounds each gh call made while serving a request
+	githubTimeout time.Duration
+
+	// allowedRepos restricts the repositories agents may be spawned against
+	allowedRepos *RepoAllowlist
+
+	// logHub captures log output for StreamLogs; logFile keeps a copy in
+	// the data dir
+	logHub  *LogHub
+	logFile *rotatingFile
+
+	// dataLock keeps other daemons off this daemon's data directory
+	dataLock *dataDirLock
+
+	// Auto-spawn settings for tasks submitted with no idle agents
+	autoSpawnMu   sync.Mutex
+	autoSpawnMax  int
+	autoSpawnType string
+
+	// skipPermissions is the permission mode for daemon-spawned agents
+	skipPermissions bool
+
+	// maxAgents caps live agents; spawning counts agents being spawned so
+	// concurrent requests can't overshoot the cap together
+	spawnMu   sync.Mutex
+	maxAgents int
+	spawning  int
 }
 
 // Config holds daemon configuration
 type Config struct {
 	SocketPath string
+	// SocketMode is applied to the socket after it is created (default 0600)
+	SocketMode os.FileMode
 	DataDir    string
+	// SchedulingStrategy selects how tasks are assigned to idle agents:
+	// round-robin (default), least-loaded, or random
+	SchedulingStrategy string
+	// AutoSpawn spawns a worktree-isolated agent when a task is submitted
+	// and no agent is idle, up to AutoSpawnMax running agents
+	AutoSpawn     bool
+	AutoSpawnMax  int
+	AutoSpawnType string
+	// WarmPoolSize keeps at least this many idle agents alive in
+	// WarmPoolRepo (default: the daemon's repo), spawning WarmPoolType agents
+	WarmPoolSize int
+	WarmPoolRepo string
+	WarmPoolType string
+	// RequirePermissions starts daemon-spawned agents (auto-spawn, warm
+	// pool) with permission prompts instead of permission-bypassing flags
+	RequirePermissions bool
+	// MaxRetries caps how many times a failed task may be retried (0 = no cap)
+	MaxRetries int
+	// MaxDescriptionBytes caps the size of task descriptions (0 = no cap)
+	MaxDescriptionBytes int
+	// MaxResultBytes caps the task results kept in the database (default
+	// DefaultMaxResultBytes, negative = no cap). Larger results are
+	// truncated, or with ResultFiles written to <data-dir>/results.
+	MaxResultBytes int
+	ResultFiles    bool
+	// BranchTemplate, if set, creates each agent worktree on a new branch
+	// named by this text/template (fields: AgentID, Date, Base)
+	BranchTemplate string
+	// Version is the build version reported by GetStatus (default "dev")
+	Version string
+	// MaxAgents caps the number of live agents; spawn requests that would
+	// exceed it are refused (default DefaultMaxAgents, negative = no cap)
+	MaxAgents int
+	// GitHubTimeout bounds each gh invocation made by the daemon
+	// (default DefaultGitHubTimeout)
+	GitHubTimeout time.Duration
+	// QuestionWebhookURL is a Slack or Discord incoming webhook that
+	// questions detected by the input monitor are posted to ("" = none).
+	// NoGitHubQuestions stops them being posted to the task's GitHub issue.
+	QuestionWebhookURL string
+	NoGitHubQuestions  bool
+	// PromptConfirmWait is how long to wait for an agent's initial prompt
+	// to appear in its pane (default DefaultPromptConfirmWait), and
+	// PromptRetries how many times to resend it if it doesn't (0 or
+	// negative = send once; callers pass DefaultPromptRetries for the
+	// default)
+	PromptConfirmWait time.Duration
+	PromptRetries     int
+	// SubmitKeys are the tmux key names sent after a prompt's text to
+	// submit it, by agent type (types not listed use their spec's
+	// SubmitKeys)
+	SubmitKeys map[string][]string
+	// StuckThreshold is how long a busy agent's pane may stay unchanged,
+	// with no activity or question showing, before an agent-stuck event is
+	// emitted (default DefaultStuckThreshold, negative = off)
+	StuckThreshold time.Duration
+	// BootTimeout is how long a new agent's CLI must stay running before
+	// its spawn succeeds (default DefaultBootTimeout, negative = no check)
+	BootTimeout time.Duration
+	// HeartbeatInterval is how often a heartbeat event is emitted for each
+	// live agent (default DefaultHeartbeatInterval, negative = off)
+	HeartbeatInterval time.Duration
+	// AgentRuntime runs agents' CLIs natively ("native" or empty) or in a
+	// per-agent container ("docker" or "podman") from AgentImage
+	AgentRuntime string
+	AgentImage   string
+	// AllowedRepos lists the repository paths or patterns agents may be
+	// spawned against (empty = any repository)
+	AllowedRepos []string
+	// EventBuffer is how many events can wait to be broadcast before new
+	// ones are dropped (default DefaultEventBuffer)
+	EventBuffer int
+	// RoutingRules choose the agent type tasks submitted without one
+	// prefer. The first matching rule wins.
+	RoutingRules []RoutingRule
 }
 
 // NewServer creates a new daemon server
 func NewServer(cfg *Config) (*Server, error) {
-	if cfg.SocketPath == "" {
-		cfg.SocketPath = DefaultSocketPath
+	socketPath, err := PrepareSocketPath(cfg.SocketPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.SocketPath = socketPath
+	if cfg.SocketMode == 0 {
+		cfg.SocketMode = DefaultSocketMode
 	}
 	if cfg.DataDir == "" {
 		cfg.DataDir = expandPath(DefaultDataDir)
 	}
+	if cfg.Version == "" {
+		cfg.Version = "dev"
+	}
+	if cfg.MaxAgents == 0 {
+		cfg.MaxAgents = DefaultMaxAgents
+	}
+	if cfg.MaxResultBytes == 0 {
+		cfg.MaxResultBytes = DefaultMaxResultBytes
+	}
+	if cfg.StuckThreshold == 0 {
+		cfg.StuckThreshold = DefaultStuckThreshold
+	}
+	if cfg.BootTimeout == 0 {
+		cfg.BootTimeout = DefaultBootTimeout
+	}
+	if cfg.HeartbeatInterval == 0 {
+		cfg.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if cfg.EventBuffer <= 0 {
+		cfg.EventBuffer = DefaultEventBuffer
+	}
+	if cfg.GitHubTimeout <= 0 {
+		cfg.GitHubTimeout = DefaultGitHubTimeout
+	}
+
+	// Refuse to share the data directory with another daemon
+	dataLock, err := lockDataDir(cfg.DataDir)
+	if err != nil {
+		return nil, err
+	}
 
 	store, err := NewStore(cfg.DataDir)
 	if err != nil {
+		dataLock.Release()
 		return nil, fmt.Errorf("init store: %w", err)
 	}
+	store.SetResultStorage(cfg.MaxResultBytes, cfg.ResultFiles)
 
-	eventCh := make(chan *mapv1.Event, 100)
+	eventCh := make(chan *mapv1.Event, cfg.EventBuffer)
 
 	worktrees, err := NewWorktreeManager(cfg.DataDir)
 	if err != nil {
+		_ = store.Close()
+		dataLock.Release()
 		return nil, fmt.Errorf("init worktree manager: %w", err)
 	}
+	if err := worktrees.SetBranchTemplate(cfg.BranchTemplate); err != nil {
+		_ = store.Close()
+		dataLock.Release()
+		return nil, err
+	}
 
 	processes := NewProcessManager(cfg.DataDir, eventCh)
+	processes.SetPromptDelivery(cfg.PromptConfirmWait, cfg.PromptRetries)
+	processes.SetSubmitKeys(cfg.SubmitKeys)
+	processes.SetMessageStore(store)
+	processes.SetBootTimeout(cfg.BootTimeout)
+	if err := processes.SetRuntime(cfg.AgentRuntime, cfg.AgentImage); err != nil {
+		_ = store.Close()
+		dataLock.Release()
+		return nil, err
+	}
+	if cfg.SchedulingStrategy != "" {
+		if err := processes.SetSchedulingStrategy(cfg.SchedulingStrategy); err != nil {
+			_ = store.Close()
+			dataLock.Release()
+			return nil, err
+		}
+	}
+	routing, err := NewTaskRouting(cfg.RoutingRules)
+	if err != nil {
+		_ = store.Close()
+		dataLock.Release()
+		return nil, err
+	}
+	// The agents offered tasks by the last daemon are gone, and so are the
+	// timers that would have withdrawn the offers
+	if n, err := store.RequeueOfferedTasks(); err != nil {
+		log.Printf("warning: failed to requeue offered tasks: %v", err)
+	} else if n > 0 {
+		log.Printf("returned %d offered task(s) to pending", n)
+	}
 	tasks := NewTaskRouter(store, processes, eventCh)
+	tasks.SetRouting(routing)
+	tasks.SetMaxRetries(cfg.MaxRetries)
+	tasks.SetMaxDescriptionBytes(cfg.MaxDescriptionBytes)
+	tasks.SetGitHubTimeout(cfg.GitHubTimeout)
 	names := NewNameGenerator()
 	githubPoller := NewGitHubPoller(store, processes, eventCh)
+	githubPoller.SetTimeout(cfg.GitHubTimeout)
 	inputMonitor := NewInputMonitor(store, processes, eventCh)
+	sinks, err := questionSinks(cfg)
+	if err != nil {
+		_ = store.Close()
+		dataLock.Release()
+		return nil, err
+	}
+	inputMonitor.SetQuestionSinks(sinks...)
+	inputMonitor.SetStuckThreshold(cfg.StuckThreshold)
+	inputMonitor.SetHeartbeatInterval(cfg.HeartbeatInterval)
 
 	// Wire up callback to process pending tasks when agents become available
 	processes.SetOnAgentAvailable(tasks.ProcessPendingTasks)
@@ -91,18 +298,204 @@ func NewServer(cfg *Config) (*Server, error) {
 		names:        names,
 		githubPoller: githubPoller,
 		inputMonitor: inputMonitor,
+		eventLog:     NewEventLog(store),
+		scheduler:    NewTaskScheduler(store, tasks),
 		eventCh:      eventCh,
 		dataDir:      cfg.DataDir,
 		watchers:     make(map[string]chan *mapv1.Event),
 		shutdown:     make(chan struct{}),
 		socketPath:   cfg.SocketPath,
+		socketMode:   cfg.SocketMode,
+		version:      cfg.Version,
+		maxAgents:    cfg.MaxAgents,
+
+		skipPermissions: !cfg.RequirePermissions,
+		githubTimeout:   cfg.GitHubTimeout,
+		allowedRepos:    NewRepoAllowlist(cfg.AllowedRepos),
+		logHub:          NewLogHub(),
+		dataLock:        dataLock,
+	}
+	tasks.SetRemoveAgent(s.removeFinishedAgent)
+
+	// Count the events the components drop on a full channel against this
+	// server. Nothing emits until the server starts, so the counters are
+	// wired before any reads.
+	processes.drops = &s.drops
+	tasks.drops = &s.drops
+	githubPoller.drops = &s.drops
+	inputMonitor.drops = &s.drops
+	githubPoller.SetOnTaskDone(tasks.applyOnComplete)
+
+	if cfg.AutoSpawn {
+		s.autoSpawnMax = cfg.AutoSpawnMax
+		if s.autoSpawnMax < 1 {
+			s.autoSpawnMax = 1
+		}
+		s.autoSpawnType = cfg.AutoSpawnType
+		if s.autoSpawnType == "" {
+			s.autoSpawnType = AgentTypeClaude
+		}
+		tasks.SetAutoSpawn(s.autoSpawnAgent)
+	}
+
+	if cfg.WarmPoolSize > 0 {
+		repoRoot := expandPath(cfg.WarmPoolRepo)
+		if repoRoot == "" {
+			repoRoot = worktrees.GetRepoRoot()
+		}
+		if repoRoot == "" {
+			log.Printf("warm pool disabled: no repository to scope it to")
+		} else {
+			agentType := cfg.WarmPoolType
+			if agentType == "" {
+				agentType = AgentTypeClaude
+			}
+			s.warmPool = NewWarmPool(processes, store, cfg.WarmPoolSize, repoRoot,
+				func(repoRoot string) (string, error) { return s.spawnPoolAgent(repoRoot, agentType) },
+				func(agentID string) {
+					// Reaped agents are idle, but hand back anything still assigned
+					_, _ = s.KillAgent(context.Background(), &mapv1.KillAgentRequest{AgentId: agentID, RequeueTasks: true})
+				},
+			)
+			processes.SetOnAgentAvailable(func() {
+				tasks.ProcessPendingTasks()
+				s.warmPool.Nudge()
+			})
+		}
 	}
 
 	return s, nil
 }
 
+// spawnPoolAgent spawns one worktree-isolated agent for the warm pool
+func (s *Server) spawnPoolAgent(repoRoot, agentType string) (string, error) {
+	resp, err := s.SpawnAgent(context.Background(), &mapv1.SpawnAgentRequest{
+		Count:            1,
+		UseWorktree:      true,
+		AgentType:        agentType,
+		SkipPermissions:  s.skipPermissions,
+		WorkingDirectory: repoRoot,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Agents) == 0 {
+		return "", fmt.Errorf("no agent spawned")
+	}
+	return resp.Agents[0].AgentId, nil
+}
+
+// autoSpawnAgent spawns a worktree-isolated agent for a task that found no
+// idle agent. The new agent picks up pending tasks through the process
+// manager's agent-available callback. Returns false if the auto-spawn cap is
+// reached or spawning fails.
+func (s *Server) autoSpawnAgent(repoRoot string) bool {
+	s.autoSpawnMu.Lock()
+	defer s.autoSpawnMu.Unlock()
+
+	if running := len(s.processes.List()); running >= s.autoSpawnMax {
+		log.Printf("auto-spawn: %d agents running (max %d), leaving task pending", running, s.autoSpawnMax)
+		return false
+	}
+
+	resp, err := s.SpawnAgent(context.Background(), &mapv1.SpawnAgentRequest{
+		Count:            1,
+		UseWorktree:      true,
+		AgentType:        s.autoSpawnType,
+		SkipPermissions:  s.skipPermissions,
+		WorkingDirectory: repoRoot,
+	})
+	if err != nil {
+		log.Printf("auto-spawn: failed to spawn agent: %v", err)
+		return false
+	}
+	return len(resp.Agents) > 0
+}
+
+// reserveAgents claims room for count new agents under the max-agents cap.
+// The returned release func must be called once spawning has finished.
+func (s *Server) reserveAgents(count int) (func(), error) {
+	s.spawnMu.Lock()
+	defer s.spawnMu.Unlock()
+
+	if s.maxAgents > 0 {
+		current := len(s.processes.List()) + s.spawning
+		if current+count > s.maxAgents {
+			return nil, resourceExhaustedf("spawning %d agent(s) would exceed the limit of %d (currently %d running); raise agent.max-agents or kill idle agents", count, s.maxAgents, current)
+		}
+	}
+	s.spawning += count
+
+	return func() {
+		s.spawnMu.Lock()
+		s.spawning -= count
+		s.spawnMu.Unlock()
+	}, nil
+}
+
+// ParseSocketMode parses an octal permission string such as "0600" or "660"
+// for the daemon socket
+func ParseSocketMode(s string) (os.FileMode, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "0o"), 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid socket mode %q: must be octal, e.g. 0600", s)
+	}
+	if v == 0 || v > 0777 {
+		return 0, fmt.Errorf("invalid socket mode %q: must be between 0001 and 0777", s)
+	}
+	return os.FileMode(v), nil
+}
+
+// maxSocketPathLen is the longest unix socket path every supported platform
+// accepts (macOS allows 104 bytes, Linux 108)
+const maxSocketPathLen = 103
+
+// ResolveSocketPath expands a leading ~ in a socket path and makes it
+// absolute, then checks it could be listened on: it must not name a
+// directory, must fit in a unix socket address, and its parent must be a
+// directory if it exists. An empty path means DefaultSocketPath.
+func ResolveSocketPath(path string) (string, error) {
+	if path == "" {
+		path = DefaultSocketPath
+	}
+	if strings.HasSuffix(path, "/") {
+		return "", fmt.Errorf("invalid socket path %q: names a directory, not a socket file", path)
+	}
+	abs, err := filepath.Abs(expandPath(path))
+	if err != nil {
+		return "", fmt.Errorf("invalid socket path %q: %w", path, err)
+	}
+	if len(abs) > maxSocketPathLen {
+		return "", fmt.Errorf("invalid socket path %q: %d bytes long, the limit is %d", abs, len(abs), maxSocketPathLen)
+	}
+	if info, err := os.Stat(abs); err == nil && info.IsDir() {
+		return "", fmt.Errorf("invalid socket path %q: is a directory", abs)
+	}
+
+	dir := filepath.Dir(abs)
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		return "", fmt.Errorf("invalid socket path %q: %s is not a directory", abs, dir)
+	}
+	return abs, nil
+}
+
+// PrepareSocketPath resolves a socket path for the daemon to listen on,
+// creating its parent directory if it doesn't exist yet
+func PrepareSocketPath(path string) (string, error) {
+	abs, err := ResolveSocketPath(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0700); err != nil {
+		return "", fmt.Errorf("create socket directory: %w", err)
+	}
+	return abs, nil
+}
+
 // Start begins listening for connections
 func (s *Server) Start() error {
+	s.captureLogs()
+
 	// Remove existing socket
 	_ = os.Remove(s.socketPath)
 
@@ -112,12 +505,20 @@ func (s *Server) Start() error {
 	}
 	s.listener = listener
 
+	// Restrict who can talk to the daemon
+	if err := os.Chmod(s.socketPath, s.socketMode); err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("chmod socket: %w", err)
+	}
+	log.Printf("socket permissions set to %#o", s.socketMode)
+
 	s.grpcServer = grpc.NewServer()
 	mapv1.RegisterDaemonServiceServer(s.grpcServer, s)
 
 	s.startedAt = time.Now()
 
-	// Start event broadcaster
+	// Start event broadcaster and the log that persists broadcast events
+	s.eventLog.Start()
 	go s.broadcastEvents()
 
 	// Start GitHub poller for bidirectional issue sync
@@ -126,6 +527,14 @@ func (s *Server) Start() error {
 	// Start input monitor to detect when agents are waiting for user input
 	s.inputMonitor.Start()
 
+	// Start warm pool to keep a floor of idle agents
+	if s.warmPool != nil {
+		s.warmPool.Start()
+	}
+
+	// Start scheduler to submit recurring tasks
+	s.scheduler.Start()
+
 	log.Printf("mapd listening on %s", s.socketPath)
 	return s.grpcServer.Serve(listener)
 }
@@ -144,6 +553,16 @@ func (s *Server) Stop() {
 		s.inputMonitor.Stop()
 	}
 
+	// Stop warm pool before killing agents so it doesn't respawn them
+	if s.warmPool != nil {
+		s.warmPool.Stop()
+	}
+
+	// Stop scheduler
+	if s.scheduler != nil {
+		s.scheduler.Stop()
+	}
+
 	// Kill all spawned processes
 	if s.processes != nil {
 		_ = s.processes.KillAll()
@@ -157,25 +576,65 @@ func (s *Server) Stop() {
 	if s.grpcServer != nil {
 		s.grpcServer.GracefulStop()
 	}
+	// The event log is only started once the server is listening
+	if s.eventLog != nil && s.listener != nil {
+		s.eventLog.Stop()
+	}
 	if s.store != nil {
 		_ = s.store.Close()
 	}
+	s.dataLock.Release()
 	_ = os.Remove(s.socketPath)
+
+	if s.logFile != nil {
+		log.SetOutput(os.Stderr)
+		_ = s.logFile.Close()
+	}
+}
+
+// captureLogs sends log output to the log hub for StreamLogs and appends it
+// to the log file in the data dir, as well as to stderr. A daemon started in
+// the background has no stderr, so the file is its only persistent log. The
+// file is rotated at MaxLogBytes.
+func (s *Server) captureLogs() {
+	writers := []io.Writer{os.Stderr, s.logHub}
+	f, err := openRotatingFile(filepath.Join(s.dataDir, LogFileName), MaxLogBytes)
+	if err != nil {
+		log.Printf("warning: cannot open log file: %v", err)
+	} else {
+		s.logFile = f
+		writers = append(writers, f)
+	}
+	log.SetOutput(io.MultiWriter(writers...))
 }
 
 // broadcastEvents sends events to all watchers
 func (s *Server) broadcastEvents() {
+	ticker := time.NewTicker(eventDropLogInterval)
+	defer ticker.Stop()
+	drops := eventDropReporter{drops: &s.drops}
+
 	for {
 		select {
 		case <-s.shutdown:
 			return
+		case <-ticker.C:
+			drops.report()
 		case event := <-s.eventCh:
+			// Heartbeats are only of interest live, so they are not
+			// kept in the event log
+			if event.Type != mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT {
+				s.eventLog.Record(event)
+			}
+			s.updateWindowTitle(event)
+
 			s.mu.RLock()
 			for _, ch := range s.watchers {
 				select {
 				case ch <- event:
 				default:
 					// Drop if watcher is slow
+					s.drops.watcher.Add(1)
 				}
 			}
 			s.mu.RUnlock()
@@ -199,7 +658,7 @@ func (s *Server) ListTasks(ctx context.Context, req *mapv1.ListTasksRequest) (*m
 		statusFilter = taskStatusToString(req.StatusFilter)
 	}
 
-	tasks, err := s.tasks.ListTasks(statusFilter, req.AgentFilter, req.GetRepoRoot(), int(req.Limit))
+	tasks, err := s.tasks.ListTasks(statusFilter, req.AgentFilter, req.GetRepoRoot(), int(req.Limit), req.GetIncludeArchived())
 	if err != nil {
 		return nil, err
 	}
@@ -213,7 +672,7 @@ func (s *Server) GetTask(ctx context.Context, req *mapv1.GetTaskRequest) (*mapv1
 		return nil, err
 	}
 	if task == nil {
-		return nil, fmt.Errorf("task not found: %s", req.TaskId)
+		return nil, notFoundf("task not found: %s", req.TaskId)
 	}
 	return &mapv1.GetTaskResponse{Task: task}, nil
 }
@@ -226,6 +685,145 @@ func (s *Server) CancelTask(ctx context.Context, req *mapv1.CancelTaskRequest) (
 	return &mapv1.CancelTaskResponse{Task: task}, nil
 }
 
+func (s *Server) RetryTask(ctx context.Context, req *mapv1.RetryTaskRequest) (*mapv1.RetryTaskResponse, error) {
+	if !req.AllFailed {
+		task, err := s.tasks.RetryTask(req.TaskId)
+		if err != nil {
+			return nil, err
+		}
+		return &mapv1.RetryTaskResponse{Retried: []*mapv1.Task{task}}, nil
+	}
+
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+	retried, skipped, err := s.tasks.RetryFailedTasks(req.RepoRoot, since)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.RetryTaskResponse{Retried: retried, Skipped: skipped}, nil
+}
+
+func (s *Server) CreateScheduledTask(ctx context.Context, req *mapv1.CreateScheduledTaskRequest) (*mapv1.CreateScheduledTaskResponse, error) {
+	sched, err := s.scheduler.Create(req)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.CreateScheduledTaskResponse{Schedule: scheduledTaskToProto(sched)}, nil
+}
+
+func (s *Server) ListScheduledTasks(ctx context.Context, req *mapv1.ListScheduledTasksRequest) (*mapv1.ListScheduledTasksResponse, error) {
+	records, err := s.store.ListScheduledTasks()
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+	schedules := make([]*mapv1.ScheduledTask, 0, len(records))
+	for _, rec := range records {
+		schedules = append(schedules, scheduledTaskToProto(rec))
+	}
+	return &mapv1.ListScheduledTasksResponse{Schedules: schedules}, nil
+}
+
+func (s *Server) DeleteScheduledTask(ctx context.Context, req *mapv1.DeleteScheduledTaskRequest) (*mapv1.DeleteScheduledTaskResponse, error) {
+	deleted, err := s.store.DeleteScheduledTask(req.GetScheduleId())
+	if err != nil {
+		return nil, fmt.Errorf("delete schedule: %w", err)
+	}
+	if !deleted {
+		return nil, notFoundf("schedule not found: %s", req.GetScheduleId())
+	}
+	return &mapv1.DeleteScheduledTaskResponse{}, nil
+}
+
+func (s *Server) ReopenTask(ctx context.Context, req *mapv1.ReopenTaskRequest) (*mapv1.ReopenTaskResponse, error) {
+	task, err := s.tasks.ReopenTask(req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.ReopenTaskResponse{Task: task}, nil
+}
+
+func (s *Server) ArchiveTask(ctx context.Context, req *mapv1.ArchiveTaskRequest) (*mapv1.ArchiveTaskResponse, error) {
+	task, err := s.tasks.ArchiveTask(req.GetTaskId())
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.ArchiveTaskResponse{Task: task}, nil
+}
+
+// ExportTasks streams every task, oldest first
+func (s *Server) ExportTasks(req *mapv1.ExportTasksRequest, stream mapv1.DaemonService_ExportTasksServer) error {
+	tasks, err := s.tasks.ExportTasks()
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if err := stream.Send(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportTasks collects every batch the client sends, then imports them
+// together, so a bad task anywhere in the export imports nothing
+func (s *Server) ImportTasks(stream mapv1.DaemonService_ImportTasksServer) error {
+	var tasks []*mapv1.Task
+	var overwrite bool
+	for first := true; ; first = false {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first {
+			overwrite = req.GetOverwrite()
+		}
+		tasks = append(tasks, req.GetTasks()...)
+	}
+
+	imported, skipped, err := s.tasks.ImportTasks(tasks, overwrite)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&mapv1.ImportTasksResponse{Imported: int32(imported), SkippedIds: skipped})
+}
+
+func (s *Server) NudgeTask(ctx context.Context, req *mapv1.NudgeTaskRequest) (*mapv1.NudgeTaskResponse, error) {
+	task, err := s.tasks.NudgeTask(ctx, req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.NudgeTaskResponse{Task: task}, nil
+}
+
+func (s *Server) AcceptTask(ctx context.Context, req *mapv1.AcceptTaskRequest) (*mapv1.AcceptTaskResponse, error) {
+	task, err := s.tasks.AcceptTask(req.TaskId, req.AgentId)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.AcceptTaskResponse{Task: task}, nil
+}
+
+func (s *Server) RejectTask(ctx context.Context, req *mapv1.RejectTaskRequest) (*mapv1.RejectTaskResponse, error) {
+	task, err := s.tasks.RejectTask(req.TaskId, req.AgentId, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.RejectTaskResponse{Task: task}, nil
+}
+
+func (s *Server) ListTaskAttempts(ctx context.Context, req *mapv1.ListTaskAttemptsRequest) (*mapv1.ListTaskAttemptsResponse, error) {
+	attempts, err := s.tasks.ListTaskAttempts(req.TaskId)
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.ListTaskAttemptsResponse{Attempts: attempts}, nil
+}
+
 func (s *Server) Shutdown(ctx context.Context, req *mapv1.ShutdownRequest) (*mapv1.ShutdownResponse, error) {
 	go func() {
 		time.Sleep(100 * time.Millisecond)
@@ -236,17 +834,43 @@ func (s *Server) Shutdown(ctx context.Context, req *mapv1.ShutdownRequest) (*map
 
 func (s *Server) GetStatus(ctx context.Context, req *mapv1.GetStatusRequest) (*mapv1.GetStatusResponse, error) {
 	pending, active, _ := s.store.GetStats()
-	spawnedAgents := len(s.processes.List())
+	idle, busy := s.processes.CountByStatus(s.store)
 
 	return &mapv1.GetStatusResponse{
-		Running:         true,
-		StartedAt:       timestamppb.New(s.startedAt),
-		ConnectedAgents: int32(spawnedAgents),
-		PendingTasks:    int32(pending),
-		ActiveTasks:     int32(active),
+		Running:              true,
+		StartedAt:            timestamppb.New(s.startedAt),
+		ConnectedAgents:      int32(idle + busy),
+		PendingTasks:         int32(pending),
+		ActiveTasks:          int32(active),
+		Version:              s.version,
+		Multiplexer:          MultiplexerTmux,
+		IdleAgents:           int32(idle),
+		BusyAgents:           int32(busy),
+		SchedulerPaused:      s.tasks.Paused(),
+		DataDir:              s.dataDir,
+		DroppedEvents:        s.drops.full.Load(),
+		DroppedWatcherEvents: s.drops.watcher.Load(),
 	}, nil
 }
 
+// SetSchedulerPaused pauses or resumes assigning tasks to agents
+func (s *Server) SetSchedulerPaused(ctx context.Context, req *mapv1.SetSchedulerPausedRequest) (*mapv1.SetSchedulerPausedResponse, error) {
+	changed := s.tasks.SetPaused(req.GetPaused())
+	if changed {
+		if req.GetPaused() {
+			log.Printf("scheduler paused")
+		} else {
+			log.Printf("scheduler resumed")
+		}
+	}
+	return &mapv1.SetSchedulerPausedResponse{Paused: req.GetPaused(), Changed: changed}, nil
+}
+
+// Ping is a liveness probe that confirms the gRPC server is serving requests
+func (s *Server) Ping(ctx context.Context, req *mapv1.PingRequest) (*mapv1.PingResponse, error) {
+	return &mapv1.PingResponse{ServerTime: timestamppb.Now()}, nil
+}
+
 func (s *Server) WatchEvents(req *mapv1.WatchEventsRequest, stream mapv1.DaemonService_WatchEventsServer) error {
 	watcherID := uuid.New().String()
 	watchCh := make(chan *mapv1.Event, 50)
@@ -268,7 +892,11 @@ func (s *Server) WatchEvents(req *mapv1.WatchEventsRequest, stream mapv1.DaemonS
 		case <-s.shutdown:
 			return nil
 		case event := <-watchCh:
-			// Apply filters
+			// Apply filters. Heartbeats are only sent to watchers that
+			// ask for them.
+			if event.Type == mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT && !req.GetIncludeHeartbeats() && len(req.TypeFilter) == 0 {
+				continue
+			}
 			if len(req.TypeFilter) > 0 {
 				found := false
 				for _, t := range req.TypeFilter {
@@ -289,6 +917,157 @@ func (s *Server) WatchEvents(req *mapv1.WatchEventsRequest, stream mapv1.DaemonS
 	}
 }
 
+// StreamLogs sends recent daemon log lines at or above the requested level
+// and, with follow, new lines as they are logged
+func (s *Server) StreamLogs(req *mapv1.StreamLogsRequest, stream mapv1.DaemonService_StreamLogsServer) error {
+	minLevel := req.GetMinLevel()
+	if minLevel == "" {
+		minLevel = LogLevelInfo
+	}
+	if !ValidLogLevel(minLevel) {
+		return invalidArgumentf("invalid log level %q: must be info, warn, or error", minLevel)
+	}
+
+	backlog, ch, unsubscribe := s.logHub.Subscribe()
+	defer unsubscribe()
+
+	var recent []LogEntry
+	for _, entry := range backlog {
+		if LogLevelAtLeast(entry.Level, minLevel) {
+			recent = append(recent, entry)
+		}
+	}
+	if tail := int(req.GetTail()); tail < len(recent) {
+		recent = recent[len(recent)-max(tail, 0):]
+	}
+	for _, entry := range recent {
+		if err := stream.Send(logEntryToProto(entry)); err != nil {
+			return err
+		}
+	}
+
+	if !req.GetFollow() {
+		return nil
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.shutdown:
+			return nil
+		case entry := <-ch:
+			if !LogLevelAtLeast(entry.Level, minLevel) {
+				continue
+			}
+			if err := stream.Send(logEntryToProto(entry)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) StreamAgentOutput(req *mapv1.StreamAgentOutputRequest, stream mapv1.DaemonService_StreamAgentOutputServer) error {
+	agentID := req.GetAgentId()
+	if agentID == "" {
+		return invalidArgumentf("agent_id is required")
+	}
+	slot := s.processes.Get(agentID)
+	if slot == nil {
+		return notFoundf("agent %s not found", agentID)
+	}
+	slot.mu.Lock()
+	session, headless := slot.TmuxSession, slot.Headless
+	slot.mu.Unlock()
+	if headless {
+		return failedPreconditionf("agent %s is headless and has no pane to follow", agentID)
+	}
+
+	ctx := stream.Context()
+	send := func(lines []string) error {
+		now := timestamppb.Now()
+		for _, line := range lines {
+			if err := stream.Send(&mapv1.AgentOutputLine{Timestamp: now, Line: line}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	prev, err := capturePaneLines(ctx, session, "-")
+	if err != nil {
+		return fmt.Errorf("capture pane for agent %s: %w", agentID, err)
+	}
+	if tail := int(req.GetTail()); tail > 0 {
+		if err := send(prev[max(len(prev)-tail, 0):]); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(paneStreamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.shutdown:
+			return nil
+		case <-ticker.C:
+		}
+
+		cur, err := capturePaneLines(ctx, session, "-")
+		if err != nil {
+			// The session is gone: the agent was killed or exited
+			return nil
+		}
+		if err := send(paneDelta(prev, cur)); err != nil {
+			return err
+		}
+		prev = cur
+	}
+}
+
+func logEntryToProto(entry LogEntry) *mapv1.LogEntry {
+	return &mapv1.LogEntry{
+		Timestamp: timestamppb.New(entry.Time),
+		Level:     entry.Level,
+		Message:   entry.Message,
+	}
+}
+
+func (s *Server) ListEvents(ctx context.Context, req *mapv1.ListEventsRequest) (*mapv1.ListEventsResponse, error) {
+	var types []string
+	for _, t := range req.TypeFilter {
+		types = append(types, t.String())
+	}
+
+	var since time.Time
+	if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+
+	records, err := s.store.ListEvents(EventFilter{
+		Types:  types,
+		TaskID: req.TaskId,
+		Since:  since,
+		Limit:  int(req.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list events: %w", err)
+	}
+
+	events := make([]*mapv1.Event, 0, len(records))
+	for _, r := range records {
+		event, err := eventFromRecord(r)
+		if err != nil {
+			log.Printf("skipping event: %v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return &mapv1.ListEventsResponse{Events: events}, nil
+}
+
 // --- Spawned Agent Management ---
 
 func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (*mapv1.SpawnAgentResponse, error) {
@@ -297,12 +1076,31 @@ func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 		count = 1
 	}
 
+	release, err := s.reserveAgents(count)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Get agent type, default to "claude"
 	agentType := req.GetAgentType()
 	if agentType == "" {
 		agentType = AgentTypeClaude
 	}
 
+	for name := range req.GetEnv() {
+		if !validEnvName(name) {
+			return nil, invalidArgumentf("invalid environment variable name %q", name)
+		}
+	}
+	// Offers are answered from the agent's session, which headless agents lack
+	if req.GetOfferTasks() && req.GetOutputOnly() {
+		return nil, invalidArgumentf("headless agents can't be offered tasks")
+	}
+	if !validOnComplete(req.GetOnComplete()) {
+		return nil, invalidArgumentf("invalid on_complete %q: must be idle, keep, or kill", req.GetOnComplete())
+	}
+
 	namePrefix := req.GetNamePrefix()
 
 	var agents []*mapv1.SpawnedAgentInfo
@@ -325,7 +1123,28 @@ func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 		repoRoot = s.worktrees.GetRepoRoot()
 	}
 
+	// Agents without a worktree work directly in the client's directory,
+	// which may be outside the repo the daemon fell back to
+	target := repoRoot
+	if !req.GetUseWorktree() && clientWorkDir != "" && !pathWithin(clientWorkDir, repoRoot) {
+		target = clientWorkDir
+	}
+	if !s.allowedRepos.Allows(target) {
+		return nil, permissionDeniedf("not authorized: repository %q is not in the daemon's security.allowed-repos list", target)
+	}
+
+	spawnDelay := time.Duration(req.GetSpawnDelayMs()) * time.Millisecond
+
 	for i := 0; i < count; i++ {
+		// Stagger bulk spawns so the CLIs don't all boot at once
+		if i > 0 && spawnDelay > 0 {
+			select {
+			case <-time.After(spawnDelay):
+			case <-ctx.Done():
+				return &mapv1.SpawnAgentResponse{Agents: agents}, ctx.Err()
+			}
+		}
+
 		var agentID string
 		if namePrefix != "" {
 			// Custom prefix provided: use prefix-uuid format
@@ -346,6 +1165,13 @@ func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 			}
 			workdir = wt.Path
 			worktreePath = wt.Path
+
+			if req.GetGitUserName() != "" || req.GetGitUserEmail() != "" {
+				if err := s.worktrees.ConfigureIdentity(agentID, req.GetGitUserName(), req.GetGitUserEmail()); err != nil {
+					_ = s.worktrees.Remove(agentID)
+					return nil, fmt.Errorf("configure git identity for %s: %w", agentID, err)
+				}
+			}
 		} else {
 			// Use the client's working directory, repo root, or daemon's cwd
 			if clientWorkDir != "" {
@@ -361,36 +1187,52 @@ func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 			}
 		}
 
+		// Hold the agent before it exists, as it takes pending tasks as soon
+		// as it is up
+		if req.GetHoldForTask() {
+			s.processes.Hold(agentID)
+		}
+
 		// Create the agent slot
-		// Skip permissions by default for autonomous agent operation
-		// Permissions are skipped when: explicitly requested via skip_permissions OR using worktrees
-		// Since proto3 bools default to false, we default to true for autonomous operation
-		skipPermissions := req.GetSkipPermissions() || req.GetUseWorktree()
-		if !req.GetSkipPermissions() && !req.GetUseWorktree() {
-			// Neither flag set - default to skipping permissions for autonomous operation
-			skipPermissions = true
-		}
-		slot, err := s.processes.Spawn(agentID, workdir, req.GetPrompt(), agentType, repoRoot, skipPermissions)
+		// The client resolves the permission mode from its flags and config
+		// (agent.skip-permissions), so the request is honored as-is
+		var slot *AgentSlot
+		if req.GetOutputOnly() {
+			slot, err = s.processes.SpawnHeadless(agentID, workdir, req.GetPrompt(), agentType, repoRoot, req.GetSkipPermissions(), req.GetEnv())
+		} else {
+			slot, err = s.processes.Spawn(agentID, workdir, req.GetPrompt(), agentType, repoRoot, req.GetSkipPermissions(), req.GetClaudeContinue(), req.GetEnv())
+		}
 		if err != nil {
+			s.processes.Unhold(agentID)
 			// Cleanup worktree if we created one
 			if worktreePath != "" {
 				_ = s.worktrees.Remove(agentID)
 			}
 			return nil, fmt.Errorf("create agent %s: %w", agentID, err)
 		}
+		slot.mu.Lock()
+		slot.Isolated = worktreePath != ""
+		slot.OfferTasks = req.GetOfferTasks()
+		slot.OnComplete = req.GetOnComplete()
+		if len(req.GetLabels()) > 0 {
+			slot.Labels = maps.Clone(req.GetLabels())
+		}
+		slot.mu.Unlock()
 
 		// Store in database
 		now := time.Now()
 		record := &SpawnedAgentRecord{
 			AgentID:      agentID,
 			WorktreePath: worktreePath,
-			PID:          0, // No persistent process in new model
+			PID:          slotPID(slot),
 			Branch:       req.GetBranch(),
 			Prompt:       req.GetPrompt(),
 			Status:       AgentStatusIdle,
 			CreatedAt:    now,
 			UpdatedAt:    now,
 			RepoRoot:     repoRoot,
+			Labels:       req.GetLabels(),
+			Multiplexer:  slot.Multiplexer,
 		}
 		if err := s.store.CreateSpawnedAgent(record); err != nil {
 			log.Printf("failed to store spawned agent %s: %v", agentID, err)
@@ -407,7 +1249,7 @@ func (s *Server) SpawnAgent(ctx context.Context, req *mapv1.SpawnAgentRequest) (
 func (s *Server) KillAgent(ctx context.Context, req *mapv1.KillAgentRequest) (*mapv1.KillAgentResponse, error) {
 	agentID := req.GetAgentId()
 	if agentID == "" {
-		return nil, fmt.Errorf("agent_id is required")
+		return nil, invalidArgumentf("agent_id is required")
 	}
 
 	slot := s.processes.Get(agentID)
@@ -418,6 +1260,14 @@ func (s *Server) KillAgent(ctx context.Context, req *mapv1.KillAgentRequest) (*m
 		}, nil
 	}
 
+	// Settle the agent's tasks while its worktree is still there to record
+	// what the interrupted attempts changed
+	requeue := req.GetRequeueTasks()
+	released, err := s.tasks.ReleaseAgentTasks(agentID, requeue)
+	if err != nil {
+		log.Printf("failed to release tasks of %s: %v", agentID, err)
+	}
+
 	// Cleanup worktree if one was created
 	if slot.WorktreePath != "" {
 		if err := s.worktrees.Remove(agentID); err != nil {
@@ -434,9 +1284,19 @@ func (s *Server) KillAgent(ctx context.Context, req *mapv1.KillAgentRequest) (*m
 	// Remove from process manager
 	s.processes.Remove(agentID)
 
+	message := fmt.Sprintf("agent %s removed", agentID)
+	if len(released) > 0 {
+		if requeue {
+			message += fmt.Sprintf("; %d task(s) requeued", len(released))
+			go s.tasks.ProcessPendingTasks()
+		} else {
+			message += fmt.Sprintf("; %d task(s) cancelled", len(released))
+		}
+	}
+
 	return &mapv1.KillAgentResponse{
 		Success: true,
-		Message: fmt.Sprintf("agent %s removed", agentID),
+		Message: message,
 	}, nil
 }
 
@@ -451,6 +1311,13 @@ func (s *Server) ListSpawnedAgents(ctx context.Context, req *mapv1.ListSpawnedAg
 		if repoFilter != "" && info.RepoRoot != repoFilter {
 			continue
 		}
+		if !sp.HasLabels(req.GetLabels()) {
+			continue
+		}
+		// Headless agents have no session to lose
+		if req.GetStale() && (sp.Headless || s.processes.HasTmuxSession(sp.AgentID)) {
+			continue
+		}
 		agents = append(agents, info)
 	}
 
@@ -460,7 +1327,7 @@ func (s *Server) ListSpawnedAgents(ctx context.Context, req *mapv1.ListSpawnedAg
 func (s *Server) RespawnAgent(ctx context.Context, req *mapv1.RespawnAgentRequest) (*mapv1.RespawnAgentResponse, error) {
 	agentID := req.GetAgentId()
 	if agentID == "" {
-		return nil, fmt.Errorf("agent_id is required")
+		return nil, invalidArgumentf("agent_id is required")
 	}
 
 	slot := s.processes.Get(agentID)
@@ -471,9 +1338,12 @@ func (s *Server) RespawnAgent(ctx context.Context, req *mapv1.RespawnAgentReques
 		}, nil
 	}
 
-	// Respawn with skip permissions if agent has a worktree (isolated environment)
-	skipPermissions := slot.WorktreePath != ""
-	if err := s.processes.RespawnInPane(agentID, skipPermissions); err != nil {
+	// Respawn with the same permission mode the agent was created with,
+	// unless the caller asks for permission prompts
+	slot.mu.Lock()
+	skipPermissions := slot.SkipPermissions && !req.GetRequirePermissions()
+	slot.mu.Unlock()
+	if err := s.processes.RespawnInPane(agentID, skipPermissions, req.GetForce()); err != nil {
 		return &mapv1.RespawnAgentResponse{
 			Success: false,
 			Message: err.Error(),
@@ -510,6 +1380,68 @@ func (s *Server) ListWorktrees(ctx context.Context, req *mapv1.ListWorktreesRequ
 	return &mapv1.ListWorktreesResponse{Worktrees: infos}, nil
 }
 
+// CreateWorktree gives an agent spawned without a worktree one of its own.
+// tmux can't change a running pane's directory, so the agent's CLI keeps
+// working in the shared checkout unless the request asks for a restart.
+func (s *Server) CreateWorktree(ctx context.Context, req *mapv1.CreateWorktreeRequest) (*mapv1.CreateWorktreeResponse, error) {
+	agentID := req.GetAgentId()
+	if agentID == "" {
+		return nil, invalidArgumentf("agent_id is required")
+	}
+	slot := s.processes.Get(agentID)
+	if slot == nil {
+		return nil, notFoundf("agent %s not found", agentID)
+	}
+
+	slot.mu.Lock()
+	isolated, repoRoot := slot.Isolated, slot.RepoRoot
+	headless, skipPermissions := slot.Headless, slot.SkipPermissions
+	slot.mu.Unlock()
+
+	if isolated || s.worktrees.Get(agentID) != nil {
+		return nil, alreadyExistsf("agent %s already has a worktree", agentID)
+	}
+	if repoRoot == "" {
+		return nil, failedPreconditionf("agent %s was not spawned in a git repository", agentID)
+	}
+
+	wt, err := s.worktrees.CreateFromRepo(agentID, req.GetBranch(), repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("create worktree for %s: %w", agentID, err)
+	}
+	if req.GetGitUserName() != "" || req.GetGitUserEmail() != "" {
+		if err := s.worktrees.ConfigureIdentity(agentID, req.GetGitUserName(), req.GetGitUserEmail()); err != nil {
+			_ = s.worktrees.Remove(agentID)
+			return nil, fmt.Errorf("configure git identity for %s: %w", agentID, err)
+		}
+	}
+	if err := s.processes.MoveToWorktree(agentID, wt.Path); err != nil {
+		_ = s.worktrees.Remove(agentID)
+		return nil, err
+	}
+	if err := s.store.UpdateSpawnedAgentWorktree(agentID, wt.Path); err != nil {
+		log.Printf("failed to record worktree for %s: %v", agentID, err)
+	}
+	log.Printf("created worktree for agent %s at %s", agentID, wt.Path)
+
+	resp := &mapv1.CreateWorktreeResponse{
+		Worktree: &mapv1.WorktreeInfo{
+			AgentId:   wt.AgentID,
+			Path:      wt.Path,
+			Branch:    wt.Branch,
+			CreatedAt: timestamppb.New(wt.CreatedAt),
+			RepoRoot:  wt.RepoRoot,
+		},
+	}
+	if req.GetRestart() && !headless {
+		if err := s.processes.RespawnInPane(agentID, skipPermissions, true); err != nil {
+			return nil, fmt.Errorf("worktree created at %s, but restart agent: %w", wt.Path, err)
+		}
+		resp.Restarted = true
+	}
+	return resp, nil
+}
+
 func (s *Server) CleanupWorktrees(ctx context.Context, req *mapv1.CleanupWorktreesRequest) (*mapv1.CleanupWorktreesResponse, error) {
 	if req.GetAgentId() != "" {
 		// Cleanup specific agent's worktree
@@ -535,6 +1467,16 @@ func (s *Server) CleanupWorktrees(ctx context.Context, req *mapv1.CleanupWorktre
 	}, nil
 }
 
+func (s *Server) PruneBranches(ctx context.Context, req *mapv1.PruneBranchesRequest) (*mapv1.PruneBranchesResponse, error) {
+	runningAgents := s.processes.ListRunning()
+	branches, err := s.worktrees.PruneBranches(req.GetRepoRoot(), runningAgents, req.GetDryRun())
+	if err != nil {
+		return nil, fmt.Errorf("prune branches: %w", err)
+	}
+
+	return &mapv1.PruneBranchesResponse{Branches: branches}, nil
+}
+
 // --- Task Input Management ---
 
 func (s *Server) RequestInput(ctx context.Context, req *mapv1.RequestInputRequest) (*mapv1.RequestInputResponse, error) {
@@ -542,10 +1484,10 @@ func (s *Server) RequestInput(ctx context.Context, req *mapv1.RequestInputReques
 	question := req.GetQuestion()
 
 	if taskID == "" {
-		return nil, fmt.Errorf("task_id is required")
+		return nil, invalidArgumentf("task_id is required")
 	}
 	if question == "" {
-		return nil, fmt.Errorf("question is required")
+		return nil, invalidArgumentf("question is required")
 	}
 
 	// Get the task
@@ -569,7 +1511,7 @@ func (s *Server) RequestInput(ctx context.Context, req *mapv1.RequestInputReques
 	}
 
 	// Post comment to GitHub
-	if err := PostQuestionToGitHub(task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, question); err != nil {
+	if err := PostQuestionToGitHub(ctx, s.githubTimeout, task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, question); err != nil {
 		return &mapv1.RequestInputResponse{
 			Success: false,
 			Message: fmt.Sprintf("failed to post to GitHub: %v", err),
@@ -584,6 +1526,8 @@ func (s *Server) RequestInput(ctx context.Context, req *mapv1.RequestInputReques
 		}, nil
 	}
 
+	s.processes.recordMessage(task.AssignedTo, taskID, MessageKindQuestion, AgentMessageReceived, question)
+
 	// Emit event
 	s.emitTaskWaitingInputEvent(task, question)
 
@@ -596,7 +1540,7 @@ func (s *Server) RequestInput(ctx context.Context, req *mapv1.RequestInputReques
 func (s *Server) GetCurrentTask(ctx context.Context, req *mapv1.GetCurrentTaskRequest) (*mapv1.GetCurrentTaskResponse, error) {
 	workingDir := req.GetWorkingDirectory()
 	if workingDir == "" {
-		return nil, fmt.Errorf("working_directory is required")
+		return nil, invalidArgumentf("working_directory is required")
 	}
 
 	// Find the agent by worktree path
@@ -622,6 +1566,89 @@ func (s *Server) GetCurrentTask(ctx context.Context, req *mapv1.GetCurrentTaskRe
 	}, nil
 }
 
+// GetAgentTask returns the in-progress or waiting-for-input task assigned to
+// an agent. An agent without an active task gets an empty response.
+func (s *Server) GetAgentTask(ctx context.Context, req *mapv1.GetAgentTaskRequest) (*mapv1.GetAgentTaskResponse, error) {
+	agentID := req.GetAgentId()
+	if agentID == "" {
+		return nil, invalidArgumentf("agent_id is required")
+	}
+
+	task, err := s.store.GetTaskByAgentID(agentID)
+	if err != nil {
+		return nil, fmt.Errorf("get task: %w", err)
+	}
+	if task == nil {
+		return &mapv1.GetAgentTaskResponse{Task: nil}, nil
+	}
+
+	return &mapv1.GetAgentTaskResponse{
+		Task: s.tasks.taskRecordToProtoWithGitHub(task),
+	}, nil
+}
+
+func (s *Server) GetAgentHistory(ctx context.Context, req *mapv1.GetAgentHistoryRequest) (*mapv1.GetAgentHistoryResponse, error) {
+	if req.GetAgentId() == "" {
+		return nil, invalidArgumentf("agent_id is required")
+	}
+
+	records, err := s.store.ListAgentMessages(req.GetAgentId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, fmt.Errorf("list agent messages: %w", err)
+	}
+
+	messages := make([]*mapv1.AgentMessage, 0, len(records))
+	for _, rec := range records {
+		messages = append(messages, agentMessageToProto(rec))
+	}
+	return &mapv1.GetAgentHistoryResponse{Messages: messages}, nil
+}
+
+// slotPID returns the PID of the process in a new agent's pane, or 0 if it
+// has none (headless agents, or a pane that has already exited)
+func slotPID(slot *AgentSlot) int {
+	if slot.Headless || slot.TmuxSession == "" {
+		return 0
+	}
+	pid, _ := panePID(slot.TmuxSession)
+	return pid
+}
+
+func (s *Server) GetAgentUsage(ctx context.Context, req *mapv1.GetAgentUsageRequest) (*mapv1.GetAgentUsageResponse, error) {
+	usage, err := s.processes.Usage(req.GetAgentId())
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.GetAgentUsageResponse{Usage: usage}, nil
+}
+
+func (s *Server) BroadcastMessage(ctx context.Context, req *mapv1.BroadcastMessageRequest) (*mapv1.BroadcastMessageResponse, error) {
+	if strings.TrimSpace(req.GetText()) == "" {
+		return nil, invalidArgumentf("text is required")
+	}
+	deliveries := s.processes.Broadcast(ctx, s.store, req.GetText(), req.GetIdleOnly(), req.GetLabels(), req.GetRepoRoot())
+	return &mapv1.BroadcastMessageResponse{Deliveries: deliveries}, nil
+}
+
+func (s *Server) ListRuns(ctx context.Context, req *mapv1.ListRunsRequest) (*mapv1.ListRunsResponse, error) {
+	runs, err := s.tasks.ListRuns(req.GetRepoRoot(), int(req.GetLimit()))
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	return &mapv1.ListRunsResponse{Runs: runs}, nil
+}
+
+func (s *Server) GetRun(ctx context.Context, req *mapv1.GetRunRequest) (*mapv1.GetRunResponse, error) {
+	if req.GetRunId() == "" {
+		return nil, invalidArgumentf("run_id is required")
+	}
+	run, tasks, err := s.tasks.GetRun(req.GetRunId())
+	if err != nil {
+		return nil, err
+	}
+	return &mapv1.GetRunResponse{Run: run, Tasks: tasks}, nil
+}
+
 func (s *Server) emitTaskWaitingInputEvent(task *TaskRecord, question string) {
 	event := &mapv1.Event{
 		EventId:   uuid.New().String(),
@@ -636,10 +1663,7 @@ func (s *Server) emitTaskWaitingInputEvent(task *TaskRecord, question string) {
 		},
 	}
 
-	select {
-	case s.eventCh <- event:
-	default:
-	}
+	sendEvent(s.eventCh, &s.drops, event)
 }
 
 // Helper functions
@@ -0,0 +1,247 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestParseSocketMode(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    os.FileMode
+		wantErr bool
+	}{
+		{"0600", 0600, false},
+		{"600", 0600, false},
+		{"0660", 0660, false},
+		{"0o770", 0770, false},
+		{"0777", 0777, false},
+		{"", 0, true},
+		{"0", 0, true},
+		{"0800", 0, true},
+		{"1777", 0, true},
+		{"rw-------", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSocketMode(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseSocketMode(%q) = %#o, want error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSocketMode(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSocketMode(%q) = %#o, want %#o", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrepareSocketPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	// ~ is expanded and a missing parent directory is created
+	got, err := PrepareSocketPath("~/run/map/mapd.sock")
+	if err != nil {
+		t.Fatalf("PrepareSocketPath: %v", err)
+	}
+	if want := filepath.Join(home, "run", "map", "mapd.sock"); got != want {
+		t.Errorf("PrepareSocketPath = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(filepath.Dir(got)); err != nil || !info.IsDir() {
+		t.Errorf("socket directory was not created: %v", err)
+	}
+
+	// Resolving alone creates nothing
+	if got, err := ResolveSocketPath("~/other/mapd.sock"); err != nil || got != filepath.Join(home, "other", "mapd.sock") {
+		t.Errorf("ResolveSocketPath = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(home, "other")); !os.IsNotExist(err) {
+		t.Errorf("ResolveSocketPath created the socket directory: %v", err)
+	}
+
+	if got, err := PrepareSocketPath(""); err != nil || got != DefaultSocketPath {
+		t.Errorf("PrepareSocketPath(\"\") = %q, %v; want the default", got, err)
+	}
+
+	file := filepath.Join(home, "file")
+	if err := os.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{
+		home,
+		home + "/",
+		filepath.Join(file, "mapd.sock"),
+		filepath.Join(home, strings.Repeat("x", maxSocketPathLen)),
+	} {
+		if _, err := PrepareSocketPath(path); err == nil {
+			t.Errorf("PrepareSocketPath(%q) should fail", path)
+		}
+	}
+}
+
+func TestServer_ReserveAgents(t *testing.T) {
+	processes := NewProcessManager("/tmp/logs", nil)
+	processes.agents["agent-a"] = &AgentSlot{AgentID: "agent-a", Status: AgentStatusIdle}
+	s := &Server{processes: processes, maxAgents: 3}
+
+	release, err := s.reserveAgents(2)
+	if err != nil {
+		t.Fatalf("reserveAgents(2) with 1 running, max 3: %v", err)
+	}
+
+	// The in-flight reservation counts against the cap
+	if _, err := s.reserveAgents(1); err == nil {
+		t.Error("expected reserveAgents(1) to fail while 2 agents are being spawned")
+	}
+
+	release()
+	if _, err := s.reserveAgents(1); err != nil {
+		t.Errorf("reserveAgents(1) after release: %v", err)
+	}
+
+	unlimited := &Server{processes: processes, maxAgents: -1}
+	if _, err := unlimited.reserveAgents(100); err != nil {
+		t.Errorf("reserveAgents with no cap: %v", err)
+	}
+}
+
+func TestServer_SpawnAgent_RepoNotAllowed(t *testing.T) {
+	worktrees, err := NewWorktreeManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewWorktreeManager: %v", err)
+	}
+	s := &Server{
+		processes:    NewProcessManager(t.TempDir(), nil),
+		worktrees:    worktrees,
+		allowedRepos: NewRepoAllowlist([]string{"/srv/allowed/*"}),
+	}
+
+	_, err = s.SpawnAgent(context.Background(), &mapv1.SpawnAgentRequest{
+		Count:            1,
+		WorkingDirectory: t.TempDir(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Errorf("SpawnAgent outside the allowlist: err = %v, want an authorization error", err)
+	}
+	if len(s.processes.List()) != 0 {
+		t.Error("no agent should be created for a repository that is not allowed")
+	}
+}
+
+func TestServer_GetAgentTask(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	s := &Server{store: store, tasks: router}
+
+	task, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{Description: "Fix the build"})
+	if err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+
+	resp, err := s.GetAgentTask(context.Background(), &mapv1.GetAgentTaskRequest{AgentId: "claude-a"})
+	if err != nil {
+		t.Fatalf("GetAgentTask failed: %v", err)
+	}
+	if resp.Task != nil {
+		t.Errorf("agent with no task returned %v, want no task", resp.Task)
+	}
+
+	if err := store.AssignTask(task.TaskId, "claude-a"); err != nil {
+		t.Fatalf("AssignTask failed: %v", err)
+	}
+	if err := store.UpdateTaskStatus(task.TaskId, "in_progress"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+
+	resp, err = s.GetAgentTask(context.Background(), &mapv1.GetAgentTaskRequest{AgentId: "claude-a"})
+	if err != nil {
+		t.Fatalf("GetAgentTask failed: %v", err)
+	}
+	if resp.Task == nil || resp.Task.TaskId != task.TaskId {
+		t.Errorf("GetAgentTask = %v, want task %s", resp.Task, task.TaskId)
+	}
+
+	if _, err := s.GetAgentTask(context.Background(), &mapv1.GetAgentTaskRequest{}); err == nil {
+		t.Error("GetAgentTask without an agent ID should fail")
+	}
+}
+
+func TestServer_ListSpawnedAgents_Stale(t *testing.T) {
+	processes := NewProcessManager(t.TempDir(), nil)
+	processes.agents["agent-gone"] = &AgentSlot{AgentID: "agent-gone", TmuxSession: "map-agent-test-no-such-session", Status: AgentStatusIdle}
+	processes.agents["agent-headless"] = &AgentSlot{AgentID: "agent-headless", Headless: true, Status: AgentStatusIdle}
+	s := &Server{processes: processes}
+
+	resp, err := s.ListSpawnedAgents(context.Background(), &mapv1.ListSpawnedAgentsRequest{Stale: true})
+	if err != nil {
+		t.Fatalf("ListSpawnedAgents failed: %v", err)
+	}
+	if len(resp.Agents) != 1 || resp.Agents[0].AgentId != "agent-gone" {
+		t.Errorf("stale agents = %v, want only agent-gone", resp.Agents)
+	}
+
+	resp, err = s.ListSpawnedAgents(context.Background(), &mapv1.ListSpawnedAgentsRequest{})
+	if err != nil {
+		t.Fatalf("ListSpawnedAgents failed: %v", err)
+	}
+	if len(resp.Agents) != 2 {
+		t.Errorf("got %d agents without --stale, want 2", len(resp.Agents))
+	}
+}
+
+func TestServer_CreateWorktree(t *testing.T) {
+	repoDir := t.TempDir()
+	initTestGitRepo(t, repoDir)
+
+	worktrees, err := NewWorktreeManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewWorktreeManager: %v", err)
+	}
+	_, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	processes := NewProcessManager(t.TempDir(), nil)
+	processes.agents["agent-a"] = &AgentSlot{
+		AgentID:      "agent-a",
+		WorktreePath: repoDir,
+		RepoRoot:     repoDir,
+		Status:       AgentStatusIdle,
+		Headless:     true,
+	}
+	s := &Server{processes: processes, worktrees: worktrees, store: store}
+
+	if _, err := s.CreateWorktree(context.Background(), &mapv1.CreateWorktreeRequest{AgentId: "missing"}); err == nil {
+		t.Error("CreateWorktree for an unknown agent should fail")
+	}
+
+	resp, err := s.CreateWorktree(context.Background(), &mapv1.CreateWorktreeRequest{AgentId: "agent-a"})
+	if err != nil {
+		t.Fatalf("CreateWorktree failed: %v", err)
+	}
+
+	slot := processes.Get("agent-a")
+	if slot.WorktreePath != resp.Worktree.Path || !slot.Isolated {
+		t.Errorf("agent should now work in %s, got path %s isolated %v", resp.Worktree.Path, slot.WorktreePath, slot.Isolated)
+	}
+	if worktrees.Get("agent-a") == nil {
+		t.Error("the new worktree should be tracked for merge and cleanup")
+	}
+	if resp.Restarted {
+		t.Error("agent should not be restarted unless asked")
+	}
+
+	if _, err := s.CreateWorktree(context.Background(), &mapv1.CreateWorktreeRequest{AgentId: "agent-a"}); err == nil {
+		t.Error("CreateWorktree for an agent that already has one should fail")
+	}
+}
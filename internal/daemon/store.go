@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -14,6 +16,13 @@ import (
 // Store provides SQLite-backed persistence for the daemon
 type Store struct {
 	db *sql.DB
+
+	// dataDir holds mapd.db and spilled task results
+	dataDir string
+	// maxResultBytes caps results kept in the database (0 = no cap);
+	// larger ones are truncated, or spilled to files with spillResults
+	maxResultBytes int
+	spillResults   bool
 }
 
 // TaskRecord represents a task in the database
@@ -36,16 +45,109 @@ type TaskRecord struct {
 	WaitingInputSince    time.Time
 	// Repository root this task belongs to
 	RepoRoot string
+	// Timing: when an agent started working and when the task completed
+	StartedAt   time.Time
+	CompletedAt time.Time
+	// RetryCount is how many times the task has been re-queued after failing
+	RetryCount int
+	// RunID groups tasks submitted together, e.g. by one sync
+	RunID string
+	// PreferAgent is the agent to route the task to when it is idle. It is
+	// set to the previous assignee when the task is re-queued.
+	PreferAgent string
+	// NoWorktree marks a read-only task that doesn't need an isolated
+	// worktree and prefers an agent in the shared checkout
+	NoWorktree bool
+	// AgentType is the type of agent the task prefers, if any
+	AgentType string
+	// Environment is a JSON snapshot of what the task last ran under
+	Environment string
+	// ArchivedAt is when a finished task was archived, hiding it from
+	// listings; zero if it isn't
+	ArchivedAt time.Time
+	// RejectedBy lists the agents that declined the task when it was
+	// offered to them
+	RejectedBy []string
+	// ResultFile is where a result too large for the database was written,
+	// relative to the data directory; Result is read back from it
+	ResultFile string
+}
+
+// RunRecord summarizes the tasks in a run by status
+type RunRecord struct {
+	RunID     string
+	RepoRoot  string
+	Total     int
+	Pending   int
+	Active    int // assigned and not yet finished
+	Completed int
+	Failed    int
+	Cancelled int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TaskAttemptRecord is one run of a task by an agent. Each retry starts a new
+// attempt, so earlier outcomes are kept rather than overwritten.
+type TaskAttemptRecord struct {
+	TaskID  string
+	Attempt int // 1 for the first run
+	AgentID string
+	Status  string
+	Result  string
+	Error   string
+	// BaseCommit is the worktree's HEAD when the attempt started
+	BaseCommit   string
+	ChangedFiles []string
+	StartedAt    time.Time
+	FinishedAt   time.Time
+}
+
+// AgentMessageRecord is one message the daemon sent to an agent, or one the
+// agent sent back (such as a question for the user)
+type AgentMessageRecord struct {
+	ID        int64
+	AgentID   string
+	TaskID    string
+	Kind      string // e.g. task, nudge, input-response
+	Direction string // AgentMessageSent or AgentMessageReceived
+	Content   string
+	CreatedAt time.Time
+}
+
+// ScheduledTaskRecord is a recurring task: each time its cron schedule
+// fires, a task with its description is submitted
+type ScheduledTaskRecord struct {
+	ScheduleID  string
+	Cron        string
+	Description string
+	ScopePaths  []string
+	RepoRoot    string
+	CreatedAt   time.Time
+	// NextRunAt is when the schedule next fires; LastRunAt and LastTaskID
+	// record the last time it did and the task it submitted
+	NextRunAt  time.Time
+	LastRunAt  time.Time
+	LastTaskID string
 }
 
 // EventRecord represents an event in the database
 type EventRecord struct {
 	EventID   string
 	Type      string
+	TaskID    string // task the event concerns, if any
 	Payload   string
 	CreatedAt time.Time
 }
 
+// EventFilter narrows ListEvents. Zero values match everything.
+type EventFilter struct {
+	Types  []string
+	TaskID string
+	Since  time.Time
+	Limit  int
+}
+
 // SpawnedAgentRecord represents a spawned agent in the database
 type SpawnedAgentRecord struct {
 	AgentID      string
@@ -58,6 +160,11 @@ type SpawnedAgentRecord struct {
 	UpdatedAt    time.Time
 	// Repository root the agent was spawned from
 	RepoRoot string
+	// Labels are user-defined key/value tags, stored as JSON
+	Labels map[string]string
+	// Multiplexer hosting the agent's session, e.g. "tmux" ("" for
+	// headless agents)
+	Multiplexer string
 }
 
 const schema = `
@@ -77,16 +184,43 @@ CREATE TABLE IF NOT EXISTS tasks (
 	last_comment_id TEXT,
 	waiting_input_question TEXT,
 	waiting_input_since INTEGER,
-	repo_root TEXT
+	repo_root TEXT,
+	started_at INTEGER,
+	completed_at INTEGER,
+	retry_count INTEGER DEFAULT 0,
+	run_id TEXT,
+	prefer_agent TEXT,
+	no_worktree INTEGER DEFAULT 0,
+	agent_type TEXT,
+	environment TEXT,
+	archived_at INTEGER DEFAULT 0,
+	rejected_by TEXT,
+	result_file TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
 CREATE INDEX IF NOT EXISTS idx_tasks_assigned_to ON tasks(assigned_to);
--- Note: idx_tasks_github is created in migrate() to support existing databases
+-- Note: idx_tasks_github and other indexes on later columns are created by
+-- the migrations in store_migrations.go
+
+CREATE TABLE IF NOT EXISTS task_attempts (
+	task_id TEXT NOT NULL,
+	attempt INTEGER NOT NULL,
+	agent_id TEXT,
+	status TEXT,
+	result TEXT,
+	error TEXT,
+	base_commit TEXT,
+	changed_files TEXT,
+	started_at INTEGER,
+	finished_at INTEGER,
+	PRIMARY KEY (task_id, attempt)
+);
 
 CREATE TABLE IF NOT EXISTS events (
 	event_id TEXT PRIMARY KEY,
 	type TEXT NOT NULL,
+	task_id TEXT,
 	payload TEXT,
 	created_at INTEGER NOT NULL
 );
@@ -103,10 +237,36 @@ CREATE TABLE IF NOT EXISTS spawned_agents (
 	status TEXT DEFAULT 'running',
 	created_at INTEGER NOT NULL,
 	updated_at INTEGER NOT NULL,
-	repo_root TEXT
+	repo_root TEXT,
+	labels TEXT,
+	multiplexer TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_spawned_agents_status ON spawned_agents(status);
+
+CREATE TABLE IF NOT EXISTS agent_messages (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	agent_id TEXT NOT NULL,
+	task_id TEXT,
+	kind TEXT NOT NULL,
+	direction TEXT NOT NULL,
+	content TEXT,
+	created_at INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_messages_agent_id ON agent_messages(agent_id);
+
+CREATE TABLE IF NOT EXISTS scheduled_tasks (
+	schedule_id TEXT PRIMARY KEY,
+	cron TEXT NOT NULL,
+	description TEXT NOT NULL,
+	scope_paths TEXT,
+	repo_root TEXT,
+	created_at INTEGER NOT NULL,
+	next_run_at INTEGER,
+	last_run_at INTEGER,
+	last_task_id TEXT
+);
 `
 
 // NewStore creates a new SQLite store
@@ -116,7 +276,10 @@ func NewStore(dataDir string) (*Store, error) {
 	}
 
 	dbPath := filepath.Join(dataDir, "mapd.db")
-	db, err := sql.Open("sqlite", dbPath)
+	// Writers wait for one another, e.g. behind an import's transaction,
+	// instead of failing with SQLITE_BUSY. The pragma is set on every
+	// pooled connection.
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=busy_timeout(5000)")
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -134,7 +297,7 @@ func NewStore(dataDir string) (*Store, error) {
 	}
 
 	// Run migrations for existing databases
-	store := &Store{db: db}
+	store := &Store{db: db, dataDir: dataDir}
 	if err := store.migrate(); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("migrate: %w", err)
@@ -148,76 +311,124 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
-// migrate adds new columns to existing databases
-func (s *Store) migrate() error {
-	migrations := []string{
-		"ALTER TABLE tasks ADD COLUMN github_owner TEXT",
-		"ALTER TABLE tasks ADD COLUMN github_repo TEXT",
-		"ALTER TABLE tasks ADD COLUMN github_issue_number INTEGER",
-		"ALTER TABLE tasks ADD COLUMN last_comment_id TEXT",
-		"ALTER TABLE tasks ADD COLUMN waiting_input_question TEXT",
-		"ALTER TABLE tasks ADD COLUMN waiting_input_since INTEGER",
-		"ALTER TABLE tasks ADD COLUMN repo_root TEXT",
-		"ALTER TABLE spawned_agents ADD COLUMN repo_root TEXT",
-	}
+// --- Task Operations ---
 
-	for _, m := range migrations {
-		// Ignore errors - column may already exist
-		_, _ = s.db.Exec(m)
-	}
+// taskColumns is the column list scanned by scanTaskFields
+const taskColumns = `task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
+		github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root,
+		started_at, completed_at, retry_count, run_id, prefer_agent, no_worktree, agent_type, environment, archived_at, rejected_by, result_file`
 
-	// Ensure indexes exist
-	_, _ = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_tasks_github ON tasks(github_owner, github_repo, github_issue_number)")
-	_, _ = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_tasks_repo_root ON tasks(repo_root)")
-	_, _ = s.db.Exec("CREATE INDEX IF NOT EXISTS idx_spawned_agents_repo_root ON spawned_agents(repo_root)")
+// CreateTask creates a new task
+func (s *Store) CreateTask(task *TaskRecord) error {
+	return s.insertTask(s.db, task)
+}
 
-	return nil
+// ReplaceTask swaps the task with task's ID for task, in one transaction so
+// a failed insert leaves the old task in place
+func (s *Store) ReplaceTask(task *TaskRecord) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(`DELETE FROM tasks WHERE task_id = ?`, task.TaskID); err != nil {
+		return err
+	}
+	if err := s.insertTask(tx, task); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// --- Task Operations ---
+// execer runs statements on the store's database or a transaction on it
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
 
-// CreateTask creates a new task
-func (s *Store) CreateTask(task *TaskRecord) error {
+// insertTask inserts task through db
+func (s *Store) insertTask(db execer, task *TaskRecord) error {
 	paths, err := json.Marshal(task.ScopePaths)
 	if err != nil {
 		return fmt.Errorf("marshal scope paths: %w", err)
 	}
 
+	rejectedBy, err := json.Marshal(task.RejectedBy)
+	if err != nil {
+		return fmt.Errorf("marshal rejected by: %w", err)
+	}
+
+	result, resultFile, err := s.storeResult(task.TaskID, task.Result)
+	if err != nil {
+		return err
+	}
+
 	var waitingInputSince int64
 	if !task.WaitingInputSince.IsZero() {
 		waitingInputSince = task.WaitingInputSince.Unix()
 	}
 
-	_, err = s.db.Exec(`
+	_, err = db.Exec(`
 		INSERT INTO tasks (task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root,
+			started_at, completed_at, retry_count, run_id, prefer_agent, no_worktree, agent_type, environment, archived_at, rejected_by, result_file)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, task.TaskID, task.Description, string(paths), task.Status, task.AssignedTo,
-		task.Result, task.Error, task.CreatedAt.Unix(), task.UpdatedAt.Unix(),
+		result, task.Error, task.CreatedAt.Unix(), task.UpdatedAt.Unix(),
 		task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, task.LastCommentID,
-		task.WaitingInputQuestion, waitingInputSince, task.RepoRoot)
+		task.WaitingInputQuestion, waitingInputSince, task.RepoRoot,
+		unixOrZero(task.StartedAt), unixOrZero(task.CompletedAt), task.RetryCount, task.RunID, task.PreferAgent, task.NoWorktree, task.AgentType, task.Environment,
+		unixOrZero(task.ArchivedAt), string(rejectedBy), resultFile)
 
 	return err
 }
 
-// GetTask retrieves a task by ID
+// SetTaskEnvironment stores the JSON snapshot of what a task is running under
+func (s *Store) SetTaskEnvironment(taskID, environment string) error {
+	_, err := s.db.Exec(`UPDATE tasks SET environment = ? WHERE task_id = ?`, environment, taskID)
+	return err
+}
+
+// ArchiveTask marks a task archived as of at
+func (s *Store) ArchiveTask(taskID string, at time.Time) error {
+	_, err := s.db.Exec(`UPDATE tasks SET archived_at = ? WHERE task_id = ?`, at.Unix(), taskID)
+	return err
+}
+
+// DeleteTask removes a task
+func (s *Store) DeleteTask(taskID string) error {
+	if _, err := s.db.Exec(`DELETE FROM tasks WHERE task_id = ?`, taskID); err != nil {
+		return err
+	}
+	s.removeResultFile(taskID)
+	return nil
+}
+
+// GetTask retrieves a task by ID, reading back a result that was spilled
+// to a file
 func (s *Store) GetTask(taskID string) (*TaskRecord, error) {
 	row := s.db.QueryRow(`
-		SELECT task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root
+		SELECT `+taskColumns+`
 		FROM tasks WHERE task_id = ?
 	`, taskID)
 
-	return s.scanTask(row)
+	task, err := s.scanTask(row)
+	s.loadResult(task)
+	return task, err
 }
 
-// ListTasks retrieves tasks with optional filters
-func (s *Store) ListTasks(statusFilter, agentFilter, repoRoot string, limit int) ([]*TaskRecord, error) {
-	query := `SELECT task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-		github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root
+// ListTasks retrieves tasks with optional filters. Archived tasks are only
+// included with includeArchived. Spilled results are left in their files,
+// with only ResultFile set.
+func (s *Store) ListTasks(statusFilter, agentFilter, repoRoot string, limit int, includeArchived bool) ([]*TaskRecord, error) {
+	query := `SELECT ` + taskColumns + `
 		FROM tasks WHERE 1=1`
 	args := []any{}
 
+	if !includeArchived {
+		query += " AND COALESCE(archived_at, 0) = 0"
+	}
+
 	if statusFilter != "" {
 		query += " AND status = ?"
 		args = append(args, statusFilter)
@@ -268,28 +479,123 @@ func (s *Store) UpdateTask(task *TaskRecord) error {
 		waitingInputSince = task.WaitingInputSince.Unix()
 	}
 
+	result, resultFile, err := s.storeResult(task.TaskID, task.Result)
+	if err != nil {
+		return err
+	}
+
 	_, err = s.db.Exec(`
 		UPDATE tasks SET description = ?, scope_paths = ?, status = ?, assigned_to = ?,
-			result = ?, error = ?, updated_at = ?,
+			result = ?, result_file = ?, error = ?, updated_at = ?,
 			github_owner = ?, github_repo = ?, github_issue_number = ?, last_comment_id = ?,
-			waiting_input_question = ?, waiting_input_since = ?, repo_root = ?
+			waiting_input_question = ?, waiting_input_since = ?, repo_root = ?,
+			started_at = ?, completed_at = ?, retry_count = ?
 		WHERE task_id = ?
 	`, task.Description, string(paths), task.Status, task.AssignedTo,
-		task.Result, task.Error, task.UpdatedAt.Unix(),
+		result, resultFile, task.Error, task.UpdatedAt.Unix(),
 		task.GitHubOwner, task.GitHubRepo, task.GitHubIssueNumber, task.LastCommentID,
-		task.WaitingInputQuestion, waitingInputSince, task.RepoRoot, task.TaskID)
+		task.WaitingInputQuestion, waitingInputSince, task.RepoRoot,
+		unixOrZero(task.StartedAt), unixOrZero(task.CompletedAt), task.RetryCount, task.TaskID)
 
 	return err
 }
 
-// UpdateTaskStatus updates a task's status
+// UpdateTaskStatus updates a task's status.
+// The first transition to in_progress records started_at, and a transition
+// to completed records completed_at.
 func (s *Store) UpdateTaskStatus(taskID, status string) error {
+	now := time.Now().Unix()
 	_, err := s.db.Exec(`
-		UPDATE tasks SET status = ?, updated_at = ? WHERE task_id = ?
-	`, status, time.Now().Unix(), taskID)
+		UPDATE tasks SET status = ?, updated_at = ?,
+			started_at = CASE WHEN ? = 'in_progress' AND COALESCE(started_at, 0) = 0 THEN ? ELSE started_at END,
+			completed_at = CASE WHEN ? = 'completed' THEN ? ELSE completed_at END
+		WHERE task_id = ?
+	`, status, now, status, now, status, now, taskID)
+	return err
+}
+
+// RequeueTask resets a task to pending for another attempt, clearing its
+// assignment, outcome, and timing, and increments its retry count
+func (s *Store) RequeueTask(taskID string) error {
+	_, err := s.db.Exec(`
+		UPDATE tasks SET status = 'pending', assigned_to = '', result = '', result_file = '', error = '',
+			prefer_agent = CASE WHEN COALESCE(assigned_to, '') != '' THEN assigned_to ELSE prefer_agent END,
+			waiting_input_question = '', waiting_input_since = 0,
+			started_at = 0, completed_at = 0, archived_at = 0,
+			retry_count = COALESCE(retry_count, 0) + 1, updated_at = ?
+		WHERE task_id = ?
+	`, time.Now().Unix(), taskID)
+	if err == nil {
+		s.removeResultFile(taskID)
+	}
 	return err
 }
 
+// ReopenTask moves a completed task back into work as a new attempt. With
+// agentID set the task resumes in_progress on that agent; otherwise it goes
+// back to pending, preferring the agent that completed it.
+func (s *Store) ReopenTask(taskID, agentID string) error {
+	now := time.Now().Unix()
+	if agentID != "" {
+		_, err := s.db.Exec(`
+			UPDATE tasks SET status = 'in_progress', assigned_to = ?, result = '', result_file = '', error = '',
+				started_at = ?, completed_at = 0, archived_at = 0,
+				retry_count = COALESCE(retry_count, 0) + 1, updated_at = ?
+			WHERE task_id = ?
+		`, agentID, now, now, taskID)
+		if err == nil {
+			s.removeResultFile(taskID)
+		}
+		return err
+	}
+
+	_, err := s.db.Exec(`
+		UPDATE tasks SET status = 'pending', assigned_to = '', result = '', result_file = '', error = '',
+			prefer_agent = CASE WHEN COALESCE(assigned_to, '') != '' THEN assigned_to ELSE prefer_agent END,
+			started_at = 0, completed_at = 0, archived_at = 0,
+			retry_count = COALESCE(retry_count, 0) + 1, updated_at = ?
+		WHERE task_id = ?
+	`, now, taskID)
+	if err == nil {
+		s.removeResultFile(taskID)
+	}
+	return err
+}
+
+// OfferTask offers a task to an agent, which may accept or reject it
+func (s *Store) OfferTask(taskID, agentID string) error {
+	_, err := s.db.Exec(`
+		UPDATE tasks SET assigned_to = ?, status = 'offered', updated_at = ? WHERE task_id = ?
+	`, agentID, time.Now().Unix(), taskID)
+	return err
+}
+
+// WithdrawOffer returns an offered task to pending, recording the agents
+// that have declined it
+func (s *Store) WithdrawOffer(taskID string, rejectedBy []string) error {
+	data, err := json.Marshal(rejectedBy)
+	if err != nil {
+		return fmt.Errorf("marshal rejected by: %w", err)
+	}
+	_, err = s.db.Exec(`
+		UPDATE tasks SET status = 'pending', assigned_to = '', rejected_by = ?, updated_at = ? WHERE task_id = ?
+	`, string(data), time.Now().Unix(), taskID)
+	return err
+}
+
+// RequeueOfferedTasks returns every offered task to pending and reports how
+// many there were. Offers time out in memory, so ones left open when the
+// daemon stopped would otherwise never be answered.
+func (s *Store) RequeueOfferedTasks() (int64, error) {
+	res, err := s.db.Exec(`
+		UPDATE tasks SET status = 'pending', assigned_to = '', updated_at = ? WHERE status = 'offered'
+	`, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // AssignTask assigns a task to an agent
 func (s *Store) AssignTask(taskID, instanceID string) error {
 	_, err := s.db.Exec(`
@@ -301,8 +607,7 @@ func (s *Store) AssignTask(taskID, instanceID string) error {
 // ListTasksWaitingInput returns tasks with status=waiting_input that have GitHub sources
 func (s *Store) ListTasksWaitingInput() ([]*TaskRecord, error) {
 	rows, err := s.db.Query(`
-		SELECT task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE status = 'waiting_input' AND github_owner != '' AND github_repo != '' AND github_issue_number > 0
 		ORDER BY waiting_input_since ASC
@@ -326,8 +631,7 @@ func (s *Store) ListTasksWaitingInput() ([]*TaskRecord, error) {
 // ListTasksInProgressWithGitHub returns tasks with status=in_progress that have GitHub sources
 func (s *Store) ListTasksInProgressWithGitHub() ([]*TaskRecord, error) {
 	rows, err := s.db.Query(`
-		SELECT task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE status = 'in_progress' AND github_owner != '' AND github_repo != '' AND github_issue_number > 0
 		ORDER BY updated_at DESC
@@ -358,6 +662,20 @@ func (s *Store) SetTaskWaitingInput(taskID, question string) error {
 	return err
 }
 
+// SetTaskGitHubSource links a task to a GitHub issue unless it already has
+// one. It reports whether the task was updated.
+func (s *Store) SetTaskGitHubSource(taskID, owner, repo string, issueNumber int) (bool, error) {
+	res, err := s.db.Exec(`
+		UPDATE tasks SET github_owner = ?, github_repo = ?, github_issue_number = ?, updated_at = ?
+		WHERE task_id = ? AND github_issue_number = 0
+	`, owner, repo, issueNumber, time.Now().Unix(), taskID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 // ClearTaskWaitingInput clears the waiting input state and returns task to in_progress
 func (s *Store) ClearTaskWaitingInput(taskID, lastCommentID string) error {
 	now := time.Now()
@@ -372,8 +690,7 @@ func (s *Store) ClearTaskWaitingInput(taskID, lastCommentID string) error {
 // GetTaskByAgentID finds the in_progress or waiting_input task assigned to an agent
 func (s *Store) GetTaskByAgentID(agentID string) (*TaskRecord, error) {
 	row := s.db.QueryRow(`
-		SELECT task_id, description, scope_paths, status, assigned_to, result, error, created_at, updated_at,
-			github_owner, github_repo, github_issue_number, last_comment_id, waiting_input_question, waiting_input_since, repo_root
+		SELECT `+taskColumns+`
 		FROM tasks
 		WHERE assigned_to = ? AND status IN ('in_progress', 'waiting_input')
 		ORDER BY updated_at DESC LIMIT 1
@@ -381,31 +698,60 @@ func (s *Store) GetTaskByAgentID(agentID string) (*TaskRecord, error) {
 	return s.scanTask(row)
 }
 
+// AgentWorking reports whether an agent has an in_progress or waiting_input
+// task. A tmux agent's slot goes idle as soon as a task's prompt is sent, so
+// the store, not the slot, says whether it is still working.
+func (s *Store) AgentWorking(agentID string) bool {
+	var n int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*) FROM tasks
+		WHERE assigned_to = ? AND status IN ('in_progress', 'waiting_input')
+	`, agentID).Scan(&n)
+	return err == nil && n > 0
+}
+
 // GetAgentByWorktreePath finds the agent assigned to a worktree path
 func (s *Store) GetAgentByWorktreePath(worktreePath string) (*SpawnedAgentRecord, error) {
 	row := s.db.QueryRow(`
-		SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root
+		SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root, labels, multiplexer
 		FROM spawned_agents WHERE worktree_path = ?
 	`, worktreePath)
 	return s.scanSpawnedAgent(row)
 }
 
 func (s *Store) scanTask(row *sql.Row) (*TaskRecord, error) {
+	task, err := scanTaskFields(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return task, err
+}
+
+func (s *Store) scanTaskRow(rows *sql.Rows) (*TaskRecord, error) {
+	return scanTaskFields(rows)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTaskFields scans a row selected with taskColumns
+func scanTaskFields(sc rowScanner) (*TaskRecord, error) {
 	var task TaskRecord
 	var pathsJSON string
-	var assignedTo, result, taskError sql.NullString
-	var githubOwner, githubRepo, lastCommentID, waitingInputQuestion, repoRoot sql.NullString
-	var githubIssueNumber, waitingInputSince sql.NullInt64
+	var assignedTo, result, taskError, rejectedBy, resultFile sql.NullString
+	var githubOwner, githubRepo, lastCommentID, waitingInputQuestion, repoRoot, runID, preferAgent, agentType, environment sql.NullString
+	var githubIssueNumber, waitingInputSince, startedAt, completedAt, retryCount, archivedAt sql.NullInt64
 	var createdAt, updatedAt int64
+	var noWorktree sql.NullBool
 
-	err := row.Scan(&task.TaskID, &task.Description, &pathsJSON, &task.Status,
+	err := sc.Scan(&task.TaskID, &task.Description, &pathsJSON, &task.Status,
 		&assignedTo, &result, &taskError, &createdAt, &updatedAt,
 		&githubOwner, &githubRepo, &githubIssueNumber, &lastCommentID,
-		&waitingInputQuestion, &waitingInputSince, &repoRoot)
+		&waitingInputQuestion, &waitingInputSince, &repoRoot,
+		&startedAt, &completedAt, &retryCount, &runID, &preferAgent, &noWorktree, &agentType, &environment, &archivedAt, &rejectedBy, &resultFile)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
 		return nil, err
 	}
 
@@ -426,45 +772,247 @@ func (s *Store) scanTask(row *sql.Row) (*TaskRecord, error) {
 		task.WaitingInputSince = time.Unix(waitingInputSince.Int64, 0)
 	}
 	task.RepoRoot = repoRoot.String
+	task.StartedAt = timeFromUnix(startedAt)
+	task.CompletedAt = timeFromUnix(completedAt)
+	task.RetryCount = int(retryCount.Int64)
+	task.RunID = runID.String
+	task.PreferAgent = preferAgent.String
+	task.NoWorktree = noWorktree.Bool
+	task.AgentType = agentType.String
+	task.Environment = environment.String
+	task.ArchivedAt = timeFromUnix(archivedAt)
+	if rejectedBy.String != "" {
+		_ = json.Unmarshal([]byte(rejectedBy.String), &task.RejectedBy)
+	}
+	task.ResultFile = resultFile.String
 
 	return &task, nil
 }
 
-func (s *Store) scanTaskRow(rows *sql.Rows) (*TaskRecord, error) {
-	var task TaskRecord
-	var pathsJSON string
-	var assignedTo, result, taskError sql.NullString
-	var githubOwner, githubRepo, lastCommentID, waitingInputQuestion, repoRoot sql.NullString
-	var githubIssueNumber, waitingInputSince sql.NullInt64
+// runSummaryQuery aggregates tasks by run; callers append WHERE conditions
+// after "run_id != ”" and then runSummaryGroup
+const runSummaryQuery = `SELECT run_id, MAX(COALESCE(repo_root, '')), COUNT(*),
+		SUM(status = 'pending'),
+		SUM(status IN ('offered', 'accepted', 'in_progress', 'waiting_input')),
+		SUM(status = 'completed'),
+		SUM(status = 'failed'),
+		SUM(status = 'cancelled'),
+		MIN(created_at), MAX(updated_at)
+	FROM tasks WHERE COALESCE(run_id, '') != ''`
+
+const runSummaryGroup = " GROUP BY run_id ORDER BY MIN(created_at) DESC"
+
+// ListRuns summarizes runs, newest first, optionally filtered by repo
+func (s *Store) ListRuns(repoRoot string, limit int) ([]*RunRecord, error) {
+	query := runSummaryQuery
+	args := []any{}
+
+	if repoRoot != "" {
+		query += " AND repo_root = ?"
+		args = append(args, repoRoot)
+	}
+	query += runSummaryGroup
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []*RunRecord
+	for rows.Next() {
+		run, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+// GetRun summarizes one run, returning nil if it has no tasks
+func (s *Store) GetRun(runID string) (*RunRecord, error) {
+	row := s.db.QueryRow(runSummaryQuery+" AND run_id = ?"+runSummaryGroup, runID)
+	run, err := scanRun(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return run, err
+}
+
+// ListRunTasks returns the tasks in a run, oldest first
+func (s *Store) ListRunTasks(runID string) ([]*TaskRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT `+taskColumns+`
+		FROM tasks WHERE run_id = ?
+		ORDER BY created_at ASC
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tasks []*TaskRecord
+	for rows.Next() {
+		task, err := s.scanTaskRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// scanRun scans a row selected with runSummaryQuery
+func scanRun(sc rowScanner) (*RunRecord, error) {
+	var run RunRecord
 	var createdAt, updatedAt int64
+	err := sc.Scan(&run.RunID, &run.RepoRoot, &run.Total,
+		&run.Pending, &run.Active, &run.Completed, &run.Failed, &run.Cancelled,
+		&createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	run.CreatedAt = time.Unix(createdAt, 0)
+	run.UpdatedAt = time.Unix(updatedAt, 0)
+	return &run, nil
+}
 
-	err := rows.Scan(&task.TaskID, &task.Description, &pathsJSON, &task.Status,
-		&assignedTo, &result, &taskError, &createdAt, &updatedAt,
-		&githubOwner, &githubRepo, &githubIssueNumber, &lastCommentID,
-		&waitingInputQuestion, &waitingInputSince, &repoRoot)
+// ListCompletedTasks returns up to limit of the most recently completed tasks
+// with recorded start and completion times, optionally filtered by repo.
+// Used as history for duration estimates.
+func (s *Store) ListCompletedTasks(repoRoot string, limit int) ([]*TaskRecord, error) {
+	query := `SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE status = 'completed' AND started_at > 0 AND completed_at >= started_at`
+	args := []any{}
+
+	if repoRoot != "" {
+		query += " AND repo_root = ?"
+		args = append(args, repoRoot)
+	}
+
+	query += " ORDER BY completed_at DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer func() { _ = rows.Close() }()
 
-	if err := json.Unmarshal([]byte(pathsJSON), &task.ScopePaths); err != nil {
-		task.ScopePaths = []string{}
+	var tasks []*TaskRecord
+	for rows.Next() {
+		task, err := s.scanTaskRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
 	}
-	task.AssignedTo = assignedTo.String
-	task.Result = result.String
-	task.Error = taskError.String
-	task.CreatedAt = time.Unix(createdAt, 0)
-	task.UpdatedAt = time.Unix(updatedAt, 0)
-	task.GitHubOwner = githubOwner.String
-	task.GitHubRepo = githubRepo.String
-	task.GitHubIssueNumber = int(githubIssueNumber.Int64)
-	task.LastCommentID = lastCommentID.String
-	task.WaitingInputQuestion = waitingInputQuestion.String
-	if waitingInputSince.Valid && waitingInputSince.Int64 > 0 {
-		task.WaitingInputSince = time.Unix(waitingInputSince.Int64, 0)
+	return tasks, rows.Err()
+}
+
+// unixOrZero converts a time to unix seconds, storing the zero time as 0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
-	task.RepoRoot = repoRoot.String
+	return t.Unix()
+}
 
-	return &task, nil
+// timeFromUnix converts a nullable unix timestamp column to a time, treating 0 as unset
+func timeFromUnix(v sql.NullInt64) time.Time {
+	if !v.Valid || v.Int64 == 0 {
+		return time.Time{}
+	}
+	return time.Unix(v.Int64, 0)
+}
+
+// --- Task Attempt Operations ---
+
+// SaveTaskAttempt creates or replaces a task attempt
+func (s *Store) SaveTaskAttempt(attempt *TaskAttemptRecord) error {
+	files, err := json.Marshal(attempt.ChangedFiles)
+	if err != nil {
+		return fmt.Errorf("marshal changed files: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT OR REPLACE INTO task_attempts (task_id, attempt, agent_id, status, result, error,
+			base_commit, changed_files, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, attempt.TaskID, attempt.Attempt, attempt.AgentID, attempt.Status, s.limitResult(attempt.Result), attempt.Error,
+		attempt.BaseCommit, string(files), unixOrZero(attempt.StartedAt), unixOrZero(attempt.FinishedAt))
+	return err
+}
+
+// GetTaskAttempt retrieves one attempt of a task, or nil if it wasn't recorded
+func (s *Store) GetTaskAttempt(taskID string, attempt int) (*TaskAttemptRecord, error) {
+	row := s.db.QueryRow(`
+		SELECT `+taskAttemptColumns+`
+		FROM task_attempts WHERE task_id = ? AND attempt = ?
+	`, taskID, attempt)
+	rec, err := scanTaskAttemptFields(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rec, err
+}
+
+// ListTaskAttempts returns a task's attempts, oldest first
+func (s *Store) ListTaskAttempts(taskID string) ([]*TaskAttemptRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT `+taskAttemptColumns+`
+		FROM task_attempts WHERE task_id = ?
+		ORDER BY attempt ASC
+	`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attempts []*TaskAttemptRecord
+	for rows.Next() {
+		rec, err := scanTaskAttemptFields(rows)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, rec)
+	}
+	return attempts, rows.Err()
+}
+
+// taskAttemptColumns is the column list scanned by scanTaskAttemptFields
+const taskAttemptColumns = `task_id, attempt, agent_id, status, result, error, base_commit, changed_files,
+		started_at, finished_at`
+
+func scanTaskAttemptFields(sc rowScanner) (*TaskAttemptRecord, error) {
+	var rec TaskAttemptRecord
+	var agentID, status, result, attemptError, baseCommit, filesJSON sql.NullString
+	var startedAt, finishedAt sql.NullInt64
+
+	err := sc.Scan(&rec.TaskID, &rec.Attempt, &agentID, &status, &result, &attemptError,
+		&baseCommit, &filesJSON, &startedAt, &finishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(filesJSON.String), &rec.ChangedFiles); err != nil {
+		rec.ChangedFiles = nil
+	}
+	rec.AgentID = agentID.String
+	rec.Status = status.String
+	rec.Result = result.String
+	rec.Error = attemptError.String
+	rec.BaseCommit = baseCommit.String
+	rec.StartedAt = timeFromUnix(startedAt)
+	rec.FinishedAt = timeFromUnix(finishedAt)
+
+	return &rec, nil
 }
 
 // --- Event Operations ---
@@ -472,18 +1020,45 @@ func (s *Store) scanTaskRow(rows *sql.Rows) (*TaskRecord, error) {
 // CreateEvent stores a new event
 func (s *Store) CreateEvent(event *EventRecord) error {
 	_, err := s.db.Exec(`
-		INSERT INTO events (event_id, type, payload, created_at)
-		VALUES (?, ?, ?, ?)
-	`, event.EventID, event.Type, event.Payload, event.CreatedAt.Unix())
+		INSERT INTO events (event_id, type, task_id, payload, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, event.EventID, event.Type, event.TaskID, event.Payload, event.CreatedAt.Unix())
 	return err
 }
 
 // ListRecentEvents retrieves recent events
 func (s *Store) ListRecentEvents(limit int) ([]*EventRecord, error) {
-	rows, err := s.db.Query(`
-		SELECT event_id, type, payload, created_at
-		FROM events ORDER BY created_at DESC LIMIT ?
-	`, limit)
+	return s.ListEvents(EventFilter{Limit: limit})
+}
+
+// ListEvents retrieves events matching filter, most recent first
+func (s *Store) ListEvents(filter EventFilter) ([]*EventRecord, error) {
+	query := `SELECT event_id, type, task_id, payload, created_at FROM events WHERE 1=1`
+	args := []any{}
+
+	if len(filter.Types) > 0 {
+		query += " AND type IN (?" + strings.Repeat(", ?", len(filter.Types)-1) + ")"
+		for _, t := range filter.Types {
+			args = append(args, t)
+		}
+	}
+	if filter.TaskID != "" {
+		query += " AND task_id = ?"
+		args = append(args, filter.TaskID)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.Since.Unix())
+	}
+
+	query += " ORDER BY created_at DESC, rowid DESC"
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -492,10 +1067,12 @@ func (s *Store) ListRecentEvents(limit int) ([]*EventRecord, error) {
 	var events []*EventRecord
 	for rows.Next() {
 		var event EventRecord
+		var taskID sql.NullString
 		var createdAt int64
-		if err := rows.Scan(&event.EventID, &event.Type, &event.Payload, &createdAt); err != nil {
+		if err := rows.Scan(&event.EventID, &event.Type, &taskID, &event.Payload, &createdAt); err != nil {
 			return nil, err
 		}
+		event.TaskID = taskID.String
 		event.CreatedAt = time.Unix(createdAt, 0)
 		events = append(events, &event)
 	}
@@ -503,6 +1080,134 @@ func (s *Store) ListRecentEvents(limit int) ([]*EventRecord, error) {
 	return events, rows.Err()
 }
 
+// --- Agent Message Operations ---
+
+// CreateAgentMessage stores a message sent to or received from an agent
+func (s *Store) CreateAgentMessage(msg *AgentMessageRecord) error {
+	if msg.CreatedAt.IsZero() {
+		msg.CreatedAt = time.Now()
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO agent_messages (agent_id, task_id, kind, direction, content, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, msg.AgentID, msg.TaskID, msg.Kind, msg.Direction, msg.Content, msg.CreatedAt.Unix())
+	if err != nil {
+		return err
+	}
+	msg.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// ListAgentMessages returns an agent's messages, oldest first. A positive
+// limit keeps only the most recent messages.
+func (s *Store) ListAgentMessages(agentID string, limit int) ([]*AgentMessageRecord, error) {
+	query := `SELECT id, agent_id, task_id, kind, direction, content, created_at
+		FROM agent_messages WHERE agent_id = ? ORDER BY id DESC`
+	args := []any{agentID}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var messages []*AgentMessageRecord
+	for rows.Next() {
+		var msg AgentMessageRecord
+		var taskID, content sql.NullString
+		var createdAt int64
+		if err := rows.Scan(&msg.ID, &msg.AgentID, &taskID, &msg.Kind, &msg.Direction, &content, &createdAt); err != nil {
+			return nil, err
+		}
+		msg.TaskID = taskID.String
+		msg.Content = content.String
+		msg.CreatedAt = time.Unix(createdAt, 0)
+		messages = append(messages, &msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Reverse(messages)
+	return messages, nil
+}
+
+// --- Scheduled Task Operations ---
+
+// CreateScheduledTask stores a recurring task
+func (s *Store) CreateScheduledTask(sched *ScheduledTaskRecord) error {
+	paths, err := json.Marshal(sched.ScopePaths)
+	if err != nil {
+		return fmt.Errorf("marshal scope paths: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO scheduled_tasks (schedule_id, cron, description, scope_paths, repo_root, created_at, next_run_at, last_run_at, last_task_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, sched.ScheduleID, sched.Cron, sched.Description, string(paths), sched.RepoRoot,
+		sched.CreatedAt.Unix(), unixOrZero(sched.NextRunAt), unixOrZero(sched.LastRunAt), sched.LastTaskID)
+	return err
+}
+
+// ListScheduledTasks returns all recurring tasks, soonest to fire first
+func (s *Store) ListScheduledTasks() ([]*ScheduledTaskRecord, error) {
+	rows, err := s.db.Query(`
+		SELECT schedule_id, cron, description, scope_paths, repo_root, created_at, next_run_at, last_run_at, last_task_id
+		FROM scheduled_tasks ORDER BY next_run_at, created_at
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var schedules []*ScheduledTaskRecord
+	for rows.Next() {
+		var sched ScheduledTaskRecord
+		var paths, repoRoot, lastTaskID sql.NullString
+		var createdAt int64
+		var nextRunAt, lastRunAt sql.NullInt64
+		if err := rows.Scan(&sched.ScheduleID, &sched.Cron, &sched.Description, &paths, &repoRoot,
+			&createdAt, &nextRunAt, &lastRunAt, &lastTaskID); err != nil {
+			return nil, err
+		}
+		if paths.Valid && paths.String != "" {
+			if err := json.Unmarshal([]byte(paths.String), &sched.ScopePaths); err != nil {
+				sched.ScopePaths = nil
+			}
+		}
+		sched.RepoRoot = repoRoot.String
+		sched.CreatedAt = time.Unix(createdAt, 0)
+		sched.NextRunAt = timeFromUnix(nextRunAt)
+		sched.LastRunAt = timeFromUnix(lastRunAt)
+		sched.LastTaskID = lastTaskID.String
+		schedules = append(schedules, &sched)
+	}
+	return schedules, rows.Err()
+}
+
+// RecordScheduledRun notes that a schedule fired at ranAt and submitted
+// taskID, and sets when it fires next
+func (s *Store) RecordScheduledRun(scheduleID, taskID string, ranAt, nextRunAt time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE scheduled_tasks SET last_run_at = ?, last_task_id = ?, next_run_at = ? WHERE schedule_id = ?
+	`, unixOrZero(ranAt), taskID, unixOrZero(nextRunAt), scheduleID)
+	return err
+}
+
+// DeleteScheduledTask removes a recurring task, reporting whether it existed
+func (s *Store) DeleteScheduledTask(scheduleID string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM scheduled_tasks WHERE schedule_id = ?`, scheduleID)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
 // --- Stats ---
 
 // GetStats returns aggregate statistics
@@ -521,18 +1226,23 @@ func (s *Store) GetStats() (pendingTasks, activeTasks int, err error) {
 
 // CreateSpawnedAgent creates a new spawned agent record
 func (s *Store) CreateSpawnedAgent(agent *SpawnedAgentRecord) error {
-	_, err := s.db.Exec(`
-		INSERT INTO spawned_agents (agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	labels, err := json.Marshal(agent.Labels)
+	if err != nil {
+		return fmt.Errorf("marshal labels: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		INSERT INTO spawned_agents (agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root, labels, multiplexer)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, agent.AgentID, agent.WorktreePath, agent.PID, agent.Branch, agent.Prompt, agent.Status,
-		agent.CreatedAt.Unix(), agent.UpdatedAt.Unix(), agent.RepoRoot)
+		agent.CreatedAt.Unix(), agent.UpdatedAt.Unix(), agent.RepoRoot, string(labels), agent.Multiplexer)
 	return err
 }
 
 // GetSpawnedAgent retrieves a spawned agent by ID
 func (s *Store) GetSpawnedAgent(agentID string) (*SpawnedAgentRecord, error) {
 	row := s.db.QueryRow(`
-		SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root
+		SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root, labels, multiplexer
 		FROM spawned_agents WHERE agent_id = ?
 	`, agentID)
 
@@ -541,7 +1251,7 @@ func (s *Store) GetSpawnedAgent(agentID string) (*SpawnedAgentRecord, error) {
 
 // ListSpawnedAgents retrieves all spawned agents, optionally filtered by status and repo
 func (s *Store) ListSpawnedAgents(statusFilter, repoRoot string) ([]*SpawnedAgentRecord, error) {
-	query := `SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root
+	query := `SELECT agent_id, worktree_path, pid, branch, prompt, status, created_at, updated_at, repo_root, labels, multiplexer
 		FROM spawned_agents WHERE 1=1`
 	args := []any{}
 
@@ -582,6 +1292,15 @@ func (s *Store) UpdateSpawnedAgentStatus(agentID, status string) error {
 	return err
 }
 
+// UpdateSpawnedAgentWorktree records a worktree created for an agent after
+// it was spawned
+func (s *Store) UpdateSpawnedAgentWorktree(agentID, worktreePath string) error {
+	_, err := s.db.Exec(`
+		UPDATE spawned_agents SET worktree_path = ?, updated_at = ? WHERE agent_id = ?
+	`, worktreePath, time.Now().Unix(), agentID)
+	return err
+}
+
 // DeleteSpawnedAgent removes a spawned agent record
 func (s *Store) DeleteSpawnedAgent(agentID string) error {
 	_, err := s.db.Exec(`DELETE FROM spawned_agents WHERE agent_id = ?`, agentID)
@@ -590,11 +1309,11 @@ func (s *Store) DeleteSpawnedAgent(agentID string) error {
 
 func (s *Store) scanSpawnedAgent(row *sql.Row) (*SpawnedAgentRecord, error) {
 	var agent SpawnedAgentRecord
-	var worktreePath, branch, prompt, repoRoot sql.NullString
+	var worktreePath, branch, prompt, repoRoot, labels, multiplexer sql.NullString
 	var createdAt, updatedAt int64
 
 	err := row.Scan(&agent.AgentID, &worktreePath, &agent.PID, &branch, &prompt,
-		&agent.Status, &createdAt, &updatedAt, &repoRoot)
+		&agent.Status, &createdAt, &updatedAt, &repoRoot, &labels, &multiplexer)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -608,17 +1327,23 @@ func (s *Store) scanSpawnedAgent(row *sql.Row) (*SpawnedAgentRecord, error) {
 	agent.CreatedAt = time.Unix(createdAt, 0)
 	agent.UpdatedAt = time.Unix(updatedAt, 0)
 	agent.RepoRoot = repoRoot.String
+	agent.Multiplexer = multiplexer.String
+	if labels.Valid && labels.String != "" {
+		if err := json.Unmarshal([]byte(labels.String), &agent.Labels); err != nil {
+			return nil, fmt.Errorf("unmarshal labels: %w", err)
+		}
+	}
 
 	return &agent, nil
 }
 
 func (s *Store) scanSpawnedAgentRow(rows *sql.Rows) (*SpawnedAgentRecord, error) {
 	var agent SpawnedAgentRecord
-	var worktreePath, branch, prompt, repoRoot sql.NullString
+	var worktreePath, branch, prompt, repoRoot, labels, multiplexer sql.NullString
 	var createdAt, updatedAt int64
 
 	err := rows.Scan(&agent.AgentID, &worktreePath, &agent.PID, &branch, &prompt,
-		&agent.Status, &createdAt, &updatedAt, &repoRoot)
+		&agent.Status, &createdAt, &updatedAt, &repoRoot, &labels, &multiplexer)
 	if err != nil {
 		return nil, err
 	}
@@ -629,6 +1354,12 @@ func (s *Store) scanSpawnedAgentRow(rows *sql.Rows) (*SpawnedAgentRecord, error)
 	agent.CreatedAt = time.Unix(createdAt, 0)
 	agent.UpdatedAt = time.Unix(updatedAt, 0)
 	agent.RepoRoot = repoRoot.String
+	agent.Multiplexer = multiplexer.String
+	if labels.Valid && labels.String != "" {
+		if err := json.Unmarshal([]byte(labels.String), &agent.Labels); err != nil {
+			return nil, fmt.Errorf("unmarshal labels: %w", err)
+		}
+	}
 
 	return &agent, nil
 }
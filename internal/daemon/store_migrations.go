@@ -0,0 +1,191 @@
+package daemon
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// migration is one schema change. Migrations are applied in version order
+// to databases whose schema_version is below theirs, each in a transaction
+// that also records its version. Steps must be idempotent: a fresh database
+// is created from the full schema and then runs every step.
+type migration struct {
+	version int
+	name    string
+	apply   func(tx *sql.Tx) error
+}
+
+// migrations are the schema changes made since the original tasks, events,
+// and spawned_agents tables. Append new steps with the next version; never
+// renumber or edit a released one.
+var migrations = []migration{
+	{1, "add GitHub source to tasks", steps(
+		addColumn("tasks", "github_owner", "TEXT"),
+		addColumn("tasks", "github_repo", "TEXT"),
+		addColumn("tasks", "github_issue_number", "INTEGER"),
+		addColumn("tasks", "last_comment_id", "TEXT"),
+		createIndex("idx_tasks_github", "tasks(github_owner, github_repo, github_issue_number)"),
+	)},
+	{2, "add waiting-input question to tasks", steps(
+		addColumn("tasks", "waiting_input_question", "TEXT"),
+		addColumn("tasks", "waiting_input_since", "INTEGER"),
+	)},
+	{3, "add repo root to tasks and agents", steps(
+		addColumn("tasks", "repo_root", "TEXT"),
+		addColumn("spawned_agents", "repo_root", "TEXT"),
+		createIndex("idx_tasks_repo_root", "tasks(repo_root)"),
+		createIndex("idx_spawned_agents_repo_root", "spawned_agents(repo_root)"),
+	)},
+	{4, "add start and completion times to tasks", steps(
+		addColumn("tasks", "started_at", "INTEGER"),
+		addColumn("tasks", "completed_at", "INTEGER"),
+	)},
+	{5, "add retry count to tasks", addColumn("tasks", "retry_count", "INTEGER DEFAULT 0")},
+	{6, "add task ID to events", steps(
+		addColumn("events", "task_id", "TEXT"),
+		createIndex("idx_events_task_id", "events(task_id)"),
+	)},
+	{7, "add labels to agents", addColumn("spawned_agents", "labels", "TEXT")},
+	{8, "add multiplexer to agents", addColumn("spawned_agents", "multiplexer", "TEXT")},
+	{9, "add run ID to tasks", steps(
+		addColumn("tasks", "run_id", "TEXT"),
+		createIndex("idx_tasks_run_id", "tasks(run_id)"),
+	)},
+	{10, "add preferred agent to tasks", addColumn("tasks", "prefer_agent", "TEXT")},
+	{11, "add no-worktree flag to tasks", addColumn("tasks", "no_worktree", "INTEGER DEFAULT 0")},
+	{12, "add agent type to tasks", addColumn("tasks", "agent_type", "TEXT")},
+	{13, "add environment to tasks", addColumn("tasks", "environment", "TEXT")},
+	{14, "add archive time to tasks", addColumn("tasks", "archived_at", "INTEGER DEFAULT 0")},
+	{15, "add rejecting agents to tasks", addColumn("tasks", "rejected_by", "TEXT")},
+	{16, "add result file to tasks", addColumn("tasks", "result_file", "TEXT")},
+}
+
+// migrate brings the database schema up to date
+func (s *Store) migrate() error {
+	return migrateSchema(s.db, migrations)
+}
+
+// migrateSchema applies the steps a database hasn't had yet, in order,
+// stopping at the first that fails. A database written by a newer mapd,
+// with versions this one doesn't know, is refused.
+func migrateSchema(db *sql.DB, all []migration) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_version (
+		version INTEGER PRIMARY KEY,
+		name TEXT,
+		applied_at INTEGER NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("create schema_version table: %w", err)
+	}
+
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+	latest := 0
+	if len(all) > 0 {
+		latest = all[len(all)-1].version
+	}
+	if current > latest {
+		return fmt.Errorf("database schema version %d is newer than this mapd supports (%d); upgrade mapd", current, latest)
+	}
+
+	for _, step := range all {
+		if step.version <= current {
+			continue
+		}
+		if err := applyMigration(db, step); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", step.version, step.name, err)
+		}
+	}
+	return nil
+}
+
+// schemaVersion returns the newest migration applied to db (0 = none)
+func schemaVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow(`SELECT MAX(version) FROM schema_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return int(version.Int64), nil
+}
+
+// applyMigration runs one step and records it, or neither
+func applyMigration(db *sql.DB, step migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := step.apply(tx); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, ?, ?)`,
+		step.version, step.name, time.Now().Unix()); err != nil {
+		return fmt.Errorf("record version: %w", err)
+	}
+	return tx.Commit()
+}
+
+// steps runs several changes in order, stopping at the first that fails
+func steps(changes ...func(tx *sql.Tx) error) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, change := range changes {
+			if err := change(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// addColumn adds a column to table unless it already exists
+func addColumn(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		exists, err := columnExists(tx, table, column)
+		if err != nil || exists {
+			return err
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+			return fmt.Errorf("add %s.%s: %w", table, column, err)
+		}
+		return nil
+	}
+}
+
+// columnExists reports whether table has column
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("inspect %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, kind string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &dflt, &pk); err != nil {
+			return false, fmt.Errorf("inspect %s: %w", table, err)
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// createIndex creates an index unless it already exists
+func createIndex(name, on string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if _, err := tx.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s", name, on)); err != nil {
+			return fmt.Errorf("create index %s: %w", name, err)
+		}
+		return nil
+	}
+}
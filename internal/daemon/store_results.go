@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// DefaultMaxResultBytes is the default cap on a task result kept in the
+// database. Headless runs store their full output as the result, which can
+// bloat mapd.db.
+const DefaultMaxResultBytes = 1024 * 1024
+
+// resultsDir is the data directory subdirectory spilled results go in
+const resultsDir = "results"
+
+// SetResultStorage caps the task results kept in the database at maxBytes
+// (0 or negative = no cap). Larger results are truncated or, with spill,
+// written to a file under <data-dir>/results with the database keeping
+// the file's path. Attempt history always keeps a truncated copy.
+func (s *Store) SetResultStorage(maxBytes int, spill bool) {
+	s.maxResultBytes = max(maxBytes, 0)
+	s.spillResults = spill
+}
+
+// storeResult returns the result and result_file column values for a
+// task's result, spilling it to a file or truncating it when it is over
+// the cap. A spill file left over from an earlier, larger result is removed.
+func (s *Store) storeResult(taskID, result string) (string, string, error) {
+	if s.maxResultBytes <= 0 || len(result) <= s.maxResultBytes {
+		s.removeResultFile(taskID)
+		return result, "", nil
+	}
+	if !s.spillResults || s.dataDir == "" {
+		return truncateResult(result, s.maxResultBytes), "", nil
+	}
+
+	rel := resultFileName(taskID)
+	path := filepath.Join(s.dataDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", "", fmt.Errorf("create results dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+		return "", "", fmt.Errorf("write result file: %w", err)
+	}
+	return "", rel, nil
+}
+
+// limitResult truncates a result that is over the cap, without spilling it
+func (s *Store) limitResult(result string) string {
+	if s.maxResultBytes <= 0 || len(result) <= s.maxResultBytes {
+		return result
+	}
+	return truncateResult(result, s.maxResultBytes)
+}
+
+// loadResult reads a spilled result back into task.Result. It is only done
+// where the full result is shown (GetTask and export), not for every row
+// of a listing.
+func (s *Store) loadResult(task *TaskRecord) {
+	if task == nil || task.ResultFile == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(s.dataDir, task.ResultFile))
+	if err != nil {
+		task.Result = fmt.Sprintf("[result file %s unreadable: %v]", task.ResultFile, err)
+		return
+	}
+	task.Result = string(data)
+}
+
+// removeResultFile deletes a task's spilled result, if it has one
+func (s *Store) removeResultFile(taskID string) {
+	if s.dataDir == "" {
+		return
+	}
+	err := os.Remove(filepath.Join(s.dataDir, resultFileName(taskID)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("warning: failed to remove result file for task %s: %v", taskID, err)
+	}
+}
+
+// resultFileName returns a task's spill file, relative to the data
+// directory. Task IDs map to the file by their UUID as-is; imported tasks
+// can carry arbitrary IDs, so any other ID is hashed, which keeps distinct
+// IDs in distinct files.
+func resultFileName(taskID string) string {
+	if id, err := uuid.Parse(taskID); err == nil && id.String() == taskID {
+		return filepath.Join(resultsDir, taskID+".txt")
+	}
+	sum := sha256.Sum256([]byte(taskID))
+	return filepath.Join(resultsDir, hex.EncodeToString(sum[:])+".txt")
+}
+
+// truncateResult keeps the first maxBytes of a result, cut on a character
+// boundary, and notes how much was dropped
+func truncateResult(result string, maxBytes int) string {
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s\n[result truncated: kept %d of %d bytes]", result[:cut], cut, len(result))
+}
@@ -0,0 +1,116 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_ResultTruncated(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	store.SetResultStorage(9, false)
+
+	now := time.Now()
+	task := &TaskRecord{TaskID: "task-1", Description: "d", Status: "completed", Result: "héllo wörld, and more", CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	got, _ := store.GetTask("task-1")
+	// The cut backs off to a character boundary rather than splitting ö
+	if !strings.HasPrefix(got.Result, "héllo w\n[result truncated: kept 8 of ") || got.ResultFile != "" {
+		t.Errorf("Result = %q, file %q; want a truncated inline result", got.Result, got.ResultFile)
+	}
+
+	// Results under the cap are kept as they are
+	got.Result = "short"
+	if err := store.UpdateTask(got); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if got, _ = store.GetTask("task-1"); got.Result != "short" {
+		t.Errorf("Result = %q, want short", got.Result)
+	}
+}
+
+func TestStore_ResultSpilledToFile(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	store.SetResultStorage(10, true)
+
+	full := strings.Repeat("output line\n", 100)
+	now := time.Now()
+	task := &TaskRecord{TaskID: "task-1", Description: "d", Status: "completed", Result: full, CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	// The database keeps only the path, and GetTask reads the full result
+	var inline string
+	if err := store.db.QueryRow(`SELECT result FROM tasks WHERE task_id = 'task-1'`).Scan(&inline); err != nil || inline != "" {
+		t.Errorf("stored result = %q (err %v), want empty", inline, err)
+	}
+	got, _ := store.GetTask("task-1")
+	if got.Result != full || got.ResultFile != resultFileName("task-1") {
+		t.Errorf("got result of %d bytes from %q, want %d bytes from %s", len(got.Result), got.ResultFile, len(full), resultFileName("task-1"))
+	}
+
+	// Listings don't read result files
+	listed, _ := store.ListTasks("", "", "", 0, true)
+	if len(listed) != 1 || listed[0].Result != "" || listed[0].ResultFile != got.ResultFile {
+		t.Error("ListTasks read the spilled result back, want only its file")
+	}
+
+	// Retrying the task clears the result and removes its file
+	path := filepath.Join(store.dataDir, got.ResultFile)
+	if err := store.RequeueTask("task-1"); err != nil {
+		t.Fatalf("RequeueTask failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("result file still exists after requeue: %v", err)
+	}
+	if got, _ = store.GetTask("task-1"); got.Result != "" || got.ResultFile != "" {
+		t.Errorf("requeued task result = %q, file %q; want both empty", got.Result, got.ResultFile)
+	}
+}
+
+func TestStore_AttemptResultTruncated(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	store.SetResultStorage(10, true)
+
+	attempt := &TaskAttemptRecord{TaskID: "task-1", Attempt: 1, Status: "completed", Result: strings.Repeat("x", 50)}
+	if err := store.SaveTaskAttempt(attempt); err != nil {
+		t.Fatalf("SaveTaskAttempt failed: %v", err)
+	}
+	got, err := store.GetTaskAttempt("task-1", 1)
+	if err != nil || got == nil {
+		t.Fatalf("GetTaskAttempt = %v, %v", got, err)
+	}
+	if !strings.HasPrefix(got.Result, strings.Repeat("x", 10)+"\n[result truncated") {
+		t.Errorf("attempt result = %q, want it truncated", got.Result)
+	}
+}
+
+func TestResultFileName(t *testing.T) {
+	id := "3f2b8c1e-9d4a-4e6b-8f0c-1a2b3c4d5e6f"
+	if got := resultFileName(id); got != filepath.Join("results", id+".txt") {
+		t.Errorf("resultFileName(%q) = %q, want the UUID as-is", id, got)
+	}
+
+	// Other IDs are hashed, so they stay in the results directory and
+	// don't share a file
+	seen := make(map[string]string)
+	for _, id := range []string{"../../etc/passwd", "a/b", "a_b", "A_B", strings.ToUpper(id)} {
+		got := resultFileName(id)
+		if filepath.Dir(got) != "results" {
+			t.Errorf("resultFileName(%q) = %q, want a file in results", id, got)
+		}
+		if other, ok := seen[got]; ok {
+			t.Errorf("resultFileName(%q) = resultFileName(%q) = %q", id, other, got)
+		}
+		seen[got] = id
+	}
+}
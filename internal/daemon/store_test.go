@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -112,6 +113,27 @@ func TestCreateTask(t *testing.T) {
 	}
 }
 
+func TestReplaceTask(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "task-1", Description: "old", Status: "pending", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if err := store.ReplaceTask(&TaskRecord{TaskID: "task-1", Description: "new", Status: "completed", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("ReplaceTask failed: %v", err)
+	}
+
+	got, err := store.GetTask("task-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetTask = %v, %v", got, err)
+	}
+	if got.Description != "new" || got.Status != "completed" {
+		t.Errorf("task = %q %s, want the replacement", got.Description, got.Status)
+	}
+}
+
 func TestGetTask_NotFound(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -144,7 +166,7 @@ func TestListTasks(t *testing.T) {
 	}
 
 	// List all
-	all, err := store.ListTasks("", "", "", 0)
+	all, err := store.ListTasks("", "", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -153,7 +175,7 @@ func TestListTasks(t *testing.T) {
 	}
 
 	// Filter by status
-	pending, err := store.ListTasks("pending", "", "", 0)
+	pending, err := store.ListTasks("pending", "", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -162,7 +184,7 @@ func TestListTasks(t *testing.T) {
 	}
 
 	// Filter by agent
-	agentTasks, err := store.ListTasks("", "agent-1", "", 0)
+	agentTasks, err := store.ListTasks("", "agent-1", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -171,7 +193,7 @@ func TestListTasks(t *testing.T) {
 	}
 
 	// With limit
-	limited, err := store.ListTasks("", "", "", 2)
+	limited, err := store.ListTasks("", "", "", 2, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -180,6 +202,69 @@ func TestListTasks(t *testing.T) {
 	}
 }
 
+func TestListRuns(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+
+	tasks := []*TaskRecord{
+		{TaskID: "task-1", Status: "completed", RunID: "run-a", RepoRoot: "/repo", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "task-2", Status: "in_progress", RunID: "run-a", RepoRoot: "/repo", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "task-3", Status: "failed", RunID: "run-a", RepoRoot: "/repo", CreatedAt: now, UpdatedAt: now.Add(time.Minute)},
+		{TaskID: "task-4", Status: "pending", RunID: "run-b", RepoRoot: "/other", CreatedAt: now.Add(time.Hour), UpdatedAt: now},
+		{TaskID: "task-5", Status: "pending", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, task := range tasks {
+		if err := store.CreateTask(task); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	runs, err := store.ListRuns("", 0)
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].RunID != "run-b" || runs[1].RunID != "run-a" {
+		t.Fatalf("ListRuns = %v, want run-b then run-a", runs)
+	}
+
+	a := runs[1]
+	if a.Total != 3 || a.Completed != 1 || a.Active != 1 || a.Failed != 1 || a.Pending != 0 {
+		t.Errorf("run-a counts = %+v, want 3 total, 1 completed, 1 active, 1 failed", a)
+	}
+	if a.UpdatedAt.Unix() != now.Add(time.Minute).Unix() {
+		t.Errorf("run-a updated at %v, want the latest task update", a.UpdatedAt)
+	}
+
+	byRepo, err := store.ListRuns("/repo", 0)
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(byRepo) != 1 || byRepo[0].RunID != "run-a" {
+		t.Errorf("ListRuns(/repo) = %v, want only run-a", byRepo)
+	}
+
+	run, err := store.GetRun("run-b")
+	if err != nil {
+		t.Fatalf("GetRun failed: %v", err)
+	}
+	if run == nil || run.Total != 1 || run.Pending != 1 {
+		t.Errorf("GetRun(run-b) = %+v, want 1 pending task", run)
+	}
+	if missing, err := store.GetRun("run-missing"); err != nil || missing != nil {
+		t.Errorf("GetRun(run-missing) = %v, %v; want nil, nil", missing, err)
+	}
+
+	runTasks, err := store.ListRunTasks("run-a")
+	if err != nil {
+		t.Fatalf("ListRunTasks failed: %v", err)
+	}
+	if len(runTasks) != 3 || runTasks[0].RunID != "run-a" {
+		t.Errorf("ListRunTasks(run-a) returned %d tasks, want 3 in run-a", len(runTasks))
+	}
+}
+
 func TestUpdateTask(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -249,6 +334,65 @@ func TestUpdateTaskStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskStatus_RecordsTiming(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	task := &TaskRecord{
+		TaskID:    "task-123",
+		Status:    "pending",
+		RepoRoot:  "/repo",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	retrieved, _ := store.GetTask("task-123")
+	if !retrieved.StartedAt.IsZero() || !retrieved.CompletedAt.IsZero() {
+		t.Fatal("pending task should have no timing recorded")
+	}
+
+	if err := store.UpdateTaskStatus("task-123", "in_progress"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	retrieved, _ = store.GetTask("task-123")
+	if retrieved.StartedAt.IsZero() {
+		t.Error("StartedAt should be set after in_progress")
+	}
+	started := retrieved.StartedAt
+
+	// A second in_progress transition should not reset the start time
+	if err := store.UpdateTaskStatus("task-123", "in_progress"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if err := store.UpdateTaskStatus("task-123", "completed"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	retrieved, _ = store.GetTask("task-123")
+	if !retrieved.StartedAt.Equal(started) {
+		t.Errorf("StartedAt changed from %v to %v", started, retrieved.StartedAt)
+	}
+	if retrieved.CompletedAt.IsZero() {
+		t.Error("CompletedAt should be set after completed")
+	}
+
+	completed, err := store.ListCompletedTasks("/repo", 10)
+	if err != nil {
+		t.Fatalf("ListCompletedTasks failed: %v", err)
+	}
+	if len(completed) != 1 {
+		t.Errorf("ListCompletedTasks returned %d tasks, want 1", len(completed))
+	}
+
+	completed, _ = store.ListCompletedTasks("/other", 10)
+	if len(completed) != 0 {
+		t.Errorf("ListCompletedTasks(/other) returned %d tasks, want 0", len(completed))
+	}
+}
+
 func TestAssignTask(t *testing.T) {
 	store, cleanup := setupTestStore(t)
 	defer cleanup()
@@ -282,6 +426,35 @@ func TestAssignTask(t *testing.T) {
 	}
 }
 
+func TestSetTaskGitHubSource(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "task-123", Status: "in_progress", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	linked, err := store.SetTaskGitHubSource("task-123", "owner", "repo", 42)
+	if err != nil || !linked {
+		t.Fatalf("SetTaskGitHubSource = (%v, %v), want (true, nil)", linked, err)
+	}
+
+	// An existing link is never overwritten
+	linked, err = store.SetTaskGitHubSource("task-123", "other", "repo", 7)
+	if err != nil || linked {
+		t.Fatalf("second SetTaskGitHubSource = (%v, %v), want (false, nil)", linked, err)
+	}
+
+	retrieved, err := store.GetTask("task-123")
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if retrieved.GitHubOwner != "owner" || retrieved.GitHubRepo != "repo" || retrieved.GitHubIssueNumber != 42 {
+		t.Errorf("GitHub source = %s/%s#%d, want owner/repo#42", retrieved.GitHubOwner, retrieved.GitHubRepo, retrieved.GitHubIssueNumber)
+	}
+}
+
 // --- Event Operations Tests ---
 
 func TestCreateEvent(t *testing.T) {
@@ -391,6 +564,8 @@ func TestSpawnedAgentCRUD(t *testing.T) {
 		Status:       "running",
 		CreatedAt:    now,
 		UpdatedAt:    now,
+		Labels:       map[string]string{"team": "backend"},
+		Multiplexer:  MultiplexerTmux,
 	}
 
 	// Create
@@ -413,6 +588,21 @@ func TestSpawnedAgentCRUD(t *testing.T) {
 	if retrieved.PID != 12345 {
 		t.Errorf("PID = %d, want 12345", retrieved.PID)
 	}
+	if retrieved.Labels["team"] != "backend" || len(retrieved.Labels) != 1 {
+		t.Errorf("Labels = %v, want team=backend", retrieved.Labels)
+	}
+	if retrieved.Multiplexer != MultiplexerTmux {
+		t.Errorf("Multiplexer = %q, want %q", retrieved.Multiplexer, MultiplexerTmux)
+	}
+
+	// Look up by worktree
+	byPath, err := store.GetAgentByWorktreePath("/path/to/worktree")
+	if err != nil {
+		t.Fatalf("GetAgentByWorktreePath failed: %v", err)
+	}
+	if byPath == nil || byPath.AgentID != "spawned-123" {
+		t.Errorf("GetAgentByWorktreePath = %+v, want spawned-123", byPath)
+	}
 
 	// Update status
 	if err := store.UpdateSpawnedAgentStatus("spawned-123", "stopped"); err != nil {
@@ -603,4 +793,199 @@ CREATE INDEX IF NOT EXISTS idx_spawned_agents_status ON spawned_agents(status);
 	if retrieved.GitHubIssueNumber != 42 {
 		t.Errorf("GitHubIssueNumber = %d, want 42", retrieved.GitHubIssueNumber)
 	}
+
+	latest := migrations[len(migrations)-1].version
+	if version, err := schemaVersion(store.db); err != nil || version != latest {
+		t.Errorf("schema version = %d, %v; want %d", version, err, latest)
+	}
+}
+
+func TestNewStore_SchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	latest := migrations[len(migrations)-1].version
+
+	// A fresh database runs every step, and reopening it runs none
+	for range 2 {
+		store, err := NewStore(dir)
+		if err != nil {
+			t.Fatalf("NewStore: %v", err)
+		}
+		version, err := schemaVersion(store.db)
+		if err != nil || version != latest {
+			t.Errorf("schema version = %d, %v; want %d", version, err, latest)
+		}
+		var applied int
+		if err := store.db.QueryRow(`SELECT COUNT(*) FROM schema_version`).Scan(&applied); err != nil {
+			t.Fatalf("count versions: %v", err)
+		}
+		if applied != len(migrations) {
+			t.Errorf("recorded %d migrations, want %d", applied, len(migrations))
+		}
+		_ = store.Close()
+	}
+
+	// A database from a newer mapd is refused
+	db, err := sql.Open("sqlite", filepath.Join(dir, "mapd.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_version (version, name, applied_at) VALUES (?, 'future', 0)`, latest+1); err != nil {
+		t.Fatalf("insert version: %v", err)
+	}
+	_ = db.Close()
+	if store, err := NewStore(dir); err == nil {
+		_ = store.Close()
+		t.Error("NewStore opened a database with a newer schema version")
+	}
+}
+
+func TestMigrateSchema_Failure(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.Exec(`CREATE TABLE items (id TEXT PRIMARY KEY)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	all := []migration{
+		{1, "add name", addColumn("items", "name", "TEXT")},
+		{2, "bad step", steps(
+			addColumn("items", "size", "INTEGER"),
+			createIndex("idx_items_missing", "items(missing)"),
+		)},
+		{3, "add owner", addColumn("items", "owner", "TEXT")},
+	}
+
+	err = migrateSchema(db, all)
+	if err == nil {
+		t.Fatal("expected the failing migration to be reported")
+	}
+	if !strings.Contains(err.Error(), "migration 2 (bad step)") {
+		t.Errorf("error = %q, want it to name migration 2", err)
+	}
+
+	// The failed step is rolled back and later steps are not run
+	if version, _ := schemaVersion(db); version != 1 {
+		t.Errorf("schema version = %d, want 1", version)
+	}
+	for column, want := range map[string]bool{"name": true, "size": false, "owner": false} {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("begin: %v", err)
+		}
+		exists, err := columnExists(tx, "items", column)
+		_ = tx.Rollback()
+		if err != nil || exists != want {
+			t.Errorf("column %s exists = %v, %v; want %v", column, exists, err, want)
+		}
+	}
+
+	// Once fixed, the remaining steps apply
+	all[1] = migration{2, "fixed step", addColumn("items", "size", "INTEGER")}
+	if err := migrateSchema(db, all); err != nil {
+		t.Fatalf("migrateSchema: %v", err)
+	}
+	if version, _ := schemaVersion(db); version != 3 {
+		t.Errorf("schema version = %d, want 3", version)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	events := []*EventRecord{
+		{EventID: "e1", Type: "EVENT_TYPE_TASK_CREATED", TaskID: "t1", Payload: "{}", CreatedAt: base},
+		{EventID: "e2", Type: "EVENT_TYPE_TASK_COMPLETED", TaskID: "t1", Payload: "{}", CreatedAt: base.Add(10 * time.Minute)},
+		{EventID: "e3", Type: "EVENT_TYPE_TASK_FAILED", TaskID: "t2", Payload: "{}", CreatedAt: base.Add(20 * time.Minute)},
+		{EventID: "e4", Type: "EVENT_TYPE_TASK_COMPLETED", TaskID: "t3", Payload: "{}", CreatedAt: base.Add(30 * time.Minute)},
+	}
+	for _, e := range events {
+		if err := store.CreateEvent(e); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	ids := func(records []*EventRecord) []string {
+		var out []string
+		for _, r := range records {
+			out = append(out, r.EventID)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   []string
+	}{
+		{"all, newest first", EventFilter{}, []string{"e4", "e3", "e2", "e1"}},
+		{"limit", EventFilter{Limit: 2}, []string{"e4", "e3"}},
+		{"type filter", EventFilter{Types: []string{"EVENT_TYPE_TASK_COMPLETED"}}, []string{"e4", "e2"}},
+		{"multiple types", EventFilter{Types: []string{"EVENT_TYPE_TASK_CREATED", "EVENT_TYPE_TASK_FAILED"}}, []string{"e3", "e1"}},
+		{"since", EventFilter{Since: base.Add(20 * time.Minute)}, []string{"e4", "e3"}},
+		{"type and since", EventFilter{Types: []string{"EVENT_TYPE_TASK_COMPLETED"}, Since: base.Add(5 * time.Minute), Limit: 1}, []string{"e4"}},
+		{"task", EventFilter{TaskID: "t1"}, []string{"e2", "e1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.ListEvents(tt.filter)
+			if err != nil {
+				t.Fatalf("ListEvents failed: %v", err)
+			}
+			gotIDs := ids(got)
+			if len(gotIDs) != len(tt.want) {
+				t.Fatalf("ListEvents = %v, want %v", gotIDs, tt.want)
+			}
+			for i := range tt.want {
+				if gotIDs[i] != tt.want[i] {
+					t.Errorf("ListEvents = %v, want %v", gotIDs, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestAgentMessages(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	messages := []*AgentMessageRecord{
+		{AgentID: "agent-1", TaskID: "task-1", Kind: MessageKindTask, Direction: AgentMessageSent, Content: "do the thing"},
+		{AgentID: "agent-2", Kind: MessageKindInitialPrompt, Direction: AgentMessageSent, Content: "hello"},
+		{AgentID: "agent-1", TaskID: "task-1", Kind: MessageKindQuestion, Direction: AgentMessageReceived, Content: "which branch?"},
+		{AgentID: "agent-1", TaskID: "task-1", Kind: MessageKindInputResponse, Direction: AgentMessageSent, Content: "main"},
+	}
+	for _, msg := range messages {
+		if err := store.CreateAgentMessage(msg); err != nil {
+			t.Fatalf("CreateAgentMessage failed: %v", err)
+		}
+	}
+
+	history, err := store.ListAgentMessages("agent-1", 0)
+	if err != nil {
+		t.Fatalf("ListAgentMessages failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("got %d messages, want 3", len(history))
+	}
+	if history[0].Content != "do the thing" || history[2].Content != "main" {
+		t.Errorf("messages out of order: %q ... %q", history[0].Content, history[2].Content)
+	}
+	if history[1].Direction != AgentMessageReceived || history[1].CreatedAt.IsZero() {
+		t.Errorf("question = %+v, want a received message with a timestamp", history[1])
+	}
+
+	recent, err := store.ListAgentMessages("agent-1", 2)
+	if err != nil {
+		t.Fatalf("ListAgentMessages with limit failed: %v", err)
+	}
+	if len(recent) != 2 || recent[0].Kind != MessageKindQuestion || recent[1].Kind != MessageKindInputResponse {
+		t.Errorf("limited history = %v, want the last two messages oldest first", recent)
+	}
 }
@@ -0,0 +1,62 @@
+package daemon
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultSubmitKeys are the tmux keys the agent CLIs' specs submit a prompt
+// with. Long pastes show as "[Pasted text #1 +N lines]": the first Enter
+// expands the preview and the second submits the prompt. For short pastes
+// the first Enter submits and the second is harmless.
+var DefaultSubmitKeys = []string{"Enter", "Enter"}
+
+// SetSubmitKeys sets, per agent type, the tmux key names sent one at a time
+// after a prompt's text to submit it, e.g. {"codex": {"Escape", "Enter"}}.
+// Blank key names are dropped; types without any keys use their spec's
+// SubmitKeys.
+func (m *ProcessManager) SetSubmitKeys(keys map[string][]string) {
+	submit := make(map[string][]string, len(keys))
+	for agentType, names := range keys {
+		if cleaned := cleanSubmitKeys(names); len(cleaned) > 0 {
+			submit[agentType] = cleaned
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.submitKeys = submit
+}
+
+// SubmitKeysFor returns the keys that submit a prompt to an agent type's CLI
+// given the configured names: those that aren't blank, or the spec's
+// SubmitKeys if none are
+func SubmitKeysFor(agentType string, configured []string) []string {
+	if keys := cleanSubmitKeys(configured); len(keys) > 0 {
+		return keys
+	}
+	return slices.Clone(agentTypeSpec(agentType).SubmitKeys)
+}
+
+// cleanSubmitKeys trims key names and drops blank ones
+func cleanSubmitKeys(names []string) []string {
+	var cleaned []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			cleaned = append(cleaned, name)
+		}
+	}
+	return cleaned
+}
+
+// submitKeysFor returns the keys that submit a prompt to an agent type's CLI
+func (m *ProcessManager) submitKeysFor(agentType string) []string {
+	spec := agentTypeSpec(agentType)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if keys, ok := m.submitKeys[spec.Name]; ok {
+		return slices.Clone(keys)
+	}
+	return slices.Clone(spec.SubmitKeys)
+}
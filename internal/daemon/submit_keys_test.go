@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetSubmitKeys(t *testing.T) {
+	m := NewProcessManager(t.TempDir(), nil)
+
+	if got := m.submitKeysFor(AgentTypeCodex); !slices.Equal(got, DefaultSubmitKeys) {
+		t.Errorf("unconfigured keys = %v, want default %v", got, DefaultSubmitKeys)
+	}
+
+	m.SetSubmitKeys(map[string][]string{
+		AgentTypeCodex:  {"Escape", " Enter "},
+		AgentTypeGemini: {"", " "},
+	})
+
+	if got := m.submitKeysFor(AgentTypeCodex); !slices.Equal(got, []string{"Escape", "Enter"}) {
+		t.Errorf("codex keys = %v, want [Escape Enter]", got)
+	}
+	// Blank lists fall back to the default, as do unlisted types
+	if got := m.submitKeysFor(AgentTypeGemini); !slices.Equal(got, DefaultSubmitKeys) {
+		t.Errorf("gemini keys = %v, want default %v", got, DefaultSubmitKeys)
+	}
+	if got := m.submitKeysFor(""); !slices.Equal(got, DefaultSubmitKeys) {
+		t.Errorf("untyped keys = %v, want claude's default %v", got, DefaultSubmitKeys)
+	}
+
+	// Callers can't modify the configured keys through the returned slice
+	m.submitKeysFor(AgentTypeCodex)[0] = "C-c"
+	if got := m.submitKeysFor(AgentTypeCodex); got[0] != "Escape" {
+		t.Errorf("codex keys = %v after modifying a copy, want [Escape Enter]", got)
+	}
+}
+
+func TestSubmitKeysFor(t *testing.T) {
+	if got := SubmitKeysFor(AgentTypeCodex, []string{" Escape", "Enter", ""}); !slices.Equal(got, []string{"Escape", "Enter"}) {
+		t.Errorf("configured keys = %v, want [Escape Enter]", got)
+	}
+	if got := SubmitKeysFor(AgentTypeGemini, []string{" "}); !slices.Equal(got, LookupAgentType(AgentTypeGemini).SubmitKeys) {
+		t.Errorf("blank keys = %v, want gemini's spec keys", got)
+	}
+}
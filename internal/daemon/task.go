@@ -3,43 +3,174 @@ package daemon
 import (
 	"context"
 	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// DefaultMaxDescriptionBytes is the default cap on task description size.
+// Descriptions are typed into agent panes, so very large ones are rejected
+// rather than sent.
+const DefaultMaxDescriptionBytes = 32 * 1024
+
 // TaskRouter manages task distribution to agents
 type TaskRouter struct {
 	mu      sync.RWMutex
 	store   *Store
 	spawned *ProcessManager // Spawned agents (Claude/Codex)
 	eventCh chan *mapv1.Event
+	drops   *eventDrops // counts events dropped on eventCh (nil = uncounted)
+
+	// autoSpawn, if set, is called when a submitted task finds no idle agent
+	autoSpawn func(repoRoot string) bool
+
+	// removeAgent, if set, removes an agent whose policy is kill once its
+	// task is done
+	removeAgent func(agentID string)
+
+	// maxRetries caps how many times a failed task may be re-queued (0 = no cap)
+	maxRetries int
+
+	// maxDescription caps a task description's size in bytes (0 = no cap)
+	maxDescription int
+
+	// ghTimeout bounds gh calls made while inferring a task's GitHub source
+	ghTimeout time.Duration
+
+	// paused stops pending tasks from being assigned to agents
+	paused bool
+
+	// routing picks an agent type for tasks submitted without one
+	routing *TaskRouting
+
+	// offerTimeout is how long an agent has to answer a task offer
+	offerTimeout time.Duration
 }
 
 // NewTaskRouter creates a new task router
 func NewTaskRouter(store *Store, spawned *ProcessManager, eventCh chan *mapv1.Event) *TaskRouter {
 	return &TaskRouter{
-		store:   store,
-		spawned: spawned,
-		eventCh: eventCh,
+		store:        store,
+		spawned:      spawned,
+		eventCh:      eventCh,
+		ghTimeout:    DefaultGitHubTimeout,
+		offerTimeout: DefaultOfferTimeout,
 	}
 }
 
+// SetGitHubTimeout sets the per-call timeout for gh calls made by the router.
+// Non-positive values restore the default.
+func (r *TaskRouter) SetGitHubTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultGitHubTimeout
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ghTimeout = d
+}
+
+// SetAutoSpawn sets a function used to spawn an agent when a submitted task
+// finds no idle agent. The spawned agent picks up pending tasks when it
+// becomes available, so spawn only reports whether an agent was started.
+func (r *TaskRouter) SetAutoSpawn(spawn func(repoRoot string) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.autoSpawn = spawn
+}
+
+// SetMaxRetries caps how many times a failed task may be retried.
+// Zero disables the cap.
+func (r *TaskRouter) SetMaxRetries(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxRetries = n
+}
+
+// SetMaxDescriptionBytes caps the size of submitted task descriptions.
+// Zero disables the cap.
+func (r *TaskRouter) SetMaxDescriptionBytes(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxDescription = n
+}
+
+// SetRouting sets the rules that choose an agent type for tasks submitted
+// without one. Nil disables routing.
+func (r *TaskRouter) SetRouting(routing *TaskRouting) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routing = routing
+}
+
+// SetPaused pauses or resumes task assignment and reports whether the state
+// changed. While paused, tasks stay pending; resuming assigns them.
+func (r *TaskRouter) SetPaused(paused bool) bool {
+	r.mu.Lock()
+	changed := r.paused != paused
+	r.paused = paused
+	r.mu.Unlock()
+
+	if !changed {
+		return false
+	}
+	if paused {
+		r.emitStatusEvent("scheduler paused: new tasks will stay pending")
+	} else {
+		r.emitStatusEvent("scheduler resumed")
+		go r.ProcessPendingTasks()
+	}
+	return true
+}
+
+// Paused reports whether task assignment is paused
+func (r *TaskRouter) Paused() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.paused
+}
+
+// validateDescription trims trailing whitespace from a task description and
+// checks it against the size limit. Caller must hold r.mu.
+func (r *TaskRouter) validateDescription(description string) (string, error) {
+	description = strings.TrimRightFunc(description, unicode.IsSpace)
+	if r.maxDescription > 0 && len(description) > r.maxDescription {
+		return "", invalidArgumentf("task description is %d bytes, over the %d byte limit (task.max-description-bytes)", len(description), r.maxDescription)
+	}
+	return description, nil
+}
+
 // SubmitTask creates a new task and routes it to an available agent
 func (r *TaskRouter) SubmitTask(ctx context.Context, req *mapv1.SubmitTaskRequest) (*mapv1.Task, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	description, err := r.validateDescription(req.GetDescription())
+	if err != nil {
+		return nil, err
+	}
+
+	agentType := req.GetAgentType()
+	if agentType == "" {
+		agentType = r.routing.AgentTypeFor(req.GetRepoRoot(), req.ScopePaths, description, nil)
+	} else if !ValidAgentType(agentType) {
+		return nil, invalidArgumentf("invalid agent type %q: must be %s", agentType, AgentTypeChoices())
+	}
+
 	taskID := uuid.New().String()
 	now := time.Now()
 
 	// Create task record with optional GitHub source
 	record := &TaskRecord{
 		TaskID:            taskID,
-		Description:       req.Description,
+		Description:       description,
 		ScopePaths:        req.ScopePaths,
 		Status:            "pending",
 		CreatedAt:         now,
@@ -48,6 +179,10 @@ func (r *TaskRouter) SubmitTask(ctx context.Context, req *mapv1.SubmitTaskReques
 		GitHubRepo:        req.GetGithubRepo(),
 		GitHubIssueNumber: int(req.GetGithubIssueNumber()),
 		RepoRoot:          req.GetRepoRoot(),
+		RunID:             req.GetRunId(),
+		PreferAgent:       req.GetPreferAgent(),
+		NoWorktree:        req.GetNoWorktree(),
+		AgentType:         agentType,
 	}
 
 	if err := r.store.CreateTask(record); err != nil {
@@ -56,11 +191,15 @@ func (r *TaskRouter) SubmitTask(ctx context.Context, req *mapv1.SubmitTaskReques
 
 	task := &mapv1.Task{
 		TaskId:      taskID,
-		Description: req.Description,
+		Description: description,
 		ScopePaths:  req.ScopePaths,
 		Status:      mapv1.TaskStatus_TASK_STATUS_PENDING,
 		CreatedAt:   timestamppb.New(now),
 		UpdatedAt:   timestamppb.New(now),
+		RunId:       record.RunID,
+		PreferAgent: record.PreferAgent,
+		NoWorktree:  record.NoWorktree,
+		AgentType:   record.AgentType,
 	}
 
 	// Add GitHub source if provided
@@ -72,25 +211,41 @@ func (r *TaskRouter) SubmitTask(ctx context.Context, req *mapv1.SubmitTaskReques
 		}
 	}
 
+	r.applyEstimate(task, record)
+
 	// Emit task created event
 	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_CREATED, task, "")
 
 	// Try to route immediately (non-blocking)
-	go r.routeTask(task)
+	go r.routeTask(task, record.RepoRoot)
 
 	return task, nil
 }
 
-// routeTask attempts to assign a task to an available agent
-func (r *TaskRouter) routeTask(task *mapv1.Task) {
+// routeTask attempts to assign a task to an available agent, spawning one
+// if auto-spawn is enabled and none are idle
+func (r *TaskRouter) routeTask(task *mapv1.Task, repoRoot string) {
+	// While paused the task waits in the queue for ProcessPendingTasks
+	if r.Paused() {
+		return
+	}
+
 	// Try to route to a spawned agent
 	if r.spawned != nil {
-		if slot := r.spawned.FindAvailableAgent(); slot != nil {
+		if slot := r.spawned.FindAvailableAgent(task); slot != nil {
 			r.executeOnSpawnedAgent(task, slot)
 			return
 		}
 	}
-	// No agents available, task remains pending
+
+	// No agents available: optionally spawn one, which will pick up the
+	// pending task once it registers. Otherwise the task remains pending.
+	r.mu.RLock()
+	spawn := r.autoSpawn
+	r.mu.RUnlock()
+	if spawn != nil {
+		_ = spawn(repoRoot)
+	}
 }
 
 // ProcessPendingTasks assigns pending tasks to available agents.
@@ -99,9 +254,13 @@ func (r *TaskRouter) ProcessPendingTasks() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.paused {
+		return
+	}
+
 	// Get pending tasks ordered by creation time (oldest first)
 	// No repo filter here - process all pending tasks
-	pendingTasks, err := r.store.ListTasks("pending", "", "", 0)
+	pendingTasks, err := r.store.ListTasks("pending", "", "", 0, false)
 	if err != nil {
 		return
 	}
@@ -114,20 +273,36 @@ func (r *TaskRouter) ProcessPendingTasks() {
 		if r.spawned == nil {
 			return
 		}
-		slot := r.spawned.FindAvailableAgent()
-		if slot == nil {
+		if len(r.spawned.ListIdle()) == 0 {
 			// No more available agents
 			return
 		}
 
-		// Convert to proto and assign
+		// The scheduler may hold a task back for a better-suited agent
 		protoTask := taskRecordToProto(task)
+		slot := r.spawned.FindAvailableAgent(protoTask)
+		if slot == nil {
+			continue
+		}
 		r.executeOnSpawnedAgent(protoTask, slot)
 	}
 }
 
 // executeOnSpawnedAgent runs a task on a spawned Claude agent slot
 func (r *TaskRouter) executeOnSpawnedAgent(task *mapv1.Task, slot *AgentSlot) {
+	// Agents that choose their work get an offer instead
+	slot.mu.Lock()
+	offer := slot.OfferTasks
+	slot.mu.Unlock()
+	if offer {
+		r.offerTask(task, slot)
+		return
+	}
+	r.startTask(task, slot)
+}
+
+// startTask marks a task in progress on an agent and sends it the task
+func (r *TaskRouter) startTask(task *mapv1.Task, slot *AgentSlot) {
 	// Update task status to in_progress
 	_ = r.store.AssignTask(task.TaskId, slot.AgentID)
 	_ = r.store.UpdateTaskStatus(task.TaskId, "in_progress")
@@ -141,7 +316,18 @@ func (r *TaskRouter) executeOnSpawnedAgent(task *mapv1.Task, slot *AgentSlot) {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 		defer cancel()
 
-		_, err := r.spawned.ExecuteTask(ctx, slot.AgentID, task.TaskId, task.Description, task.ScopePaths)
+		// Linking may wait on gh, so it doesn't hold up the prompt
+		go r.linkGitHubSource(context.Background(), task.TaskId, slot.WorktreePath)
+		r.captureEnvironment(task.TaskId, slot)
+		r.startAttempt(task, slot)
+
+		output, err := r.spawned.ExecuteTask(ctx, slot.AgentID, task.TaskId, task.Description, task.ScopePaths)
+
+		// Headless agents run the task to completion, so record the outcome
+		if slot.Headless {
+			r.finishHeadlessTask(task.TaskId, slot.AgentID, output, err)
+			return
+		}
 
 		// Only update task if sending to tmux failed
 		if err != nil {
@@ -154,14 +340,73 @@ func (r *TaskRouter) executeOnSpawnedAgent(task *mapv1.Task, slot *AgentSlot) {
 			record.Status = "failed"
 			record.Error = err.Error()
 			_ = r.store.UpdateTask(record)
+			r.finishAttempt(record)
 
 			protoTask := taskRecordToProto(record)
 			r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_FAILED, protoTask, slot.AgentID)
+			r.applyOnComplete(task.TaskId, slot.AgentID)
 		}
 		// Task stays in_progress - user can manually complete/cancel via CLI
 	}()
 }
 
+// finishHeadlessTask stores a headless run's output as the task result and
+// marks the task completed, or failed if the run failed. A task cancelled
+// while it ran keeps its cancelled status.
+func (r *TaskRouter) finishHeadlessTask(taskID, agentID, output string, runErr error) {
+	record, _ := r.store.GetTask(taskID)
+	if record == nil || record.Status == "cancelled" {
+		return
+	}
+
+	now := time.Now()
+	record.Result = output
+	record.UpdatedAt = now
+	eventType := mapv1.EventType_EVENT_TYPE_TASK_COMPLETED
+	if runErr != nil {
+		record.Status = "failed"
+		record.Error = runErr.Error()
+		eventType = mapv1.EventType_EVENT_TYPE_TASK_FAILED
+	} else {
+		record.Status = "completed"
+		record.CompletedAt = now
+	}
+	if err := r.store.UpdateTask(record); err != nil {
+		log.Printf("task %s: failed to store headless result: %v", taskID, err)
+		return
+	}
+	r.finishAttempt(record)
+
+	r.emitTaskEvent(eventType, taskRecordToProto(record), agentID)
+	r.applyOnComplete(taskID, agentID)
+}
+
+// linkGitHubSource links a task without GitHub metadata to the issue its
+// agent's branch is for, so questions and answers can flow through the issue.
+// Failures are ignored: most branches aren't tied to an issue. It runs
+// alongside the task, so each gh call is bounded by the GitHub timeout.
+func (r *TaskRouter) linkGitHubSource(ctx context.Context, taskID, workdir string) {
+	if workdir == "" {
+		return
+	}
+	r.mu.RLock()
+	timeout := r.ghTimeout
+	r.mu.RUnlock()
+
+	record, err := r.store.GetTask(taskID)
+	if err != nil || record == nil || record.GitHubIssueNumber != 0 {
+		return
+	}
+
+	src, ok := inferGitHubSource(ctx, workdir, timeout)
+	if !ok {
+		return
+	}
+	if linked, err := r.store.SetTaskGitHubSource(taskID, src.Owner, src.Repo, src.Number); err == nil && linked {
+		log.Printf("task %s linked to %s/%s#%d from its agent's branch", taskID, src.Owner, src.Repo, src.Number)
+	}
+}
+
 // GetTask retrieves a task by ID
 func (r *TaskRouter) GetTask(taskID string) (*mapv1.Task, error) {
 	record, err := r.store.GetTask(taskID)
@@ -171,23 +416,151 @@ func (r *TaskRouter) GetTask(taskID string) (*mapv1.Task, error) {
 	if record == nil {
 		return nil, nil
 	}
-	return taskRecordToProto(record), nil
+	task := r.taskRecordToProtoWithGitHub(record)
+	r.applyEstimate(task, record)
+	return task, nil
+}
+
+// estimateHistoryLimit caps how many recently completed tasks an estimate
+// is drawn from
+const estimateHistoryLimit = 200
+
+// applyEstimate fills in the estimated duration for a task from the
+// durations of similar completed tasks in the same repo
+func (r *TaskRouter) applyEstimate(task *mapv1.Task, rec *TaskRecord) {
+	history, err := r.store.ListCompletedTasks(rec.RepoRoot, estimateHistoryLimit)
+	if err != nil {
+		return
+	}
+	estimate, samples := estimateTaskDuration(history, rec)
+	task.EstimatedDurationSeconds = int64(estimate / time.Second)
+	task.EstimateSampleSize = int32(samples)
+}
+
+// estimateTaskDuration returns the median duration of completed tasks similar
+// to rec, along with the number of tasks it was computed from. Tasks whose
+// scope paths overlap rec's are preferred; if there are none, all completed
+// tasks in the history are used.
+func estimateTaskDuration(history []*TaskRecord, rec *TaskRecord) (time.Duration, int) {
+	var similar, all []time.Duration
+	for _, h := range history {
+		if h.TaskID == rec.TaskID || h.StartedAt.IsZero() || h.CompletedAt.Before(h.StartedAt) {
+			continue
+		}
+		d := h.CompletedAt.Sub(h.StartedAt)
+		all = append(all, d)
+		if scopesOverlap(h.ScopePaths, rec.ScopePaths) {
+			similar = append(similar, d)
+		}
+	}
+
+	if len(similar) > 0 {
+		return medianDuration(similar), len(similar)
+	}
+	return medianDuration(all), len(all)
+}
+
+// scopesOverlap reports whether any path in a is equal to, or nested under,
+// any path in b (or vice versa)
+func scopesOverlap(a, b []string) bool {
+	for _, pa := range a {
+		for _, pb := range b {
+			if pathWithin(pa, pb) || pathWithin(pb, pa) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathWithin reports whether path equals dir or is nested under it
+func pathWithin(path, dir string) bool {
+	path = filepath.Clean(path)
+	dir = filepath.Clean(dir)
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// medianDuration returns the median of the given durations, or 0 if empty
+func medianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
 }
 
 // ListTasks retrieves tasks with optional filters
-func (r *TaskRouter) ListTasks(statusFilter, agentFilter, repoRoot string, limit int) ([]*mapv1.Task, error) {
-	records, err := r.store.ListTasks(statusFilter, agentFilter, repoRoot, limit)
+func (r *TaskRouter) ListTasks(statusFilter, agentFilter, repoRoot string, limit int, includeArchived bool) ([]*mapv1.Task, error) {
+	records, err := r.store.ListTasks(statusFilter, agentFilter, repoRoot, limit, includeArchived)
 	if err != nil {
 		return nil, err
 	}
 
 	tasks := make([]*mapv1.Task, len(records))
 	for i, rec := range records {
-		tasks[i] = taskRecordToProto(rec)
+		tasks[i] = r.taskRecordToProtoWithGitHub(rec)
 	}
 	return tasks, nil
 }
 
+// ExportTasks returns every task, archived ones included, oldest first and
+// with spilled results read back in
+func (r *TaskRouter) ExportTasks() ([]*mapv1.Task, error) {
+	records, err := r.store.ListTasks("", "", "", 0, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// ListTasks returns newest first
+	tasks := make([]*mapv1.Task, len(records))
+	for i, rec := range records {
+		r.store.loadResult(rec)
+		tasks[len(records)-1-i] = r.taskRecordToProtoWithGitHub(rec)
+	}
+	return tasks, nil
+}
+
+// ListRuns summarizes runs, newest first, optionally filtered by repo
+func (r *TaskRouter) ListRuns(repoRoot string, limit int) ([]*mapv1.RunSummary, error) {
+	records, err := r.store.ListRuns(repoRoot, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*mapv1.RunSummary, len(records))
+	for i, rec := range records {
+		runs[i] = runRecordToProto(rec)
+	}
+	return runs, nil
+}
+
+// GetRun returns a run's summary and its tasks, oldest first
+func (r *TaskRouter) GetRun(runID string) (*mapv1.RunSummary, []*mapv1.Task, error) {
+	record, err := r.store.GetRun(runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if record == nil {
+		return nil, nil, notFoundf("run not found: %s", runID)
+	}
+
+	records, err := r.store.ListRunTasks(runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks := make([]*mapv1.Task, len(records))
+	for i, rec := range records {
+		tasks[i] = r.taskRecordToProtoWithGitHub(rec)
+	}
+	return runRecordToProto(record), tasks, nil
+}
+
 // CancelTask cancels a task
 func (r *TaskRouter) CancelTask(taskID string) (*mapv1.Task, error) {
 	task, err := r.store.GetTask(taskID)
@@ -195,15 +568,20 @@ func (r *TaskRouter) CancelTask(taskID string) (*mapv1.Task, error) {
 		return nil, err
 	}
 	if task == nil {
-		return nil, fmt.Errorf("task not found: %s", taskID)
+		return nil, notFoundf("task not found: %s", taskID)
 	}
 
-	// Can only cancel pending or in_progress tasks
+	// Can only cancel pending, offered, or in_progress tasks
 	switch task.Status {
 	case "pending", "in_progress":
 		// OK to cancel
+	case "offered":
+		// Free the agent that was considering it
+		if r.spawned != nil {
+			defer r.spawned.ReleaseReservation(task.AssignedTo, task.TaskID)
+		}
 	default:
-		return nil, fmt.Errorf("cannot cancel task in status: %s", task.Status)
+		return nil, failedPreconditionf("cannot cancel task in status: %s", task.Status)
 	}
 
 	task.Status = "cancelled"
@@ -211,13 +589,258 @@ func (r *TaskRouter) CancelTask(taskID string) (*mapv1.Task, error) {
 	if err := r.store.UpdateTask(task); err != nil {
 		return nil, err
 	}
+	r.finishAttempt(task)
 
 	protoTask := taskRecordToProto(task)
 	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_CANCELLED, protoTask, task.AssignedTo)
+	r.applyOnComplete(task.TaskID, task.AssignedTo)
 
 	return protoTask, nil
 }
 
+// ReleaseAgentTasks cancels the active tasks assigned to an agent that is
+// being killed, or with requeue puts them back in the queue for another
+// agent. Requeued tasks aren't routed here, so the caller can remove the
+// agent before they are handed out again.
+func (r *TaskRouter) ReleaseAgentTasks(agentID string, requeue bool) ([]*mapv1.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assigned, err := r.store.ListTasks("", agentID, "", 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var released []*mapv1.Task
+	for _, task := range assigned {
+		switch task.Status {
+		case "accepted", "in_progress", "waiting_input":
+			// Still being worked on
+		case "offered":
+			// The agent never took the task, so it goes back in the queue
+			if err := r.store.WithdrawOffer(task.TaskID, task.RejectedBy); err != nil {
+				return released, fmt.Errorf("requeue task %s: %w", task.TaskID, err)
+			}
+			task.Status = "pending"
+			task.AssignedTo = ""
+			released = append(released, taskRecordToProto(task))
+			continue
+		default:
+			continue
+		}
+
+		// The attempt on this agent ends cancelled either way
+		task.Status = "cancelled"
+		task.Error = fmt.Sprintf("agent %s was killed", agentID)
+		task.UpdatedAt = time.Now()
+
+		if requeue {
+			requeued, err := r.requeue(task)
+			if err != nil {
+				return released, err
+			}
+			released = append(released, requeued)
+			continue
+		}
+
+		if err := r.store.UpdateTask(task); err != nil {
+			return released, fmt.Errorf("cancel task %s: %w", task.TaskID, err)
+		}
+		r.finishAttempt(task)
+
+		cancelled := taskRecordToProto(task)
+		r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_CANCELLED, cancelled, agentID)
+		released = append(released, cancelled)
+	}
+	return released, nil
+}
+
+// NudgeTask re-sends an in-progress task's prompt to its assigned agent. The
+// task's state is left unchanged.
+func (r *TaskRouter) NudgeTask(ctx context.Context, taskID string) (*mapv1.Task, error) {
+	task, err := r.store.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+	if task.Status != "in_progress" {
+		return nil, failedPreconditionf("cannot nudge task in status: %s", task.Status)
+	}
+	if task.AssignedTo == "" {
+		return nil, failedPreconditionf("task %s has no assigned agent", taskID)
+	}
+
+	if err := r.spawned.NudgeTask(ctx, task.AssignedTo, task.TaskID, task.Description, task.ScopePaths); err != nil {
+		return nil, err
+	}
+	return taskRecordToProto(task), nil
+}
+
+// RetryTask re-queues a failed or cancelled task and routes it to an
+// available agent. Tasks that have reached the retry cap are rejected.
+func (r *TaskRouter) RetryTask(taskID string) (*mapv1.Task, error) {
+	r.mu.Lock()
+	task, err := r.store.GetTask(taskID)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	if task == nil {
+		r.mu.Unlock()
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+
+	switch task.Status {
+	case "failed", "cancelled":
+		// OK to retry
+	default:
+		r.mu.Unlock()
+		return nil, failedPreconditionf("cannot retry task in status: %s", task.Status)
+	}
+
+	if r.retryLimitReached(task) {
+		r.mu.Unlock()
+		return nil, resourceExhaustedf("task %s reached the retry limit (%d)", taskID, r.maxRetries)
+	}
+
+	retried, err := r.requeue(task)
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	go r.routeTask(retried, task.RepoRoot)
+	return retried, nil
+}
+
+// ReopenTask moves a completed task that needs more work back into work.
+// If the agent that completed it is still running in a tmux session and
+// isn't working on another task, the task resumes in_progress there, where
+// the agent still has its context; otherwise it goes back to pending and is
+// routed as usual, preferring that agent. The completed attempt is kept,
+// and the reopened work is a new attempt. Cancelled tasks can't be
+// reopened; retry them instead.
+func (r *TaskRouter) ReopenTask(taskID string) (*mapv1.Task, error) {
+	r.mu.Lock()
+	task, err := r.store.GetTask(taskID)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, err
+	}
+	if task == nil {
+		r.mu.Unlock()
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+
+	switch task.Status {
+	case "completed":
+		// OK to reopen
+	case "cancelled", "failed":
+		r.mu.Unlock()
+		return nil, failedPreconditionf("cannot reopen a %s task; use 'map task retry' instead", task.Status)
+	default:
+		r.mu.Unlock()
+		return nil, failedPreconditionf("cannot reopen task in status: %s", task.Status)
+	}
+
+	var agentID string
+	var slot *AgentSlot
+	if task.AssignedTo != "" && r.spawned != nil {
+		// An agent already on another task would end up with two
+		if s := r.spawned.Get(task.AssignedTo); s != nil && !s.Headless && !r.store.AgentWorking(s.AgentID) {
+			agentID, slot = s.AgentID, s
+		}
+	}
+
+	r.finishAttempt(task)
+	if err := r.store.ReopenTask(taskID, agentID); err != nil {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("reopen task %s: %w", taskID, err)
+	}
+	record, err := r.store.GetTask(taskID)
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	reopened := taskRecordToProto(record)
+	if slot != nil {
+		// The task resumes without going through startTask, so its new
+		// attempt is recorded here
+		r.captureEnvironment(taskID, slot)
+		r.startAttempt(reopened, slot)
+	}
+	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_REOPENED, reopened, agentID)
+
+	if agentID == "" {
+		go r.routeTask(reopened, record.RepoRoot)
+	}
+	return reopened, nil
+}
+
+// RetryFailedTasks re-queues every failed task, optionally limited to one
+// repo and to tasks that failed at or after since. Tasks at the retry cap are
+// returned as skipped and left failed.
+func (r *TaskRouter) RetryFailedTasks(repoRoot string, since time.Time) (retried, skipped []*mapv1.Task, err error) {
+	r.mu.Lock()
+	failed, err := r.store.ListTasks("failed", "", repoRoot, 0, false)
+	if err != nil {
+		r.mu.Unlock()
+		return nil, nil, err
+	}
+
+	// Retry oldest first so they keep their relative queue order
+	for i := len(failed) - 1; i >= 0; i-- {
+		task := failed[i]
+		if !since.IsZero() && task.UpdatedAt.Before(since) {
+			continue
+		}
+		if r.retryLimitReached(task) {
+			skipped = append(skipped, taskRecordToProto(task))
+			continue
+		}
+
+		t, err := r.requeue(task)
+		if err != nil {
+			r.mu.Unlock()
+			return retried, skipped, err
+		}
+		retried = append(retried, t)
+	}
+	r.mu.Unlock()
+
+	if len(retried) > 0 {
+		go r.ProcessPendingTasks()
+	}
+	return retried, skipped, nil
+}
+
+// retryLimitReached reports whether a task may not be retried again.
+// Caller must hold r.mu.
+func (r *TaskRouter) retryLimitReached(task *TaskRecord) bool {
+	return r.maxRetries > 0 && task.RetryCount >= r.maxRetries
+}
+
+// requeue resets a task to pending, keeping the outcome of the attempt that
+// just ended. Caller must hold r.mu.
+func (r *TaskRouter) requeue(task *TaskRecord) (*mapv1.Task, error) {
+	r.finishAttempt(task)
+	if err := r.store.RequeueTask(task.TaskID); err != nil {
+		return nil, fmt.Errorf("requeue task %s: %w", task.TaskID, err)
+	}
+
+	record, err := r.store.GetTask(task.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	retried := taskRecordToProto(record)
+	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_CREATED, retried, "")
+	return retried, nil
+}
+
 func (r *TaskRouter) emitTaskEvent(eventType mapv1.EventType, task *mapv1.Task, agentID string) {
 	event := &mapv1.Event{
 		EventId:   uuid.New().String(),
@@ -232,15 +855,24 @@ func (r *TaskRouter) emitTaskEvent(eventType mapv1.EventType, task *mapv1.Task,
 		},
 	}
 
-	// Non-blocking send
-	select {
-	case r.eventCh <- event:
-	default:
+	sendEvent(r.eventCh, r.drops, event)
+}
+
+// emitStatusEvent broadcasts a message about the router's state
+func (r *TaskRouter) emitStatusEvent(message string) {
+	event := &mapv1.Event{
+		EventId:   uuid.New().String(),
+		Timestamp: timestamppb.Now(),
+		Payload: &mapv1.Event_Status{
+			Status: &mapv1.StatusEvent{Message: message},
+		},
 	}
+
+	sendEvent(r.eventCh, r.drops, event)
 }
 
 func taskRecordToProto(rec *TaskRecord) *mapv1.Task {
-	return &mapv1.Task{
+	task := &mapv1.Task{
 		TaskId:      rec.TaskID,
 		Description: rec.Description,
 		ScopePaths:  rec.ScopePaths,
@@ -250,23 +882,46 @@ func taskRecordToProto(rec *TaskRecord) *mapv1.Task {
 		Error:       rec.Error,
 		CreatedAt:   timestamppb.New(rec.CreatedAt),
 		UpdatedAt:   timestamppb.New(rec.UpdatedAt),
+		RetryCount:  int32(rec.RetryCount),
+		RunId:       rec.RunID,
+		PreferAgent: rec.PreferAgent,
+		NoWorktree:  rec.NoWorktree,
+		AgentType:   rec.AgentType,
+		RepoRoot:    rec.RepoRoot,
+		Environment: decodeEnvironment(rec.Environment),
+		RejectedBy:  rec.RejectedBy,
+	}
+	if !rec.StartedAt.IsZero() {
+		task.StartedAt = timestamppb.New(rec.StartedAt)
+	}
+	if !rec.ArchivedAt.IsZero() {
+		task.ArchivedAt = timestamppb.New(rec.ArchivedAt)
+	}
+	if !rec.CompletedAt.IsZero() {
+		task.CompletedAt = timestamppb.New(rec.CompletedAt)
+	}
+	return task
+}
+
+func runRecordToProto(rec *RunRecord) *mapv1.RunSummary {
+	return &mapv1.RunSummary{
+		RunId:     rec.RunID,
+		RepoRoot:  rec.RepoRoot,
+		Total:     int32(rec.Total),
+		Pending:   int32(rec.Pending),
+		Active:    int32(rec.Active),
+		Completed: int32(rec.Completed),
+		Failed:    int32(rec.Failed),
+		Cancelled: int32(rec.Cancelled),
+		CreatedAt: timestamppb.New(rec.CreatedAt),
+		UpdatedAt: timestamppb.New(rec.UpdatedAt),
 	}
 }
 
 // taskRecordToProtoWithGitHub converts TaskRecord to proto including GitHub fields
 func (r *TaskRouter) taskRecordToProtoWithGitHub(rec *TaskRecord) *mapv1.Task {
-	task := &mapv1.Task{
-		TaskId:                rec.TaskID,
-		Description:           rec.Description,
-		ScopePaths:            rec.ScopePaths,
-		Status:                taskStatusFromString(rec.Status),
-		AssignedTo:            rec.AssignedTo,
-		Result:                rec.Result,
-		Error:                 rec.Error,
-		CreatedAt:             timestamppb.New(rec.CreatedAt),
-		UpdatedAt:             timestamppb.New(rec.UpdatedAt),
-		WaitingInputQuestion:  rec.WaitingInputQuestion,
-	}
+	task := taskRecordToProto(rec)
+	task.WaitingInputQuestion = rec.WaitingInputQuestion
 
 	if rec.GitHubOwner != "" && rec.GitHubRepo != "" && rec.GitHubIssueNumber > 0 {
 		task.GithubSource = &mapv1.GitHubSource{
@@ -0,0 +1,38 @@
+package daemon
+
+import (
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// ArchiveTask hides a finished task from task listings while keeping it for
+// history and export. Archiving an archived task leaves it as it is.
+// Retrying or reopening the task brings it back.
+func (r *TaskRouter) ArchiveTask(taskID string) (*mapv1.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, err := r.store.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+
+	switch task.Status {
+	case "completed", "failed", "cancelled":
+		// Finished, OK to archive
+	default:
+		return nil, failedPreconditionf("cannot archive task in status: %s; cancel it first", task.Status)
+	}
+
+	if task.ArchivedAt.IsZero() {
+		task.ArchivedAt = time.Now()
+		if err := r.store.ArchiveTask(taskID, task.ArchivedAt); err != nil {
+			return nil, err
+		}
+	}
+	return r.taskRecordToProtoWithGitHub(task), nil
+}
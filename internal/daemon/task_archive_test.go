@@ -0,0 +1,79 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaskRouter_ArchiveTask(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, rec := range []*TaskRecord{
+		{TaskID: "done", Description: "done", Status: "completed", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "broken", Description: "broken", Status: "failed", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "queued", Description: "queued", Status: "pending", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.CreateTask(rec); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	task, err := router.ArchiveTask("done")
+	if err != nil {
+		t.Fatalf("ArchiveTask: %v", err)
+	}
+	if task.ArchivedAt == nil {
+		t.Fatal("archived task has no archived_at")
+	}
+	if _, err := router.ArchiveTask("broken"); err != nil {
+		t.Fatalf("ArchiveTask(failed): %v", err)
+	}
+
+	// Archiving again is harmless
+	if _, err := router.ArchiveTask("done"); err != nil {
+		t.Errorf("ArchiveTask twice: %v", err)
+	}
+
+	if _, err := router.ArchiveTask("queued"); err == nil {
+		t.Error("expected an error archiving a pending task")
+	}
+	if _, err := router.ArchiveTask("missing"); err == nil {
+		t.Error("expected an error archiving an unknown task")
+	}
+
+	listed, err := router.ListTasks("", "", "", 0, false)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(listed) != 1 || listed[0].TaskId != "queued" {
+		t.Errorf("ListTasks = %d tasks, want only the pending one", len(listed))
+	}
+	all, err := router.ListTasks("", "", "", 0, true)
+	if err != nil {
+		t.Fatalf("ListTasks(include archived): %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("ListTasks(include archived) = %d tasks, want 3", len(all))
+	}
+
+	// Archived tasks can still be looked up
+	got, err := router.GetTask("done")
+	if err != nil || got == nil || got.ArchivedAt == nil {
+		t.Errorf("GetTask(archived) = %v, %v", got, err)
+	}
+
+	// Retrying brings a task back out of the archive
+	router.SetPaused(true)
+	if _, err := router.RetryTask("broken"); err != nil {
+		t.Fatalf("RetryTask: %v", err)
+	}
+	rec, err := store.GetTask("broken")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	if !rec.ArchivedAt.IsZero() {
+		t.Error("retried task is still archived")
+	}
+}
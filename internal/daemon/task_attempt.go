@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// startAttempt records the start of a task's current attempt on an agent,
+// noting the worktree's HEAD so the attempt's changes can be listed later
+func (r *TaskRouter) startAttempt(task *mapv1.Task, slot *AgentSlot) {
+	attempt := &TaskAttemptRecord{
+		TaskID:    task.TaskId,
+		Attempt:   int(task.RetryCount) + 1,
+		AgentID:   slot.AgentID,
+		Status:    "in_progress",
+		StartedAt: time.Now(),
+	}
+	if slot.WorktreePath != "" {
+		attempt.BaseCommit = gitOutput(slot.WorktreePath, "rev-parse", "HEAD")
+	}
+	if err := r.store.SaveTaskAttempt(attempt); err != nil {
+		log.Printf("task %s: failed to record attempt %d: %v", task.TaskId, attempt.Attempt, err)
+	}
+}
+
+// finishAttempt records the outcome of a task's current attempt along with
+// the files its agent changed. Attempts that already finished are left alone,
+// as are tasks that never reached an agent.
+func (r *TaskRouter) finishAttempt(record *TaskRecord) {
+	number := record.RetryCount + 1
+	attempt, err := r.store.GetTaskAttempt(record.TaskID, number)
+	if err != nil {
+		log.Printf("task %s: failed to load attempt %d: %v", record.TaskID, number, err)
+		return
+	}
+	if attempt == nil {
+		if record.AssignedTo == "" {
+			return
+		}
+		attempt = &TaskAttemptRecord{
+			TaskID:    record.TaskID,
+			Attempt:   number,
+			AgentID:   record.AssignedTo,
+			StartedAt: record.StartedAt,
+		}
+	}
+	if !attempt.FinishedAt.IsZero() {
+		return
+	}
+
+	attempt.Status = record.Status
+	attempt.Result = record.Result
+	attempt.Error = record.Error
+	attempt.FinishedAt = time.Now()
+	if workdir := r.agentWorkdir(attempt.AgentID); workdir != "" {
+		attempt.ChangedFiles = changedFiles(workdir, attempt.BaseCommit)
+	}
+
+	if err := r.store.SaveTaskAttempt(attempt); err != nil {
+		log.Printf("task %s: failed to record attempt %d: %v", record.TaskID, number, err)
+	}
+}
+
+// ListTaskAttempts returns a task's attempts, oldest first. An attempt still
+// running reflects the task's current status and the agent's changes so far.
+func (r *TaskRouter) ListTaskAttempts(taskID string) ([]*mapv1.TaskAttempt, error) {
+	task, err := r.store.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+
+	records, err := r.store.ListTaskAttempts(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]*mapv1.TaskAttempt, len(records))
+	for i, rec := range records {
+		if rec.FinishedAt.IsZero() && rec.Attempt == task.RetryCount+1 {
+			rec.Status = task.Status
+			rec.Result = task.Result
+			rec.Error = task.Error
+			if workdir := r.agentWorkdir(rec.AgentID); workdir != "" {
+				rec.ChangedFiles = changedFiles(workdir, rec.BaseCommit)
+			}
+		}
+		attempts[i] = taskAttemptToProto(rec)
+	}
+	return attempts, nil
+}
+
+// agentWorkdir returns the worktree of a live agent, or "" if the agent is
+// gone or runs without a worktree
+func (r *TaskRouter) agentWorkdir(agentID string) string {
+	if r.spawned == nil || agentID == "" {
+		return ""
+	}
+	if slot := r.spawned.Get(agentID); slot != nil {
+		return slot.WorktreePath
+	}
+	return ""
+}
+
+// changedFiles lists files in workdir that differ from base, including
+// committed, uncommitted, and untracked changes. An empty base compares
+// against HEAD.
+func changedFiles(workdir, base string) []string {
+	if base == "" {
+		base = "HEAD"
+	}
+
+	var files []string
+	for _, out := range []string{
+		gitOutput(workdir, "diff", "--name-only", base),
+		gitOutput(workdir, "ls-files", "--others", "--exclude-standard"),
+	} {
+		for _, line := range strings.Split(out, "\n") {
+			if line != "" {
+				files = append(files, line)
+			}
+		}
+	}
+
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+func taskAttemptToProto(rec *TaskAttemptRecord) *mapv1.TaskAttempt {
+	attempt := &mapv1.TaskAttempt{
+		TaskId:       rec.TaskID,
+		Attempt:      int32(rec.Attempt),
+		AgentId:      rec.AgentID,
+		Status:       taskStatusFromString(rec.Status),
+		Result:       rec.Result,
+		Error:        rec.Error,
+		ChangedFiles: rec.ChangedFiles,
+	}
+	if !rec.StartedAt.IsZero() {
+		attempt.StartedAt = timestamppb.New(rec.StartedAt)
+	}
+	if !rec.FinishedAt.IsZero() {
+		attempt.FinishedAt = timestamppb.New(rec.FinishedAt)
+	}
+	return attempt
+}
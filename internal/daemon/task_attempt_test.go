@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestStore_TaskAttempts(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, attempt := range []*TaskAttemptRecord{
+		{TaskID: "t1", Attempt: 2, AgentID: "b", Status: "in_progress", StartedAt: now},
+		{TaskID: "t1", Attempt: 1, AgentID: "a", Status: "failed", Error: "boom", ChangedFiles: []string{"x.go"}, StartedAt: now, FinishedAt: now},
+		{TaskID: "t2", Attempt: 1, AgentID: "c", Status: "in_progress", StartedAt: now},
+	} {
+		if err := store.SaveTaskAttempt(attempt); err != nil {
+			t.Fatalf("SaveTaskAttempt failed: %v", err)
+		}
+	}
+
+	attempts, err := store.ListTaskAttempts("t1")
+	if err != nil {
+		t.Fatalf("ListTaskAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0].Attempt != 1 || attempts[1].Attempt != 2 {
+		t.Fatalf("attempts = %+v, want attempts 1 and 2 in order", attempts)
+	}
+	if attempts[0].Error != "boom" || !slices.Equal(attempts[0].ChangedFiles, []string{"x.go"}) || attempts[0].FinishedAt.IsZero() {
+		t.Errorf("attempt 1 = %+v, want its outcome and changed files", attempts[0])
+	}
+
+	// Saving again replaces the attempt
+	attempts[1].Status = "completed"
+	attempts[1].FinishedAt = now
+	if err := store.SaveTaskAttempt(attempts[1]); err != nil {
+		t.Fatalf("SaveTaskAttempt failed: %v", err)
+	}
+	got, err := store.GetTaskAttempt("t1", 2)
+	if err != nil || got == nil || got.Status != "completed" {
+		t.Errorf("GetTaskAttempt = %+v, %v; want completed attempt", got, err)
+	}
+
+	missing, err := store.GetTaskAttempt("t1", 3)
+	if err != nil || missing != nil {
+		t.Errorf("GetTaskAttempt for a missing attempt = %+v, %v; want nil, nil", missing, err)
+	}
+}
+
+func TestTaskRouter_RetryKeepsAttempts(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{
+		TaskID: "flaky", Status: "failed", AssignedTo: "claude-a", Error: "first failure",
+		CreatedAt: now, UpdatedAt: now,
+	}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	if _, err := router.RetryTask("flaky"); err != nil {
+		t.Fatalf("RetryTask failed: %v", err)
+	}
+
+	// The second attempt runs on another agent and is cancelled
+	record, _ := store.GetTask("flaky")
+	router.startAttempt(taskRecordToProto(record), &AgentSlot{AgentID: "claude-b"})
+	if err := store.AssignTask("flaky", "claude-b"); err != nil {
+		t.Fatalf("AssignTask failed: %v", err)
+	}
+	if err := store.UpdateTaskStatus("flaky", "in_progress"); err != nil {
+		t.Fatalf("UpdateTaskStatus failed: %v", err)
+	}
+	if _, err := router.CancelTask("flaky"); err != nil {
+		t.Fatalf("CancelTask failed: %v", err)
+	}
+
+	attempts, err := router.ListTaskAttempts("flaky")
+	if err != nil {
+		t.Fatalf("ListTaskAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(attempts))
+	}
+	if attempts[0].AgentId != "claude-a" || attempts[0].Status != mapv1.TaskStatus_TASK_STATUS_FAILED || attempts[0].Error != "first failure" {
+		t.Errorf("attempt 1 = %+v, want the original failure", attempts[0])
+	}
+	if attempts[1].AgentId != "claude-b" || attempts[1].Status != mapv1.TaskStatus_TASK_STATUS_CANCELLED || attempts[1].FinishedAt == nil {
+		t.Errorf("attempt 2 = %+v, want a finished cancelled attempt", attempts[1])
+	}
+
+	if _, err := router.ListTaskAttempts("nope"); err == nil {
+		t.Error("expected error for unknown task")
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	initTestGitRepo(t, dir)
+	base := gitOutput(dir, "rev-parse", "HEAD")
+
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// A committed change, an uncommitted edit, and an untracked file
+	write("committed.go", "package x")
+	git("add", "committed.go")
+	git("commit", "-m", "add committed.go")
+	write("README.md", "# Changed")
+	write("untracked.txt", "new")
+
+	want := []string{"README.md", "committed.go", "untracked.txt"}
+	if got := changedFiles(dir, base); !slices.Equal(got, want) {
+		t.Errorf("changedFiles(base) = %v, want %v", got, want)
+	}
+
+	// Without a base, committed changes are not included
+	want = []string{"README.md", "untracked.txt"}
+	if got := changedFiles(dir, ""); !slices.Equal(got, want) {
+		t.Errorf("changedFiles(HEAD) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"log"
+	"os"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// captureEnvironment records the conditions a task is about to run under
+// on slot, replacing any snapshot from an earlier attempt
+func (r *TaskRouter) captureEnvironment(taskID string, slot *AgentSlot) {
+	data, err := protojson.Marshal(snapshotEnvironment(slot, time.Now()))
+	if err != nil {
+		log.Printf("task %s: failed to encode environment: %v", taskID, err)
+		return
+	}
+	if err := r.store.SetTaskEnvironment(taskID, string(data)); err != nil {
+		log.Printf("task %s: failed to record environment: %v", taskID, err)
+	}
+}
+
+// snapshotEnvironment describes the agent in slot and the state of its
+// working directory
+func snapshotEnvironment(slot *AgentSlot, now time.Time) *mapv1.TaskEnvironment {
+	slot.mu.Lock()
+	env := &mapv1.TaskEnvironment{
+		AgentId:        slot.AgentID,
+		AgentType:      slot.AgentType,
+		Workdir:        slot.WorktreePath,
+		Worktree:       slot.Isolated,
+		PermissionMode: "prompt",
+		Headless:       slot.Headless,
+		Image:          slot.Image,
+		CapturedAt:     timestamppb.New(now),
+	}
+	if slot.SkipPermissions {
+		env.PermissionMode = "skip"
+	}
+	// Variables copied into the agent's session take precedence; otherwise
+	// the agent inherits the daemon's value of the CLI's model variable
+	if spec := LookupAgentType(env.AgentType); spec != nil && spec.ModelEnvVar != "" {
+		model, ok := slot.Env[spec.ModelEnvVar]
+		if !ok {
+			model = os.Getenv(spec.ModelEnvVar)
+		}
+		env.Model = model
+	}
+	slot.mu.Unlock()
+	if env.Workdir != "" {
+		env.Branch = gitOutput(env.Workdir, "symbolic-ref", "--short", "-q", "HEAD")
+		env.HeadSha = gitOutput(env.Workdir, "rev-parse", "HEAD")
+	}
+	return env
+}
+
+// decodeEnvironment parses a stored environment snapshot, returning nil if
+// there is none or it can't be read
+func decodeEnvironment(data string) *mapv1.TaskEnvironment {
+	if data == "" {
+		return nil
+	}
+	env := &mapv1.TaskEnvironment{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal([]byte(data), env); err != nil {
+		return nil
+	}
+	return env
+}
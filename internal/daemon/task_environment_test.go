@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	initTestGitRepo(t, dir)
+	t.Setenv("ANTHROPIC_MODEL", "claude-test-model")
+
+	slot := &AgentSlot{
+		AgentID:         "claude-swift-fox",
+		AgentType:       AgentTypeClaude,
+		WorktreePath:    dir,
+		Isolated:        true,
+		SkipPermissions: true,
+	}
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	env := snapshotEnvironment(slot, now)
+
+	if env.AgentId != "claude-swift-fox" || env.AgentType != AgentTypeClaude {
+		t.Errorf("got agent %s (%s)", env.AgentId, env.AgentType)
+	}
+	if env.Model != "claude-test-model" {
+		t.Errorf("model = %q, want it from ANTHROPIC_MODEL", env.Model)
+	}
+	if env.PermissionMode != "skip" || !env.Worktree || env.Workdir != dir {
+		t.Errorf("got permissions %q, worktree %v, workdir %q", env.PermissionMode, env.Worktree, env.Workdir)
+	}
+	if head := gitOutput(dir, "rev-parse", "HEAD"); head == "" || env.HeadSha != head {
+		t.Errorf("head = %q, want %q", env.HeadSha, head)
+	}
+	if branch := gitOutput(dir, "symbolic-ref", "--short", "HEAD"); env.Branch != branch {
+		t.Errorf("branch = %q, want %q", env.Branch, branch)
+	}
+	if !env.CapturedAt.AsTime().Equal(now) {
+		t.Errorf("captured at %v, want %v", env.CapturedAt.AsTime(), now)
+	}
+
+	// A value copied into the agent's session wins over the daemon's
+	slot.Env = map[string]string{"ANTHROPIC_MODEL": "claude-session-model"}
+	if env := snapshotEnvironment(slot, now); env.Model != "claude-session-model" {
+		t.Errorf("model = %q, want it from the slot's env", env.Model)
+	}
+
+	// Other agent types don't read ANTHROPIC_MODEL
+	slot.AgentType = AgentTypeCodex
+	if env := snapshotEnvironment(slot, now); env.Model != "" {
+		t.Errorf("codex model = %q, want empty", env.Model)
+	}
+}
+
+func TestTaskEnvironmentStored(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	record := &TaskRecord{TaskID: "task-1", Description: "x", Status: "in_progress", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if err := store.CreateTask(record); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	router.captureEnvironment("task-1", &AgentSlot{AgentID: "codex-calm-owl", AgentType: AgentTypeCodex})
+
+	task, err := router.GetTask("task-1")
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	env := task.GetEnvironment()
+	if env == nil || env.AgentId != "codex-calm-owl" || env.PermissionMode != "prompt" {
+		t.Errorf("environment = %v, want codex-calm-owl with prompts", env)
+	}
+
+	if decodeEnvironment("not json") != nil || decodeEnvironment("") != nil {
+		t.Error("decodeEnvironment should return nil for missing or unreadable snapshots")
+	}
+}
@@ -0,0 +1,126 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ImportTasks recreates exported tasks with their original IDs, timestamps,
+// status, and GitHub source. Tasks whose IDs already exist are skipped, or
+// replaced with overwrite. Every task is checked before any is written, so
+// a bad export imports nothing. Imported pending tasks join the queue.
+func (r *TaskRouter) ImportTasks(tasks []*mapv1.Task, overwrite bool) (imported int, skipped []string, err error) {
+	records := make([]*TaskRecord, 0, len(tasks))
+	seen := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		record, err := importedTaskRecord(task)
+		if err != nil {
+			return 0, nil, invalidArgumentf("task %d: %v", i+1, err)
+		}
+		if seen[record.TaskID] {
+			return 0, nil, invalidArgumentf("task %s appears more than once", record.TaskID)
+		}
+		seen[record.TaskID] = true
+		records = append(records, record)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending bool
+	for _, record := range records {
+		existing, err := r.store.GetTask(record.TaskID)
+		if err != nil {
+			return imported, skipped, fmt.Errorf("get task %s: %w", record.TaskID, err)
+		}
+		if existing != nil {
+			if !overwrite {
+				skipped = append(skipped, record.TaskID)
+				continue
+			}
+			if err := r.store.ReplaceTask(record); err != nil {
+				return imported, skipped, fmt.Errorf("replace task %s: %w", record.TaskID, err)
+			}
+		} else if err := r.store.CreateTask(record); err != nil {
+			return imported, skipped, fmt.Errorf("create task %s: %w", record.TaskID, err)
+		}
+		imported++
+		pending = pending || record.Status == "pending"
+	}
+
+	if pending && !r.paused {
+		go r.ProcessPendingTasks()
+	}
+	return imported, skipped, nil
+}
+
+// importedTaskRecord converts an exported task back into a store record
+func importedTaskRecord(task *mapv1.Task) (*TaskRecord, error) {
+	if task.GetTaskId() == "" {
+		return nil, fmt.Errorf("missing task_id")
+	}
+	status := taskStatusToString(task.GetStatus())
+	if status == "" {
+		return nil, fmt.Errorf("task %s has no status", task.GetTaskId())
+	}
+	if task.GetAgentType() != "" && !ValidAgentType(task.GetAgentType()) {
+		return nil, fmt.Errorf("task %s has invalid agent type %q", task.GetTaskId(), task.GetAgentType())
+	}
+
+	record := &TaskRecord{
+		TaskID:               task.GetTaskId(),
+		Description:          task.GetDescription(),
+		ScopePaths:           task.GetScopePaths(),
+		Status:               status,
+		AssignedTo:           task.GetAssignedTo(),
+		Result:               task.GetResult(),
+		Error:                task.GetError(),
+		CreatedAt:            timeOrZero(task.GetCreatedAt()),
+		UpdatedAt:            timeOrZero(task.GetUpdatedAt()),
+		StartedAt:            timeOrZero(task.GetStartedAt()),
+		CompletedAt:          timeOrZero(task.GetCompletedAt()),
+		ArchivedAt:           timeOrZero(task.GetArchivedAt()),
+		WaitingInputQuestion: task.GetWaitingInputQuestion(),
+		RepoRoot:             task.GetRepoRoot(),
+		RetryCount:           int(task.GetRetryCount()),
+		RunID:                task.GetRunId(),
+		PreferAgent:          task.GetPreferAgent(),
+		NoWorktree:           task.GetNoWorktree(),
+		AgentType:            task.GetAgentType(),
+		RejectedBy:           task.GetRejectedBy(),
+	}
+	if record.ScopePaths == nil {
+		record.ScopePaths = []string{}
+	}
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+	if record.UpdatedAt.IsZero() {
+		record.UpdatedAt = record.CreatedAt
+	}
+	if env := task.GetEnvironment(); env != nil {
+		data, err := protojson.Marshal(env)
+		if err != nil {
+			return nil, fmt.Errorf("encode environment: %w", err)
+		}
+		record.Environment = string(data)
+	}
+	if src := task.GetGithubSource(); src != nil {
+		record.GitHubOwner = src.GetOwner()
+		record.GitHubRepo = src.GetRepo()
+		record.GitHubIssueNumber = int(src.GetIssueNumber())
+	}
+	return record, nil
+}
+
+// timeOrZero converts a timestamp that may be unset
+func timeOrZero(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestTaskRouter_ImportTasks(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.SetPaused(true)
+
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exported := []*mapv1.Task{
+		{
+			TaskId:      "task-done",
+			Description: "Fix the login bug",
+			ScopePaths:  []string{"/repo/auth"},
+			Status:      mapv1.TaskStatus_TASK_STATUS_COMPLETED,
+			AssignedTo:  "claude-swift-fox",
+			Result:      "fixed",
+			CreatedAt:   timestamppb.New(created),
+			UpdatedAt:   timestamppb.New(created.Add(time.Hour)),
+			CompletedAt: timestamppb.New(created.Add(time.Hour)),
+			GithubSource: &mapv1.GitHubSource{
+				Owner: "acme", Repo: "web", IssueNumber: 42,
+			},
+			RepoRoot: "/repo",
+			RunId:    "run-1",
+		},
+		{
+			TaskId:      "task-queued",
+			Description: "Update the docs",
+			Status:      mapv1.TaskStatus_TASK_STATUS_PENDING,
+			CreatedAt:   timestamppb.New(created),
+		},
+	}
+
+	imported, skipped, err := router.ImportTasks(exported, false)
+	if err != nil {
+		t.Fatalf("ImportTasks: %v", err)
+	}
+	if imported != 2 || len(skipped) != 0 {
+		t.Fatalf("imported %d, skipped %v; want 2 imported, none skipped", imported, skipped)
+	}
+
+	got, err := store.GetTask("task-done")
+	if err != nil || got == nil {
+		t.Fatalf("GetTask: %v, %v", got, err)
+	}
+	if got.Status != "completed" || got.AssignedTo != "claude-swift-fox" || got.Result != "fixed" {
+		t.Errorf("got status %q, assignee %q, result %q", got.Status, got.AssignedTo, got.Result)
+	}
+	if !got.CreatedAt.Equal(created) || !got.CompletedAt.Equal(created.Add(time.Hour)) {
+		t.Errorf("timestamps not preserved: created %v, completed %v", got.CreatedAt, got.CompletedAt)
+	}
+	if got.GitHubOwner != "acme" || got.GitHubRepo != "web" || got.GitHubIssueNumber != 42 {
+		t.Errorf("GitHub source not preserved: %s/%s#%d", got.GitHubOwner, got.GitHubRepo, got.GitHubIssueNumber)
+	}
+	if got.RepoRoot != "/repo" || got.RunID != "run-1" {
+		t.Errorf("got repo %q, run %q", got.RepoRoot, got.RunID)
+	}
+
+	// Existing IDs are skipped, or replaced with overwrite
+	exported[0].Result = "fixed again"
+	imported, skipped, err = router.ImportTasks(exported[:1], false)
+	if err != nil || imported != 0 || len(skipped) != 1 || skipped[0] != "task-done" {
+		t.Fatalf("re-import = %d, %v, %v; want task-done skipped", imported, skipped, err)
+	}
+	if got, _ := store.GetTask("task-done"); got.Result != "fixed" {
+		t.Errorf("skipped task was changed: result %q", got.Result)
+	}
+
+	imported, _, err = router.ImportTasks(exported[:1], true)
+	if err != nil || imported != 1 {
+		t.Fatalf("overwrite import = %d, %v; want 1", imported, err)
+	}
+	if got, _ := store.GetTask("task-done"); got.Result != "fixed again" {
+		t.Errorf("overwritten task result = %q, want %q", got.Result, "fixed again")
+	}
+}
+
+func TestTaskRouter_ImportTasksInvalid(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	tests := []struct {
+		name  string
+		tasks []*mapv1.Task
+	}{
+		{"missing ID", []*mapv1.Task{{Description: "x", Status: mapv1.TaskStatus_TASK_STATUS_PENDING}}},
+		{"missing status", []*mapv1.Task{{TaskId: "t1", Description: "x"}}},
+		{"duplicate ID", []*mapv1.Task{
+			{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_COMPLETED},
+			{TaskId: "t1", Status: mapv1.TaskStatus_TASK_STATUS_COMPLETED},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := router.ImportTasks(tt.tasks, false); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	// Nothing is written when any task is bad
+	if got, _ := store.GetTask("t1"); got != nil {
+		t.Error("a rejected import should write nothing")
+	}
+}
+
+func TestTaskRouter_ExportTasks(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	store.SetResultStorage(10, true)
+
+	now := time.Now()
+	full := strings.Repeat("output line\n", 100)
+	for i, id := range []string{"task-old", "task-new"} {
+		created := now.Add(time.Duration(i) * time.Minute)
+		record := &TaskRecord{TaskID: id, Description: "d", Status: "completed", Result: full, CreatedAt: created, UpdatedAt: created}
+		if err := store.CreateTask(record); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	tasks, err := router.ExportTasks()
+	if err != nil {
+		t.Fatalf("ExportTasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].TaskId != "task-old" || tasks[1].TaskId != "task-new" {
+		t.Fatalf("exported %v, want task-old then task-new", tasks)
+	}
+	for _, task := range tasks {
+		if task.Result != full {
+			t.Errorf("%s: exported result of %d bytes, want the spilled %d", task.TaskId, len(task.Result), len(full))
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// DefaultOfferTimeout is how long an agent has to accept or reject a task
+// offered to it before the offer is treated as rejected
+const DefaultOfferTimeout = 5 * time.Minute
+
+// offerTask offers a task to an agent spawned with --offer-tasks instead of
+// starting it. The agent is reserved for the task until it answers with
+// map task accept or map task reject, or the offer times out. An offer that
+// can't be delivered counts as a rejection, so the task moves on.
+func (r *TaskRouter) offerTask(task *mapv1.Task, slot *AgentSlot) {
+	if err := r.spawned.ReserveAgent(slot.AgentID, task.TaskId); err != nil {
+		// Another task got the agent first; this one stays pending
+		return
+	}
+	_ = r.store.OfferTask(task.TaskId, slot.AgentID)
+	task.Status = mapv1.TaskStatus_TASK_STATUS_OFFERED
+	task.AssignedTo = slot.AgentID
+	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_OFFERED, task, slot.AgentID)
+
+	// Callers may hold r.mu, so the offer is sent in the background
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := r.spawned.SendOffer(ctx, slot.AgentID, task.TaskId, task.Description); err != nil {
+			r.withdrawOffer(task.TaskId, slot.AgentID, fmt.Sprintf("offer not delivered: %v", err))
+			return
+		}
+
+		r.mu.RLock()
+		timeout := r.offerTimeout
+		r.mu.RUnlock()
+		time.AfterFunc(timeout, func() {
+			r.withdrawOffer(task.TaskId, slot.AgentID, "offer timed out")
+		})
+	}()
+}
+
+// AcceptTask claims a task offered to agentID, which starts working on it
+func (r *TaskRouter) AcceptTask(taskID, agentID string) (*mapv1.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.offeredTask(taskID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	var slot *AgentSlot
+	if r.spawned != nil {
+		slot = r.spawned.Get(record.AssignedTo)
+	}
+	if slot == nil {
+		return nil, failedPreconditionf("agent %s no longer exists", record.AssignedTo)
+	}
+
+	task := taskRecordToProto(record)
+	_ = r.store.AssignTask(taskID, slot.AgentID)
+	task.Status = mapv1.TaskStatus_TASK_STATUS_ACCEPTED
+	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_ACCEPTED, task, slot.AgentID)
+	log.Printf("task %s accepted by agent %s", taskID, slot.AgentID)
+
+	r.startTask(task, slot)
+	return task, nil
+}
+
+// RejectTask declines a task offered to agentID. The task goes back to
+// pending and won't be offered to that agent again.
+func (r *TaskRouter) RejectTask(taskID, agentID, reason string) (*mapv1.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.offeredTask(taskID, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "rejected"
+	}
+	return r.rejectOffer(record, reason)
+}
+
+// withdrawOffer rejects a task on behalf of agentID if the task is still
+// offered to it, e.g. when the offer times out
+func (r *TaskRouter) withdrawOffer(taskID, agentID, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, err := r.store.GetTask(taskID)
+	if err != nil || record == nil || record.Status != "offered" || record.AssignedTo != agentID {
+		return
+	}
+	if _, err := r.rejectOffer(record, reason); err != nil {
+		log.Printf("task %s: failed to withdraw offer to agent %s: %v", taskID, agentID, err)
+	}
+}
+
+// offeredTask returns a task that is waiting for agentID to answer an offer
+func (r *TaskRouter) offeredTask(taskID, agentID string) (*TaskRecord, error) {
+	if agentID == "" {
+		return nil, invalidArgumentf("agent_id is required")
+	}
+	record, err := r.store.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, notFoundf("task not found: %s", taskID)
+	}
+	if record.Status != "offered" {
+		return nil, failedPreconditionf("task %s is %s, not offered", taskID, record.Status)
+	}
+	if record.AssignedTo != agentID {
+		return nil, failedPreconditionf("task %s is offered to agent %s, not %s", taskID, record.AssignedTo, agentID)
+	}
+	return record, nil
+}
+
+// rejectOffer returns an offered task to pending, recording its agent as
+// one that declined it, and frees the agent. Freeing the agent re-runs
+// routing, which offers the task to someone else. Callers hold r.mu.
+func (r *TaskRouter) rejectOffer(record *TaskRecord, reason string) (*mapv1.Task, error) {
+	agentID := record.AssignedTo
+	rejectedBy := record.RejectedBy
+	if !slices.Contains(rejectedBy, agentID) {
+		rejectedBy = append(slices.Clone(rejectedBy), agentID)
+	}
+	if err := r.store.WithdrawOffer(record.TaskID, rejectedBy); err != nil {
+		return nil, fmt.Errorf("reject task: %w", err)
+	}
+	record.Status = "pending"
+	record.AssignedTo = ""
+	record.RejectedBy = rejectedBy
+	record.UpdatedAt = time.Now()
+	log.Printf("task %s rejected by agent %s: %s", record.TaskID, agentID, reason)
+
+	task := taskRecordToProto(record)
+	r.emitTaskEvent(mapv1.EventType_EVENT_TYPE_TASK_REJECTED, task, agentID)
+	if r.spawned != nil {
+		r.spawned.ReleaseReservation(agentID, record.TaskID)
+	}
+	return task, nil
+}
+
+// ReserveAgent marks an idle agent busy with taskID without sending it
+// anything, so it isn't given other work while it considers an offer
+func (m *ProcessManager) ReserveAgent(agentID, taskID string) error {
+	slot := m.Get(agentID)
+	if slot == nil {
+		return notFoundf("agent %s not found", agentID)
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if slot.Status == AgentStatusBusy {
+		return failedPreconditionf("agent %s is busy", agentID)
+	}
+	slot.Status = AgentStatusBusy
+	slot.CurrentTask = taskID
+	return nil
+}
+
+// ReleaseReservation frees an agent reserved for taskID. Agents that have
+// moved on to other work are left alone.
+func (m *ProcessManager) ReleaseReservation(agentID, taskID string) {
+	slot := m.Get(agentID)
+	if slot == nil {
+		return
+	}
+
+	slot.mu.Lock()
+	reserved := slot.Status == AgentStatusBusy && slot.CurrentTask == taskID
+	slot.mu.Unlock()
+	if reserved {
+		m.releaseSlot(slot)
+	}
+}
+
+// SendOffer types a task offer into an agent's session, asking it to
+// accept or reject the task
+func (m *ProcessManager) SendOffer(ctx context.Context, agentID, taskID, description string) error {
+	slot := m.Get(agentID)
+	if slot == nil {
+		return notFoundf("agent %s not found", agentID)
+	}
+
+	slot.mu.Lock()
+	session, agentType, headless := slot.TmuxSession, slot.AgentType, slot.Headless
+	slot.mu.Unlock()
+	if headless {
+		return failedPreconditionf("agent %s is headless and can't answer offers", agentID)
+	}
+
+	prompt := buildOfferPrompt(taskID, agentID, description)
+	if err := m.sendPrompt(ctx, session, agentType, prompt); err != nil {
+		return fmt.Errorf("send offer: %w", err)
+	}
+	m.recordMessage(agentID, taskID, MessageKindOffer, AgentMessageSent, prompt)
+	return nil
+}
+
+// buildOfferPrompt builds the message offering a task to an agent
+func buildOfferPrompt(taskID, agentID, description string) string {
+	return fmt.Sprintf("[Task offer: %s]\n\n%s\n\nThis task is offered to you. Run `map task accept %s --agent %s` to take it on, or `map task reject %s --agent %s` if you shouldn't work on it.",
+		taskID, description, taskID, agentID, taskID, agentID)
+}
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// setupOfferTest returns a router with two idle agents, agent-a taking
+// offers, and a pending task that prefers agent-a
+func setupOfferTest(t *testing.T) (*TaskRouter, *Store) {
+	t.Helper()
+	router, store, cleanup := setupTestTaskRouter(t)
+	t.Cleanup(cleanup)
+
+	router.spawned = NewProcessManager(t.TempDir(), nil)
+	router.spawned.agents["agent-a"] = &AgentSlot{
+		AgentID:     "agent-a",
+		TmuxSession: "map-agent-offer-test-missing",
+		Status:      AgentStatusIdle,
+		OfferTasks:  true,
+	}
+	router.spawned.agents["agent-b"] = &AgentSlot{
+		AgentID:     "agent-b",
+		TmuxSession: "map-agent-offer-test-missing-b",
+		Status:      AgentStatusIdle,
+	}
+
+	now := time.Now()
+	record := &TaskRecord{TaskID: "task-1", Description: "refactor", Status: "pending", PreferAgent: "agent-a", CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateTask(record); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	return router, store
+}
+
+func TestTaskRouter_RejectTask(t *testing.T) {
+	router, store := setupOfferTest(t)
+
+	if err := router.spawned.ReserveAgent("agent-a", "task-1"); err != nil {
+		t.Fatalf("ReserveAgent failed: %v", err)
+	}
+	if err := store.OfferTask("task-1", "agent-a"); err != nil {
+		t.Fatalf("OfferTask failed: %v", err)
+	}
+
+	// Only the agent the task is offered to can answer the offer
+	if _, err := router.RejectTask("task-1", "agent-b", "not my area"); err == nil {
+		t.Error("RejectTask by agent-b succeeded, want error for a task offered to agent-a")
+	}
+	if _, err := router.AcceptTask("task-1", "agent-b"); err == nil {
+		t.Error("AcceptTask by agent-b succeeded, want error for a task offered to agent-a")
+	}
+
+	task, err := router.RejectTask("task-1", "agent-a", "not my area")
+	if err != nil {
+		t.Fatalf("RejectTask failed: %v", err)
+	}
+	if task.Status != mapv1.TaskStatus_TASK_STATUS_PENDING || task.AssignedTo != "" {
+		t.Errorf("rejected task status=%v assigned=%q, want pending and unassigned", task.Status, task.AssignedTo)
+	}
+
+	record, _ := store.GetTask("task-1")
+	if record.Status != "pending" || !slices.Equal(record.RejectedBy, []string{"agent-a"}) {
+		t.Errorf("stored task status=%s rejected_by=%v, want pending rejected by agent-a", record.Status, record.RejectedBy)
+	}
+	if slot := router.spawned.Get("agent-a"); slot.Status != AgentStatusIdle || slot.CurrentTask != "" {
+		t.Errorf("agent-a status=%s task=%q, want idle", slot.Status, slot.CurrentTask)
+	}
+
+	// The task skips the agent that rejected it, even though it prefers it
+	if slot := router.spawned.FindAvailableAgent(task); slot == nil || slot.AgentID != "agent-b" {
+		t.Errorf("FindAvailableAgent = %v, want agent-b", slot)
+	}
+
+	// The task is no longer offered, so it can't be rejected or accepted
+	if _, err := router.RejectTask("task-1", "agent-a", ""); err == nil {
+		t.Error("RejectTask of a pending task succeeded, want error")
+	}
+	if _, err := router.AcceptTask("task-1", "agent-a"); err == nil {
+		t.Error("AcceptTask of a pending task succeeded, want error")
+	}
+}
+
+func TestTaskRouter_AcceptTask_AgentGone(t *testing.T) {
+	router, store := setupOfferTest(t)
+
+	if err := store.OfferTask("task-1", "agent-gone"); err != nil {
+		t.Fatalf("OfferTask failed: %v", err)
+	}
+	if _, err := router.AcceptTask("task-1", "agent-gone"); err == nil {
+		t.Error("AcceptTask for a missing agent succeeded, want error")
+	}
+	if _, err := router.AcceptTask("missing", "agent-a"); err == nil {
+		t.Error("AcceptTask of a missing task succeeded, want error")
+	}
+}
+
+func TestTaskRouter_OfferUndelivered(t *testing.T) {
+	router, store := setupOfferTest(t)
+
+	task, _ := store.GetTask("task-1")
+	router.executeOnSpawnedAgent(taskRecordToProto(task), router.spawned.Get("agent-a"))
+
+	event := <-router.eventCh
+	if event.Type != mapv1.EventType_EVENT_TYPE_TASK_OFFERED || event.GetTask().GetAgentId() != "agent-a" {
+		t.Fatalf("first event = %v, want task offered to agent-a", event)
+	}
+
+	// agent-a has no session, so the offer can't be delivered and counts
+	// as a rejection
+	select {
+	case event = <-router.eventCh:
+		if event.Type != mapv1.EventType_EVENT_TYPE_TASK_REJECTED {
+			t.Fatalf("second event = %v, want task rejected", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("undelivered offer was not withdrawn")
+	}
+
+	record, _ := store.GetTask("task-1")
+	if record.Status != "pending" || !slices.Equal(record.RejectedBy, []string{"agent-a"}) {
+		t.Errorf("task status=%s rejected_by=%v, want pending rejected by agent-a", record.Status, record.RejectedBy)
+	}
+}
+
+func TestStore_RequeueOfferedTasks(t *testing.T) {
+	_, store := setupOfferTest(t)
+
+	if err := store.OfferTask("task-1", "agent-a"); err != nil {
+		t.Fatalf("OfferTask failed: %v", err)
+	}
+	n, err := store.RequeueOfferedTasks()
+	if err != nil || n != 1 {
+		t.Fatalf("RequeueOfferedTasks = %d, %v; want 1", n, err)
+	}
+
+	record, _ := store.GetTask("task-1")
+	if record.Status != "pending" || record.AssignedTo != "" {
+		t.Errorf("task status=%s assigned=%q, want pending and unassigned", record.Status, record.AssignedTo)
+	}
+}
@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// scheduleCheckInterval is how often recurring tasks are checked for a due
+// run. Cron schedules have minute resolution.
+const scheduleCheckInterval = 15 * time.Second
+
+// TaskScheduler submits a task each time a recurring task's cron schedule
+// fires. Schedules are evaluated in the daemon's local time zone. Runs
+// missed while the daemon was down are not replayed: a schedule that came
+// due in the meantime fires once when the daemon starts, then carries on
+// from its next time.
+type TaskScheduler struct {
+	store    *Store
+	tasks    *TaskRouter
+	stop     chan struct{}
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewTaskScheduler creates a scheduler that submits tasks through tasks
+func NewTaskScheduler(store *Store, tasks *TaskRouter) *TaskScheduler {
+	return &TaskScheduler{
+		store:    store,
+		tasks:    tasks,
+		stop:     make(chan struct{}),
+		interval: scheduleCheckInterval,
+		now:      time.Now,
+	}
+}
+
+// Start begins checking for due schedules
+func (s *TaskScheduler) Start() {
+	go s.loop()
+}
+
+// Stop stops the check loop
+func (s *TaskScheduler) Stop() {
+	close(s.stop)
+}
+
+func (s *TaskScheduler) loop() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.runDue()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.runDue()
+		}
+	}
+}
+
+// Create validates and stores a recurring task, returning it with its first
+// run time filled in
+func (s *TaskScheduler) Create(req *mapv1.CreateScheduledTaskRequest) (*ScheduledTaskRecord, error) {
+	cron, err := ParseCron(req.GetCron())
+	if err != nil {
+		return nil, invalidArgumentf("%v", err)
+	}
+
+	now := s.now()
+	next := cron.Next(now)
+	if next.IsZero() {
+		return nil, invalidArgumentf("cron expression %q never fires", req.GetCron())
+	}
+
+	description := strings.TrimSpace(req.GetDescription())
+	if description == "" {
+		return nil, invalidArgumentf("description is required")
+	}
+	s.tasks.mu.Lock()
+	description, err = s.tasks.validateDescription(description)
+	s.tasks.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	sched := &ScheduledTaskRecord{
+		ScheduleID:  "sched-" + uuid.New().String()[:8],
+		Cron:        strings.TrimSpace(req.GetCron()),
+		Description: description,
+		ScopePaths:  req.GetScopePaths(),
+		RepoRoot:    req.GetRepoRoot(),
+		CreatedAt:   now,
+		NextRunAt:   next,
+	}
+	if err := s.store.CreateScheduledTask(sched); err != nil {
+		return nil, fmt.Errorf("create schedule: %w", err)
+	}
+	log.Printf("schedule %s created (%s), next run %s", sched.ScheduleID, sched.Cron, next.Format(time.RFC3339))
+	return sched, nil
+}
+
+// runDue submits a task for every schedule whose next run has come
+func (s *TaskScheduler) runDue() {
+	schedules, err := s.store.ListScheduledTasks()
+	if err != nil {
+		log.Printf("list schedules: %v", err)
+		return
+	}
+
+	now := s.now()
+	for _, sched := range schedules {
+		if sched.NextRunAt.IsZero() || sched.NextRunAt.After(now) {
+			continue
+		}
+		s.fire(sched, now)
+	}
+}
+
+// fire submits one run of a schedule and moves it to its next time. The
+// next time is set even if the submission fails, so a bad schedule can't
+// retry every check.
+func (s *TaskScheduler) fire(sched *ScheduledTaskRecord, now time.Time) {
+	var next time.Time
+	if cron, err := ParseCron(sched.Cron); err != nil {
+		log.Printf("schedule %s: %v; it will not run again", sched.ScheduleID, err)
+	} else {
+		next = cron.Next(now)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var taskID string
+	task, err := s.tasks.SubmitTask(ctx, &mapv1.SubmitTaskRequest{
+		Description: sched.Description,
+		ScopePaths:  sched.ScopePaths,
+		RepoRoot:    sched.RepoRoot,
+		RunId:       sched.ScheduleID,
+	})
+	if err != nil {
+		log.Printf("schedule %s: submit task: %v", sched.ScheduleID, err)
+	} else {
+		taskID = task.TaskId
+		log.Printf("schedule %s submitted task %s", sched.ScheduleID, taskID)
+	}
+
+	if err := s.store.RecordScheduledRun(sched.ScheduleID, taskID, now, next); err != nil {
+		log.Printf("schedule %s: record run: %v", sched.ScheduleID, err)
+	}
+}
+
+func scheduledTaskToProto(sched *ScheduledTaskRecord) *mapv1.ScheduledTask {
+	pb := &mapv1.ScheduledTask{
+		ScheduleId:  sched.ScheduleID,
+		Cron:        sched.Cron,
+		Description: sched.Description,
+		ScopePaths:  sched.ScopePaths,
+		RepoRoot:    sched.RepoRoot,
+		CreatedAt:   timestamppb.New(sched.CreatedAt),
+		LastTaskId:  sched.LastTaskID,
+	}
+	if !sched.NextRunAt.IsZero() {
+		pb.NextRunAt = timestamppb.New(sched.NextRunAt)
+	}
+	if !sched.LastRunAt.IsZero() {
+		pb.LastRunAt = timestamppb.New(sched.LastRunAt)
+	}
+	return pb
+}
@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestTaskScheduler_Create(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.Local)
+	scheduler := NewTaskScheduler(store, router)
+	scheduler.now = func() time.Time { return now }
+
+	if _, err := scheduler.Create(&mapv1.CreateScheduledTaskRequest{Cron: "0 0 30 2 *", Description: "never"}); err == nil {
+		t.Error("expected error for a schedule that never fires")
+	}
+	if _, err := scheduler.Create(&mapv1.CreateScheduledTaskRequest{Cron: "bogus", Description: "bad"}); err == nil {
+		t.Error("expected error for an invalid cron expression")
+	}
+	if _, err := scheduler.Create(&mapv1.CreateScheduledTaskRequest{Cron: "@daily", Description: "  "}); err == nil {
+		t.Error("expected error for an empty description")
+	}
+
+	sched, err := scheduler.Create(&mapv1.CreateScheduledTaskRequest{
+		Cron:        "0 9 * * *",
+		Description: "update dependencies",
+		ScopePaths:  []string{"/repo/go.mod"},
+		RepoRoot:    "/repo",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if want := time.Date(2025, time.January, 16, 9, 0, 0, 0, time.Local); !sched.NextRunAt.Equal(want) {
+		t.Errorf("NextRunAt = %v, want %v", sched.NextRunAt, want)
+	}
+
+	schedules, err := store.ListScheduledTasks()
+	if err != nil {
+		t.Fatalf("ListScheduledTasks: %v", err)
+	}
+	if len(schedules) != 1 {
+		t.Fatalf("expected 1 schedule, got %d", len(schedules))
+	}
+	got := schedules[0]
+	if got.ScheduleID != sched.ScheduleID || got.Cron != "0 9 * * *" || got.RepoRoot != "/repo" {
+		t.Errorf("unexpected stored schedule: %+v", got)
+	}
+	if len(got.ScopePaths) != 1 || got.ScopePaths[0] != "/repo/go.mod" {
+		t.Errorf("ScopePaths = %v", got.ScopePaths)
+	}
+}
+
+func TestTaskScheduler_RunDue(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Date(2025, time.January, 15, 8, 59, 0, 0, time.Local)
+	scheduler := NewTaskScheduler(store, router)
+	scheduler.now = func() time.Time { return now }
+
+	sched, err := scheduler.Create(&mapv1.CreateScheduledTaskRequest{Cron: "0 9 * * *", Description: "daily report"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Not due yet
+	scheduler.runDue()
+	if tasks, _ := store.ListTasks("", "", "", 0, false); len(tasks) != 0 {
+		t.Fatalf("expected no tasks before the schedule fires, got %d", len(tasks))
+	}
+
+	// Due: one task is submitted and the schedule moves to tomorrow
+	now = time.Date(2025, time.January, 15, 9, 0, 10, 0, time.Local)
+	scheduler.runDue()
+
+	tasks, err := store.ListTasks("", "", "", 0, false)
+	if err != nil {
+		t.Fatalf("ListTasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	if tasks[0].Description != "daily report" || tasks[0].RunID != sched.ScheduleID {
+		t.Errorf("unexpected task: description %q, run %q", tasks[0].Description, tasks[0].RunID)
+	}
+
+	schedules, err := store.ListScheduledTasks()
+	if err != nil {
+		t.Fatalf("ListScheduledTasks: %v", err)
+	}
+	got := schedules[0]
+	if got.LastTaskID != tasks[0].TaskID {
+		t.Errorf("LastTaskID = %q, want %q", got.LastTaskID, tasks[0].TaskID)
+	}
+	if want := time.Date(2025, time.January, 16, 9, 0, 0, 0, time.Local); !got.NextRunAt.Equal(want) {
+		t.Errorf("NextRunAt = %v, want %v", got.NextRunAt, want)
+	}
+
+	// Checking again in the same minute doesn't fire twice
+	scheduler.runDue()
+	if tasks, _ := store.ListTasks("", "", "", 0, false); len(tasks) != 1 {
+		t.Errorf("expected 1 task after re-check, got %d", len(tasks))
+	}
+
+	// Missed runs fire once, then resume from now
+	now = time.Date(2025, time.January, 20, 12, 0, 0, 0, time.Local)
+	scheduler.runDue()
+	if tasks, _ := store.ListTasks("", "", "", 0, false); len(tasks) != 2 {
+		t.Errorf("expected 2 tasks after catching up, got %d", len(tasks))
+	}
+	schedules, _ = store.ListScheduledTasks()
+	if want := time.Date(2025, time.January, 21, 9, 0, 0, 0, time.Local); !schedules[0].NextRunAt.Equal(want) {
+		t.Errorf("NextRunAt after catch-up = %v, want %v", schedules[0].NextRunAt, want)
+	}
+
+	deleted, err := store.DeleteScheduledTask(sched.ScheduleID)
+	if err != nil || !deleted {
+		t.Fatalf("DeleteScheduledTask = %v, %v", deleted, err)
+	}
+	if deleted, _ := store.DeleteScheduledTask(sched.ScheduleID); deleted {
+		t.Error("expected second delete to report not found")
+	}
+}
@@ -2,7 +2,9 @@ package daemon
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -86,6 +88,83 @@ func TestTaskRouter_SubmitTask(t *testing.T) {
 	}
 }
 
+func TestTaskRouter_SubmitTask_AutoSpawn(t *testing.T) {
+	router, _, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	spawned := make(chan string, 1)
+	router.SetAutoSpawn(func(repoRoot string) bool {
+		spawned <- repoRoot
+		return true
+	})
+
+	_, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: "Needs an agent",
+		RepoRoot:    "/repo",
+	})
+	if err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+
+	select {
+	case repoRoot := <-spawned:
+		if repoRoot != "/repo" {
+			t.Errorf("auto-spawn repoRoot = %q, want %q", repoRoot, "/repo")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("auto-spawn was not called when no agent was available")
+	}
+}
+
+func TestTaskRouter_Paused(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	spawned := make(chan string, 1)
+	router.SetAutoSpawn(func(repoRoot string) bool {
+		spawned <- repoRoot
+		return true
+	})
+
+	if !router.SetPaused(true) {
+		t.Fatal("SetPaused(true) reported no change")
+	}
+	if router.SetPaused(true) {
+		t.Error("pausing twice reported a change")
+	}
+	if !router.Paused() {
+		t.Error("Paused() = false after pausing")
+	}
+
+	task, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{Description: "Wait for resume"})
+	if err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+
+	select {
+	case <-spawned:
+		t.Fatal("a paused router tried to route a task")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if stored, _ := store.GetTask(task.TaskId); stored == nil || stored.Status != "pending" {
+		t.Errorf("task should stay pending while paused, got %+v", stored)
+	}
+
+	var messages []string
+	for len(router.eventCh) > 0 {
+		if status := (<-router.eventCh).GetStatus(); status != nil {
+			messages = append(messages, status.Message)
+		}
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "paused") {
+		t.Errorf("status events = %v, want one pause event", messages)
+	}
+
+	if !router.SetPaused(false) || router.Paused() {
+		t.Error("SetPaused(false) should resume the router")
+	}
+}
+
 func TestTaskRouter_GetTask(t *testing.T) {
 	router, store, cleanup := setupTestTaskRouter(t)
 	defer cleanup()
@@ -140,7 +219,7 @@ func TestTaskRouter_ListTasks(t *testing.T) {
 	}
 
 	// List all
-	all, err := router.ListTasks("", "", "", 0)
+	all, err := router.ListTasks("", "", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -149,7 +228,7 @@ func TestTaskRouter_ListTasks(t *testing.T) {
 	}
 
 	// Filter by status
-	pending, err := router.ListTasks("pending", "", "", 0)
+	pending, err := router.ListTasks("pending", "", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -158,7 +237,7 @@ func TestTaskRouter_ListTasks(t *testing.T) {
 	}
 
 	// Filter by agent
-	agentTasks, err := router.ListTasks("", "agent-1", "", 0)
+	agentTasks, err := router.ListTasks("", "agent-1", "", 0, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -167,7 +246,7 @@ func TestTaskRouter_ListTasks(t *testing.T) {
 	}
 
 	// With limit
-	limited, err := router.ListTasks("", "", "", 2)
+	limited, err := router.ListTasks("", "", "", 2, false)
 	if err != nil {
 		t.Fatalf("ListTasks failed: %v", err)
 	}
@@ -234,6 +313,260 @@ func TestTaskRouter_CancelTask_NotFound(t *testing.T) {
 	}
 }
 
+func TestTaskRouter_NudgeTask_Guards(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.spawned = NewProcessManager("/tmp/logs", nil)
+
+	now := time.Now()
+	records := []*TaskRecord{
+		{TaskID: "pending", Status: "pending", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "completed", Status: "completed", AssignedTo: "agent-a", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "unassigned", Status: "in_progress", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "gone-agent", Status: "in_progress", AssignedTo: "agent-a", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, r := range records {
+		if err := store.CreateTask(r); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	for _, id := range []string{"pending", "completed", "unassigned", "gone-agent", "missing"} {
+		if _, err := router.NudgeTask(context.Background(), id); err == nil {
+			t.Errorf("NudgeTask(%q) succeeded, want error", id)
+		}
+	}
+
+	// A failed nudge leaves the task as it was
+	record, _ := store.GetTask("gone-agent")
+	if record.Status != "in_progress" || record.AssignedTo != "agent-a" {
+		t.Errorf("task changed by nudge: status=%s assigned=%s", record.Status, record.AssignedTo)
+	}
+}
+
+func TestTaskRouter_RetryTask(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Now()
+	testCases := []struct {
+		name        string
+		status      string
+		expectError bool
+	}{
+		{"failed task", "failed", false},
+		{"cancelled task", "cancelled", false},
+		{"pending task", "pending", true},
+		{"completed task", "completed", true},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			taskID := fmt.Sprintf("retry-%d", i)
+			record := &TaskRecord{
+				TaskID:     taskID,
+				Status:     tc.status,
+				AssignedTo: "claude-swift-fox",
+				Error:      "boom",
+				CreatedAt:  now,
+				UpdatedAt:  now,
+			}
+			if err := store.CreateTask(record); err != nil {
+				t.Fatalf("CreateTask failed: %v", err)
+			}
+
+			task, err := router.RetryTask(taskID)
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RetryTask failed: %v", err)
+			}
+			if task.Status != mapv1.TaskStatus_TASK_STATUS_PENDING {
+				t.Errorf("Status = %v, want pending", task.Status)
+			}
+			if task.RetryCount != 1 {
+				t.Errorf("RetryCount = %d, want 1", task.RetryCount)
+			}
+
+			stored, _ := store.GetTask(taskID)
+			if stored.AssignedTo != "" || stored.Error != "" {
+				t.Errorf("retry should clear assignment and error, got %q / %q", stored.AssignedTo, stored.Error)
+			}
+			if stored.PreferAgent != "claude-swift-fox" || task.PreferAgent != "claude-swift-fox" {
+				t.Errorf("retry should prefer the previous assignee, got %q", stored.PreferAgent)
+			}
+		})
+	}
+}
+
+func TestTaskRouter_RetryTask_RespectsCap(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.SetMaxRetries(2)
+
+	now := time.Now()
+	if err := store.CreateTask(&TaskRecord{TaskID: "capped", Status: "failed", CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+
+	for i := 1; i <= 2; i++ {
+		task, err := router.RetryTask("capped")
+		if err != nil {
+			t.Fatalf("retry %d failed: %v", i, err)
+		}
+		if task.RetryCount != int32(i) {
+			t.Errorf("RetryCount = %d, want %d", task.RetryCount, i)
+		}
+		_ = store.UpdateTaskStatus("capped", "failed")
+	}
+
+	if _, err := router.RetryTask("capped"); err == nil {
+		t.Error("expected error once the retry limit is reached")
+	}
+}
+
+func TestTaskRouter_ReopenTask(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, rec := range []*TaskRecord{
+		{TaskID: "done-gone", Status: "completed", AssignedTo: "agent-x", Result: "ok", CompletedAt: now},
+		{TaskID: "done-live", Status: "completed", AssignedTo: "agent-a", Result: "ok", CompletedAt: now},
+		{TaskID: "cancelled", Status: "cancelled"},
+		{TaskID: "pending", Status: "pending"},
+	} {
+		rec.CreatedAt, rec.UpdatedAt = now, now
+		if err := store.CreateTask(rec); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	for _, id := range []string{"cancelled", "pending", "missing"} {
+		if _, err := router.ReopenTask(id); err == nil {
+			t.Errorf("ReopenTask(%s): expected error", id)
+		}
+	}
+
+	// Without its agent the task goes back to the queue, preferring it
+	task, err := router.ReopenTask("done-gone")
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	if task.Status != mapv1.TaskStatus_TASK_STATUS_PENDING || task.PreferAgent != "agent-x" {
+		t.Errorf("got status %v prefer %q, want pending preferring agent-x", task.Status, task.PreferAgent)
+	}
+	stored, _ := store.GetTask("done-gone")
+	if !stored.CompletedAt.IsZero() || stored.Result != "" || stored.RetryCount != 1 {
+		t.Errorf("reopen should clear completion and start a new attempt, got %+v", stored)
+	}
+
+	// With its agent still running the task resumes there
+	router.spawned = newSchedulingTestManager(t, SchedulingRoundRobin)
+	task, err = router.ReopenTask("done-live")
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	if task.Status != mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS || task.AssignedTo != "agent-a" {
+		t.Errorf("got status %v on %q, want in_progress on agent-a", task.Status, task.AssignedTo)
+	}
+	stored, _ = store.GetTask("done-live")
+	if !stored.CompletedAt.IsZero() || stored.StartedAt.IsZero() {
+		t.Errorf("reopened task should be started and not completed, got started %v completed %v", stored.StartedAt, stored.CompletedAt)
+	}
+	if env := decodeEnvironment(stored.Environment); env == nil || env.AgentId != "agent-a" {
+		t.Errorf("reopened task environment = %v, want a snapshot of agent-a", env)
+	}
+
+	// The completed attempt is kept and the resumed work is a new one
+	attempts, err := router.ListTaskAttempts("done-live")
+	if err != nil {
+		t.Fatalf("ListTaskAttempts failed: %v", err)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("got %d attempts, want the completed one and the reopened one", len(attempts))
+	}
+	if attempts[0].Status != mapv1.TaskStatus_TASK_STATUS_COMPLETED || attempts[0].FinishedAt == nil {
+		t.Errorf("first attempt = %v, want it finished as completed", attempts[0])
+	}
+	if a := attempts[1]; a.Attempt != 2 || a.AgentId != "agent-a" || a.Status != mapv1.TaskStatus_TASK_STATUS_IN_PROGRESS || a.FinishedAt != nil {
+		t.Errorf("second attempt = %v, want attempt 2 in progress on agent-a", a)
+	}
+}
+
+func TestTaskRouter_ReopenTask_AgentBusy(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.spawned = newSchedulingTestManager(t, SchedulingRoundRobin)
+
+	now := time.Now()
+	for _, rec := range []*TaskRecord{
+		{TaskID: "done", Status: "completed", AssignedTo: "agent-a", Result: "ok", CompletedAt: now},
+		{TaskID: "current", Status: "in_progress", AssignedTo: "agent-a"},
+	} {
+		rec.CreatedAt, rec.UpdatedAt = now, now
+		if err := store.CreateTask(rec); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	// agent-a is running but on another task, so the reopened task waits
+	// in the queue for it rather than joining that task
+	task, err := router.ReopenTask("done")
+	if err != nil {
+		t.Fatalf("ReopenTask failed: %v", err)
+	}
+	if task.Status != mapv1.TaskStatus_TASK_STATUS_PENDING || task.PreferAgent != "agent-a" {
+		t.Errorf("got status %v prefer %q, want pending preferring agent-a", task.Status, task.PreferAgent)
+	}
+}
+
+func TestTaskRouter_RetryFailedTasks(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.SetMaxRetries(1)
+
+	now := time.Now()
+	tasks := []*TaskRecord{
+		{TaskID: "old-failure", Status: "failed", CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)},
+		{TaskID: "recent-failure", Status: "failed", CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-10 * time.Minute)},
+		{TaskID: "exhausted", Status: "failed", RetryCount: 1, CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(-5 * time.Minute)},
+		{TaskID: "other-repo", Status: "failed", RepoRoot: "/other", CreatedAt: now, UpdatedAt: now},
+		{TaskID: "done", Status: "completed", CreatedAt: now, UpdatedAt: now},
+	}
+	for _, rec := range tasks {
+		if rec.TaskID != "other-repo" {
+			rec.RepoRoot = "/repo"
+		}
+		if err := store.CreateTask(rec); err != nil {
+			t.Fatalf("CreateTask failed: %v", err)
+		}
+	}
+
+	retried, skipped, err := router.RetryFailedTasks("/repo", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("RetryFailedTasks failed: %v", err)
+	}
+
+	if len(retried) != 1 || retried[0].TaskId != "recent-failure" {
+		t.Errorf("retried = %v, want [recent-failure]", retried)
+	}
+	if len(skipped) != 1 || skipped[0].TaskId != "exhausted" {
+		t.Errorf("skipped = %v, want [exhausted]", skipped)
+	}
+
+	for id, want := range map[string]string{"old-failure": "failed", "recent-failure": "pending", "exhausted": "failed", "other-repo": "failed"} {
+		rec, _ := store.GetTask(id)
+		if rec.Status != want {
+			t.Errorf("task %s status = %q, want %q", id, rec.Status, want)
+		}
+	}
+}
+
 func Test_taskStatusFromString(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -298,3 +631,179 @@ func Test_taskRecordToProto(t *testing.T) {
 		t.Errorf("Error = %q, want %q", proto.Error, "some error")
 	}
 }
+
+func Test_medianDuration(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []time.Duration
+		want  time.Duration
+	}{
+		{"empty", nil, 0},
+		{"single", []time.Duration{5 * time.Minute}, 5 * time.Minute},
+		{"odd", []time.Duration{9 * time.Minute, time.Minute, 3 * time.Minute}, 3 * time.Minute},
+		{"even", []time.Duration{4 * time.Minute, time.Minute, 2 * time.Minute, 10 * time.Minute}, 3 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := medianDuration(tt.input); got != tt.want {
+				t.Errorf("medianDuration(%v) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_estimateTaskDuration(t *testing.T) {
+	base := time.Now()
+	done := func(id string, d time.Duration, paths ...string) *TaskRecord {
+		return &TaskRecord{
+			TaskID:      id,
+			ScopePaths:  paths,
+			StartedAt:   base,
+			CompletedAt: base.Add(d),
+		}
+	}
+
+	history := []*TaskRecord{
+		done("a", 10*time.Minute, "/repo/internal/cli"),
+		done("b", 20*time.Minute, "/repo/internal/cli/task.go"),
+		done("c", 60*time.Minute, "/repo/docs"),
+		done("d", 90*time.Minute),
+	}
+
+	// Overlapping scopes are preferred
+	est, n := estimateTaskDuration(history, &TaskRecord{TaskID: "new", ScopePaths: []string{"/repo/internal/cli"}})
+	if n != 2 || est != 15*time.Minute {
+		t.Errorf("scoped estimate = %v over %d tasks, want 15m0s over 2", est, n)
+	}
+
+	// Without overlap, fall back to all completed tasks
+	est, n = estimateTaskDuration(history, &TaskRecord{TaskID: "new"})
+	if n != 4 || est != 40*time.Minute {
+		t.Errorf("fallback estimate = %v over %d tasks, want 40m0s over 4", est, n)
+	}
+
+	// The task itself is never part of its own estimate
+	est, n = estimateTaskDuration(history, &TaskRecord{TaskID: "c", ScopePaths: []string{"/repo/docs"}})
+	if n != 3 || est != 20*time.Minute {
+		t.Errorf("self-excluded estimate = %v over %d tasks, want 20m0s over 3", est, n)
+	}
+
+	// No history yields no estimate
+	est, n = estimateTaskDuration(nil, &TaskRecord{TaskID: "new"})
+	if n != 0 || est != 0 {
+		t.Errorf("empty estimate = %v over %d tasks, want 0 over 0", est, n)
+	}
+}
+
+func TestTaskRouter_SubmitTask_DescriptionLimit(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+	router.SetMaxDescriptionBytes(16)
+
+	// Trailing whitespace is trimmed before the limit is checked
+	task, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: "fix the tests\n\n   \t",
+	})
+	if err != nil {
+		t.Fatalf("SubmitTask failed: %v", err)
+	}
+	if task.Description != "fix the tests" {
+		t.Errorf("Description = %q, want trailing whitespace trimmed", task.Description)
+	}
+	stored, err := store.GetTask(task.TaskId)
+	if err != nil || stored == nil {
+		t.Fatalf("GetTask: %v, %v", stored, err)
+	}
+	if stored.Description != "fix the tests" {
+		t.Errorf("stored Description = %q, want trailing whitespace trimmed", stored.Description)
+	}
+
+	_, err = router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: strings.Repeat("x", 17),
+	})
+	if err == nil || !strings.Contains(err.Error(), "16 byte limit") {
+		t.Errorf("SubmitTask over the limit: err = %v, want a limit error", err)
+	}
+
+	tasks, err := store.ListTasks("", "", "", 0, false)
+	if err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("store has %d tasks, want only the accepted one", len(tasks))
+	}
+
+	// Zero removes the limit
+	router.SetMaxDescriptionBytes(0)
+	if _, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{
+		Description: strings.Repeat("x", 1000),
+	}); err != nil {
+		t.Errorf("SubmitTask with no limit failed: %v", err)
+	}
+}
+
+func TestTaskRouter_ReleaseAgentTasks(t *testing.T) {
+	router, store, cleanup := setupTestTaskRouter(t)
+	defer cleanup()
+
+	submit := func(desc, agentID, status string) string {
+		t.Helper()
+		task, err := router.SubmitTask(context.Background(), &mapv1.SubmitTaskRequest{Description: desc})
+		if err != nil {
+			t.Fatalf("SubmitTask failed: %v", err)
+		}
+		if err := store.AssignTask(task.TaskId, agentID); err != nil {
+			t.Fatalf("AssignTask failed: %v", err)
+		}
+		if err := store.UpdateTaskStatus(task.TaskId, status); err != nil {
+			t.Fatalf("UpdateTaskStatus failed: %v", err)
+		}
+		return task.TaskId
+	}
+
+	active := submit("active", "claude-a", "in_progress")
+	waiting := submit("waiting", "claude-a", "waiting_input")
+	done := submit("done", "claude-a", "completed")
+	other := submit("other agent", "claude-b", "in_progress")
+
+	released, err := router.ReleaseAgentTasks("claude-a", false)
+	if err != nil {
+		t.Fatalf("ReleaseAgentTasks failed: %v", err)
+	}
+	if len(released) != 2 {
+		t.Fatalf("released %d tasks, want 2", len(released))
+	}
+
+	wantStatus := map[string]string{
+		active:  "cancelled",
+		waiting: "cancelled",
+		done:    "completed",
+		other:   "in_progress",
+	}
+	for id, want := range wantStatus {
+		task, err := store.GetTask(id)
+		if err != nil || task == nil {
+			t.Fatalf("GetTask(%s): %v, %v", id, task, err)
+		}
+		if task.Status != want {
+			t.Errorf("task %q status = %s, want %s", task.Description, task.Status, want)
+		}
+	}
+
+	// With requeue, the tasks go back to pending and lose their agent
+	released, err = router.ReleaseAgentTasks("claude-b", true)
+	if err != nil {
+		t.Fatalf("ReleaseAgentTasks(requeue) failed: %v", err)
+	}
+	if len(released) != 1 {
+		t.Fatalf("requeued %d tasks, want 1", len(released))
+	}
+	task, err := store.GetTask(other)
+	if err != nil || task == nil {
+		t.Fatalf("GetTask: %v, %v", task, err)
+	}
+	if task.Status != "pending" || task.AssignedTo != "" {
+		t.Errorf("requeued task status = %s, assigned to %q; want pending and unassigned", task.Status, task.AssignedTo)
+	}
+}
@@ -0,0 +1,153 @@
+package daemon
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WarmPool keeps a minimum number of idle agents alive for a single repo so
+// submitted tasks are picked up without waiting for an agent to spawn.
+// Agents it spawns are tracked so that only pool-owned agents are reaped
+// when more than the minimum sit idle.
+type WarmPool struct {
+	processes *ProcessManager
+	store     *Store // tells working agents from idle ones (nil = slot status only)
+	size      int
+	repoRoot  string
+
+	// spawn starts one idle agent in repoRoot and returns its ID
+	spawn func(repoRoot string) (string, error)
+	// kill removes an agent
+	kill func(agentID string)
+
+	mu       sync.Mutex
+	owned    map[string]bool // agent IDs spawned by the pool
+	stop     chan struct{}
+	nudge    chan struct{}
+	interval time.Duration
+}
+
+// NewWarmPool creates a warm pool of the given size scoped to repoRoot
+func NewWarmPool(processes *ProcessManager, store *Store, size int, repoRoot string, spawn func(string) (string, error), kill func(string)) *WarmPool {
+	return &WarmPool{
+		processes: processes,
+		store:     store,
+		size:      size,
+		repoRoot:  repoRoot,
+		spawn:     spawn,
+		kill:      kill,
+		owned:     make(map[string]bool),
+		stop:      make(chan struct{}),
+		nudge:     make(chan struct{}, 1),
+		interval:  5 * time.Second,
+	}
+}
+
+// Start begins the reconcile loop
+func (p *WarmPool) Start() {
+	go p.loop()
+}
+
+// Stop stops the reconcile loop
+func (p *WarmPool) Stop() {
+	close(p.stop)
+}
+
+// Nudge requests a reconcile without waiting for the next tick
+func (p *WarmPool) Nudge() {
+	select {
+	case p.nudge <- struct{}{}:
+	default:
+	}
+}
+
+func (p *WarmPool) loop() {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.reconcile()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.reconcile()
+		case <-p.nudge:
+			p.reconcile()
+		}
+	}
+}
+
+// reconcile spawns agents until the repo has at least size idle agents, and
+// reaps pool-owned idle agents beyond that. Agents with an active task are
+// working even when their slot shows idle, so they neither count toward
+// the pool nor get reaped.
+func (p *WarmPool) reconcile() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var idle []string
+	live := make(map[string]bool)
+	for _, slot := range p.processes.List() {
+		if slot.RepoRoot != p.repoRoot {
+			continue
+		}
+		live[slot.AgentID] = true
+		slot.mu.Lock()
+		available := slot.Status == AgentStatusIdle && !slot.spent()
+		slot.mu.Unlock()
+		if available && (p.store == nil || !p.store.AgentWorking(slot.AgentID)) {
+			idle = append(idle, slot.AgentID)
+		}
+	}
+
+	// Forget pool agents that were killed elsewhere
+	for id := range p.owned {
+		if !live[id] {
+			delete(p.owned, id)
+		}
+	}
+
+	spawnCount, reap := warmPoolPlan(idle, p.owned, p.size)
+
+	for i := 0; i < spawnCount; i++ {
+		agentID, err := p.spawn(p.repoRoot)
+		if err != nil {
+			log.Printf("warm pool: failed to spawn agent: %v", err)
+			return
+		}
+		p.owned[agentID] = true
+		log.Printf("warm pool: spawned %s (%d idle, want %d)", agentID, len(idle)+i+1, p.size)
+	}
+
+	for _, agentID := range reap {
+		p.kill(agentID)
+		delete(p.owned, agentID)
+		log.Printf("warm pool: reaped idle agent %s", agentID)
+	}
+}
+
+// warmPoolPlan decides how many agents to spawn and which idle pool-owned
+// agents to reap so that exactly size agents are idle where possible.
+// Agents the pool did not spawn are never reaped.
+func warmPoolPlan(idle []string, owned map[string]bool, size int) (spawn int, reap []string) {
+	if len(idle) < size {
+		return size - len(idle), nil
+	}
+
+	excess := len(idle) - size
+	sorted := append([]string(nil), idle...)
+	sort.Strings(sorted)
+	for _, id := range sorted {
+		if excess == 0 {
+			break
+		}
+		if owned[id] {
+			reap = append(reap, id)
+			excess--
+		}
+	}
+	return 0, reap
+}
@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_warmPoolPlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		idle      []string
+		owned     map[string]bool
+		size      int
+		wantSpawn int
+		wantReap  []string
+	}{
+		{"empty pool fills", nil, nil, 2, 2, nil},
+		{"partial pool tops up", []string{"a"}, nil, 3, 2, nil},
+		{"exact size is stable", []string{"a", "b"}, map[string]bool{"a": true}, 2, 0, nil},
+		{"excess owned agents reaped", []string{"c", "a", "b"}, map[string]bool{"a": true, "c": true}, 1, 0, []string{"a", "c"}},
+		{"unowned agents never reaped", []string{"a", "b", "c"}, map[string]bool{"b": true}, 1, 0, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spawn, reap := warmPoolPlan(tt.idle, tt.owned, tt.size)
+			if spawn != tt.wantSpawn {
+				t.Errorf("spawn = %d, want %d", spawn, tt.wantSpawn)
+			}
+			if !slices.Equal(reap, tt.wantReap) {
+				t.Errorf("reap = %v, want %v", reap, tt.wantReap)
+			}
+		})
+	}
+}
+
+func TestWarmPool_Reconcile(t *testing.T) {
+	manager := NewProcessManager("/tmp/logs", nil)
+
+	// A busy agent in the pool's repo and an idle agent in another repo
+	// do not count toward the pool
+	manager.agents["busy"] = &AgentSlot{AgentID: "busy", Status: AgentStatusBusy, RepoRoot: "/repo"}
+	manager.agents["elsewhere"] = &AgentSlot{AgentID: "elsewhere", Status: AgentStatusIdle, RepoRoot: "/other"}
+
+	var spawned, killed []string
+	spawn := func(repoRoot string) (string, error) {
+		if repoRoot != "/repo" {
+			t.Errorf("spawn repoRoot = %q, want /repo", repoRoot)
+		}
+		id := fmt.Sprintf("pool-%d", len(spawned))
+		spawned = append(spawned, id)
+		manager.agents[id] = &AgentSlot{AgentID: id, Status: AgentStatusIdle, RepoRoot: repoRoot}
+		return id, nil
+	}
+	kill := func(agentID string) {
+		killed = append(killed, agentID)
+		delete(manager.agents, agentID)
+	}
+
+	pool := NewWarmPool(manager, nil, 2, "/repo", spawn, kill)
+
+	pool.reconcile()
+	if len(spawned) != 2 {
+		t.Fatalf("spawned %d agents, want 2", len(spawned))
+	}
+
+	// Busy agent finishes: three idle in repo, one pool agent is reaped
+	manager.agents["busy"].Status = AgentStatusIdle
+	pool.reconcile()
+	if len(killed) != 1 || !slices.Contains(spawned, killed[0]) {
+		t.Errorf("killed = %v, want one pool-owned agent", killed)
+	}
+	if _, ok := manager.agents["busy"]; !ok {
+		t.Error("agent not spawned by the pool should not be reaped")
+	}
+}
+
+func TestWarmPool_Reconcile_WorkingAgent(t *testing.T) {
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+	manager := NewProcessManager(t.TempDir(), nil)
+
+	var spawned, killed []string
+	spawn := func(repoRoot string) (string, error) {
+		id := fmt.Sprintf("pool-%d", len(spawned))
+		spawned = append(spawned, id)
+		manager.agents[id] = &AgentSlot{AgentID: id, Status: AgentStatusIdle, RepoRoot: repoRoot}
+		return id, nil
+	}
+	kill := func(agentID string) {
+		killed = append(killed, agentID)
+		delete(manager.agents, agentID)
+	}
+
+	pool := NewWarmPool(manager, store, 1, "/repo", spawn, kill)
+	pool.reconcile()
+	if len(spawned) != 1 {
+		t.Fatalf("spawned %d agents, want 1", len(spawned))
+	}
+
+	// The pool agent takes a task. Its tmux slot goes straight back to
+	// idle, but the task keeps it working, so the pool tops up.
+	now := time.Now()
+	task := &TaskRecord{TaskID: "task-1", Description: "work", Status: "in_progress", AssignedTo: spawned[0], CreatedAt: now, UpdatedAt: now}
+	if err := store.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	pool.reconcile()
+	if len(spawned) != 2 {
+		t.Fatalf("spawned %d agents, want 2 with the first one working", len(spawned))
+	}
+
+	// Even with a surplus of pool agents, the working one is never reaped
+	manager.agents["pool-extra"] = &AgentSlot{AgentID: "pool-extra", Status: AgentStatusIdle, RepoRoot: "/repo"}
+	pool.owned["pool-extra"] = true
+	pool.reconcile()
+	if slices.Contains(killed, spawned[0]) {
+		t.Errorf("killed = %v, working agent %s must not be reaped", killed, spawned[0])
+	}
+	if len(killed) != 1 {
+		t.Errorf("killed = %v, want one idle surplus agent", killed)
+	}
+}
@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"strings"
+	"unicode"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+// idleWindowTitle names the tmux window of an agent with no active task
+const idleWindowTitle = "idle"
+
+// windowTitleDescriptionMax caps how much of a task description goes into
+// the window title, so the status bar stays readable
+const windowTitleDescriptionMax = 32
+
+// taskWindowTitle builds the tmux window title for an agent whose task moved
+// to status: the status and the start of the description while the task is
+// active, or idle once it has finished or gone back to the queue
+func taskWindowTitle(status, description string) string {
+	switch status {
+	case "accepted", "in_progress", "waiting_input":
+	default:
+		return idleWindowTitle
+	}
+
+	// Keep the first line, with control characters and runs of spaces collapsed
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	line = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r)
+	}), " ")
+	if runes := []rune(line); len(runes) > windowTitleDescriptionMax {
+		line = string(runes[:windowTitleDescriptionMax-1]) + "…"
+	}
+	if line == "" {
+		return status
+	}
+	return status + ": " + line
+}
+
+// retitlesWindow reports whether a task event moves the task into or out of
+// work on its agent, which is when the agent's window title changes
+func retitlesWindow(eventType mapv1.EventType) bool {
+	switch eventType {
+	case mapv1.EventType_EVENT_TYPE_TASK_ACCEPTED,
+		mapv1.EventType_EVENT_TYPE_TASK_STARTED,
+		mapv1.EventType_EVENT_TYPE_TASK_WAITING_INPUT,
+		mapv1.EventType_EVENT_TYPE_TASK_INPUT_RECEIVED,
+		mapv1.EventType_EVENT_TYPE_TASK_REOPENED,
+		mapv1.EventType_EVENT_TYPE_TASK_COMPLETED,
+		mapv1.EventType_EVENT_TYPE_TASK_FAILED,
+		mapv1.EventType_EVENT_TYPE_TASK_CANCELLED:
+		return true
+	}
+	return false
+}
+
+// updateWindowTitle retitles the tmux window of the agent a task event is
+// about, so attached users and `agent watch --all` can see what each agent
+// is doing. Only events that move a task into or out of work count, and the
+// store lookup and tmux call run off the broadcast loop.
+func (s *Server) updateWindowTitle(event *mapv1.Event) {
+	taskEvent := event.GetTask()
+	if taskEvent == nil || s.processes == nil || s.store == nil || !retitlesWindow(event.GetType()) {
+		return
+	}
+	go s.retitleWindow(taskEvent.GetTaskId(), taskEvent.GetAgentId())
+}
+
+// retitleWindow sets the window title of the agent working on a task from
+// the task's current status. Without agentID the task's assignee is used.
+func (s *Server) retitleWindow(taskID, agentID string) {
+	task, err := s.store.GetTask(taskID)
+	if err != nil || task == nil {
+		return
+	}
+	if agentID == "" {
+		agentID = task.AssignedTo
+	}
+	if agentID == "" {
+		return
+	}
+
+	s.processes.SetWindowTitle(agentID, taskWindowTitle(task.Status, task.Description))
+}
@@ -0,0 +1,45 @@
+package daemon
+
+import (
+	"testing"
+
+	mapv1 "github.com/pmarsceill/mapcli/proto/map/v1"
+)
+
+func TestTaskWindowTitle(t *testing.T) {
+	tests := []struct {
+		status      string
+		description string
+		want        string
+	}{
+		{"in_progress", "Fix the flaky auth test", "in_progress: Fix the flaky auth test"},
+		{"waiting_input", "  Add\tretries  to the\nGitHub poller", "waiting_input: Add retries to the"},
+		{"in_progress", "Rewrite the scheduler so agents are picked by load", "in_progress: Rewrite the scheduler so agents…"},
+		{"accepted", "", "accepted"},
+		{"completed", "Fix the flaky auth test", idleWindowTitle},
+		{"failed", "Fix the flaky auth test", idleWindowTitle},
+		{"cancelled", "Fix the flaky auth test", idleWindowTitle},
+		{"pending", "Fix the flaky auth test", idleWindowTitle},
+	}
+
+	for _, tt := range tests {
+		if got := taskWindowTitle(tt.status, tt.description); got != tt.want {
+			t.Errorf("taskWindowTitle(%q, %q) = %q, want %q", tt.status, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestRetitlesWindow(t *testing.T) {
+	for eventType, want := range map[mapv1.EventType]bool{
+		mapv1.EventType_EVENT_TYPE_TASK_STARTED:    true,
+		mapv1.EventType_EVENT_TYPE_TASK_COMPLETED:  true,
+		mapv1.EventType_EVENT_TYPE_TASK_CANCELLED:  true,
+		mapv1.EventType_EVENT_TYPE_TASK_CREATED:    false,
+		mapv1.EventType_EVENT_TYPE_TASK_OFFERED:    false,
+		mapv1.EventType_EVENT_TYPE_AGENT_HEARTBEAT: false,
+	} {
+		if got := retitlesWindow(eventType); got != want {
+			t.Errorf("retitlesWindow(%v) = %v, want %v", eventType, got, want)
+		}
+	}
+}
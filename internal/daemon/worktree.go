@@ -8,15 +8,34 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 )
 
+// mapBranchPrefix is the naming prefix for per-agent branches (map/<agent-id>)
+const mapBranchPrefix = "map/"
+
+// DefaultBranchTemplate is the per-agent branch name pattern used when
+// branch-per-agent worktrees are enabled without a custom template
+const DefaultBranchTemplate = mapBranchPrefix + "{{.AgentID}}"
+
+// BranchTemplateData is the data available to branch name templates
+type BranchTemplateData struct {
+	AgentID string // agent ID, e.g. "claude-swift-fox"
+	Date    string // creation date as YYYY-MM-DD
+	Base    string // branch the worktree was created from
+}
+
 // WorktreeManager manages git worktrees for spawned agents
 type WorktreeManager struct {
 	repoRoot    string
 	worktreeDir string
 	mu          sync.RWMutex
 	worktrees   map[string]*Worktree
+
+	// branchTemplate, if set, names a new branch created for each worktree;
+	// otherwise worktrees use a detached HEAD
+	branchTemplate *template.Template
 }
 
 // Worktree represents a git worktree for an agent
@@ -49,6 +68,26 @@ func NewWorktreeManager(dataDir string) (*WorktreeManager, error) {
 	}, nil
 }
 
+// SetBranchTemplate enables branch-per-agent worktrees named by the given
+// text/template (see BranchTemplateData). An empty template restores
+// detached-HEAD worktrees.
+func (m *WorktreeManager) SetBranchTemplate(tmpl string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tmpl == "" {
+		m.branchTemplate = nil
+		return nil
+	}
+
+	t, err := template.New("branch").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return fmt.Errorf("parse branch template: %w", err)
+	}
+	m.branchTemplate = t
+	return nil
+}
+
 // Create creates a new worktree for an agent using the manager's default repo root
 func (m *WorktreeManager) Create(agentID, branch string) (*Worktree, error) {
 	return m.CreateFromRepo(agentID, branch, m.repoRoot)
@@ -76,7 +115,7 @@ func (m *WorktreeManager) CreateFromRepo(agentID, branch, repoRoot string) (*Wor
 
 	// Check if worktree already exists
 	if _, err := os.Stat(worktreePath); err == nil {
-		return nil, fmt.Errorf("worktree already exists for agent %s", agentID)
+		return nil, alreadyExistsf("worktree already exists for agent %s", agentID)
 	}
 
 	// Create the worktree using detached HEAD to avoid branch conflicts
@@ -86,13 +125,36 @@ func (m *WorktreeManager) CreateFromRepo(agentID, branch, repoRoot string) (*Wor
 		return nil, fmt.Errorf("get commit SHA for branch %s: %w", branch, err)
 	}
 
-	// Create worktree at the commit (detached HEAD)
-	cmd := exec.Command("git", "worktree", "add", "--detach", worktreePath, commitSHA)
-	cmd.Dir = repoRoot
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("create worktree: %s: %w", stderr.String(), err)
+	// Create worktree at the commit, on a new per-agent branch if a branch
+	// template is configured, otherwise with a detached HEAD
+	args := []string{"worktree", "add", "--detach", worktreePath, commitSHA}
+	if m.branchTemplate != nil {
+		name, err := renderBranchName(m.branchTemplate, BranchTemplateData{
+			AgentID: agentID,
+			Date:    time.Now().Format("2006-01-02"),
+			Base:    branch,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := validateBranchName(repoRoot, name); err != nil {
+			return nil, err
+		}
+		args = []string{"worktree", "add", "-b", name, worktreePath, commitSHA}
+		branch = name
+	}
+
+	stderr, err := runWorktreeAdd(repoRoot, args)
+	if err != nil && isStaleWorktreeError(stderr) {
+		// git still tracks a worktree at this path from an unclean shutdown;
+		// clear the stale registration and try once more
+		if pruneErr := pruneStaleWorktree(repoRoot, worktreePath); pruneErr != nil {
+			return nil, fmt.Errorf("create worktree: %s: %w (prune stale worktree: %v)", stderr, err, pruneErr)
+		}
+		stderr, err = runWorktreeAdd(repoRoot, args)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create worktree: %s: %w", stderr, err)
 	}
 
 	wt := &Worktree{
@@ -218,13 +280,181 @@ func (m *WorktreeManager) CleanupAgent(agentID string) error {
 	return m.Remove(agentID)
 }
 
+// PruneBranches deletes local map/<agent-id> branches that are not checked out
+// in any worktree and do not belong to a running agent. If repoRoot is empty,
+// the manager's default repo root is used. With dryRun set, the branches that
+// would be deleted are returned without deleting them.
+func (m *WorktreeManager) PruneBranches(repoRoot string, runningAgentIDs map[string]bool, dryRun bool) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if repoRoot == "" {
+		repoRoot = m.repoRoot
+	}
+	if repoRoot == "" {
+		return nil, fmt.Errorf("not in a git repository")
+	}
+
+	branches, err := listMapBranches(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	checkedOut, err := listWorktreeBranches(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for _, branch := range branches {
+		agentID := strings.TrimPrefix(branch, mapBranchPrefix)
+		if runningAgentIDs[agentID] || checkedOut[branch] {
+			continue
+		}
+
+		if !dryRun {
+			cmd := exec.Command("git", "branch", "-D", branch)
+			cmd.Dir = repoRoot
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				return pruned, fmt.Errorf("delete branch %s: %s: %w", branch, stderr.String(), err)
+			}
+		}
+		pruned = append(pruned, branch)
+	}
+
+	return pruned, nil
+}
+
 // GetRepoRoot returns the git repository root path
 func (m *WorktreeManager) GetRepoRoot() string {
 	return m.repoRoot
 }
 
+// IdentityTemplateData is the data available to git identity templates
+type IdentityTemplateData struct {
+	AgentID string
+}
+
+// ConfigureIdentity sets user.name and user.email in an agent's worktree so
+// commits made there are attributed to the agent. The values are
+// text/templates rendered with IdentityTemplateData. The settings are
+// written to the worktree's own config so the main checkout and other
+// agents are unaffected.
+func (m *WorktreeManager) ConfigureIdentity(agentID, nameTmpl, emailTmpl string) error {
+	m.mu.RLock()
+	wt, ok := m.worktrees[agentID]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no worktree for agent %s", agentID)
+	}
+
+	data := IdentityTemplateData{AgentID: agentID}
+	name, err := renderIdentity("git user name", nameTmpl, data)
+	if err != nil {
+		return err
+	}
+	email, err := renderIdentity("git user email", emailTmpl, data)
+	if err != nil {
+		return err
+	}
+
+	// Per-worktree config requires the extension to be enabled in the repo
+	settings := [][]string{{"config", "extensions.worktreeConfig", "true"}}
+	if name != "" {
+		settings = append(settings, []string{"config", "--worktree", "user.name", name})
+	}
+	if email != "" {
+		settings = append(settings, []string{"config", "--worktree", "user.email", email})
+	}
+
+	for _, args := range settings {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = wt.Path
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("git %s: %s: %w", strings.Join(args, " "), strings.TrimSpace(stderr.String()), err)
+		}
+	}
+	return nil
+}
+
 // Helper functions
 
+// renderBranchName executes a branch name template
+func renderBranchName(t *template.Template, data BranchTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render branch template: %w", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// runWorktreeAdd runs a git worktree add command and returns its stderr
+func runWorktreeAdd(repoRoot string, args []string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+// isStaleWorktreeError reports whether git worktree add failed because git
+// still has the path registered as a worktree whose directory is gone
+func isStaleWorktreeError(stderr string) bool {
+	return strings.Contains(stderr, "already registered") ||
+		strings.Contains(stderr, "missing but locked worktree")
+}
+
+// pruneStaleWorktree unlocks the worktree at path, if it is locked, and
+// prunes administrative entries for worktrees whose directories are missing
+func pruneStaleWorktree(repoRoot, path string) error {
+	// Unlock fails harmlessly when the worktree isn't locked
+	unlock := exec.Command("git", "worktree", "unlock", path)
+	unlock.Dir = repoRoot
+	_ = unlock.Run()
+
+	prune := exec.Command("git", "worktree", "prune")
+	prune.Dir = repoRoot
+	if out, err := prune.CombinedOutput(); err != nil {
+		return fmt.Errorf("git worktree prune: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return nil
+}
+
+// renderIdentity executes a git identity template; an empty template renders
+// to an empty value
+func renderIdentity(what, tmpl string, data IdentityTemplateData) (string, error) {
+	if tmpl == "" {
+		return "", nil
+	}
+	t, err := template.New(what).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parse %s template: %w", what, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render %s template: %w", what, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// validateBranchName checks that name is a legal git branch name
+func validateBranchName(repoRoot, name string) error {
+	if name == "" {
+		return fmt.Errorf("branch template produced an empty branch name")
+	}
+	cmd := exec.Command("git", "check-ref-format", "--branch", name)
+	cmd.Dir = repoRoot
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("branch template produced invalid branch name %q", name)
+	}
+	return nil
+}
+
 func getGitRepoRoot() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	var stdout, stderr bytes.Buffer
@@ -236,6 +466,46 @@ func getGitRepoRoot() (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// listMapBranches returns local branches matching the map/<agent-id> naming pattern
+func listMapBranches(repoRoot string) ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/"+mapBranchPrefix)
+	cmd.Dir = repoRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("list branches: %s", stderr.String())
+	}
+
+	var branches []string
+	for line := range strings.SplitSeq(strings.TrimSpace(stdout.String()), "\n") {
+		if line != "" {
+			branches = append(branches, line)
+		}
+	}
+	return branches, nil
+}
+
+// listWorktreeBranches returns the set of branches checked out in any worktree of the repo
+func listWorktreeBranches(repoRoot string) (map[string]bool, error) {
+	cmd := exec.Command("git", "worktree", "list", "--porcelain")
+	cmd.Dir = repoRoot
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("list worktrees: %s", stderr.String())
+	}
+
+	branches := make(map[string]bool)
+	for line := range strings.SplitSeq(stdout.String(), "\n") {
+		if ref, ok := strings.CutPrefix(line, "branch refs/heads/"); ok {
+			branches[ref] = true
+		}
+	}
+	return branches, nil
+}
+
 func getCurrentBranch(repoRoot string) (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = repoRoot
@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"text/template"
 )
 
 func setupTestWorktreeManager(t *testing.T) (*WorktreeManager, string, func()) {
@@ -383,3 +385,266 @@ func TestWorktreeManager_Create_AlreadyExists(t *testing.T) {
 		t.Error("Create should fail when worktree already exists")
 	}
 }
+
+func TestWorktreeManager_PruneBranches_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	repoDir, err := os.MkdirTemp("", "mapd-git-test-*")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(repoDir) }()
+
+	initTestGitRepo(t, repoDir)
+
+	mgr, _, cleanup := setupTestWorktreeManager(t)
+	defer cleanup()
+
+	for _, branch := range []string{"map/orphan", "map/running", "feature/keep"} {
+		cmd := exec.Command("git", "branch", branch)
+		cmd.Dir = repoDir
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("git branch %s: %v", branch, err)
+		}
+	}
+
+	running := map[string]bool{"running": true}
+
+	// Dry run reports but does not delete
+	pruned, err := mgr.PruneBranches(repoDir, running, true)
+	if err != nil {
+		t.Fatalf("PruneBranches (dry run) failed: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != "map/orphan" {
+		t.Errorf("PruneBranches (dry run) = %v, want [map/orphan]", pruned)
+	}
+	branches, _ := listMapBranches(repoDir)
+	if len(branches) != 2 {
+		t.Errorf("dry run deleted branches: %v", branches)
+	}
+
+	pruned, err = mgr.PruneBranches(repoDir, running, false)
+	if err != nil {
+		t.Fatalf("PruneBranches failed: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0] != "map/orphan" {
+		t.Errorf("PruneBranches = %v, want [map/orphan]", pruned)
+	}
+	branches, _ = listMapBranches(repoDir)
+	if len(branches) != 1 || branches[0] != "map/running" {
+		t.Errorf("remaining map branches = %v, want [map/running]", branches)
+	}
+}
+
+func TestRenderBranchName(t *testing.T) {
+	data := BranchTemplateData{AgentID: "claude-swift-fox", Date: "2025-01-02", Base: "main"}
+
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{DefaultBranchTemplate, "map/claude-swift-fox"},
+		{"agents/{{.AgentID}}", "agents/claude-swift-fox"},
+		{"{{.Date}}-{{.AgentID}}", "2025-01-02-claude-swift-fox"},
+		{"{{.Base}}/{{.AgentID}}", "main/claude-swift-fox"},
+	}
+
+	for _, tt := range tests {
+		tmpl := template.Must(template.New("branch").Option("missingkey=error").Parse(tt.tmpl))
+		got, err := renderBranchName(tmpl, data)
+		if err != nil {
+			t.Errorf("renderBranchName(%q) error: %v", tt.tmpl, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("renderBranchName(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+}
+
+func TestWorktreeManager_SetBranchTemplate(t *testing.T) {
+	mgr, _, cleanup := setupTestWorktreeManager(t)
+	defer cleanup()
+
+	if err := mgr.SetBranchTemplate("agents/{{.AgentID"); err == nil {
+		t.Error("expected error for unparseable template")
+	}
+
+	if err := mgr.SetBranchTemplate("agents/{{.AgentID}}"); err != nil {
+		t.Fatalf("SetBranchTemplate failed: %v", err)
+	}
+	if mgr.branchTemplate == nil {
+		t.Error("branchTemplate should be set")
+	}
+
+	if err := mgr.SetBranchTemplate(""); err != nil {
+		t.Fatalf("SetBranchTemplate(\"\") failed: %v", err)
+	}
+	if mgr.branchTemplate != nil {
+		t.Error("empty template should restore detached HEAD worktrees")
+	}
+}
+
+func TestWorktreeManager_CreateFromRepo_BranchTemplate_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	repoDir, err := os.MkdirTemp("", "mapd-git-test-*")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(repoDir) }()
+	initTestGitRepo(t, repoDir)
+
+	mgr, _, cleanup := setupTestWorktreeManager(t)
+	defer cleanup()
+
+	if err := mgr.SetBranchTemplate("agents/{{.AgentID}}"); err != nil {
+		t.Fatalf("SetBranchTemplate failed: %v", err)
+	}
+
+	wt, err := mgr.CreateFromRepo("test-agent", "", repoDir)
+	if err != nil {
+		t.Fatalf("CreateFromRepo failed: %v", err)
+	}
+	defer func() { _ = mgr.Remove("test-agent") }()
+
+	if wt.Branch != "agents/test-agent" {
+		t.Errorf("Branch = %q, want %q", wt.Branch, "agents/test-agent")
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = wt.Path
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("git rev-parse: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "agents/test-agent" {
+		t.Errorf("worktree HEAD = %q, want %q", got, "agents/test-agent")
+	}
+
+	// A template that renders an illegal ref is rejected before git runs
+	if err := mgr.SetBranchTemplate("bad..{{.AgentID}}"); err != nil {
+		t.Fatalf("SetBranchTemplate failed: %v", err)
+	}
+	if _, err := mgr.CreateFromRepo("other-agent", "", repoDir); err == nil {
+		t.Error("expected error for invalid branch name")
+	}
+}
+
+func TestWorktreeManager_ConfigureIdentity_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	repoDir, err := os.MkdirTemp("", "mapd-git-test-*")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(repoDir) }()
+	initTestGitRepo(t, repoDir)
+
+	mgr, _, cleanup := setupTestWorktreeManager(t)
+	defer cleanup()
+
+	wt, err := mgr.CreateFromRepo("test-agent", "", repoDir)
+	if err != nil {
+		t.Fatalf("CreateFromRepo failed: %v", err)
+	}
+	defer func() { _ = mgr.Remove("test-agent") }()
+
+	if err := mgr.ConfigureIdentity("test-agent", "map-agent {{.AgentID}}", "{{.AgentID}}@map.local"); err != nil {
+		t.Fatalf("ConfigureIdentity failed: %v", err)
+	}
+
+	gitConfig := func(dir, key string) string {
+		cmd := exec.Command("git", "config", key)
+		cmd.Dir = dir
+		out, _ := cmd.Output()
+		return strings.TrimSpace(string(out))
+	}
+
+	if got := gitConfig(wt.Path, "user.name"); got != "map-agent test-agent" {
+		t.Errorf("worktree user.name = %q, want %q", got, "map-agent test-agent")
+	}
+	if got := gitConfig(wt.Path, "user.email"); got != "test-agent@map.local" {
+		t.Errorf("worktree user.email = %q, want %q", got, "test-agent@map.local")
+	}
+
+	// The main checkout keeps its own identity
+	if got := gitConfig(repoDir, "user.name"); got != "Test User" {
+		t.Errorf("repo user.name = %q, want %q", got, "Test User")
+	}
+
+	if err := mgr.ConfigureIdentity("missing-agent", "x", "y"); err == nil {
+		t.Error("expected error for agent without a worktree")
+	}
+}
+
+func TestWorktreeManager_CreateFromRepo_StaleWorktree_Integration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	repoDir, err := os.MkdirTemp("", "mapd-git-test-*")
+	if err != nil {
+		t.Fatalf("create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(repoDir) }()
+	initTestGitRepo(t, repoDir)
+
+	for _, locked := range []bool{false, true} {
+		mgr, _, cleanup := setupTestWorktreeManager(t)
+
+		wt, err := mgr.CreateFromRepo("test-agent", "", repoDir)
+		if err != nil {
+			cleanup()
+			t.Fatalf("CreateFromRepo failed: %v", err)
+		}
+		if locked {
+			cmd := exec.Command("git", "worktree", "lock", wt.Path)
+			cmd.Dir = repoDir
+			if err := cmd.Run(); err != nil {
+				cleanup()
+				t.Fatalf("git worktree lock: %v", err)
+			}
+		}
+
+		// Simulate an unclean shutdown: the directory is gone but git still
+		// has the worktree registered
+		if err := os.RemoveAll(wt.Path); err != nil {
+			cleanup()
+			t.Fatalf("remove worktree dir: %v", err)
+		}
+		mgr.mu.Lock()
+		delete(mgr.worktrees, "test-agent")
+		mgr.mu.Unlock()
+
+		if _, err := mgr.CreateFromRepo("test-agent", "", repoDir); err != nil {
+			t.Errorf("CreateFromRepo over stale worktree (locked=%v) failed: %v", locked, err)
+		}
+		_ = mgr.Remove("test-agent")
+		cleanup()
+	}
+}
+
+func TestIsStaleWorktreeError(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"fatal: '/tmp/wt' is a missing but already registered worktree;\nuse 'add -f' to override, or 'prune' or 'remove' to clear", true},
+		{"fatal: '/tmp/wt' is a missing but locked worktree;\nuse 'add -f -f' to override, or 'unlock' and 'prune' or 'remove' to clear", true},
+		{"fatal: invalid reference: main", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isStaleWorktreeError(tt.stderr); got != tt.want {
+			t.Errorf("isStaleWorktreeError(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+}
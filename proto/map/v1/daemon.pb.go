@@ -34,7 +34,18 @@ type SubmitTaskRequest struct {
 	GithubRepo        string `protobuf:"bytes,5,opt,name=github_repo,json=githubRepo,proto3" json:"github_repo,omitempty"`
 	GithubIssueNumber int32  `protobuf:"varint,6,opt,name=github_issue_number,json=githubIssueNumber,proto3" json:"github_issue_number,omitempty"`
 	// Repository root the task belongs to
-	RepoRoot      string `protobuf:"bytes,7,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	RepoRoot string `protobuf:"bytes,7,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Optional: run (batch of related tasks) to group the task into
+	RunId string `protobuf:"bytes,8,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// Optional: agent to prefer when it is idle, e.g. the one that has
+	// context from a prior task
+	PreferAgent string `protobuf:"bytes,9,opt,name=prefer_agent,json=preferAgent,proto3" json:"prefer_agent,omitempty"`
+	// Optional: the task is read-only and can run in an agent's shared
+	// working directory rather than an isolated worktree
+	NoWorktree bool `protobuf:"varint,10,opt,name=no_worktree,json=noWorktree,proto3" json:"no_worktree,omitempty"`
+	// Optional: agent type to prefer when one is idle. When empty, the
+	// daemon's routing rules may choose one.
+	AgentType     string `protobuf:"bytes,11,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -118,6 +129,34 @@ func (x *SubmitTaskRequest) GetRepoRoot() string {
 	return ""
 }
 
+func (x *SubmitTaskRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *SubmitTaskRequest) GetPreferAgent() string {
+	if x != nil {
+		return x.PreferAgent
+	}
+	return ""
+}
+
+func (x *SubmitTaskRequest) GetNoWorktree() bool {
+	if x != nil {
+		return x.NoWorktree
+	}
+	return false
+}
+
+func (x *SubmitTaskRequest) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
 // SubmitTaskResponse returns the created task
 type SubmitTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -173,9 +212,11 @@ type ListTasksRequest struct {
 	// Limit number of results (0 = no limit)
 	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	// Optional filter by repo root path (only show tasks for this repo)
-	RepoRoot      string `protobuf:"bytes,4,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	RepoRoot string `protobuf:"bytes,4,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Include archived tasks, which are left out by default
+	IncludeArchived bool `protobuf:"varint,5,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ListTasksRequest) Reset() {
@@ -236,6 +277,13 @@ func (x *ListTasksRequest) GetRepoRoot() string {
 	return ""
 }
 
+func (x *ListTasksRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
 // ListTasksResponse contains the list of tasks
 type ListTasksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -461,29 +509,35 @@ func (x *CancelTaskResponse) GetTask() *Task {
 	return nil
 }
 
-// ShutdownRequest asks the daemon to shut down
-type ShutdownRequest struct {
+// RetryTaskRequest re-queues a failed task, or every failed task
+type RetryTaskRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Force immediate shutdown without waiting for tasks
-	Force         bool `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`
+	// Task to retry (ignored when all_failed is set)
+	TaskId string `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Retry every failed task instead of a single one
+	AllFailed bool `protobuf:"varint,2,opt,name=all_failed,json=allFailed,proto3" json:"all_failed,omitempty"`
+	// With all_failed: only retry tasks that failed at or after this time
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// With all_failed: only retry tasks from this repository (empty = all)
+	RepoRoot      string `protobuf:"bytes,4,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShutdownRequest) Reset() {
-	*x = ShutdownRequest{}
+func (x *RetryTaskRequest) Reset() {
+	*x = RetryTaskRequest{}
 	mi := &file_map_v1_daemon_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShutdownRequest) String() string {
+func (x *RetryTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShutdownRequest) ProtoMessage() {}
+func (*RetryTaskRequest) ProtoMessage() {}
 
-func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
+func (x *RetryTaskRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -495,40 +549,63 @@ func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShutdownRequest.ProtoReflect.Descriptor instead.
-func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetryTaskRequest.ProtoReflect.Descriptor instead.
+func (*RetryTaskRequest) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ShutdownRequest) GetForce() bool {
+func (x *RetryTaskRequest) GetTaskId() string {
 	if x != nil {
-		return x.Force
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RetryTaskRequest) GetAllFailed() bool {
+	if x != nil {
+		return x.AllFailed
 	}
 	return false
 }
 
-// ShutdownResponse confirms shutdown initiated
-type ShutdownResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+func (x *RetryTaskRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *RetryTaskRequest) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+// RetryTaskResponse reports which tasks were re-queued
+type RetryTaskResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Retried []*Task                `protobuf:"bytes,1,rep,name=retried,proto3" json:"retried,omitempty"`
+	// Tasks left failed because they reached the retry limit
+	Skipped       []*Task `protobuf:"bytes,2,rep,name=skipped,proto3" json:"skipped,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ShutdownResponse) Reset() {
-	*x = ShutdownResponse{}
+func (x *RetryTaskResponse) Reset() {
+	*x = RetryTaskResponse{}
 	mi := &file_map_v1_daemon_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ShutdownResponse) String() string {
+func (x *RetryTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ShutdownResponse) ProtoMessage() {}
+func (*RetryTaskResponse) ProtoMessage() {}
 
-func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
+func (x *RetryTaskResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -540,39 +617,47 @@ func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ShutdownResponse.ProtoReflect.Descriptor instead.
-func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RetryTaskResponse.ProtoReflect.Descriptor instead.
+func (*RetryTaskResponse) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ShutdownResponse) GetMessage() string {
+func (x *RetryTaskResponse) GetRetried() []*Task {
 	if x != nil {
-		return x.Message
+		return x.Retried
 	}
-	return ""
+	return nil
 }
 
-// GetStatusRequest retrieves daemon status
-type GetStatusRequest struct {
+func (x *RetryTaskResponse) GetSkipped() []*Task {
+	if x != nil {
+		return x.Skipped
+	}
+	return nil
+}
+
+// ReopenTaskRequest moves a completed task back into work
+type ReopenTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatusRequest) Reset() {
-	*x = GetStatusRequest{}
+func (x *ReopenTaskRequest) Reset() {
+	*x = ReopenTaskRequest{}
 	mi := &file_map_v1_daemon_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatusRequest) String() string {
+func (x *ReopenTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatusRequest) ProtoMessage() {}
+func (*ReopenTaskRequest) ProtoMessage() {}
 
-func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *ReopenTaskRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -584,37 +669,41 @@ func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReopenTaskRequest.ProtoReflect.Descriptor instead.
+func (*ReopenTaskRequest) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{10}
 }
 
-// GetStatusResponse returns daemon status
-type GetStatusResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Running         bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
-	StartedAt       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
-	ConnectedAgents int32                  `protobuf:"varint,3,opt,name=connected_agents,json=connectedAgents,proto3" json:"connected_agents,omitempty"`
-	PendingTasks    int32                  `protobuf:"varint,4,opt,name=pending_tasks,json=pendingTasks,proto3" json:"pending_tasks,omitempty"`
-	ActiveTasks     int32                  `protobuf:"varint,5,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *ReopenTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
 }
 
-func (x *GetStatusResponse) Reset() {
-	*x = GetStatusResponse{}
+type ReopenTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The reopened task: in_progress on its previous agent if that agent is
+	// still running, otherwise pending
+	Task          *Task `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReopenTaskResponse) Reset() {
+	*x = ReopenTaskResponse{}
 	mi := &file_map_v1_daemon_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatusResponse) String() string {
+func (x *ReopenTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatusResponse) ProtoMessage() {}
+func (*ReopenTaskResponse) ProtoMessage() {}
 
-func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *ReopenTaskResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -626,73 +715,41 @@ func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReopenTaskResponse.ProtoReflect.Descriptor instead.
+func (*ReopenTaskResponse) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *GetStatusResponse) GetRunning() bool {
-	if x != nil {
-		return x.Running
-	}
-	return false
-}
-
-func (x *GetStatusResponse) GetStartedAt() *timestamppb.Timestamp {
+func (x *ReopenTaskResponse) GetTask() *Task {
 	if x != nil {
-		return x.StartedAt
+		return x.Task
 	}
 	return nil
 }
 
-func (x *GetStatusResponse) GetConnectedAgents() int32 {
-	if x != nil {
-		return x.ConnectedAgents
-	}
-	return 0
-}
-
-func (x *GetStatusResponse) GetPendingTasks() int32 {
-	if x != nil {
-		return x.PendingTasks
-	}
-	return 0
-}
-
-func (x *GetStatusResponse) GetActiveTasks() int32 {
-	if x != nil {
-		return x.ActiveTasks
-	}
-	return 0
-}
-
-// WatchEventsRequest configures event streaming
-type WatchEventsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Filter by event types (empty = all events)
-	TypeFilter []EventType `protobuf:"varint,1,rep,packed,name=type_filter,json=typeFilter,proto3,enum=map.v1.EventType" json:"type_filter,omitempty"`
-	// Filter by agent ID
-	AgentFilter string `protobuf:"bytes,2,opt,name=agent_filter,json=agentFilter,proto3" json:"agent_filter,omitempty"`
-	// Filter by task ID
-	TaskFilter    string `protobuf:"bytes,3,opt,name=task_filter,json=taskFilter,proto3" json:"task_filter,omitempty"`
+// ArchiveTaskRequest hides a finished task from task listings, keeping it
+// for history and export
+type ArchiveTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WatchEventsRequest) Reset() {
-	*x = WatchEventsRequest{}
+func (x *ArchiveTaskRequest) Reset() {
+	*x = ArchiveTaskRequest{}
 	mi := &file_map_v1_daemon_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WatchEventsRequest) String() string {
+func (x *ArchiveTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WatchEventsRequest) ProtoMessage() {}
+func (*ArchiveTaskRequest) ProtoMessage() {}
 
-func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+func (x *ArchiveTaskRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -704,73 +761,39 @@ func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
-func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArchiveTaskRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveTaskRequest) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *WatchEventsRequest) GetTypeFilter() []EventType {
-	if x != nil {
-		return x.TypeFilter
-	}
-	return nil
-}
-
-func (x *WatchEventsRequest) GetAgentFilter() string {
-	if x != nil {
-		return x.AgentFilter
-	}
-	return ""
-}
-
-func (x *WatchEventsRequest) GetTaskFilter() string {
+func (x *ArchiveTaskRequest) GetTaskId() string {
 	if x != nil {
-		return x.TaskFilter
+		return x.TaskId
 	}
 	return ""
 }
 
-// SpawnAgentRequest requests spawning Claude Code agents
-type SpawnAgentRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Number of agents to spawn (default: 1)
-	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
-	// Git branch for worktrees (empty = current branch)
-	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
-	// Whether to create worktree isolation
-	UseWorktree bool `protobuf:"varint,3,opt,name=use_worktree,json=useWorktree,proto3" json:"use_worktree,omitempty"`
-	// Agent name prefix (default: "claude")
-	NamePrefix string `protobuf:"bytes,4,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
-	// Initial prompt to send to Claude
-	Prompt string `protobuf:"bytes,5,opt,name=prompt,proto3" json:"prompt,omitempty"`
-	// Agent type: "claude" (default) or "codex"
-	AgentType string `protobuf:"bytes,6,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
-	// Skip permission prompts so tasks execute immediately without user intervention
-	// For claude: uses --dangerously-skip-permissions
-	// For codex: uses --dangerously-bypass-approvals-and-sandbox
-	// Default: true (agents work autonomously)
-	SkipPermissions bool `protobuf:"varint,7,opt,name=skip_permissions,json=skipPermissions,proto3" json:"skip_permissions,omitempty"`
-	// Working directory - the git repository root to use for worktrees
-	// If empty, uses daemon's current directory
-	WorkingDirectory string `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+type ArchiveTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpawnAgentRequest) Reset() {
-	*x = SpawnAgentRequest{}
+func (x *ArchiveTaskResponse) Reset() {
+	*x = ArchiveTaskResponse{}
 	mi := &file_map_v1_daemon_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpawnAgentRequest) String() string {
+func (x *ArchiveTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpawnAgentRequest) ProtoMessage() {}
+func (*ArchiveTaskResponse) ProtoMessage() {}
 
-func (x *SpawnAgentRequest) ProtoReflect() protoreflect.Message {
+func (x *ArchiveTaskResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_map_v1_daemon_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -782,90 +805,3020 @@ func (x *SpawnAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpawnAgentRequest.ProtoReflect.Descriptor instead.
-func (*SpawnAgentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ArchiveTaskResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveTaskResponse) Descriptor() ([]byte, []int) {
 	return file_map_v1_daemon_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SpawnAgentRequest) GetCount() int32 {
+func (x *ArchiveTaskResponse) GetTask() *Task {
 	if x != nil {
-		return x.Count
+		return x.Task
 	}
-	return 0
+	return nil
 }
 
-func (x *SpawnAgentRequest) GetBranch() string {
-	if x != nil {
-		return x.Branch
-	}
-	return ""
+// ExportTasksRequest streams every task, from all repositories and
+// including archived ones, oldest first
+type ExportTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpawnAgentRequest) GetUseWorktree() bool {
-	if x != nil {
-		return x.UseWorktree
-	}
-	return false
+func (x *ExportTasksRequest) Reset() {
+	*x = ExportTasksRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpawnAgentRequest) GetNamePrefix() string {
-	if x != nil {
-		return x.NamePrefix
-	}
-	return ""
+func (x *ExportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *SpawnAgentRequest) GetPrompt() string {
+func (*ExportTasksRequest) ProtoMessage() {}
+
+func (x *ExportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[14]
 	if x != nil {
-		return x.Prompt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ExportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{14}
+}
+
+// ImportTasksRequest is one batch of tasks exported from a daemon, e.g. on
+// another machine, to recreate keeping their IDs, timestamps, and status.
+// Nothing is imported until the client has sent every batch.
+type ImportTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Tasks []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Replace tasks whose IDs already exist instead of skipping them. Read
+	// from the first batch.
+	Overwrite     bool `protobuf:"varint,2,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksRequest) Reset() {
+	*x = ImportTasksRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksRequest) ProtoMessage() {}
+
+func (x *ImportTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksRequest.ProtoReflect.Descriptor instead.
+func (*ImportTasksRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ImportTasksRequest) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *ImportTasksRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type ImportTasksResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Imported int32                  `protobuf:"varint,1,opt,name=imported,proto3" json:"imported,omitempty"`
+	// Tasks left alone because their IDs already exist
+	SkippedIds    []string `protobuf:"bytes,2,rep,name=skipped_ids,json=skippedIds,proto3" json:"skipped_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTasksResponse) Reset() {
+	*x = ImportTasksResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTasksResponse) ProtoMessage() {}
+
+func (x *ImportTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTasksResponse.ProtoReflect.Descriptor instead.
+func (*ImportTasksResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ImportTasksResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportTasksResponse) GetSkippedIds() []string {
+	if x != nil {
+		return x.SkippedIds
+	}
+	return nil
+}
+
+// NudgeTaskRequest re-sends an in-progress task's prompt to its agent
+type NudgeTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NudgeTaskRequest) Reset() {
+	*x = NudgeTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NudgeTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NudgeTaskRequest) ProtoMessage() {}
+
+func (x *NudgeTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NudgeTaskRequest.ProtoReflect.Descriptor instead.
+func (*NudgeTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *NudgeTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// NudgeTaskResponse returns the nudged task
+type NudgeTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NudgeTaskResponse) Reset() {
+	*x = NudgeTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NudgeTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NudgeTaskResponse) ProtoMessage() {}
+
+func (x *NudgeTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NudgeTaskResponse.ProtoReflect.Descriptor instead.
+func (*NudgeTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *NudgeTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// AcceptTaskRequest claims a task offered to the calling agent
+type AcceptTaskRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// The calling agent, which must be the one the task is offered to
+	AgentId       string `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptTaskRequest) Reset() {
+	*x = AcceptTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptTaskRequest) ProtoMessage() {}
+
+func (x *AcceptTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptTaskRequest.ProtoReflect.Descriptor instead.
+func (*AcceptTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AcceptTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AcceptTaskRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// AcceptTaskResponse returns the accepted task, now in progress
+type AcceptTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptTaskResponse) Reset() {
+	*x = AcceptTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptTaskResponse) ProtoMessage() {}
+
+func (x *AcceptTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptTaskResponse.ProtoReflect.Descriptor instead.
+func (*AcceptTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AcceptTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// RejectTaskRequest declines a task offered to the calling agent
+type RejectTaskRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Optional: why the agent declined, for the daemon log
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// The calling agent, which must be the one the task is offered to
+	AgentId       string `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectTaskRequest) Reset() {
+	*x = RejectTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTaskRequest) ProtoMessage() {}
+
+func (x *RejectTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTaskRequest.ProtoReflect.Descriptor instead.
+func (*RejectTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RejectTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *RejectTaskRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RejectTaskRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// RejectTaskResponse returns the rejected task, back in the queue
+type RejectTaskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectTaskResponse) Reset() {
+	*x = RejectTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectTaskResponse) ProtoMessage() {}
+
+func (x *RejectTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectTaskResponse.ProtoReflect.Descriptor instead.
+func (*RejectTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RejectTaskResponse) GetTask() *Task {
+	if x != nil {
+		return x.Task
+	}
+	return nil
+}
+
+// ListTaskAttemptsRequest lists the recorded runs of a task
+type ListTaskAttemptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskAttemptsRequest) Reset() {
+	*x = ListTaskAttemptsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskAttemptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskAttemptsRequest) ProtoMessage() {}
+
+func (x *ListTaskAttemptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskAttemptsRequest.ProtoReflect.Descriptor instead.
+func (*ListTaskAttemptsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListTaskAttemptsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// ListTaskAttemptsResponse returns a task's attempts, oldest first
+type ListTaskAttemptsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attempts      []*TaskAttempt         `protobuf:"bytes,1,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTaskAttemptsResponse) Reset() {
+	*x = ListTaskAttemptsResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTaskAttemptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTaskAttemptsResponse) ProtoMessage() {}
+
+func (x *ListTaskAttemptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTaskAttemptsResponse.ProtoReflect.Descriptor instead.
+func (*ListTaskAttemptsResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListTaskAttemptsResponse) GetAttempts() []*TaskAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+// ShutdownRequest asks the daemon to shut down
+type ShutdownRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Force immediate shutdown without waiting for tasks
+	Force         bool `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShutdownRequest) Reset() {
+	*x = ShutdownRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownRequest) ProtoMessage() {}
+
+func (x *ShutdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownRequest.ProtoReflect.Descriptor instead.
+func (*ShutdownRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ShutdownRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+// ShutdownResponse confirms shutdown initiated
+type ShutdownResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShutdownResponse) Reset() {
+	*x = ShutdownResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShutdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShutdownResponse) ProtoMessage() {}
+
+func (x *ShutdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShutdownResponse.ProtoReflect.Descriptor instead.
+func (*ShutdownResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ShutdownResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// GetStatusRequest retrieves daemon status
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{27}
+}
+
+// GetStatusResponse returns daemon status
+type GetStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Running         bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	StartedAt       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	ConnectedAgents int32                  `protobuf:"varint,3,opt,name=connected_agents,json=connectedAgents,proto3" json:"connected_agents,omitempty"`
+	PendingTasks    int32                  `protobuf:"varint,4,opt,name=pending_tasks,json=pendingTasks,proto3" json:"pending_tasks,omitempty"`
+	ActiveTasks     int32                  `protobuf:"varint,5,opt,name=active_tasks,json=activeTasks,proto3" json:"active_tasks,omitempty"`
+	// Build version of the daemon ("dev" for untagged builds)
+	Version string `protobuf:"bytes,6,opt,name=version,proto3" json:"version,omitempty"`
+	// Terminal multiplexer hosting agent sessions (e.g. "tmux")
+	Multiplexer string `protobuf:"bytes,7,opt,name=multiplexer,proto3" json:"multiplexer,omitempty"`
+	// Agents waiting for work and agents running a task
+	IdleAgents int32 `protobuf:"varint,8,opt,name=idle_agents,json=idleAgents,proto3" json:"idle_agents,omitempty"`
+	BusyAgents int32 `protobuf:"varint,9,opt,name=busy_agents,json=busyAgents,proto3" json:"busy_agents,omitempty"`
+	// Whether task assignment is paused (see SetSchedulerPaused)
+	SchedulerPaused bool `protobuf:"varint,10,opt,name=scheduler_paused,json=schedulerPaused,proto3" json:"scheduler_paused,omitempty"`
+	// Data directory the daemon stores its database and worktrees in
+	DataDir string `protobuf:"bytes,11,opt,name=data_dir,json=dataDir,proto3" json:"data_dir,omitempty"`
+	// Events dropped since the daemon started because the event channel was
+	// full, and because a WatchEvents client fell behind
+	DroppedEvents        int64 `protobuf:"varint,12,opt,name=dropped_events,json=droppedEvents,proto3" json:"dropped_events,omitempty"`
+	DroppedWatcherEvents int64 `protobuf:"varint,13,opt,name=dropped_watcher_events,json=droppedWatcherEvents,proto3" json:"dropped_watcher_events,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusResponse) ProtoMessage() {}
+
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetStatusResponse) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *GetStatusResponse) GetConnectedAgents() int32 {
+	if x != nil {
+		return x.ConnectedAgents
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetPendingTasks() int32 {
+	if x != nil {
+		return x.PendingTasks
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetActiveTasks() int32 {
+	if x != nil {
+		return x.ActiveTasks
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetMultiplexer() string {
+	if x != nil {
+		return x.Multiplexer
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetIdleAgents() int32 {
+	if x != nil {
+		return x.IdleAgents
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetBusyAgents() int32 {
+	if x != nil {
+		return x.BusyAgents
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetSchedulerPaused() bool {
+	if x != nil {
+		return x.SchedulerPaused
+	}
+	return false
+}
+
+func (x *GetStatusResponse) GetDataDir() string {
+	if x != nil {
+		return x.DataDir
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetDroppedEvents() int64 {
+	if x != nil {
+		return x.DroppedEvents
+	}
+	return 0
+}
+
+func (x *GetStatusResponse) GetDroppedWatcherEvents() int64 {
+	if x != nil {
+		return x.DroppedWatcherEvents
+	}
+	return 0
+}
+
+// SetSchedulerPausedRequest pauses or resumes task assignment. While paused,
+// new and retried tasks stay pending; tasks already assigned carry on.
+type SetSchedulerPausedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSchedulerPausedRequest) Reset() {
+	*x = SetSchedulerPausedRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSchedulerPausedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSchedulerPausedRequest) ProtoMessage() {}
+
+func (x *SetSchedulerPausedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSchedulerPausedRequest.ProtoReflect.Descriptor instead.
+func (*SetSchedulerPausedRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SetSchedulerPausedRequest) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+// SetSchedulerPausedResponse reports the scheduler's state after the request
+type SetSchedulerPausedResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Paused bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// False if the scheduler was already in the requested state
+	Changed       bool `protobuf:"varint,2,opt,name=changed,proto3" json:"changed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetSchedulerPausedResponse) Reset() {
+	*x = SetSchedulerPausedResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetSchedulerPausedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetSchedulerPausedResponse) ProtoMessage() {}
+
+func (x *SetSchedulerPausedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetSchedulerPausedResponse.ProtoReflect.Descriptor instead.
+func (*SetSchedulerPausedResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetSchedulerPausedResponse) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *SetSchedulerPausedResponse) GetChanged() bool {
+	if x != nil {
+		return x.Changed
+	}
+	return false
+}
+
+// PingRequest is a lightweight liveness probe
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{31}
+}
+
+// PingResponse confirms the daemon is serving requests
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PingResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+// WatchEventsRequest configures event streaming
+type WatchEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filter by event types (empty = all events)
+	TypeFilter []EventType `protobuf:"varint,1,rep,packed,name=type_filter,json=typeFilter,proto3,enum=map.v1.EventType" json:"type_filter,omitempty"`
+	// Filter by agent ID
+	AgentFilter string `protobuf:"bytes,2,opt,name=agent_filter,json=agentFilter,proto3" json:"agent_filter,omitempty"`
+	// Filter by task ID
+	TaskFilter string `protobuf:"bytes,3,opt,name=task_filter,json=taskFilter,proto3" json:"task_filter,omitempty"`
+	// Also send agent heartbeat events, which are otherwise only sent when
+	// type_filter names them
+	IncludeHeartbeats bool `protobuf:"varint,4,opt,name=include_heartbeats,json=includeHeartbeats,proto3" json:"include_heartbeats,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *WatchEventsRequest) GetTypeFilter() []EventType {
+	if x != nil {
+		return x.TypeFilter
+	}
+	return nil
+}
+
+func (x *WatchEventsRequest) GetAgentFilter() string {
+	if x != nil {
+		return x.AgentFilter
+	}
+	return ""
+}
+
+func (x *WatchEventsRequest) GetTaskFilter() string {
+	if x != nil {
+		return x.TaskFilter
+	}
+	return ""
+}
+
+func (x *WatchEventsRequest) GetIncludeHeartbeats() bool {
+	if x != nil {
+		return x.IncludeHeartbeats
+	}
+	return false
+}
+
+// StreamLogsRequest tails the daemon's log output
+type StreamLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only send lines at or above this level: info (default), warn, or error
+	MinLevel string `protobuf:"bytes,1,opt,name=min_level,json=minLevel,proto3" json:"min_level,omitempty"`
+	// Number of recent matching lines to send first (0 = none)
+	Tail int32 `protobuf:"varint,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	// Keep streaming new lines until the client disconnects
+	Follow        bool `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLogsRequest) Reset() {
+	*x = StreamLogsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLogsRequest) ProtoMessage() {}
+
+func (x *StreamLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamLogsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *StreamLogsRequest) GetMinLevel() string {
+	if x != nil {
+		return x.MinLevel
+	}
+	return ""
+}
+
+func (x *StreamLogsRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+func (x *StreamLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+// LogEntry is one line of daemon log output
+type LogEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// info, warn, or error, inferred from the message
+	Level         string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_map_v1_daemon_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *LogEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ListEventsRequest queries the persisted event log
+type ListEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Maximum number of events to return (most recent first)
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Filter by event types (empty = all events)
+	TypeFilter []EventType `protobuf:"varint,2,rep,packed,name=type_filter,json=typeFilter,proto3,enum=map.v1.EventType" json:"type_filter,omitempty"`
+	// Only return events at or after this time
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// Only return events about this task
+	TaskId        string `protobuf:"bytes,4,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsRequest) Reset() {
+	*x = ListEventsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsRequest) ProtoMessage() {}
+
+func (x *ListEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListEventsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListEventsRequest) GetTypeFilter() []EventType {
+	if x != nil {
+		return x.TypeFilter
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListEventsRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+// ListEventsResponse returns persisted events, most recent first
+type ListEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventsResponse) Reset() {
+	*x = ListEventsResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventsResponse) ProtoMessage() {}
+
+func (x *ListEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListEventsResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ListEventsResponse) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// SpawnAgentRequest requests spawning Claude Code agents
+type SpawnAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of agents to spawn (default: 1)
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// Git branch for worktrees (empty = current branch)
+	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// Whether to create worktree isolation
+	UseWorktree bool `protobuf:"varint,3,opt,name=use_worktree,json=useWorktree,proto3" json:"use_worktree,omitempty"`
+	// Agent name prefix (default: "claude")
+	NamePrefix string `protobuf:"bytes,4,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// Initial prompt to send to Claude
+	Prompt string `protobuf:"bytes,5,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// Agent type: "claude" (default), "codex", or "gemini"
+	AgentType string `protobuf:"bytes,6,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	// Skip permission prompts so tasks execute immediately without user intervention
+	// For claude: uses --dangerously-skip-permissions
+	// For codex: uses --dangerously-bypass-approvals-and-sandbox
+	// For gemini: uses --yolo
+	// Honored as sent; the CLI defaults it from agent.skip-permissions (true)
+	SkipPermissions bool `protobuf:"varint,7,opt,name=skip_permissions,json=skipPermissions,proto3" json:"skip_permissions,omitempty"`
+	// Working directory - the git repository root to use for worktrees
+	// If empty, uses daemon's current directory
+	WorkingDirectory string `protobuf:"bytes,8,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	// Git identity to configure in each agent's worktree, as text/templates
+	// with {{.AgentID}} available (empty = leave git config untouched)
+	GitUserName  string `protobuf:"bytes,9,opt,name=git_user_name,json=gitUserName,proto3" json:"git_user_name,omitempty"`
+	GitUserEmail string `protobuf:"bytes,10,opt,name=git_user_email,json=gitUserEmail,proto3" json:"git_user_email,omitempty"`
+	// Run agents headless: no terminal multiplexer session; each task runs as
+	// a non-interactive CLI invocation whose output is stored as the result
+	OutputOnly bool `protobuf:"varint,11,opt,name=output_only,json=outputOnly,proto3" json:"output_only,omitempty"`
+	// Key/value labels to tag the agents with (e.g. team=backend)
+	Labels map[string]string `protobuf:"bytes,12,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Milliseconds to wait between starting each agent when count > 1
+	SpawnDelayMs int64 `protobuf:"varint,13,opt,name=spawn_delay_ms,json=spawnDelayMs,proto3" json:"spawn_delay_ms,omitempty"`
+	// Start claude agents with --continue, resuming the most recent
+	// conversation in their working directory (ignored for codex and gemini)
+	ClaudeContinue bool `protobuf:"varint,14,opt,name=claude_continue,json=claudeContinue,proto3" json:"claude_continue,omitempty"`
+	// Environment variables to set in each agent's session, copied from the
+	// invoking CLI's environment (map agent create --copy-env)
+	Env map[string]string `protobuf:"bytes,15,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Offer tasks to the agents to accept or reject rather than assigning
+	// them outright (map task accept/reject)
+	OfferTasks bool `protobuf:"varint,16,opt,name=offer_tasks,json=offerTasks,proto3" json:"offer_tasks,omitempty"`
+	// What happens to each agent once a task it was given reaches a terminal
+	// state: "idle" (default) returns it to the pool, "keep" leaves it
+	// running without giving it more tasks, "kill" removes it and its worktree
+	OnComplete string `protobuf:"bytes,17,opt,name=on_complete,json=onComplete,proto3" json:"on_complete,omitempty"`
+	// Hold each agent for the first task submitted preferring it
+	// (prefer_agent): until then it is given no queued task, so a task
+	// submitted right after the spawn isn't beaten to it by an older one
+	HoldForTask   bool `protobuf:"varint,18,opt,name=hold_for_task,json=holdForTask,proto3" json:"hold_for_task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpawnAgentRequest) Reset() {
+	*x = SpawnAgentRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpawnAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpawnAgentRequest) ProtoMessage() {}
+
+func (x *SpawnAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpawnAgentRequest.ProtoReflect.Descriptor instead.
+func (*SpawnAgentRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SpawnAgentRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SpawnAgentRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetUseWorktree() bool {
+	if x != nil {
+		return x.UseWorktree
+	}
+	return false
+}
+
+func (x *SpawnAgentRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
 }
 
 func (x *SpawnAgentRequest) GetAgentType() string {
 	if x != nil {
-		return x.AgentType
+		return x.AgentType
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetSkipPermissions() bool {
+	if x != nil {
+		return x.SkipPermissions
+	}
+	return false
+}
+
+func (x *SpawnAgentRequest) GetWorkingDirectory() string {
+	if x != nil {
+		return x.WorkingDirectory
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetGitUserName() string {
+	if x != nil {
+		return x.GitUserName
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetGitUserEmail() string {
+	if x != nil {
+		return x.GitUserEmail
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetOutputOnly() bool {
+	if x != nil {
+		return x.OutputOnly
+	}
+	return false
+}
+
+func (x *SpawnAgentRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SpawnAgentRequest) GetSpawnDelayMs() int64 {
+	if x != nil {
+		return x.SpawnDelayMs
+	}
+	return 0
+}
+
+func (x *SpawnAgentRequest) GetClaudeContinue() bool {
+	if x != nil {
+		return x.ClaudeContinue
+	}
+	return false
+}
+
+func (x *SpawnAgentRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *SpawnAgentRequest) GetOfferTasks() bool {
+	if x != nil {
+		return x.OfferTasks
+	}
+	return false
+}
+
+func (x *SpawnAgentRequest) GetOnComplete() string {
+	if x != nil {
+		return x.OnComplete
+	}
+	return ""
+}
+
+func (x *SpawnAgentRequest) GetHoldForTask() bool {
+	if x != nil {
+		return x.HoldForTask
+	}
+	return false
+}
+
+// SpawnAgentResponse returns info about spawned agents
+type SpawnAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*SpawnedAgentInfo    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpawnAgentResponse) Reset() {
+	*x = SpawnAgentResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpawnAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpawnAgentResponse) ProtoMessage() {}
+
+func (x *SpawnAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpawnAgentResponse.ProtoReflect.Descriptor instead.
+func (*SpawnAgentResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SpawnAgentResponse) GetAgents() []*SpawnedAgentInfo {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// SpawnedAgentInfo describes a spawned agent
+type SpawnedAgentInfo struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AgentId      string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	WorktreePath string                 `protobuf:"bytes,2,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
+	Pid          int32                  `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+	Status       string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LogFile      string                 `protobuf:"bytes,6,opt,name=log_file,json=logFile,proto3" json:"log_file,omitempty"`
+	// Agent type: "claude", "codex", or "gemini"
+	AgentType string `protobuf:"bytes,7,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	// Repository root the agent was created from
+	RepoRoot string `protobuf:"bytes,8,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Headless (output-only) agent with no tmux session
+	Headless bool `protobuf:"varint,9,opt,name=headless,proto3" json:"headless,omitempty"`
+	// User-defined key/value labels
+	Labels map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Terminal multiplexer hosting the agent's session, e.g. "tmux" (empty
+	// for headless agents)
+	Multiplexer string `protobuf:"bytes,11,opt,name=multiplexer,proto3" json:"multiplexer,omitempty"`
+	// Tasks are offered to the agent to accept or reject
+	OfferTasks bool `protobuf:"varint,12,opt,name=offer_tasks,json=offerTasks,proto3" json:"offer_tasks,omitempty"`
+	// Lifecycle policy applied when the agent's task finishes: "idle",
+	// "keep", or "kill"
+	OnComplete    string `protobuf:"bytes,13,opt,name=on_complete,json=onComplete,proto3" json:"on_complete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpawnedAgentInfo) Reset() {
+	*x = SpawnedAgentInfo{}
+	mi := &file_map_v1_daemon_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpawnedAgentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpawnedAgentInfo) ProtoMessage() {}
+
+func (x *SpawnedAgentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpawnedAgentInfo.ProtoReflect.Descriptor instead.
+func (*SpawnedAgentInfo) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SpawnedAgentInfo) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetWorktreePath() string {
+	if x != nil {
+		return x.WorktreePath
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *SpawnedAgentInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *SpawnedAgentInfo) GetLogFile() string {
+	if x != nil {
+		return x.LogFile
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetHeadless() bool {
+	if x != nil {
+		return x.Headless
+	}
+	return false
+}
+
+func (x *SpawnedAgentInfo) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SpawnedAgentInfo) GetMultiplexer() string {
+	if x != nil {
+		return x.Multiplexer
+	}
+	return ""
+}
+
+func (x *SpawnedAgentInfo) GetOfferTasks() bool {
+	if x != nil {
+		return x.OfferTasks
+	}
+	return false
+}
+
+func (x *SpawnedAgentInfo) GetOnComplete() string {
+	if x != nil {
+		return x.OnComplete
+	}
+	return ""
+}
+
+// KillAgentRequest requests termination of a spawned agent
+type KillAgentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Force kill (SIGKILL instead of SIGTERM)
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// Put the agent's active tasks back in the queue instead of cancelling them
+	RequeueTasks  bool `protobuf:"varint,3,opt,name=requeue_tasks,json=requeueTasks,proto3" json:"requeue_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillAgentRequest) Reset() {
+	*x = KillAgentRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillAgentRequest) ProtoMessage() {}
+
+func (x *KillAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillAgentRequest.ProtoReflect.Descriptor instead.
+func (*KillAgentRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *KillAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *KillAgentRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *KillAgentRequest) GetRequeueTasks() bool {
+	if x != nil {
+		return x.RequeueTasks
+	}
+	return false
+}
+
+// KillAgentResponse confirms agent termination
+type KillAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KillAgentResponse) Reset() {
+	*x = KillAgentResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KillAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KillAgentResponse) ProtoMessage() {}
+
+func (x *KillAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KillAgentResponse.ProtoReflect.Descriptor instead.
+func (*KillAgentResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *KillAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *KillAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// ListSpawnedAgentsRequest requests list of spawned agents
+type ListSpawnedAgentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional filter by repo root path (only show agents for this repo)
+	RepoRoot string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Optional label selector: only agents with every one of these labels
+	Labels map[string]string `protobuf:"bytes,2,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Only agents whose tmux session no longer exists
+	Stale         bool `protobuf:"varint,3,opt,name=stale,proto3" json:"stale,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSpawnedAgentsRequest) Reset() {
+	*x = ListSpawnedAgentsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSpawnedAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSpawnedAgentsRequest) ProtoMessage() {}
+
+func (x *ListSpawnedAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSpawnedAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListSpawnedAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ListSpawnedAgentsRequest) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *ListSpawnedAgentsRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *ListSpawnedAgentsRequest) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+// ListSpawnedAgentsResponse returns spawned agents
+type ListSpawnedAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*SpawnedAgentInfo    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSpawnedAgentsResponse) Reset() {
+	*x = ListSpawnedAgentsResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSpawnedAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSpawnedAgentsResponse) ProtoMessage() {}
+
+func (x *ListSpawnedAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSpawnedAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListSpawnedAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ListSpawnedAgentsResponse) GetAgents() []*SpawnedAgentInfo {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// RespawnAgentRequest requests restarting claude in a dead agent pane
+type RespawnAgentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Restart the agent even if its pane is still running (e.g. stuck on a fatal error)
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// Restart with permission prompts, and keep that mode for later respawns
+	RequirePermissions bool `protobuf:"varint,3,opt,name=require_permissions,json=requirePermissions,proto3" json:"require_permissions,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *RespawnAgentRequest) Reset() {
+	*x = RespawnAgentRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespawnAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespawnAgentRequest) ProtoMessage() {}
+
+func (x *RespawnAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespawnAgentRequest.ProtoReflect.Descriptor instead.
+func (*RespawnAgentRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RespawnAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *RespawnAgentRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *RespawnAgentRequest) GetRequirePermissions() bool {
+	if x != nil {
+		return x.RequirePermissions
+	}
+	return false
+}
+
+// RespawnAgentResponse confirms respawn
+type RespawnAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespawnAgentResponse) Reset() {
+	*x = RespawnAgentResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespawnAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespawnAgentResponse) ProtoMessage() {}
+
+func (x *RespawnAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespawnAgentResponse.ProtoReflect.Descriptor instead.
+func (*RespawnAgentResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RespawnAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RespawnAgentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// GetAgentHistoryRequest lists the messages exchanged with an agent
+type GetAgentHistoryRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Only the most recent messages (0 = all)
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentHistoryRequest) Reset() {
+	*x = GetAgentHistoryRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentHistoryRequest) ProtoMessage() {}
+
+func (x *GetAgentHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetAgentHistoryRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *GetAgentHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// GetAgentHistoryResponse returns the agent's messages, oldest first
+type GetAgentHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*AgentMessage        `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentHistoryResponse) Reset() {
+	*x = GetAgentHistoryResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentHistoryResponse) ProtoMessage() {}
+
+func (x *GetAgentHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetAgentHistoryResponse) GetMessages() []*AgentMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+// GetAgentUsageRequest samples the CPU and memory used by agents' CLI
+// processes
+type GetAgentUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional: only this agent (default: all agents)
+	AgentId       string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentUsageRequest) Reset() {
+	*x = GetAgentUsageRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentUsageRequest) ProtoMessage() {}
+
+func (x *GetAgentUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentUsageRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetAgentUsageRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// AgentUsage is a point-in-time sample of the processes running in an
+// agent's pane: the CLI and everything it started
+type AgentUsage struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// PID of the pane's process (0 if it isn't running)
+	Pid int32 `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+	// CPU use summed over the processes, in percent of one core
+	CpuPercent float64 `protobuf:"fixed64,3,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	// Resident memory summed over the processes
+	MemoryBytes int64 `protobuf:"varint,4,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	// Number of processes sampled
+	Processes int32 `protobuf:"varint,5,opt,name=processes,proto3" json:"processes,omitempty"`
+	// Why the agent couldn't be sampled, if it couldn't
+	Error         string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentUsage) Reset() {
+	*x = AgentUsage{}
+	mi := &file_map_v1_daemon_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentUsage) ProtoMessage() {}
+
+func (x *AgentUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentUsage.ProtoReflect.Descriptor instead.
+func (*AgentUsage) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *AgentUsage) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentUsage) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetMemoryBytes() int64 {
+	if x != nil {
+		return x.MemoryBytes
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetProcesses() int32 {
+	if x != nil {
+		return x.Processes
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetAgentUsageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Usage         []*AgentUsage          `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentUsageResponse) Reset() {
+	*x = GetAgentUsageResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentUsageResponse) ProtoMessage() {}
+
+func (x *GetAgentUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentUsageResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetAgentUsageResponse) GetUsage() []*AgentUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// StreamAgentOutputRequest follows an agent's pane as plain text lines, for
+// watching without a terminal
+type StreamAgentOutputRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Number of recent pane lines to send first (0 = none)
+	Tail          int32 `protobuf:"varint,2,opt,name=tail,proto3" json:"tail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAgentOutputRequest) Reset() {
+	*x = StreamAgentOutputRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAgentOutputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAgentOutputRequest) ProtoMessage() {}
+
+func (x *StreamAgentOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAgentOutputRequest.ProtoReflect.Descriptor instead.
+func (*StreamAgentOutputRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *StreamAgentOutputRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *StreamAgentOutputRequest) GetTail() int32 {
+	if x != nil {
+		return x.Tail
+	}
+	return 0
+}
+
+// AgentOutputLine is one new or changed line of an agent's pane
+type AgentOutputLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Line          string                 `protobuf:"bytes,2,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentOutputLine) Reset() {
+	*x = AgentOutputLine{}
+	mi := &file_map_v1_daemon_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentOutputLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentOutputLine) ProtoMessage() {}
+
+func (x *AgentOutputLine) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentOutputLine.ProtoReflect.Descriptor instead.
+func (*AgentOutputLine) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AgentOutputLine) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AgentOutputLine) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+// BroadcastMessageRequest sends text to the sessions of many agents at once
+type BroadcastMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Text  string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// Only send to idle agents
+	IdleOnly bool `protobuf:"varint,2,opt,name=idle_only,json=idleOnly,proto3" json:"idle_only,omitempty"`
+	// Only send to agents carrying all of these labels
+	Labels map[string]string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Only send to agents from this repository (empty = any)
+	RepoRoot      string `protobuf:"bytes,4,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastMessageRequest) Reset() {
+	*x = BroadcastMessageRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastMessageRequest) ProtoMessage() {}
+
+func (x *BroadcastMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastMessageRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastMessageRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *BroadcastMessageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *BroadcastMessageRequest) GetIdleOnly() bool {
+	if x != nil {
+		return x.IdleOnly
+	}
+	return false
+}
+
+func (x *BroadcastMessageRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *BroadcastMessageRequest) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+// BroadcastMessageResponse reports what happened for each matching agent
+type BroadcastMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*BroadcastDelivery   `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastMessageResponse) Reset() {
+	*x = BroadcastMessageResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastMessageResponse) ProtoMessage() {}
+
+func (x *BroadcastMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastMessageResponse.ProtoReflect.Descriptor instead.
+func (*BroadcastMessageResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *BroadcastMessageResponse) GetDeliveries() []*BroadcastDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+// BroadcastDelivery is the outcome of a broadcast for one agent
+type BroadcastDelivery struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Why the text was not delivered (empty = delivered)
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastDelivery) Reset() {
+	*x = BroadcastDelivery{}
+	mi := &file_map_v1_daemon_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastDelivery) ProtoMessage() {}
+
+func (x *BroadcastDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastDelivery.ProtoReflect.Descriptor instead.
+func (*BroadcastDelivery) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *BroadcastDelivery) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *BroadcastDelivery) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// ListWorktreesRequest requests list of worktrees
+type ListWorktreesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional filter by repo root path (only show worktrees for this repo)
+	RepoRoot      string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorktreesRequest) Reset() {
+	*x = ListWorktreesRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorktreesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorktreesRequest) ProtoMessage() {}
+
+func (x *ListWorktreesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorktreesRequest.ProtoReflect.Descriptor instead.
+func (*ListWorktreesRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListWorktreesRequest) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+// ListWorktreesResponse returns worktree info
+type ListWorktreesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Worktrees     []*WorktreeInfo        `protobuf:"bytes,1,rep,name=worktrees,proto3" json:"worktrees,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWorktreesResponse) Reset() {
+	*x = ListWorktreesResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWorktreesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWorktreesResponse) ProtoMessage() {}
+
+func (x *ListWorktreesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWorktreesResponse.ProtoReflect.Descriptor instead.
+func (*ListWorktreesResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListWorktreesResponse) GetWorktrees() []*WorktreeInfo {
+	if x != nil {
+		return x.Worktrees
+	}
+	return nil
+}
+
+// WorktreeInfo describes a git worktree
+type WorktreeInfo struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AgentId   string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Path      string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Branch    string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Repository root the worktree was created from
+	RepoRoot      string `protobuf:"bytes,5,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorktreeInfo) Reset() {
+	*x = WorktreeInfo{}
+	mi := &file_map_v1_daemon_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorktreeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorktreeInfo) ProtoMessage() {}
+
+func (x *WorktreeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorktreeInfo.ProtoReflect.Descriptor instead.
+func (*WorktreeInfo) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *WorktreeInfo) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
 	}
 	return ""
 }
 
-func (x *SpawnAgentRequest) GetSkipPermissions() bool {
+func (x *WorktreeInfo) GetPath() string {
 	if x != nil {
-		return x.SkipPermissions
+		return x.Path
+	}
+	return ""
+}
+
+func (x *WorktreeInfo) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *WorktreeInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WorktreeInfo) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+// CreateWorktreeRequest requests a worktree for an agent spawned without one
+type CreateWorktreeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Branch or ref to create the worktree from (empty = repo's current branch)
+	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// Restart the agent's CLI in the new worktree. Otherwise only later
+	// respawns and headless runs use it.
+	Restart bool `protobuf:"varint,3,opt,name=restart,proto3" json:"restart,omitempty"`
+	// Optional git identity templates, as for SpawnAgentRequest
+	GitUserName   string `protobuf:"bytes,4,opt,name=git_user_name,json=gitUserName,proto3" json:"git_user_name,omitempty"`
+	GitUserEmail  string `protobuf:"bytes,5,opt,name=git_user_email,json=gitUserEmail,proto3" json:"git_user_email,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorktreeRequest) Reset() {
+	*x = CreateWorktreeRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorktreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorktreeRequest) ProtoMessage() {}
+
+func (x *CreateWorktreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorktreeRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorktreeRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CreateWorktreeRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CreateWorktreeRequest) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *CreateWorktreeRequest) GetRestart() bool {
+	if x != nil {
+		return x.Restart
 	}
 	return false
 }
 
-func (x *SpawnAgentRequest) GetWorkingDirectory() string {
+func (x *CreateWorktreeRequest) GetGitUserName() string {
 	if x != nil {
-		return x.WorkingDirectory
+		return x.GitUserName
 	}
 	return ""
 }
 
-// SpawnAgentResponse returns info about spawned agents
-type SpawnAgentResponse struct {
+func (x *CreateWorktreeRequest) GetGitUserEmail() string {
+	if x != nil {
+		return x.GitUserEmail
+	}
+	return ""
+}
+
+// CreateWorktreeResponse returns the new worktree
+type CreateWorktreeResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Worktree *WorktreeInfo          `protobuf:"bytes,1,opt,name=worktree,proto3" json:"worktree,omitempty"`
+	// Whether the agent's CLI was restarted in the worktree
+	Restarted     bool `protobuf:"varint,2,opt,name=restarted,proto3" json:"restarted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorktreeResponse) Reset() {
+	*x = CreateWorktreeResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorktreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorktreeResponse) ProtoMessage() {}
+
+func (x *CreateWorktreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorktreeResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorktreeResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CreateWorktreeResponse) GetWorktree() *WorktreeInfo {
+	if x != nil {
+		return x.Worktree
+	}
+	return nil
+}
+
+func (x *CreateWorktreeResponse) GetRestarted() bool {
+	if x != nil {
+		return x.Restarted
+	}
+	return false
+}
+
+// CleanupWorktreesRequest requests worktree cleanup
+type CleanupWorktreesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Specific agent ID to cleanup (empty = all orphaned)
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Remove all worktrees
+	All           bool `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CleanupWorktreesRequest) Reset() {
+	*x = CleanupWorktreesRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CleanupWorktreesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CleanupWorktreesRequest) ProtoMessage() {}
+
+func (x *CleanupWorktreesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CleanupWorktreesRequest.ProtoReflect.Descriptor instead.
+func (*CleanupWorktreesRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *CleanupWorktreesRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *CleanupWorktreesRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+// CleanupWorktreesResponse confirms cleanup
+type CleanupWorktreesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*SpawnedAgentInfo    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	RemovedCount  int32                  `protobuf:"varint,1,opt,name=removed_count,json=removedCount,proto3" json:"removed_count,omitempty"`
+	RemovedPaths  []string               `protobuf:"bytes,2,rep,name=removed_paths,json=removedPaths,proto3" json:"removed_paths,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpawnAgentResponse) Reset() {
-	*x = SpawnAgentResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[14]
+func (x *CleanupWorktreesResponse) Reset() {
+	*x = CleanupWorktreesResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpawnAgentResponse) String() string {
+func (x *CleanupWorktreesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpawnAgentResponse) ProtoMessage() {}
+func (*CleanupWorktreesResponse) ProtoMessage() {}
 
-func (x *SpawnAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[14]
+func (x *CleanupWorktreesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -876,50 +3829,51 @@ func (x *SpawnAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpawnAgentResponse.ProtoReflect.Descriptor instead.
-func (*SpawnAgentResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use CleanupWorktreesResponse.ProtoReflect.Descriptor instead.
+func (*CleanupWorktreesResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *SpawnAgentResponse) GetAgents() []*SpawnedAgentInfo {
+func (x *CleanupWorktreesResponse) GetRemovedCount() int32 {
 	if x != nil {
-		return x.Agents
+		return x.RemovedCount
+	}
+	return 0
+}
+
+func (x *CleanupWorktreesResponse) GetRemovedPaths() []string {
+	if x != nil {
+		return x.RemovedPaths
 	}
 	return nil
 }
 
-// SpawnedAgentInfo describes a spawned agent
-type SpawnedAgentInfo struct {
-	state        protoimpl.MessageState `protogen:"open.v1"`
-	AgentId      string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	WorktreePath string                 `protobuf:"bytes,2,opt,name=worktree_path,json=worktreePath,proto3" json:"worktree_path,omitempty"`
-	Pid          int32                  `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
-	Status       string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	CreatedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	LogFile      string                 `protobuf:"bytes,6,opt,name=log_file,json=logFile,proto3" json:"log_file,omitempty"`
-	// Agent type: "claude" or "codex"
-	AgentType string `protobuf:"bytes,7,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
-	// Repository root the agent was created from
-	RepoRoot      string `protobuf:"bytes,8,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+// PruneBranchesRequest requests deletion of leftover map/<agent-id> branches
+type PruneBranchesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Repository to prune branches in (empty = daemon's repo)
+	RepoRoot string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Report which branches would be deleted without deleting them
+	DryRun        bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpawnedAgentInfo) Reset() {
-	*x = SpawnedAgentInfo{}
-	mi := &file_map_v1_daemon_proto_msgTypes[15]
+func (x *PruneBranchesRequest) Reset() {
+	*x = PruneBranchesRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpawnedAgentInfo) String() string {
+func (x *PruneBranchesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SpawnedAgentInfo) ProtoMessage() {}
+func (*PruneBranchesRequest) ProtoMessage() {}
 
-func (x *SpawnedAgentInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[15]
+func (x *PruneBranchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,92 +3884,94 @@ func (x *SpawnedAgentInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SpawnedAgentInfo.ProtoReflect.Descriptor instead.
-func (*SpawnedAgentInfo) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use PruneBranchesRequest.ProtoReflect.Descriptor instead.
+func (*PruneBranchesRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *SpawnedAgentInfo) GetAgentId() string {
+func (x *PruneBranchesRequest) GetRepoRoot() string {
 	if x != nil {
-		return x.AgentId
+		return x.RepoRoot
 	}
 	return ""
 }
 
-func (x *SpawnedAgentInfo) GetWorktreePath() string {
+func (x *PruneBranchesRequest) GetDryRun() bool {
 	if x != nil {
-		return x.WorktreePath
+		return x.DryRun
 	}
-	return ""
+	return false
 }
 
-func (x *SpawnedAgentInfo) GetPid() int32 {
-	if x != nil {
-		return x.Pid
-	}
-	return 0
+// PruneBranchesResponse lists the pruned (or prunable, for dry runs) branches
+type PruneBranchesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Branches      []string               `protobuf:"bytes,1,rep,name=branches,proto3" json:"branches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SpawnedAgentInfo) GetStatus() string {
-	if x != nil {
-		return x.Status
-	}
-	return ""
+func (x *PruneBranchesResponse) Reset() {
+	*x = PruneBranchesResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *SpawnedAgentInfo) GetCreatedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.CreatedAt
-	}
-	return nil
+func (x *PruneBranchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *SpawnedAgentInfo) GetLogFile() string {
+func (*PruneBranchesResponse) ProtoMessage() {}
+
+func (x *PruneBranchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[65]
 	if x != nil {
-		return x.LogFile
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *SpawnedAgentInfo) GetAgentType() string {
-	if x != nil {
-		return x.AgentType
-	}
-	return ""
+// Deprecated: Use PruneBranchesResponse.ProtoReflect.Descriptor instead.
+func (*PruneBranchesResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *SpawnedAgentInfo) GetRepoRoot() string {
+func (x *PruneBranchesResponse) GetBranches() []string {
 	if x != nil {
-		return x.RepoRoot
+		return x.Branches
 	}
-	return ""
+	return nil
 }
 
-// KillAgentRequest requests termination of a spawned agent
-type KillAgentRequest struct {
-	state   protoimpl.MessageState `protogen:"open.v1"`
-	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	// Force kill (SIGKILL instead of SIGTERM)
-	Force         bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+// RequestInputRequest signals that an agent needs user input
+type RequestInputRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Question      string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KillAgentRequest) Reset() {
-	*x = KillAgentRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[16]
+func (x *RequestInputRequest) Reset() {
+	*x = RequestInputRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KillAgentRequest) String() string {
+func (x *RequestInputRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KillAgentRequest) ProtoMessage() {}
+func (*RequestInputRequest) ProtoMessage() {}
 
-func (x *KillAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[16]
+func (x *RequestInputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,27 +3982,27 @@ func (x *KillAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KillAgentRequest.ProtoReflect.Descriptor instead.
-func (*KillAgentRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use RequestInputRequest.ProtoReflect.Descriptor instead.
+func (*RequestInputRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *KillAgentRequest) GetAgentId() string {
+func (x *RequestInputRequest) GetTaskId() string {
 	if x != nil {
-		return x.AgentId
+		return x.TaskId
 	}
 	return ""
 }
 
-func (x *KillAgentRequest) GetForce() bool {
+func (x *RequestInputRequest) GetQuestion() string {
 	if x != nil {
-		return x.Force
+		return x.Question
 	}
-	return false
+	return ""
 }
 
-// KillAgentResponse confirms agent termination
-type KillAgentResponse struct {
+// RequestInputResponse confirms the input request was processed
+type RequestInputResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
@@ -1054,21 +4010,21 @@ type KillAgentResponse struct {
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KillAgentResponse) Reset() {
-	*x = KillAgentResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[17]
+func (x *RequestInputResponse) Reset() {
+	*x = RequestInputResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[67]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *KillAgentResponse) String() string {
+func (x *RequestInputResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KillAgentResponse) ProtoMessage() {}
+func (*RequestInputResponse) ProtoMessage() {}
 
-func (x *KillAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[17]
+func (x *RequestInputResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[67]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1079,49 +4035,48 @@ func (x *KillAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KillAgentResponse.ProtoReflect.Descriptor instead.
-func (*KillAgentResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use RequestInputResponse.ProtoReflect.Descriptor instead.
+func (*RequestInputResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *KillAgentResponse) GetSuccess() bool {
+func (x *RequestInputResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *KillAgentResponse) GetMessage() string {
+func (x *RequestInputResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-// ListSpawnedAgentsRequest requests list of spawned agents
-type ListSpawnedAgentsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional filter by repo root path (only show agents for this repo)
-	RepoRoot      string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// GetCurrentTaskRequest looks up the task for a working directory
+type GetCurrentTaskRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	WorkingDirectory string                 `protobuf:"bytes,1,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ListSpawnedAgentsRequest) Reset() {
-	*x = ListSpawnedAgentsRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[18]
+func (x *GetCurrentTaskRequest) Reset() {
+	*x = GetCurrentTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSpawnedAgentsRequest) String() string {
+func (x *GetCurrentTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSpawnedAgentsRequest) ProtoMessage() {}
+func (*GetCurrentTaskRequest) ProtoMessage() {}
 
-func (x *ListSpawnedAgentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[18]
+func (x *GetCurrentTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1132,41 +4087,41 @@ func (x *ListSpawnedAgentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSpawnedAgentsRequest.ProtoReflect.Descriptor instead.
-func (*ListSpawnedAgentsRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use GetCurrentTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *ListSpawnedAgentsRequest) GetRepoRoot() string {
+func (x *GetCurrentTaskRequest) GetWorkingDirectory() string {
 	if x != nil {
-		return x.RepoRoot
+		return x.WorkingDirectory
 	}
 	return ""
 }
 
-// ListSpawnedAgentsResponse returns spawned agents
-type ListSpawnedAgentsResponse struct {
+// GetCurrentTaskResponse returns the task for the working directory
+type GetCurrentTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*SpawnedAgentInfo    `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSpawnedAgentsResponse) Reset() {
-	*x = ListSpawnedAgentsResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[19]
+func (x *GetCurrentTaskResponse) Reset() {
+	*x = GetCurrentTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSpawnedAgentsResponse) String() string {
+func (x *GetCurrentTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSpawnedAgentsResponse) ProtoMessage() {}
+func (*GetCurrentTaskResponse) ProtoMessage() {}
 
-func (x *ListSpawnedAgentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[19]
+func (x *GetCurrentTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1177,41 +4132,41 @@ func (x *ListSpawnedAgentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSpawnedAgentsResponse.ProtoReflect.Descriptor instead.
-func (*ListSpawnedAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use GetCurrentTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrentTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *ListSpawnedAgentsResponse) GetAgents() []*SpawnedAgentInfo {
+func (x *GetCurrentTaskResponse) GetTask() *Task {
 	if x != nil {
-		return x.Agents
+		return x.Task
 	}
 	return nil
 }
 
-// RespawnAgentRequest requests restarting claude in a dead agent pane
-type RespawnAgentRequest struct {
+// GetAgentTaskRequest looks up the active task of an agent
+type GetAgentTaskRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RespawnAgentRequest) Reset() {
-	*x = RespawnAgentRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[20]
+func (x *GetAgentTaskRequest) Reset() {
+	*x = GetAgentTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RespawnAgentRequest) String() string {
+func (x *GetAgentTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RespawnAgentRequest) ProtoMessage() {}
+func (*GetAgentTaskRequest) ProtoMessage() {}
 
-func (x *RespawnAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[20]
+func (x *GetAgentTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1222,42 +4177,41 @@ func (x *RespawnAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RespawnAgentRequest.ProtoReflect.Descriptor instead.
-func (*RespawnAgentRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use GetAgentTaskRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *RespawnAgentRequest) GetAgentId() string {
+func (x *GetAgentTaskRequest) GetAgentId() string {
 	if x != nil {
 		return x.AgentId
 	}
 	return ""
 }
 
-// RespawnAgentResponse confirms respawn
-type RespawnAgentResponse struct {
+// GetAgentTaskResponse returns the agent's active task, unset if it has none
+type GetAgentTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RespawnAgentResponse) Reset() {
-	*x = RespawnAgentResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[21]
+func (x *GetAgentTaskResponse) Reset() {
+	*x = GetAgentTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RespawnAgentResponse) String() string {
+func (x *GetAgentTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RespawnAgentResponse) ProtoMessage() {}
+func (*GetAgentTaskResponse) ProtoMessage() {}
 
-func (x *RespawnAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[21]
+func (x *GetAgentTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1268,49 +4222,44 @@ func (x *RespawnAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RespawnAgentResponse.ProtoReflect.Descriptor instead.
-func (*RespawnAgentResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{21}
-}
-
-func (x *RespawnAgentResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use GetAgentTaskResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *RespawnAgentResponse) GetMessage() string {
+func (x *GetAgentTaskResponse) GetTask() *Task {
 	if x != nil {
-		return x.Message
+		return x.Task
 	}
-	return ""
+	return nil
 }
 
-// ListWorktreesRequest requests list of worktrees
-type ListWorktreesRequest struct {
+// ListRunsRequest lists runs, newest first
+type ListRunsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional filter by repo root path (only show worktrees for this repo)
-	RepoRoot      string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Optional filter by repo root path
+	RepoRoot string `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// Limit number of results (0 = no limit)
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListWorktreesRequest) Reset() {
-	*x = ListWorktreesRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[22]
+func (x *ListRunsRequest) Reset() {
+	*x = ListRunsRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListWorktreesRequest) String() string {
+func (x *ListRunsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListWorktreesRequest) ProtoMessage() {}
+func (*ListRunsRequest) ProtoMessage() {}
 
-func (x *ListWorktreesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[22]
+func (x *ListRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1321,41 +4270,48 @@ func (x *ListWorktreesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListWorktreesRequest.ProtoReflect.Descriptor instead.
-func (*ListWorktreesRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use ListRunsRequest.ProtoReflect.Descriptor instead.
+func (*ListRunsRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *ListWorktreesRequest) GetRepoRoot() string {
+func (x *ListRunsRequest) GetRepoRoot() string {
 	if x != nil {
 		return x.RepoRoot
 	}
 	return ""
 }
 
-// ListWorktreesResponse returns worktree info
-type ListWorktreesResponse struct {
+func (x *ListRunsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// ListRunsResponse returns a summary of each run
+type ListRunsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Worktrees     []*WorktreeInfo        `protobuf:"bytes,1,rep,name=worktrees,proto3" json:"worktrees,omitempty"`
+	Runs          []*RunSummary          `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListWorktreesResponse) Reset() {
-	*x = ListWorktreesResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[23]
+func (x *ListRunsResponse) Reset() {
+	*x = ListRunsResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListWorktreesResponse) String() string {
+func (x *ListRunsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListWorktreesResponse) ProtoMessage() {}
+func (*ListRunsResponse) ProtoMessage() {}
 
-func (x *ListWorktreesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[23]
+func (x *ListRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1366,46 +4322,41 @@ func (x *ListWorktreesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListWorktreesResponse.ProtoReflect.Descriptor instead.
-func (*ListWorktreesResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use ListRunsResponse.ProtoReflect.Descriptor instead.
+func (*ListRunsResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *ListWorktreesResponse) GetWorktrees() []*WorktreeInfo {
+func (x *ListRunsResponse) GetRuns() []*RunSummary {
 	if x != nil {
-		return x.Worktrees
+		return x.Runs
 	}
 	return nil
 }
 
-// WorktreeInfo describes a git worktree
-type WorktreeInfo struct {
-	state     protoimpl.MessageState `protogen:"open.v1"`
-	AgentId   string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Path      string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
-	Branch    string                 `protobuf:"bytes,3,opt,name=branch,proto3" json:"branch,omitempty"`
-	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	// Repository root the worktree was created from
-	RepoRoot      string `protobuf:"bytes,5,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+// GetRunRequest looks up a run by ID
+type GetRunRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WorktreeInfo) Reset() {
-	*x = WorktreeInfo{}
-	mi := &file_map_v1_daemon_proto_msgTypes[24]
+func (x *GetRunRequest) Reset() {
+	*x = GetRunRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WorktreeInfo) String() string {
+func (x *GetRunRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorktreeInfo) ProtoMessage() {}
+func (*GetRunRequest) ProtoMessage() {}
 
-func (x *WorktreeInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[24]
+func (x *GetRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1416,72 +4367,98 @@ func (x *WorktreeInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorktreeInfo.ProtoReflect.Descriptor instead.
-func (*WorktreeInfo) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use GetRunRequest.ProtoReflect.Descriptor instead.
+func (*GetRunRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *WorktreeInfo) GetAgentId() string {
+func (x *GetRunRequest) GetRunId() string {
 	if x != nil {
-		return x.AgentId
+		return x.RunId
 	}
 	return ""
 }
 
-func (x *WorktreeInfo) GetPath() string {
-	if x != nil {
-		return x.Path
-	}
-	return ""
+// GetRunResponse returns a run's summary and its tasks, oldest first
+type GetRunResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Run           *RunSummary            `protobuf:"bytes,1,opt,name=run,proto3" json:"run,omitempty"`
+	Tasks         []*Task                `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WorktreeInfo) GetBranch() string {
+func (x *GetRunResponse) Reset() {
+	*x = GetRunResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRunResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRunResponse) ProtoMessage() {}
+
+func (x *GetRunResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[75]
 	if x != nil {
-		return x.Branch
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *WorktreeInfo) GetCreatedAt() *timestamppb.Timestamp {
+// Deprecated: Use GetRunResponse.ProtoReflect.Descriptor instead.
+func (*GetRunResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *GetRunResponse) GetRun() *RunSummary {
 	if x != nil {
-		return x.CreatedAt
+		return x.Run
 	}
 	return nil
 }
 
-func (x *WorktreeInfo) GetRepoRoot() string {
+func (x *GetRunResponse) GetTasks() []*Task {
 	if x != nil {
-		return x.RepoRoot
+		return x.Tasks
 	}
-	return ""
+	return nil
 }
 
-// CleanupWorktreesRequest requests worktree cleanup
-type CleanupWorktreesRequest struct {
+// CreateScheduledTaskRequest creates a recurring task
+type CreateScheduledTaskRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Specific agent ID to cleanup (empty = all orphaned)
-	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	// Remove all worktrees
-	All           bool `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	// Standard five-field cron expression, e.g. "0 9 * * 1"
+	Cron          string   `protobuf:"bytes,1,opt,name=cron,proto3" json:"cron,omitempty"`
+	Description   string   `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	ScopePaths    []string `protobuf:"bytes,3,rep,name=scope_paths,json=scopePaths,proto3" json:"scope_paths,omitempty"`
+	RepoRoot      string   `protobuf:"bytes,4,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupWorktreesRequest) Reset() {
-	*x = CleanupWorktreesRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[25]
+func (x *CreateScheduledTaskRequest) Reset() {
+	*x = CreateScheduledTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupWorktreesRequest) String() string {
+func (x *CreateScheduledTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupWorktreesRequest) ProtoMessage() {}
+func (*CreateScheduledTaskRequest) ProtoMessage() {}
 
-func (x *CleanupWorktreesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[25]
+func (x *CreateScheduledTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1492,49 +4469,61 @@ func (x *CleanupWorktreesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupWorktreesRequest.ProtoReflect.Descriptor instead.
-func (*CleanupWorktreesRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{25}
+// Deprecated: Use CreateScheduledTaskRequest.ProtoReflect.Descriptor instead.
+func (*CreateScheduledTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *CleanupWorktreesRequest) GetAgentId() string {
+func (x *CreateScheduledTaskRequest) GetCron() string {
 	if x != nil {
-		return x.AgentId
+		return x.Cron
 	}
 	return ""
 }
 
-func (x *CleanupWorktreesRequest) GetAll() bool {
+func (x *CreateScheduledTaskRequest) GetDescription() string {
 	if x != nil {
-		return x.All
+		return x.Description
 	}
-	return false
+	return ""
 }
 
-// CleanupWorktreesResponse confirms cleanup
-type CleanupWorktreesResponse struct {
+func (x *CreateScheduledTaskRequest) GetScopePaths() []string {
+	if x != nil {
+		return x.ScopePaths
+	}
+	return nil
+}
+
+func (x *CreateScheduledTaskRequest) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+type CreateScheduledTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RemovedCount  int32                  `protobuf:"varint,1,opt,name=removed_count,json=removedCount,proto3" json:"removed_count,omitempty"`
-	RemovedPaths  []string               `protobuf:"bytes,2,rep,name=removed_paths,json=removedPaths,proto3" json:"removed_paths,omitempty"`
+	Schedule      *ScheduledTask         `protobuf:"bytes,1,opt,name=schedule,proto3" json:"schedule,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CleanupWorktreesResponse) Reset() {
-	*x = CleanupWorktreesResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[26]
+func (x *CreateScheduledTaskResponse) Reset() {
+	*x = CreateScheduledTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[77]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CleanupWorktreesResponse) String() string {
+func (x *CreateScheduledTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CleanupWorktreesResponse) ProtoMessage() {}
+func (*CreateScheduledTaskResponse) ProtoMessage() {}
 
-func (x *CleanupWorktreesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[26]
+func (x *CreateScheduledTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[77]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1545,49 +4534,39 @@ func (x *CleanupWorktreesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CleanupWorktreesResponse.ProtoReflect.Descriptor instead.
-func (*CleanupWorktreesResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{26}
-}
-
-func (x *CleanupWorktreesResponse) GetRemovedCount() int32 {
-	if x != nil {
-		return x.RemovedCount
-	}
-	return 0
+// Deprecated: Use CreateScheduledTaskResponse.ProtoReflect.Descriptor instead.
+func (*CreateScheduledTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{77}
 }
 
-func (x *CleanupWorktreesResponse) GetRemovedPaths() []string {
+func (x *CreateScheduledTaskResponse) GetSchedule() *ScheduledTask {
 	if x != nil {
-		return x.RemovedPaths
+		return x.Schedule
 	}
 	return nil
 }
 
-// RequestInputRequest signals that an agent needs user input
-type RequestInputRequest struct {
+type ListScheduledTasksRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	Question      string                 `protobuf:"bytes,2,opt,name=question,proto3" json:"question,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RequestInputRequest) Reset() {
-	*x = RequestInputRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[27]
+func (x *ListScheduledTasksRequest) Reset() {
+	*x = ListScheduledTasksRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RequestInputRequest) String() string {
+func (x *ListScheduledTasksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestInputRequest) ProtoMessage() {}
+func (*ListScheduledTasksRequest) ProtoMessage() {}
 
-func (x *RequestInputRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[27]
+func (x *ListScheduledTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1598,49 +4577,33 @@ func (x *RequestInputRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestInputRequest.ProtoReflect.Descriptor instead.
-func (*RequestInputRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{27}
-}
-
-func (x *RequestInputRequest) GetTaskId() string {
-	if x != nil {
-		return x.TaskId
-	}
-	return ""
-}
-
-func (x *RequestInputRequest) GetQuestion() string {
-	if x != nil {
-		return x.Question
-	}
-	return ""
+// Deprecated: Use ListScheduledTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledTasksRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{78}
 }
 
-// RequestInputResponse confirms the input request was processed
-type RequestInputResponse struct {
+type ListScheduledTasksResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Schedules     []*ScheduledTask       `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RequestInputResponse) Reset() {
-	*x = RequestInputResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[28]
+func (x *ListScheduledTasksResponse) Reset() {
+	*x = ListScheduledTasksResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RequestInputResponse) String() string {
+func (x *ListScheduledTasksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RequestInputResponse) ProtoMessage() {}
+func (*ListScheduledTasksResponse) ProtoMessage() {}
 
-func (x *RequestInputResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[28]
+func (x *ListScheduledTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1651,48 +4614,42 @@ func (x *RequestInputResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RequestInputResponse.ProtoReflect.Descriptor instead.
-func (*RequestInputResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{28}
-}
-
-func (x *RequestInputResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use ListScheduledTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledTasksResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *RequestInputResponse) GetMessage() string {
+func (x *ListScheduledTasksResponse) GetSchedules() []*ScheduledTask {
 	if x != nil {
-		return x.Message
+		return x.Schedules
 	}
-	return ""
+	return nil
 }
 
-// GetCurrentTaskRequest looks up the task for a working directory
-type GetCurrentTaskRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	WorkingDirectory string                 `protobuf:"bytes,1,opt,name=working_directory,json=workingDirectory,proto3" json:"working_directory,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// DeleteScheduledTaskRequest removes a recurring task. Tasks it already
+// submitted are kept.
+type DeleteScheduledTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCurrentTaskRequest) Reset() {
-	*x = GetCurrentTaskRequest{}
-	mi := &file_map_v1_daemon_proto_msgTypes[29]
+func (x *DeleteScheduledTaskRequest) Reset() {
+	*x = DeleteScheduledTaskRequest{}
+	mi := &file_map_v1_daemon_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCurrentTaskRequest) String() string {
+func (x *DeleteScheduledTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCurrentTaskRequest) ProtoMessage() {}
+func (*DeleteScheduledTaskRequest) ProtoMessage() {}
 
-func (x *GetCurrentTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[29]
+func (x *DeleteScheduledTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1703,41 +4660,39 @@ func (x *GetCurrentTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCurrentTaskRequest.ProtoReflect.Descriptor instead.
-func (*GetCurrentTaskRequest) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use DeleteScheduledTaskRequest.ProtoReflect.Descriptor instead.
+func (*DeleteScheduledTaskRequest) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *GetCurrentTaskRequest) GetWorkingDirectory() string {
+func (x *DeleteScheduledTaskRequest) GetScheduleId() string {
 	if x != nil {
-		return x.WorkingDirectory
+		return x.ScheduleId
 	}
 	return ""
 }
 
-// GetCurrentTaskResponse returns the task for the working directory
-type GetCurrentTaskResponse struct {
+type DeleteScheduledTaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Task          *Task                  `protobuf:"bytes,1,opt,name=task,proto3" json:"task,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCurrentTaskResponse) Reset() {
-	*x = GetCurrentTaskResponse{}
-	mi := &file_map_v1_daemon_proto_msgTypes[30]
+func (x *DeleteScheduledTaskResponse) Reset() {
+	*x = DeleteScheduledTaskResponse{}
+	mi := &file_map_v1_daemon_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCurrentTaskResponse) String() string {
+func (x *DeleteScheduledTaskResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCurrentTaskResponse) ProtoMessage() {}
+func (*DeleteScheduledTaskResponse) ProtoMessage() {}
 
-func (x *GetCurrentTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_daemon_proto_msgTypes[30]
+func (x *DeleteScheduledTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_daemon_proto_msgTypes[81]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1748,23 +4703,16 @@ func (x *GetCurrentTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCurrentTaskResponse.ProtoReflect.Descriptor instead.
-func (*GetCurrentTaskResponse) Descriptor() ([]byte, []int) {
-	return file_map_v1_daemon_proto_rawDescGZIP(), []int{30}
-}
-
-func (x *GetCurrentTaskResponse) GetTask() *Task {
-	if x != nil {
-		return x.Task
-	}
-	return nil
+// Deprecated: Use DeleteScheduledTaskResponse.ProtoReflect.Descriptor instead.
+func (*DeleteScheduledTaskResponse) Descriptor() ([]byte, []int) {
+	return file_map_v1_daemon_proto_rawDescGZIP(), []int{81}
 }
 
 var File_map_v1_daemon_proto protoreflect.FileDescriptor
 
 const file_map_v1_daemon_proto_rawDesc = "" +
 	"\n" +
-	"\x13map/v1/daemon.proto\x12\x06map.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x12map/v1/types.proto\"\x8f\x02\n" +
+	"\x13map/v1/daemon.proto\x12\x06map.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x12map/v1/types.proto\"\x89\x03\n" +
 	"\x11SubmitTaskRequest\x12 \n" +
 	"\vdescription\x18\x01 \x01(\tR\vdescription\x12\x1f\n" +
 	"\vscope_paths\x18\x02 \x03(\tR\n" +
@@ -1774,14 +4722,22 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\vgithub_repo\x18\x05 \x01(\tR\n" +
 	"githubRepo\x12.\n" +
 	"\x13github_issue_number\x18\x06 \x01(\x05R\x11githubIssueNumber\x12\x1b\n" +
-	"\trepo_root\x18\a \x01(\tR\brepoRoot\"6\n" +
+	"\trepo_root\x18\a \x01(\tR\brepoRoot\x12\x15\n" +
+	"\x06run_id\x18\b \x01(\tR\x05runId\x12!\n" +
+	"\fprefer_agent\x18\t \x01(\tR\vpreferAgent\x12\x1f\n" +
+	"\vno_worktree\x18\n" +
+	" \x01(\bR\n" +
+	"noWorktree\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\v \x01(\tR\tagentType\"6\n" +
 	"\x12SubmitTaskResponse\x12 \n" +
-	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"\xa1\x01\n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"\xcc\x01\n" +
 	"\x10ListTasksRequest\x127\n" +
 	"\rstatus_filter\x18\x01 \x01(\x0e2\x12.map.v1.TaskStatusR\fstatusFilter\x12!\n" +
 	"\fagent_filter\x18\x02 \x01(\tR\vagentFilter\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1b\n" +
-	"\trepo_root\x18\x04 \x01(\tR\brepoRoot\"7\n" +
+	"\trepo_root\x18\x04 \x01(\tR\brepoRoot\x12)\n" +
+	"\x10include_archived\x18\x05 \x01(\bR\x0fincludeArchived\"7\n" +
 	"\x11ListTasksResponse\x12\"\n" +
 	"\x05tasks\x18\x01 \x03(\v2\f.map.v1.TaskR\x05tasks\")\n" +
 	"\x0eGetTaskRequest\x12\x17\n" +
@@ -1791,25 +4747,106 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\x11CancelTaskRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\"6\n" +
 	"\x12CancelTaskResponse\x12 \n" +
-	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"'\n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"\x99\x01\n" +
+	"\x10RetryTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"all_failed\x18\x02 \x01(\bR\tallFailed\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x1b\n" +
+	"\trepo_root\x18\x04 \x01(\tR\brepoRoot\"c\n" +
+	"\x11RetryTaskResponse\x12&\n" +
+	"\aretried\x18\x01 \x03(\v2\f.map.v1.TaskR\aretried\x12&\n" +
+	"\askipped\x18\x02 \x03(\v2\f.map.v1.TaskR\askipped\",\n" +
+	"\x11ReopenTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"6\n" +
+	"\x12ReopenTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"-\n" +
+	"\x12ArchiveTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"7\n" +
+	"\x13ArchiveTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"\x14\n" +
+	"\x12ExportTasksRequest\"V\n" +
+	"\x12ImportTasksRequest\x12\"\n" +
+	"\x05tasks\x18\x01 \x03(\v2\f.map.v1.TaskR\x05tasks\x12\x1c\n" +
+	"\toverwrite\x18\x02 \x01(\bR\toverwrite\"R\n" +
+	"\x13ImportTasksResponse\x12\x1a\n" +
+	"\bimported\x18\x01 \x01(\x05R\bimported\x12\x1f\n" +
+	"\vskipped_ids\x18\x02 \x03(\tR\n" +
+	"skippedIds\"+\n" +
+	"\x10NudgeTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"5\n" +
+	"\x11NudgeTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"G\n" +
+	"\x11AcceptTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"6\n" +
+	"\x12AcceptTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"_\n" +
+	"\x11RejectTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\"6\n" +
+	"\x12RejectTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"2\n" +
+	"\x17ListTaskAttemptsRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\"K\n" +
+	"\x18ListTaskAttemptsResponse\x12/\n" +
+	"\battempts\x18\x01 \x03(\v2\x13.map.v1.TaskAttemptR\battempts\"'\n" +
 	"\x0fShutdownRequest\x12\x14\n" +
 	"\x05force\x18\x01 \x01(\bR\x05force\",\n" +
 	"\x10ShutdownResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"\x12\n" +
-	"\x10GetStatusRequest\"\xdb\x01\n" +
+	"\x10GetStatusRequest\"\xfc\x03\n" +
 	"\x11GetStatusResponse\x12\x18\n" +
 	"\arunning\x18\x01 \x01(\bR\arunning\x129\n" +
 	"\n" +
 	"started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12)\n" +
 	"\x10connected_agents\x18\x03 \x01(\x05R\x0fconnectedAgents\x12#\n" +
 	"\rpending_tasks\x18\x04 \x01(\x05R\fpendingTasks\x12!\n" +
-	"\factive_tasks\x18\x05 \x01(\x05R\vactiveTasks\"\x8c\x01\n" +
+	"\factive_tasks\x18\x05 \x01(\x05R\vactiveTasks\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\tR\aversion\x12 \n" +
+	"\vmultiplexer\x18\a \x01(\tR\vmultiplexer\x12\x1f\n" +
+	"\vidle_agents\x18\b \x01(\x05R\n" +
+	"idleAgents\x12\x1f\n" +
+	"\vbusy_agents\x18\t \x01(\x05R\n" +
+	"busyAgents\x12)\n" +
+	"\x10scheduler_paused\x18\n" +
+	" \x01(\bR\x0fschedulerPaused\x12\x19\n" +
+	"\bdata_dir\x18\v \x01(\tR\adataDir\x12%\n" +
+	"\x0edropped_events\x18\f \x01(\x03R\rdroppedEvents\x124\n" +
+	"\x16dropped_watcher_events\x18\r \x01(\x03R\x14droppedWatcherEvents\"3\n" +
+	"\x19SetSchedulerPausedRequest\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\"N\n" +
+	"\x1aSetSchedulerPausedResponse\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\x12\x18\n" +
+	"\achanged\x18\x02 \x01(\bR\achanged\"\r\n" +
+	"\vPingRequest\"K\n" +
+	"\fPingResponse\x12;\n" +
+	"\vserver_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\"\xbb\x01\n" +
 	"\x12WatchEventsRequest\x122\n" +
 	"\vtype_filter\x18\x01 \x03(\x0e2\x11.map.v1.EventTypeR\n" +
 	"typeFilter\x12!\n" +
 	"\fagent_filter\x18\x02 \x01(\tR\vagentFilter\x12\x1f\n" +
 	"\vtask_filter\x18\x03 \x01(\tR\n" +
-	"taskFilter\"\x94\x02\n" +
+	"taskFilter\x12-\n" +
+	"\x12include_heartbeats\x18\x04 \x01(\bR\x11includeHeartbeats\"\\\n" +
+	"\x11StreamLogsRequest\x12\x1b\n" +
+	"\tmin_level\x18\x01 \x01(\tR\bminLevel\x12\x12\n" +
+	"\x04tail\x18\x02 \x01(\x05R\x04tail\x12\x16\n" +
+	"\x06follow\x18\x03 \x01(\bR\x06follow\"t\n" +
+	"\bLogEntry\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xa8\x01\n" +
+	"\x11ListEventsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x122\n" +
+	"\vtype_filter\x18\x02 \x03(\x0e2\x11.map.v1.EventTypeR\n" +
+	"typeFilter\x120\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x17\n" +
+	"\atask_id\x18\x04 \x01(\tR\x06taskId\";\n" +
+	"\x12ListEventsResponse\x12%\n" +
+	"\x06events\x18\x01 \x03(\v2\r.map.v1.EventR\x06events\"\x9c\x06\n" +
 	"\x11SpawnAgentRequest\x12\x14\n" +
 	"\x05count\x18\x01 \x01(\x05R\x05count\x12\x16\n" +
 	"\x06branch\x18\x02 \x01(\tR\x06branch\x12!\n" +
@@ -1820,9 +4857,29 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\n" +
 	"agent_type\x18\x06 \x01(\tR\tagentType\x12)\n" +
 	"\x10skip_permissions\x18\a \x01(\bR\x0fskipPermissions\x12+\n" +
-	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\"F\n" +
+	"\x11working_directory\x18\b \x01(\tR\x10workingDirectory\x12\"\n" +
+	"\rgit_user_name\x18\t \x01(\tR\vgitUserName\x12$\n" +
+	"\x0egit_user_email\x18\n" +
+	" \x01(\tR\fgitUserEmail\x12\x1f\n" +
+	"\voutput_only\x18\v \x01(\bR\n" +
+	"outputOnly\x12=\n" +
+	"\x06labels\x18\f \x03(\v2%.map.v1.SpawnAgentRequest.LabelsEntryR\x06labels\x12$\n" +
+	"\x0espawn_delay_ms\x18\r \x01(\x03R\fspawnDelayMs\x12'\n" +
+	"\x0fclaude_continue\x18\x0e \x01(\bR\x0eclaudeContinue\x124\n" +
+	"\x03env\x18\x0f \x03(\v2\".map.v1.SpawnAgentRequest.EnvEntryR\x03env\x12\x1f\n" +
+	"\voffer_tasks\x18\x10 \x01(\bR\n" +
+	"offerTasks\x12\x1f\n" +
+	"\von_complete\x18\x11 \x01(\tR\n" +
+	"onComplete\x12\"\n" +
+	"\rhold_for_task\x18\x12 \x01(\bR\vholdForTask\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"F\n" +
 	"\x12SpawnAgentResponse\x120\n" +
-	"\x06agents\x18\x01 \x03(\v2\x18.map.v1.SpawnedAgentInfoR\x06agents\"\x8e\x02\n" +
+	"\x06agents\x18\x01 \x03(\v2\x18.map.v1.SpawnedAgentInfoR\x06agents\"\x87\x04\n" +
 	"\x10SpawnedAgentInfo\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12#\n" +
 	"\rworktree_path\x18\x02 \x01(\tR\fworktreePath\x12\x10\n" +
@@ -1833,22 +4890,80 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\blog_file\x18\x06 \x01(\tR\alogFile\x12\x1d\n" +
 	"\n" +
 	"agent_type\x18\a \x01(\tR\tagentType\x12\x1b\n" +
-	"\trepo_root\x18\b \x01(\tR\brepoRoot\"C\n" +
+	"\trepo_root\x18\b \x01(\tR\brepoRoot\x12\x1a\n" +
+	"\bheadless\x18\t \x01(\bR\bheadless\x12<\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2$.map.v1.SpawnedAgentInfo.LabelsEntryR\x06labels\x12 \n" +
+	"\vmultiplexer\x18\v \x01(\tR\vmultiplexer\x12\x1f\n" +
+	"\voffer_tasks\x18\f \x01(\bR\n" +
+	"offerTasks\x12\x1f\n" +
+	"\von_complete\x18\r \x01(\tR\n" +
+	"onComplete\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
 	"\x10KillAgentRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
-	"\x05force\x18\x02 \x01(\bR\x05force\"G\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\x12#\n" +
+	"\rrequeue_tasks\x18\x03 \x01(\bR\frequeueTasks\"G\n" +
 	"\x11KillAgentResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"7\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xce\x01\n" +
 	"\x18ListSpawnedAgentsRequest\x12\x1b\n" +
-	"\trepo_root\x18\x01 \x01(\tR\brepoRoot\"M\n" +
+	"\trepo_root\x18\x01 \x01(\tR\brepoRoot\x12D\n" +
+	"\x06labels\x18\x02 \x03(\v2,.map.v1.ListSpawnedAgentsRequest.LabelsEntryR\x06labels\x12\x14\n" +
+	"\x05stale\x18\x03 \x01(\bR\x05stale\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"M\n" +
 	"\x19ListSpawnedAgentsResponse\x120\n" +
-	"\x06agents\x18\x01 \x03(\v2\x18.map.v1.SpawnedAgentInfoR\x06agents\"0\n" +
+	"\x06agents\x18\x01 \x03(\v2\x18.map.v1.SpawnedAgentInfoR\x06agents\"w\n" +
 	"\x13RespawnAgentRequest\x12\x19\n" +
-	"\bagent_id\x18\x01 \x01(\tR\aagentId\"J\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05force\x18\x02 \x01(\bR\x05force\x12/\n" +
+	"\x13require_permissions\x18\x03 \x01(\bR\x12requirePermissions\"J\n" +
 	"\x14RespawnAgentResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"3\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"I\n" +
+	"\x16GetAgentHistoryRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"K\n" +
+	"\x17GetAgentHistoryResponse\x120\n" +
+	"\bmessages\x18\x01 \x03(\v2\x14.map.v1.AgentMessageR\bmessages\"1\n" +
+	"\x14GetAgentUsageRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xb1\x01\n" +
+	"\n" +
+	"AgentUsage\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x10\n" +
+	"\x03pid\x18\x02 \x01(\x05R\x03pid\x12\x1f\n" +
+	"\vcpu_percent\x18\x03 \x01(\x01R\n" +
+	"cpuPercent\x12!\n" +
+	"\fmemory_bytes\x18\x04 \x01(\x03R\vmemoryBytes\x12\x1c\n" +
+	"\tprocesses\x18\x05 \x01(\x05R\tprocesses\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"A\n" +
+	"\x15GetAgentUsageResponse\x12(\n" +
+	"\x05usage\x18\x01 \x03(\v2\x12.map.v1.AgentUsageR\x05usage\"I\n" +
+	"\x18StreamAgentOutputRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x12\n" +
+	"\x04tail\x18\x02 \x01(\x05R\x04tail\"_\n" +
+	"\x0fAgentOutputLine\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\tR\x04line\"\xe7\x01\n" +
+	"\x17BroadcastMessageRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1b\n" +
+	"\tidle_only\x18\x02 \x01(\bR\bidleOnly\x12C\n" +
+	"\x06labels\x18\x03 \x03(\v2+.map.v1.BroadcastMessageRequest.LabelsEntryR\x06labels\x12\x1b\n" +
+	"\trepo_root\x18\x04 \x01(\tR\brepoRoot\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"U\n" +
+	"\x18BroadcastMessageResponse\x129\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x19.map.v1.BroadcastDeliveryR\n" +
+	"deliveries\"D\n" +
+	"\x11BroadcastDelivery\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"3\n" +
 	"\x14ListWorktreesRequest\x12\x1b\n" +
 	"\trepo_root\x18\x01 \x01(\tR\brepoRoot\"K\n" +
 	"\x15ListWorktreesResponse\x122\n" +
@@ -1859,13 +4974,27 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\x06branch\x18\x03 \x01(\tR\x06branch\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
-	"\trepo_root\x18\x05 \x01(\tR\brepoRoot\"F\n" +
+	"\trepo_root\x18\x05 \x01(\tR\brepoRoot\"\xae\x01\n" +
+	"\x15CreateWorktreeRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06branch\x18\x02 \x01(\tR\x06branch\x12\x18\n" +
+	"\arestart\x18\x03 \x01(\bR\arestart\x12\"\n" +
+	"\rgit_user_name\x18\x04 \x01(\tR\vgitUserName\x12$\n" +
+	"\x0egit_user_email\x18\x05 \x01(\tR\fgitUserEmail\"h\n" +
+	"\x16CreateWorktreeResponse\x120\n" +
+	"\bworktree\x18\x01 \x01(\v2\x14.map.v1.WorktreeInfoR\bworktree\x12\x1c\n" +
+	"\trestarted\x18\x02 \x01(\bR\trestarted\"F\n" +
 	"\x17CleanupWorktreesRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x10\n" +
 	"\x03all\x18\x02 \x01(\bR\x03all\"d\n" +
 	"\x18CleanupWorktreesResponse\x12#\n" +
 	"\rremoved_count\x18\x01 \x01(\x05R\fremovedCount\x12#\n" +
-	"\rremoved_paths\x18\x02 \x03(\tR\fremovedPaths\"J\n" +
+	"\rremoved_paths\x18\x02 \x03(\tR\fremovedPaths\"L\n" +
+	"\x14PruneBranchesRequest\x12\x1b\n" +
+	"\trepo_root\x18\x01 \x01(\tR\brepoRoot\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"3\n" +
+	"\x15PruneBranchesResponse\x12\x1a\n" +
+	"\bbranches\x18\x01 \x03(\tR\bbranches\"J\n" +
 	"\x13RequestInputRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1a\n" +
 	"\bquestion\x18\x02 \x01(\tR\bquestion\"J\n" +
@@ -1875,26 +5004,85 @@ const file_map_v1_daemon_proto_rawDesc = "" +
 	"\x15GetCurrentTaskRequest\x12+\n" +
 	"\x11working_directory\x18\x01 \x01(\tR\x10workingDirectory\":\n" +
 	"\x16GetCurrentTaskResponse\x12 \n" +
-	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task2\xc1\b\n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"0\n" +
+	"\x13GetAgentTaskRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"8\n" +
+	"\x14GetAgentTaskResponse\x12 \n" +
+	"\x04task\x18\x01 \x01(\v2\f.map.v1.TaskR\x04task\"D\n" +
+	"\x0fListRunsRequest\x12\x1b\n" +
+	"\trepo_root\x18\x01 \x01(\tR\brepoRoot\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\":\n" +
+	"\x10ListRunsResponse\x12&\n" +
+	"\x04runs\x18\x01 \x03(\v2\x12.map.v1.RunSummaryR\x04runs\"&\n" +
+	"\rGetRunRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\"Z\n" +
+	"\x0eGetRunResponse\x12$\n" +
+	"\x03run\x18\x01 \x01(\v2\x12.map.v1.RunSummaryR\x03run\x12\"\n" +
+	"\x05tasks\x18\x02 \x03(\v2\f.map.v1.TaskR\x05tasks\"\x90\x01\n" +
+	"\x1aCreateScheduledTaskRequest\x12\x12\n" +
+	"\x04cron\x18\x01 \x01(\tR\x04cron\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vscope_paths\x18\x03 \x03(\tR\n" +
+	"scopePaths\x12\x1b\n" +
+	"\trepo_root\x18\x04 \x01(\tR\brepoRoot\"P\n" +
+	"\x1bCreateScheduledTaskResponse\x121\n" +
+	"\bschedule\x18\x01 \x01(\v2\x15.map.v1.ScheduledTaskR\bschedule\"\x1b\n" +
+	"\x19ListScheduledTasksRequest\"Q\n" +
+	"\x1aListScheduledTasksResponse\x123\n" +
+	"\tschedules\x18\x01 \x03(\v2\x15.map.v1.ScheduledTaskR\tschedules\"=\n" +
+	"\x1aDeleteScheduledTaskRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\"\x1d\n" +
+	"\x1bDeleteScheduledTaskResponse2\x94\x17\n" +
 	"\rDaemonService\x12C\n" +
 	"\n" +
 	"SubmitTask\x12\x19.map.v1.SubmitTaskRequest\x1a\x1a.map.v1.SubmitTaskResponse\x12@\n" +
 	"\tListTasks\x12\x18.map.v1.ListTasksRequest\x1a\x19.map.v1.ListTasksResponse\x12:\n" +
 	"\aGetTask\x12\x16.map.v1.GetTaskRequest\x1a\x17.map.v1.GetTaskResponse\x12C\n" +
 	"\n" +
-	"CancelTask\x12\x19.map.v1.CancelTaskRequest\x1a\x1a.map.v1.CancelTaskResponse\x12I\n" +
+	"CancelTask\x12\x19.map.v1.CancelTaskRequest\x1a\x1a.map.v1.CancelTaskResponse\x12@\n" +
+	"\tRetryTask\x12\x18.map.v1.RetryTaskRequest\x1a\x19.map.v1.RetryTaskResponse\x12C\n" +
+	"\n" +
+	"ReopenTask\x12\x19.map.v1.ReopenTaskRequest\x1a\x1a.map.v1.ReopenTaskResponse\x12F\n" +
+	"\vArchiveTask\x12\x1a.map.v1.ArchiveTaskRequest\x1a\x1b.map.v1.ArchiveTaskResponse\x129\n" +
+	"\vExportTasks\x12\x1a.map.v1.ExportTasksRequest\x1a\f.map.v1.Task0\x01\x12H\n" +
+	"\vImportTasks\x12\x1a.map.v1.ImportTasksRequest\x1a\x1b.map.v1.ImportTasksResponse(\x01\x12@\n" +
+	"\tNudgeTask\x12\x18.map.v1.NudgeTaskRequest\x1a\x19.map.v1.NudgeTaskResponse\x12C\n" +
+	"\n" +
+	"AcceptTask\x12\x19.map.v1.AcceptTaskRequest\x1a\x1a.map.v1.AcceptTaskResponse\x12C\n" +
+	"\n" +
+	"RejectTask\x12\x19.map.v1.RejectTaskRequest\x1a\x1a.map.v1.RejectTaskResponse\x12U\n" +
+	"\x10ListTaskAttempts\x12\x1f.map.v1.ListTaskAttemptsRequest\x1a .map.v1.ListTaskAttemptsResponse\x12I\n" +
 	"\fRequestInput\x12\x1b.map.v1.RequestInputRequest\x1a\x1c.map.v1.RequestInputResponse\x12O\n" +
-	"\x0eGetCurrentTask\x12\x1d.map.v1.GetCurrentTaskRequest\x1a\x1e.map.v1.GetCurrentTaskResponse\x12=\n" +
+	"\x0eGetCurrentTask\x12\x1d.map.v1.GetCurrentTaskRequest\x1a\x1e.map.v1.GetCurrentTaskResponse\x12I\n" +
+	"\fGetAgentTask\x12\x1b.map.v1.GetAgentTaskRequest\x1a\x1c.map.v1.GetAgentTaskResponse\x12=\n" +
+	"\bListRuns\x12\x17.map.v1.ListRunsRequest\x1a\x18.map.v1.ListRunsResponse\x127\n" +
+	"\x06GetRun\x12\x15.map.v1.GetRunRequest\x1a\x16.map.v1.GetRunResponse\x12^\n" +
+	"\x13CreateScheduledTask\x12\".map.v1.CreateScheduledTaskRequest\x1a#.map.v1.CreateScheduledTaskResponse\x12[\n" +
+	"\x12ListScheduledTasks\x12!.map.v1.ListScheduledTasksRequest\x1a\".map.v1.ListScheduledTasksResponse\x12^\n" +
+	"\x13DeleteScheduledTask\x12\".map.v1.DeleteScheduledTaskRequest\x1a#.map.v1.DeleteScheduledTaskResponse\x12=\n" +
 	"\bShutdown\x12\x17.map.v1.ShutdownRequest\x1a\x18.map.v1.ShutdownResponse\x12@\n" +
-	"\tGetStatus\x12\x18.map.v1.GetStatusRequest\x1a\x19.map.v1.GetStatusResponse\x12:\n" +
+	"\tGetStatus\x12\x18.map.v1.GetStatusRequest\x1a\x19.map.v1.GetStatusResponse\x121\n" +
+	"\x04Ping\x12\x13.map.v1.PingRequest\x1a\x14.map.v1.PingResponse\x12[\n" +
+	"\x12SetSchedulerPaused\x12!.map.v1.SetSchedulerPausedRequest\x1a\".map.v1.SetSchedulerPausedResponse\x12:\n" +
 	"\vWatchEvents\x12\x1a.map.v1.WatchEventsRequest\x1a\r.map.v1.Event0\x01\x12C\n" +
 	"\n" +
+	"ListEvents\x12\x19.map.v1.ListEventsRequest\x1a\x1a.map.v1.ListEventsResponse\x12;\n" +
+	"\n" +
+	"StreamLogs\x12\x19.map.v1.StreamLogsRequest\x1a\x10.map.v1.LogEntry0\x01\x12C\n" +
+	"\n" +
 	"SpawnAgent\x12\x19.map.v1.SpawnAgentRequest\x1a\x1a.map.v1.SpawnAgentResponse\x12@\n" +
 	"\tKillAgent\x12\x18.map.v1.KillAgentRequest\x1a\x19.map.v1.KillAgentResponse\x12X\n" +
 	"\x11ListSpawnedAgents\x12 .map.v1.ListSpawnedAgentsRequest\x1a!.map.v1.ListSpawnedAgentsResponse\x12I\n" +
-	"\fRespawnAgent\x12\x1b.map.v1.RespawnAgentRequest\x1a\x1c.map.v1.RespawnAgentResponse\x12L\n" +
-	"\rListWorktrees\x12\x1c.map.v1.ListWorktreesRequest\x1a\x1d.map.v1.ListWorktreesResponse\x12U\n" +
-	"\x10CleanupWorktrees\x12\x1f.map.v1.CleanupWorktreesRequest\x1a .map.v1.CleanupWorktreesResponseB1Z/github.com/pmarsceill/mapcli/proto/map/v1;mapv1b\x06proto3"
+	"\fRespawnAgent\x12\x1b.map.v1.RespawnAgentRequest\x1a\x1c.map.v1.RespawnAgentResponse\x12R\n" +
+	"\x0fGetAgentHistory\x12\x1e.map.v1.GetAgentHistoryRequest\x1a\x1f.map.v1.GetAgentHistoryResponse\x12P\n" +
+	"\x11StreamAgentOutput\x12 .map.v1.StreamAgentOutputRequest\x1a\x17.map.v1.AgentOutputLine0\x01\x12L\n" +
+	"\rGetAgentUsage\x12\x1c.map.v1.GetAgentUsageRequest\x1a\x1d.map.v1.GetAgentUsageResponse\x12U\n" +
+	"\x10BroadcastMessage\x12\x1f.map.v1.BroadcastMessageRequest\x1a .map.v1.BroadcastMessageResponse\x12L\n" +
+	"\rListWorktrees\x12\x1c.map.v1.ListWorktreesRequest\x1a\x1d.map.v1.ListWorktreesResponse\x12O\n" +
+	"\x0eCreateWorktree\x12\x1d.map.v1.CreateWorktreeRequest\x1a\x1e.map.v1.CreateWorktreeResponse\x12U\n" +
+	"\x10CleanupWorktrees\x12\x1f.map.v1.CleanupWorktreesRequest\x1a .map.v1.CleanupWorktreesResponse\x12L\n" +
+	"\rPruneBranches\x12\x1c.map.v1.PruneBranchesRequest\x1a\x1d.map.v1.PruneBranchesResponseB1Z/github.com/pmarsceill/mapcli/proto/map/v1;mapv1b\x06proto3"
 
 var (
 	file_map_v1_daemon_proto_rawDescOnce sync.Once
@@ -1908,94 +5096,235 @@ func file_map_v1_daemon_proto_rawDescGZIP() []byte {
 	return file_map_v1_daemon_proto_rawDescData
 }
 
-var file_map_v1_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 31)
+var file_map_v1_daemon_proto_msgTypes = make([]protoimpl.MessageInfo, 87)
 var file_map_v1_daemon_proto_goTypes = []any{
-	(*SubmitTaskRequest)(nil),         // 0: map.v1.SubmitTaskRequest
-	(*SubmitTaskResponse)(nil),        // 1: map.v1.SubmitTaskResponse
-	(*ListTasksRequest)(nil),          // 2: map.v1.ListTasksRequest
-	(*ListTasksResponse)(nil),         // 3: map.v1.ListTasksResponse
-	(*GetTaskRequest)(nil),            // 4: map.v1.GetTaskRequest
-	(*GetTaskResponse)(nil),           // 5: map.v1.GetTaskResponse
-	(*CancelTaskRequest)(nil),         // 6: map.v1.CancelTaskRequest
-	(*CancelTaskResponse)(nil),        // 7: map.v1.CancelTaskResponse
-	(*ShutdownRequest)(nil),           // 8: map.v1.ShutdownRequest
-	(*ShutdownResponse)(nil),          // 9: map.v1.ShutdownResponse
-	(*GetStatusRequest)(nil),          // 10: map.v1.GetStatusRequest
-	(*GetStatusResponse)(nil),         // 11: map.v1.GetStatusResponse
-	(*WatchEventsRequest)(nil),        // 12: map.v1.WatchEventsRequest
-	(*SpawnAgentRequest)(nil),         // 13: map.v1.SpawnAgentRequest
-	(*SpawnAgentResponse)(nil),        // 14: map.v1.SpawnAgentResponse
-	(*SpawnedAgentInfo)(nil),          // 15: map.v1.SpawnedAgentInfo
-	(*KillAgentRequest)(nil),          // 16: map.v1.KillAgentRequest
-	(*KillAgentResponse)(nil),         // 17: map.v1.KillAgentResponse
-	(*ListSpawnedAgentsRequest)(nil),  // 18: map.v1.ListSpawnedAgentsRequest
-	(*ListSpawnedAgentsResponse)(nil), // 19: map.v1.ListSpawnedAgentsResponse
-	(*RespawnAgentRequest)(nil),       // 20: map.v1.RespawnAgentRequest
-	(*RespawnAgentResponse)(nil),      // 21: map.v1.RespawnAgentResponse
-	(*ListWorktreesRequest)(nil),      // 22: map.v1.ListWorktreesRequest
-	(*ListWorktreesResponse)(nil),     // 23: map.v1.ListWorktreesResponse
-	(*WorktreeInfo)(nil),              // 24: map.v1.WorktreeInfo
-	(*CleanupWorktreesRequest)(nil),   // 25: map.v1.CleanupWorktreesRequest
-	(*CleanupWorktreesResponse)(nil),  // 26: map.v1.CleanupWorktreesResponse
-	(*RequestInputRequest)(nil),       // 27: map.v1.RequestInputRequest
-	(*RequestInputResponse)(nil),      // 28: map.v1.RequestInputResponse
-	(*GetCurrentTaskRequest)(nil),     // 29: map.v1.GetCurrentTaskRequest
-	(*GetCurrentTaskResponse)(nil),    // 30: map.v1.GetCurrentTaskResponse
-	(*Task)(nil),                      // 31: map.v1.Task
-	(TaskStatus)(0),                   // 32: map.v1.TaskStatus
-	(*timestamppb.Timestamp)(nil),     // 33: google.protobuf.Timestamp
-	(EventType)(0),                    // 34: map.v1.EventType
-	(*Event)(nil),                     // 35: map.v1.Event
+	(*SubmitTaskRequest)(nil),           // 0: map.v1.SubmitTaskRequest
+	(*SubmitTaskResponse)(nil),          // 1: map.v1.SubmitTaskResponse
+	(*ListTasksRequest)(nil),            // 2: map.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),           // 3: map.v1.ListTasksResponse
+	(*GetTaskRequest)(nil),              // 4: map.v1.GetTaskRequest
+	(*GetTaskResponse)(nil),             // 5: map.v1.GetTaskResponse
+	(*CancelTaskRequest)(nil),           // 6: map.v1.CancelTaskRequest
+	(*CancelTaskResponse)(nil),          // 7: map.v1.CancelTaskResponse
+	(*RetryTaskRequest)(nil),            // 8: map.v1.RetryTaskRequest
+	(*RetryTaskResponse)(nil),           // 9: map.v1.RetryTaskResponse
+	(*ReopenTaskRequest)(nil),           // 10: map.v1.ReopenTaskRequest
+	(*ReopenTaskResponse)(nil),          // 11: map.v1.ReopenTaskResponse
+	(*ArchiveTaskRequest)(nil),          // 12: map.v1.ArchiveTaskRequest
+	(*ArchiveTaskResponse)(nil),         // 13: map.v1.ArchiveTaskResponse
+	(*ExportTasksRequest)(nil),          // 14: map.v1.ExportTasksRequest
+	(*ImportTasksRequest)(nil),          // 15: map.v1.ImportTasksRequest
+	(*ImportTasksResponse)(nil),         // 16: map.v1.ImportTasksResponse
+	(*NudgeTaskRequest)(nil),            // 17: map.v1.NudgeTaskRequest
+	(*NudgeTaskResponse)(nil),           // 18: map.v1.NudgeTaskResponse
+	(*AcceptTaskRequest)(nil),           // 19: map.v1.AcceptTaskRequest
+	(*AcceptTaskResponse)(nil),          // 20: map.v1.AcceptTaskResponse
+	(*RejectTaskRequest)(nil),           // 21: map.v1.RejectTaskRequest
+	(*RejectTaskResponse)(nil),          // 22: map.v1.RejectTaskResponse
+	(*ListTaskAttemptsRequest)(nil),     // 23: map.v1.ListTaskAttemptsRequest
+	(*ListTaskAttemptsResponse)(nil),    // 24: map.v1.ListTaskAttemptsResponse
+	(*ShutdownRequest)(nil),             // 25: map.v1.ShutdownRequest
+	(*ShutdownResponse)(nil),            // 26: map.v1.ShutdownResponse
+	(*GetStatusRequest)(nil),            // 27: map.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),           // 28: map.v1.GetStatusResponse
+	(*SetSchedulerPausedRequest)(nil),   // 29: map.v1.SetSchedulerPausedRequest
+	(*SetSchedulerPausedResponse)(nil),  // 30: map.v1.SetSchedulerPausedResponse
+	(*PingRequest)(nil),                 // 31: map.v1.PingRequest
+	(*PingResponse)(nil),                // 32: map.v1.PingResponse
+	(*WatchEventsRequest)(nil),          // 33: map.v1.WatchEventsRequest
+	(*StreamLogsRequest)(nil),           // 34: map.v1.StreamLogsRequest
+	(*LogEntry)(nil),                    // 35: map.v1.LogEntry
+	(*ListEventsRequest)(nil),           // 36: map.v1.ListEventsRequest
+	(*ListEventsResponse)(nil),          // 37: map.v1.ListEventsResponse
+	(*SpawnAgentRequest)(nil),           // 38: map.v1.SpawnAgentRequest
+	(*SpawnAgentResponse)(nil),          // 39: map.v1.SpawnAgentResponse
+	(*SpawnedAgentInfo)(nil),            // 40: map.v1.SpawnedAgentInfo
+	(*KillAgentRequest)(nil),            // 41: map.v1.KillAgentRequest
+	(*KillAgentResponse)(nil),           // 42: map.v1.KillAgentResponse
+	(*ListSpawnedAgentsRequest)(nil),    // 43: map.v1.ListSpawnedAgentsRequest
+	(*ListSpawnedAgentsResponse)(nil),   // 44: map.v1.ListSpawnedAgentsResponse
+	(*RespawnAgentRequest)(nil),         // 45: map.v1.RespawnAgentRequest
+	(*RespawnAgentResponse)(nil),        // 46: map.v1.RespawnAgentResponse
+	(*GetAgentHistoryRequest)(nil),      // 47: map.v1.GetAgentHistoryRequest
+	(*GetAgentHistoryResponse)(nil),     // 48: map.v1.GetAgentHistoryResponse
+	(*GetAgentUsageRequest)(nil),        // 49: map.v1.GetAgentUsageRequest
+	(*AgentUsage)(nil),                  // 50: map.v1.AgentUsage
+	(*GetAgentUsageResponse)(nil),       // 51: map.v1.GetAgentUsageResponse
+	(*StreamAgentOutputRequest)(nil),    // 52: map.v1.StreamAgentOutputRequest
+	(*AgentOutputLine)(nil),             // 53: map.v1.AgentOutputLine
+	(*BroadcastMessageRequest)(nil),     // 54: map.v1.BroadcastMessageRequest
+	(*BroadcastMessageResponse)(nil),    // 55: map.v1.BroadcastMessageResponse
+	(*BroadcastDelivery)(nil),           // 56: map.v1.BroadcastDelivery
+	(*ListWorktreesRequest)(nil),        // 57: map.v1.ListWorktreesRequest
+	(*ListWorktreesResponse)(nil),       // 58: map.v1.ListWorktreesResponse
+	(*WorktreeInfo)(nil),                // 59: map.v1.WorktreeInfo
+	(*CreateWorktreeRequest)(nil),       // 60: map.v1.CreateWorktreeRequest
+	(*CreateWorktreeResponse)(nil),      // 61: map.v1.CreateWorktreeResponse
+	(*CleanupWorktreesRequest)(nil),     // 62: map.v1.CleanupWorktreesRequest
+	(*CleanupWorktreesResponse)(nil),    // 63: map.v1.CleanupWorktreesResponse
+	(*PruneBranchesRequest)(nil),        // 64: map.v1.PruneBranchesRequest
+	(*PruneBranchesResponse)(nil),       // 65: map.v1.PruneBranchesResponse
+	(*RequestInputRequest)(nil),         // 66: map.v1.RequestInputRequest
+	(*RequestInputResponse)(nil),        // 67: map.v1.RequestInputResponse
+	(*GetCurrentTaskRequest)(nil),       // 68: map.v1.GetCurrentTaskRequest
+	(*GetCurrentTaskResponse)(nil),      // 69: map.v1.GetCurrentTaskResponse
+	(*GetAgentTaskRequest)(nil),         // 70: map.v1.GetAgentTaskRequest
+	(*GetAgentTaskResponse)(nil),        // 71: map.v1.GetAgentTaskResponse
+	(*ListRunsRequest)(nil),             // 72: map.v1.ListRunsRequest
+	(*ListRunsResponse)(nil),            // 73: map.v1.ListRunsResponse
+	(*GetRunRequest)(nil),               // 74: map.v1.GetRunRequest
+	(*GetRunResponse)(nil),              // 75: map.v1.GetRunResponse
+	(*CreateScheduledTaskRequest)(nil),  // 76: map.v1.CreateScheduledTaskRequest
+	(*CreateScheduledTaskResponse)(nil), // 77: map.v1.CreateScheduledTaskResponse
+	(*ListScheduledTasksRequest)(nil),   // 78: map.v1.ListScheduledTasksRequest
+	(*ListScheduledTasksResponse)(nil),  // 79: map.v1.ListScheduledTasksResponse
+	(*DeleteScheduledTaskRequest)(nil),  // 80: map.v1.DeleteScheduledTaskRequest
+	(*DeleteScheduledTaskResponse)(nil), // 81: map.v1.DeleteScheduledTaskResponse
+	nil,                                 // 82: map.v1.SpawnAgentRequest.LabelsEntry
+	nil,                                 // 83: map.v1.SpawnAgentRequest.EnvEntry
+	nil,                                 // 84: map.v1.SpawnedAgentInfo.LabelsEntry
+	nil,                                 // 85: map.v1.ListSpawnedAgentsRequest.LabelsEntry
+	nil,                                 // 86: map.v1.BroadcastMessageRequest.LabelsEntry
+	(*Task)(nil),                        // 87: map.v1.Task
+	(TaskStatus)(0),                     // 88: map.v1.TaskStatus
+	(*timestamppb.Timestamp)(nil),       // 89: google.protobuf.Timestamp
+	(*TaskAttempt)(nil),                 // 90: map.v1.TaskAttempt
+	(EventType)(0),                      // 91: map.v1.EventType
+	(*Event)(nil),                       // 92: map.v1.Event
+	(*AgentMessage)(nil),                // 93: map.v1.AgentMessage
+	(*RunSummary)(nil),                  // 94: map.v1.RunSummary
+	(*ScheduledTask)(nil),               // 95: map.v1.ScheduledTask
 }
 var file_map_v1_daemon_proto_depIdxs = []int32{
-	31, // 0: map.v1.SubmitTaskResponse.task:type_name -> map.v1.Task
-	32, // 1: map.v1.ListTasksRequest.status_filter:type_name -> map.v1.TaskStatus
-	31, // 2: map.v1.ListTasksResponse.tasks:type_name -> map.v1.Task
-	31, // 3: map.v1.GetTaskResponse.task:type_name -> map.v1.Task
-	31, // 4: map.v1.CancelTaskResponse.task:type_name -> map.v1.Task
-	33, // 5: map.v1.GetStatusResponse.started_at:type_name -> google.protobuf.Timestamp
-	34, // 6: map.v1.WatchEventsRequest.type_filter:type_name -> map.v1.EventType
-	15, // 7: map.v1.SpawnAgentResponse.agents:type_name -> map.v1.SpawnedAgentInfo
-	33, // 8: map.v1.SpawnedAgentInfo.created_at:type_name -> google.protobuf.Timestamp
-	15, // 9: map.v1.ListSpawnedAgentsResponse.agents:type_name -> map.v1.SpawnedAgentInfo
-	24, // 10: map.v1.ListWorktreesResponse.worktrees:type_name -> map.v1.WorktreeInfo
-	33, // 11: map.v1.WorktreeInfo.created_at:type_name -> google.protobuf.Timestamp
-	31, // 12: map.v1.GetCurrentTaskResponse.task:type_name -> map.v1.Task
-	0,  // 13: map.v1.DaemonService.SubmitTask:input_type -> map.v1.SubmitTaskRequest
-	2,  // 14: map.v1.DaemonService.ListTasks:input_type -> map.v1.ListTasksRequest
-	4,  // 15: map.v1.DaemonService.GetTask:input_type -> map.v1.GetTaskRequest
-	6,  // 16: map.v1.DaemonService.CancelTask:input_type -> map.v1.CancelTaskRequest
-	27, // 17: map.v1.DaemonService.RequestInput:input_type -> map.v1.RequestInputRequest
-	29, // 18: map.v1.DaemonService.GetCurrentTask:input_type -> map.v1.GetCurrentTaskRequest
-	8,  // 19: map.v1.DaemonService.Shutdown:input_type -> map.v1.ShutdownRequest
-	10, // 20: map.v1.DaemonService.GetStatus:input_type -> map.v1.GetStatusRequest
-	12, // 21: map.v1.DaemonService.WatchEvents:input_type -> map.v1.WatchEventsRequest
-	13, // 22: map.v1.DaemonService.SpawnAgent:input_type -> map.v1.SpawnAgentRequest
-	16, // 23: map.v1.DaemonService.KillAgent:input_type -> map.v1.KillAgentRequest
-	18, // 24: map.v1.DaemonService.ListSpawnedAgents:input_type -> map.v1.ListSpawnedAgentsRequest
-	20, // 25: map.v1.DaemonService.RespawnAgent:input_type -> map.v1.RespawnAgentRequest
-	22, // 26: map.v1.DaemonService.ListWorktrees:input_type -> map.v1.ListWorktreesRequest
-	25, // 27: map.v1.DaemonService.CleanupWorktrees:input_type -> map.v1.CleanupWorktreesRequest
-	1,  // 28: map.v1.DaemonService.SubmitTask:output_type -> map.v1.SubmitTaskResponse
-	3,  // 29: map.v1.DaemonService.ListTasks:output_type -> map.v1.ListTasksResponse
-	5,  // 30: map.v1.DaemonService.GetTask:output_type -> map.v1.GetTaskResponse
-	7,  // 31: map.v1.DaemonService.CancelTask:output_type -> map.v1.CancelTaskResponse
-	28, // 32: map.v1.DaemonService.RequestInput:output_type -> map.v1.RequestInputResponse
-	30, // 33: map.v1.DaemonService.GetCurrentTask:output_type -> map.v1.GetCurrentTaskResponse
-	9,  // 34: map.v1.DaemonService.Shutdown:output_type -> map.v1.ShutdownResponse
-	11, // 35: map.v1.DaemonService.GetStatus:output_type -> map.v1.GetStatusResponse
-	35, // 36: map.v1.DaemonService.WatchEvents:output_type -> map.v1.Event
-	14, // 37: map.v1.DaemonService.SpawnAgent:output_type -> map.v1.SpawnAgentResponse
-	17, // 38: map.v1.DaemonService.KillAgent:output_type -> map.v1.KillAgentResponse
-	19, // 39: map.v1.DaemonService.ListSpawnedAgents:output_type -> map.v1.ListSpawnedAgentsResponse
-	21, // 40: map.v1.DaemonService.RespawnAgent:output_type -> map.v1.RespawnAgentResponse
-	23, // 41: map.v1.DaemonService.ListWorktrees:output_type -> map.v1.ListWorktreesResponse
-	26, // 42: map.v1.DaemonService.CleanupWorktrees:output_type -> map.v1.CleanupWorktreesResponse
-	28, // [28:43] is the sub-list for method output_type
-	13, // [13:28] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	87, // 0: map.v1.SubmitTaskResponse.task:type_name -> map.v1.Task
+	88, // 1: map.v1.ListTasksRequest.status_filter:type_name -> map.v1.TaskStatus
+	87, // 2: map.v1.ListTasksResponse.tasks:type_name -> map.v1.Task
+	87, // 3: map.v1.GetTaskResponse.task:type_name -> map.v1.Task
+	87, // 4: map.v1.CancelTaskResponse.task:type_name -> map.v1.Task
+	89, // 5: map.v1.RetryTaskRequest.since:type_name -> google.protobuf.Timestamp
+	87, // 6: map.v1.RetryTaskResponse.retried:type_name -> map.v1.Task
+	87, // 7: map.v1.RetryTaskResponse.skipped:type_name -> map.v1.Task
+	87, // 8: map.v1.ReopenTaskResponse.task:type_name -> map.v1.Task
+	87, // 9: map.v1.ArchiveTaskResponse.task:type_name -> map.v1.Task
+	87, // 10: map.v1.ImportTasksRequest.tasks:type_name -> map.v1.Task
+	87, // 11: map.v1.NudgeTaskResponse.task:type_name -> map.v1.Task
+	87, // 12: map.v1.AcceptTaskResponse.task:type_name -> map.v1.Task
+	87, // 13: map.v1.RejectTaskResponse.task:type_name -> map.v1.Task
+	90, // 14: map.v1.ListTaskAttemptsResponse.attempts:type_name -> map.v1.TaskAttempt
+	89, // 15: map.v1.GetStatusResponse.started_at:type_name -> google.protobuf.Timestamp
+	89, // 16: map.v1.PingResponse.server_time:type_name -> google.protobuf.Timestamp
+	91, // 17: map.v1.WatchEventsRequest.type_filter:type_name -> map.v1.EventType
+	89, // 18: map.v1.LogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	91, // 19: map.v1.ListEventsRequest.type_filter:type_name -> map.v1.EventType
+	89, // 20: map.v1.ListEventsRequest.since:type_name -> google.protobuf.Timestamp
+	92, // 21: map.v1.ListEventsResponse.events:type_name -> map.v1.Event
+	82, // 22: map.v1.SpawnAgentRequest.labels:type_name -> map.v1.SpawnAgentRequest.LabelsEntry
+	83, // 23: map.v1.SpawnAgentRequest.env:type_name -> map.v1.SpawnAgentRequest.EnvEntry
+	40, // 24: map.v1.SpawnAgentResponse.agents:type_name -> map.v1.SpawnedAgentInfo
+	89, // 25: map.v1.SpawnedAgentInfo.created_at:type_name -> google.protobuf.Timestamp
+	84, // 26: map.v1.SpawnedAgentInfo.labels:type_name -> map.v1.SpawnedAgentInfo.LabelsEntry
+	85, // 27: map.v1.ListSpawnedAgentsRequest.labels:type_name -> map.v1.ListSpawnedAgentsRequest.LabelsEntry
+	40, // 28: map.v1.ListSpawnedAgentsResponse.agents:type_name -> map.v1.SpawnedAgentInfo
+	93, // 29: map.v1.GetAgentHistoryResponse.messages:type_name -> map.v1.AgentMessage
+	50, // 30: map.v1.GetAgentUsageResponse.usage:type_name -> map.v1.AgentUsage
+	89, // 31: map.v1.AgentOutputLine.timestamp:type_name -> google.protobuf.Timestamp
+	86, // 32: map.v1.BroadcastMessageRequest.labels:type_name -> map.v1.BroadcastMessageRequest.LabelsEntry
+	56, // 33: map.v1.BroadcastMessageResponse.deliveries:type_name -> map.v1.BroadcastDelivery
+	59, // 34: map.v1.ListWorktreesResponse.worktrees:type_name -> map.v1.WorktreeInfo
+	89, // 35: map.v1.WorktreeInfo.created_at:type_name -> google.protobuf.Timestamp
+	59, // 36: map.v1.CreateWorktreeResponse.worktree:type_name -> map.v1.WorktreeInfo
+	87, // 37: map.v1.GetCurrentTaskResponse.task:type_name -> map.v1.Task
+	87, // 38: map.v1.GetAgentTaskResponse.task:type_name -> map.v1.Task
+	94, // 39: map.v1.ListRunsResponse.runs:type_name -> map.v1.RunSummary
+	94, // 40: map.v1.GetRunResponse.run:type_name -> map.v1.RunSummary
+	87, // 41: map.v1.GetRunResponse.tasks:type_name -> map.v1.Task
+	95, // 42: map.v1.CreateScheduledTaskResponse.schedule:type_name -> map.v1.ScheduledTask
+	95, // 43: map.v1.ListScheduledTasksResponse.schedules:type_name -> map.v1.ScheduledTask
+	0,  // 44: map.v1.DaemonService.SubmitTask:input_type -> map.v1.SubmitTaskRequest
+	2,  // 45: map.v1.DaemonService.ListTasks:input_type -> map.v1.ListTasksRequest
+	4,  // 46: map.v1.DaemonService.GetTask:input_type -> map.v1.GetTaskRequest
+	6,  // 47: map.v1.DaemonService.CancelTask:input_type -> map.v1.CancelTaskRequest
+	8,  // 48: map.v1.DaemonService.RetryTask:input_type -> map.v1.RetryTaskRequest
+	10, // 49: map.v1.DaemonService.ReopenTask:input_type -> map.v1.ReopenTaskRequest
+	12, // 50: map.v1.DaemonService.ArchiveTask:input_type -> map.v1.ArchiveTaskRequest
+	14, // 51: map.v1.DaemonService.ExportTasks:input_type -> map.v1.ExportTasksRequest
+	15, // 52: map.v1.DaemonService.ImportTasks:input_type -> map.v1.ImportTasksRequest
+	17, // 53: map.v1.DaemonService.NudgeTask:input_type -> map.v1.NudgeTaskRequest
+	19, // 54: map.v1.DaemonService.AcceptTask:input_type -> map.v1.AcceptTaskRequest
+	21, // 55: map.v1.DaemonService.RejectTask:input_type -> map.v1.RejectTaskRequest
+	23, // 56: map.v1.DaemonService.ListTaskAttempts:input_type -> map.v1.ListTaskAttemptsRequest
+	66, // 57: map.v1.DaemonService.RequestInput:input_type -> map.v1.RequestInputRequest
+	68, // 58: map.v1.DaemonService.GetCurrentTask:input_type -> map.v1.GetCurrentTaskRequest
+	70, // 59: map.v1.DaemonService.GetAgentTask:input_type -> map.v1.GetAgentTaskRequest
+	72, // 60: map.v1.DaemonService.ListRuns:input_type -> map.v1.ListRunsRequest
+	74, // 61: map.v1.DaemonService.GetRun:input_type -> map.v1.GetRunRequest
+	76, // 62: map.v1.DaemonService.CreateScheduledTask:input_type -> map.v1.CreateScheduledTaskRequest
+	78, // 63: map.v1.DaemonService.ListScheduledTasks:input_type -> map.v1.ListScheduledTasksRequest
+	80, // 64: map.v1.DaemonService.DeleteScheduledTask:input_type -> map.v1.DeleteScheduledTaskRequest
+	25, // 65: map.v1.DaemonService.Shutdown:input_type -> map.v1.ShutdownRequest
+	27, // 66: map.v1.DaemonService.GetStatus:input_type -> map.v1.GetStatusRequest
+	31, // 67: map.v1.DaemonService.Ping:input_type -> map.v1.PingRequest
+	29, // 68: map.v1.DaemonService.SetSchedulerPaused:input_type -> map.v1.SetSchedulerPausedRequest
+	33, // 69: map.v1.DaemonService.WatchEvents:input_type -> map.v1.WatchEventsRequest
+	36, // 70: map.v1.DaemonService.ListEvents:input_type -> map.v1.ListEventsRequest
+	34, // 71: map.v1.DaemonService.StreamLogs:input_type -> map.v1.StreamLogsRequest
+	38, // 72: map.v1.DaemonService.SpawnAgent:input_type -> map.v1.SpawnAgentRequest
+	41, // 73: map.v1.DaemonService.KillAgent:input_type -> map.v1.KillAgentRequest
+	43, // 74: map.v1.DaemonService.ListSpawnedAgents:input_type -> map.v1.ListSpawnedAgentsRequest
+	45, // 75: map.v1.DaemonService.RespawnAgent:input_type -> map.v1.RespawnAgentRequest
+	47, // 76: map.v1.DaemonService.GetAgentHistory:input_type -> map.v1.GetAgentHistoryRequest
+	52, // 77: map.v1.DaemonService.StreamAgentOutput:input_type -> map.v1.StreamAgentOutputRequest
+	49, // 78: map.v1.DaemonService.GetAgentUsage:input_type -> map.v1.GetAgentUsageRequest
+	54, // 79: map.v1.DaemonService.BroadcastMessage:input_type -> map.v1.BroadcastMessageRequest
+	57, // 80: map.v1.DaemonService.ListWorktrees:input_type -> map.v1.ListWorktreesRequest
+	60, // 81: map.v1.DaemonService.CreateWorktree:input_type -> map.v1.CreateWorktreeRequest
+	62, // 82: map.v1.DaemonService.CleanupWorktrees:input_type -> map.v1.CleanupWorktreesRequest
+	64, // 83: map.v1.DaemonService.PruneBranches:input_type -> map.v1.PruneBranchesRequest
+	1,  // 84: map.v1.DaemonService.SubmitTask:output_type -> map.v1.SubmitTaskResponse
+	3,  // 85: map.v1.DaemonService.ListTasks:output_type -> map.v1.ListTasksResponse
+	5,  // 86: map.v1.DaemonService.GetTask:output_type -> map.v1.GetTaskResponse
+	7,  // 87: map.v1.DaemonService.CancelTask:output_type -> map.v1.CancelTaskResponse
+	9,  // 88: map.v1.DaemonService.RetryTask:output_type -> map.v1.RetryTaskResponse
+	11, // 89: map.v1.DaemonService.ReopenTask:output_type -> map.v1.ReopenTaskResponse
+	13, // 90: map.v1.DaemonService.ArchiveTask:output_type -> map.v1.ArchiveTaskResponse
+	87, // 91: map.v1.DaemonService.ExportTasks:output_type -> map.v1.Task
+	16, // 92: map.v1.DaemonService.ImportTasks:output_type -> map.v1.ImportTasksResponse
+	18, // 93: map.v1.DaemonService.NudgeTask:output_type -> map.v1.NudgeTaskResponse
+	20, // 94: map.v1.DaemonService.AcceptTask:output_type -> map.v1.AcceptTaskResponse
+	22, // 95: map.v1.DaemonService.RejectTask:output_type -> map.v1.RejectTaskResponse
+	24, // 96: map.v1.DaemonService.ListTaskAttempts:output_type -> map.v1.ListTaskAttemptsResponse
+	67, // 97: map.v1.DaemonService.RequestInput:output_type -> map.v1.RequestInputResponse
+	69, // 98: map.v1.DaemonService.GetCurrentTask:output_type -> map.v1.GetCurrentTaskResponse
+	71, // 99: map.v1.DaemonService.GetAgentTask:output_type -> map.v1.GetAgentTaskResponse
+	73, // 100: map.v1.DaemonService.ListRuns:output_type -> map.v1.ListRunsResponse
+	75, // 101: map.v1.DaemonService.GetRun:output_type -> map.v1.GetRunResponse
+	77, // 102: map.v1.DaemonService.CreateScheduledTask:output_type -> map.v1.CreateScheduledTaskResponse
+	79, // 103: map.v1.DaemonService.ListScheduledTasks:output_type -> map.v1.ListScheduledTasksResponse
+	81, // 104: map.v1.DaemonService.DeleteScheduledTask:output_type -> map.v1.DeleteScheduledTaskResponse
+	26, // 105: map.v1.DaemonService.Shutdown:output_type -> map.v1.ShutdownResponse
+	28, // 106: map.v1.DaemonService.GetStatus:output_type -> map.v1.GetStatusResponse
+	32, // 107: map.v1.DaemonService.Ping:output_type -> map.v1.PingResponse
+	30, // 108: map.v1.DaemonService.SetSchedulerPaused:output_type -> map.v1.SetSchedulerPausedResponse
+	92, // 109: map.v1.DaemonService.WatchEvents:output_type -> map.v1.Event
+	37, // 110: map.v1.DaemonService.ListEvents:output_type -> map.v1.ListEventsResponse
+	35, // 111: map.v1.DaemonService.StreamLogs:output_type -> map.v1.LogEntry
+	39, // 112: map.v1.DaemonService.SpawnAgent:output_type -> map.v1.SpawnAgentResponse
+	42, // 113: map.v1.DaemonService.KillAgent:output_type -> map.v1.KillAgentResponse
+	44, // 114: map.v1.DaemonService.ListSpawnedAgents:output_type -> map.v1.ListSpawnedAgentsResponse
+	46, // 115: map.v1.DaemonService.RespawnAgent:output_type -> map.v1.RespawnAgentResponse
+	48, // 116: map.v1.DaemonService.GetAgentHistory:output_type -> map.v1.GetAgentHistoryResponse
+	53, // 117: map.v1.DaemonService.StreamAgentOutput:output_type -> map.v1.AgentOutputLine
+	51, // 118: map.v1.DaemonService.GetAgentUsage:output_type -> map.v1.GetAgentUsageResponse
+	55, // 119: map.v1.DaemonService.BroadcastMessage:output_type -> map.v1.BroadcastMessageResponse
+	58, // 120: map.v1.DaemonService.ListWorktrees:output_type -> map.v1.ListWorktreesResponse
+	61, // 121: map.v1.DaemonService.CreateWorktree:output_type -> map.v1.CreateWorktreeResponse
+	63, // 122: map.v1.DaemonService.CleanupWorktrees:output_type -> map.v1.CleanupWorktreesResponse
+	65, // 123: map.v1.DaemonService.PruneBranches:output_type -> map.v1.PruneBranchesResponse
+	84, // [84:124] is the sub-list for method output_type
+	44, // [44:84] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_map_v1_daemon_proto_init() }
@@ -2010,7 +5339,7 @@ func file_map_v1_daemon_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_map_v1_daemon_proto_rawDesc), len(file_map_v1_daemon_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   31,
+			NumMessages:   87,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
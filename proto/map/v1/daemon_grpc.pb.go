@@ -19,21 +19,46 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DaemonService_SubmitTask_FullMethodName        = "/map.v1.DaemonService/SubmitTask"
-	DaemonService_ListTasks_FullMethodName         = "/map.v1.DaemonService/ListTasks"
-	DaemonService_GetTask_FullMethodName           = "/map.v1.DaemonService/GetTask"
-	DaemonService_CancelTask_FullMethodName        = "/map.v1.DaemonService/CancelTask"
-	DaemonService_RequestInput_FullMethodName      = "/map.v1.DaemonService/RequestInput"
-	DaemonService_GetCurrentTask_FullMethodName    = "/map.v1.DaemonService/GetCurrentTask"
-	DaemonService_Shutdown_FullMethodName          = "/map.v1.DaemonService/Shutdown"
-	DaemonService_GetStatus_FullMethodName         = "/map.v1.DaemonService/GetStatus"
-	DaemonService_WatchEvents_FullMethodName       = "/map.v1.DaemonService/WatchEvents"
-	DaemonService_SpawnAgent_FullMethodName        = "/map.v1.DaemonService/SpawnAgent"
-	DaemonService_KillAgent_FullMethodName         = "/map.v1.DaemonService/KillAgent"
-	DaemonService_ListSpawnedAgents_FullMethodName = "/map.v1.DaemonService/ListSpawnedAgents"
-	DaemonService_RespawnAgent_FullMethodName      = "/map.v1.DaemonService/RespawnAgent"
-	DaemonService_ListWorktrees_FullMethodName     = "/map.v1.DaemonService/ListWorktrees"
-	DaemonService_CleanupWorktrees_FullMethodName  = "/map.v1.DaemonService/CleanupWorktrees"
+	DaemonService_SubmitTask_FullMethodName          = "/map.v1.DaemonService/SubmitTask"
+	DaemonService_ListTasks_FullMethodName           = "/map.v1.DaemonService/ListTasks"
+	DaemonService_GetTask_FullMethodName             = "/map.v1.DaemonService/GetTask"
+	DaemonService_CancelTask_FullMethodName          = "/map.v1.DaemonService/CancelTask"
+	DaemonService_RetryTask_FullMethodName           = "/map.v1.DaemonService/RetryTask"
+	DaemonService_ReopenTask_FullMethodName          = "/map.v1.DaemonService/ReopenTask"
+	DaemonService_ArchiveTask_FullMethodName         = "/map.v1.DaemonService/ArchiveTask"
+	DaemonService_ExportTasks_FullMethodName         = "/map.v1.DaemonService/ExportTasks"
+	DaemonService_ImportTasks_FullMethodName         = "/map.v1.DaemonService/ImportTasks"
+	DaemonService_NudgeTask_FullMethodName           = "/map.v1.DaemonService/NudgeTask"
+	DaemonService_AcceptTask_FullMethodName          = "/map.v1.DaemonService/AcceptTask"
+	DaemonService_RejectTask_FullMethodName          = "/map.v1.DaemonService/RejectTask"
+	DaemonService_ListTaskAttempts_FullMethodName    = "/map.v1.DaemonService/ListTaskAttempts"
+	DaemonService_RequestInput_FullMethodName        = "/map.v1.DaemonService/RequestInput"
+	DaemonService_GetCurrentTask_FullMethodName      = "/map.v1.DaemonService/GetCurrentTask"
+	DaemonService_GetAgentTask_FullMethodName        = "/map.v1.DaemonService/GetAgentTask"
+	DaemonService_ListRuns_FullMethodName            = "/map.v1.DaemonService/ListRuns"
+	DaemonService_GetRun_FullMethodName              = "/map.v1.DaemonService/GetRun"
+	DaemonService_CreateScheduledTask_FullMethodName = "/map.v1.DaemonService/CreateScheduledTask"
+	DaemonService_ListScheduledTasks_FullMethodName  = "/map.v1.DaemonService/ListScheduledTasks"
+	DaemonService_DeleteScheduledTask_FullMethodName = "/map.v1.DaemonService/DeleteScheduledTask"
+	DaemonService_Shutdown_FullMethodName            = "/map.v1.DaemonService/Shutdown"
+	DaemonService_GetStatus_FullMethodName           = "/map.v1.DaemonService/GetStatus"
+	DaemonService_Ping_FullMethodName                = "/map.v1.DaemonService/Ping"
+	DaemonService_SetSchedulerPaused_FullMethodName  = "/map.v1.DaemonService/SetSchedulerPaused"
+	DaemonService_WatchEvents_FullMethodName         = "/map.v1.DaemonService/WatchEvents"
+	DaemonService_ListEvents_FullMethodName          = "/map.v1.DaemonService/ListEvents"
+	DaemonService_StreamLogs_FullMethodName          = "/map.v1.DaemonService/StreamLogs"
+	DaemonService_SpawnAgent_FullMethodName          = "/map.v1.DaemonService/SpawnAgent"
+	DaemonService_KillAgent_FullMethodName           = "/map.v1.DaemonService/KillAgent"
+	DaemonService_ListSpawnedAgents_FullMethodName   = "/map.v1.DaemonService/ListSpawnedAgents"
+	DaemonService_RespawnAgent_FullMethodName        = "/map.v1.DaemonService/RespawnAgent"
+	DaemonService_GetAgentHistory_FullMethodName     = "/map.v1.DaemonService/GetAgentHistory"
+	DaemonService_StreamAgentOutput_FullMethodName   = "/map.v1.DaemonService/StreamAgentOutput"
+	DaemonService_GetAgentUsage_FullMethodName       = "/map.v1.DaemonService/GetAgentUsage"
+	DaemonService_BroadcastMessage_FullMethodName    = "/map.v1.DaemonService/BroadcastMessage"
+	DaemonService_ListWorktrees_FullMethodName       = "/map.v1.DaemonService/ListWorktrees"
+	DaemonService_CreateWorktree_FullMethodName      = "/map.v1.DaemonService/CreateWorktree"
+	DaemonService_CleanupWorktrees_FullMethodName    = "/map.v1.DaemonService/CleanupWorktrees"
+	DaemonService_PruneBranches_FullMethodName       = "/map.v1.DaemonService/PruneBranches"
 )
 
 // DaemonServiceClient is the client API for DaemonService service.
@@ -47,21 +72,46 @@ type DaemonServiceClient interface {
 	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
 	GetTask(ctx context.Context, in *GetTaskRequest, opts ...grpc.CallOption) (*GetTaskResponse, error)
 	CancelTask(ctx context.Context, in *CancelTaskRequest, opts ...grpc.CallOption) (*CancelTaskResponse, error)
+	RetryTask(ctx context.Context, in *RetryTaskRequest, opts ...grpc.CallOption) (*RetryTaskResponse, error)
+	ReopenTask(ctx context.Context, in *ReopenTaskRequest, opts ...grpc.CallOption) (*ReopenTaskResponse, error)
+	ArchiveTask(ctx context.Context, in *ArchiveTaskRequest, opts ...grpc.CallOption) (*ArchiveTaskResponse, error)
+	ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Task], error)
+	ImportTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTasksRequest, ImportTasksResponse], error)
+	NudgeTask(ctx context.Context, in *NudgeTaskRequest, opts ...grpc.CallOption) (*NudgeTaskResponse, error)
+	AcceptTask(ctx context.Context, in *AcceptTaskRequest, opts ...grpc.CallOption) (*AcceptTaskResponse, error)
+	RejectTask(ctx context.Context, in *RejectTaskRequest, opts ...grpc.CallOption) (*RejectTaskResponse, error)
+	ListTaskAttempts(ctx context.Context, in *ListTaskAttemptsRequest, opts ...grpc.CallOption) (*ListTaskAttemptsResponse, error)
 	RequestInput(ctx context.Context, in *RequestInputRequest, opts ...grpc.CallOption) (*RequestInputResponse, error)
 	GetCurrentTask(ctx context.Context, in *GetCurrentTaskRequest, opts ...grpc.CallOption) (*GetCurrentTaskResponse, error)
+	GetAgentTask(ctx context.Context, in *GetAgentTaskRequest, opts ...grpc.CallOption) (*GetAgentTaskResponse, error)
+	ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error)
+	GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*GetRunResponse, error)
+	CreateScheduledTask(ctx context.Context, in *CreateScheduledTaskRequest, opts ...grpc.CallOption) (*CreateScheduledTaskResponse, error)
+	ListScheduledTasks(ctx context.Context, in *ListScheduledTasksRequest, opts ...grpc.CallOption) (*ListScheduledTasksResponse, error)
+	DeleteScheduledTask(ctx context.Context, in *DeleteScheduledTaskRequest, opts ...grpc.CallOption) (*DeleteScheduledTaskResponse, error)
 	// Daemon control
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	SetSchedulerPaused(ctx context.Context, in *SetSchedulerPausedRequest, opts ...grpc.CallOption) (*SetSchedulerPausedResponse, error)
 	// Real-time event streaming
 	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+	ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error)
+	StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error)
 	// Spawned agent management
 	SpawnAgent(ctx context.Context, in *SpawnAgentRequest, opts ...grpc.CallOption) (*SpawnAgentResponse, error)
 	KillAgent(ctx context.Context, in *KillAgentRequest, opts ...grpc.CallOption) (*KillAgentResponse, error)
 	ListSpawnedAgents(ctx context.Context, in *ListSpawnedAgentsRequest, opts ...grpc.CallOption) (*ListSpawnedAgentsResponse, error)
 	RespawnAgent(ctx context.Context, in *RespawnAgentRequest, opts ...grpc.CallOption) (*RespawnAgentResponse, error)
+	GetAgentHistory(ctx context.Context, in *GetAgentHistoryRequest, opts ...grpc.CallOption) (*GetAgentHistoryResponse, error)
+	StreamAgentOutput(ctx context.Context, in *StreamAgentOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AgentOutputLine], error)
+	GetAgentUsage(ctx context.Context, in *GetAgentUsageRequest, opts ...grpc.CallOption) (*GetAgentUsageResponse, error)
+	BroadcastMessage(ctx context.Context, in *BroadcastMessageRequest, opts ...grpc.CallOption) (*BroadcastMessageResponse, error)
 	// Worktree management
 	ListWorktrees(ctx context.Context, in *ListWorktreesRequest, opts ...grpc.CallOption) (*ListWorktreesResponse, error)
+	CreateWorktree(ctx context.Context, in *CreateWorktreeRequest, opts ...grpc.CallOption) (*CreateWorktreeResponse, error)
 	CleanupWorktrees(ctx context.Context, in *CleanupWorktreesRequest, opts ...grpc.CallOption) (*CleanupWorktreesResponse, error)
+	PruneBranches(ctx context.Context, in *PruneBranchesRequest, opts ...grpc.CallOption) (*PruneBranchesResponse, error)
 }
 
 type daemonServiceClient struct {
@@ -112,6 +162,108 @@ func (c *daemonServiceClient) CancelTask(ctx context.Context, in *CancelTaskRequ
 	return out, nil
 }
 
+func (c *daemonServiceClient) RetryTask(ctx context.Context, in *RetryTaskRequest, opts ...grpc.CallOption) (*RetryTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_RetryTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ReopenTask(ctx context.Context, in *ReopenTaskRequest, opts ...grpc.CallOption) (*ReopenTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReopenTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ReopenTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ArchiveTask(ctx context.Context, in *ArchiveTaskRequest, opts ...grpc.CallOption) (*ArchiveTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ArchiveTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ExportTasks(ctx context.Context, in *ExportTasksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Task], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[0], DaemonService_ExportTasks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportTasksRequest, Task]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_ExportTasksClient = grpc.ServerStreamingClient[Task]
+
+func (c *daemonServiceClient) ImportTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportTasksRequest, ImportTasksResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[1], DaemonService_ImportTasks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportTasksRequest, ImportTasksResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_ImportTasksClient = grpc.ClientStreamingClient[ImportTasksRequest, ImportTasksResponse]
+
+func (c *daemonServiceClient) NudgeTask(ctx context.Context, in *NudgeTaskRequest, opts ...grpc.CallOption) (*NudgeTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NudgeTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_NudgeTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) AcceptTask(ctx context.Context, in *AcceptTaskRequest, opts ...grpc.CallOption) (*AcceptTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_AcceptTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) RejectTask(ctx context.Context, in *RejectTaskRequest, opts ...grpc.CallOption) (*RejectTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_RejectTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListTaskAttempts(ctx context.Context, in *ListTaskAttemptsRequest, opts ...grpc.CallOption) (*ListTaskAttemptsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTaskAttemptsResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ListTaskAttempts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) RequestInput(ctx context.Context, in *RequestInputRequest, opts ...grpc.CallOption) (*RequestInputResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RequestInputResponse)
@@ -132,6 +284,66 @@ func (c *daemonServiceClient) GetCurrentTask(ctx context.Context, in *GetCurrent
 	return out, nil
 }
 
+func (c *daemonServiceClient) GetAgentTask(ctx context.Context, in *GetAgentTaskRequest, opts ...grpc.CallOption) (*GetAgentTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_GetAgentTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListRuns(ctx context.Context, in *ListRunsRequest, opts ...grpc.CallOption) (*ListRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRunsResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ListRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) GetRun(ctx context.Context, in *GetRunRequest, opts ...grpc.CallOption) (*GetRunResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRunResponse)
+	err := c.cc.Invoke(ctx, DaemonService_GetRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) CreateScheduledTask(ctx context.Context, in *CreateScheduledTaskRequest, opts ...grpc.CallOption) (*CreateScheduledTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateScheduledTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_CreateScheduledTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) ListScheduledTasks(ctx context.Context, in *ListScheduledTasksRequest, opts ...grpc.CallOption) (*ListScheduledTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledTasksResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ListScheduledTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) DeleteScheduledTask(ctx context.Context, in *DeleteScheduledTaskRequest, opts ...grpc.CallOption) (*DeleteScheduledTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteScheduledTaskResponse)
+	err := c.cc.Invoke(ctx, DaemonService_DeleteScheduledTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ShutdownResponse)
@@ -152,9 +364,29 @@ func (c *daemonServiceClient) GetStatus(ctx context.Context, in *GetStatusReques
 	return out, nil
 }
 
+func (c *daemonServiceClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, DaemonService_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) SetSchedulerPaused(ctx context.Context, in *SetSchedulerPausedRequest, opts ...grpc.CallOption) (*SetSchedulerPausedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetSchedulerPausedResponse)
+	err := c.cc.Invoke(ctx, DaemonService_SetSchedulerPaused_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[0], DaemonService_WatchEvents_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[2], DaemonService_WatchEvents_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +403,35 @@ func (c *daemonServiceClient) WatchEvents(ctx context.Context, in *WatchEventsRe
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DaemonService_WatchEventsClient = grpc.ServerStreamingClient[Event]
 
+func (c *daemonServiceClient) ListEvents(ctx context.Context, in *ListEventsRequest, opts ...grpc.CallOption) (*ListEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventsResponse)
+	err := c.cc.Invoke(ctx, DaemonService_ListEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) StreamLogs(ctx context.Context, in *StreamLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[3], DaemonService_StreamLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamLogsRequest, LogEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_StreamLogsClient = grpc.ServerStreamingClient[LogEntry]
+
 func (c *daemonServiceClient) SpawnAgent(ctx context.Context, in *SpawnAgentRequest, opts ...grpc.CallOption) (*SpawnAgentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SpawnAgentResponse)
@@ -211,6 +472,55 @@ func (c *daemonServiceClient) RespawnAgent(ctx context.Context, in *RespawnAgent
 	return out, nil
 }
 
+func (c *daemonServiceClient) GetAgentHistory(ctx context.Context, in *GetAgentHistoryRequest, opts ...grpc.CallOption) (*GetAgentHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentHistoryResponse)
+	err := c.cc.Invoke(ctx, DaemonService_GetAgentHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) StreamAgentOutput(ctx context.Context, in *StreamAgentOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AgentOutputLine], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DaemonService_ServiceDesc.Streams[4], DaemonService_StreamAgentOutput_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamAgentOutputRequest, AgentOutputLine]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_StreamAgentOutputClient = grpc.ServerStreamingClient[AgentOutputLine]
+
+func (c *daemonServiceClient) GetAgentUsage(ctx context.Context, in *GetAgentUsageRequest, opts ...grpc.CallOption) (*GetAgentUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentUsageResponse)
+	err := c.cc.Invoke(ctx, DaemonService_GetAgentUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *daemonServiceClient) BroadcastMessage(ctx context.Context, in *BroadcastMessageRequest, opts ...grpc.CallOption) (*BroadcastMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadcastMessageResponse)
+	err := c.cc.Invoke(ctx, DaemonService_BroadcastMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) ListWorktrees(ctx context.Context, in *ListWorktreesRequest, opts ...grpc.CallOption) (*ListWorktreesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListWorktreesResponse)
@@ -221,6 +531,16 @@ func (c *daemonServiceClient) ListWorktrees(ctx context.Context, in *ListWorktre
 	return out, nil
 }
 
+func (c *daemonServiceClient) CreateWorktree(ctx context.Context, in *CreateWorktreeRequest, opts ...grpc.CallOption) (*CreateWorktreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWorktreeResponse)
+	err := c.cc.Invoke(ctx, DaemonService_CreateWorktree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *daemonServiceClient) CleanupWorktrees(ctx context.Context, in *CleanupWorktreesRequest, opts ...grpc.CallOption) (*CleanupWorktreesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CleanupWorktreesResponse)
@@ -231,6 +551,16 @@ func (c *daemonServiceClient) CleanupWorktrees(ctx context.Context, in *CleanupW
 	return out, nil
 }
 
+func (c *daemonServiceClient) PruneBranches(ctx context.Context, in *PruneBranchesRequest, opts ...grpc.CallOption) (*PruneBranchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PruneBranchesResponse)
+	err := c.cc.Invoke(ctx, DaemonService_PruneBranches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DaemonServiceServer is the server API for DaemonService service.
 // All implementations must embed UnimplementedDaemonServiceServer
 // for forward compatibility.
@@ -242,21 +572,46 @@ type DaemonServiceServer interface {
 	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
 	GetTask(context.Context, *GetTaskRequest) (*GetTaskResponse, error)
 	CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error)
+	RetryTask(context.Context, *RetryTaskRequest) (*RetryTaskResponse, error)
+	ReopenTask(context.Context, *ReopenTaskRequest) (*ReopenTaskResponse, error)
+	ArchiveTask(context.Context, *ArchiveTaskRequest) (*ArchiveTaskResponse, error)
+	ExportTasks(*ExportTasksRequest, grpc.ServerStreamingServer[Task]) error
+	ImportTasks(grpc.ClientStreamingServer[ImportTasksRequest, ImportTasksResponse]) error
+	NudgeTask(context.Context, *NudgeTaskRequest) (*NudgeTaskResponse, error)
+	AcceptTask(context.Context, *AcceptTaskRequest) (*AcceptTaskResponse, error)
+	RejectTask(context.Context, *RejectTaskRequest) (*RejectTaskResponse, error)
+	ListTaskAttempts(context.Context, *ListTaskAttemptsRequest) (*ListTaskAttemptsResponse, error)
 	RequestInput(context.Context, *RequestInputRequest) (*RequestInputResponse, error)
 	GetCurrentTask(context.Context, *GetCurrentTaskRequest) (*GetCurrentTaskResponse, error)
+	GetAgentTask(context.Context, *GetAgentTaskRequest) (*GetAgentTaskResponse, error)
+	ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error)
+	GetRun(context.Context, *GetRunRequest) (*GetRunResponse, error)
+	CreateScheduledTask(context.Context, *CreateScheduledTaskRequest) (*CreateScheduledTaskResponse, error)
+	ListScheduledTasks(context.Context, *ListScheduledTasksRequest) (*ListScheduledTasksResponse, error)
+	DeleteScheduledTask(context.Context, *DeleteScheduledTaskRequest) (*DeleteScheduledTaskResponse, error)
 	// Daemon control
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	SetSchedulerPaused(context.Context, *SetSchedulerPausedRequest) (*SetSchedulerPausedResponse, error)
 	// Real-time event streaming
 	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
+	StreamLogs(*StreamLogsRequest, grpc.ServerStreamingServer[LogEntry]) error
 	// Spawned agent management
 	SpawnAgent(context.Context, *SpawnAgentRequest) (*SpawnAgentResponse, error)
 	KillAgent(context.Context, *KillAgentRequest) (*KillAgentResponse, error)
 	ListSpawnedAgents(context.Context, *ListSpawnedAgentsRequest) (*ListSpawnedAgentsResponse, error)
 	RespawnAgent(context.Context, *RespawnAgentRequest) (*RespawnAgentResponse, error)
+	GetAgentHistory(context.Context, *GetAgentHistoryRequest) (*GetAgentHistoryResponse, error)
+	StreamAgentOutput(*StreamAgentOutputRequest, grpc.ServerStreamingServer[AgentOutputLine]) error
+	GetAgentUsage(context.Context, *GetAgentUsageRequest) (*GetAgentUsageResponse, error)
+	BroadcastMessage(context.Context, *BroadcastMessageRequest) (*BroadcastMessageResponse, error)
 	// Worktree management
 	ListWorktrees(context.Context, *ListWorktreesRequest) (*ListWorktreesResponse, error)
+	CreateWorktree(context.Context, *CreateWorktreeRequest) (*CreateWorktreeResponse, error)
 	CleanupWorktrees(context.Context, *CleanupWorktreesRequest) (*CleanupWorktreesResponse, error)
+	PruneBranches(context.Context, *PruneBranchesRequest) (*PruneBranchesResponse, error)
 	mustEmbedUnimplementedDaemonServiceServer()
 }
 
@@ -279,21 +634,78 @@ func (UnimplementedDaemonServiceServer) GetTask(context.Context, *GetTaskRequest
 func (UnimplementedDaemonServiceServer) CancelTask(context.Context, *CancelTaskRequest) (*CancelTaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CancelTask not implemented")
 }
+func (UnimplementedDaemonServiceServer) RetryTask(context.Context, *RetryTaskRequest) (*RetryTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetryTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ReopenTask(context.Context, *ReopenTaskRequest) (*ReopenTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReopenTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ArchiveTask(context.Context, *ArchiveTaskRequest) (*ArchiveTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ArchiveTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ExportTasks(*ExportTasksRequest, grpc.ServerStreamingServer[Task]) error {
+	return status.Error(codes.Unimplemented, "method ExportTasks not implemented")
+}
+func (UnimplementedDaemonServiceServer) ImportTasks(grpc.ClientStreamingServer[ImportTasksRequest, ImportTasksResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportTasks not implemented")
+}
+func (UnimplementedDaemonServiceServer) NudgeTask(context.Context, *NudgeTaskRequest) (*NudgeTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method NudgeTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) AcceptTask(context.Context, *AcceptTaskRequest) (*AcceptTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcceptTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) RejectTask(context.Context, *RejectTaskRequest) (*RejectTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RejectTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListTaskAttempts(context.Context, *ListTaskAttemptsRequest) (*ListTaskAttemptsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTaskAttempts not implemented")
+}
 func (UnimplementedDaemonServiceServer) RequestInput(context.Context, *RequestInputRequest) (*RequestInputResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RequestInput not implemented")
 }
 func (UnimplementedDaemonServiceServer) GetCurrentTask(context.Context, *GetCurrentTaskRequest) (*GetCurrentTaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCurrentTask not implemented")
 }
+func (UnimplementedDaemonServiceServer) GetAgentTask(context.Context, *GetAgentTaskRequest) (*GetAgentTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgentTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListRuns(context.Context, *ListRunsRequest) (*ListRunsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetRun(context.Context, *GetRunRequest) (*GetRunResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRun not implemented")
+}
+func (UnimplementedDaemonServiceServer) CreateScheduledTask(context.Context, *CreateScheduledTaskRequest) (*CreateScheduledTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateScheduledTask not implemented")
+}
+func (UnimplementedDaemonServiceServer) ListScheduledTasks(context.Context, *ListScheduledTasksRequest) (*ListScheduledTasksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListScheduledTasks not implemented")
+}
+func (UnimplementedDaemonServiceServer) DeleteScheduledTask(context.Context, *DeleteScheduledTaskRequest) (*DeleteScheduledTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteScheduledTask not implemented")
+}
 func (UnimplementedDaemonServiceServer) Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Shutdown not implemented")
 }
 func (UnimplementedDaemonServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetStatus not implemented")
 }
+func (UnimplementedDaemonServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedDaemonServiceServer) SetSchedulerPaused(context.Context, *SetSchedulerPausedRequest) (*SetSchedulerPausedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetSchedulerPaused not implemented")
+}
 func (UnimplementedDaemonServiceServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
 	return status.Error(codes.Unimplemented, "method WatchEvents not implemented")
 }
+func (UnimplementedDaemonServiceServer) ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListEvents not implemented")
+}
+func (UnimplementedDaemonServiceServer) StreamLogs(*StreamLogsRequest, grpc.ServerStreamingServer[LogEntry]) error {
+	return status.Error(codes.Unimplemented, "method StreamLogs not implemented")
+}
 func (UnimplementedDaemonServiceServer) SpawnAgent(context.Context, *SpawnAgentRequest) (*SpawnAgentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SpawnAgent not implemented")
 }
@@ -306,12 +718,30 @@ func (UnimplementedDaemonServiceServer) ListSpawnedAgents(context.Context, *List
 func (UnimplementedDaemonServiceServer) RespawnAgent(context.Context, *RespawnAgentRequest) (*RespawnAgentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method RespawnAgent not implemented")
 }
+func (UnimplementedDaemonServiceServer) GetAgentHistory(context.Context, *GetAgentHistoryRequest) (*GetAgentHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgentHistory not implemented")
+}
+func (UnimplementedDaemonServiceServer) StreamAgentOutput(*StreamAgentOutputRequest, grpc.ServerStreamingServer[AgentOutputLine]) error {
+	return status.Error(codes.Unimplemented, "method StreamAgentOutput not implemented")
+}
+func (UnimplementedDaemonServiceServer) GetAgentUsage(context.Context, *GetAgentUsageRequest) (*GetAgentUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgentUsage not implemented")
+}
+func (UnimplementedDaemonServiceServer) BroadcastMessage(context.Context, *BroadcastMessageRequest) (*BroadcastMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BroadcastMessage not implemented")
+}
 func (UnimplementedDaemonServiceServer) ListWorktrees(context.Context, *ListWorktreesRequest) (*ListWorktreesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListWorktrees not implemented")
 }
+func (UnimplementedDaemonServiceServer) CreateWorktree(context.Context, *CreateWorktreeRequest) (*CreateWorktreeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateWorktree not implemented")
+}
 func (UnimplementedDaemonServiceServer) CleanupWorktrees(context.Context, *CleanupWorktreesRequest) (*CleanupWorktreesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CleanupWorktrees not implemented")
 }
+func (UnimplementedDaemonServiceServer) PruneBranches(context.Context, *PruneBranchesRequest) (*PruneBranchesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PruneBranches not implemented")
+}
 func (UnimplementedDaemonServiceServer) mustEmbedUnimplementedDaemonServiceServer() {}
 func (UnimplementedDaemonServiceServer) testEmbeddedByValue()                       {}
 
@@ -405,71 +835,323 @@ func _DaemonService_CancelTask_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DaemonService_RequestInput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RequestInputRequest)
+func _DaemonService_RetryTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DaemonServiceServer).RequestInput(ctx, in)
+		return srv.(DaemonServiceServer).RetryTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DaemonService_RequestInput_FullMethodName,
+		FullMethod: DaemonService_RetryTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DaemonServiceServer).RequestInput(ctx, req.(*RequestInputRequest))
+		return srv.(DaemonServiceServer).RetryTask(ctx, req.(*RetryTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DaemonService_GetCurrentTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetCurrentTaskRequest)
+func _DaemonService_ReopenTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReopenTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DaemonServiceServer).GetCurrentTask(ctx, in)
+		return srv.(DaemonServiceServer).ReopenTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DaemonService_GetCurrentTask_FullMethodName,
+		FullMethod: DaemonService_ReopenTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DaemonServiceServer).GetCurrentTask(ctx, req.(*GetCurrentTaskRequest))
+		return srv.(DaemonServiceServer).ReopenTask(ctx, req.(*ReopenTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DaemonService_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ShutdownRequest)
+func _DaemonService_ArchiveTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DaemonServiceServer).Shutdown(ctx, in)
+		return srv.(DaemonServiceServer).ArchiveTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DaemonService_Shutdown_FullMethodName,
+		FullMethod: DaemonService_ArchiveTask_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DaemonServiceServer).Shutdown(ctx, req.(*ShutdownRequest))
+		return srv.(DaemonServiceServer).ArchiveTask(ctx, req.(*ArchiveTaskRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _DaemonService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetStatusRequest)
+func _DaemonService_ExportTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportTasksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServiceServer).ExportTasks(m, &grpc.GenericServerStream[ExportTasksRequest, Task]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_ExportTasksServer = grpc.ServerStreamingServer[Task]
+
+func _DaemonService_ImportTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DaemonServiceServer).ImportTasks(&grpc.GenericServerStream[ImportTasksRequest, ImportTasksResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_ImportTasksServer = grpc.ClientStreamingServer[ImportTasksRequest, ImportTasksResponse]
+
+func _DaemonService_NudgeTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NudgeTaskRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(DaemonServiceServer).GetStatus(ctx, in)
+		return srv.(DaemonServiceServer).NudgeTask(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: DaemonService_GetStatus_FullMethodName,
+		FullMethod: DaemonService_NudgeTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).NudgeTask(ctx, req.(*NudgeTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_AcceptTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).AcceptTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_AcceptTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).AcceptTask(ctx, req.(*AcceptTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_RejectTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).RejectTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_RejectTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).RejectTask(ctx, req.(*RejectTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListTaskAttempts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTaskAttemptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListTaskAttempts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_ListTaskAttempts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListTaskAttempts(ctx, req.(*ListTaskAttemptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_RequestInput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestInputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).RequestInput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_RequestInput_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).RequestInput(ctx, req.(*RequestInputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetCurrentTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetCurrentTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetCurrentTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetCurrentTask(ctx, req.(*GetCurrentTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetAgentTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetAgentTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetAgentTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetAgentTask(ctx, req.(*GetAgentTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_ListRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListRuns(ctx, req.(*ListRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetRun(ctx, req.(*GetRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_CreateScheduledTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateScheduledTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).CreateScheduledTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_CreateScheduledTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).CreateScheduledTask(ctx, req.(*CreateScheduledTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_ListScheduledTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListScheduledTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_ListScheduledTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListScheduledTasks(ctx, req.(*ListScheduledTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_DeleteScheduledTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteScheduledTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).DeleteScheduledTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_DeleteScheduledTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).DeleteScheduledTask(ctx, req.(*DeleteScheduledTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShutdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).Shutdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_Shutdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).Shutdown(ctx, req.(*ShutdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetStatus_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(DaemonServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
@@ -477,6 +1159,42 @@ func _DaemonService_GetStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_SetSchedulerPaused_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSchedulerPausedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).SetSchedulerPaused(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_SetSchedulerPaused_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).SetSchedulerPaused(ctx, req.(*SetSchedulerPausedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -488,6 +1206,35 @@ func _DaemonService_WatchEvents_Handler(srv interface{}, stream grpc.ServerStrea
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type DaemonService_WatchEventsServer = grpc.ServerStreamingServer[Event]
 
+func _DaemonService_ListEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).ListEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_ListEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).ListEvents(ctx, req.(*ListEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_StreamLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServiceServer).StreamLogs(m, &grpc.GenericServerStream[StreamLogsRequest, LogEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_StreamLogsServer = grpc.ServerStreamingServer[LogEntry]
+
 func _DaemonService_SpawnAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SpawnAgentRequest)
 	if err := dec(in); err != nil {
@@ -560,6 +1307,71 @@ func _DaemonService_RespawnAgent_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonService_GetAgentHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetAgentHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetAgentHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetAgentHistory(ctx, req.(*GetAgentHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_StreamAgentOutput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamAgentOutputRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DaemonServiceServer).StreamAgentOutput(m, &grpc.GenericServerStream[StreamAgentOutputRequest, AgentOutputLine]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DaemonService_StreamAgentOutputServer = grpc.ServerStreamingServer[AgentOutputLine]
+
+func _DaemonService_GetAgentUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).GetAgentUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_GetAgentUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).GetAgentUsage(ctx, req.(*GetAgentUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DaemonService_BroadcastMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).BroadcastMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_BroadcastMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).BroadcastMessage(ctx, req.(*BroadcastMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonService_ListWorktrees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListWorktreesRequest)
 	if err := dec(in); err != nil {
@@ -578,6 +1390,24 @@ func _DaemonService_ListWorktrees_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonService_CreateWorktree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorktreeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).CreateWorktree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_CreateWorktree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).CreateWorktree(ctx, req.(*CreateWorktreeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _DaemonService_CleanupWorktrees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CleanupWorktreesRequest)
 	if err := dec(in); err != nil {
@@ -596,6 +1426,24 @@ func _DaemonService_CleanupWorktrees_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DaemonService_PruneBranches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PruneBranchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DaemonServiceServer).PruneBranches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DaemonService_PruneBranches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DaemonServiceServer).PruneBranches(ctx, req.(*PruneBranchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // DaemonService_ServiceDesc is the grpc.ServiceDesc for DaemonService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -619,6 +1467,34 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CancelTask",
 			Handler:    _DaemonService_CancelTask_Handler,
 		},
+		{
+			MethodName: "RetryTask",
+			Handler:    _DaemonService_RetryTask_Handler,
+		},
+		{
+			MethodName: "ReopenTask",
+			Handler:    _DaemonService_ReopenTask_Handler,
+		},
+		{
+			MethodName: "ArchiveTask",
+			Handler:    _DaemonService_ArchiveTask_Handler,
+		},
+		{
+			MethodName: "NudgeTask",
+			Handler:    _DaemonService_NudgeTask_Handler,
+		},
+		{
+			MethodName: "AcceptTask",
+			Handler:    _DaemonService_AcceptTask_Handler,
+		},
+		{
+			MethodName: "RejectTask",
+			Handler:    _DaemonService_RejectTask_Handler,
+		},
+		{
+			MethodName: "ListTaskAttempts",
+			Handler:    _DaemonService_ListTaskAttempts_Handler,
+		},
 		{
 			MethodName: "RequestInput",
 			Handler:    _DaemonService_RequestInput_Handler,
@@ -627,6 +1503,30 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCurrentTask",
 			Handler:    _DaemonService_GetCurrentTask_Handler,
 		},
+		{
+			MethodName: "GetAgentTask",
+			Handler:    _DaemonService_GetAgentTask_Handler,
+		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _DaemonService_ListRuns_Handler,
+		},
+		{
+			MethodName: "GetRun",
+			Handler:    _DaemonService_GetRun_Handler,
+		},
+		{
+			MethodName: "CreateScheduledTask",
+			Handler:    _DaemonService_CreateScheduledTask_Handler,
+		},
+		{
+			MethodName: "ListScheduledTasks",
+			Handler:    _DaemonService_ListScheduledTasks_Handler,
+		},
+		{
+			MethodName: "DeleteScheduledTask",
+			Handler:    _DaemonService_DeleteScheduledTask_Handler,
+		},
 		{
 			MethodName: "Shutdown",
 			Handler:    _DaemonService_Shutdown_Handler,
@@ -635,6 +1535,18 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStatus",
 			Handler:    _DaemonService_GetStatus_Handler,
 		},
+		{
+			MethodName: "Ping",
+			Handler:    _DaemonService_Ping_Handler,
+		},
+		{
+			MethodName: "SetSchedulerPaused",
+			Handler:    _DaemonService_SetSchedulerPaused_Handler,
+		},
+		{
+			MethodName: "ListEvents",
+			Handler:    _DaemonService_ListEvents_Handler,
+		},
 		{
 			MethodName: "SpawnAgent",
 			Handler:    _DaemonService_SpawnAgent_Handler,
@@ -651,21 +1563,61 @@ var DaemonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RespawnAgent",
 			Handler:    _DaemonService_RespawnAgent_Handler,
 		},
+		{
+			MethodName: "GetAgentHistory",
+			Handler:    _DaemonService_GetAgentHistory_Handler,
+		},
+		{
+			MethodName: "GetAgentUsage",
+			Handler:    _DaemonService_GetAgentUsage_Handler,
+		},
+		{
+			MethodName: "BroadcastMessage",
+			Handler:    _DaemonService_BroadcastMessage_Handler,
+		},
 		{
 			MethodName: "ListWorktrees",
 			Handler:    _DaemonService_ListWorktrees_Handler,
 		},
+		{
+			MethodName: "CreateWorktree",
+			Handler:    _DaemonService_CreateWorktree_Handler,
+		},
 		{
 			MethodName: "CleanupWorktrees",
 			Handler:    _DaemonService_CleanupWorktrees_Handler,
 		},
+		{
+			MethodName: "PruneBranches",
+			Handler:    _DaemonService_PruneBranches_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportTasks",
+			Handler:       _DaemonService_ExportTasks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportTasks",
+			Handler:       _DaemonService_ImportTasks_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "WatchEvents",
 			Handler:       _DaemonService_WatchEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "StreamLogs",
+			Handler:       _DaemonService_StreamLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamAgentOutput",
+			Handler:       _DaemonService_StreamAgentOutput_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "map/v1/daemon.proto",
 }
@@ -104,21 +104,33 @@ const (
 	EventType_EVENT_TYPE_TASK_CANCELLED      EventType = 7
 	EventType_EVENT_TYPE_TASK_WAITING_INPUT  EventType = 8
 	EventType_EVENT_TYPE_TASK_INPUT_RECEIVED EventType = 9
+	// A busy agent's pane stopped changing without showing activity or a question
+	EventType_EVENT_TYPE_AGENT_STUCK EventType = 10
+	// A completed task was reopened for more work
+	EventType_EVENT_TYPE_TASK_REOPENED EventType = 11
+	// Periodic liveness report for a running agent
+	EventType_EVENT_TYPE_AGENT_HEARTBEAT EventType = 12
+	// An agent declined a task offered to it
+	EventType_EVENT_TYPE_TASK_REJECTED EventType = 13
 )
 
 // Enum value maps for EventType.
 var (
 	EventType_name = map[int32]string{
-		0: "EVENT_TYPE_UNSPECIFIED",
-		1: "EVENT_TYPE_TASK_CREATED",
-		2: "EVENT_TYPE_TASK_OFFERED",
-		3: "EVENT_TYPE_TASK_ACCEPTED",
-		4: "EVENT_TYPE_TASK_STARTED",
-		5: "EVENT_TYPE_TASK_COMPLETED",
-		6: "EVENT_TYPE_TASK_FAILED",
-		7: "EVENT_TYPE_TASK_CANCELLED",
-		8: "EVENT_TYPE_TASK_WAITING_INPUT",
-		9: "EVENT_TYPE_TASK_INPUT_RECEIVED",
+		0:  "EVENT_TYPE_UNSPECIFIED",
+		1:  "EVENT_TYPE_TASK_CREATED",
+		2:  "EVENT_TYPE_TASK_OFFERED",
+		3:  "EVENT_TYPE_TASK_ACCEPTED",
+		4:  "EVENT_TYPE_TASK_STARTED",
+		5:  "EVENT_TYPE_TASK_COMPLETED",
+		6:  "EVENT_TYPE_TASK_FAILED",
+		7:  "EVENT_TYPE_TASK_CANCELLED",
+		8:  "EVENT_TYPE_TASK_WAITING_INPUT",
+		9:  "EVENT_TYPE_TASK_INPUT_RECEIVED",
+		10: "EVENT_TYPE_AGENT_STUCK",
+		11: "EVENT_TYPE_TASK_REOPENED",
+		12: "EVENT_TYPE_AGENT_HEARTBEAT",
+		13: "EVENT_TYPE_TASK_REJECTED",
 	}
 	EventType_value = map[string]int32{
 		"EVENT_TYPE_UNSPECIFIED":         0,
@@ -131,6 +143,10 @@ var (
 		"EVENT_TYPE_TASK_CANCELLED":      7,
 		"EVENT_TYPE_TASK_WAITING_INPUT":  8,
 		"EVENT_TYPE_TASK_INPUT_RECEIVED": 9,
+		"EVENT_TYPE_AGENT_STUCK":         10,
+		"EVENT_TYPE_TASK_REOPENED":       11,
+		"EVENT_TYPE_AGENT_HEARTBEAT":     12,
+		"EVENT_TYPE_TASK_REJECTED":       13,
 	}
 )
 
@@ -236,8 +252,35 @@ type Task struct {
 	Error                string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
 	GithubSource         *GitHubSource          `protobuf:"bytes,10,opt,name=github_source,json=githubSource,proto3" json:"github_source,omitempty"`
 	WaitingInputQuestion string                 `protobuf:"bytes,11,opt,name=waiting_input_question,json=waitingInputQuestion,proto3" json:"waiting_input_question,omitempty"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+	StartedAt            *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	CompletedAt          *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// Median duration of similar completed tasks, 0 if there is no history
+	EstimatedDurationSeconds int64 `protobuf:"varint,14,opt,name=estimated_duration_seconds,json=estimatedDurationSeconds,proto3" json:"estimated_duration_seconds,omitempty"`
+	EstimateSampleSize       int32 `protobuf:"varint,15,opt,name=estimate_sample_size,json=estimateSampleSize,proto3" json:"estimate_sample_size,omitempty"`
+	// Number of times the task has been re-queued after failing
+	RetryCount int32 `protobuf:"varint,16,opt,name=retry_count,json=retryCount,proto3" json:"retry_count,omitempty"`
+	// Run (batch of related tasks) the task was submitted in, if any
+	RunId string `protobuf:"bytes,17,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// Agent the task should go to if it is idle, e.g. the one that ran it
+	// before a retry. Otherwise the task is scheduled as usual.
+	PreferAgent string `protobuf:"bytes,18,opt,name=prefer_agent,json=preferAgent,proto3" json:"prefer_agent,omitempty"`
+	// The task is read-only (analysis, Q&A) and goes to an agent working in
+	// the shared checkout when one is idle, leaving worktree agents free
+	NoWorktree bool `protobuf:"varint,19,opt,name=no_worktree,json=noWorktree,proto3" json:"no_worktree,omitempty"`
+	// Agent type (claude, codex, gemini) the task goes to when one is idle,
+	// set explicitly or by a routing rule. Otherwise any agent may take it.
+	AgentType string `protobuf:"bytes,20,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	// Repository root the task belongs to, if any
+	RepoRoot string `protobuf:"bytes,21,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	// What the task last ran under, captured when an agent started it
+	Environment *TaskEnvironment `protobuf:"bytes,22,opt,name=environment,proto3" json:"environment,omitempty"`
+	// When the task was archived; archived tasks are hidden from listings
+	ArchivedAt *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`
+	// Agents that declined the task when it was offered to them; routing
+	// doesn't offer it to them again
+	RejectedBy    []string `protobuf:"bytes,24,rep,name=rejected_by,json=rejectedBy,proto3" json:"rejected_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task) Reset() {
@@ -347,6 +390,659 @@ func (x *Task) GetWaitingInputQuestion() string {
 	return ""
 }
 
+func (x *Task) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Task) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *Task) GetEstimatedDurationSeconds() int64 {
+	if x != nil {
+		return x.EstimatedDurationSeconds
+	}
+	return 0
+}
+
+func (x *Task) GetEstimateSampleSize() int32 {
+	if x != nil {
+		return x.EstimateSampleSize
+	}
+	return 0
+}
+
+func (x *Task) GetRetryCount() int32 {
+	if x != nil {
+		return x.RetryCount
+	}
+	return 0
+}
+
+func (x *Task) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *Task) GetPreferAgent() string {
+	if x != nil {
+		return x.PreferAgent
+	}
+	return ""
+}
+
+func (x *Task) GetNoWorktree() bool {
+	if x != nil {
+		return x.NoWorktree
+	}
+	return false
+}
+
+func (x *Task) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
+func (x *Task) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *Task) GetEnvironment() *TaskEnvironment {
+	if x != nil {
+		return x.Environment
+	}
+	return nil
+}
+
+func (x *Task) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+func (x *Task) GetRejectedBy() []string {
+	if x != nil {
+		return x.RejectedBy
+	}
+	return nil
+}
+
+// TaskEnvironment records the conditions a task ran under, for debugging
+// failures and comparing retries
+type TaskEnvironment struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AgentId   string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	AgentType string                 `protobuf:"bytes,2,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	// Model the agent CLI was pointed at through its environment, e.g.
+	// ANTHROPIC_MODEL; empty when the CLI's default was used
+	Model string `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	// Working directory and whether it is the agent's own worktree
+	Workdir  string `protobuf:"bytes,4,opt,name=workdir,proto3" json:"workdir,omitempty"`
+	Worktree bool   `protobuf:"varint,5,opt,name=worktree,proto3" json:"worktree,omitempty"`
+	// Branch checked out in workdir, empty for a detached HEAD
+	Branch  string `protobuf:"bytes,6,opt,name=branch,proto3" json:"branch,omitempty"`
+	HeadSha string `protobuf:"bytes,7,opt,name=head_sha,json=headSha,proto3" json:"head_sha,omitempty"`
+	// "skip" when the agent runs without permission prompts, else "prompt"
+	PermissionMode string `protobuf:"bytes,8,opt,name=permission_mode,json=permissionMode,proto3" json:"permission_mode,omitempty"`
+	Headless       bool   `protobuf:"varint,9,opt,name=headless,proto3" json:"headless,omitempty"`
+	// Container image the agent's CLI runs in, empty when native
+	Image         string                 `protobuf:"bytes,10,opt,name=image,proto3" json:"image,omitempty"`
+	CapturedAt    *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=captured_at,json=capturedAt,proto3" json:"captured_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskEnvironment) Reset() {
+	*x = TaskEnvironment{}
+	mi := &file_map_v1_types_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskEnvironment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskEnvironment) ProtoMessage() {}
+
+func (x *TaskEnvironment) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskEnvironment.ProtoReflect.Descriptor instead.
+func (*TaskEnvironment) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *TaskEnvironment) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetWorkdir() string {
+	if x != nil {
+		return x.Workdir
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetWorktree() bool {
+	if x != nil {
+		return x.Worktree
+	}
+	return false
+}
+
+func (x *TaskEnvironment) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetHeadSha() string {
+	if x != nil {
+		return x.HeadSha
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetPermissionMode() string {
+	if x != nil {
+		return x.PermissionMode
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetHeadless() bool {
+	if x != nil {
+		return x.Headless
+	}
+	return false
+}
+
+func (x *TaskEnvironment) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *TaskEnvironment) GetCapturedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CapturedAt
+	}
+	return nil
+}
+
+// ScheduledTask is a recurring task: each time its cron schedule fires, a
+// task with its description is submitted in a run named by schedule_id
+type ScheduledTask struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	// Standard five-field cron expression, in the daemon's local time zone
+	Cron        string                 `protobuf:"bytes,2,opt,name=cron,proto3" json:"cron,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ScopePaths  []string               `protobuf:"bytes,4,rep,name=scope_paths,json=scopePaths,proto3" json:"scope_paths,omitempty"`
+	RepoRoot    string                 `protobuf:"bytes,5,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	NextRunAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_run_at,json=nextRunAt,proto3" json:"next_run_at,omitempty"`
+	LastRunAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_run_at,json=lastRunAt,proto3" json:"last_run_at,omitempty"`
+	// Task submitted the last time the schedule fired
+	LastTaskId    string `protobuf:"bytes,9,opt,name=last_task_id,json=lastTaskId,proto3" json:"last_task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledTask) Reset() {
+	*x = ScheduledTask{}
+	mi := &file_map_v1_types_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledTask) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledTask) ProtoMessage() {}
+
+func (x *ScheduledTask) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledTask.ProtoReflect.Descriptor instead.
+func (*ScheduledTask) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ScheduledTask) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduledTask) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *ScheduledTask) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ScheduledTask) GetScopePaths() []string {
+	if x != nil {
+		return x.ScopePaths
+	}
+	return nil
+}
+
+func (x *ScheduledTask) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *ScheduledTask) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ScheduledTask) GetNextRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRunAt
+	}
+	return nil
+}
+
+func (x *ScheduledTask) GetLastRunAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRunAt
+	}
+	return nil
+}
+
+func (x *ScheduledTask) GetLastTaskId() string {
+	if x != nil {
+		return x.LastTaskId
+	}
+	return ""
+}
+
+// RunSummary aggregates the status of the tasks submitted in one run
+type RunSummary struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RunId    string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	RepoRoot string                 `protobuf:"bytes,2,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	Total    int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Pending  int32                  `protobuf:"varint,4,opt,name=pending,proto3" json:"pending,omitempty"`
+	// Tasks assigned to an agent and not yet finished
+	Active    int32 `protobuf:"varint,5,opt,name=active,proto3" json:"active,omitempty"`
+	Completed int32 `protobuf:"varint,6,opt,name=completed,proto3" json:"completed,omitempty"`
+	Failed    int32 `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
+	Cancelled int32 `protobuf:"varint,8,opt,name=cancelled,proto3" json:"cancelled,omitempty"`
+	// When the run's first task was created and its last task updated
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunSummary) Reset() {
+	*x = RunSummary{}
+	mi := &file_map_v1_types_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSummary) ProtoMessage() {}
+
+func (x *RunSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSummary.ProtoReflect.Descriptor instead.
+func (*RunSummary) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RunSummary) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *RunSummary) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *RunSummary) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *RunSummary) GetPending() int32 {
+	if x != nil {
+		return x.Pending
+	}
+	return 0
+}
+
+func (x *RunSummary) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *RunSummary) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *RunSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *RunSummary) GetCancelled() int32 {
+	if x != nil {
+		return x.Cancelled
+	}
+	return 0
+}
+
+func (x *RunSummary) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *RunSummary) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// AgentMessage is one message the daemon sent to an agent (a task prompt,
+// nudge, or input response) or one the agent sent back (a question)
+type AgentMessage struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	TaskId  string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// task, offer, initial-prompt, nudge, input-response, or question
+	Kind string `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	// "sent" (daemon to agent) or "received" (agent to daemon)
+	Direction     string                 `protobuf:"bytes,4,opt,name=direction,proto3" json:"direction,omitempty"`
+	Content       string                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentMessage) Reset() {
+	*x = AgentMessage{}
+	mi := &file_map_v1_types_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentMessage) ProtoMessage() {}
+
+func (x *AgentMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentMessage.ProtoReflect.Descriptor instead.
+func (*AgentMessage) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AgentMessage) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *AgentMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// TaskAttempt is one run of a task by an agent. Each retry starts a new attempt.
+type TaskAttempt struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// 1 for the first run, incremented on each retry
+	Attempt int32      `protobuf:"varint,2,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	AgentId string     `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status  TaskStatus `protobuf:"varint,4,opt,name=status,proto3,enum=map.v1.TaskStatus" json:"status,omitempty"`
+	Result  string     `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
+	Error   string     `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// Files changed in the agent's worktree during the attempt
+	ChangedFiles  []string               `protobuf:"bytes,7,rep,name=changed_files,json=changedFiles,proto3" json:"changed_files,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskAttempt) Reset() {
+	*x = TaskAttempt{}
+	mi := &file_map_v1_types_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskAttempt) ProtoMessage() {}
+
+func (x *TaskAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskAttempt.ProtoReflect.Descriptor instead.
+func (*TaskAttempt) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TaskAttempt) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskAttempt) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *TaskAttempt) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *TaskAttempt) GetStatus() TaskStatus {
+	if x != nil {
+		return x.Status
+	}
+	return TaskStatus_TASK_STATUS_UNSPECIFIED
+}
+
+func (x *TaskAttempt) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *TaskAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TaskAttempt) GetChangedFiles() []string {
+	if x != nil {
+		return x.ChangedFiles
+	}
+	return nil
+}
+
+func (x *TaskAttempt) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *TaskAttempt) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
 // TaskEvent contains task-related event data
 type TaskEvent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -360,7 +1056,7 @@ type TaskEvent struct {
 
 func (x *TaskEvent) Reset() {
 	*x = TaskEvent{}
-	mi := &file_map_v1_types_proto_msgTypes[2]
+	mi := &file_map_v1_types_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -372,7 +1068,7 @@ func (x *TaskEvent) String() string {
 func (*TaskEvent) ProtoMessage() {}
 
 func (x *TaskEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_types_proto_msgTypes[2]
+	mi := &file_map_v1_types_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -385,7 +1081,7 @@ func (x *TaskEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TaskEvent.ProtoReflect.Descriptor instead.
 func (*TaskEvent) Descriptor() ([]byte, []int) {
-	return file_map_v1_types_proto_rawDescGZIP(), []int{2}
+	return file_map_v1_types_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TaskEvent) GetTaskId() string {
@@ -426,7 +1122,7 @@ type StatusEvent struct {
 
 func (x *StatusEvent) Reset() {
 	*x = StatusEvent{}
-	mi := &file_map_v1_types_proto_msgTypes[3]
+	mi := &file_map_v1_types_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -438,7 +1134,7 @@ func (x *StatusEvent) String() string {
 func (*StatusEvent) ProtoMessage() {}
 
 func (x *StatusEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_types_proto_msgTypes[3]
+	mi := &file_map_v1_types_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -451,7 +1147,7 @@ func (x *StatusEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StatusEvent.ProtoReflect.Descriptor instead.
 func (*StatusEvent) Descriptor() ([]byte, []int) {
-	return file_map_v1_types_proto_rawDescGZIP(), []int{3}
+	return file_map_v1_types_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *StatusEvent) GetMessage() string {
@@ -461,6 +1157,78 @@ func (x *StatusEvent) GetMessage() string {
 	return ""
 }
 
+// AgentHeartbeat reports that an agent is still alive, with its status and
+// current task
+type AgentHeartbeat struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	AgentId string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// "idle" or "busy"
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Current task ID, empty when idle
+	TaskId        string `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	AgentType     string `protobuf:"bytes,4,opt,name=agent_type,json=agentType,proto3" json:"agent_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgentHeartbeat) Reset() {
+	*x = AgentHeartbeat{}
+	mi := &file_map_v1_types_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentHeartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentHeartbeat) ProtoMessage() {}
+
+func (x *AgentHeartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_map_v1_types_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentHeartbeat.ProtoReflect.Descriptor instead.
+func (*AgentHeartbeat) Descriptor() ([]byte, []int) {
+	return file_map_v1_types_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AgentHeartbeat) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentHeartbeat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AgentHeartbeat) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AgentHeartbeat) GetAgentType() string {
+	if x != nil {
+		return x.AgentType
+	}
+	return ""
+}
+
 // Event represents a system event for real-time streaming
 type Event struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
@@ -471,6 +1239,7 @@ type Event struct {
 	//
 	//	*Event_Task
 	//	*Event_Status
+	//	*Event_Heartbeat
 	Payload       isEvent_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -478,7 +1247,7 @@ type Event struct {
 
 func (x *Event) Reset() {
 	*x = Event{}
-	mi := &file_map_v1_types_proto_msgTypes[4]
+	mi := &file_map_v1_types_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -490,7 +1259,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_map_v1_types_proto_msgTypes[4]
+	mi := &file_map_v1_types_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +1272,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_map_v1_types_proto_rawDescGZIP(), []int{4}
+	return file_map_v1_types_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Event) GetEventId() string {
@@ -552,6 +1321,15 @@ func (x *Event) GetStatus() *StatusEvent {
 	return nil
 }
 
+func (x *Event) GetHeartbeat() *AgentHeartbeat {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_Heartbeat); ok {
+			return x.Heartbeat
+		}
+	}
+	return nil
+}
+
 type isEvent_Payload interface {
 	isEvent_Payload()
 }
@@ -564,10 +1342,16 @@ type Event_Status struct {
 	Status *StatusEvent `protobuf:"bytes,5,opt,name=status,proto3,oneof"`
 }
 
+type Event_Heartbeat struct {
+	Heartbeat *AgentHeartbeat `protobuf:"bytes,6,opt,name=heartbeat,proto3,oneof"`
+}
+
 func (*Event_Task) isEvent_Payload() {}
 
 func (*Event_Status) isEvent_Payload() {}
 
+func (*Event_Heartbeat) isEvent_Payload() {}
+
 var File_map_v1_types_proto protoreflect.FileDescriptor
 
 const file_map_v1_types_proto_rawDesc = "" +
@@ -576,7 +1360,7 @@ const file_map_v1_types_proto_rawDesc = "" +
 	"\fGitHubSource\x12\x14\n" +
 	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x12\n" +
 	"\x04repo\x18\x02 \x01(\tR\x04repo\x12!\n" +
-	"\fissue_number\x18\x03 \x01(\x05R\vissueNumber\"\xc4\x03\n" +
+	"\fissue_number\x18\x03 \x01(\x05R\vissueNumber\"\xff\a\n" +
 	"\x04Task\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
@@ -593,7 +1377,89 @@ const file_map_v1_types_proto_rawDesc = "" +
 	"\x05error\x18\t \x01(\tR\x05error\x129\n" +
 	"\rgithub_source\x18\n" +
 	" \x01(\v2\x14.map.v1.GitHubSourceR\fgithubSource\x124\n" +
-	"\x16waiting_input_question\x18\v \x01(\tR\x14waitingInputQuestion\"\xa5\x01\n" +
+	"\x16waiting_input_question\x18\v \x01(\tR\x14waitingInputQuestion\x129\n" +
+	"\n" +
+	"started_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fcompleted_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12<\n" +
+	"\x1aestimated_duration_seconds\x18\x0e \x01(\x03R\x18estimatedDurationSeconds\x120\n" +
+	"\x14estimate_sample_size\x18\x0f \x01(\x05R\x12estimateSampleSize\x12\x1f\n" +
+	"\vretry_count\x18\x10 \x01(\x05R\n" +
+	"retryCount\x12\x15\n" +
+	"\x06run_id\x18\x11 \x01(\tR\x05runId\x12!\n" +
+	"\fprefer_agent\x18\x12 \x01(\tR\vpreferAgent\x12\x1f\n" +
+	"\vno_worktree\x18\x13 \x01(\bR\n" +
+	"noWorktree\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\x14 \x01(\tR\tagentType\x12\x1b\n" +
+	"\trepo_root\x18\x15 \x01(\tR\brepoRoot\x129\n" +
+	"\venvironment\x18\x16 \x01(\v2\x17.map.v1.TaskEnvironmentR\venvironment\x12;\n" +
+	"\varchived_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\x12\x1f\n" +
+	"\vrejected_by\x18\x18 \x03(\tR\n" +
+	"rejectedBy\"\xe2\x02\n" +
+	"\x0fTaskEnvironment\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\x02 \x01(\tR\tagentType\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x12\x18\n" +
+	"\aworkdir\x18\x04 \x01(\tR\aworkdir\x12\x1a\n" +
+	"\bworktree\x18\x05 \x01(\bR\bworktree\x12\x16\n" +
+	"\x06branch\x18\x06 \x01(\tR\x06branch\x12\x19\n" +
+	"\bhead_sha\x18\a \x01(\tR\aheadSha\x12'\n" +
+	"\x0fpermission_mode\x18\b \x01(\tR\x0epermissionMode\x12\x1a\n" +
+	"\bheadless\x18\t \x01(\bR\bheadless\x12\x14\n" +
+	"\x05image\x18\n" +
+	" \x01(\tR\x05image\x12;\n" +
+	"\vcaptured_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"capturedAt\"\xf9\x02\n" +
+	"\rScheduledTask\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12\x12\n" +
+	"\x04cron\x18\x02 \x01(\tR\x04cron\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vscope_paths\x18\x04 \x03(\tR\n" +
+	"scopePaths\x12\x1b\n" +
+	"\trepo_root\x18\x05 \x01(\tR\brepoRoot\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12:\n" +
+	"\vnext_run_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tnextRunAt\x12:\n" +
+	"\vlast_run_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tlastRunAt\x12 \n" +
+	"\flast_task_id\x18\t \x01(\tR\n" +
+	"lastTaskId\"\xd2\x02\n" +
+	"\n" +
+	"RunSummary\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1b\n" +
+	"\trepo_root\x18\x02 \x01(\tR\brepoRoot\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x18\n" +
+	"\apending\x18\x04 \x01(\x05R\apending\x12\x16\n" +
+	"\x06active\x18\x05 \x01(\x05R\x06active\x12\x1c\n" +
+	"\tcompleted\x18\x06 \x01(\x05R\tcompleted\x12\x16\n" +
+	"\x06failed\x18\a \x01(\x05R\x06failed\x12\x1c\n" +
+	"\tcancelled\x18\b \x01(\x05R\tcancelled\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc8\x01\n" +
+	"\fAgentMessage\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tdirection\x18\x04 \x01(\tR\tdirection\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd2\x02\n" +
+	"\vTaskAttempt\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x18\n" +
+	"\aattempt\x18\x02 \x01(\x05R\aattempt\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12*\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x12.map.v1.TaskStatusR\x06status\x12\x16\n" +
+	"\x06result\x18\x05 \x01(\tR\x06result\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12#\n" +
+	"\rchanged_files\x18\a \x03(\tR\fchangedFiles\x129\n" +
+	"\n" +
+	"started_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"\xa5\x01\n" +
 	"\tTaskEvent\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x121\n" +
 	"\n" +
@@ -602,13 +1468,20 @@ const file_map_v1_types_proto_rawDesc = "" +
 	"new_status\x18\x03 \x01(\x0e2\x12.map.v1.TaskStatusR\tnewStatus\x12\x19\n" +
 	"\bagent_id\x18\x04 \x01(\tR\aagentId\"'\n" +
 	"\vStatusEvent\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\xe6\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"{\n" +
+	"\x0eAgentHeartbeat\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x1d\n" +
+	"\n" +
+	"agent_type\x18\x04 \x01(\tR\tagentType\"\x9e\x02\n" +
 	"\x05Event\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12%\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x11.map.v1.EventTypeR\x04type\x128\n" +
 	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12'\n" +
 	"\x04task\x18\x04 \x01(\v2\x11.map.v1.TaskEventH\x00R\x04task\x12-\n" +
-	"\x06status\x18\x05 \x01(\v2\x13.map.v1.StatusEventH\x00R\x06statusB\t\n" +
+	"\x06status\x18\x05 \x01(\v2\x13.map.v1.StatusEventH\x00R\x06status\x126\n" +
+	"\theartbeat\x18\x06 \x01(\v2\x16.map.v1.AgentHeartbeatH\x00R\theartbeatB\t\n" +
 	"\apayload*\xff\x01\n" +
 	"\n" +
 	"TaskStatus\x12\x1b\n" +
@@ -620,7 +1493,7 @@ const file_map_v1_types_proto_rawDesc = "" +
 	"\x15TASK_STATUS_COMPLETED\x10\x05\x12\x16\n" +
 	"\x12TASK_STATUS_FAILED\x10\x06\x12\x19\n" +
 	"\x15TASK_STATUS_CANCELLED\x10\a\x12\x1d\n" +
-	"\x19TASK_STATUS_WAITING_INPUT\x10\b*\xbd\x02\n" +
+	"\x19TASK_STATUS_WAITING_INPUT\x10\b*\xb5\x03\n" +
 	"\tEventType\x12\x1a\n" +
 	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
 	"\x17EVENT_TYPE_TASK_CREATED\x10\x01\x12\x1b\n" +
@@ -631,7 +1504,12 @@ const file_map_v1_types_proto_rawDesc = "" +
 	"\x16EVENT_TYPE_TASK_FAILED\x10\x06\x12\x1d\n" +
 	"\x19EVENT_TYPE_TASK_CANCELLED\x10\a\x12!\n" +
 	"\x1dEVENT_TYPE_TASK_WAITING_INPUT\x10\b\x12\"\n" +
-	"\x1eEVENT_TYPE_TASK_INPUT_RECEIVED\x10\tB1Z/github.com/pmarsceill/mapcli/proto/map/v1;mapv1b\x06proto3"
+	"\x1eEVENT_TYPE_TASK_INPUT_RECEIVED\x10\t\x12\x1a\n" +
+	"\x16EVENT_TYPE_AGENT_STUCK\x10\n" +
+	"\x12\x1c\n" +
+	"\x18EVENT_TYPE_TASK_REOPENED\x10\v\x12\x1e\n" +
+	"\x1aEVENT_TYPE_AGENT_HEARTBEAT\x10\f\x12\x1c\n" +
+	"\x18EVENT_TYPE_TASK_REJECTED\x10\rB1Z/github.com/pmarsceill/mapcli/proto/map/v1;mapv1b\x06proto3"
 
 var (
 	file_map_v1_types_proto_rawDescOnce sync.Once
@@ -646,33 +1524,54 @@ func file_map_v1_types_proto_rawDescGZIP() []byte {
 }
 
 var file_map_v1_types_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_map_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_map_v1_types_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_map_v1_types_proto_goTypes = []any{
 	(TaskStatus)(0),               // 0: map.v1.TaskStatus
 	(EventType)(0),                // 1: map.v1.EventType
 	(*GitHubSource)(nil),          // 2: map.v1.GitHubSource
 	(*Task)(nil),                  // 3: map.v1.Task
-	(*TaskEvent)(nil),             // 4: map.v1.TaskEvent
-	(*StatusEvent)(nil),           // 5: map.v1.StatusEvent
-	(*Event)(nil),                 // 6: map.v1.Event
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(*TaskEnvironment)(nil),       // 4: map.v1.TaskEnvironment
+	(*ScheduledTask)(nil),         // 5: map.v1.ScheduledTask
+	(*RunSummary)(nil),            // 6: map.v1.RunSummary
+	(*AgentMessage)(nil),          // 7: map.v1.AgentMessage
+	(*TaskAttempt)(nil),           // 8: map.v1.TaskAttempt
+	(*TaskEvent)(nil),             // 9: map.v1.TaskEvent
+	(*StatusEvent)(nil),           // 10: map.v1.StatusEvent
+	(*AgentHeartbeat)(nil),        // 11: map.v1.AgentHeartbeat
+	(*Event)(nil),                 // 12: map.v1.Event
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
 }
 var file_map_v1_types_proto_depIdxs = []int32{
 	0,  // 0: map.v1.Task.status:type_name -> map.v1.TaskStatus
-	7,  // 1: map.v1.Task.created_at:type_name -> google.protobuf.Timestamp
-	7,  // 2: map.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 1: map.v1.Task.created_at:type_name -> google.protobuf.Timestamp
+	13, // 2: map.v1.Task.updated_at:type_name -> google.protobuf.Timestamp
 	2,  // 3: map.v1.Task.github_source:type_name -> map.v1.GitHubSource
-	0,  // 4: map.v1.TaskEvent.old_status:type_name -> map.v1.TaskStatus
-	0,  // 5: map.v1.TaskEvent.new_status:type_name -> map.v1.TaskStatus
-	1,  // 6: map.v1.Event.type:type_name -> map.v1.EventType
-	7,  // 7: map.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
-	4,  // 8: map.v1.Event.task:type_name -> map.v1.TaskEvent
-	5,  // 9: map.v1.Event.status:type_name -> map.v1.StatusEvent
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	13, // 4: map.v1.Task.started_at:type_name -> google.protobuf.Timestamp
+	13, // 5: map.v1.Task.completed_at:type_name -> google.protobuf.Timestamp
+	4,  // 6: map.v1.Task.environment:type_name -> map.v1.TaskEnvironment
+	13, // 7: map.v1.Task.archived_at:type_name -> google.protobuf.Timestamp
+	13, // 8: map.v1.TaskEnvironment.captured_at:type_name -> google.protobuf.Timestamp
+	13, // 9: map.v1.ScheduledTask.created_at:type_name -> google.protobuf.Timestamp
+	13, // 10: map.v1.ScheduledTask.next_run_at:type_name -> google.protobuf.Timestamp
+	13, // 11: map.v1.ScheduledTask.last_run_at:type_name -> google.protobuf.Timestamp
+	13, // 12: map.v1.RunSummary.created_at:type_name -> google.protobuf.Timestamp
+	13, // 13: map.v1.RunSummary.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 14: map.v1.AgentMessage.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 15: map.v1.TaskAttempt.status:type_name -> map.v1.TaskStatus
+	13, // 16: map.v1.TaskAttempt.started_at:type_name -> google.protobuf.Timestamp
+	13, // 17: map.v1.TaskAttempt.finished_at:type_name -> google.protobuf.Timestamp
+	0,  // 18: map.v1.TaskEvent.old_status:type_name -> map.v1.TaskStatus
+	0,  // 19: map.v1.TaskEvent.new_status:type_name -> map.v1.TaskStatus
+	1,  // 20: map.v1.Event.type:type_name -> map.v1.EventType
+	13, // 21: map.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 22: map.v1.Event.task:type_name -> map.v1.TaskEvent
+	10, // 23: map.v1.Event.status:type_name -> map.v1.StatusEvent
+	11, // 24: map.v1.Event.heartbeat:type_name -> map.v1.AgentHeartbeat
+	25, // [25:25] is the sub-list for method output_type
+	25, // [25:25] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_map_v1_types_proto_init() }
@@ -680,9 +1579,10 @@ func file_map_v1_types_proto_init() {
 	if File_map_v1_types_proto != nil {
 		return
 	}
-	file_map_v1_types_proto_msgTypes[4].OneofWrappers = []any{
+	file_map_v1_types_proto_msgTypes[10].OneofWrappers = []any{
 		(*Event_Task)(nil),
 		(*Event_Status)(nil),
+		(*Event_Heartbeat)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -690,7 +1590,7 @@ func file_map_v1_types_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_map_v1_types_proto_rawDesc), len(file_map_v1_types_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   5,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   0,
 		},